@@ -0,0 +1,307 @@
+// Package analyze computes complexity and style metrics for Go source files.
+package analyze
+
+import "fmt"
+import "go/ast"
+import "go/parser"
+import "go/token"
+import "io"
+import "io/fs"
+import "os"
+import "path/filepath"
+import "sort"
+import "strings"
+import "text/tabwriter"
+import "unicode"
+
+// Func holds the metrics for one function or method.
+type Func struct {
+	Name         string // Recv.Name for methods
+	Pos          token.Position
+	Lines        int
+	CommentLines int
+	Complexity   int // McCabe cyclomatic complexity
+}
+
+// File holds the metrics for one source file.
+type File struct {
+	Path         string
+	Lines        int // non-blank lines
+	CommentLines int
+	Funcs        []Func
+}
+
+// CommentDensity returns the fraction of non-blank lines that carry a
+// comment.
+func (f File) CommentDensity() float64 {
+	if f.Lines == 0 {
+		return 0
+	}
+	return float64(f.CommentLines) / float64(f.Lines)
+}
+
+// An Issue is a naming-convention violation.
+type Issue struct {
+	Pos     token.Position
+	Message string
+}
+
+// Report is the result of analyzing a set of files.
+type Report struct {
+	Files  []File
+	Issues []Issue
+}
+
+// Dir analyzes every .go file under root, skipping vendor, testdata and
+// hidden directories.
+func Dir(root string) (*Report, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return Files(paths...)
+}
+
+// Files analyzes the named source files.
+func Files(paths ...string) (*Report, error) {
+	r := &Report{}
+	fset := token.NewFileSet()
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		file, issues := analyzeFile(fset, f, src)
+		file.Path = path
+		r.Files = append(r.Files, file)
+		r.Issues = append(r.Issues, issues...)
+	}
+	return r, nil
+}
+
+func analyzeFile(fset *token.FileSet, f *ast.File, src []byte) (File, []Issue) {
+	var file File
+	for _, line := range strings.Split(string(src), "\n") {
+		if strings.TrimSpace(line) != "" {
+			file.Lines++
+		}
+	}
+
+	// Record each line holding a comment once, however many comments it has.
+	commented := map[int]bool{}
+	for _, group := range f.Comments {
+		for _, c := range group.List {
+			start, end := fset.Position(c.Pos()).Line, fset.Position(c.End()).Line
+			for l := start; l <= end; l++ {
+				commented[l] = true
+			}
+		}
+	}
+	file.CommentLines = len(commented)
+
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Body == nil {
+			continue
+		}
+		start, end := fset.Position(fd.Pos()).Line, fset.Position(fd.End()).Line
+		fn := Func{
+			Name:       funcName(fd),
+			Pos:        fset.Position(fd.Pos()),
+			Lines:      end - start + 1,
+			Complexity: Complexity(fd.Body),
+		}
+		for l := start; l <= end; l++ {
+			if commented[l] {
+				fn.CommentLines++
+			}
+		}
+		file.Funcs = append(file.Funcs, fn)
+	}
+	return file, namingIssues(fset, f)
+}
+
+func funcName(fd *ast.FuncDecl) string {
+	if fd.Recv == nil || len(fd.Recv.List) == 0 {
+		return fd.Name.Name
+	}
+	typ := fd.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name + "." + fd.Name.Name
+	}
+	return fd.Name.Name
+}
+
+// Complexity returns the cyclomatic complexity of n: one plus the number of
+// decision points (conditionals, loops, non-default cases and short-circuit
+// operators) it contains.
+func Complexity(n ast.Node) int {
+	c := 1
+	ast.Inspect(n, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			c++
+		case *ast.CaseClause:
+			if n.List != nil {
+				c++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				c++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				c++
+			}
+		}
+		return true
+	})
+	return c
+}
+
+// initialisms that Go style writes in a consistent case, keyed by the
+// mixed-case spelling that is flagged.
+var initialisms = map[string]string{
+	"Api": "API", "Http": "HTTP", "Id": "ID", "Json": "JSON",
+	"Sql": "SQL", "Uri": "URI", "Url": "URL", "Xml": "XML",
+}
+
+func namingIssues(fset *token.FileSet, f *ast.File) []Issue {
+	var issues []Issue
+	report := func(id *ast.Ident, format string, args ...any) {
+		issues = append(issues, Issue{fset.Position(id.Pos()), fmt.Sprintf(format, args...)})
+	}
+	check := func(id *ast.Ident) {
+		name := id.Name
+		if name == "_" || strings.HasPrefix(name, "Test") || strings.HasPrefix(name, "Benchmark") ||
+			strings.HasPrefix(name, "Example") || strings.HasPrefix(name, "Fuzz") {
+			return
+		}
+		if strings.Contains(name, "_") {
+			report(id, "%s: use MixedCaps rather than underscores", name)
+			return
+		}
+		for _, word := range splitWords(name) {
+			if fix, ok := initialisms[word]; ok {
+				report(id, "%s: initialism %s should be %s", name, word, fix)
+				return
+			}
+		}
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			check(n.Name)
+			if n.Recv != nil {
+				for _, field := range n.Recv.List {
+					for _, id := range field.Names {
+						if id.Name == "this" || id.Name == "self" {
+							report(id, "receiver name %s should be a short abbreviation of the type", id.Name)
+						}
+					}
+				}
+			}
+		case *ast.TypeSpec:
+			check(n.Name)
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				check(id)
+			}
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						check(id)
+					}
+				}
+			}
+		}
+		return true
+	})
+	return issues
+}
+
+// splitWords splits a MixedCaps identifier at each upper-case letter.
+func splitWords(name string) []string {
+	var words []string
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	return append(words, name[start:])
+}
+
+// Render writes a per-function table, per-file totals and any naming issues
+// to w. Functions are listed by descending complexity.
+func (r *Report) Render(w io.Writer) error {
+	type row struct {
+		file string
+		fn   Func
+	}
+	var rows []row
+	for _, f := range r.Files {
+		for _, fn := range f.Funcs {
+			rows = append(rows, row{f.Path, fn})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].fn.Complexity > rows[j].fn.Complexity
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FUNCTION\tLOCATION\tLINES\tCOMPLEXITY")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s:%d\t%d\t%d\n", row.fn.Name, row.file, row.fn.Pos.Line, row.fn.Lines, row.fn.Complexity)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintln(tw, "FILE\tLINES\tCOMMENTS\tDENSITY")
+	for _, f := range r.Files {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.0f%%\n", f.Path, f.Lines, f.CommentLines, 100*f.CommentDensity())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Issues) > 0 {
+		fmt.Fprintln(w)
+		for _, issue := range r.Issues {
+			if _, err := fmt.Fprintf(w, "%s: %s\n", issue.Pos, issue.Message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package analyze
+
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+const sample = `package sample
+
+// Classify describes n.
+func Classify(n int) string {
+	if n < 0 || n > 100 {
+		return "out of range"
+	}
+	switch {
+	case n%2 == 0:
+		return "even"
+	default:
+		return "odd"
+	}
+}
+
+func get_user_Id() {}
+`
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sample.go"), []byte(sample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Dir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Files) != 1 {
+		t.Fatalf("analyzed %d files, want 1", len(r.Files))
+	}
+	f := r.Files[0]
+	if f.Lines != 14 || f.CommentLines != 1 {
+		t.Errorf("file lines, comments == %d, %d, want 14, 1", f.Lines, f.CommentLines)
+	}
+
+	cases := []struct {
+		name              string
+		lines, complexity int
+	}{
+		{"Classify", 11, 4},
+		{"get_user_Id", 1, 1},
+	}
+	for i, c := range cases {
+		fn := f.Funcs[i]
+		if fn.Name != c.name || fn.Lines != c.lines || fn.Complexity != c.complexity {
+			t.Errorf("Funcs[%d] == %s (%d lines, complexity %d), want %s (%d, %d)",
+				i, fn.Name, fn.Lines, fn.Complexity, c.name, c.lines, c.complexity)
+		}
+	}
+
+	if len(r.Issues) != 1 || !strings.Contains(r.Issues[0].Message, "underscores") {
+		t.Errorf("Issues == %v, want one underscore issue", r.Issues)
+	}
+
+	var b strings.Builder
+	if err := r.Render(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "Classify") {
+		t.Errorf("Render output missing Classify:\n%s", b.String())
+	}
+}
@@ -0,0 +1,244 @@
+// Package apisum summarizes the exported API of Go packages: their types,
+// functions and signatures, as a structured model that can be rendered as
+// Markdown or plain text.
+package apisum
+
+import "bytes"
+import "go/ast"
+import "go/build"
+import "go/doc"
+import "go/parser"
+import "go/printer"
+import "go/token"
+import "io/fs"
+import "os"
+import "path/filepath"
+import "strings"
+
+// Package is the exported API of one package.
+type Package struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	Doc        string // first sentence of the package comment
+	Consts     []Value
+	Vars       []Value
+	Funcs      []Func
+	Types      []Type
+}
+
+// Value is an exported const or var declaration.
+type Value struct {
+	Names []string
+	Doc   string
+}
+
+// Func is an exported function or method.
+type Func struct {
+	Name      string
+	Signature string // e.g. "func Add(x, y int) int"
+	Doc       string
+}
+
+// Type is an exported type along with its constructors and methods.
+type Type struct {
+	Name    string
+	Decl    string   // e.g. "type Align int" or "type Table struct"
+	Fields  []string // exported struct fields or interface methods
+	Doc     string
+	Funcs   []Func // functions returning the type
+	Methods []Func
+}
+
+// Load summarizes the packages matched by pattern. A pattern is a
+// directory, a directory followed by "/..." to include every package
+// beneath it, or an import path resolved through go/build.
+func Load(pattern string) ([]*Package, error) {
+	if root, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return loadTree(root)
+	}
+	dir := pattern
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		bp, err := build.Import(pattern, ".", build.FindOnly)
+		if err != nil {
+			return nil, err
+		}
+		dir = bp.Dir
+	}
+	p, err := LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return []*Package{p}, nil
+}
+
+func loadTree(root string) ([]*Package, error) {
+	var pkgs []*Package
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+			return filepath.SkipDir
+		}
+		p, err := LoadDir(path)
+		if _, ok := err.(*build.NoGoError); ok {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		pkgs = append(pkgs, p)
+		return nil
+	})
+	return pkgs, err
+}
+
+// LoadDir summarizes the package in dir, excluding test files.
+func LoadDir(dir string) (*Package, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	bp, err := build.ImportDir(abs, build.ImportComment)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	importPath := bp.ImportPath
+	if bp.ImportComment != "" {
+		importPath = bp.ImportComment
+	}
+	dp, err := doc.NewFromFiles(fset, files, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Package{
+		ImportPath: importPath,
+		Name:       dp.Name,
+		Dir:        dir,
+		Doc:        dp.Synopsis(dp.Doc),
+		Consts:     values(dp, dp.Consts),
+		Vars:       values(dp, dp.Vars),
+		Funcs:      funcs(fset, dp, dp.Funcs),
+	}
+	for _, t := range dp.Types {
+		typ := Type{
+			Name:    t.Name,
+			Doc:     dp.Synopsis(t.Doc),
+			Funcs:   funcs(fset, dp, t.Funcs),
+			Methods: funcs(fset, dp, t.Methods),
+		}
+		typ.Decl, typ.Fields = typeDecl(fset, t.Decl, t.Name)
+		p.Types = append(p.Types, typ)
+		// Exported consts and vars of the type are listed with the package.
+		p.Consts = append(p.Consts, values(dp, t.Consts)...)
+		p.Vars = append(p.Vars, values(dp, t.Vars)...)
+	}
+	return p, nil
+}
+
+func values(dp *doc.Package, vs []*doc.Value) []Value {
+	var out []Value
+	for _, v := range vs {
+		out = append(out, Value{Names: v.Names, Doc: dp.Synopsis(v.Doc)})
+	}
+	return out
+}
+
+func funcs(fset *token.FileSet, dp *doc.Package, fs []*doc.Func) []Func {
+	var out []Func
+	for _, f := range fs {
+		decl := *f.Decl
+		decl.Body = nil
+		decl.Doc = nil
+		out = append(out, Func{Name: f.Name, Signature: format(fset, &decl), Doc: dp.Synopsis(f.Doc)})
+	}
+	return out
+}
+
+// typeDecl returns a one-line declaration for the named type and, for
+// structs and interfaces, their exported members.
+func typeDecl(fset *token.FileSet, decl *ast.GenDecl, name string) (string, []string) {
+	for _, spec := range decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != name {
+			continue
+		}
+		head := "type " + name
+		if ts.TypeParams != nil {
+			head += typeParams(fset, ts.TypeParams)
+		}
+		var members []string
+		switch t := ts.Type.(type) {
+		case *ast.StructType:
+			for _, f := range t.Fields.List {
+				members = append(members, member(fset, f)...)
+			}
+			return head + " struct", members
+		case *ast.InterfaceType:
+			for _, f := range t.Methods.List {
+				members = append(members, member(fset, f)...)
+			}
+			return head + " interface", members
+		}
+		sep := " "
+		if ts.Assign.IsValid() {
+			sep = " = "
+		}
+		return head + sep + format(fset, ts.Type), nil
+	}
+	return "type " + name, nil
+}
+
+// typeParams renders a type parameter list such as "[K comparable, V any]".
+func typeParams(fset *token.FileSet, fl *ast.FieldList) string {
+	var params []string
+	for _, f := range fl.List {
+		var names []string
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+		params = append(params, strings.Join(names, ", ")+" "+format(fset, f.Type))
+	}
+	return "[" + strings.Join(params, ", ") + "]"
+}
+
+func member(fset *token.FileSet, f *ast.Field) []string {
+	if len(f.Names) == 0 {
+		return []string{format(fset, f.Type)} // embedded
+	}
+	var out []string
+	for _, n := range f.Names {
+		if !n.IsExported() {
+			continue
+		}
+		if ft, ok := f.Type.(*ast.FuncType); ok {
+			out = append(out, n.Name+strings.TrimPrefix(format(fset, ft), "func"))
+		} else {
+			out = append(out, n.Name+" "+format(fset, f.Type))
+		}
+	}
+	return out
+}
+
+func format(fset *token.FileSet, node any) string {
+	var b bytes.Buffer
+	printer.Fprint(&b, fset, node)
+	// Collapse declarations that the printer spread over several lines.
+	s := strings.Join(strings.Fields(b.String()), " ")
+	return strings.NewReplacer("( ", "(", ", )", ")").Replace(s)
+}
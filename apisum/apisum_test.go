@@ -0,0 +1,81 @@
+package apisum
+
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+const source = `// Package shapes draws shapes.
+package shapes
+
+// Pi is close enough.
+const Pi = 3.14
+
+// Shape is anything with an area.
+type Shape interface {
+	Area() float64
+}
+
+// Rect is a rectangle.
+type Rect struct {
+	W, H float64
+	name string
+}
+
+// NewRect returns a w by h rectangle.
+func NewRect(w, h float64) *Rect { return &Rect{W: w, H: h} }
+
+// Area returns the area of r.
+func (r *Rect) Area() float64 { return r.W * r.H }
+
+func Scale(s Shape, k float64) float64 { return s.Area() * k }
+
+func hidden() {}
+`
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "shapes.go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := pkgs[0]
+	if p.Name != "shapes" || p.Doc != "Package shapes draws shapes." {
+		t.Errorf("package = %q (%q)", p.Name, p.Doc)
+	}
+
+	var b strings.Builder
+	if err := p.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `package shapes // import "."
+
+Package shapes draws shapes.
+
+const Pi
+func Scale(s Shape, k float64) float64
+type Rect struct
+    W float64
+    H float64
+    func NewRect(w, h float64) *Rect
+    func (r *Rect) Area() float64
+type Shape interface
+    Area() float64
+`
+	if got := b.String(); got != want {
+		t.Errorf("WriteText() ==\n%s\nwant\n%s", got, want)
+	}
+
+	b.Reset()
+	if err := p.WriteMarkdown(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"# shapes", "### Rect", "#### NewRect", "Area returns the area of r."} {
+		if !strings.Contains(b.String(), s) {
+			t.Errorf("WriteMarkdown() missing %q:\n%s", s, b.String())
+		}
+	}
+}
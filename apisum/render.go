@@ -0,0 +1,110 @@
+package apisum
+
+import "fmt"
+import "io"
+import "strings"
+
+// WriteText writes p in a compact, go doc-like layout for the terminal:
+// declarations only, with methods and constructors indented beneath their
+// types.
+func (p *Package) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s // import %q\n", p.Name, p.ImportPath)
+	if p.Doc != "" {
+		fmt.Fprintf(&b, "\n%s\n", p.Doc)
+	}
+	if len(p.Consts)+len(p.Vars)+len(p.Funcs)+len(p.Types) > 0 {
+		b.WriteByte('\n')
+	}
+	for _, v := range p.Consts {
+		fmt.Fprintf(&b, "const %s\n", strings.Join(v.Names, ", "))
+	}
+	for _, v := range p.Vars {
+		fmt.Fprintf(&b, "var %s\n", strings.Join(v.Names, ", "))
+	}
+	for _, f := range p.Funcs {
+		fmt.Fprintln(&b, f.Signature)
+	}
+	for _, t := range p.Types {
+		fmt.Fprintln(&b, t.Decl)
+		for _, f := range t.Fields {
+			fmt.Fprintf(&b, "    %s\n", f)
+		}
+		for _, f := range t.Funcs {
+			fmt.Fprintf(&b, "    %s\n", f.Signature)
+		}
+		for _, f := range t.Methods {
+			fmt.Fprintf(&b, "    %s\n", f.Signature)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes p as a Markdown document with a section per
+// declaration kind and doc summaries alongside each signature.
+func (p *Package) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n`import %q`\n", p.Name, p.ImportPath)
+	if p.Doc != "" {
+		fmt.Fprintf(&b, "\n%s\n", p.Doc)
+	}
+
+	if len(p.Consts)+len(p.Vars) > 0 {
+		b.WriteString("\n## Constants and variables\n\n")
+		for _, v := range p.Consts {
+			writeValue(&b, "const", v)
+		}
+		for _, v := range p.Vars {
+			writeValue(&b, "var", v)
+		}
+	}
+
+	if len(p.Funcs) > 0 {
+		b.WriteString("\n## Functions\n")
+		for _, f := range p.Funcs {
+			writeFunc(&b, "###", f)
+		}
+	}
+
+	if len(p.Types) > 0 {
+		b.WriteString("\n## Types\n")
+		for _, t := range p.Types {
+			fmt.Fprintf(&b, "\n### %s\n\n```go\n%s", t.Name, t.Decl)
+			if len(t.Fields) > 0 {
+				b.WriteString(" {\n")
+				for _, f := range t.Fields {
+					fmt.Fprintf(&b, "\t%s\n", f)
+				}
+				b.WriteString("}")
+			}
+			b.WriteString("\n```\n")
+			if t.Doc != "" {
+				fmt.Fprintf(&b, "\n%s\n", t.Doc)
+			}
+			for _, f := range t.Funcs {
+				writeFunc(&b, "####", f)
+			}
+			for _, f := range t.Methods {
+				writeFunc(&b, "####", f)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeValue(b *strings.Builder, kind string, v Value) {
+	fmt.Fprintf(b, "- `%s %s`", kind, strings.Join(v.Names, ", "))
+	if v.Doc != "" {
+		fmt.Fprintf(b, " — %s", v.Doc)
+	}
+	b.WriteByte('\n')
+}
+
+func writeFunc(b *strings.Builder, heading string, f Func) {
+	fmt.Fprintf(b, "\n%s %s\n\n```go\n%s\n```\n", heading, f.Name, f.Signature)
+	if f.Doc != "" {
+		fmt.Fprintf(b, "\n%s\n", f.Doc)
+	}
+}
@@ -0,0 +1,249 @@
+// Package astcheck inspects a learner's Go source for required and
+// forbidden constructs and reports structured feedback for grading.
+package astcheck
+
+import "fmt"
+import "go/ast"
+import "go/parser"
+import "go/token"
+
+// A Finding is a single observation made by a Rule. Pos is the zero value
+// when the finding is about something missing from the source.
+type Finding struct {
+	Pos     token.Position
+	Message string
+}
+
+func (f Finding) String() string {
+	if !f.Pos.IsValid() {
+		return f.Message
+	}
+	return fmt.Sprintf("%s: %s", f.Pos, f.Message)
+}
+
+// A Rule checks a parsed file and returns its findings. A rule passes when
+// it returns no findings.
+type Rule struct {
+	Name  string
+	Check func(fset *token.FileSet, f *ast.File) []Finding
+}
+
+// Feedback is the outcome of applying one Rule.
+type Feedback struct {
+	Rule     string
+	Passed   bool
+	Findings []Finding
+}
+
+// Report is the outcome of applying a set of rules to one submission.
+type Report struct {
+	Feedback []Feedback
+}
+
+// Passed reports whether every rule passed.
+func (r Report) Passed() bool {
+	for _, fb := range r.Feedback {
+		if !fb.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Score returns the number of rules that passed and the number applied.
+func (r Report) Score() (passed, total int) {
+	for _, fb := range r.Feedback {
+		if fb.Passed {
+			passed++
+		}
+	}
+	return passed, len(r.Feedback)
+}
+
+// Check parses src, which may be a string, []byte or io.Reader as accepted
+// by go/parser, and applies each rule to it. A parse error is returned as
+// is so callers can show it to the learner.
+func Check(filename string, src any, rules ...Rule) (Report, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return Report{}, err
+	}
+	return CheckFile(fset, f, rules...), nil
+}
+
+// CheckFile applies each rule to an already parsed file.
+func CheckFile(fset *token.FileSet, f *ast.File, rules ...Rule) Report {
+	var r Report
+	for _, rule := range rules {
+		findings := rule.Check(fset, f)
+		r.Feedback = append(r.Feedback, Feedback{
+			Rule:     rule.Name,
+			Passed:   len(findings) == 0,
+			Findings: findings,
+		})
+	}
+	return r
+}
+
+// Require returns a rule that passes when at least one node satisfies
+// match. missing describes the construct for the failure message.
+func Require(name, missing string, match func(ast.Node) bool) Rule {
+	return Rule{
+		Name: name,
+		Check: func(fset *token.FileSet, f *ast.File) []Finding {
+			found := false
+			ast.Inspect(f, func(n ast.Node) bool {
+				if found || n == nil {
+					return false
+				}
+				found = match(n)
+				return !found
+			})
+			if found {
+				return nil
+			}
+			return []Finding{{Message: "expected " + missing}}
+		},
+	}
+}
+
+// Forbid returns a rule that reports every node satisfying match. The
+// message is attached to each offending position.
+func Forbid(name, message string, match func(ast.Node) bool) Rule {
+	return Rule{
+		Name: name,
+		Check: func(fset *token.FileSet, f *ast.File) []Finding {
+			var findings []Finding
+			ast.Inspect(f, func(n ast.Node) bool {
+				if n != nil && match(n) {
+					findings = append(findings, Finding{fset.Position(n.Pos()), message})
+				}
+				return true
+			})
+			return findings
+		},
+	}
+}
+
+// UsesForLoop requires a for or for-range statement.
+func UsesForLoop() Rule {
+	return Require("uses-for-loop", "a for loop", func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return true
+		}
+		return false
+	})
+}
+
+// UsesSwitch requires an expression or type switch statement.
+func UsesSwitch() Rule {
+	return Require("uses-switch", "a switch statement", func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			return true
+		}
+		return false
+	})
+}
+
+// DeclaresFunc requires a top-level function (not a method) called name.
+func DeclaresFunc(name string) Rule {
+	return Require("declares-func-"+name, "a function named "+name, func(n ast.Node) bool {
+		fd, ok := n.(*ast.FuncDecl)
+		return ok && fd.Recv == nil && fd.Name.Name == name
+	})
+}
+
+// DeclaresPointerMethod requires a method with a pointer receiver.
+func DeclaresPointerMethod() Rule {
+	return Require("declares-pointer-method", "a method on a pointer receiver", func(n ast.Node) bool {
+		fd, ok := n.(*ast.FuncDecl)
+		if !ok || fd.Recv == nil || len(fd.Recv.List) == 0 {
+			return false
+		}
+		_, ok = fd.Recv.List[0].Type.(*ast.StarExpr)
+		return ok
+	})
+}
+
+// Calls requires a call to pkg.name, such as Calls("fmt", "Println"). An
+// empty pkg matches a call to a plain identifier.
+func Calls(pkg, name string) Rule {
+	label := name
+	if pkg != "" {
+		label = pkg + "." + name
+	}
+	return Require("calls-"+label, "a call to "+label, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			return pkg == "" && fn.Name == name
+		case *ast.SelectorExpr:
+			x, ok := fn.X.(*ast.Ident)
+			return ok && x.Name == pkg && fn.Sel.Name == name
+		}
+		return false
+	})
+}
+
+// NoNakedReturns forbids bare return statements in functions with named
+// results whose bodies span more than maxLines lines. Short functions are
+// allowed them, as suggested by Effective Go.
+func NoNakedReturns(maxLines int) Rule {
+	return Rule{
+		Name: "no-long-naked-returns",
+		Check: func(fset *token.FileSet, f *ast.File) []Finding {
+			var findings []Finding
+			ast.Inspect(f, func(n ast.Node) bool {
+				var typ *ast.FuncType
+				var body *ast.BlockStmt
+				switch fn := n.(type) {
+				case *ast.FuncDecl:
+					typ, body = fn.Type, fn.Body
+				case *ast.FuncLit:
+					typ, body = fn.Type, fn.Body
+				default:
+					return true
+				}
+				if body == nil || !namedResults(typ) {
+					return true
+				}
+				lines := fset.Position(body.End()).Line - fset.Position(body.Pos()).Line + 1
+				if lines <= maxLines {
+					return true
+				}
+				ast.Inspect(body, func(m ast.Node) bool {
+					if _, ok := m.(*ast.FuncLit); ok {
+						return false // checked on its own
+					}
+					if ret, ok := m.(*ast.ReturnStmt); ok && len(ret.Results) == 0 {
+						findings = append(findings, Finding{
+							fset.Position(ret.Pos()),
+							fmt.Sprintf("naked return in a function of %d lines (limit %d)", lines, maxLines),
+						})
+					}
+					return true
+				})
+				return true
+			})
+			return findings
+		},
+	}
+}
+
+func namedResults(typ *ast.FuncType) bool {
+	if typ.Results == nil {
+		return false
+	}
+	for _, field := range typ.Results.List {
+		if len(field.Names) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+package astcheck
+
+import "testing"
+
+const submission = `package main
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() { c.n++ }
+
+func split(sum int) (x, y int) {
+	x = sum * 4 / 9
+	y = sum - x
+	return
+}
+
+func main() {
+	for i := 0; i < 3; i++ {
+		println(i)
+	}
+}
+`
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		rule Rule
+		want bool
+	}{
+		{UsesForLoop(), true},
+		{UsesSwitch(), false},
+		{DeclaresPointerMethod(), true},
+		{DeclaresFunc("split"), true},
+		{DeclaresFunc("Inc"), false},
+		{Calls("", "println"), true},
+		{Calls("fmt", "Println"), false},
+		{NoNakedReturns(10), true},
+		{NoNakedReturns(3), false},
+	}
+	for _, c := range cases {
+		r, err := Check("main.go", submission, c.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.Passed(); got != c.want {
+			t.Errorf("%s passed == %v, want %v (%v)", c.rule.Name, got, c.want, r.Feedback[0].Findings)
+		}
+	}
+}
+
+func TestCheckParseError(t *testing.T) {
+	if _, err := Check("main.go", "package main\nfunc {", UsesForLoop()); err == nil {
+		t.Error("Check of invalid source returned no error")
+	}
+}
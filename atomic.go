@@ -0,0 +1,103 @@
+package golib
+
+import "sync/atomic"
+
+// Atomic holds a value of type T that goroutines may load and store
+// concurrently. Each store boxes the value, so for int64 and bool prefer
+// AtomicInt64 and AtomicBool, which do not allocate. The zero value holds
+// the zero T. An Atomic must not be copied after first use.
+type Atomic[T comparable] struct {
+	p atomic.Pointer[T]
+}
+
+// NewAtomic returns an Atomic holding v.
+func NewAtomic[T comparable](v T) *Atomic[T] {
+	a := new(Atomic[T])
+	a.Store(v)
+	return a
+}
+
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
+
+// Load returns the value.
+func (a *Atomic[T]) Load() T { return deref(a.p.Load()) }
+
+// Store sets the value to v.
+func (a *Atomic[T]) Store(v T) { a.p.Store(&v) }
+
+// Swap sets the value to v and returns the value it replaced.
+func (a *Atomic[T]) Swap(v T) T { return deref(a.p.Swap(&v)) }
+
+// CompareAndSwap sets the value to new if it equals old, comparing with
+// ==, and reports whether it did.
+func (a *Atomic[T]) CompareAndSwap(old, new T) bool {
+	for {
+		p := a.p.Load()
+		if deref(p) != old {
+			return false
+		}
+		if a.p.CompareAndSwap(p, &new) {
+			return true
+		}
+	}
+}
+
+// Update sets the value to fn of the value and returns the result. If
+// another goroutine changes the value meanwhile, it calls fn again with
+// the new value, so fn may run more than once and should have no side
+// effects.
+func (a *Atomic[T]) Update(fn func(T) T) T {
+	for {
+		p := a.p.Load()
+		v := fn(deref(p))
+		if a.p.CompareAndSwap(p, &v) {
+			return v
+		}
+	}
+}
+
+// AtomicInt64 is an atomic.Int64 with Update. The zero value is zero.
+type AtomicInt64 struct {
+	atomic.Int64
+}
+
+// Update sets the value to fn of the value and returns the result,
+// retrying as Atomic.Update does.
+func (a *AtomicInt64) Update(fn func(int64) int64) int64 {
+	for {
+		old := a.Load()
+		v := fn(old)
+		if a.CompareAndSwap(old, v) {
+			return v
+		}
+	}
+}
+
+// AtomicBool is an atomic.Bool with Update and Toggle. The zero value is
+// false.
+type AtomicBool struct {
+	atomic.Bool
+}
+
+// Update sets the value to fn of the value and returns the result,
+// retrying as Atomic.Update does.
+func (a *AtomicBool) Update(fn func(bool) bool) bool {
+	for {
+		old := a.Load()
+		v := fn(old)
+		if a.CompareAndSwap(old, v) {
+			return v
+		}
+	}
+}
+
+// Toggle negates the value and returns the new value.
+func (a *AtomicBool) Toggle() bool {
+	return a.Update(func(b bool) bool { return !b })
+}
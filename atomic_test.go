@@ -0,0 +1,55 @@
+package golib
+
+import "sync"
+import "testing"
+
+func TestAtomic(t *testing.T) {
+	var a Atomic[string]
+	if got := a.Load(); got != "" {
+		t.Fatalf("zero Load = %q", got)
+	}
+	if !a.CompareAndSwap("", "a") {
+		t.Fatal("CompareAndSwap from zero failed")
+	}
+	if a.CompareAndSwap("b", "c") {
+		t.Fatal("CompareAndSwap with wrong old succeeded")
+	}
+	if old := a.Swap("b"); old != "a" {
+		t.Fatalf("Swap returned %q", old)
+	}
+	if got := a.Update(func(s string) string { return s + "!" }); got != "b!" {
+		t.Fatalf("Update = %q", got)
+	}
+	if got := NewAtomic(3).Load(); got != 3 {
+		t.Fatalf("NewAtomic(3).Load() = %d", got)
+	}
+}
+
+func TestAtomicUpdateContended(t *testing.T) {
+	type point struct{ x, y int }
+	var a Atomic[point]
+	var i64 AtomicInt64
+	var b AtomicBool
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				a.Update(func(p point) point { return point{p.x + 1, p.y + 2} })
+				i64.Update(func(n int64) int64 { return n + 1 })
+				b.Toggle()
+			}
+		}()
+	}
+	wg.Wait()
+	if got := a.Load(); got != (point{8000, 16000}) {
+		t.Errorf("Atomic = %v", got)
+	}
+	if got := i64.Load(); got != 8000 {
+		t.Errorf("AtomicInt64 = %d", got)
+	}
+	if b.Load() {
+		t.Error("AtomicBool toggled an odd number of times")
+	}
+}
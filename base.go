@@ -0,0 +1,119 @@
+package golib
+
+import "fmt"
+import "math"
+import "strings"
+
+// baseDigits are the digits for bases up to 62. The first 36 match
+// strconv, so ToBase(n, 16) == strconv.FormatInt(n, 16).
+const baseDigits = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// ToBase returns n written in base, which must be between 2 and 62.
+// Digits beyond 9 are a-z and then A-Z. It panics for other bases, as
+// strconv.FormatInt does.
+func ToBase(n int64, base int) string {
+	if base < 2 || base > len(baseDigits) {
+		panic(fmt.Sprintf("golib: invalid base %d", base))
+	}
+	if n == 0 {
+		return "0"
+	}
+	u := uint64(n)
+	if n < 0 {
+		u = -u // two's complement: correct even for math.MinInt64
+	}
+	var buf [65]byte // 64 binary digits and a sign
+	i := len(buf)
+	for u > 0 {
+		i--
+		buf[i] = baseDigits[u%uint64(base)]
+		u /= uint64(base)
+	}
+	if n < 0 {
+		i--
+		buf[i] = '-'
+	}
+	return string(buf[i:])
+}
+
+// FromBase parses s, an integer in base between 2 and 62 with an optional
+// sign. Up to base 36 digits are case-insensitive, as in strconv; above
+// that, lower and upper case are different digits.
+func FromBase(s string, base int) (int64, error) {
+	if base < 2 || base > len(baseDigits) {
+		return 0, fmt.Errorf("golib: invalid base %d", base)
+	}
+	neg := false
+	digits := s
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		neg, digits = digits[0] == '-', digits[1:]
+	}
+	if digits == "" {
+		return 0, fmt.Errorf("golib: invalid base-%d number %q", base, s)
+	}
+
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++ // -MinInt64 is one more than MaxInt64
+	}
+	var u uint64
+	for i := 0; i < len(digits); i++ {
+		c := digits[i]
+		if base <= 36 && c >= 'A' && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		d := strings.IndexByte(baseDigits[:base], c)
+		if d < 0 {
+			return 0, fmt.Errorf("golib: invalid base-%d number %q", base, s)
+		}
+		if u > (limit-uint64(d))/uint64(base) {
+			return 0, fmt.Errorf("golib: base-%d number %q overflows int64", base, s)
+		}
+		u = u*uint64(base) + uint64(d)
+	}
+	if neg {
+		return int64(-u), nil
+	}
+	return int64(u), nil
+}
+
+// Binary returns n in base 2 with a "0b" prefix, after any sign.
+func Binary(n int64) string { return prefixed(n, 2, "0b") }
+
+// Octal returns n in base 8 with a "0o" prefix, after any sign.
+func Octal(n int64) string { return prefixed(n, 8, "0o") }
+
+// Hex returns n in base 16 with a "0x" prefix, after any sign.
+func Hex(n int64) string { return prefixed(n, 16, "0x") }
+
+// ParseBinary parses a base-2 number with or without a "0b" prefix.
+func ParseBinary(s string) (int64, error) { return parsePrefixed(s, 2, "0b") }
+
+// ParseOctal parses a base-8 number with or without a "0o" prefix.
+func ParseOctal(s string) (int64, error) { return parsePrefixed(s, 8, "0o") }
+
+// ParseHex parses a base-16 number with or without a "0x" prefix.
+func ParseHex(s string) (int64, error) { return parsePrefixed(s, 16, "0x") }
+
+func prefixed(n int64, base int, prefix string) string {
+	s := ToBase(n, base)
+	if n < 0 {
+		return "-" + prefix + s[1:]
+	}
+	return prefix + s
+}
+
+func parsePrefixed(s string, base int, prefix string) (int64, error) {
+	sign, rest := "", s
+	if strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+		sign, rest = rest[:1], rest[1:]
+	}
+	if len(rest) >= 2 && strings.EqualFold(rest[:2], prefix) {
+		rest = rest[2:]
+	}
+	n, err := FromBase(sign+rest, base)
+	if err != nil || strings.HasPrefix(rest, "-") || strings.HasPrefix(rest, "+") {
+		return 0, fmt.Errorf("golib: invalid base-%d number %q", base, s)
+	}
+	return n, nil
+}
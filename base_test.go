@@ -0,0 +1,105 @@
+package golib
+
+import "math"
+import "strconv"
+import "strings"
+import "testing"
+
+func TestToBase(t *testing.T) {
+	cases := []struct {
+		n    int64
+		base int
+		want string
+	}{
+		{0, 2, "0"},
+		{10, 2, "1010"},
+		{255, 16, "ff"},
+		{-255, 16, "-ff"},
+		{61, 62, "Z"},
+		{62, 62, "10"},
+		{3843, 62, "ZZ"},
+		{math.MaxInt64, 62, "aZl8N0y58M7"},
+		{math.MinInt64, 2, "-1" + strings.Repeat("0", 63)},
+	}
+	for _, c := range cases {
+		got := ToBase(c.n, c.base)
+		if got != c.want {
+			t.Errorf("ToBase(%d, %d) == %q, want %q", c.n, c.base, got, c.want)
+		}
+		if back, err := FromBase(got, c.base); back != c.n || err != nil {
+			t.Errorf("FromBase(%q, %d) == %d, %v, want %d", got, c.base, back, err, c.n)
+		}
+	}
+	// Bases up to 36 agree with strconv.
+	for base := 2; base <= 36; base++ {
+		for _, n := range []int64{1, -7, 12345, math.MaxInt64, math.MinInt64} {
+			if got, want := ToBase(n, base), strconv.FormatInt(n, base); got != want {
+				t.Errorf("ToBase(%d, %d) == %q, strconv gives %q", n, base, got, want)
+			}
+		}
+	}
+}
+
+func TestFromBase(t *testing.T) {
+	if n, err := FromBase("FF", 16); n != 255 || err != nil {
+		t.Errorf("FromBase(FF, 16) == %d, %v", n, err)
+	}
+	if n, _ := FromBase("a", 62); n != 10 {
+		t.Errorf("FromBase(a, 62) == %d", n)
+	}
+	if n, _ := FromBase("A", 62); n != 36 {
+		t.Errorf("FromBase(A, 62) == %d", n)
+	}
+	bad := []struct {
+		s    string
+		base int
+	}{
+		{"", 10}, {"-", 10}, {"12", 2}, {"g", 16}, {"1", 1}, {"1", 63},
+		{"9223372036854775808", 10}, {"-9223372036854775809", 10},
+	}
+	for _, c := range bad {
+		if _, err := FromBase(c.s, c.base); err == nil {
+			t.Errorf("FromBase(%q, %d) succeeded", c.s, c.base)
+		}
+	}
+	if n, err := FromBase("-9223372036854775808", 10); n != math.MinInt64 || err != nil {
+		t.Errorf("FromBase(MinInt64) == %d, %v", n, err)
+	}
+}
+
+func TestPrefixed(t *testing.T) {
+	if got := Binary(5); got != "0b101" {
+		t.Errorf("Binary(5) == %q", got)
+	}
+	if got := Octal(-8); got != "-0o10" {
+		t.Errorf("Octal(-8) == %q", got)
+	}
+	if got := Hex(255); got != "0xff" {
+		t.Errorf("Hex(255) == %q", got)
+	}
+	cases := []struct {
+		parse func(string) (int64, error)
+		in    string
+		want  int64
+	}{
+		{ParseBinary, "0b101", 5},
+		{ParseBinary, "101", 5},
+		{ParseOctal, "0O17", 15},
+		{ParseOctal, "-0o10", -8},
+		{ParseHex, "0xFF", 255},
+		{ParseHex, "+ff", 255},
+	}
+	for _, c := range cases {
+		if got, err := c.parse(c.in); got != c.want || err != nil {
+			t.Errorf("parse(%q) == %d, %v, want %d", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"0x", "0x-1", "-+ff", "0xg"} {
+		if _, err := ParseHex(in); err == nil {
+			t.Errorf("ParseHex(%q) succeeded", in)
+		}
+	}
+	if _, err := ParseBinary("0b2"); err == nil {
+		t.Error("ParseBinary(0b2) succeeded")
+	}
+}
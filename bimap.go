@@ -0,0 +1,84 @@
+package golib
+
+import "iter"
+import "maps"
+
+// BiMap is a one-to-one map that can be looked up in both directions,
+// such as between IDs and names. Each key has one value and each value
+// one key. The zero value is not usable; call NewBiMap. A BiMap is not
+// safe for concurrent use.
+type BiMap[K, V comparable] struct {
+	fwd map[K]V
+	rev map[V]K
+}
+
+// NewBiMap returns an empty BiMap.
+func NewBiMap[K, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{fwd: map[K]V{}, rev: map[V]K{}}
+}
+
+// Len returns the number of pairs in m.
+func (m *BiMap[K, V]) Len() int { return len(m.fwd) }
+
+// Put pairs k with v. Any existing pair with key k or value v is removed
+// first, so the map stays one-to-one.
+func (m *BiMap[K, V]) Put(k K, v V) {
+	m.DeleteKey(k)
+	m.DeleteValue(v)
+	m.fwd[k] = v
+	m.rev[v] = k
+}
+
+// TryPut pairs k with v only if neither is already in m, and reports
+// whether it did.
+func (m *BiMap[K, V]) TryPut(k K, v V) bool {
+	if _, ok := m.fwd[k]; ok {
+		return false
+	}
+	if _, ok := m.rev[v]; ok {
+		return false
+	}
+	m.fwd[k] = v
+	m.rev[v] = k
+	return true
+}
+
+// Get returns the value paired with k.
+func (m *BiMap[K, V]) Get(k K) (V, bool) {
+	v, ok := m.fwd[k]
+	return v, ok
+}
+
+// GetKey returns the key paired with v.
+func (m *BiMap[K, V]) GetKey(v V) (K, bool) {
+	k, ok := m.rev[v]
+	return k, ok
+}
+
+// DeleteKey removes the pair with key k and reports whether there was one.
+func (m *BiMap[K, V]) DeleteKey(k K) bool {
+	v, ok := m.fwd[k]
+	if ok {
+		delete(m.fwd, k)
+		delete(m.rev, v)
+	}
+	return ok
+}
+
+// DeleteValue removes the pair with value v and reports whether there was
+// one.
+func (m *BiMap[K, V]) DeleteValue(v V) bool {
+	k, ok := m.rev[v]
+	if ok {
+		delete(m.rev, v)
+		delete(m.fwd, k)
+	}
+	return ok
+}
+
+// Inverse returns m with keys and values swapped. It shares m's storage,
+// so changes to either are seen by both.
+func (m *BiMap[K, V]) Inverse() *BiMap[V, K] { return &BiMap[V, K]{fwd: m.rev, rev: m.fwd} }
+
+// All yields the pairs of m in no particular order.
+func (m *BiMap[K, V]) All() iter.Seq2[K, V] { return maps.All(m.fwd) }
@@ -0,0 +1,56 @@
+package golib
+
+import "testing"
+
+func TestBiMap(t *testing.T) {
+	m := NewBiMap[int, string]()
+	m.Put(1, "one")
+	m.Put(2, "two")
+	if v, ok := m.Get(1); v != "one" || !ok {
+		t.Errorf("Get(1) == %q, %v", v, ok)
+	}
+	if k, ok := m.GetKey("two"); k != 2 || !ok {
+		t.Errorf("GetKey(two) == %d, %v", k, ok)
+	}
+
+	// Re-pairing a value removes its old key, and re-pairing a key its
+	// old value.
+	m.Put(3, "one")
+	if _, ok := m.Get(1); ok {
+		t.Error("key 1 survived its value moving to 3")
+	}
+	m.Put(2, "deux")
+	if _, ok := m.GetKey("two"); ok {
+		t.Error("value two survived its key moving to deux")
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() == %d, want 2", m.Len())
+	}
+
+	if m.TryPut(3, "trois") || m.TryPut(4, "deux") || !m.TryPut(4, "four") {
+		t.Error("TryPut accepted a conflict or refused a new pair")
+	}
+
+	inv := m.Inverse()
+	if k, ok := inv.Get("four"); k != 4 || !ok {
+		t.Errorf("Inverse().Get(four) == %d, %v", k, ok)
+	}
+	inv.DeleteKey("four")
+	if _, ok := m.Get(4); ok || m.Len() != 2 {
+		t.Error("deleting through the inverse did not change the map")
+	}
+	if !m.DeleteValue("one") || m.DeleteValue("one") || m.DeleteKey(3) {
+		t.Error("DeleteValue or DeleteKey reported wrongly")
+	}
+
+	n := 0
+	for k, v := range m.All() {
+		if k != 2 || v != "deux" {
+			t.Errorf("All() yielded %d, %q", k, v)
+		}
+		n++
+	}
+	if n != 1 {
+		t.Errorf("All() yielded %d pairs, want 1", n)
+	}
+}
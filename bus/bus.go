@@ -0,0 +1,101 @@
+// Package bus provides an in-process publish/subscribe bus that fans each
+// published value out to every subscriber.
+package bus
+
+import "sync"
+import "sync/atomic"
+
+// Bus broadcasts values of type T to its subscribers. Publishing never
+// blocks: a subscriber whose buffer is full misses the value, and the miss
+// is counted in its Dropped total.
+type Bus[T any] struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription[T]]struct{}
+	closed bool
+}
+
+// New returns a bus with no subscribers.
+func New[T any]() *Bus[T] {
+	return &Bus[T]{subs: map[*Subscription[T]]struct{}{}}
+}
+
+// Subscription receives published values on C until it or the bus is
+// closed, at which point C is closed.
+type Subscription[T any] struct {
+	C       <-chan T
+	c       chan T
+	bus     *Bus[T]
+	dropped atomic.Int64
+}
+
+// Subscribe registers a subscriber whose channel buffers up to buffer
+// values. Subscribing to a closed bus returns a subscription whose channel
+// is already closed.
+func (b *Bus[T]) Subscribe(buffer int) *Subscription[T] {
+	c := make(chan T, buffer)
+	s := &Subscription[T]{C: c, c: c, bus: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(c)
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Publish sends v to every subscriber with room in its buffer and returns
+// how many received it.
+func (b *Bus[T]) Publish(v T) int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	n := 0
+	for s := range b.subs {
+		select {
+		case s.c <- v:
+			n++
+		default:
+			s.dropped.Add(1)
+		}
+	}
+	return n
+}
+
+// Len returns the number of current subscribers.
+func (b *Bus[T]) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Close closes every subscription. Later publishes are discarded.
+func (b *Bus[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for s := range b.subs {
+		close(s.c)
+	}
+	b.subs = nil
+}
+
+// Close unsubscribes s and closes its channel. It is safe to call more
+// than once.
+func (s *Subscription[T]) Close() {
+	b := s.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.subs[s]; ok {
+		delete(b.subs, s)
+		close(s.c)
+	}
+}
+
+// Dropped returns the number of values s missed because its buffer was
+// full.
+func (s *Subscription[T]) Dropped() int64 {
+	return s.dropped.Load()
+}
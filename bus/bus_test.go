@@ -0,0 +1,38 @@
+package bus
+
+import "testing"
+
+func TestBus(t *testing.T) {
+	b := New[string]()
+	fast := b.Subscribe(4)
+	slow := b.Subscribe(1)
+
+	for _, msg := range []string{"a", "b", "c"} {
+		b.Publish(msg)
+	}
+	if got := slow.Dropped(); got != 2 {
+		t.Errorf("slow.Dropped() == %d, want 2", got)
+	}
+	if got := <-slow.C; got != "a" {
+		t.Errorf("slow received %q, want %q", got, "a")
+	}
+
+	slow.Close()
+	slow.Close()
+	if n := b.Publish("d"); n != 1 || b.Len() != 1 {
+		t.Errorf("Publish after unsubscribe reached %d of %d subscribers, want 1 of 1", n, b.Len())
+	}
+
+	b.Close()
+	var got []string
+	for msg := range fast.C {
+		got = append(got, msg)
+	}
+	if len(got) != 4 {
+		t.Errorf("fast received %q, want a, b, c, d", got)
+	}
+	if _, ok := <-b.Subscribe(1).C; ok {
+		t.Error("Subscribe on a closed bus returned an open channel")
+	}
+	fast.Close() // already closed by the bus
+}
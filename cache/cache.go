@@ -0,0 +1,93 @@
+// Package cache provides an in-memory cache whose entries expire after a
+// fixed time to live.
+package cache
+
+import "sync"
+import "time"
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// Cache maps keys to values that expire ttl after they are set. Expired
+// entries are dropped lazily, with a full sweep at most once per ttl, so
+// no background goroutine is needed. It is safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	items     map[K]entry[V]
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New returns an empty cache whose entries live for ttl.
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	return &Cache[K, V]{ttl: ttl, items: map[K]entry[V]{}, now: time.Now}
+}
+
+// Get returns the value for key if it is present and unexpired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok || !c.now().Before(e.expires) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key, replacing any existing entry and restarting
+// its time to live.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+	c.items[key] = entry[V]{value, c.now().Add(c.ttl)}
+}
+
+// Add stores value under key only if no unexpired entry exists, and
+// reports whether it did. It makes the cache usable as a set of recently
+// seen keys, for example to drop duplicate deliveries.
+func (c *Cache[K, V]) Add(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sweep()
+	now := c.now()
+	if e, ok := c.items[key]; ok && now.Before(e.expires) {
+		return false
+	}
+	c.items[key] = entry[V]{value, now.Add(c.ttl)}
+	return true
+}
+
+// Delete removes key.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+}
+
+// Len returns the number of entries, including expired ones not yet
+// swept.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.items)
+}
+
+// sweep drops expired entries if a ttl has passed since the last sweep.
+// c.mu must be held.
+func (c *Cache[K, V]) sweep() {
+	now := c.now()
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for k, e := range c.items {
+		if !now.Before(e.expires) {
+			delete(c.items, k)
+		}
+	}
+}
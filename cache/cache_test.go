@@ -0,0 +1,44 @@
+package cache
+
+import "testing"
+import "time"
+
+func TestCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := New[string, int](time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	if v, ok := c.Get("a"); v != 1 || !ok {
+		t.Errorf("Get(a) == %d, %v, want 1, true", v, ok)
+	}
+	if c.Add("a", 2) {
+		t.Error("Add of a live key succeeded")
+	}
+
+	now = now.Add(30 * time.Second)
+	if !c.Add("b", 3) {
+		t.Error("Add of a new key failed")
+	}
+
+	now = now.Add(31 * time.Second) // a has expired, b has not
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get returned an expired entry")
+	}
+	if !c.Add("a", 4) {
+		t.Error("Add of an expired key failed")
+	}
+	if v, _ := c.Get("b"); v != 3 {
+		t.Errorf("Get(b) == %d, want 3", v)
+	}
+
+	now = now.Add(2 * time.Minute)
+	c.Set("c", 5) // triggers a sweep of a and b
+	if c.Len() != 1 {
+		t.Errorf("Len after sweep == %d, want 1", c.Len())
+	}
+	c.Delete("c")
+	if _, ok := c.Get("c"); ok {
+		t.Error("Get returned a deleted entry")
+	}
+}
@@ -0,0 +1,250 @@
+// Package callgraph extracts a static function call graph from a Go
+// package and renders it as Graphviz DOT or as an ASCII tree.
+//
+// Calls are resolved with go/types, so method calls and calls into other
+// packages are attributed to the function actually named. Calls through
+// interface values and function variables are not followed.
+package callgraph
+
+import "fmt"
+import "go/ast"
+import "go/build"
+import "go/importer"
+import "go/parser"
+import "go/token"
+import "go/types"
+import "io"
+import "path/filepath"
+import "sort"
+import "strings"
+
+// Graph is a directed call graph. Node names are qualified by package name
+// for functions outside the analyzed package, and written as T.M or (*T).M
+// for methods.
+type Graph struct {
+	// Package is the name of the analyzed package.
+	Package string
+	// Funcs lists the functions declared in the package, sorted.
+	Funcs []string
+	// Calls maps each caller to its sorted, distinct callees.
+	Calls map[string][]string
+}
+
+// Load parses and type-checks the package in dir, excluding test files,
+// and returns its call graph.
+func Load(dir string) (*Graph, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	info := &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+		Uses: map[*ast.Ident]types.Object{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check(bp.ImportPath, fset, files, info)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{Package: pkg.Name(), Calls: map[string][]string{}}
+	for _, f := range files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			fn, ok := info.Defs[fd.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			caller := g.name(pkg, fn)
+			g.Funcs = append(g.Funcs, caller)
+			if fd.Body == nil {
+				continue
+			}
+			seen := map[string]bool{}
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				var id *ast.Ident
+				switch fun := ast.Unparen(call.Fun).(type) {
+				case *ast.Ident:
+					id = fun
+				case *ast.SelectorExpr:
+					id = fun.Sel
+				case *ast.IndexExpr: // explicit instantiation
+					id = identOf(fun.X)
+				case *ast.IndexListExpr:
+					id = identOf(fun.X)
+				}
+				if id == nil {
+					return true
+				}
+				callee, ok := info.Uses[id].(*types.Func)
+				if !ok {
+					return true
+				}
+				if sig, ok := callee.Type().(*types.Signature); ok && sig.Recv() != nil && types.IsInterface(sig.Recv().Type()) {
+					return true
+				}
+				name := g.name(pkg, callee.Origin())
+				if !seen[name] {
+					seen[name] = true
+					g.Calls[caller] = append(g.Calls[caller], name)
+				}
+				return true
+			})
+			sort.Strings(g.Calls[caller])
+		}
+	}
+	sort.Strings(g.Funcs)
+	return g, nil
+}
+
+func identOf(x ast.Expr) *ast.Ident {
+	switch x := x.(type) {
+	case *ast.Ident:
+		return x
+	case *ast.SelectorExpr:
+		return x.Sel
+	}
+	return nil
+}
+
+func (g *Graph) name(pkg *types.Package, fn *types.Func) string {
+	qualifier := func(p *types.Package) string {
+		if p == pkg {
+			return ""
+		}
+		return p.Name()
+	}
+	sig := fn.Type().(*types.Signature)
+	if recv := sig.Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			return fmt.Sprintf("(*%s).%s", typeName(ptr.Elem(), qualifier), fn.Name())
+		}
+		return typeName(t, qualifier) + "." + fn.Name()
+	}
+	if q := qualifier(fn.Pkg()); q != "" {
+		return q + "." + fn.Name()
+	}
+	return fn.Name()
+}
+
+// typeName writes a receiver's named type without its type arguments.
+func typeName(t types.Type, q types.Qualifier) string {
+	if named, ok := t.(*types.Named); ok {
+		obj := named.Obj()
+		if p := q(obj.Pkg()); p != "" {
+			return p + "." + obj.Name()
+		}
+		return obj.Name()
+	}
+	return types.TypeString(t, q)
+}
+
+// internal reports whether name was declared in the analyzed package.
+func (g *Graph) internal(name string) bool {
+	i := sort.SearchStrings(g.Funcs, name)
+	return i < len(g.Funcs) && g.Funcs[i] == name
+}
+
+// Roots returns the package's functions that no other package function
+// calls, which are the natural entry points for rendering.
+func (g *Graph) Roots() []string {
+	called := map[string]bool{}
+	for caller, callees := range g.Calls {
+		for _, c := range callees {
+			if c != caller {
+				called[c] = true
+			}
+		}
+	}
+	var roots []string
+	for _, fn := range g.Funcs {
+		if !called[fn] {
+			roots = append(roots, fn)
+		}
+	}
+	return roots
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Functions from other
+// packages are drawn as grey boxes.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", g.Package)
+	b.WriteString("\tnode [shape=ellipse];\n")
+	external := map[string]bool{}
+	for _, fn := range g.Funcs {
+		fmt.Fprintf(&b, "\t%q;\n", fn)
+	}
+	for _, caller := range g.Funcs {
+		for _, callee := range g.Calls[caller] {
+			if !g.internal(callee) && !external[callee] {
+				external[callee] = true
+				fmt.Fprintf(&b, "\t%q [shape=box, color=grey];\n", callee)
+			}
+			fmt.Fprintf(&b, "\t%q -> %q;\n", caller, callee)
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteTree writes the calls reachable from each root as an indented tree.
+// Functions already expanded are marked with "..." and recursive calls with
+// "(recursive)" rather than being expanded again. With no roots, Roots is
+// used.
+func (g *Graph) WriteTree(w io.Writer, roots ...string) error {
+	if len(roots) == 0 {
+		roots = g.Roots()
+	}
+	var b strings.Builder
+	expanded := map[string]bool{}
+	path := map[string]bool{}
+	var walk func(name, prefix string)
+	walk = func(name, prefix string) {
+		path[name] = true
+		expanded[name] = true
+		callees := g.Calls[name]
+		for i, c := range callees {
+			branch, next := "├── ", "│   "
+			if i == len(callees)-1 {
+				branch, next = "└── ", "    "
+			}
+			b.WriteString(prefix + branch + c)
+			switch {
+			case path[c]:
+				b.WriteString(" (recursive)\n")
+			case expanded[c] && len(g.Calls[c]) > 0:
+				b.WriteString(" ...\n")
+			default:
+				b.WriteByte('\n')
+				walk(c, prefix+next)
+			}
+		}
+		delete(path, name)
+	}
+	for _, root := range roots {
+		b.WriteString(root + "\n")
+		walk(root, "")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,73 @@
+package callgraph
+
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+const program = `package demo
+
+import "strings"
+
+type T struct{}
+
+func (t *T) Shout(s string) string { return strings.ToUpper(s) }
+
+func fact(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func Run() string {
+	var t T
+	return t.Shout("x") + string(rune(fact(3)))
+}
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "demo.go"), []byte(program), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		caller string
+		want   string
+	}{
+		{"Run", "(*T).Shout fact"},
+		{"(*T).Shout", "strings.ToUpper"},
+		{"fact", "fact"},
+	}
+	for _, c := range cases {
+		if got := strings.Join(g.Calls[c.caller], " "); got != c.want {
+			t.Errorf("Calls[%q] == %q, want %q", c.caller, got, c.want)
+		}
+	}
+
+	var tree strings.Builder
+	if err := g.WriteTree(&tree); err != nil {
+		t.Fatal(err)
+	}
+	want := "Run\n" +
+		"├── (*T).Shout\n" +
+		"│   └── strings.ToUpper\n" +
+		"└── fact\n" +
+		"    └── fact (recursive)\n"
+	if tree.String() != want {
+		t.Errorf("WriteTree() ==\n%s\nwant\n%s", tree.String(), want)
+	}
+
+	var dot strings.Builder
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(dot.String(), `"Run" -> "fact";`) {
+		t.Errorf("WriteDOT() missing Run -> fact edge:\n%s", dot.String())
+	}
+}
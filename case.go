@@ -0,0 +1,80 @@
+package golib
+
+import "strings"
+import "unicode"
+
+// SnakeCase returns s as lowercase words joined by underscores, splitting
+// at punctuation, spaces and changes of case: "HTTPServer error" becomes
+// "http_server_error".
+func SnakeCase(s string) string { return strings.Join(lowerWords(s), "_") }
+
+// KebabCase is like SnakeCase but joins the words with hyphens.
+func KebabCase(s string) string { return strings.Join(lowerWords(s), "-") }
+
+// CamelCase returns the words of s, split as by SnakeCase, joined with
+// each after the first capitalised: "user id" becomes "userId".
+func CamelCase(s string) string {
+	words := lowerWords(s)
+	for i := 1; i < len(words); i++ {
+		words[i] = capitalize(words[i])
+	}
+	return strings.Join(words, "")
+}
+
+// PascalCase is like CamelCase but capitalises the first word too.
+func PascalCase(s string) string {
+	words := lowerWords(s)
+	for i, w := range words {
+		words[i] = capitalize(w)
+	}
+	return strings.Join(words, "")
+}
+
+func capitalize(w string) string {
+	r := []rune(w)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func lowerWords(s string) []string {
+	words := Words(s)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return words
+}
+
+// Words splits s into words at anything that is not a letter or digit and
+// where the case changes: before an upper-case letter that follows a
+// lower-case letter or digit, and before the last of a run of upper-case
+// letters that is followed by a lower-case one, so that "parseHTTPRequest2"
+// gives "parse", "HTTP" and "Request2".
+func Words(s string) []string {
+	var words []string
+	r := []rune(s)
+	start := -1
+	for i, c := range r {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			if start >= 0 {
+				words = append(words, string(r[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(c) {
+			prev := r[i-1]
+			acronymEnd := unicode.IsUpper(prev) && i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || acronymEnd {
+				words = append(words, string(r[start:i]))
+				start = i
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(r[start:]))
+	}
+	return words
+}
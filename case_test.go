@@ -0,0 +1,44 @@
+package golib
+
+import "slices"
+import "testing"
+
+func TestWords(t *testing.T) {
+	cases := []struct {
+		in   string
+		want []string
+	}{
+		{"parseHTTPRequest2", []string{"parse", "HTTP", "Request2"}},
+		{"HTTPServer error", []string{"HTTP", "Server", "error"}},
+		{"user_id", []string{"user", "id"}},
+		{"  --already-kebab--  ", []string{"already", "kebab"}},
+		{"ID", []string{"ID"}},
+		{"v2Beta", []string{"v2", "Beta"}},
+		{"", nil},
+	}
+	for _, c := range cases {
+		if got := Words(c.in); !slices.Equal(got, c.want) {
+			t.Errorf("Words(%q) == %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestCases(t *testing.T) {
+	cases := []struct {
+		fn       func(string) string
+		in, want string
+	}{
+		{SnakeCase, "HTTPServer error", "http_server_error"},
+		{SnakeCase, "userID", "user_id"},
+		{KebabCase, "Hello World", "hello-world"},
+		{CamelCase, "user id", "userId"},
+		{CamelCase, "XML_http_request", "xmlHttpRequest"},
+		{PascalCase, "élan vital", "ÉlanVital"},
+		{PascalCase, "", ""},
+	}
+	for _, c := range cases {
+		if got := c.fn(c.in); got != c.want {
+			t.Errorf("%q -> %q, want %q", c.in, got, c.want)
+		}
+	}
+}
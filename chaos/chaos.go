@@ -0,0 +1,152 @@
+// Package chaos injects faults — latency, errors and partial transfers —
+// into readers, writers, HTTP transports and function calls, to test how
+// code copes with failure.
+//
+// A Faults value says what to inject and a Trigger when: on a random
+// fraction of calls with Probability, or in a fixed pattern with
+// Sequence, Every and FirstN, which make a test deterministic. Triggers
+// are safe for concurrent use, so one Faults value can wrap many things.
+package chaos
+
+import "context"
+import "errors"
+import "sync"
+import "time"
+
+import "github.com/lukehedger/golib"
+import "github.com/lukehedger/golib/clock"
+
+// ErrInjected is the error injected when Faults.Err is nil.
+var ErrInjected = errors.New("chaos: injected fault")
+
+// A Trigger decides, call by call, whether a fault is injected.
+type Trigger interface {
+	Fire() bool
+}
+
+// TriggerFunc adapts a function to a Trigger. It must be safe for
+// concurrent use.
+type TriggerFunc func() bool
+
+func (f TriggerFunc) Fire() bool { return f() }
+
+// Always returns a Trigger that fires on every call.
+func Always() Trigger { return TriggerFunc(func() bool { return true }) }
+
+// Probability returns a Trigger that fires on each call with probability
+// p, drawing from a generator seeded with seed so that runs repeat.
+func Probability(p float64, seed uint64) Trigger {
+	var mu sync.Mutex
+	r := golib.NewFast(seed)
+	return TriggerFunc(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return r.Float64() < p
+	})
+}
+
+// Sequence returns a Trigger that fires or not as pattern says, call by
+// call, starting again from the beginning when it runs out. An empty
+// pattern never fires.
+func Sequence(pattern ...bool) Trigger {
+	var mu sync.Mutex
+	i := 0
+	return TriggerFunc(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(pattern) == 0 {
+			return false
+		}
+		fire := pattern[i]
+		i = (i + 1) % len(pattern)
+		return fire
+	})
+}
+
+// Every returns a Trigger that fires on every nth call: the nth, the
+// 2nth and so on. It panics if n < 1.
+func Every(n int) Trigger {
+	if n < 1 {
+		panic("chaos: Every needs n >= 1")
+	}
+	pattern := make([]bool, n)
+	pattern[n-1] = true
+	return Sequence(pattern...)
+}
+
+// FirstN returns a Trigger that fires on the first n calls and never
+// again, as when a dependency fails for a while and then recovers.
+func FirstN(n int) Trigger {
+	var mu sync.Mutex
+	return TriggerFunc(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		n--
+		return n >= 0
+	})
+}
+
+// Faults describes the faults to inject. Each applies to a call when its
+// trigger fires; a nil trigger never fires. A call that is delayed may
+// also fail.
+type Faults struct {
+	// Latency is how long a call is delayed when Delay fires.
+	Latency time.Duration
+	Delay   Trigger
+	// Err is the error a call returns when Fail fires. Nil means
+	// ErrInjected.
+	Err  error
+	Fail Trigger
+	// Short makes a read or write transfer only part of what it was
+	// asked to, or an HTTP response body end early.
+	Short Trigger
+
+	Clock clock.Clock // nil means clock.Real
+}
+
+// Call wraps fn to inject f's latency and errors. An injected error
+// replaces the call to fn.
+func Call(fn func(context.Context) error, f Faults) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if err := f.inject(ctx); err != nil {
+			return err
+		}
+		return fn(ctx)
+	}
+}
+
+// Func is like Call for a function that returns a value.
+func Func[T any](fn func(context.Context) (T, error), f Faults) func(context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		if err := f.inject(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+		return fn(ctx)
+	}
+}
+
+// inject waits out any latency, returning ctx's error if it ends first,
+// then the injected error if Fail fires.
+func (f Faults) inject(ctx context.Context) error {
+	if fires(f.Delay) && f.Latency > 0 {
+		select {
+		case <-clock.Or(f.Clock).After(f.Latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if fires(f.Fail) {
+		return f.err()
+	}
+	return nil
+}
+
+func (f Faults) err() error {
+	if f.Err == nil {
+		return ErrInjected
+	}
+	return f.Err
+}
+
+func fires(t Trigger) bool { return t != nil && t.Fire() }
@@ -0,0 +1,97 @@
+package chaos
+
+import "context"
+import "errors"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+func fire(t Trigger, n int) []bool {
+	var got []bool
+	for range n {
+		got = append(got, t.Fire())
+	}
+	return got
+}
+
+func TestTriggers(t *testing.T) {
+	cases := []struct {
+		name string
+		t    Trigger
+		want []bool
+	}{
+		{"Always", Always(), []bool{true, true, true}},
+		{"Sequence", Sequence(true, false), []bool{true, false, true, false, true}},
+		{"Sequence()", Sequence(), []bool{false, false}},
+		{"Every", Every(3), []bool{false, false, true, false, false, true}},
+		{"FirstN", FirstN(2), []bool{true, true, false, false}},
+	}
+	for _, c := range cases {
+		got := fire(c.t, len(c.want))
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("%s fired %v, want %v", c.name, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestProbability(t *testing.T) {
+	a, b := fire(Probability(0.3, 1), 1000), fire(Probability(0.3, 1), 1000)
+	n := 0
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatal("Probability with the same seed fired differently")
+		}
+		if a[i] {
+			n++
+		}
+	}
+	if n < 250 || n > 350 {
+		t.Errorf("Probability(0.3) fired %d times in 1000", n)
+	}
+}
+
+func TestCall(t *testing.T) {
+	calls := 0
+	fn := Call(func(context.Context) error { calls++; return nil }, Faults{Fail: Sequence(true, false)})
+	if err := fn(context.Background()); !errors.Is(err, ErrInjected) {
+		t.Errorf("first call: %v, want ErrInjected", err)
+	}
+	if err := fn(context.Background()); err != nil || calls != 1 {
+		t.Errorf("second call: %v after %d calls, want nil after 1", err, calls)
+	}
+
+	boom := errors.New("boom")
+	get := Func(func(context.Context) (int, error) { return 42, nil }, Faults{Err: boom, Fail: Always()})
+	if v, err := get(context.Background()); v != 0 || err != boom {
+		t.Errorf("Func == %d, %v, want 0, boom", v, err)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	f := clock.NewFake(time.Time{})
+	fn := Call(func(context.Context) error { return nil }, Faults{Latency: time.Second, Delay: Always(), Clock: f})
+	done := make(chan error)
+	go func() { done <- fn(context.Background()) }()
+	f.BlockUntil(1)
+	select {
+	case <-done:
+		t.Fatal("call returned before the latency passed")
+	default:
+	}
+	f.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { done <- fn(ctx) }()
+	f.BlockUntil(1)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("cancelled call: %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,53 @@
+package chaos
+
+import "io"
+import "net/http"
+
+// Transport wraps rt, or http.DefaultTransport if rt is nil, to inject
+// f's faults into each request. Latency is cut short if the request's
+// context ends. An injected error takes the place of the response, and a
+// short response's body fails with io.ErrUnexpectedEOF after its first
+// read.
+func Transport(rt http.RoundTripper, f Faults) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return &transport{rt, f}
+}
+
+type transport struct {
+	rt http.RoundTripper
+	f  Faults
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.f.inject(req.Context()); err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+	resp, err := t.rt.RoundTrip(req)
+	if err == nil && fires(t.f.Short) {
+		resp.Body = &truncated{ReadCloser: resp.Body}
+	}
+	return resp, err
+}
+
+// truncated is a body that ends early.
+type truncated struct {
+	io.ReadCloser
+	read bool
+}
+
+func (b *truncated) Read(p []byte) (int, error) {
+	if b.read {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b.read = true
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
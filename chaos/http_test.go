@@ -0,0 +1,39 @@
+package chaos
+
+import "errors"
+import "io"
+import "net/http"
+import "net/http/httptest"
+import "testing"
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hello, world")
+	}))
+	defer srv.Close()
+	c := &http.Client{Transport: Transport(nil, Faults{Fail: Sequence(true, false, false), Short: Sequence(false, true)})}
+
+	if _, err := c.Get(srv.URL); !errors.Is(err, ErrInjected) {
+		t.Errorf("first Get: %v, want ErrInjected", err)
+	}
+
+	resp, err := c.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "hello, world" || err != nil {
+		t.Errorf("second Get read %q, %v", body, err)
+	}
+
+	resp, err = c.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("short Get: %v, want ErrUnexpectedEOF", err)
+	}
+}
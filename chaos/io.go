@@ -0,0 +1,46 @@
+package chaos
+
+import "context"
+import "io"
+
+// Reader wraps r to inject f's faults into each Read. A short read fills
+// at most half of the buffer, as a slow network might.
+func Reader(r io.Reader, f Faults) io.Reader { return &reader{r, f} }
+
+type reader struct {
+	r io.Reader
+	f Faults
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if err := r.f.inject(context.Background()); err != nil {
+		return 0, err
+	}
+	if len(p) > 1 && fires(r.f.Short) {
+		p = p[:len(p)/2]
+	}
+	return r.r.Read(p)
+}
+
+// Writer wraps w to inject f's faults into each Write. A short write
+// writes half of the buffer and returns io.ErrShortWrite.
+func Writer(w io.Writer, f Faults) io.Writer { return &writer{w, f} }
+
+type writer struct {
+	w io.Writer
+	f Faults
+}
+
+func (w *writer) Write(p []byte) (int, error) {
+	if err := w.f.inject(context.Background()); err != nil {
+		return 0, err
+	}
+	if len(p) > 0 && fires(w.f.Short) {
+		n, err := w.w.Write(p[:len(p)/2])
+		if err == nil {
+			err = io.ErrShortWrite
+		}
+		return n, err
+	}
+	return w.w.Write(p)
+}
@@ -0,0 +1,40 @@
+package chaos
+
+import "bytes"
+import "errors"
+import "io"
+import "strings"
+import "testing"
+
+func TestReader(t *testing.T) {
+	r := Reader(strings.NewReader("abcdefgh"), Faults{Short: Sequence(true, false), Fail: Sequence(false, false, true)})
+	buf := make([]byte, 8)
+	if n, err := r.Read(buf); n != 4 || err != nil {
+		t.Errorf("short Read == %d, %v, want 4, nil", n, err)
+	}
+	if n, err := r.Read(buf); n != 4 || err != nil {
+		t.Errorf("Read == %d, %v, want 4, nil", n, err)
+	}
+	if n, err := r.Read(buf); n != 0 || !errors.Is(err, ErrInjected) {
+		t.Errorf("failed Read == %d, %v, want 0, ErrInjected", n, err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := Writer(&b, Faults{Short: Sequence(true, false)})
+	if n, err := w.Write([]byte("abcd")); n != 2 || err != io.ErrShortWrite {
+		t.Errorf("short Write == %d, %v, want 2, ErrShortWrite", n, err)
+	}
+	if n, err := w.Write([]byte("cd")); n != 2 || err != nil {
+		t.Errorf("Write == %d, %v, want 2, nil", n, err)
+	}
+	if b.String() != "abcd" {
+		t.Errorf("wrote %q, want abcd", b.String())
+	}
+
+	w = Writer(&b, Faults{Fail: Always()})
+	if n, err := w.Write([]byte("x")); n != 0 || !errors.Is(err, ErrInjected) {
+		t.Errorf("failed Write == %d, %v, want 0, ErrInjected", n, err)
+	}
+}
@@ -0,0 +1,120 @@
+// Package clock abstracts the passage of time so that code which sleeps
+// or waits on timers can be tested deterministically.
+//
+// Code takes a Clock, using Real in production. Tests pass a Fake, whose
+// time moves only when Advance is called, and use BlockUntil to wait for
+// the code under test to reach its next sleep before advancing.
+package clock
+
+import "slices"
+import "sync"
+import "time"
+
+// A Clock tells the time and waits for it to pass.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel that receives the time once d has passed.
+	After(d time.Duration) <-chan time.Time
+	// Sleep waits until d has passed.
+	Sleep(d time.Duration)
+}
+
+// Real is the Clock of the time package.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+
+// Or returns c, or Real if c is nil, for optional Clock fields.
+func Or(c Clock) Clock {
+	if c == nil {
+		return Real
+	}
+	return c
+}
+
+// Fake is a Clock whose time changes only when told to. It is safe for
+// concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+	changed chan struct{} // closed and replaced when waiters is added to
+}
+
+type waiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewFake returns a Fake set to t.
+func NewFake(t time.Time) *Fake {
+	return &Fake{now: t, changed: make(chan struct{})}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// After returns a channel that receives the fake time once the clock has
+// been advanced by d. If d is not positive the channel is ready at once.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- f.now
+		return c
+	}
+	f.waiters = append(f.waiters, waiter{f.now.Add(d), c})
+	close(f.changed)
+	f.changed = make(chan struct{})
+	return c
+}
+
+// Sleep blocks until the clock has been advanced by d.
+func (f *Fake) Sleep(d time.Duration) { <-f.After(d) }
+
+// Advance moves the clock forward by d, waking every sleeper and firing
+// every After channel whose time has come, earliest first.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	slices.SortStableFunc(f.waiters, func(a, b waiter) int { return a.at.Compare(b.at) })
+	n := 0
+	for _, w := range f.waiters {
+		if w.at.After(f.now) {
+			break
+		}
+		w.c <- w.at
+		n++
+	}
+	f.waiters = slices.Delete(f.waiters, 0, n)
+}
+
+// Waiters returns the number of pending sleeps and After channels.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n sleeps or After channels are pending,
+// so that a test knows the code under test is waiting on the clock.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		ok, changed := len(f.waiters) >= n, f.changed
+		f.mu.Unlock()
+		if ok {
+			return
+		}
+		<-changed
+	}
+}
@@ -0,0 +1,70 @@
+package clock
+
+import "sync"
+import "testing"
+import "time"
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+	if !f.Now().Equal(start) {
+		t.Errorf("Now() == %v, want %v", f.Now(), start)
+	}
+
+	late, early := f.After(2*time.Second), f.After(time.Second)
+	select {
+	case <-early:
+		t.Fatal("After fired before Advance")
+	default:
+	}
+	f.Advance(time.Second)
+	if got := <-early; !got.Equal(start.Add(time.Second)) {
+		t.Errorf("early fired at %v", got)
+	}
+	select {
+	case <-late:
+		t.Fatal("late fired too soon")
+	default:
+	}
+	if f.Waiters() != 1 {
+		t.Errorf("Waiters() == %d, want 1", f.Waiters())
+	}
+	f.Advance(time.Hour)
+	<-late
+	if got := f.Now(); !got.Equal(start.Add(time.Hour + time.Second)) {
+		t.Errorf("Now() == %v after advancing", got)
+	}
+	select {
+	case <-f.After(0):
+	default:
+		t.Error("After(0) is not ready at once")
+	}
+}
+
+func TestFakeBlockUntil(t *testing.T) {
+	f := NewFake(time.Time{})
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.Sleep(time.Minute)
+		}()
+	}
+	f.BlockUntil(3)
+	f.Advance(time.Minute)
+	wg.Wait()
+	if f.Waiters() != 0 {
+		t.Errorf("Waiters() == %d after waking everyone", f.Waiters())
+	}
+}
+
+func TestOr(t *testing.T) {
+	if Or(nil) != Real {
+		t.Error("Or(nil) != Real")
+	}
+	f := NewFake(time.Time{})
+	if Or(f) != f {
+		t.Error("Or(f) != f")
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import "bufio"
+import "os"
+
+import "github.com/lukehedger/golib/apisum"
+
+var apiCommand = &command{
+	Name:    "api",
+	Usage:   "api [-md] [packages]",
+	Summary: "list the exported API of packages",
+}
+
+func init() {
+	apiCommand.Run = runAPI
+}
+
+func runAPI(args []string) error {
+	fs := newFlagSet(apiCommand)
+	markdown := fs.Bool("md", false, "write Markdown instead of plain text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	patterns := fs.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	first := true
+	for _, pattern := range patterns {
+		pkgs, err := apisum.Load(pattern)
+		if err != nil {
+			return err
+		}
+		for _, p := range pkgs {
+			if !first {
+				w.WriteString("\n")
+			}
+			first = false
+			if *markdown {
+				err = p.WriteMarkdown(w)
+			} else {
+				err = p.WriteText(w)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import "bufio"
+import "encoding/csv"
+import "errors"
+import "fmt"
+import "io"
+import "math"
+import "os"
+import "slices"
+import "strconv"
+import "strings"
+
+import "github.com/lukehedger/golib"
+import "github.com/lukehedger/golib/stats"
+import "github.com/lukehedger/golib/termplot"
+
+var chartCommand = &command{
+	Name:    "chart",
+	Usage:   "chart [-column name] [-bins n] [-width n] [-show summary,trend,hist] [file]",
+	Summary: "summarise and chart numbers read from a file or standard input",
+}
+
+func init() {
+	chartCommand.Run = runChart
+}
+
+func runChart(args []string) error {
+	fs := newFlagSet(chartCommand)
+	column := fs.String("column", "", "read CSV and chart this column, by header `name` or 1-based index")
+	bins := fs.Int("bins", 10, "number of histogram bins")
+	width := fs.Int("width", 50, "chart width in characters")
+	show := fs.String("show", "summary,trend,hist", "comma-separated `sections` to print")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var in io.Reader = os.Stdin
+	if fs.NArg() > 0 && fs.Arg(0) != "-" {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var xs []float64
+	var err error
+	if *column != "" {
+		xs, err = readColumn(in, *column)
+	} else {
+		xs, err = readNumbers(in)
+	}
+	if err != nil {
+		return err
+	}
+	if len(xs) == 0 {
+		return errors.New("no numbers in input")
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	for i, section := range splitList(*show) {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		switch section {
+		case "summary":
+			err = writeSummary(w, xs)
+		case "trend":
+			_, err = fmt.Fprintln(w, termplot.Sparkline(xs, *width))
+		case "hist":
+			var hist []stats.Bin
+			if hist, err = stats.Histogram(xs, *bins); err == nil {
+				err = termplot.Histogram(w, hist, *width)
+			}
+		default:
+			err = fmt.Errorf("unknown section %q", section)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSummary(w io.Writer, xs []float64) error {
+	ps, err := stats.Quantiles(xs, 0, 0.5, 0.9, 0.99, 1)
+	if err != nil {
+		return err
+	}
+	mean, _ := stats.Mean(xs)
+	sd, _ := stats.StdDev(xs)
+	t := new(golib.Table).SetAlign(1, golib.AlignRight)
+	for _, row := range []struct {
+		name string
+		v    float64
+	}{
+		{"count", float64(len(xs))}, {"min", ps[0]}, {"mean", mean}, {"stddev", sd},
+		{"p50", ps[1]}, {"p90", ps[2]}, {"p99", ps[3]}, {"max", ps[4]},
+	} {
+		t.AddRow(row.name, strconv.FormatFloat(row.v, 'g', 6, 64))
+	}
+	return t.Render(w)
+}
+
+// readNumbers reads numbers separated by whitespace or commas. A first
+// line with no numbers is taken as a header and skipped.
+func readNumbers(r io.Reader) ([]float64, error) {
+	var xs []float64
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.FieldsFunc(s.Text(), func(r rune) bool {
+			return r == ',' || r == ';' || r == ' ' || r == '\t'
+		})
+		var row []float64
+		for _, f := range fields {
+			x, err := parseFinite(f)
+			if err != nil {
+				if line == 1 && len(row) == 0 {
+					row = nil
+					break // a header
+				}
+				return nil, fmt.Errorf("line %d: %q is not a finite number", line, f)
+			}
+			row = append(row, x)
+		}
+		xs = append(xs, row...)
+	}
+	return xs, s.Err()
+}
+
+// parseFinite parses a number, rejecting NaN and infinities, which no
+// chart or summary can place.
+func parseFinite(s string) (float64, error) {
+	x, err := strconv.ParseFloat(s, 64)
+	if err == nil && (math.IsNaN(x) || math.IsInf(x, 0)) {
+		err = errors.New("not finite")
+	}
+	return x, err
+}
+
+// readColumn reads one column of a CSV file with a header row. Empty
+// cells are skipped.
+func readColumn(r io.Reader, column string) ([]float64, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+	col := slices.Index(header, column)
+	if n, err := strconv.Atoi(column); col < 0 && err == nil && n >= 1 && n <= len(header) {
+		col = n - 1
+	}
+	if col < 0 {
+		return nil, fmt.Errorf("no column %q; columns are %s", column, strings.Join(header, ", "))
+	}
+	var xs []float64
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return xs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if col >= len(row) || strings.TrimSpace(row[col]) == "" {
+			continue
+		}
+		x, err := parseFinite(strings.TrimSpace(row[col]))
+		if err != nil {
+			line, _ := cr.FieldPos(col)
+			return nil, fmt.Errorf("line %d: %q is not a finite number", line, row[col])
+		}
+		xs = append(xs, x)
+	}
+}
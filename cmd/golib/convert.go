@@ -0,0 +1,100 @@
+package main
+
+import "bufio"
+import "errors"
+import "fmt"
+import "io"
+import "os"
+import "strings"
+
+import "github.com/lukehedger/golib/convert"
+
+var convertCommand = &command{
+	Name:    "convert",
+	Usage:   "convert [-from format] [-to format] [-columns a,b] [-infer] [-strings a,b] [-o file] [file]",
+	Summary: "convert records between CSV, JSON, JSON Lines and YAML",
+}
+
+func init() {
+	convertCommand.Run = runConvert
+}
+
+func runConvert(args []string) error {
+	fs := newFlagSet(convertCommand)
+	from := fs.String("from", "", "input `format`: csv, json, jsonl or yaml (default from the file extension)")
+	to := fs.String("to", "", "output `format` (default from the -o extension, else json)")
+	columns := fs.String("columns", "", "comma-separated `list` of columns to keep, in order")
+	var opts convert.Options
+	fs.BoolVar(&opts.Infer, "infer", true, "turn CSV and YAML text into numbers, booleans and nulls")
+	strs := fs.String("strings", "", "comma-separated `list` of columns never to infer")
+	out := fs.String("o", "", "write to `file` instead of standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("at most one input file")
+	}
+	opts.Columns = splitList(*columns)
+	opts.Strings = splitList(*strs)
+
+	var in io.Reader = os.Stdin
+	inName := ""
+	if fs.NArg() == 1 && fs.Arg(0) != "-" {
+		inName = fs.Arg(0)
+		f, err := os.Open(inName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	src, err := pickFormat(*from, inName, "")
+	if err != nil {
+		return fmt.Errorf("input: %w", err)
+	}
+	dst, err := pickFormat(*to, *out, convert.JSON)
+	if err != nil {
+		return fmt.Errorf("output: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+	if _, err := convert.Convert(bw, dst, bufio.NewReader(in), src, opts); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// pickFormat returns the named format, else the one implied by filename,
+// else def.
+func pickFormat(name, filename string, def convert.Format) (convert.Format, error) {
+	switch {
+	case name != "":
+		return convert.ParseFormat(name)
+	case filename != "":
+		return convert.FormatFromFilename(filename)
+	case def != "":
+		return def, nil
+	}
+	return "", errors.New("cannot tell the format; use -from")
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts
+}
@@ -0,0 +1,68 @@
+package main
+
+import "errors"
+import "fmt"
+
+import "github.com/lukehedger/golib"
+import "github.com/lukehedger/golib/dedup"
+
+var dedupCommand = &command{
+	Name:    "dedup",
+	Usage:   "dedup [-link] [-min size] [-workers n] dir...",
+	Summary: "find duplicate files, and optionally hard-link them together",
+}
+
+func init() {
+	dedupCommand.Run = runDedup
+}
+
+func runDedup(args []string) error {
+	fs := newFlagSet(dedupCommand)
+	link := fs.Bool("link", false, "replace duplicates with hard links to the first copy")
+	minSize := fs.String("min", "1", "ignore files smaller than `size`, e.g. 4KiB")
+	workers := fs.Int("workers", 0, "number of files to hash at once (default: number of CPUs)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no directories given")
+	}
+	size, err := golib.ParseBytes(*minSize)
+	if err != nil {
+		return err
+	}
+	groups, err := dedup.Find(fs.Args(), dedup.Options{Workers: *workers, MinSize: size})
+	if err != nil {
+		return err
+	}
+
+	var wasted int64
+	linked := 0
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%d copies of %s:\n", len(g.Paths), golib.HumanizeBytes(g.Size))
+		for _, p := range g.Paths {
+			fmt.Println("\t" + p)
+		}
+		wasted += g.Wasted()
+		if *link {
+			n, err := dedup.Link(g)
+			linked += n
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(groups) > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("duplicate sets: %d, wasted: %s", len(groups), golib.HumanizeBytes(wasted))
+	if *link {
+		fmt.Printf(", linked: %d", linked)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import "context"
+import "log"
+import "os"
+import "os/signal"
+
+import "github.com/lukehedger/golib/files"
+import "github.com/lukehedger/golib/serve"
+
+var filesCommand = &command{
+	Name:    "files",
+	Usage:   "files [-addr address] [-index] [-gzip] [-hidden] [dir]",
+	Summary: "serve a directory over HTTP",
+}
+
+func init() {
+	filesCommand.Run = runFiles
+}
+
+func runFiles(args []string) error {
+	fs := newFlagSet(filesCommand)
+	addr := fs.String("addr", "localhost:8000", "listen address")
+	var opts files.Options
+	fs.BoolVar(&opts.Index, "index", true, "list directories without an index.html")
+	fs.BoolVar(&opts.Gzip, "gzip", true, "compress text responses")
+	fs.BoolVar(&opts.Hidden, "hidden", false, "serve dot files")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	h, err := files.Dir(dir, opts)
+	if err != nil {
+		return err
+	}
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	s := &serve.Server{Addr: *addr, Handler: serve.Chain(h, serve.Logger(logger), serve.Recover(logger))}
+	logger.Printf("serving %s on http://%s/", dir, *addr)
+	return s.ListenAndServe(ctx)
+}
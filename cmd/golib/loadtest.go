@@ -0,0 +1,78 @@
+package main
+
+import "context"
+import "errors"
+import "fmt"
+import "net/http"
+import "os"
+import "os/signal"
+import "strings"
+
+import "github.com/lukehedger/golib/loadtest"
+
+var loadtestCommand = &command{
+	Name:    "loadtest",
+	Usage:   "loadtest [-rate n] [-c workers] [-d duration] [-n requests] [-method m] [-H 'Name: value']... [-body data] url",
+	Summary: "send HTTP requests at a target rate and report latency percentiles",
+}
+
+func init() {
+	loadtestCommand.Run = runLoadtest
+}
+
+// headerFlags collects repeated -H flags.
+type headerFlags http.Header
+
+func (h headerFlags) String() string { return "" }
+
+func (h headerFlags) Set(s string) error {
+	k, v, ok := strings.Cut(s, ":")
+	if !ok {
+		return errors.New(`want "Name: value"`)
+	}
+	http.Header(h).Add(strings.TrimSpace(k), strings.TrimSpace(v))
+	return nil
+}
+
+func runLoadtest(args []string) error {
+	fs := newFlagSet(loadtestCommand)
+	cfg := loadtest.Config{Header: http.Header{}}
+	fs.Float64Var(&cfg.Rate, "rate", 0, "target requests per second, or 0 for as fast as possible")
+	fs.IntVar(&cfg.Workers, "c", loadtest.DefaultWorkers, "number of concurrent requests")
+	fs.DurationVar(&cfg.Duration, "d", loadtest.DefaultDuration, "test `duration`")
+	fs.IntVar(&cfg.Requests, "n", 0, "stop after this many requests")
+	fs.DurationVar(&cfg.Timeout, "timeout", loadtest.DefaultTimeout, "per-request `timeout`")
+	fs.StringVar(&cfg.Method, "method", http.MethodGet, "HTTP `method`")
+	fs.Var(headerFlags(cfg.Header), "H", "add a request `header`")
+	body := fs.String("body", "", "request body; @file reads it from a file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("want exactly one URL")
+	}
+	cfg.URL = fs.Arg(0)
+	if strings.HasPrefix(*body, "@") {
+		b, err := os.ReadFile((*body)[1:])
+		if err != nil {
+			return err
+		}
+		cfg.Body = b
+	} else if *body != "" {
+		cfg.Body = []byte(*body)
+	}
+
+	// Interrupting the test still prints the results so far.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	res, err := loadtest.Run(ctx, cfg)
+	if res == nil {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "interrupted")
+	}
+	_, err = res.WriteTo(os.Stdout)
+	return err
+}
@@ -0,0 +1,90 @@
+package main
+
+import "fmt"
+import "io"
+import "os"
+import "time"
+
+import "github.com/lukehedger/golib/loganalyze"
+
+var logsCommand = &command{
+	Name:    "logs",
+	Usage:   "logs [-format auto|json|combined|plain] [-pattern regexp] [-window d] [-top n] [-since t] [-until t] [file...]",
+	Summary: "summarise log files by level, status, message and error rate",
+}
+
+func init() {
+	logsCommand.Run = runLogs
+}
+
+func runLogs(args []string) error {
+	fs := newFlagSet(logsCommand)
+	format := fs.String("format", "auto", "log `format`")
+	pattern := fs.String("pattern", "", "parse lines with this `regexp`, using the named groups time, level, status and msg")
+	layout := fs.String("time-layout", "", "Go time `layout` of the time group, for -pattern")
+	window := fs.Duration("window", time.Minute, "width of the error-rate buckets, or 0 for none")
+	top := fs.Int("top", loganalyze.DefaultTop, "number of messages to list")
+	since := fs.String("since", "", "ignore entries before `time` (RFC 3339)")
+	until := fs.String("until", "", "ignore entries at or after `time` (RFC 3339)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var p loganalyze.Parser
+	switch {
+	case *pattern != "":
+		re, err := loganalyze.NewRegexp(*pattern, *layout)
+		if err != nil {
+			return err
+		}
+		p = re
+	case *format == "auto":
+		p = loganalyze.Auto
+	case *format == "json":
+		p = &loganalyze.JSON{TimeLayout: *layout}
+	case *format == "combined":
+		p = loganalyze.Combined
+	case *format == "plain":
+		p = loganalyze.Plain
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	opts := loganalyze.Options{Window: *window, Top: *top}
+	for _, t := range []struct {
+		s   string
+		dst *time.Time
+	}{{*since, &opts.Since}, {*until, &opts.Until}} {
+		if t.s == "" {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339, t.s)
+		if err != nil {
+			return err
+		}
+		*t.dst = v
+	}
+
+	a := loganalyze.NewAnalyzer(opts)
+	if fs.NArg() == 0 {
+		if err := a.Read(os.Stdin, p); err != nil {
+			return err
+		}
+	}
+	for _, name := range fs.Args() {
+		var r io.Reader = os.Stdin
+		if name != "-" {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		if err := a.Read(r, p); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	_, err := a.Report().WriteTo(os.Stdout)
+	return err
+}
@@ -0,0 +1,120 @@
+// Command golib exposes the golib packages on the command line.
+//
+// Usage:
+//
+//	golib <command> [arguments]
+//
+// Run "golib help <command>" for the flags a command accepts.
+package main
+
+import "errors"
+import "flag"
+import "fmt"
+import "os"
+import "strings"
+
+import "github.com/lukehedger/golib"
+
+// A command is a golib subcommand. Run receives the arguments that follow
+// the command name.
+type command struct {
+	Name    string
+	Usage   string
+	Summary string
+	Run     func(args []string) error
+}
+
+var commands = []*command{
+	apiCommand,
+	chartCommand,
+	convertCommand,
+	dedupCommand,
+	filesCommand,
+	loadtestCommand,
+	logsCommand,
+	manifestCommand,
+	mockgenCommand,
+	mockserveCommand,
+	pipeCommand,
+	qrCommand,
+	scanCommand,
+	tourCommand,
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := args[0]
+	if name == "help" {
+		help(args[1:])
+		return
+	}
+	for _, c := range commands {
+		if c.Name == name {
+			err := c.Run(args[1:])
+			if errors.Is(err, flag.ErrHelp) {
+				os.Exit(2)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "golib %s: %v\n", c.Name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "golib: unknown command %q\n", name)
+	var names []string
+	for _, c := range commands {
+		names = append(names, c.Name)
+	}
+	if s := golib.Suggest(name, names, 0.8); len(s) > 0 {
+		fmt.Fprintf(os.Stderr, "Did you mean %q?\n", s[0])
+	}
+	fmt.Fprintln(os.Stderr, "Run 'golib help' for usage.")
+	os.Exit(2)
+}
+
+func usage() {
+	var b strings.Builder
+	b.WriteString("Usage:\n\n\tgolib <command> [arguments]\n\nThe commands are:\n\n")
+	t := new(golib.Table)
+	for _, c := range commands {
+		t.AddRow("\t"+c.Name, c.Summary)
+	}
+	t.Render(&b)
+	b.WriteString("\nUse \"golib help <command>\" for more information about a command.\n")
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+func help(args []string) {
+	if len(args) == 0 {
+		usage()
+		return
+	}
+	for _, c := range commands {
+		if c.Name == args[0] {
+			c.Run([]string{"-h"})
+			fmt.Fprintf(os.Stderr, "\n%s.\n", c.Summary)
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "golib help: unknown command %q\n", args[0])
+	os.Exit(2)
+}
+
+// newFlagSet returns a flag set for c that prints c's usage on error.
+func newFlagSet(c *command) *flag.FlagSet {
+	fs := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: golib %s\n", c.Usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
@@ -0,0 +1,106 @@
+package main
+
+import "errors"
+import "fmt"
+import "os"
+import "path/filepath"
+
+import "github.com/lukehedger/golib/manifest"
+
+var manifestCommand = &command{
+	Name:    "manifest",
+	Usage:   "manifest [-c SHA256SUMS | -o SHA256SUMS] [-workers n] [dir]",
+	Summary: "print SHA-256 checksums of a tree, or verify it against a manifest",
+}
+
+func init() {
+	manifestCommand.Run = runManifest
+}
+
+func runManifest(args []string) error {
+	fs := newFlagSet(manifestCommand)
+	check := fs.String("c", "", "verify the tree against manifest `file`")
+	out := fs.String("o", "", "write the manifest to `file` instead of standard output")
+	workers := fs.Int("workers", 0, "number of files to hash at once (default: number of CPUs)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	dir := "."
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	root := os.DirFS(dir)
+
+	if *check != "" && *out != "" {
+		return errors.New("-c and -o are mutually exclusive")
+	}
+
+	// The manifest file itself may live in the tree; never hash it.
+	skip := skipFile(dir, *check+*out)
+
+	if *check == "" {
+		m, err := manifest.Build(root, *workers, skip)
+		if err != nil {
+			return err
+		}
+		if *out == "" {
+			_, err = m.WriteTo(os.Stdout)
+			return err
+		}
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		if _, err := m.WriteTo(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	f, err := os.Open(*check)
+	if err != nil {
+		return err
+	}
+	m, err := manifest.Parse(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	d, err := manifest.Verify(root, m, *workers, skip)
+	if err != nil {
+		return err
+	}
+	for _, p := range d.Added {
+		fmt.Println("added:   ", p)
+	}
+	for _, p := range d.Removed {
+		fmt.Println("removed: ", p)
+	}
+	for _, p := range d.Modified {
+		fmt.Println("modified:", p)
+	}
+	if !d.Clean() {
+		return fmt.Errorf("%d added, %d removed, %d modified", len(d.Added), len(d.Removed), len(d.Modified))
+	}
+	return nil
+}
+
+// skipFile returns a function reporting whether a path relative to dir
+// names file, or nil if file is empty or outside dir.
+func skipFile(dir, file string) func(string) bool {
+	if file == "" {
+		return nil
+	}
+	abs, err1 := filepath.Abs(file)
+	absDir, err2 := filepath.Abs(dir)
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+	rel, err := filepath.Rel(absDir, abs)
+	if err != nil || !filepath.IsLocal(rel) {
+		return nil
+	}
+	rel = filepath.ToSlash(rel)
+	return func(p string) bool { return p == rel }
+}
@@ -0,0 +1,38 @@
+package main
+
+import "errors"
+import "os"
+
+import "github.com/lukehedger/golib/mock"
+
+var mockgenCommand = &command{
+	Name:    "mockgen",
+	Usage:   "mockgen [-dir dir] [-o file] interface",
+	Summary: "generate a mock of an interface for tests",
+}
+
+func init() {
+	mockgenCommand.Run = runMockgen
+}
+
+func runMockgen(args []string) error {
+	fs := newFlagSet(mockgenCommand)
+	dir := fs.String("dir", ".", "the `directory` of the interface's package")
+	out := fs.String("o", "", "write the mock to `file` instead of standard output")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("give one interface name")
+	}
+	src, err := mock.Generate(*dir, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0o644)
+}
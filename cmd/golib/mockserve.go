@@ -0,0 +1,48 @@
+package main
+
+import "context"
+import "errors"
+import "log"
+import "os"
+import "os/signal"
+
+import "github.com/lukehedger/golib/mockserve"
+import "github.com/lukehedger/golib/serve"
+
+var mockserveCommand = &command{
+	Name:    "mockserve",
+	Usage:   "mockserve [-addr address] [-seed n] routes.yaml",
+	Summary: "serve canned HTTP responses described in a YAML or JSON routes file",
+}
+
+func init() {
+	mockserveCommand.Run = runMockserve
+}
+
+func runMockserve(args []string) error {
+	fs := newFlagSet(mockserveCommand)
+	addr := fs.String("addr", "localhost:8000", "listen address")
+	seed := fs.Uint64("seed", 1, "seed for fake data in response bodies")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("want exactly one routes file")
+	}
+	routes, err := mockserve.LoadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	h, err := mockserve.New(routes, *seed)
+	if err != nil {
+		return err
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	s := &serve.Server{Addr: *addr, Handler: serve.Chain(h, serve.Logger(logger), serve.Recover(logger))}
+	logger.Printf("serving %d routes from %s on http://%s/", len(routes), fs.Arg(0), *addr)
+	return s.ListenAndServe(ctx)
+}
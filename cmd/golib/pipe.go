@@ -0,0 +1,183 @@
+package main
+
+import "bufio"
+import "crypto/md5"
+import "crypto/sha1"
+import "crypto/sha256"
+import "encoding/base64"
+import "encoding/hex"
+import "errors"
+import "fmt"
+import "hash"
+import "io"
+import "os"
+import "slices"
+import "strconv"
+import "strings"
+
+import "github.com/lukehedger/golib"
+
+var pipeCommand = &command{
+	Name:    "pipe",
+	Usage:   "pipe [-whole] [-list] transform[:arg]...",
+	Summary: "apply a chain of string transforms to each line of standard input",
+}
+
+func init() {
+	pipeCommand.Run = runPipe
+}
+
+// A transform maps one string to another. Those that take an argument get
+// it from after a colon, as in wrap:40.
+type transform struct {
+	Summary string
+	Arg     string // what the argument is, if there is one
+	Fn      func(s, arg string) (string, error)
+}
+
+func plain(fn func(string) string) func(string, string) (string, error) {
+	return func(s, _ string) (string, error) { return fn(s), nil }
+}
+
+func withInt(fn func(string, int) string) func(string, string) (string, error) {
+	return func(s, arg string) (string, error) {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return "", fmt.Errorf("want a number, not %q", arg)
+		}
+		return fn(s, n), nil
+	}
+}
+
+func digest(h func() hash.Hash) func(string, string) (string, error) {
+	return plain(func(s string) string {
+		d := h()
+		io.WriteString(d, s)
+		return hex.EncodeToString(d.Sum(nil))
+	})
+}
+
+var transforms = map[string]transform{
+	"reverse":  {Summary: "reverse the characters", Fn: plain(golib.Reverse)},
+	"upper":    {Summary: "convert to upper case", Fn: plain(strings.ToUpper)},
+	"lower":    {Summary: "convert to lower case", Fn: plain(strings.ToLower)},
+	"trim":     {Summary: "remove leading and trailing space", Fn: plain(strings.TrimSpace)},
+	"slug":     {Summary: "make a URL slug", Fn: plain(golib.Slugify)},
+	"snake":    {Summary: "convert to snake_case", Fn: plain(golib.SnakeCase)},
+	"kebab":    {Summary: "convert to kebab-case", Fn: plain(golib.KebabCase)},
+	"camel":    {Summary: "convert to camelCase", Fn: plain(golib.CamelCase)},
+	"pascal":   {Summary: "convert to PascalCase", Fn: plain(golib.PascalCase)},
+	"dedent":   {Summary: "remove common leading indentation", Fn: plain(golib.Dedent)},
+	"wrap":     {Summary: "wrap at a width", Arg: "width", Fn: withInt(golib.Wrap)},
+	"truncate": {Summary: "cut to a length, marking the cut with …", Arg: "length", Fn: withInt(func(s string, n int) string { return golib.Truncate(s, n, "…") })},
+	"indent":   {Summary: "prefix each line", Arg: "prefix", Fn: func(s, arg string) (string, error) { return golib.Indent(s, arg), nil }},
+	"rle":      {Summary: "run-length encode", Fn: plain(golib.RunLengthEncode)},
+	"unrle":    {Summary: "run-length decode", Fn: func(s, _ string) (string, error) { return golib.RunLengthDecode(s) }},
+	"base64":   {Summary: "encode as base64", Fn: plain(func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) })},
+	"unbase64": {Summary: "decode base64", Fn: func(s, _ string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(s)
+		return string(b), err
+	}},
+	"hex": {Summary: "encode as hexadecimal", Fn: plain(func(s string) string { return hex.EncodeToString([]byte(s)) })},
+	"unhex": {Summary: "decode hexadecimal", Fn: func(s, _ string) (string, error) {
+		b, err := hex.DecodeString(s)
+		return string(b), err
+	}},
+	"md5":    {Summary: "hash with MD5, in hexadecimal", Fn: digest(md5.New)},
+	"sha1":   {Summary: "hash with SHA-1, in hexadecimal", Fn: digest(sha1.New)},
+	"sha256": {Summary: "hash with SHA-256, in hexadecimal", Fn: digest(sha256.New)},
+}
+
+// A step is a transform with its argument.
+type step struct {
+	name, arg string
+	transform
+}
+
+func runPipe(args []string) error {
+	fs := newFlagSet(pipeCommand)
+	whole := fs.Bool("whole", false, "transform all of the input at once rather than line by line")
+	list := fs.Bool("list", false, "list the transforms")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *list {
+		listTransforms(os.Stdout)
+		return nil
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no transforms given; see golib pipe -list")
+	}
+	var steps []step
+	for _, a := range fs.Args() {
+		name, arg, hasArg := strings.Cut(a, ":")
+		t, ok := transforms[name]
+		switch {
+		case !ok:
+			return fmt.Errorf("unknown transform %q; see golib pipe -list", name)
+		case t.Arg != "" && !hasArg:
+			return fmt.Errorf("%s needs an argument, as in %s:%s", name, name, t.Arg)
+		case t.Arg == "" && hasArg:
+			return fmt.Errorf("%s takes no argument", name)
+		}
+		steps = append(steps, step{name, arg, t})
+	}
+
+	apply := func(s string) (string, error) {
+		for _, st := range steps {
+			var err error
+			if s, err = st.Fn(s, st.arg); err != nil {
+				return "", fmt.Errorf("%s: %w", st.name, err)
+			}
+		}
+		return s, nil
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if *whole {
+		in, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		out, err := apply(string(in))
+		if err != nil {
+			return err
+		}
+		w.WriteString(out)
+		return w.Flush()
+	}
+
+	// Flush after each line so that results stream out as input arrives.
+	sc := bufio.NewScanner(os.Stdin)
+	sc.Buffer(nil, 1<<24)
+	for n := 1; sc.Scan(); n++ {
+		out, err := apply(sc.Text())
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		w.WriteString(out)
+		w.WriteByte('\n')
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+func listTransforms(w io.Writer) {
+	var names []string
+	for name := range transforms {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	t := new(golib.Table)
+	for _, name := range names {
+		tr := transforms[name]
+		if tr.Arg != "" {
+			name += ":" + tr.Arg
+		}
+		t.AddRow(name, tr.Summary)
+	}
+	t.Render(w)
+}
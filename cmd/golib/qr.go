@@ -0,0 +1,51 @@
+package main
+
+import "errors"
+import "fmt"
+import "os"
+import "strings"
+
+import "github.com/lukehedger/golib/qr"
+
+var qrCommand = &command{
+	Name:    "qr",
+	Usage:   "qr [-level L|M|Q|H] [-invert] [-o file.png] [-scale n] text...",
+	Summary: "print text as a QR code, or write it to a PNG file",
+}
+
+func init() {
+	qrCommand.Run = runQR
+}
+
+func runQR(args []string) error {
+	fs := newFlagSet(qrCommand)
+	level := fs.String("level", "M", "error-correction `level`")
+	invert := fs.Bool("invert", false, "draw light modules, for terminals with a dark background")
+	out := fs.String("o", "", "write a PNG image to `file` instead of printing")
+	scale := fs.Int("scale", 8, "PNG pixels per module")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no text given")
+	}
+	l, err := qr.ParseLevel(*level)
+	if err != nil {
+		return err
+	}
+	code, err := qr.Encode(strings.Join(fs.Args(), " "), l)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		fmt.Print(code.Text(*invert))
+		return nil
+	}
+	b, err := code.PNG(*scale)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out, b, 0o644)
+}
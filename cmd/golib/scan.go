@@ -0,0 +1,52 @@
+package main
+
+import "context"
+import "errors"
+import "os"
+import "os/signal"
+import "strconv"
+
+import "github.com/lukehedger/golib"
+import "github.com/lukehedger/golib/scan"
+
+var scanCommand = &command{
+	Name:    "scan",
+	Usage:   "scan [-p ports] [-c n] [-timeout d] [-banner] host",
+	Summary: "list the open TCP ports of a host you are authorised to test",
+}
+
+func init() {
+	scanCommand.Run = runScan
+}
+
+func runScan(args []string) error {
+	fs := newFlagSet(scanCommand)
+	spec := fs.String("p", "1-1024", "`ports` to probe, e.g. 22,80,8000-8100")
+	var opts scan.Options
+	fs.IntVar(&opts.Concurrency, "c", scan.DefaultConcurrency, "number of ports to probe at once")
+	fs.DurationVar(&opts.Timeout, "timeout", scan.DefaultTimeout, "connection `timeout`")
+	fs.BoolVar(&opts.Banner, "banner", false, "read the banner each open port sends")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("want exactly one host")
+	}
+	ports, err := scan.ParsePorts(*spec)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	open, err := scan.Scan(ctx, fs.Arg(0), ports, opts)
+	t := new(golib.Table).SetHeaders("PORT", "SERVICE", "BANNER").SetMaxWidth(80)
+	for _, r := range open {
+		t.AddRow(strconv.Itoa(r.Port)+"/tcp", r.Service, r.Banner)
+	}
+	if len(open) > 0 {
+		t.Render(os.Stdout)
+	}
+	return err
+}
@@ -0,0 +1,200 @@
+package main
+
+import "bufio"
+import "fmt"
+import "io"
+import "os"
+import "strconv"
+import "strings"
+
+import "github.com/lukehedger/golib/lessons"
+import "github.com/lukehedger/golib/tui"
+
+var tourCommand = &command{
+	Name:    "tour",
+	Usage:   "tour [-plain]",
+	Summary: "take the golib lessons in order, full screen or at a prompt",
+}
+
+func init() {
+	tourCommand.Run = runTour
+}
+
+func runTour(args []string) error {
+	fs := newFlagSet(tourCommand)
+	plain := fs.Bool("plain", false, "use line-by-line prompts even in a terminal")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	path, err := lessons.Default.Path()
+	if err != nil {
+		return err
+	}
+	t := &tour{path: path, done: map[string]bool{}}
+	if *plain || !tui.IsTerminal(os.Stdin) || !tui.IsTerminal(os.Stdout) {
+		return t.prompt(os.Stdin, os.Stdout)
+	}
+	return t.screen()
+}
+
+// tour is a pass through the lessons, recording which have been taken.
+type tour struct {
+	path []lessons.Lesson
+	done map[string]bool
+}
+
+// missing returns the prerequisites of l not yet taken.
+func (t *tour) missing(l lessons.Lesson) []string {
+	var names []string
+	for _, name := range l.Requires {
+		if !t.done[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (t *tour) progress(width int) string {
+	n := 0
+	for _, l := range t.path {
+		if t.done[l.Name] {
+			n++
+		}
+	}
+	return tui.Bar(n, len(t.path), width)
+}
+
+// entry describes lesson i on one line.
+func (t *tour) entry(i int) string {
+	l := t.path[i]
+	mark := " "
+	if t.done[l.Name] {
+		mark = "x"
+	}
+	s := fmt.Sprintf("[%s] %d. %-10s %s", mark, i+1, l.Name, l.Summary)
+	if m := t.missing(l); len(m) > 0 {
+		s += " (after " + strings.Join(m, ", ") + ")"
+	}
+	return s
+}
+
+// prompt runs the tour as numbered prompts, for when input or output is
+// not a terminal.
+func (t *tour) prompt(in io.Reader, out io.Writer) error {
+	sc := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "\nProgress %s\n", t.progress(20))
+		for i := range t.path {
+			fmt.Fprintln(out, t.entry(i))
+		}
+		fmt.Fprint(out, "Lesson number, or q to quit: ")
+		if !sc.Scan() {
+			fmt.Fprintln(out)
+			return sc.Err()
+		}
+		answer := strings.TrimSpace(sc.Text())
+		if answer == "q" {
+			return nil
+		}
+		n, err := strconv.Atoi(answer)
+		if err != nil || n < 1 || n > len(t.path) {
+			fmt.Fprintf(out, "No lesson %q.\n", answer)
+			continue
+		}
+		t.run(n-1, out)
+	}
+}
+
+func (t *tour) run(i int, out io.Writer) {
+	l := t.path[i]
+	fmt.Fprintf(out, "\n== %s: %s ==\n", l.Name, l.Summary)
+	l.Run()
+	t.done[l.Name] = true
+}
+
+// screen runs the tour full screen: arrow keys or j and k choose a
+// lesson, Enter takes it and q leaves. A lesson runs on the main screen
+// in the terminal's usual mode, and Enter returns to the list.
+func (t *tour) screen() error {
+	scr := tui.NewScreen(os.Stdout)
+	restore, err := tui.MakeRaw(os.Stdin)
+	if err != nil {
+		return err
+	}
+	defer func() { restore() }()
+	if err := scr.Enter(); err != nil {
+		return err
+	}
+	defer scr.Exit()
+
+	keys := tui.NewKeyReader(os.Stdin)
+	cur := 0
+	for {
+		if err := scr.Draw(t.frame(cur)); err != nil {
+			return err
+		}
+		k, err := keys.ReadKey()
+		if err != nil {
+			return err
+		}
+		switch {
+		case k.Is('q') || k.Code == tui.KeyEsc || k.IsCtrl('c'):
+			return nil
+		case k.Code == tui.KeyUp || k.Is('k'):
+			cur = max(cur-1, 0)
+		case k.Code == tui.KeyDown || k.Is('j'):
+			cur = min(cur+1, len(t.path)-1)
+		case k.Code == tui.KeyHome:
+			cur = 0
+		case k.Code == tui.KeyEnd:
+			cur = len(t.path) - 1
+		case k.Code == tui.KeyEnter:
+			scr.Exit()
+			restore()
+			t.run(cur, os.Stdout)
+			fmt.Print("\nPress Enter to return to the tour.")
+			// Read through keys, which may hold input already, rather
+			// than a second buffer that would take input from it.
+			for {
+				k, err := keys.ReadKey()
+				if err != nil {
+					return err
+				}
+				if k.Code == tui.KeyEnter {
+					break
+				}
+			}
+			r, err := tui.MakeRaw(os.Stdin)
+			if err != nil {
+				return err
+			}
+			restore = r
+			scr.Enter()
+			if cur < len(t.path)-1 {
+				cur++
+			}
+		}
+	}
+}
+
+// frame returns the lines of the list with lesson cur selected, cut to
+// the terminal's size.
+func (t *tour) frame(cur int) []string {
+	width, height, _ := tui.Size(os.Stdout)
+	lines := []string{"golib tour", "", "Progress " + t.progress(30), ""}
+	for i, l := range t.path {
+		s := tui.Fit(t.entry(i), width)
+		switch {
+		case i == cur:
+			s = tui.Reverse(s)
+		case len(t.missing(l)) > 0:
+			s = tui.Dim(s)
+		}
+		lines = append(lines, s)
+	}
+	lines = append(lines, "", "↑/↓ choose · Enter run · q quit")
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	return lines
+}
@@ -0,0 +1,211 @@
+// Package colors parses, converts and mixes colours, and measures and
+// enforces WCAG contrast.
+//
+// Color implements image/color.Color, so colours from this package can be
+// drawn directly with the image and imaging packages.
+package colors
+
+import "fmt"
+import "math"
+import "strconv"
+import "strings"
+
+// Color is an 8-bit sRGB colour with straight (non-premultiplied) alpha.
+type Color struct {
+	R, G, B, A uint8
+}
+
+// RGB returns an opaque colour.
+func RGB(r, g, b uint8) Color { return Color{r, g, b, 255} }
+
+// RGBA implements image/color.Color.
+func (c Color) RGBA() (r, g, b, a uint32) {
+	a = uint32(c.A) * 0x101
+	r = uint32(c.R) * 0x101 * a / 0xffff
+	g = uint32(c.G) * 0x101 * a / 0xffff
+	b = uint32(c.B) * 0x101 * a / 0xffff
+	return r, g, b, a
+}
+
+// Hex returns c as "#rrggbb", or "#rrggbbaa" if it is not opaque.
+func (c Color) Hex() string {
+	if c.A == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+func (c Color) String() string { return c.Hex() }
+
+// Parse parses a CSS colour: a hex colour ("#f80", "#ff8800",
+// "#ff880080"), an rgb() or rgba() function, an hsl() or hsla() function,
+// or a colour name such as "rebeccapurple".
+func Parse(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if c, ok := named[s]; ok {
+		return c, nil
+	}
+	if strings.HasPrefix(s, "#") {
+		return parseHex(s[1:])
+	}
+	if name, args, ok := strings.Cut(s, "("); ok && strings.HasSuffix(args, ")") {
+		return parseFunc(strings.TrimSpace(name), args[:len(args)-1])
+	}
+	return Color{}, fmt.Errorf("colors: cannot parse %q", s)
+}
+
+// MustParse is like Parse but panics on error. It is meant for colour
+// constants known to be valid.
+func MustParse(s string) Color {
+	c, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func parseHex(h string) (Color, error) {
+	switch len(h) {
+	case 3, 4: // #rgb, #rgba: each digit is doubled
+		var b strings.Builder
+		for _, d := range h {
+			b.WriteRune(d)
+			b.WriteRune(d)
+		}
+		h = b.String()
+	case 6, 8:
+	default:
+		return Color{}, fmt.Errorf("colors: bad hex colour #%s", h)
+	}
+	if len(h) == 6 {
+		h += "ff"
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("colors: bad hex colour #%s", h)
+	}
+	return Color{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+func parseFunc(name, args string) (Color, error) {
+	// Accept both the legacy comma syntax and the modern space syntax
+	// with an optional "/ alpha".
+	args = strings.NewReplacer(",", " ", "/", " ").Replace(args)
+	parts := strings.Fields(args)
+	if len(parts) != 3 && len(parts) != 4 {
+		return Color{}, fmt.Errorf("colors: %s() takes 3 or 4 arguments", name)
+	}
+	alpha := 1.0
+	if len(parts) == 4 {
+		a, err := parseNumber(parts[3], 1)
+		if err != nil {
+			return Color{}, err
+		}
+		alpha = a
+	}
+
+	switch name {
+	case "rgb", "rgba":
+		var ch [3]uint8
+		for i, p := range parts[:3] {
+			v, err := parseNumber(p, 255)
+			if err != nil {
+				return Color{}, err
+			}
+			ch[i] = uint8(math.Round(v))
+		}
+		return Color{ch[0], ch[1], ch[2], to8(alpha)}, nil
+	case "hsl", "hsla":
+		h, err := strconv.ParseFloat(strings.TrimSuffix(parts[0], "deg"), 64)
+		if err != nil {
+			return Color{}, fmt.Errorf("colors: bad hue %q", parts[0])
+		}
+		s, err := parseNumber(parts[1], 1)
+		if err != nil {
+			return Color{}, err
+		}
+		l, err := parseNumber(parts[2], 1)
+		if err != nil {
+			return Color{}, err
+		}
+		c := FromHSL(h, s, l)
+		c.A = to8(alpha)
+		return c, nil
+	}
+	return Color{}, fmt.Errorf("colors: unknown function %s()", name)
+}
+
+// parseNumber parses a number or percentage, scaling percentages so that
+// 100% is full, and clamps the result to [0, full].
+func parseNumber(s string, full float64) (float64, error) {
+	pct := strings.HasSuffix(s, "%")
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("colors: bad number %q", s)
+	}
+	if pct {
+		v = v * full / 100
+	}
+	return math.Min(math.Max(v, 0), full), nil
+}
+
+// to8 converts a channel in [0, 1] to 8 bits.
+func to8(v float64) uint8 {
+	return uint8(math.Round(math.Min(math.Max(v, 0), 1) * 255))
+}
+
+// named holds the CSS basic colours and the most used extended ones.
+var named = map[string]Color{
+	"transparent":   {0, 0, 0, 0},
+	"black":         RGB(0x00, 0x00, 0x00),
+	"silver":        RGB(0xc0, 0xc0, 0xc0),
+	"gray":          RGB(0x80, 0x80, 0x80),
+	"grey":          RGB(0x80, 0x80, 0x80),
+	"white":         RGB(0xff, 0xff, 0xff),
+	"maroon":        RGB(0x80, 0x00, 0x00),
+	"red":           RGB(0xff, 0x00, 0x00),
+	"purple":        RGB(0x80, 0x00, 0x80),
+	"fuchsia":       RGB(0xff, 0x00, 0xff),
+	"magenta":       RGB(0xff, 0x00, 0xff),
+	"green":         RGB(0x00, 0x80, 0x00),
+	"lime":          RGB(0x00, 0xff, 0x00),
+	"olive":         RGB(0x80, 0x80, 0x00),
+	"yellow":        RGB(0xff, 0xff, 0x00),
+	"navy":          RGB(0x00, 0x00, 0x80),
+	"blue":          RGB(0x00, 0x00, 0xff),
+	"teal":          RGB(0x00, 0x80, 0x80),
+	"aqua":          RGB(0x00, 0xff, 0xff),
+	"cyan":          RGB(0x00, 0xff, 0xff),
+	"orange":        RGB(0xff, 0xa5, 0x00),
+	"gold":          RGB(0xff, 0xd7, 0x00),
+	"pink":          RGB(0xff, 0xc0, 0xcb),
+	"hotpink":       RGB(0xff, 0x69, 0xb4),
+	"crimson":       RGB(0xdc, 0x14, 0x3c),
+	"tomato":        RGB(0xff, 0x63, 0x47),
+	"coral":         RGB(0xff, 0x7f, 0x50),
+	"salmon":        RGB(0xfa, 0x80, 0x72),
+	"brown":         RGB(0xa5, 0x2a, 0x2a),
+	"chocolate":     RGB(0xd2, 0x69, 0x1e),
+	"tan":           RGB(0xd2, 0xb4, 0x8c),
+	"beige":         RGB(0xf5, 0xf5, 0xdc),
+	"ivory":         RGB(0xff, 0xff, 0xf0),
+	"khaki":         RGB(0xf0, 0xe6, 0x8c),
+	"indigo":        RGB(0x4b, 0x00, 0x82),
+	"violet":        RGB(0xee, 0x82, 0xee),
+	"orchid":        RGB(0xda, 0x70, 0xd6),
+	"plum":          RGB(0xdd, 0xa0, 0xdd),
+	"lavender":      RGB(0xe6, 0xe6, 0xfa),
+	"rebeccapurple": RGB(0x66, 0x33, 0x99),
+	"turquoise":     RGB(0x40, 0xe0, 0xd0),
+	"skyblue":       RGB(0x87, 0xce, 0xeb),
+	"steelblue":     RGB(0x46, 0x82, 0xb4),
+	"royalblue":     RGB(0x41, 0x69, 0xe1),
+	"slategray":     RGB(0x70, 0x80, 0x90),
+	"darkgray":      RGB(0xa9, 0xa9, 0xa9),
+	"lightgray":     RGB(0xd3, 0xd3, 0xd3),
+	"forestgreen":   RGB(0x22, 0x8b, 0x22),
+	"seagreen":      RGB(0x2e, 0x8b, 0x57),
+	"darkgreen":     RGB(0x00, 0x64, 0x00),
+	"darkred":       RGB(0x8b, 0x00, 0x00),
+	"darkblue":      RGB(0x00, 0x00, 0x8b),
+}
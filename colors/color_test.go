@@ -0,0 +1,53 @@
+package colors
+
+import "image/color"
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Color
+	}{
+		{"#f80", RGB(0xff, 0x88, 0x00)},
+		{"#F80A", Color{0xff, 0x88, 0x00, 0xaa}},
+		{"#336699", RGB(0x33, 0x66, 0x99)},
+		{"#33669980", Color{0x33, 0x66, 0x99, 0x80}},
+		{"rgb(255, 0, 128)", RGB(255, 0, 128)},
+		{"rgba(255,0,0,0.5)", Color{255, 0, 0, 128}},
+		{"rgb(100% 50% 0% / 25%)", Color{255, 128, 0, 64}},
+		{"hsl(120, 100%, 25%)", RGB(0, 128, 0)},
+		{"hsl(0deg 0% 100%)", RGB(255, 255, 255)},
+		{"  RebeccaPurple ", RGB(0x66, 0x33, 0x99)},
+		{"transparent", Color{}},
+	}
+	for _, c := range cases {
+		got, err := Parse(c.in)
+		if err != nil || got != c.want {
+			t.Errorf("Parse(%q) == %v, %v, want %v", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"", "#12", "#ggg", "rgb(1, 2)", "cmyk(1 2 3 4)", "rgb(a b c)", "notacolour"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) succeeded", in)
+		}
+	}
+}
+
+func TestHex(t *testing.T) {
+	if got := RGB(1, 171, 255).Hex(); got != "#01abff" {
+		t.Errorf("Hex() == %q", got)
+	}
+	if got := (Color{1, 2, 3, 4}).String(); got != "#01020304" {
+		t.Errorf("String() == %q", got)
+	}
+}
+
+func TestImageColor(t *testing.T) {
+	var c color.Color = Color{255, 0, 0, 128}
+	r, g, b, a := c.RGBA()
+	want := color.NRGBA{255, 0, 0, 128}
+	wr, wg, wb, wa := want.RGBA()
+	if r != wr || g != wg || b != wb || a != wa {
+		t.Errorf("RGBA() == %d %d %d %d, want %d %d %d %d", r, g, b, a, wr, wg, wb, wa)
+	}
+}
@@ -0,0 +1,114 @@
+package colors
+
+import "math"
+
+// WCAG 2 minimum contrast ratios.
+const (
+	ContrastAALarge = 3.0 // large text (18pt, or 14pt bold) at level AA
+	ContrastAA      = 4.5 // body text at level AA
+	ContrastAAA     = 7.0 // body text at level AAA
+)
+
+// Luminance returns the WCAG relative luminance of c, from 0 for black to
+// 1 for white. Alpha is ignored.
+func (c Color) Luminance() float64 {
+	lin := func(v uint8) float64 {
+		s := float64(v) / 255
+		if s <= 0.04045 {
+			return s / 12.92
+		}
+		return math.Pow((s+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(c.R) + 0.7152*lin(c.G) + 0.0722*lin(c.B)
+}
+
+// Contrast returns the WCAG contrast ratio between a and b, from 1 (no
+// contrast) to 21 (black on white). The order of a and b does not matter.
+func Contrast(a, b Color) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// Accessible returns fg adjusted, if needed, to reach at least ratio
+// contrast against bg. It keeps fg's hue and saturation and moves its
+// lightness away from bg's as little as possible. If even black or white
+// falls short, the better of the two is returned.
+func Accessible(fg, bg Color, ratio float64) Color {
+	if Contrast(fg, bg) >= ratio {
+		return fg
+	}
+	h, s, l := fg.HSL()
+	try := func(target float64) (Color, bool) {
+		// Binary-search the lightness between l and target for the
+		// closest value to l that meets the ratio.
+		near, far := l, target
+		end := FromHSL(h, s, far)
+		if Contrast(end, bg) < ratio {
+			return end, false
+		}
+		for range 20 {
+			mid := (near + far) / 2
+			if Contrast(FromHSL(h, s, mid), bg) >= ratio {
+				far = mid
+			} else {
+				near = mid
+			}
+		}
+		return FromHSL(h, s, far), true
+	}
+
+	// Prefer the direction of greater contrast: darken on light
+	// backgrounds and lighten on dark ones.
+	first, second := 0.0, 1.0
+	if bg.Luminance() < 0.18 {
+		first, second = 1.0, 0.0
+	}
+	withAlpha := func(c Color) Color { c.A = fg.A; return c }
+	if c, ok := try(first); ok {
+		return withAlpha(c)
+	}
+	if c, ok := try(second); ok {
+		return withAlpha(c)
+	}
+	black, white := RGB(0, 0, 0), RGB(255, 255, 255)
+	if Contrast(black, bg) >= Contrast(white, bg) {
+		return withAlpha(black)
+	}
+	return withAlpha(white)
+}
+
+// Palette returns n colours with hues evenly spaced around the colour
+// wheel starting at base, each adjusted with Accessible to reach ratio
+// against bg. It suits categorical data such as chart series.
+func Palette(base, bg Color, n int, ratio float64) []Color {
+	if n <= 0 {
+		return nil
+	}
+	h, s, l := base.HSL()
+	out := make([]Color, n)
+	for i := range out {
+		c := FromHSL(h+360*float64(i)/float64(n), s, l)
+		out[i] = Accessible(c, bg, ratio)
+	}
+	return out
+}
+
+// Shades returns n colours with base's hue and saturation, from light to
+// dark with evenly spaced lightness: a single-hue scale suitable for
+// heatmaps.
+func Shades(base Color, n int) []Color {
+	if n <= 0 {
+		return nil
+	}
+	h, s, _ := base.HSL()
+	out := make([]Color, n)
+	for i := range out {
+		// Spread lightness over [0.95, 0.15], avoiding pure white and black.
+		l := 0.95 - 0.8*float64(i)/math.Max(float64(n-1), 1)
+		out[i] = FromHSL(h, s, l)
+	}
+	return out
+}
@@ -0,0 +1,85 @@
+package colors
+
+import "math"
+import "testing"
+
+func TestContrast(t *testing.T) {
+	black, white := RGB(0, 0, 0), RGB(255, 255, 255)
+	cases := []struct {
+		a, b Color
+		want float64
+	}{
+		{black, white, 21},
+		{white, black, 21},
+		{white, white, 1},
+		{RGB(0x77, 0x77, 0x77), white, 4.48},
+		{RGB(0, 0, 255), white, 8.59},
+	}
+	for _, c := range cases {
+		if got := Contrast(c.a, c.b); math.Abs(got-c.want) > 0.01 {
+			t.Errorf("Contrast(%v, %v) == %.2f, want %.2f", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestAccessible(t *testing.T) {
+	white, dark := RGB(255, 255, 255), RGB(0x12, 0x12, 0x12)
+	cases := []struct {
+		fg, bg Color
+		ratio  float64
+	}{
+		{RGB(255, 200, 0), white, ContrastAA},
+		{RGB(0, 0, 180), dark, ContrastAA},
+		{RGB(0x77, 0x77, 0x77), white, ContrastAAA},
+		{RGB(0, 0, 0), white, ContrastAA}, // already fine
+	}
+	for _, c := range cases {
+		got := Accessible(c.fg, c.bg, c.ratio)
+		if r := Contrast(got, c.bg); r < c.ratio {
+			t.Errorf("Accessible(%v, %v, %v) == %v with contrast %.2f", c.fg, c.bg, c.ratio, got, r)
+		}
+		h1, _, _ := c.fg.HSL()
+		h2, _, _ := got.HSL()
+		if s := got; s != RGB(0, 0, 0) && s != RGB(255, 255, 255) && math.Abs(h1-h2) > 3 {
+			t.Errorf("Accessible(%v) changed hue from %.0f to %.0f", c.fg, h1, h2)
+		}
+	}
+	if got := Accessible(RGB(0, 0, 0), white, ContrastAA); got != RGB(0, 0, 0) {
+		t.Errorf("Accessible changed a compliant colour to %v", got)
+	}
+	// Mid grey cannot reach 21:1 with anything; the best extreme wins.
+	if got := Accessible(RGB(200, 0, 0), RGB(128, 128, 128), 21); got != RGB(0, 0, 0) && got != RGB(255, 255, 255) {
+		t.Errorf("Accessible(unreachable) == %v", got)
+	}
+}
+
+func TestPaletteAndShades(t *testing.T) {
+	white := RGB(255, 255, 255)
+	p := Palette(RGB(0xe6, 0x39, 0x46), white, 6, ContrastAA)
+	if len(p) != 6 {
+		t.Fatalf("len(Palette) == %d", len(p))
+	}
+	seen := map[Color]bool{}
+	for _, c := range p {
+		if Contrast(c, white) < ContrastAA {
+			t.Errorf("palette colour %v has contrast %.2f", c, Contrast(c, white))
+		}
+		seen[c] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("Palette has duplicates: %v", p)
+	}
+
+	for _, n := range []int{0, -1} {
+		if p := Palette(RGB(0xe6, 0x39, 0x46), white, n, ContrastAA); p != nil {
+			t.Errorf("Palette(%d) == %v, want nil", n, p)
+		}
+	}
+
+	s := Shades(RGB(0, 0, 255), 5)
+	for i := 1; i < len(s); i++ {
+		if s[i].Luminance() >= s[i-1].Luminance() {
+			t.Errorf("Shades not getting darker at %d: %v", i, s)
+		}
+	}
+}
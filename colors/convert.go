@@ -0,0 +1,111 @@
+package colors
+
+import "math"
+
+// HSL returns c's hue in degrees [0, 360) and its saturation and
+// lightness in [0, 1]. Alpha is ignored.
+func (c Color) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (hi + lo) / 2
+	if d := hi - lo; d > 0 {
+		s = d / (1 - math.Abs(2*l-1))
+		h = hue(r, g, b, hi, d)
+	}
+	return h, s, l
+}
+
+// HSV returns c's hue in degrees [0, 360) and its saturation and value in
+// [0, 1]. Alpha is ignored.
+func (c Color) HSV() (h, s, v float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	v = hi
+	if d := hi - lo; d > 0 {
+		s = d / hi
+		h = hue(r, g, b, hi, d)
+	}
+	return h, s, v
+}
+
+func hue(r, g, b, hi, d float64) float64 {
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// FromHSL returns the opaque colour with hue h in degrees and saturation
+// s and lightness l in [0, 1].
+func FromHSL(h, s, l float64) Color {
+	s, l = clamp01(s), clamp01(l)
+	chroma := (1 - math.Abs(2*l-1)) * s
+	return fromHueChroma(h, chroma, l-chroma/2)
+}
+
+// FromHSV returns the opaque colour with hue h in degrees and saturation
+// s and value v in [0, 1].
+func FromHSV(h, s, v float64) Color {
+	s, v = clamp01(s), clamp01(v)
+	chroma := v * s
+	return fromHueChroma(h, chroma, v-chroma)
+}
+
+func fromHueChroma(h, chroma, m float64) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	hp := h / 60
+	x := chroma * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch int(hp) {
+	case 0:
+		r, g = chroma, x
+	case 1:
+		r, g = x, chroma
+	case 2:
+		g, b = chroma, x
+	case 3:
+		g, b = x, chroma
+	case 4:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	return Color{to8(r + m), to8(g + m), to8(b + m), 255}
+}
+
+func clamp01(v float64) float64 { return math.Min(math.Max(v, 0), 1) }
+
+// Lighten returns c with its HSL lightness increased by amount, where 1
+// is the full range; the result is clamped at white.
+func (c Color) Lighten(amount float64) Color {
+	h, s, l := c.HSL()
+	out := FromHSL(h, s, l+amount)
+	out.A = c.A
+	return out
+}
+
+// Darken returns c with its HSL lightness decreased by amount.
+func (c Color) Darken(amount float64) Color { return c.Lighten(-amount) }
+
+// Mix blends c with other: weight 0 gives c, 1 gives other. Channels,
+// including alpha, are interpolated linearly.
+func (c Color) Mix(other Color, weight float64) Color {
+	w := clamp01(weight)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a)*(1-w) + float64(b)*w))
+	}
+	return Color{mix(c.R, other.R), mix(c.G, other.G), mix(c.B, other.B), mix(c.A, other.A)}
+}
@@ -0,0 +1,61 @@
+package colors
+
+import "math"
+import "testing"
+
+func TestHSLHSV(t *testing.T) {
+	cases := []struct {
+		c          Color
+		h, s, l, v float64
+		sv         float64 // HSV saturation
+	}{
+		{RGB(255, 0, 0), 0, 1, 0.5, 1, 1},
+		{RGB(0, 255, 0), 120, 1, 0.5, 1, 1},
+		{RGB(0, 0, 255), 240, 1, 0.5, 1, 1},
+		{RGB(255, 255, 255), 0, 0, 1, 1, 0},
+		{RGB(0, 0, 0), 0, 0, 0, 0, 0},
+		{RGB(0x66, 0x33, 0x99), 270, 0.5, 0.4, 0.6, 2.0 / 3},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 0.005 }
+	for _, c := range cases {
+		h, s, l := c.c.HSL()
+		if !near(h, c.h) || !near(s, c.s) || !near(l, c.l) {
+			t.Errorf("%v.HSL() == %.3f %.3f %.3f, want %v %v %v", c.c, h, s, l, c.h, c.s, c.l)
+		}
+		if got := FromHSL(h, s, l); got != c.c {
+			t.Errorf("FromHSL(%v.HSL()) == %v", c.c, got)
+		}
+		h, s, v := c.c.HSV()
+		if !near(h, c.h) || !near(s, c.sv) || !near(v, c.v) {
+			t.Errorf("%v.HSV() == %.3f %.3f %.3f, want %v %v %v", c.c, h, s, v, c.h, c.sv, c.v)
+		}
+		if got := FromHSV(h, s, v); got != c.c {
+			t.Errorf("FromHSV(%v.HSV()) == %v", c.c, got)
+		}
+	}
+	if got := FromHSL(-120, 1, 0.5); got != RGB(0, 0, 255) {
+		t.Errorf("FromHSL(-120, ...) == %v", got)
+	}
+}
+
+func TestLightenDarkenMix(t *testing.T) {
+	red := RGB(255, 0, 0)
+	if got := red.Lighten(0.25); got != RGB(255, 128, 128) {
+		t.Errorf("Lighten(0.25) == %v", got)
+	}
+	if got := red.Darken(0.25); got != RGB(128, 0, 0) {
+		t.Errorf("Darken(0.25) == %v", got)
+	}
+	if got := red.Lighten(2); got != RGB(255, 255, 255) {
+		t.Errorf("Lighten(2) == %v", got)
+	}
+	if got := (Color{255, 0, 0, 10}).Darken(0.1); got.A != 10 {
+		t.Errorf("Darken lost alpha: %v", got)
+	}
+	if got := red.Mix(RGB(0, 0, 255), 0.5); got != RGB(128, 0, 128) {
+		t.Errorf("Mix() == %v", got)
+	}
+	if got := red.Mix(RGB(0, 0, 255), 0); got != red {
+		t.Errorf("Mix(0) == %v", got)
+	}
+}
@@ -0,0 +1,58 @@
+package golib
+
+// Compose returns the function that applies f and then g: Compose(g, f)(x)
+// is g(f(x)), in the order of mathematical composition.
+func Compose[A, B, C any](g func(B) C, f func(A) B) func(A) C {
+	return func(a A) C { return g(f(a)) }
+}
+
+// Pipe returns the function that applies fns in turn, left to right, each
+// to the result of the one before. With no functions it returns its
+// argument.
+func Pipe[T any](fns ...func(T) T) func(T) T {
+	return func(v T) T {
+		for _, fn := range fns {
+			v = fn(v)
+		}
+		return v
+	}
+}
+
+// Pipe2 is like Compose but takes the functions in the order they apply,
+// which suits a chain whose types change.
+func Pipe2[A, B, C any](f func(A) B, g func(B) C) func(A) C {
+	return func(a A) C { return g(f(a)) }
+}
+
+// Pipe3 is Pipe2 for three functions.
+func Pipe3[A, B, C, D any](f func(A) B, g func(B) C, h func(C) D) func(A) D {
+	return func(a A) D { return h(g(f(a))) }
+}
+
+// PipeE is like Pipe for functions that can fail: it stops at the first
+// error and returns it, with the zero T.
+func PipeE[T any](fns ...func(T) (T, error)) func(T) (T, error) {
+	return func(v T) (T, error) {
+		for _, fn := range fns {
+			var err error
+			if v, err = fn(v); err != nil {
+				var zero T
+				return zero, err
+			}
+		}
+		return v, nil
+	}
+}
+
+// PipeE2 is like Pipe2 for functions that can fail: g is not called if f
+// fails.
+func PipeE2[A, B, C any](f func(A) (B, error), g func(B) (C, error)) func(A) (C, error) {
+	return func(a A) (C, error) {
+		b, err := f(a)
+		if err != nil {
+			var zero C
+			return zero, err
+		}
+		return g(b)
+	}
+}
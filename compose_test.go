@@ -0,0 +1,51 @@
+package golib
+
+import "errors"
+import "strconv"
+import "strings"
+import "testing"
+
+func TestCompose(t *testing.T) {
+	length := func(s string) int { return len(s) }
+	double := func(n int) int { return n * 2 }
+	if got := Compose(double, length)("abc"); got != 6 {
+		t.Errorf("Compose = %d", got)
+	}
+	if got := Pipe2(length, strconv.Itoa)("abcd"); got != "4" {
+		t.Errorf("Pipe2 = %q", got)
+	}
+	if got := Pipe3(strings.TrimSpace, length, double)("  ab  "); got != 4 {
+		t.Errorf("Pipe3 = %d", got)
+	}
+	if got := Pipe(strings.TrimSpace, strings.ToUpper, Reverse)(" abc "); got != "CBA" {
+		t.Errorf("Pipe = %q", got)
+	}
+	if got := Pipe[int]()(7); got != 7 {
+		t.Errorf("empty Pipe = %d", got)
+	}
+}
+
+func TestPipeE(t *testing.T) {
+	boom := errors.New("boom")
+	ran := false
+	half := func(n int) (int, error) {
+		if n%2 != 0 {
+			return 0, boom
+		}
+		return n / 2, nil
+	}
+	after := func(n int) (int, error) { ran = true; return n, nil }
+	if got, err := PipeE(half, half)(8); got != 2 || err != nil {
+		t.Errorf("PipeE(8) = %d, %v", got, err)
+	}
+	if got, err := PipeE(half, half, after)(6); got != 0 || !errors.Is(err, boom) || ran {
+		t.Errorf("PipeE(6) = %d, %v; later step ran: %v", got, err, ran)
+	}
+	parse := PipeE2(strconv.Atoi, half)
+	if got, err := parse("10"); got != 5 || err != nil {
+		t.Errorf("PipeE2(10) = %d, %v", got, err)
+	}
+	if _, err := parse("x"); err == nil {
+		t.Error("PipeE2 ignored a parse error")
+	}
+}
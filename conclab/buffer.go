@@ -0,0 +1,141 @@
+package conclab
+
+import "context"
+import "sync"
+import "sync/atomic"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+// BoundedBuffer is the producer/consumer problem: producers put items
+// into a buffer of fixed capacity and consumers take them out. It is
+// built the textbook way, from a lock and two counting semaphores for
+// the free slots and the filled ones.
+type BoundedBuffer struct {
+	// Producers and Consumers are the number of each. Zero means 1.
+	Producers, Consumers int
+	// Items is how many items each producer puts. Zero means until the
+	// context is done.
+	Items int
+	// Capacity is the size of the buffer. Zero means 1.
+	Capacity int
+	// Produce and Consume are how long making and using an item take,
+	// outside the buffer's lock.
+	Produce, Consume time.Duration
+	// LockFirst takes the buffer's lock before waiting on a semaphore:
+	// the textbook mistake. A producer holding the lock while the
+	// buffer is full, or a consumer holding it while it is empty, waits
+	// for someone who in turn waits for the lock.
+	LockFirst bool
+
+	Clock           clock.Clock // nil means clock.Real
+	DeadlockTimeout time.Duration
+}
+
+// BufferStats describes a bounded buffer simulation.
+type BufferStats struct {
+	Produced []int // per producer
+	Consumed []int // per consumer
+	// MaxDepth is the most items the buffer held at once.
+	MaxDepth int
+	// ProducerWait and ConsumerWait are the longest any producer waited
+	// for a free slot or consumer for an item, including the lock.
+	ProducerWait, ConsumerWait time.Duration
+}
+
+// Fairness returns the fairness index of the items consumed.
+func (s BufferStats) Fairness() float64 { return Fairness(s.Consumed) }
+
+// Run starts the producers and consumers and returns once every item has
+// been produced and consumed, or with ErrDeadlock or the context's error
+// if the simulation ends first.
+func (b BoundedBuffer) Run(ctx context.Context) (BufferStats, error) {
+	np, nc, capacity := max(b.Producers, 1), max(b.Consumers, 1), max(b.Capacity, 1)
+	st := BufferStats{Produced: make([]int, np), Consumed: make([]int, nc)}
+	var (
+		lock   = semaphore(1)
+		free   = semaphore(capacity)
+		filled = make(chan struct{}, capacity)
+		items  []int
+		depth  int
+
+		remaining atomic.Int64 // items still to be consumed, if bounded
+		waits     = make([]time.Duration, np+nc)
+	)
+	remaining.Store(int64(np * b.Items))
+
+	s, stop := start(ctx, b.Clock, b.DeadlockTimeout)
+	// enter waits on sem and takes the lock in the configured order.
+	enter := func(s *sim, sem chan struct{}) bool {
+		if b.LockFirst {
+			return s.acquire(lock) && s.acquire(sem)
+		}
+		return s.acquire(sem) && s.acquire(lock)
+	}
+
+	var wg sync.WaitGroup
+	for p := range np {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; b.Items == 0 || i < b.Items; i++ {
+				if !s.sleep(b.Produce) {
+					return
+				}
+				t := s.clk.Now()
+				if !enter(s, free) {
+					return
+				}
+				waits[p] = max(waits[p], s.clk.Now().Sub(t))
+				items = append(items, p)
+				depth = max(depth, len(items))
+				release(lock)
+				release(filled)
+				st.Produced[p]++
+				s.progress.Add(1)
+			}
+		}()
+	}
+	// Consumers stop when every item is consumed, so the last one tells
+	// the rest by cancelling.
+	done, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	for c := range nc {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cs := &sim{clk: s.clk, ctx: done}
+			for b.Items == 0 || remaining.Load() > 0 {
+				t := s.clk.Now()
+				if !enter(cs, filled) {
+					return
+				}
+				waits[np+c] = max(waits[np+c], s.clk.Now().Sub(t))
+				items = items[1:]
+				release(lock)
+				release(free)
+				if remaining.Add(-1) == 0 && b.Items > 0 {
+					cancel()
+				}
+				st.Consumed[c]++
+				s.progress.Add(1)
+				if !s.sleep(b.Consume) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	st.MaxDepth = depth
+	st.ProducerWait = maxOf(waits[:np])
+	st.ConsumerWait = maxOf(waits[np:])
+	return st, stop()
+}
+
+func maxOf(ds []time.Duration) time.Duration {
+	var m time.Duration
+	for _, d := range ds {
+		m = max(m, d)
+	}
+	return m
+}
@@ -0,0 +1,41 @@
+package conclab
+
+import "context"
+import "errors"
+import "testing"
+import "time"
+
+func TestBoundedBuffer(t *testing.T) {
+	b := BoundedBuffer{Producers: 3, Consumers: 2, Items: 100, Capacity: 4, DeadlockTimeout: 5 * time.Second}
+	st, err := b.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, n := range st.Produced {
+		if n != 100 {
+			t.Errorf("producer %d produced %d, want 100", i, n)
+		}
+	}
+	if total := st.Consumed[0] + st.Consumed[1]; total != 300 {
+		t.Errorf("consumed %d, want 300", total)
+	}
+	if st.MaxDepth < 1 || st.MaxDepth > 4 {
+		t.Errorf("MaxDepth == %d, want 1 to 4", st.MaxDepth)
+	}
+	if f := st.Fairness(); f <= 0 || f > 1 {
+		t.Errorf("Fairness() == %v", f)
+	}
+}
+
+func TestBoundedBufferLockFirst(t *testing.T) {
+	// A slow producer leaves the buffer empty, so the consumer soon
+	// waits for an item while holding the lock the producer needs.
+	b := BoundedBuffer{Items: 1000, Produce: time.Millisecond, LockFirst: true, DeadlockTimeout: 50 * time.Millisecond}
+	st, err := b.Run(context.Background())
+	if !errors.Is(err, ErrDeadlock) {
+		t.Fatalf("Run() error == %v, want ErrDeadlock", err)
+	}
+	if st.Produced[0] == 1000 {
+		t.Error("every item was produced despite the deadlock")
+	}
+}
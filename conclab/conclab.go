@@ -0,0 +1,116 @@
+// Package conclab simulates the classic problems of concurrent
+// programming — the dining philosophers, the bounded buffer shared by
+// producers and consumers, and readers and writers sharing a resource —
+// with instrumentation to show how each strategy behaves.
+//
+// Every simulation waits on a clock.Clock, so a test can drive it with a
+// clock.Fake and arrange the interleaving it wants to study. A watchdog on
+// the same clock ends a simulation with ErrDeadlock once no goroutine has
+// made progress for the deadlock timeout; the statistics gathered up to
+// that point are still returned.
+package conclab
+
+import "context"
+import "errors"
+import "sync/atomic"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+// DefaultDeadlockTimeout is how long a simulation may go without progress
+// before it is declared deadlocked, unless configured otherwise.
+const DefaultDeadlockTimeout = time.Second
+
+// ErrDeadlock reports that a simulation stopped making progress.
+var ErrDeadlock = errors.New("conclab: no progress before the deadlock timeout")
+
+// Fairness returns Jain's fairness index of counts: 1 when every count is
+// equal, falling to 1/len(counts) when one participant has everything.
+// It returns 0 if counts is empty or all zero.
+func Fairness(counts []int) float64 {
+	var sum, squares float64
+	for _, c := range counts {
+		sum += float64(c)
+		squares += float64(c) * float64(c)
+	}
+	if squares == 0 {
+		return 0
+	}
+	return sum * sum / (float64(len(counts)) * squares)
+}
+
+// sim holds what every simulation shares: its clock, its context and the
+// progress counter the watchdog checks.
+type sim struct {
+	clk      clock.Clock
+	ctx      context.Context
+	progress atomic.Int64
+}
+
+// start returns a sim whose context is cancelled with ErrDeadlock when
+// progress stalls for timeout, and a function that stops it and reports
+// why it ended: nil if it was stopped first, ErrDeadlock, or the parent
+// context's error.
+func start(ctx context.Context, clk clock.Clock, timeout time.Duration) (*sim, func() error) {
+	if timeout <= 0 {
+		timeout = DefaultDeadlockTimeout
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	s := &sim{clk: clock.Or(clk), ctx: ctx}
+	go func() {
+		for {
+			last := s.progress.Load()
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.clk.After(timeout):
+			}
+			if s.progress.Load() == last {
+				cancel(ErrDeadlock)
+				return
+			}
+		}
+	}()
+	return s, func() error {
+		err := context.Cause(ctx)
+		cancel(nil)
+		return err
+	}
+}
+
+// sleep waits for d on the simulation's clock, reporting false if the
+// simulation ended first.
+func (s *sim) sleep(d time.Duration) bool {
+	if d <= 0 {
+		return s.ctx.Err() == nil
+	}
+	select {
+	case <-s.clk.After(d):
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// acquire takes a token from c, a channel used as a semaphore, reporting
+// false if the simulation ended first.
+func (s *sim) acquire(c chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// release returns a token to c.
+func release(c chan struct{}) { c <- struct{}{} }
+
+// semaphore returns a channel holding n tokens.
+func semaphore(n int) chan struct{} {
+	c := make(chan struct{}, n)
+	for range n {
+		c <- struct{}{}
+	}
+	return c
+}
@@ -0,0 +1,48 @@
+package conclab
+
+import "context"
+import "errors"
+import "math"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+func TestFairness(t *testing.T) {
+	cases := []struct {
+		counts []int
+		want   float64
+	}{
+		{nil, 0},
+		{[]int{0, 0}, 0},
+		{[]int{3, 3, 3}, 1},
+		{[]int{4, 0, 0, 0}, 0.25},
+		{[]int{1, 3}, 0.8},
+	}
+	for _, c := range cases {
+		if got := Fairness(c.counts); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("Fairness(%v) == %v, want %v", c.counts, got, c.want)
+		}
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	f := clock.NewFake(time.Time{})
+	s, stop := start(context.Background(), f, time.Minute)
+
+	// Progress within each period keeps the watchdog quiet.
+	for range 3 {
+		f.BlockUntil(1)
+		s.progress.Add(1)
+		f.Advance(time.Minute)
+	}
+	if s.ctx.Err() != nil {
+		t.Fatal("watchdog fired despite progress")
+	}
+	f.BlockUntil(1)
+	f.Advance(time.Minute)
+	<-s.ctx.Done()
+	if err := stop(); !errors.Is(err, ErrDeadlock) {
+		t.Errorf("stop() == %v, want ErrDeadlock", err)
+	}
+}
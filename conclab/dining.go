@@ -0,0 +1,128 @@
+package conclab
+
+import "context"
+import "sync"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+// A Strategy is how a dining philosopher picks up forks.
+type Strategy int
+
+const (
+	// Naive picks up the left fork and then the right. If every
+	// philosopher holds a left fork at once, none can eat: deadlock.
+	Naive Strategy = iota
+	// Ordered picks up the lower-numbered fork first. A total order on
+	// resources rules out a cycle of waiters.
+	Ordered
+	// Waiter lets at most all but one philosopher reach for forks at
+	// once, so at least one can always take both.
+	Waiter
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case Naive:
+		return "naive"
+	case Ordered:
+		return "ordered"
+	case Waiter:
+		return "waiter"
+	}
+	return "unknown"
+}
+
+// Dining is the dining philosophers problem: philosophers around a table
+// alternate between thinking and eating, and each needs the forks on both
+// sides to eat.
+type Dining struct {
+	// Philosophers is the number at the table. Fewer than two means 5.
+	Philosophers int
+	// Meals is how many times each philosopher eats. Zero means until
+	// the context is done.
+	Meals    int
+	Strategy Strategy
+	// Think and Eat are how long each activity takes. Reach is the pause
+	// between picking up the first fork and the second, which widens the
+	// window for a deadlock.
+	Think, Eat, Reach time.Duration
+
+	Clock           clock.Clock // nil means clock.Real
+	DeadlockTimeout time.Duration
+}
+
+// DiningStats describes a dining simulation. Each slice has one element
+// per philosopher.
+type DiningStats struct {
+	Meals []int
+	// MaxWait is the longest a philosopher went hungry: from finishing
+	// thinking to holding both forks.
+	MaxWait []time.Duration
+	// Holding is the number of forks each philosopher held when the
+	// simulation ended. After a deadlock under Naive, every philosopher
+	// holds one.
+	Holding []int
+}
+
+// Fairness returns the fairness index of the meals eaten.
+func (s DiningStats) Fairness() float64 { return Fairness(s.Meals) }
+
+// Run seats the philosophers and returns once each has eaten d.Meals, or
+// with ErrDeadlock or the context's error if the simulation ends first.
+func (d Dining) Run(ctx context.Context) (DiningStats, error) {
+	n := d.Philosophers
+	if n < 2 {
+		n = 5
+	}
+	st := DiningStats{Meals: make([]int, n), MaxWait: make([]time.Duration, n), Holding: make([]int, n)}
+	forks := make([]chan struct{}, n)
+	for i := range forks {
+		forks[i] = semaphore(1)
+	}
+	seats := semaphore(n - 1)
+
+	s, stop := start(ctx, d.Clock, d.DeadlockTimeout)
+	var wg sync.WaitGroup
+	for i := range n {
+		first, second := i, (i+1)%n
+		if d.Strategy == Ordered && second < first {
+			first, second = second, first
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for meal := 0; d.Meals == 0 || meal < d.Meals; meal++ {
+				if !s.sleep(d.Think) {
+					return
+				}
+				hungry := s.clk.Now()
+				if d.Strategy == Waiter && !s.acquire(seats) {
+					return
+				}
+				if !s.acquire(forks[first]) {
+					return
+				}
+				st.Holding[i]++
+				if !s.sleep(d.Reach) || !s.acquire(forks[second]) {
+					return
+				}
+				st.Holding[i]++
+				st.MaxWait[i] = max(st.MaxWait[i], s.clk.Now().Sub(hungry))
+				if !s.sleep(d.Eat) {
+					return
+				}
+				st.Meals[i]++
+				s.progress.Add(1)
+				release(forks[second])
+				release(forks[first])
+				st.Holding[i] = 0
+				if d.Strategy == Waiter {
+					release(seats)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return st, stop()
+}
@@ -0,0 +1,68 @@
+package conclab
+
+import "context"
+import "errors"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+func TestDiningDeadlock(t *testing.T) {
+	f := clock.NewFake(time.Time{})
+	d := Dining{Philosophers: 5, Meals: 1, Strategy: Naive, Reach: time.Second, Clock: f, DeadlockTimeout: time.Minute}
+	type result struct {
+		st  DiningStats
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		st, err := d.Run(context.Background())
+		done <- result{st, err}
+	}()
+
+	// Every philosopher pauses holding a left fork, alongside the
+	// watchdog; after that nobody can take a right fork.
+	f.BlockUntil(6)
+	f.Advance(time.Second)
+	f.Advance(time.Minute)
+	r := <-done
+	if !errors.Is(r.err, ErrDeadlock) {
+		t.Fatalf("Run() error == %v, want ErrDeadlock", r.err)
+	}
+	for i := range 5 {
+		if r.st.Holding[i] != 1 || r.st.Meals[i] != 0 {
+			t.Errorf("philosopher %d holds %d forks after %d meals, want 1 after 0", i, r.st.Holding[i], r.st.Meals[i])
+		}
+	}
+}
+
+func TestDiningStrategies(t *testing.T) {
+	for _, s := range []Strategy{Ordered, Waiter} {
+		d := Dining{Meals: 50, Strategy: s, Reach: time.Microsecond, DeadlockTimeout: 5 * time.Second}
+		st, err := d.Run(context.Background())
+		if err != nil {
+			t.Errorf("%v: %v", s, err)
+			continue
+		}
+		for i, m := range st.Meals {
+			if m != 50 || st.Holding[i] != 0 {
+				t.Errorf("%v: philosopher %d ate %d meals, holding %d forks", s, i, m, st.Holding[i])
+			}
+		}
+		if st.Fairness() != 1 {
+			t.Errorf("%v: Fairness() == %v", s, st.Fairness())
+		}
+	}
+}
+
+func TestDiningCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	st, err := Dining{Strategy: Ordered, Eat: time.Millisecond}.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error == %v, want context.DeadlineExceeded", err)
+	}
+	if len(st.Meals) != 5 {
+		t.Errorf("len(Meals) == %d, want 5", len(st.Meals))
+	}
+}
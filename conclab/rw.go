@@ -0,0 +1,224 @@
+package conclab
+
+import "context"
+import "slices"
+import "sync"
+import "sync/atomic"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+// A Policy decides who goes next when readers and writers both wait.
+type Policy int
+
+const (
+	// ReadersFirst admits a reader whenever no writer is active. A
+	// steady stream of readers can starve the writers.
+	ReadersFirst Policy = iota
+	// WritersFirst holds back new readers while any writer waits. A
+	// steady stream of writers can starve the readers.
+	WritersFirst
+	// FIFO admits everyone in arrival order, letting consecutive readers
+	// in together. Nobody starves.
+	FIFO
+)
+
+func (p Policy) String() string {
+	switch p {
+	case ReadersFirst:
+		return "readers-first"
+	case WritersFirst:
+		return "writers-first"
+	case FIFO:
+		return "fifo"
+	}
+	return "unknown"
+}
+
+// ReadersWriters is the readers/writers problem: any number of readers
+// may share a resource at once, but a writer needs it to itself.
+type ReadersWriters struct {
+	// Readers and Writers are the number of each. Zero means 1.
+	Readers, Writers int
+	// Ops is how many times each reader reads or writer writes. Zero
+	// means until the context is done.
+	Ops    int
+	Policy Policy
+	// Read and Write are how long each access holds the resource, and
+	// Pause the time between one access and the next.
+	Read, Write, Pause time.Duration
+
+	Clock           clock.Clock // nil means clock.Real
+	DeadlockTimeout time.Duration
+}
+
+// RWStats describes a readers/writers simulation.
+type RWStats struct {
+	Reads  []int // per reader
+	Writes []int // per writer
+	// MaxReaders is the most readers that shared the resource at once.
+	MaxReaders int
+	// ReadWait and WriteWait are the longest any reader or writer waited
+	// to be admitted.
+	ReadWait, WriteWait time.Duration
+	// Violations counts accesses that found the resource shared when
+	// they should not have: a writer alongside anyone else. A correct
+	// lock leaves it zero.
+	Violations int
+}
+
+// Fairness returns the fairness index of all the accesses, readers and
+// writers together.
+func (s RWStats) Fairness() float64 { return Fairness(slices.Concat(s.Reads, s.Writes)) }
+
+// Run starts the readers and writers and returns once each has made its
+// accesses, or with ErrDeadlock or the context's error if the simulation
+// ends first.
+func (rw ReadersWriters) Run(ctx context.Context) (RWStats, error) {
+	nr, nw := max(rw.Readers, 1), max(rw.Writers, 1)
+	st := RWStats{Reads: make([]int, nr), Writes: make([]int, nw)}
+	s, stop := start(ctx, rw.Clock, rw.DeadlockTimeout)
+	l := newRWLock(s.ctx, rw.Policy)
+
+	// The lock's own bookkeeping is not trusted: these counters check it.
+	var readers, writers, maxReaders, violations atomic.Int64
+	waits := make([]time.Duration, nr+nw)
+
+	access := func(i int, write bool, n *int, hold time.Duration) {
+		for op := 0; rw.Ops == 0 || op < rw.Ops; op++ {
+			if op > 0 && !s.sleep(rw.Pause) {
+				return
+			}
+			t := s.clk.Now()
+			if !l.lock(write) {
+				return
+			}
+			waits[i] = max(waits[i], s.clk.Now().Sub(t))
+			if write {
+				if writers.Add(1) > 1 || readers.Load() > 0 {
+					violations.Add(1)
+				}
+			} else {
+				r := readers.Add(1)
+				if writers.Load() > 0 {
+					violations.Add(1)
+				}
+				for m := maxReaders.Load(); r > m; m = maxReaders.Load() {
+					if maxReaders.CompareAndSwap(m, r) {
+						break
+					}
+				}
+			}
+			ok := s.sleep(hold)
+			if write {
+				writers.Add(-1)
+			} else {
+				readers.Add(-1)
+			}
+			l.unlock(write)
+			if !ok {
+				return
+			}
+			*n++
+			s.progress.Add(1)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range nr {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			access(i, false, &st.Reads[i], rw.Read)
+		}()
+	}
+	for i := range nw {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			access(nr+i, true, &st.Writes[i], rw.Write)
+		}()
+	}
+	wg.Wait()
+	st.MaxReaders = int(maxReaders.Load())
+	st.Violations = int(violations.Load())
+	st.ReadWait = maxOf(waits[:nr])
+	st.WriteWait = maxOf(waits[nr:])
+	return st, stop()
+}
+
+// rwLock is a readers/writer lock with a choice of policy whose waits end
+// when ctx does.
+type rwLock struct {
+	ctx    context.Context
+	policy Policy
+
+	mu             sync.Mutex
+	cond           sync.Cond
+	readers        int // active
+	writing        bool
+	waitingWriters int
+	next, serving  uint64 // FIFO tickets
+}
+
+func newRWLock(ctx context.Context, p Policy) *rwLock {
+	l := &rwLock{ctx: ctx, policy: p}
+	l.cond.L = &l.mu
+	context.AfterFunc(ctx, func() {
+		l.mu.Lock()
+		l.cond.Broadcast()
+		l.mu.Unlock()
+	})
+	return l
+}
+
+// lock admits a reader or writer, reporting false if ctx ended first.
+func (l *rwLock) lock(write bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ticket := l.next
+	l.next++
+	if write {
+		l.waitingWriters++
+		defer func() { l.waitingWriters-- }()
+	}
+	for !l.admits(write, ticket) {
+		if l.ctx.Err() != nil {
+			return false
+		}
+		l.cond.Wait()
+	}
+	if write {
+		l.writing = true
+	} else {
+		l.readers++
+	}
+	l.serving++
+	// The next ticket may be a reader that can join this one.
+	l.cond.Broadcast()
+	return true
+}
+
+func (l *rwLock) admits(write bool, ticket uint64) bool {
+	if l.writing || write && l.readers > 0 {
+		return false
+	}
+	switch l.policy {
+	case WritersFirst:
+		return write || l.waitingWriters == 0
+	case FIFO:
+		return ticket == l.serving
+	}
+	return true
+}
+
+func (l *rwLock) unlock(write bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if write {
+		l.writing = false
+	} else {
+		l.readers--
+	}
+	l.cond.Broadcast()
+}
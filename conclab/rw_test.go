@@ -0,0 +1,83 @@
+package conclab
+
+import "context"
+import "testing"
+import "time"
+
+func TestReadersWriters(t *testing.T) {
+	for _, p := range []Policy{ReadersFirst, WritersFirst, FIFO} {
+		rw := ReadersWriters{Readers: 4, Writers: 2, Ops: 50, Policy: p, Read: 10 * time.Microsecond, DeadlockTimeout: 5 * time.Second}
+		st, err := rw.Run(context.Background())
+		if err != nil {
+			t.Errorf("%v: %v", p, err)
+			continue
+		}
+		if st.Violations != 0 {
+			t.Errorf("%v: %d exclusion violations", p, st.Violations)
+		}
+		for i, n := range st.Reads {
+			if n != 50 {
+				t.Errorf("%v: reader %d read %d times, want 50", p, i, n)
+			}
+		}
+		for i, n := range st.Writes {
+			if n != 50 {
+				t.Errorf("%v: writer %d wrote %d times, want 50", p, i, n)
+			}
+		}
+		if st.MaxReaders < 1 || st.MaxReaders > 4 {
+			t.Errorf("%v: MaxReaders == %d", p, st.MaxReaders)
+		}
+	}
+}
+
+func TestRWLockFIFO(t *testing.T) {
+	l := newRWLock(context.Background(), FIFO)
+	l.lock(false)
+
+	// A writer queues behind the reader; a later reader must queue
+	// behind the writer rather than join the first.
+	order := make(chan string, 2)
+	go func() {
+		l.lock(true)
+		order <- "writer"
+		l.unlock(true)
+	}()
+	for {
+		l.mu.Lock()
+		queued := l.next == 2
+		l.mu.Unlock()
+		if queued {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	go func() {
+		l.lock(false)
+		order <- "reader"
+		l.unlock(false)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case who := <-order:
+		t.Fatalf("%s admitted while the first reader holds the lock", who)
+	default:
+	}
+	l.unlock(false)
+	if who := <-order; who != "writer" {
+		t.Errorf("%s admitted first, want writer", who)
+	}
+	<-order
+}
+
+func TestRWLockCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := newRWLock(ctx, ReadersFirst)
+	l.lock(true)
+	got := make(chan bool)
+	go func() { got <- l.lock(false) }()
+	cancel()
+	if <-got {
+		t.Error("lock() succeeded after cancellation")
+	}
+}
@@ -0,0 +1,35 @@
+package golib
+
+// If returns a if cond is true and b otherwise. Both are evaluated, so it
+// suits values rather than calls with side effects.
+func If[T any](cond bool, a, b T) T {
+	if cond {
+		return a
+	}
+	return b
+}
+
+// Coalesce returns the first of vals that is not the zero value, or the
+// zero value if all are. It fills in defaults:
+//
+//	port := Coalesce(cfg.Port, os.Getenv("PORT"), "8080")
+func Coalesce[T comparable](vals ...T) T {
+	var zero T
+	for _, v := range vals {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
+// CoalescePtr returns the first of ptrs that is not nil, or nil. Unlike
+// Coalesce it can tell a value set to zero from one not set.
+func CoalescePtr[T any](ptrs ...*T) *T {
+	for _, p := range ptrs {
+		if p != nil {
+			return p
+		}
+	}
+	return nil
+}
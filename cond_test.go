@@ -0,0 +1,32 @@
+package golib
+
+import "testing"
+
+func TestIf(t *testing.T) {
+	if got := If(true, "a", "b"); got != "a" {
+		t.Errorf("If(true) == %q", got)
+	}
+	if got := If(false, 1, 2); got != 2 {
+		t.Errorf("If(false) == %d", got)
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	if got := Coalesce("", "x", "y"); got != "x" {
+		t.Errorf("Coalesce == %q", got)
+	}
+	if got := Coalesce(0, 0); got != 0 {
+		t.Errorf("Coalesce of zeros == %d", got)
+	}
+	if got := Coalesce[int](); got != 0 {
+		t.Errorf("Coalesce() == %d", got)
+	}
+
+	zero, five := 0, 5
+	if got := CoalescePtr(nil, &zero, &five); got != &zero {
+		t.Errorf("CoalescePtr == %v", got)
+	}
+	if got := CoalescePtr[int](nil, nil); got != nil {
+		t.Errorf("CoalescePtr of nils == %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+// Package contracts checks HTTP handlers against declarative request and
+// response expectations, in process and without a network.
+//
+// A Contract pairs a Request with the Response it must produce. Response
+// bodies are compared as JSON: expected values are matched exactly unless
+// they are Matchers, which relax the comparison, as in
+//
+//	contracts.Run(t, handler, contracts.Contract{
+//		Name:    "create user",
+//		Request: contracts.Request{Method: "POST", Path: "/users", Body: map[string]any{"name": "Ann"}},
+//		Response: contracts.Response{
+//			Status: 201,
+//			Body:   contracts.Partial{"id": contracts.Number, "name": "Ann"},
+//		},
+//	})
+package contracts
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "fmt"
+import "io"
+import "net/http"
+import "net/http/httptest"
+import "strings"
+import "testing"
+
+// A Contract is a request and the response it must produce.
+type Contract struct {
+	Name     string
+	Request  Request
+	Response Response
+}
+
+// Request describes the request to send.
+type Request struct {
+	Method string // default GET
+	Path   string // may include a query string
+	Header map[string]string
+	// Body is sent as is if it is a string or []byte, and otherwise
+	// encoded as JSON with Content-Type application/json.
+	Body any
+}
+
+// Response describes the expected response. Zero fields are not checked.
+type Response struct {
+	Status int
+	// Header maps header names to their expected values, each either a
+	// string to match exactly or a Matcher.
+	Header map[string]any
+	// Body is the expected JSON body: a JSON-encodable value, a Matcher,
+	// or maps and slices containing Matchers.
+	Body any
+	// Contains, if set, must occur in the raw body, which need not be
+	// JSON.
+	Contains string
+}
+
+// Run checks each contract against h as a subtest of t.
+func Run(t *testing.T, h http.Handler, contracts ...Contract) {
+	t.Helper()
+	for _, c := range contracts {
+		name := c.Name
+		if name == "" {
+			name = strings.TrimSpace(c.Request.Method + " " + c.Request.Path)
+		}
+		t.Run(name, func(t *testing.T) {
+			for _, err := range Check(h, c) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Check sends c's request to h and returns every way in which the
+// response breaks the contract.
+func Check(h http.Handler, c Contract) []error {
+	req, err := c.Request.build()
+	if err != nil {
+		return []error{err}
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	resp := rec.Result()
+	body, _ := io.ReadAll(resp.Body)
+
+	var errs []error
+	want := c.Response
+	if want.Status != 0 && resp.StatusCode != want.Status {
+		errs = append(errs, fmt.Errorf("status: got %d, want %d", resp.StatusCode, want.Status))
+	}
+	for _, name := range sortedKeys(want.Header) {
+		w := want.Header[name]
+		vs, ok := resp.Header[http.CanonicalHeaderKey(name)]
+		var got any = strings.Join(vs, ", ")
+		if !ok {
+			got = missing
+		}
+		m, isMatcher := w.(Matcher)
+		if !isMatcher {
+			m = Equal(w)
+		}
+		if err := m.Match(got); err != nil {
+			errs = append(errs, fmt.Errorf("header %s: %w", name, err))
+		}
+	}
+	if want.Contains != "" && !bytes.Contains(body, []byte(want.Contains)) {
+		errs = append(errs, fmt.Errorf("body does not contain %q: %s", want.Contains, abbreviate(body)))
+	}
+	if want.Body != nil {
+		var got any
+		if err := json.Unmarshal(body, &got); err != nil {
+			errs = append(errs, fmt.Errorf("body is not JSON: %v: %s", err, abbreviate(body)))
+		} else {
+			errs = append(errs, match("$", want.Body, got)...)
+		}
+	}
+	return errs
+}
+
+func (r Request) build() (*http.Request, error) {
+	var body io.Reader
+	isJSON := false
+	switch b := r.Body.(type) {
+	case nil:
+	case string:
+		body = strings.NewReader(b)
+	case []byte:
+		body = bytes.NewReader(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			return nil, fmt.Errorf("contracts: request body: %w", err)
+		}
+		body, isJSON = bytes.NewReader(data), true
+	}
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req := httptest.NewRequest(method, r.Path, body)
+	if isJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range r.Header {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// match compares an expected value with a decoded JSON value, returning
+// an error for each mismatch, labelled with its JSON path.
+func match(path string, want, got any) []error {
+	switch w := want.(type) {
+	case pathMatcher:
+		return w.matchPath(path, got)
+	case Matcher:
+		if err := w.Match(got); err != nil {
+			return []error{fmt.Errorf("%s: %w", path, err)}
+		}
+		return nil
+	case map[string]any:
+		return matchObject(path, w, got, false)
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			return []error{fmt.Errorf("%s: got %s, want an array", path, describe(got))}
+		}
+		if len(g) != len(w) {
+			return []error{fmt.Errorf("%s: got %d elements, want %d", path, len(g), len(w))}
+		}
+		var errs []error
+		for i := range w {
+			errs = append(errs, match(fmt.Sprintf("%s[%d]", path, i), w[i], g[i])...)
+		}
+		return errs
+	}
+	if err := Equal(want).Match(got); err != nil {
+		return []error{fmt.Errorf("%s: %w", path, err)}
+	}
+	return nil
+}
+
+func matchObject(path string, want map[string]any, got any, partial bool) []error {
+	g, ok := got.(map[string]any)
+	if !ok {
+		return []error{fmt.Errorf("%s: got %s, want an object", path, describe(got))}
+	}
+	var errs []error
+	for _, k := range sortedKeys(want) {
+		v, ok := g[k]
+		if !ok {
+			v = missing
+		}
+		errs = append(errs, match(path+"."+k, want[k], v)...)
+	}
+	if !partial {
+		for _, k := range sortedKeys(g) {
+			if _, ok := want[k]; !ok {
+				errs = append(errs, fmt.Errorf("%s.%s: unexpected field", path, k))
+			}
+		}
+	}
+	return errs
+}
+
+// missing stands for an absent header or object field.
+var missing = &struct{}{}
+
+var errMissing = errors.New("missing")
+
+func abbreviate(b []byte) string {
+	const max = 200
+	if len(b) > max {
+		return string(b[:max]) + "…"
+	}
+	return string(b)
+}
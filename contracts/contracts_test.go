@@ -0,0 +1,94 @@
+package contracts
+
+import "encoding/json"
+import "io"
+import "net/http"
+import "strings"
+import "testing"
+
+// users is a small JSON API to check contracts against.
+var users = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/users":
+		var in struct{ Name string }
+		if r.Header.Get("Content-Type") != "application/json" || json.NewDecoder(r.Body).Decode(&in) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error": "bad request"}`)
+			return
+		}
+		w.Header().Set("Location", "/users/7")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]any{"id": 7, "name": in.Name, "created": "2024-05-01T12:00:00Z"})
+	case r.URL.Path == "/users":
+		io.WriteString(w, `[{"id": 1, "name": "Ann", "tags": []}, {"id": 2, "name": "Bob"}]`)
+	case r.URL.Path == "/text":
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "hello "+r.URL.Query().Get("name"))
+	default:
+		http.NotFound(w, r)
+	}
+})
+
+func TestRun(t *testing.T) {
+	Run(t, users,
+		Contract{
+			Name:    "create",
+			Request: Request{Method: "POST", Path: "/users", Body: map[string]any{"name": "Ann"}},
+			Response: Response{
+				Status: 201,
+				Header: map[string]any{"Location": "/users/7", "content-type": Regexp("^application/json")},
+				Body:   map[string]any{"id": 7, "name": "Ann", "created": Regexp(`^\d{4}-`)},
+			},
+		},
+		Contract{
+			Request:  Request{Path: "/users"},
+			Response: Response{Body: Each(Partial{"id": Number, "name": String, "tags": Optional(Array)})},
+		},
+		Contract{
+			Request:  Request{Method: "POST", Path: "/users", Body: "not json"},
+			Response: Response{Status: 400, Body: Partial{"error": Any}},
+		},
+		Contract{
+			Request:  Request{Path: "/text?name=you"},
+			Response: Response{Status: 200, Contains: "hello you"},
+		},
+	)
+}
+
+func TestCheckFailures(t *testing.T) {
+	c := Contract{
+		Request: Request{Path: "/users"},
+		Response: Response{
+			Status: 404,
+			Header: map[string]any{"X-Missing": Any, "Content-Type": "text/html"},
+			Body: []any{
+				map[string]any{"id": 1, "name": "Anne", "tags": Ignore},
+				map[string]any{"id": String, "age": 30},
+			},
+			Contains: "Carol",
+		},
+	}
+	var got []string
+	for _, err := range Check(users, c) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"status: got 200, want 404",
+		`header Content-Type: got "application/json", want "text/html"`,
+		"header X-Missing: missing",
+		`body does not contain "Carol": [{"id": 1, "name": "Ann", "tags": []}, {"id": 2, "name": "Bob"}]`,
+		`$[0].name: got "Ann", want "Anne"`,
+		"$[1].age: missing",
+		"$[1].id: got 2, want a string",
+		"$[1].name: unexpected field",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Check() errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	errs := Check(users, Contract{Request: Request{Path: "/text"}, Response: Response{Body: Any}})
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), "body is not JSON") {
+		t.Errorf("Check() of a text body == %v", errs)
+	}
+}
@@ -0,0 +1,185 @@
+package contracts
+
+import "encoding/json"
+import "errors"
+import "fmt"
+import "reflect"
+import "regexp"
+import "slices"
+
+// A Matcher checks a decoded JSON value: a string, float64, bool, nil,
+// []any or map[string]any. It returns nil if the value matches.
+type Matcher interface {
+	Match(got any) error
+}
+
+// MatcherFunc adapts a function to the Matcher interface.
+type MatcherFunc func(got any) error
+
+// Match calls f(got).
+func (f MatcherFunc) Match(got any) error { return f(got) }
+
+// Matchers for common cases.
+var (
+	// Any matches any value, but the field must be present.
+	Any Matcher = MatcherFunc(func(got any) error {
+		if got == missing {
+			return errMissing
+		}
+		return nil
+	})
+	// Ignore matches anything, including an absent field.
+	Ignore Matcher = ignore{}
+
+	String = Type("string")
+	Number = Type("number")
+	Bool   = Type("boolean")
+	Array  = Type("array")
+	Object = Type("object")
+	Null   = Type("null")
+)
+
+type ignore struct{}
+
+func (ignore) Match(any) error { return nil }
+
+// Equal matches values equal to want after a JSON round trip, so that
+// Equal(1) matches the decoded float64 1 and a struct matches its JSON
+// object form.
+func Equal(want any) Matcher {
+	return MatcherFunc(func(got any) error {
+		if got == missing {
+			return errMissing
+		}
+		norm := want
+		if data, err := json.Marshal(want); err == nil {
+			json.Unmarshal(data, &norm)
+		}
+		if !reflect.DeepEqual(norm, got) {
+			return fmt.Errorf("got %s, want %s", describe(got), describe(norm))
+		}
+		return nil
+	})
+}
+
+// Type matches values of the named JSON type: "string", "number",
+// "boolean", "array", "object" or "null".
+func Type(name string) Matcher {
+	return MatcherFunc(func(got any) error {
+		if got == missing {
+			return errMissing
+		}
+		if t := jsonType(got); t != name {
+			return fmt.Errorf("got %s, want a %s", describe(got), name)
+		}
+		return nil
+	})
+}
+
+// Regexp matches strings matching the regular expression pattern.
+func Regexp(pattern string) Matcher {
+	re := regexp.MustCompile(pattern)
+	return MatcherFunc(func(got any) error {
+		s, ok := got.(string)
+		if !ok {
+			return fmt.Errorf("got %s, want a string matching %s", describe(got), pattern)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("got %q, want a match for %s", s, pattern)
+		}
+		return nil
+	})
+}
+
+// pathMatcher is implemented by matchers that contain expectations of
+// their own, so that errors within them carry the full JSON path.
+type pathMatcher interface {
+	Matcher
+	matchPath(path string, got any) []error
+}
+
+// Partial matches objects containing at least the given fields; unlike a
+// plain map, other fields are allowed.
+type Partial map[string]any
+
+// Match implements Matcher.
+func (p Partial) Match(got any) error { return errors.Join(p.matchPath("$", got)...) }
+
+func (p Partial) matchPath(path string, got any) []error {
+	return matchObject(path, p, got, true)
+}
+
+// Each matches arrays whose every element matches want.
+func Each(want any) Matcher { return each{want} }
+
+type each struct{ want any }
+
+func (e each) Match(got any) error { return errors.Join(e.matchPath("$", got)...) }
+
+func (e each) matchPath(path string, got any) []error {
+	g, ok := got.([]any)
+	if !ok {
+		return []error{fmt.Errorf("%s: got %s, want an array", path, describe(got))}
+	}
+	var errs []error
+	for i, v := range g {
+		errs = append(errs, match(fmt.Sprintf("%s[%d]", path, i), e.want, v)...)
+	}
+	return errs
+}
+
+// Optional matches an absent field, or a present one matching want.
+func Optional(want any) Matcher { return optional{want} }
+
+type optional struct{ want any }
+
+func (o optional) Match(got any) error { return errors.Join(o.matchPath("$", got)...) }
+
+func (o optional) matchPath(path string, got any) []error {
+	if got == missing {
+		return nil
+	}
+	return match(path, o.want, got)
+}
+
+func jsonType(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// describe formats a decoded JSON value for an error message.
+func describe(v any) string {
+	if v == missing {
+		return "nothing"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if len(b) > 60 {
+		return jsonType(v) + " " + string(b[:57]) + "..."
+	}
+	return string(b)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
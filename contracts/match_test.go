@@ -0,0 +1,67 @@
+package contracts
+
+import "encoding/json"
+import "testing"
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestMatchers(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+	}
+	cases := []struct {
+		m    Matcher
+		json string
+		ok   bool
+	}{
+		{Any, `null`, true},
+		{Equal(1), `1`, true},
+		{Equal(1), `"1"`, false},
+		{Equal(point{3}), `{"x": 3}`, true},
+		{Equal([]string{"a"}), `["a"]`, true},
+		{String, `"s"`, true},
+		{String, `1`, false},
+		{Number, `1.5`, true},
+		{Bool, `false`, true},
+		{Null, `null`, true},
+		{Array, `{}`, false},
+		{Object, `{}`, true},
+		{Regexp(`^a+$`), `"aaa"`, true},
+		{Regexp(`^a+$`), `"ab"`, false},
+		{Regexp(`^a+$`), `7`, false},
+		{Partial{"a": 1}, `{"a": 1, "b": 2}`, true},
+		{Partial{"a": 1}, `{"b": 2}`, false},
+		{Partial{"a": Optional(String)}, `{}`, true},
+		{Partial{"a": Optional(String)}, `{"a": 1}`, false},
+		{Partial{"a": Ignore}, `{}`, true},
+		{Each(Number), `[1, 2, 3]`, true},
+		{Each(Number), `[1, "2"]`, false},
+		{Each(Number), `[]`, true},
+		{MatcherFunc(func(any) error { return nil }), `0`, true},
+	}
+	for _, c := range cases {
+		err := c.m.Match(decode(t, c.json))
+		if (err == nil) != c.ok {
+			t.Errorf("%#v.Match(%s) == %v, want ok %v", c.m, c.json, err, c.ok)
+		}
+	}
+}
+
+func TestNestedPaths(t *testing.T) {
+	want := Partial{"users": Each(Partial{"email": Regexp("@")})}
+	got := decode(t, `{"users": [{"email": "a@b"}, {"email": "nope"}]}`)
+	errs := match("$", want, got)
+	if len(errs) != 1 || errs[0].Error() != `$.users[1].email: got "nope", want a match for @` {
+		t.Errorf("errors == %v", errs)
+	}
+	if err := want.Match(got); err == nil || err.Error() != errs[0].Error() {
+		t.Errorf("Match() == %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+// Package convert streams tabular records between CSV, JSON, JSON Lines
+// and YAML.
+//
+// Records are read and written one at a time, so files larger than
+// memory convert in constant space. A record is an ordered list of fields,
+// so column order survives a round trip through formats, like JSON, whose
+// objects are unordered.
+//
+// YAML support covers what tabular data needs: a top-level sequence of
+// flat mappings with scalar values. Nested values are written in flow
+// style (as JSON, which YAML accepts).
+package convert
+
+import "errors"
+import "fmt"
+import "io"
+import "path/filepath"
+import "slices"
+import "strconv"
+import "strings"
+
+// Format is a record file format.
+type Format string
+
+const (
+	CSV   Format = "csv"
+	JSON  Format = "json"  // an array of objects
+	JSONL Format = "jsonl" // one object per line
+	YAML  Format = "yaml"  // a sequence of mappings
+)
+
+// ParseFormat parses a format name, accepting common aliases such as
+// "ndjson" and "yml".
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "csv":
+		return CSV, nil
+	case "json":
+		return JSON, nil
+	case "jsonl", "ndjson":
+		return JSONL, nil
+	case "yaml", "yml":
+		return YAML, nil
+	}
+	return "", fmt.Errorf("convert: unknown format %q", s)
+}
+
+// FormatFromFilename returns the format implied by name's extension.
+func FormatFromFilename(name string) (Format, error) {
+	return ParseFormat(filepath.Ext(name))
+}
+
+// Field is a named value in a record. Values are nil, bool, int64,
+// float64, string, or for nested JSON, []any and map[string]any.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Record is an ordered list of fields.
+type Record []Field
+
+// Get returns the value of the field named key.
+func (r Record) Get(key string) (any, bool) {
+	for _, f := range r {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Keys returns the field names in order.
+func (r Record) Keys() []string {
+	keys := make([]string, len(r))
+	for i, f := range r {
+		keys[i] = f.Key
+	}
+	return keys
+}
+
+// Reader reads records. Read returns io.EOF after the last one.
+type Reader interface {
+	Read() (Record, error)
+}
+
+// Writer writes records. Close finishes the output, for example writing a
+// closing bracket, but does not close the underlying io.Writer.
+type Writer interface {
+	Write(Record) error
+	Close() error
+}
+
+// Options control reading.
+type Options struct {
+	// Columns, if set, selects and orders the fields of every record.
+	// Fields a record lacks become null.
+	Columns []string
+
+	// Infer converts untyped text values, from CSV cells and plain YAML
+	// scalars, to numbers, booleans and null where they parse as such.
+	Infer bool
+
+	// Strings lists columns exempt from Infer, such as postcodes or IDs
+	// whose leading zeros matter.
+	Strings []string
+}
+
+// NewReader returns a Reader for records in format f.
+func NewReader(r io.Reader, f Format, opts Options) (Reader, error) {
+	var rd Reader
+	switch f {
+	case CSV:
+		rd = newCSVReader(r, opts)
+	case JSON:
+		rd = newJSONReader(r)
+	case JSONL:
+		rd = newJSONLReader(r)
+	case YAML:
+		rd = newYAMLReader(r, opts)
+	default:
+		return nil, fmt.Errorf("convert: unknown format %q", f)
+	}
+	if len(opts.Columns) > 0 {
+		rd = &selectReader{rd, opts.Columns}
+	}
+	return rd, nil
+}
+
+// NewWriter returns a Writer producing format f.
+func NewWriter(w io.Writer, f Format) (Writer, error) {
+	switch f {
+	case CSV:
+		return newCSVWriter(w), nil
+	case JSON:
+		return &jsonWriter{w: w}, nil
+	case JSONL:
+		return &jsonlWriter{w: w}, nil
+	case YAML:
+		return &yamlWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("convert: unknown format %q", f)
+}
+
+// Convert copies every record from src, in format from, to dst, in format
+// to, and returns the number of records copied.
+func Convert(dst io.Writer, to Format, src io.Reader, from Format, opts Options) (int, error) {
+	r, err := NewReader(src, from, opts)
+	if err != nil {
+		return 0, err
+	}
+	w, err := NewWriter(dst, to)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+		if err := w.Write(rec); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, w.Close()
+}
+
+type selectReader struct {
+	r       Reader
+	columns []string
+}
+
+func (s *selectReader) Read() (Record, error) {
+	rec, err := s.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	out := make(Record, len(s.columns))
+	for i, c := range s.columns {
+		v, _ := rec.Get(c)
+		out[i] = Field{c, v}
+	}
+	return out, nil
+}
+
+// infer converts s to nil, a bool, an int64 or a float64 if it reads as
+// one, and otherwise returns it unchanged.
+func infer(s string) any {
+	switch s {
+	case "", "null", "NULL", "~":
+		return nil
+	case "true", "TRUE", "True":
+		return true
+	case "false", "FALSE", "False":
+		return false
+	}
+	// Leave numbers with leading zeros, like "007" or "-007", as text.
+	u := s
+	if u[0] == '+' || u[0] == '-' {
+		u = u[1:]
+	}
+	if len(u) > 1 && u[0] == '0' && u[1] != '.' {
+		return s
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	// ParseFloat also accepts hex floats, "Inf" and "NaN"; keep those as
+	// text.
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !strings.ContainsAny(s, "xXpPiInN") {
+		return f
+	}
+	return s
+}
+
+// infer applies o.Infer to a text value in column.
+func (o *Options) infer(column, s string) any {
+	if !o.Infer || slices.Contains(o.Strings, column) {
+		return s
+	}
+	return infer(s)
+}
+
+// text formats a scalar value for text-only formats such as CSV.
+func text(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return string(mustJSON(v))
+}
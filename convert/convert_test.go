@@ -0,0 +1,123 @@
+package convert
+
+import "errors"
+import "io"
+import "reflect"
+import "strings"
+import "testing"
+
+var people = []Record{
+	{{"name", "Ann"}, {"age", int64(31)}, {"zip", "02139"}, {"admin", true}},
+	{{"name", "Bob, Jr."}, {"age", int64(4)}, {"zip", "10001"}, {"admin", false}},
+	{{"name", "Çelik: \"the\" builder"}, {"age", nil}, {"zip", "94103"}, {"admin", false}},
+}
+
+func writeAll(t *testing.T, f Format, recs []Record) string {
+	t.Helper()
+	var b strings.Builder
+	w, err := NewWriter(&b, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range recs {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func readAll(t *testing.T, f Format, s string, opts Options) []Record {
+	t.Helper()
+	r, err := NewReader(strings.NewReader(s), f, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recs []Record
+	for {
+		rec, err := r.Read()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("%s: %v", f, err)
+			}
+			return recs
+		}
+		recs = append(recs, rec)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	opts := Options{Infer: true, Strings: []string{"zip"}}
+	for _, f := range []Format{CSV, JSON, JSONL, YAML} {
+		out := writeAll(t, f, people)
+		got := readAll(t, f, out, opts)
+		if !reflect.DeepEqual(got, people) {
+			t.Errorf("%s round trip ==\n%v\nwant\n%v\nvia\n%s", f, got, people, out)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	in := "id,score\n1,9.5\n2,7\n"
+	var out strings.Builder
+	n, err := Convert(&out, JSONL, strings.NewReader(in), CSV, Options{Infer: true, Columns: []string{"score", "id", "missing"}})
+	if err != nil || n != 2 {
+		t.Fatalf("Convert() == %d, %v", n, err)
+	}
+	want := `{"score":9.5,"id":1,"missing":null}` + "\n" + `{"score":7,"id":2,"missing":null}` + "\n"
+	if out.String() != want {
+		t.Errorf("Convert() ==\n%s\nwant\n%s", out.String(), want)
+	}
+
+	if _, err := Convert(&out, "xml", strings.NewReader(in), CSV, Options{}); err == nil {
+		t.Error("Convert to xml succeeded")
+	}
+}
+
+func TestInfer(t *testing.T) {
+	cases := []struct {
+		in   string
+		want any
+	}{
+		{"", nil},
+		{"null", nil},
+		{"true", true},
+		{"False", false},
+		{"42", int64(42)},
+		{"-7", int64(-7)},
+		{"0", int64(0)},
+		{"0.5", 0.5},
+		{"1e3", 1000.0},
+		{"007", "007"},
+		{"-007", "-007"},
+		{"+007", "+007"},
+		{"-0.5", -0.5},
+		{"+3", int64(3)},
+		{"0x1F", "0x1F"},
+		{"Inf", "Inf"},
+		{"NaN", "NaN"},
+		{"12 monkeys", "12 monkeys"},
+	}
+	for _, c := range cases {
+		if got := infer(c.in); got != c.want {
+			t.Errorf("infer(%q) == %#v, want %#v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"CSV": CSV, ".json": JSON, "ndjson": JSONL, "yml": YAML} {
+		if got, err := ParseFormat(in); got != want || err != nil {
+			t.Errorf("ParseFormat(%q) == %q, %v", in, got, err)
+		}
+	}
+	if f, err := FormatFromFilename("data/people.yaml"); f != YAML || err != nil {
+		t.Errorf("FormatFromFilename() == %q, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+}
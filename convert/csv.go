@@ -0,0 +1,75 @@
+package convert
+
+import "encoding/csv"
+import "fmt"
+import "io"
+
+type csvReader struct {
+	r      *csv.Reader
+	opts   Options
+	header []string
+}
+
+func newCSVReader(r io.Reader, opts Options) *csvReader {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // short and long rows are padded or reported below
+	cr.ReuseRecord = true
+	return &csvReader{r: cr, opts: opts}
+}
+
+func (c *csvReader) Read() (Record, error) {
+	if c.header == nil {
+		h, err := c.r.Read()
+		if err != nil {
+			return nil, err
+		}
+		c.header = append([]string(nil), h...)
+	}
+	row, err := c.r.Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(row) > len(c.header) {
+		line, _ := c.r.FieldPos(0)
+		return nil, fmt.Errorf("convert: csv line %d: %d fields, header has %d", line, len(row), len(c.header))
+	}
+	rec := make(Record, len(c.header))
+	for i, k := range c.header {
+		var v any
+		if i < len(row) {
+			v = c.opts.infer(k, row[i])
+		}
+		rec[i] = Field{k, v}
+	}
+	return rec, nil
+}
+
+// csvWriter takes its columns from the first record; later records are
+// written in that column order, with missing fields left empty and extra
+// fields dropped.
+type csvWriter struct {
+	w      *csv.Writer
+	header []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter { return &csvWriter{w: csv.NewWriter(w)} }
+
+func (c *csvWriter) Write(rec Record) error {
+	if c.header == nil {
+		c.header = rec.Keys()
+		if err := c.w.Write(c.header); err != nil {
+			return err
+		}
+	}
+	row := make([]string, len(c.header))
+	for i, k := range c.header {
+		v, _ := rec.Get(k)
+		row[i] = text(v)
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
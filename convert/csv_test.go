@@ -0,0 +1,32 @@
+package convert
+
+import "reflect"
+import "testing"
+
+func TestCSVReader(t *testing.T) {
+	in := "a,b,c\n1,,x\n2\n"
+	got := readAll(t, CSV, in, Options{})
+	want := []Record{
+		{{"a", "1"}, {"b", ""}, {"c", "x"}},
+		{{"a", "2"}, {"b", nil}, {"c", nil}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read ==\n%v\nwant\n%v", got, want)
+	}
+
+	r, _ := NewReader(stringsReader("a\n1,2\n"), CSV, Options{})
+	if _, err := r.Read(); err == nil {
+		t.Error("row longer than header accepted")
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	got := writeAll(t, CSV, []Record{
+		{{"a", int64(1)}, {"b", []any{int64(1), "x"}}},
+		{{"b", 2.5}, {"extra", "dropped"}},
+	})
+	want := "a,b\n1,\"[1,\"\"x\"\"]\"\n,2.5\n"
+	if got != want {
+		t.Errorf("CSV ==\n%s\nwant\n%s", got, want)
+	}
+}
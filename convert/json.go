@@ -0,0 +1,185 @@
+package convert
+
+import "bufio"
+import "bytes"
+import "encoding/json"
+import "fmt"
+import "io"
+
+// jsonReader streams the elements of a top-level JSON array.
+type jsonReader struct {
+	d       *json.Decoder
+	started bool
+}
+
+func newJSONReader(r io.Reader) *jsonReader {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	return &jsonReader{d: d}
+}
+
+func (j *jsonReader) Read() (Record, error) {
+	if !j.started {
+		j.started = true
+		tok, err := j.d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if tok != json.Delim('[') {
+			return nil, fmt.Errorf("convert: json input is not an array")
+		}
+	}
+	if !j.d.More() {
+		if _, err := j.d.Token(); err != nil { // the closing ']'
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return readObject(j.d)
+}
+
+// jsonlReader reads one object per line, skipping blank lines.
+type jsonlReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newJSONLReader(r io.Reader) *jsonlReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 16<<20)
+	return &jsonlReader{s: s}
+}
+
+func (j *jsonlReader) Read() (Record, error) {
+	for j.s.Scan() {
+		j.line++
+		line := bytes.TrimSpace(j.s.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		d := json.NewDecoder(bytes.NewReader(line))
+		d.UseNumber()
+		rec, err := readObject(d)
+		if err != nil {
+			return nil, fmt.Errorf("convert: jsonl line %d: %w", j.line, err)
+		}
+		return rec, nil
+	}
+	if err := j.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// readObject decodes one JSON object, keeping its keys in order.
+func readObject(d *json.Decoder) (Record, error) {
+	tok, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("convert: expected a json object, got %v", tok)
+	}
+	var rec Record
+	for d.More() {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		if err := d.Decode(&v); err != nil {
+			return nil, err
+		}
+		rec = append(rec, Field{tok.(string), fromJSON(v)})
+	}
+	if _, err := d.Token(); err != nil { // the closing '}'
+		return nil, err
+	}
+	return rec, nil
+}
+
+// fromJSON converts json.Number to int64 or float64, recursively.
+func fromJSON(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = fromJSON(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = fromJSON(v[k])
+		}
+	}
+	return v
+}
+
+// appendObject appends rec as a compact JSON object.
+func appendObject(b []byte, rec Record) []byte {
+	b = append(b, '{')
+	for i, f := range rec {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, mustJSON(f.Key)...)
+		b = append(b, ':')
+		b = append(b, mustJSON(f.Value)...)
+	}
+	return append(b, '}')
+}
+
+// mustJSON marshals values that are always representable: the types a
+// Record holds. NaN and infinities, which JSON lacks, become null.
+func mustJSON(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte("null")
+	}
+	return b
+}
+
+type jsonWriter struct {
+	w   io.Writer
+	n   int
+	buf []byte
+}
+
+func (j *jsonWriter) Write(rec Record) error {
+	j.buf = j.buf[:0]
+	if j.n == 0 {
+		j.buf = append(j.buf, "[\n  "...)
+	} else {
+		j.buf = append(j.buf, ",\n  "...)
+	}
+	j.buf = appendObject(j.buf, rec)
+	j.n++
+	_, err := j.w.Write(j.buf)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	end := "\n]\n"
+	if j.n == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+type jsonlWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (j *jsonlWriter) Write(rec Record) error {
+	j.buf = append(appendObject(j.buf[:0], rec), '\n')
+	_, err := j.w.Write(j.buf)
+	return err
+}
+
+func (j *jsonlWriter) Close() error { return nil }
@@ -0,0 +1,50 @@
+package convert
+
+import "io"
+import "reflect"
+import "strings"
+import "testing"
+
+func stringsReader(s string) io.Reader { return strings.NewReader(s) }
+
+func TestJSONReader(t *testing.T) {
+	in := `[{"z": 1, "a": {"n": 2.5}, "list": [1, "two"]}, {}]`
+	got := readAll(t, JSON, in, Options{})
+	want := []Record{
+		{{"z", int64(1)}, {"a", map[string]any{"n": 2.5}}, {"list", []any{int64(1), "two"}}},
+		nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read ==\n%#v\nwant\n%#v", got, want)
+	}
+
+	for _, bad := range []string{`{"a": 1}`, `[1, 2]`, `[{"a": }]`} {
+		r, _ := NewReader(stringsReader(bad), JSON, Options{})
+		if _, err := r.Read(); err == nil {
+			t.Errorf("Read(%s) succeeded", bad)
+		}
+	}
+}
+
+func TestJSONLReader(t *testing.T) {
+	got := readAll(t, JSONL, "{\"a\":1}\n\n{\"a\":2}\n", Options{})
+	if len(got) != 2 {
+		t.Fatalf("read %d records", len(got))
+	}
+	r, _ := NewReader(stringsReader("{\"a\":1}\nnope\n"), JSONL, Options{})
+	r.Read()
+	if _, err := r.Read(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("bad line error == %v", err)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	if got := writeAll(t, JSON, nil); got != "[]\n" {
+		t.Errorf("empty JSON == %q", got)
+	}
+	got := writeAll(t, JSON, []Record{{{"b", int64(1)}, {"a", "x"}}, {{"c", nil}}})
+	want := "[\n  {\"b\":1,\"a\":\"x\"},\n  {\"c\":null}\n]\n"
+	if got != want {
+		t.Errorf("JSON ==\n%s\nwant\n%s", got, want)
+	}
+}
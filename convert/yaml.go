@@ -0,0 +1,273 @@
+package convert
+
+import "bufio"
+import "encoding/json"
+import "fmt"
+import "io"
+import "strconv"
+import "strings"
+
+// yamlReader reads a block sequence of flat block mappings:
+//
+//	---
+//	- name: Ann
+//	  age: 31
+//	- name: "Bob: the builder"
+//
+// Comments, blank lines and document markers are skipped. Values may be
+// plain, single- or double-quoted scalars, or JSON-style flow
+// collections.
+type yamlReader struct {
+	s    *bufio.Scanner
+	opts Options
+	line int
+	next Record // the record being collected
+	done bool
+	// closed is set when the current record was written "- {}".
+	closed bool
+}
+
+func newYAMLReader(r io.Reader, opts Options) *yamlReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 16<<20)
+	return &yamlReader{s: s, opts: opts}
+}
+
+func (y *yamlReader) Read() (Record, error) {
+	for !y.done && y.s.Scan() {
+		y.line++
+		raw := strings.TrimRight(y.s.Text(), " \t\r")
+		trimmed := strings.TrimSpace(raw)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#"):
+			continue
+		case trimmed == "---" || trimmed == "[]":
+			continue
+		case trimmed == "...":
+			y.done = true
+			continue
+		}
+
+		var entry string
+		start := false
+		switch {
+		case strings.HasPrefix(raw, "- ") || raw == "-":
+			entry, start = strings.TrimSpace(raw[1:]), true
+			// An empty record, as the writer writes it, takes no fields.
+			if y.closed = entry == "{}"; y.closed {
+				entry = ""
+			}
+		case strings.HasPrefix(raw, " ") && y.next != nil && !y.closed:
+			entry = trimmed
+		default:
+			return nil, y.errorf("expected a sequence item")
+		}
+
+		var rec Record
+		if start && y.next != nil {
+			rec = y.next
+			y.next = Record{}
+		} else if start {
+			y.next = Record{}
+		}
+		if entry != "" {
+			f, err := y.field(entry)
+			if err != nil {
+				return nil, err
+			}
+			y.next = append(y.next, f)
+		}
+		if rec != nil {
+			return rec, nil
+		}
+	}
+	if err := y.s.Err(); err != nil {
+		return nil, err
+	}
+	if y.next != nil {
+		rec := y.next
+		y.next = nil
+		return rec, nil
+	}
+	return nil, io.EOF
+}
+
+func (y *yamlReader) errorf(format string, args ...any) error {
+	return fmt.Errorf("convert: yaml line %d: %s", y.line, fmt.Sprintf(format, args...))
+}
+
+// field parses "key: value".
+func (y *yamlReader) field(s string) (Field, error) {
+	var key string
+	if s[0] == '"' || s[0] == '\'' {
+		k, rest, err := y.quoted(s)
+		if err != nil {
+			return Field{}, err
+		}
+		if !strings.HasPrefix(rest, ":") {
+			return Field{}, y.errorf("expected ':' after key")
+		}
+		key, s = k, rest[1:]
+	} else {
+		i := strings.Index(s+" ", ": ")
+		if i < 0 {
+			return Field{}, y.errorf("expected key: value")
+		}
+		key, s = strings.TrimSpace(s[:i]), s[i+1:]
+	}
+	s = strings.TrimSpace(s)
+
+	switch {
+	case s == "":
+		return Field{key, nil}, nil
+	case s[0] == '"' || s[0] == '\'':
+		v, rest, err := y.quoted(s)
+		if err != nil {
+			return Field{}, err
+		}
+		if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+			return Field{}, y.errorf("unexpected text after quoted value")
+		}
+		return Field{key, v}, nil
+	case s[0] == '[' || s[0] == '{':
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return Field{}, y.errorf("flow collections must be JSON: %v", err)
+		}
+		return Field{key, fromJSON(v)}, nil
+	}
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	return Field{key, y.opts.infer(key, s)}, nil
+}
+
+// quoted parses a quoted scalar at the start of s and returns the rest.
+func (y *yamlReader) quoted(s string) (string, string, error) {
+	if s[0] == '\'' {
+		var b strings.Builder
+		for i := 1; i < len(s); i++ {
+			if s[i] == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+					continue
+				}
+				return b.String(), s[i+1:], nil
+			}
+			b.WriteByte(s[i])
+		}
+		return "", "", y.errorf("unterminated quoted string")
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			v, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", y.errorf("bad escape in %s", s[:i+1])
+			}
+			return v, s[i+1:], nil
+		}
+	}
+	return "", "", y.errorf("unterminated quoted string")
+}
+
+type yamlWriter struct {
+	w io.Writer
+	n int
+	b strings.Builder
+}
+
+func (y *yamlWriter) Write(rec Record) error {
+	y.b.Reset()
+	if len(rec) == 0 {
+		y.b.WriteString("- {}\n")
+	}
+	for i, f := range rec {
+		if i == 0 {
+			y.b.WriteString("- ")
+		} else {
+			y.b.WriteString("  ")
+		}
+		y.b.WriteString(yamlKey(f.Key))
+		y.b.WriteString(":")
+		if v := yamlValue(f.Value); v != "" {
+			y.b.WriteByte(' ')
+			y.b.WriteString(v)
+		}
+		y.b.WriteByte('\n')
+	}
+	y.n++
+	_, err := io.WriteString(y.w, y.b.String())
+	return err
+}
+
+func (y *yamlWriter) Close() error {
+	if y.n == 0 {
+		_, err := io.WriteString(y.w, "[]\n")
+		return err
+	}
+	return nil
+}
+
+func yamlKey(k string) string {
+	if needsQuotes(k) || ambiguous(k) || strings.Contains(k, ":") {
+		return strconv.Quote(k)
+	}
+	return k
+}
+
+func yamlValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if needsQuotes(v) || ambiguous(v) {
+			return strconv.Quote(v)
+		}
+		return v
+	case bool, int64, float64:
+		return text(v)
+	}
+	return string(mustJSON(v))
+}
+
+// ambiguous reports whether a YAML parser, possibly one following the
+// YAML 1.1 rules, might read the plain scalar s as something other than a
+// string: "02139", "1e3", "yes" and "~" all need quoting.
+func ambiguous(s string) bool {
+	if infer(s) != any(s) {
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "y", "n", "yes", "no", "on", "off", ".inf", "-.inf", ".nan":
+		return true
+	}
+	return false
+}
+
+// needsQuotes reports whether s cannot be written as a plain scalar.
+func needsQuotes(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`~") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
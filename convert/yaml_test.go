@@ -0,0 +1,75 @@
+package convert
+
+import "reflect"
+import "testing"
+
+func TestYAMLReader(t *testing.T) {
+	in := `# people
+---
+- name: Ann   # inline comment
+  title: 'It''s me'
+  tags: ["a", 1]
+  empty:
+  "quoted: key": "line\nbreak"
+-
+  name: Bob
+- url: http://example.com:8080/x
+...
+- ignored: after end marker
+`
+	got := readAll(t, YAML, in, Options{Infer: true})
+	want := []Record{
+		{{"name", "Ann"}, {"title", "It's me"}, {"tags", []any{"a", int64(1)}}, {"empty", nil}, {"quoted: key", "line\nbreak"}},
+		{{"name", "Bob"}},
+		{{"url", "http://example.com:8080/x"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read ==\n%#v\nwant\n%#v", got, want)
+	}
+
+	for _, bad := range []string{"name: top-level map\n", "- a: 'open\n", "- a: \"x\" y\n", "- novalue\n"} {
+		r, _ := NewReader(stringsReader(bad), YAML, Options{})
+		if _, err := r.Read(); err == nil {
+			t.Errorf("Read(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestYAMLWriter(t *testing.T) {
+	got := writeAll(t, YAML, []Record{
+		{{"s", "plain text"}, {"n", "42"}, {"b", "true"}, {"lead", "- dash"}, {"colon", "a: b"}, {"zip", "02139"}, {"yes", "yes"}},
+		{{"nested", map[string]any{"k": int64(1)}}, {"null", nil}, {"f", 1.5}},
+	})
+	want := `- s: plain text
+  "n": "42"
+  b: "true"
+  lead: "- dash"
+  colon: "a: b"
+  zip: "02139"
+  "yes": "yes"
+- nested: {"k":1}
+  "null": null
+  f: 1.5
+`
+	if got != want {
+		t.Errorf("YAML ==\n%s\nwant\n%s", got, want)
+	}
+	if got := writeAll(t, YAML, nil); got != "[]\n" {
+		t.Errorf("empty YAML == %q", got)
+	}
+}
+
+func TestYAMLEmptyRecord(t *testing.T) {
+	recs := []Record{{{"a", "1"}}, {}, {{"b", "2"}}, {}}
+	out := writeAll(t, YAML, recs)
+	if want := "- a: \"1\"\n- {}\n- b: \"2\"\n- {}\n"; out != want {
+		t.Fatalf("YAML ==\n%s\nwant\n%s", out, want)
+	}
+	if got := readAll(t, YAML, out, Options{}); !reflect.DeepEqual(got, recs) {
+		t.Errorf("read back ==\n%#v\nwant\n%#v", got, recs)
+	}
+	r, _ := NewReader(stringsReader("- {}\n  a: 1\n"), YAML, Options{})
+	if _, err := r.Read(); err == nil {
+		t.Error("field after {} accepted")
+	}
+}
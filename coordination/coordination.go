@@ -0,0 +1,42 @@
+// Package coordination defines locks and leader elections that code can
+// be written against without caring where they are implemented.
+//
+// Memory provides both within a single process, for tests and for
+// learning how the primitives behave. FileLock and FileElection use
+// advisory file locks, so that separate processes on one machine can
+// coordinate through a shared file.
+package coordination
+
+import "context"
+import "errors"
+
+// ErrNotLocked is returned by Unlock when the lock is not held.
+var ErrNotLocked = errors.New("coordination: not locked")
+
+// ErrNoLeader is returned by Election.Leader when no candidate leads.
+var ErrNoLeader = errors.New("coordination: no leader")
+
+// A Lock is a mutual exclusion lock that may be shared beyond a single
+// goroutine: between processes, or between independent components.
+type Lock interface {
+	// Lock waits until the lock is acquired or ctx is done.
+	Lock(ctx context.Context) error
+	// TryLock acquires the lock if it is free and reports whether it did.
+	TryLock() (bool, error)
+	// Unlock releases the lock.
+	Unlock() error
+}
+
+// An Election chooses one leader among candidates. Each candidate holds
+// its own Election value.
+type Election interface {
+	// Campaign waits until this candidate is the leader or ctx is done.
+	Campaign(ctx context.Context) error
+	// Resign gives up leadership, letting another candidate win. It is
+	// a no-op for a candidate that is not the leader.
+	Resign() error
+	// Leader returns the ID of the current leader, or ErrNoLeader.
+	Leader() (string, error)
+	// IsLeader reports whether this candidate is the leader.
+	IsLeader() bool
+}
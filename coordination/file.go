@@ -0,0 +1,167 @@
+package coordination
+
+import "context"
+import "errors"
+import "io"
+import "os"
+import "strings"
+import "sync"
+import "time"
+
+// pollInterval is how often FileLock.Lock retries a held lock.
+const pollInterval = 10 * time.Millisecond
+
+// FileLock is a Lock held as an exclusive advisory lock on a file, which
+// is created if needed and left in place afterwards. Separate FileLocks
+// for the same path exclude each other, whether in one process or many.
+// The lock is released if the process exits.
+type FileLock struct {
+	path string
+
+	mu sync.Mutex
+	f  *os.File // open while locked
+}
+
+// NewFileLock returns a lock on the file at path.
+func NewFileLock(path string) *FileLock {
+	return &FileLock{path: path}
+}
+
+func (l *FileLock) Lock(ctx context.Context) error {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		ok, err := l.TryLock()
+		if ok || err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (l *FileLock) TryLock() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f != nil {
+		return false, nil
+	}
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return false, err
+	}
+	ok, err := lockFile(f, true)
+	if !ok || err != nil {
+		f.Close()
+		return false, err
+	}
+	l.f = f
+	return true, nil
+}
+
+func (l *FileLock) Unlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return ErrNotLocked
+	}
+	err := unlockFile(l.f)
+	if cerr := l.f.Close(); err == nil {
+		err = cerr
+	}
+	l.f = nil
+	return err
+}
+
+// FileElection is a candidate in an election held through a file: the
+// leader holds a FileLock on it and writes its ID there.
+type FileElection struct {
+	lock *FileLock
+	id   string
+}
+
+// NewFileElection returns the candidate id in the election held through
+// the file at path.
+func NewFileElection(path, id string) *FileElection {
+	return &FileElection{lock: NewFileLock(path), id: id}
+}
+
+func (e *FileElection) Campaign(ctx context.Context) error {
+	if e.IsLeader() {
+		return nil
+	}
+	if err := e.lock.Lock(ctx); err != nil {
+		return err
+	}
+	if err := e.record(e.id); err != nil {
+		e.lock.Unlock()
+		return err
+	}
+	return nil
+}
+
+// record replaces the contents of the locked file with s.
+func (e *FileElection) record(s string) error {
+	e.lock.mu.Lock()
+	defer e.lock.mu.Unlock()
+	f := e.lock.f
+	if err := f.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := f.WriteAt([]byte(s), 0); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+func (e *FileElection) Resign() error {
+	if !e.IsLeader() {
+		return nil
+	}
+	if err := e.record(""); err != nil {
+		e.lock.Unlock()
+		return err
+	}
+	return e.lock.Unlock()
+}
+
+// Leader returns the ID written by the candidate holding the lock. An ID
+// left behind by a leader that exited without resigning is ignored,
+// since its lock was released with it.
+func (e *FileElection) Leader() (string, error) {
+	f, err := os.Open(e.lock.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNoLeader
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	// A shared lock is granted only if nobody holds the exclusive one.
+	free, err := lockFile(f, false)
+	if err != nil {
+		return "", err
+	}
+	if free {
+		unlockFile(f)
+		return "", ErrNoLeader
+	}
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	id := strings.TrimSpace(string(b))
+	if id == "" {
+		return "", ErrNoLeader
+	}
+	return id, nil
+}
+
+func (e *FileElection) IsLeader() bool {
+	e.lock.mu.Lock()
+	defer e.lock.mu.Unlock()
+	return e.lock.f != nil
+}
@@ -0,0 +1,31 @@
+package coordination
+
+import "os"
+import "path/filepath"
+import "testing"
+
+var (
+	_ Lock     = (*FileLock)(nil)
+	_ Election = (*FileElection)(nil)
+)
+
+func TestFileLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lock")
+	testLock(t, NewFileLock(path), NewFileLock(path))
+}
+
+func TestFileElection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leader")
+	testElection(t, NewFileElection(path, "a"), NewFileElection(path, "b"))
+}
+
+func TestFileElectionStaleID(t *testing.T) {
+	// An ID left in the file by a leader that died is not a leader.
+	path := filepath.Join(t.TempDir(), "leader")
+	if err := os.WriteFile(path, []byte("ghost"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileElection(path, "a").Leader(); err != ErrNoLeader {
+		t.Errorf("Leader() with stale ID: err == %v, want ErrNoLeader", err)
+	}
+}
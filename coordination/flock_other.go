@@ -0,0 +1,12 @@
+//go:build !unix
+
+package coordination
+
+import "errors"
+import "os"
+
+var errUnsupported = errors.New("coordination: file locks are not supported on this platform")
+
+func lockFile(f *os.File, exclusive bool) (bool, error) { return false, errUnsupported }
+
+func unlockFile(f *os.File) error { return errUnsupported }
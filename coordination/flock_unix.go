@@ -0,0 +1,25 @@
+//go:build unix
+
+package coordination
+
+import "errors"
+import "os"
+import "syscall"
+
+// lockFile takes an exclusive or shared flock on f without waiting and
+// reports whether it got it.
+func lockFile(f *os.File, exclusive bool) (bool, error) {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	err := syscall.Flock(int(f.Fd()), how|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
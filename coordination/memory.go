@@ -0,0 +1,132 @@
+package coordination
+
+import "context"
+import "sync"
+import "sync/atomic"
+
+// Memory holds named locks and elections for a single process. The zero
+// value is ready to use.
+type Memory struct {
+	mu        sync.Mutex
+	locks     map[string]*MemoryLock
+	elections map[string]*memoryElection
+}
+
+// Lock returns the lock with the given name, creating it if needed. Every
+// call with the same name returns the same lock.
+func (m *Memory) Lock(name string) *MemoryLock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks == nil {
+		m.locks = map[string]*MemoryLock{}
+	}
+	l, ok := m.locks[name]
+	if !ok {
+		l = NewMemoryLock()
+		m.locks[name] = l
+	}
+	return l
+}
+
+// Election returns the candidate id in the named election.
+func (m *Memory) Election(name, id string) *MemoryElection {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.elections == nil {
+		m.elections = map[string]*memoryElection{}
+	}
+	e, ok := m.elections[name]
+	if !ok {
+		e = &memoryElection{lock: NewMemoryLock()}
+		m.elections[name] = e
+	}
+	return &MemoryElection{e: e, id: id}
+}
+
+// MemoryLock is a Lock held in memory. Unlike sync.Mutex, waiting for it
+// can be cancelled.
+type MemoryLock struct {
+	c chan struct{}
+}
+
+// NewMemoryLock returns an unlocked MemoryLock.
+func NewMemoryLock() *MemoryLock {
+	return &MemoryLock{c: make(chan struct{}, 1)}
+}
+
+func (l *MemoryLock) Lock(ctx context.Context) error {
+	select {
+	case l.c <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *MemoryLock) TryLock() (bool, error) {
+	select {
+	case l.c <- struct{}{}:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+func (l *MemoryLock) Unlock() error {
+	select {
+	case <-l.c:
+		return nil
+	default:
+		return ErrNotLocked
+	}
+}
+
+type memoryElection struct {
+	lock   *MemoryLock
+	mu     sync.Mutex
+	leader string
+}
+
+// MemoryElection is one candidate in an election held by Memory. A
+// candidate should campaign from one goroutine at a time; its other
+// methods may be called from any.
+type MemoryElection struct {
+	e       *memoryElection
+	id      string
+	leading atomic.Bool
+}
+
+func (c *MemoryElection) Campaign(ctx context.Context) error {
+	if c.leading.Load() {
+		return nil
+	}
+	if err := c.e.lock.Lock(ctx); err != nil {
+		return err
+	}
+	c.e.mu.Lock()
+	c.e.leader = c.id
+	c.e.mu.Unlock()
+	c.leading.Store(true)
+	return nil
+}
+
+func (c *MemoryElection) Resign() error {
+	if !c.leading.CompareAndSwap(true, false) {
+		return nil
+	}
+	c.e.mu.Lock()
+	c.e.leader = ""
+	c.e.mu.Unlock()
+	return c.e.lock.Unlock()
+}
+
+func (c *MemoryElection) Leader() (string, error) {
+	c.e.mu.Lock()
+	defer c.e.mu.Unlock()
+	if c.e.leader == "" {
+		return "", ErrNoLeader
+	}
+	return c.e.leader, nil
+}
+
+func (c *MemoryElection) IsLeader() bool { return c.leading.Load() }
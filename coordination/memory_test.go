@@ -0,0 +1,111 @@
+package coordination
+
+import "context"
+import "testing"
+import "time"
+
+var (
+	_ Lock     = (*MemoryLock)(nil)
+	_ Election = (*MemoryElection)(nil)
+)
+
+// testLock checks the Lock contract for two handles on the same lock.
+func testLock(t *testing.T, a, b Lock) {
+	t.Helper()
+	if ok, err := a.TryLock(); !ok || err != nil {
+		t.Fatalf("TryLock on free lock == %v, %v", ok, err)
+	}
+	if ok, err := b.TryLock(); ok || err != nil {
+		t.Errorf("TryLock on held lock == %v, %v, want false", ok, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if err := b.Lock(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Lock on held lock: err == %v, want DeadlineExceeded", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() { acquired <- b.Lock(context.Background()) }()
+	time.Sleep(20 * time.Millisecond)
+	if err := a.Unlock(); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Errorf("Lock after release: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Lock not acquired after release")
+	}
+	if err := b.Unlock(); err != nil {
+		t.Errorf("Unlock: %v", err)
+	}
+	if err := b.Unlock(); err != ErrNotLocked {
+		t.Errorf("second Unlock: err == %v, want ErrNotLocked", err)
+	}
+}
+
+// testElection checks the Election contract for two candidates.
+func testElection(t *testing.T, a, b Election) {
+	t.Helper()
+	if _, err := a.Leader(); err != ErrNoLeader {
+		t.Errorf("Leader before campaigning: err == %v, want ErrNoLeader", err)
+	}
+	ctx := context.Background()
+	if err := a.Campaign(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Campaign(ctx); err != nil {
+		t.Errorf("Campaign by the leader: %v", err)
+	}
+	if id, err := b.Leader(); id != "a" || err != nil {
+		t.Errorf("Leader() == %q, %v, want \"a\"", id, err)
+	}
+	if !a.IsLeader() || b.IsLeader() {
+		t.Errorf("IsLeader() == %v, %v, want true, false", a.IsLeader(), b.IsLeader())
+	}
+
+	won := make(chan error, 1)
+	go func() { won <- b.Campaign(ctx) }()
+	select {
+	case <-won:
+		t.Fatal("second candidate won while the first leads")
+	case <-time.After(20 * time.Millisecond):
+	}
+	if err := b.Resign(); err != nil {
+		t.Errorf("Resign by a follower: %v", err)
+	}
+	if err := a.Resign(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-won:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second candidate did not win after the leader resigned")
+	}
+	if id, err := a.Leader(); id != "b" || err != nil {
+		t.Errorf("Leader() == %q, %v, want \"b\"", id, err)
+	}
+	b.Resign()
+}
+
+func TestMemoryLock(t *testing.T) {
+	var m Memory
+	if m.Lock("x") != m.Lock("x") || m.Lock("x") == m.Lock("y") {
+		t.Error("Lock does not return one lock per name")
+	}
+	testLock(t, m.Lock("x"), m.Lock("x"))
+}
+
+func TestMemoryElection(t *testing.T) {
+	var m Memory
+	testElection(t, m.Election("e", "a"), m.Election("e", "b"))
+	if _, err := m.Election("other", "c").Leader(); err != ErrNoLeader {
+		t.Errorf("unrelated election has a leader")
+	}
+}
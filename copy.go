@@ -0,0 +1,116 @@
+package golib
+
+import "context"
+import "errors"
+import "io"
+import "time"
+
+// CopyOptions configure CopyContext and CopyNContext. The zero value
+// copies as fast as possible without reporting progress.
+type CopyOptions struct {
+	// Limiter paces the copy at one token per byte, waiting for each
+	// chunk's tokens before writing it. A *ratelimit.Limiter will do,
+	// provided its burst is at least BufferSize.
+	Limiter interface {
+		WaitN(ctx context.Context, n int) error
+	}
+	// Progress is called after each chunk is written with the number of
+	// bytes copied so far.
+	Progress func(written int64)
+	// BufferSize is the most bytes read and written at once. Zero means
+	// 32 KiB.
+	BufferSize int
+}
+
+// CopyContext copies from src to dst until EOF, an error, or ctx is done,
+// and returns the number of bytes copied. Like io.Copy, reaching EOF is
+// not an error.
+//
+// ctx is checked between chunks. If src or dst has a SetReadDeadline or
+// SetWriteDeadline method, as net.Conn and os.File do, a read or write
+// blocked when ctx ends is interrupted by setting its deadline to the
+// past, and the deadline is left that way. In every case the error is
+// then ctx's.
+func CopyContext(ctx context.Context, dst io.Writer, src io.Reader, opts CopyOptions) (int64, error) {
+	return copyContext(ctx, dst, src, -1, opts)
+}
+
+// CopyNContext is like CopyContext but copies at most n bytes. As with
+// io.CopyN, the error is io.EOF if src ends before n bytes are copied.
+func CopyNContext(ctx context.Context, dst io.Writer, src io.Reader, n int64, opts CopyOptions) (int64, error) {
+	written, err := copyContext(ctx, dst, src, n, opts)
+	if err == nil && written < n {
+		err = io.EOF
+	}
+	return written, err
+}
+
+// copyContext copies up to limit bytes, or until EOF if limit < 0.
+func copyContext(ctx context.Context, dst io.Writer, src io.Reader, limit int64, opts CopyOptions) (written int64, err error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		past := time.Unix(1, 0)
+		if d, ok := src.(interface{ SetReadDeadline(time.Time) error }); ok {
+			d.SetReadDeadline(past)
+		}
+		if d, ok := dst.(interface{ SetWriteDeadline(time.Time) error }); ok {
+			d.SetWriteDeadline(past)
+		}
+	})
+	defer stop()
+	// Whatever went wrong, a done context is the reason to report.
+	defer func() {
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		}
+	}()
+
+	size := opts.BufferSize
+	if size <= 0 {
+		size = 32 << 10
+	}
+	if limit >= 0 && limit < int64(size) {
+		size = max(int(limit), 1)
+	}
+	buf := make([]byte, size)
+	for limit < 0 || written < limit {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		p := buf
+		if limit >= 0 && limit-written < int64(len(p)) {
+			p = p[:limit-written]
+		}
+		nr, rerr := src.Read(p)
+		if nr > 0 {
+			if opts.Limiter != nil {
+				if err := opts.Limiter.WaitN(ctx, nr); err != nil {
+					return written, err
+				}
+			}
+			nw, werr := dst.Write(p[:nr])
+			if nw < 0 || nw > nr {
+				nw, werr = 0, errors.New("golib: invalid write result")
+			}
+			written += int64(nw)
+			if opts.Progress != nil && nw > 0 {
+				opts.Progress(written)
+			}
+			if werr != nil {
+				return written, werr
+			}
+			if nw != nr {
+				return written, io.ErrShortWrite
+			}
+		}
+		if rerr == io.EOF {
+			return written, nil
+		}
+		if rerr != nil {
+			return written, rerr
+		}
+	}
+	return written, nil
+}
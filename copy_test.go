@@ -0,0 +1,67 @@
+package golib
+
+import "bytes"
+import "context"
+import "io"
+import "net"
+import "strings"
+import "testing"
+import "time"
+
+// countingLimiter records the tokens it is asked for.
+type countingLimiter struct{ tokens []int }
+
+func (l *countingLimiter) WaitN(ctx context.Context, n int) error {
+	l.tokens = append(l.tokens, n)
+	return ctx.Err()
+}
+
+func TestCopyContext(t *testing.T) {
+	var dst bytes.Buffer
+	var progress []int64
+	lim := &countingLimiter{}
+	opts := CopyOptions{Limiter: lim, Progress: func(n int64) { progress = append(progress, n) }, BufferSize: 4}
+	n, err := CopyContext(context.Background(), &dst, strings.NewReader("hello, world"), opts)
+	if n != 12 || err != nil || dst.String() != "hello, world" {
+		t.Fatalf("CopyContext == %d, %v, copied %q", n, err, dst.String())
+	}
+	if len(progress) != 3 || progress[2] != 12 {
+		t.Errorf("progress == %v, want [4 8 12]", progress)
+	}
+	if len(lim.tokens) != 3 || lim.tokens[0] != 4 {
+		t.Errorf("limiter asked for %v, want [4 4 4]", lim.tokens)
+	}
+}
+
+func TestCopyNContext(t *testing.T) {
+	var dst bytes.Buffer
+	n, err := CopyNContext(context.Background(), &dst, strings.NewReader("hello, world"), 5, CopyOptions{})
+	if n != 5 || err != nil || dst.String() != "hello" {
+		t.Errorf("CopyNContext(5) == %d, %v, copied %q", n, err, dst.String())
+	}
+	dst.Reset()
+	n, err = CopyNContext(context.Background(), &dst, strings.NewReader("hi"), 5, CopyOptions{})
+	if n != 2 || err != io.EOF {
+		t.Errorf("CopyNContext past EOF == %d, %v, want 2, EOF", n, err)
+	}
+}
+
+func TestCopyContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := CopyContext(ctx, io.Discard, strings.NewReader("x"), CopyOptions{}); err != context.Canceled {
+		t.Errorf("CopyContext with done context: %v", err)
+	}
+
+	// A read blocked on a connection is interrupted by the deadline.
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	go server.Write([]byte("partial"))
+	n, err := CopyContext(ctx, io.Discard, client, CopyOptions{})
+	if n != 7 || err != context.DeadlineExceeded {
+		t.Errorf("CopyContext from stalled conn == %d, %v, want 7, DeadlineExceeded", n, err)
+	}
+}
@@ -0,0 +1,122 @@
+// Package cryptox wraps AES-256-GCM so that the safe choices are the only
+// ones: nonces are random and travel with the ciphertext, every message
+// is authenticated, and keys made from passphrases go through a slow key
+// derivation function with a random salt.
+//
+// The output of Encrypt is
+//
+//	nonce (12 bytes) || ciphertext || tag (16 bytes)
+//
+// Random nonces are safe for about 2^32 messages under one key. For more,
+// or for data too large for memory, use the streams, which derive a fresh
+// key for each stream.
+package cryptox
+
+import "crypto/aes"
+import "crypto/cipher"
+import "crypto/pbkdf2"
+import "crypto/rand"
+import "crypto/sha256"
+import "errors"
+import "fmt"
+
+// KeySize is the size of keys, in bytes: AES-256.
+const KeySize = 32
+
+// SaltSize is the size of the salts made by NewSalt.
+const SaltSize = 16
+
+// Iterations is the PBKDF2-HMAC-SHA256 work factor of DeriveKey, as
+// recommended by OWASP in 2023.
+const Iterations = 600_000
+
+// ErrDecrypt is returned when ciphertext is too short, was made with
+// another key or has been altered. It says nothing more, by design.
+var ErrDecrypt = errors.New("cryptox: message authentication failed")
+
+// NewKey returns a random key.
+func NewKey() []byte { return random(KeySize) }
+
+// NewSalt returns a random salt for DeriveKey.
+func NewSalt() []byte { return random(SaltSize) }
+
+func random(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b) // never fails
+	return b
+}
+
+// DeriveKey derives a key from passphrase and salt with PBKDF2-HMAC-SHA256
+// at Iterations rounds. The same passphrase and salt always give the same
+// key; use a fresh salt, from NewSalt, for each key and store it with the
+// ciphertext.
+//
+// scrypt and Argon2 resist attack by GPUs better, since they need memory
+// as well as time, but they live in golang.org/x/crypto and golib uses
+// only the standard library. See Iterations for the work factor.
+func DeriveKey(passphrase string, salt []byte) []byte {
+	k, err := pbkdf2.Key(sha256.New, passphrase, salt, Iterations, KeySize)
+	if err != nil {
+		panic(err) // only for FIPS-only mode, which KeySize satisfies
+	}
+	return k
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("cryptox: key is %d bytes, want %d", len(key), KeySize)
+	}
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("cryptox: %w", err)
+	}
+	return cipher.NewGCM(b)
+}
+
+// Encrypt encrypts and authenticates plaintext under key, which must be
+// KeySize bytes.
+func Encrypt(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := random(gcm.NonceSize())
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt reverses Encrypt.
+func Decrypt(key, ciphertext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	n := gcm.NonceSize()
+	if len(ciphertext) < n+gcm.Overhead() {
+		return nil, ErrDecrypt
+	}
+	p, err := gcm.Open(nil, ciphertext[:n], ciphertext[n:], nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return p, nil
+}
+
+// EncryptWithPassphrase encrypts plaintext under a key derived from
+// passphrase, with the salt stored before the ciphertext.
+func EncryptWithPassphrase(passphrase string, plaintext []byte) ([]byte, error) {
+	salt := NewSalt()
+	c, err := Encrypt(DeriveKey(passphrase, salt), plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append(salt, c...), nil
+}
+
+// DecryptWithPassphrase reverses EncryptWithPassphrase. A wrong passphrase
+// gives ErrDecrypt.
+func DecryptWithPassphrase(passphrase string, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < SaltSize {
+		return nil, ErrDecrypt
+	}
+	return Decrypt(DeriveKey(passphrase, ciphertext[:SaltSize]), ciphertext[SaltSize:])
+}
@@ -0,0 +1,59 @@
+package cryptox
+
+import "bytes"
+import "errors"
+import "testing"
+
+func TestEncrypt(t *testing.T) {
+	key := NewKey()
+	msg := []byte("attack at dawn")
+	a, err := Encrypt(key, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := Encrypt(key, msg)
+	if bytes.Equal(a, b) {
+		t.Error("same nonce used twice")
+	}
+	if len(a) != 12+len(msg)+16 {
+		t.Errorf("ciphertext is %d bytes", len(a))
+	}
+	if p, err := Decrypt(key, a); !bytes.Equal(p, msg) || err != nil {
+		t.Errorf("Decrypt = %q, %v", p, err)
+	}
+
+	a[len(a)-1] ^= 1
+	if _, err := Decrypt(key, a); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("altered: %v", err)
+	}
+	if _, err := Decrypt(NewKey(), b); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong key: %v", err)
+	}
+	if _, err := Decrypt(key, b[:20]); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("short: %v", err)
+	}
+	if _, err := Encrypt(key[:16], msg); err == nil {
+		t.Error("AES-128 key accepted")
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	salt := NewSalt()
+	if !bytes.Equal(DeriveKey("pw", salt), DeriveKey("pw", salt)) {
+		t.Error("DeriveKey is not deterministic")
+	}
+	if bytes.Equal(DeriveKey("pw", salt), DeriveKey("pw", NewSalt())) {
+		t.Error("salt ignored")
+	}
+
+	c, err := EncryptWithPassphrase("correct horse", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p, err := DecryptWithPassphrase("correct horse", c); string(p) != "secret" || err != nil {
+		t.Errorf("Decrypt = %q, %v", p, err)
+	}
+	if _, err := DecryptWithPassphrase("battery staple", c); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong passphrase: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package cryptox
+
+import "crypto/cipher"
+import "crypto/hkdf"
+import "crypto/sha256"
+import "encoding/binary"
+import "errors"
+import "fmt"
+import "io"
+import "os"
+
+// A stream is split into chunks of ChunkSize bytes of plaintext, each
+// sealed on its own, so that neither end holds more than a chunk in
+// memory. The stream starts with a random salt, from which a key for this
+// stream alone is derived; each chunk's nonce is its number, with the
+// last byte set on the final chunk so that a stream cut short at a chunk
+// boundary does not decrypt:
+//
+//	salt (32 bytes) || sealed chunk || sealed chunk || ... || sealed final chunk
+
+// ChunkSize is how much plaintext a stream seals at a time.
+const ChunkSize = 64 << 10
+
+const streamSaltSize = 32
+
+func streamGCM(key, salt []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("cryptox: key is %d bytes, want %d", len(key), KeySize)
+	}
+	k, err := hkdf.Key(sha256.New, key, salt, "golib cryptox stream", KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("cryptox: %w", err)
+	}
+	return newGCM(k)
+}
+
+func chunkNonce(nonce []byte, i uint64, final bool) {
+	binary.BigEndian.PutUint64(nonce[3:11], i)
+	nonce[11] = 0
+	if final {
+		nonce[11] = 1
+	}
+}
+
+// EncryptStream encrypts everything read from src under key and writes it
+// to dst.
+func EncryptStream(key []byte, dst io.Writer, src io.Reader) error {
+	salt := random(streamSaltSize)
+	gcm, err := streamGCM(key, salt)
+	if err != nil {
+		return err
+	}
+	if _, err := dst.Write(salt); err != nil {
+		return err
+	}
+	// Read a chunk ahead, to know which chunk is the last.
+	cur, next := make([]byte, ChunkSize), make([]byte, ChunkSize)
+	n, err := readChunk(src, cur)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	out := make([]byte, 0, ChunkSize+gcm.Overhead())
+	for i := uint64(0); ; i++ {
+		m := 0
+		if n == ChunkSize {
+			if m, err = readChunk(src, next); err != nil {
+				return err
+			}
+		}
+		final := m == 0
+		chunkNonce(nonce, i, final)
+		if _, err := dst.Write(gcm.Seal(out[:0], nonce, cur[:n], nil)); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+		cur, next, n = next, cur, m
+	}
+}
+
+// readChunk fills p from r as far as it can, returning a short count only
+// at the end of r.
+func readChunk(r io.Reader, p []byte) (int, error) {
+	n, err := io.ReadFull(r, p)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return n, err
+}
+
+// DecryptStream reverses EncryptStream, writing the plaintext to dst. Each
+// chunk is checked before it is written, but a stream that fails part way
+// has had its earlier chunks written: discard dst on error.
+func DecryptStream(key []byte, dst io.Writer, src io.Reader) error {
+	salt := make([]byte, streamSaltSize)
+	if _, err := io.ReadFull(src, salt); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ErrDecrypt
+		}
+		return err
+	}
+	gcm, err := streamGCM(key, salt)
+	if err != nil {
+		return err
+	}
+	size := ChunkSize + gcm.Overhead()
+	cur, next := make([]byte, size), make([]byte, size)
+	n, err := readChunk(src, cur)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	out := make([]byte, 0, ChunkSize)
+	for i := uint64(0); ; i++ {
+		m := 0
+		if n == size {
+			if m, err = readChunk(src, next); err != nil {
+				return err
+			}
+		}
+		final := m == 0
+		chunkNonce(nonce, i, final)
+		p, err := gcm.Open(out[:0], nonce, cur[:n], nil)
+		if err != nil {
+			return ErrDecrypt
+		}
+		if _, err := dst.Write(p); err != nil {
+			return err
+		}
+		if final {
+			return nil
+		}
+		cur, next, n = next, cur, m
+	}
+}
+
+// EncryptFile encrypts the file src into a new file dst, with EncryptStream.
+func EncryptFile(key []byte, dst, src string) error {
+	return convertFile(EncryptStream, key, dst, src)
+}
+
+// DecryptFile decrypts the file src, made by EncryptFile, into a new file
+// dst. If decryption fails, dst is removed.
+func DecryptFile(key []byte, dst, src string) error {
+	return convertFile(DecryptStream, key, dst, src)
+}
+
+func convertFile(fn func([]byte, io.Writer, io.Reader) error, key []byte, dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	err = fn(key, out, in)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(dst))
+	}
+	return nil
+}
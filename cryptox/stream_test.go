@@ -0,0 +1,75 @@
+package cryptox
+
+import "bytes"
+import "errors"
+import "os"
+import "path/filepath"
+import "testing"
+
+func TestStream(t *testing.T) {
+	key := NewKey()
+	for _, size := range []int{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3*ChunkSize + 7} {
+		msg := random(size)
+		var c bytes.Buffer
+		if err := EncryptStream(key, &c, bytes.NewReader(msg)); err != nil {
+			t.Fatal(err)
+		}
+		var p bytes.Buffer
+		if err := DecryptStream(key, &p, bytes.NewReader(c.Bytes())); err != nil || !bytes.Equal(p.Bytes(), msg) {
+			t.Errorf("size %d: %d bytes back, %v", size, p.Len(), err)
+		}
+	}
+}
+
+func TestStreamTampering(t *testing.T) {
+	key := NewKey()
+	var c bytes.Buffer
+	EncryptStream(key, &c, bytes.NewReader(random(2*ChunkSize+100)))
+	full := c.Bytes()
+	chunk := ChunkSize + 16
+
+	cases := map[string][]byte{
+		"truncated at a chunk": full[:streamSaltSize+chunk],
+		"truncated mid-chunk":  full[:len(full)-1],
+		"salt only":            full[:streamSaltSize],
+		"empty":                nil,
+		"chunks swapped": append(append(append([]byte{}, full[:streamSaltSize]...),
+			full[streamSaltSize+chunk:streamSaltSize+2*chunk]...), full[streamSaltSize:streamSaltSize+chunk]...),
+	}
+	for name, in := range cases {
+		if err := DecryptStream(key, new(bytes.Buffer), bytes.NewReader(in)); !errors.Is(err, ErrDecrypt) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if err := DecryptStream(NewKey(), new(bytes.Buffer), bytes.NewReader(full)); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong key: %v", err)
+	}
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	plain, enc, dec := filepath.Join(dir, "plain"), filepath.Join(dir, "enc"), filepath.Join(dir, "dec")
+	msg := random(ChunkSize + 10)
+	os.WriteFile(plain, msg, 0o644)
+	key := NewKey()
+	if err := EncryptFile(key, enc, plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := EncryptFile(key, enc, plain); !errors.Is(err, os.ErrExist) {
+		t.Errorf("overwrote: %v", err)
+	}
+	if err := DecryptFile(key, dec, enc); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(dec); !bytes.Equal(got, msg) {
+		t.Error("round trip differs")
+	}
+
+	bad := filepath.Join(dir, "bad")
+	if err := DecryptFile(NewKey(), bad, enc); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong key: %v", err)
+	}
+	if _, err := os.Stat(bad); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("output of failed decryption left behind: %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+// Package dedup finds duplicate files and optionally replaces them with
+// hard links.
+//
+// Files are compared in three passes, each narrowing the candidates of
+// the last: by size, by a SHA-256 hash of their first few kilobytes, and
+// finally by a hash of their full contents. Most files are thus never
+// read in full.
+package dedup
+
+import "cmp"
+import "crypto/sha256"
+import "encoding/hex"
+import "fmt"
+import "io"
+import "io/fs"
+import "os"
+import "path/filepath"
+import "runtime"
+import "slices"
+import "strings"
+import "sync"
+
+// DefaultPrefixSize is the number of bytes hashed in the prescreening
+// pass when Options.PrefixSize is zero.
+const DefaultPrefixSize = 4096
+
+// Options configures Find. The zero value is ready to use.
+type Options struct {
+	// Workers is the number of files hashed at once. Zero means
+	// runtime.NumCPU().
+	Workers int
+	// MinSize is the smallest file considered. Empty files are always
+	// skipped.
+	MinSize int64
+	// PrefixSize is the number of leading bytes hashed to prescreen
+	// candidates. Zero means DefaultPrefixSize.
+	PrefixSize int64
+}
+
+// A Group is a set of files with identical contents.
+type Group struct {
+	Size  int64
+	Hash  string   // hex-encoded SHA-256 of the contents
+	Paths []string // sorted
+}
+
+// Wasted returns the bytes that would be freed by keeping one copy.
+func (g Group) Wasted() int64 {
+	return g.Size * int64(len(g.Paths)-1)
+}
+
+// Find walks the roots and returns the groups of duplicate regular files,
+// largest waste first. Paths that are already hard links to one another
+// are reported once.
+func Find(roots []string, opts Options) ([]Group, error) {
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if opts.PrefixSize <= 0 {
+		opts.PrefixSize = DefaultPrefixSize
+	}
+
+	bySize := map[int64][]file{}
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if size := info.Size(); size > 0 && size >= opts.MinSize {
+				bySize[size] = append(bySize[size], file{path, info})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var groups []Group
+	prefix := func(p string) (string, error) { return hashFile(p, opts.PrefixSize) }
+	full := func(p string) (string, error) { return hashFile(p, -1) }
+	for size, files := range bySize {
+		paths := distinct(files)
+		if len(paths) < 2 {
+			continue
+		}
+		cs, err := split(paths, opts.Workers, prefix)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cs {
+			// A file no longer than the prefix was hashed in full.
+			if size <= opts.PrefixSize {
+				groups = append(groups, Group{size, c.hash, c.paths})
+				continue
+			}
+			byHash, err := split(c.paths, opts.Workers, full)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range byHash {
+				groups = append(groups, Group{size, f.hash, f.paths})
+			}
+		}
+	}
+	slices.SortFunc(groups, func(a, b Group) int {
+		if c := cmp.Compare(b.Wasted(), a.Wasted()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Paths[0], b.Paths[0])
+	})
+	return groups, nil
+}
+
+type file struct {
+	path string
+	info fs.FileInfo
+}
+
+// distinct returns the paths of files, dropping any that are the same
+// file as an earlier one.
+func distinct(files []file) []string {
+	var kept []file
+	for _, f := range files {
+		if !slices.ContainsFunc(kept, func(k file) bool { return os.SameFile(k.info, f.info) }) {
+			kept = append(kept, f)
+		}
+	}
+	paths := make([]string, len(kept))
+	for i, f := range kept {
+		paths[i] = f.path
+	}
+	return paths
+}
+
+type candidates struct {
+	hash  string
+	paths []string
+}
+
+// split hashes paths with workers goroutines and returns the sets of two
+// or more paths sharing a hash.
+func split(paths []string, workers int, hash func(string) (string, error)) ([]candidates, error) {
+	hashes := make([]string, len(paths))
+	errs := make([]error, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(workers, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				hashes[i], errs[i] = hash(paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var out []candidates
+	index := map[string]int{}
+	for i, p := range paths {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("dedup: %w", errs[i])
+		}
+		if j, ok := index[hashes[i]]; ok {
+			out[j].paths = append(out[j].paths, p)
+			continue
+		}
+		index[hashes[i]] = len(out)
+		out = append(out, candidates{hashes[i], []string{p}})
+	}
+	return slices.DeleteFunc(out, func(c candidates) bool {
+		slices.Sort(c.paths)
+		return len(c.paths) < 2
+	}), nil
+}
+
+// hashFile returns the hex SHA-256 of the first n bytes of the named file,
+// or of all of it if n is negative.
+func hashFile(path string, n int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if n >= 0 {
+		r = io.LimitReader(f, n)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,70 @@
+package dedup
+
+import "os"
+import "path/filepath"
+import "reflect"
+import "strings"
+import "testing"
+
+// tree creates the given files under a temporary directory and returns
+// its path.
+func tree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, data := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func paths(dir string, groups []Group) [][]string {
+	var out [][]string
+	for _, g := range groups {
+		var ps []string
+		for _, p := range g.Paths {
+			rel, _ := filepath.Rel(dir, p)
+			ps = append(ps, filepath.ToSlash(rel))
+		}
+		out = append(out, ps)
+	}
+	return out
+}
+
+func TestFind(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	dir := tree(t, map[string]string{
+		"a.txt":     "hello",
+		"sub/b.txt": "hello",
+		"c.txt":     "world", // same size, different contents
+		"big1":      long + "1",
+		"big2":      long + "2", // same prefix, different tail
+		"big3":      long + "1",
+		"big4":      long + "1",
+		"empty1":    "",
+		"empty2":    "",
+	})
+	for _, prefix := range []int64{0, 16} {
+		groups, err := Find([]string{dir}, Options{Workers: 2, PrefixSize: prefix})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := [][]string{{"big1", "big3", "big4"}, {"a.txt", "sub/b.txt"}}
+		if got := paths(dir, groups); !reflect.DeepEqual(got, want) {
+			t.Errorf("PrefixSize %d: Find() == %v, want %v", prefix, got, want)
+		}
+		if len(groups) == 2 && (groups[0].Wasted() != 202 || groups[1].Wasted() != 5) {
+			t.Errorf("Wasted() == %d, %d, want 202, 5", groups[0].Wasted(), groups[1].Wasted())
+		}
+	}
+
+	groups, _ := Find([]string{dir}, Options{MinSize: 10})
+	if len(groups) != 1 {
+		t.Errorf("MinSize 10: got %d groups, want 1", len(groups))
+	}
+}
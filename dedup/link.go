@@ -0,0 +1,69 @@
+package dedup
+
+import "fmt"
+import "os"
+import "path/filepath"
+
+// Link replaces every file in g but the first with a hard link to the
+// first, returning the number of files replaced. Each replacement is made
+// atomically by linking to a temporary name and renaming it over the
+// duplicate.
+//
+// Every file is hashed again first. If the kept file no longer matches
+// g, Link makes no changes and returns an error; a duplicate that no
+// longer matches is left alone.
+//
+// All paths in g must be on the same file system.
+func Link(g Group) (int, error) {
+	if len(g.Paths) < 2 {
+		return 0, nil
+	}
+	keep := g.Paths[0]
+	keepInfo, err := os.Stat(keep)
+	if err != nil {
+		return 0, err
+	}
+	if ok, err := matches(keep, keepInfo, g); err != nil {
+		return 0, err
+	} else if !ok {
+		return 0, fmt.Errorf("dedup: %s has changed since it was scanned", keep)
+	}
+	n := 0
+	for _, p := range g.Paths[1:] {
+		info, err := os.Stat(p)
+		if err != nil {
+			return n, err
+		}
+		if os.SameFile(keepInfo, info) {
+			continue
+		}
+		if ok, err := matches(p, info, g); err != nil {
+			return n, err
+		} else if !ok {
+			continue
+		}
+		tmp := filepath.Join(filepath.Dir(p), fmt.Sprintf(".%s.dedup%d", filepath.Base(p), os.Getpid()))
+		if err := os.Link(keep, tmp); err != nil {
+			return n, fmt.Errorf("dedup: %w", err)
+		}
+		if err := os.Rename(tmp, p); err != nil {
+			os.Remove(tmp)
+			return n, fmt.Errorf("dedup: %w", err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// matches reports whether the file at path, with the given info, still
+// has g's size and contents.
+func matches(path string, info os.FileInfo, g Group) (bool, error) {
+	if !info.Mode().IsRegular() || info.Size() != g.Size {
+		return false, nil
+	}
+	h, err := hashFile(path, -1)
+	if err != nil {
+		return false, err
+	}
+	return h == g.Hash, nil
+}
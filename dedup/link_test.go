@@ -0,0 +1,67 @@
+package dedup
+
+import "os"
+import "path/filepath"
+import "testing"
+
+func TestLink(t *testing.T) {
+	dir := tree(t, map[string]string{"a": "same", "b": "same", "c/d": "same"})
+	groups, err := Find([]string{dir}, Options{})
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("Find() == %v, %v", groups, err)
+	}
+	n, err := Link(groups[0])
+	if err != nil || n != 2 {
+		t.Fatalf("Link() == %d, %v, want 2", n, err)
+	}
+	a, _ := os.Stat(filepath.Join(dir, "a"))
+	for _, p := range []string{"b", "c/d"} {
+		info, err := os.Stat(filepath.Join(dir, p))
+		if err != nil || !os.SameFile(a, info) {
+			t.Errorf("%s is not a link to a", p)
+		}
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "c"))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+
+	// Linked files are one file, so no longer duplicates.
+	if groups, _ := Find([]string{dir}, Options{}); len(groups) != 0 {
+		t.Errorf("Find() after Link == %v", paths(dir, groups))
+	}
+}
+
+func TestLinkChanged(t *testing.T) {
+	dir := tree(t, map[string]string{"a": "same", "b": "same", "c": "same"})
+	groups, err := Find([]string{dir}, Options{})
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("Find() == %v, %v", groups, err)
+	}
+	g := groups[0]
+
+	// Same size, different contents: only a hash catches it.
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a", "diff")
+	if n, err := Link(g); err == nil || n != 0 {
+		t.Errorf("Link() with changed kept file == %d, %v, want an error", n, err)
+	}
+	for _, p := range []string{"b", "c"} {
+		if data, _ := os.ReadFile(filepath.Join(dir, p)); string(data) != "same" {
+			t.Errorf("%s == %q after refused Link", p, data)
+		}
+	}
+
+	write("a", "same")
+	write("c", "diff")
+	if n, err := Link(g); err != nil || n != 1 {
+		t.Errorf("Link() with changed duplicate == %d, %v, want 1", n, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "c")); string(data) != "diff" {
+		t.Errorf("changed duplicate was replaced")
+	}
+}
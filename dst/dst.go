@@ -0,0 +1,296 @@
+// Package dst tests concurrent code deterministically by choosing the
+// interleaving of its goroutines.
+//
+// A test hands its goroutines, as plain functions called tasks, to the
+// run function it is given. Only one task runs at a time, and it runs
+// until it finishes or calls Yield, when the scheduler chooses which task
+// goes next. A Schedule records those choices, so any interleaving that
+// breaks the code can be replayed exactly. Explore tries the schedules
+// one after another, depth first, and stops at the first that fails.
+//
+// Code under test marks the points where another goroutine could
+// interfere by calling Yield, which does nothing outside a test. Between
+// yields a task must not wait on another task, since the other cannot
+// run until it yields: spin with TryLock and Yield rather than calling
+// Lock. A task that blocks anyway fails the schedule with ErrStalled.
+//
+// One schedule runs at a time across the whole process, and tasks must
+// not start goroutines of their own that call Yield.
+package dst
+
+import "errors"
+import "fmt"
+import "slices"
+import "sync"
+import "sync/atomic"
+import "time"
+
+// DefaultStall is how long a task may run without yielding before the
+// schedule fails with ErrStalled, unless configured otherwise.
+const DefaultStall = time.Second
+
+// ErrStalled reports that a task neither yielded nor finished in time,
+// most likely because it was waiting on another task.
+var ErrStalled = errors.New("dst: task stalled without yielding")
+
+// ErrMismatch reports that a replayed schedule made a choice the test did
+// not offer, because the test is not deterministic or has changed.
+var ErrMismatch = errors.New("dst: schedule does not match the test")
+
+// A Schedule is a sequence of choices, one for each time more than one
+// task could run. The tasks are taken round robin: choice 0 is the next
+// task after the one that last ran, in the order they were passed to run,
+// and choice i is the one i further on. A task that spins waiting for
+// another therefore lets it run.
+type Schedule []int
+
+// A Test sets up fresh state, calls run with the tasks to interleave,
+// then checks the outcome and returns an error if it is wrong. It must be
+// deterministic given the schedule. run may be called more than once.
+type Test func(run func(tasks ...func())) error
+
+// A Failure is the error from a test under a particular schedule.
+type Failure struct {
+	Schedule Schedule
+	Err      error
+}
+
+func (f *Failure) Error() string { return fmt.Sprintf("dst: schedule %v: %v", f.Schedule, f.Err) }
+func (f *Failure) Unwrap() error { return f.Err }
+
+// Explorer tries the schedules of a test.
+type Explorer struct {
+	// Delays bounds how far a schedule may depart from round robin: the
+	// sum of its choices. Most concurrency bugs need only one or two.
+	// Zero means 2; negative means no bound, in which case a test that
+	// spins can have endless schedules and Schedules is the only limit.
+	Delays int
+	// Schedules is the most schedules to try. Zero means 10000.
+	Schedules int
+	// Stall is how long a task may run without yielding. Zero means
+	// DefaultStall.
+	Stall time.Duration
+}
+
+// Explore runs test under each schedule within the bounds, stopping at
+// the first failure, which it returns as a *Failure. It returns the
+// number of schedules tried.
+func (e Explorer) Explore(test Test) (int, error) {
+	bound := e.Delays
+	if bound == 0 {
+		bound = 2
+	}
+	limit := e.Schedules
+	if limit <= 0 {
+		limit = 10000
+	}
+	var prefix Schedule
+	for n := 1; ; n++ {
+		s, err := try(prefix, e.Stall, test)
+		if err != nil {
+			return n, err
+		}
+		var ok bool
+		if prefix, ok = s.next(bound); !ok || n == limit {
+			return n, nil
+		}
+	}
+}
+
+// Replay runs test under schedule, as reported by a Failure, returning a
+// *Failure if the test fails. Choices past the end of the schedule are 0.
+func Replay(schedule Schedule, test Test) error {
+	_, err := try(schedule, 0, test)
+	return err
+}
+
+// Yield lets the scheduler switch to another task. Outside a test it does
+// nothing.
+func Yield() {
+	if s := active.Load(); s != nil {
+		s.yield()
+	}
+}
+
+var (
+	running sync.Mutex // held while a schedule runs
+	active  atomic.Pointer[scheduler]
+)
+
+type scheduler struct {
+	prefix Schedule
+	stall  time.Duration
+
+	// The choices made so far, and how many tasks were on offer at each.
+	followed Schedule
+	offered  []int
+
+	cur    atomic.Pointer[task] // the task allowed to run, if any
+	events chan event
+	abort  chan struct{} // closed when the schedule fails
+	err    error
+}
+
+type task struct {
+	wake chan struct{}
+}
+
+// An event is what a task sends the scheduler when it yields or ends.
+type event struct {
+	done     bool
+	panicked bool
+	value    any
+}
+
+// aborted is the panic that unwinds a task when its schedule fails.
+type aborted struct{}
+
+func try(prefix Schedule, stall time.Duration, test Test) (*scheduler, error) {
+	if stall <= 0 {
+		stall = DefaultStall
+	}
+	running.Lock()
+	defer running.Unlock()
+	s := &scheduler{prefix: prefix, stall: stall, events: make(chan event), abort: make(chan struct{})}
+	active.Store(s)
+	defer active.Store(nil)
+
+	err := test(s.run)
+	if s.err != nil {
+		err = s.err
+	}
+	if err != nil {
+		return s, &Failure{Schedule: s.followed, Err: err}
+	}
+	return s, nil
+}
+
+// next returns the schedule that follows s's in depth-first order: the
+// last choice that can be increased within bound is, and those after it
+// are dropped. It reports false when there is none.
+func (s *scheduler) next(bound int) (Schedule, bool) {
+	delays := 0
+	for _, c := range s.followed {
+		delays += c
+	}
+	for i := len(s.followed) - 1; i >= 0; i-- {
+		c := s.followed[i]
+		delays -= c
+		if c+1 >= s.offered[i] || bound >= 0 && delays+c+1 > bound {
+			continue
+		}
+		return append(slices.Clone(s.followed[:i]), c+1), true
+	}
+	return nil, false
+}
+
+// run starts a goroutine for each task and lets them proceed one at a
+// time until all have finished or the schedule fails.
+func (s *scheduler) run(fns ...func()) {
+	if s.err != nil {
+		return
+	}
+	tasks := make([]*task, len(fns))
+	for i, fn := range fns {
+		t := &task{wake: make(chan struct{})}
+		tasks[i] = t
+		go s.start(t, fn)
+	}
+
+	from := 0 // where the round robin resumes
+	for len(tasks) > 0 {
+		c, err := s.choose(len(tasks))
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		i := (from + c) % len(tasks)
+		t := tasks[i]
+		s.cur.Store(t)
+		t.wake <- struct{}{}
+
+		var ev event
+		select {
+		case ev = <-s.events:
+		case <-time.After(s.stall):
+			s.fail(ErrStalled)
+			return
+		}
+		switch {
+		case ev.panicked:
+			s.fail(fmt.Errorf("panic: %v", ev.value))
+			return
+		case ev.done:
+			tasks = slices.Delete(tasks, i, i+1)
+			from = i
+		default:
+			from = i + 1
+		}
+	}
+	s.cur.Store(nil)
+}
+
+// choose returns the next choice among n tasks, from the prefix while it
+// lasts and 0 after that. A single task is no choice and is not recorded.
+func (s *scheduler) choose(n int) (int, error) {
+	if n == 1 {
+		return 0, nil
+	}
+	c := 0
+	if k := len(s.followed); k < len(s.prefix) {
+		c = s.prefix[k]
+		if c < 0 || c >= n {
+			return 0, ErrMismatch
+		}
+	}
+	s.followed = append(s.followed, c)
+	s.offered = append(s.offered, n)
+	return c, nil
+}
+
+func (s *scheduler) fail(err error) {
+	s.err = err
+	s.cur.Store(nil)
+	close(s.abort)
+}
+
+// start runs fn as task t once it is first chosen, reporting its end.
+func (s *scheduler) start(t *task, fn func()) {
+	select {
+	case <-t.wake:
+	case <-s.abort:
+		return
+	}
+	ev := event{done: true}
+	defer func() {
+		if v := recover(); v != nil {
+			if _, ok := v.(aborted); ok {
+				return
+			}
+			ev = event{panicked: true, value: v}
+		}
+		select {
+		case s.events <- ev:
+		case <-s.abort:
+		}
+	}()
+	fn()
+}
+
+// yield hands control back to the scheduler and waits to be chosen again.
+func (s *scheduler) yield() {
+	t := s.cur.Load()
+	if t == nil {
+		return
+	}
+	select {
+	case s.events <- event{}:
+	case <-s.abort:
+		panic(aborted{})
+	}
+	select {
+	case <-t.wake:
+	case <-s.abort:
+		panic(aborted{})
+	}
+}
@@ -0,0 +1,154 @@
+package dst
+
+import "errors"
+import "fmt"
+import "slices"
+import "strings"
+import "sync"
+import "testing"
+import "time"
+
+// counter increments without a lock, yielding between the read and the
+// write, where another goroutine could interfere.
+type counter struct{ n int }
+
+func (c *counter) inc() {
+	n := c.n
+	Yield()
+	c.n = n + 1
+}
+
+// lockedCounter fixes counter by holding a lock across the increment,
+// spinning on TryLock so that waiting yields.
+type lockedCounter struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (c *lockedCounter) inc() {
+	for !c.mu.TryLock() {
+		Yield()
+	}
+	n := c.n
+	Yield()
+	c.n = n + 1
+	c.mu.Unlock()
+}
+
+func TestExploreFindsLostUpdate(t *testing.T) {
+	test := func(run func(...func())) error {
+		var c counter
+		run(c.inc, c.inc)
+		if c.n != 2 {
+			return fmt.Errorf("n == %d, want 2", c.n)
+		}
+		return nil
+	}
+	_, err := Explorer{}.Explore(test)
+	var f *Failure
+	if !errors.As(err, &f) {
+		t.Fatalf("Explore() error == %v, want a Failure", err)
+	}
+	if err := Replay(f.Schedule, test); err == nil || err.Error() != f.Error() {
+		t.Errorf("Replay(%v) == %v, want %v", f.Schedule, err, f)
+	}
+}
+
+func TestExploreLocked(t *testing.T) {
+	n, err := Explorer{}.Explore(func(run func(...func())) error {
+		var c lockedCounter
+		run(c.inc, c.inc, c.inc)
+		if c.n != 3 {
+			return fmt.Errorf("n == %d, want 3", c.n)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n < 2 {
+		t.Errorf("Explore() tried %d schedules", n)
+	}
+}
+
+func TestExploreBound(t *testing.T) {
+	// Two tasks of two steps each have six interleavings: one is plain
+	// round robin, three depart from it once and two twice.
+	cases := []struct {
+		delays, want int
+	}{
+		{-1, 6},
+		{1, 4},
+		{2, 6},
+	}
+	for _, c := range cases {
+		var traces []string
+		n, err := Explorer{Delays: c.delays}.Explore(func(run func(...func())) error {
+			var trace []string
+			step := func(name string) func() {
+				return func() {
+					trace = append(trace, name+"1")
+					Yield()
+					trace = append(trace, name+"2")
+				}
+			}
+			run(step("a"), step("b"))
+			traces = append(traces, strings.Join(trace, " "))
+			return nil
+		})
+		if err != nil || n != c.want {
+			t.Errorf("Delays %d: Explore() == %d, %v, want %d", c.delays, n, err, c.want)
+		}
+		slices.Sort(traces)
+		if len(slices.Compact(traces)) != n {
+			t.Errorf("Delays %d: repeated interleavings %q", c.delays, traces)
+		}
+	}
+}
+
+func TestExploreLimit(t *testing.T) {
+	n, err := Explorer{Delays: -1, Schedules: 3}.Explore(func(run func(...func())) error {
+		var c lockedCounter
+		run(c.inc, c.inc, c.inc)
+		return nil
+	})
+	if n != 3 || err != nil {
+		t.Errorf("Explore() == %d, %v, want 3, nil", n, err)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	err := Replay(nil, func(run func(...func())) error {
+		run(func() { Yield() }, func() { panic("boom") })
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "panic: boom") {
+		t.Errorf("Replay() error == %v, want the panic", err)
+	}
+}
+
+func TestStalled(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	_, err := Explorer{Stall: 20 * time.Millisecond}.Explore(func(run func(...func())) error {
+		run(func() { <-block })
+		return nil
+	})
+	if !errors.Is(err, ErrStalled) {
+		t.Errorf("Explore() error == %v, want ErrStalled", err)
+	}
+}
+
+func TestMismatch(t *testing.T) {
+	err := Replay(Schedule{5}, func(run func(...func())) error {
+		run(func() {}, func() {})
+		return nil
+	})
+	if !errors.Is(err, ErrMismatch) {
+		t.Errorf("Replay() error == %v, want ErrMismatch", err)
+	}
+}
+
+func TestYieldOutsideTest(t *testing.T) {
+	Yield() // must not block
+}
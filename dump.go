@@ -0,0 +1,216 @@
+package golib
+
+import "fmt"
+import "io"
+import "reflect"
+import "sort"
+import "strings"
+
+// Dump returns a multi-line, indented rendering of v that shows type names
+// and walks nested structs, maps, slices and pointers. Reference cycles are
+// reported rather than followed.
+func Dump(v any) string {
+	var b strings.Builder
+	Fdump(&b, v)
+	return b.String()
+}
+
+// Fdump writes the rendering produced by Dump, followed by a newline, to w.
+func Fdump(w io.Writer, v any) error {
+	d := &dumper{seen: map[visit]bool{}}
+	d.value(reflect.ValueOf(v), 0)
+	d.buf.WriteByte('\n')
+	_, err := io.WriteString(w, d.buf.String())
+	return err
+}
+
+// A visit identifies a reference on the current path so that cycles can be
+// detected. Slices are keyed by length as well as address because a
+// sub-slice shares its backing array with its parent.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+type dumper struct {
+	buf  strings.Builder
+	seen map[visit]bool
+}
+
+func (d *dumper) indent(depth int) {
+	d.buf.WriteString(strings.Repeat("  ", depth))
+}
+
+func (d *dumper) value(v reflect.Value, depth int) {
+	if !v.IsValid() {
+		d.buf.WriteString("nil")
+		return
+	}
+
+	if s, ok := stringer(v); ok {
+		fmt.Fprintf(&d.buf, "%s(%q)", v.Type(), s)
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			d.buf.WriteString("nil")
+			return
+		}
+		d.value(v.Elem(), depth)
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			fmt.Fprintf(&d.buf, "(%s)(nil)", v.Type())
+			return
+		}
+		key := visit{v.Pointer(), v.Type(), 0}
+		if d.seen[key] {
+			fmt.Fprintf(&d.buf, "<cycle %s>", v.Type())
+			return
+		}
+		d.seen[key] = true
+		d.buf.WriteByte('&')
+		d.value(v.Elem(), depth)
+		delete(d.seen, key)
+
+	case reflect.Struct:
+		t := v.Type()
+		if v.NumField() == 0 {
+			fmt.Fprintf(&d.buf, "%s{}", t)
+			return
+		}
+		fmt.Fprintf(&d.buf, "%s{\n", t)
+		for i := 0; i < v.NumField(); i++ {
+			d.indent(depth + 1)
+			d.buf.WriteString(t.Field(i).Name)
+			d.buf.WriteString(": ")
+			d.value(v.Field(i), depth+1)
+			d.buf.WriteString(",\n")
+		}
+		d.indent(depth)
+		d.buf.WriteByte('}')
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				fmt.Fprintf(&d.buf, "%s(nil)", v.Type())
+				return
+			}
+			key := visit{v.Pointer(), v.Type(), v.Len()}
+			if d.seen[key] {
+				fmt.Fprintf(&d.buf, "<cycle %s>", v.Type())
+				return
+			}
+			d.seen[key] = true
+			defer delete(d.seen, key)
+		}
+		if v.Len() == 0 {
+			fmt.Fprintf(&d.buf, "%s{}", v.Type())
+			return
+		}
+		fmt.Fprintf(&d.buf, "%s{\n", v.Type())
+		for i := 0; i < v.Len(); i++ {
+			d.indent(depth + 1)
+			d.value(v.Index(i), depth+1)
+			d.buf.WriteString(",\n")
+		}
+		d.indent(depth)
+		d.buf.WriteByte('}')
+
+	case reflect.Map:
+		if v.IsNil() {
+			fmt.Fprintf(&d.buf, "%s(nil)", v.Type())
+			return
+		}
+		key := visit{v.Pointer(), v.Type(), 0}
+		if d.seen[key] {
+			fmt.Fprintf(&d.buf, "<cycle %s>", v.Type())
+			return
+		}
+		d.seen[key] = true
+		defer delete(d.seen, key)
+		if v.Len() == 0 {
+			fmt.Fprintf(&d.buf, "%s{}", v.Type())
+			return
+		}
+		fmt.Fprintf(&d.buf, "%s{\n", v.Type())
+		for _, k := range sortedKeys(v) {
+			d.indent(depth + 1)
+			d.value(k, depth+1)
+			d.buf.WriteString(": ")
+			d.value(v.MapIndex(k), depth+1)
+			d.buf.WriteString(",\n")
+		}
+		d.indent(depth)
+		d.buf.WriteByte('}')
+
+	case reflect.String:
+		fmt.Fprintf(&d.buf, "%q", v.String())
+	case reflect.Bool:
+		fmt.Fprintf(&d.buf, "%t", v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		fmt.Fprintf(&d.buf, "%d", v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		fmt.Fprintf(&d.buf, "%d", v.Uint())
+	case reflect.Float32, reflect.Float64:
+		fmt.Fprintf(&d.buf, "%g", v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		fmt.Fprintf(&d.buf, "%g", v.Complex())
+	default:
+		// Funcs, channels and unsafe pointers have no useful structure.
+		if v.IsNil() {
+			fmt.Fprintf(&d.buf, "(%s)(nil)", v.Type())
+		} else {
+			fmt.Fprintf(&d.buf, "(%s)(%#x)", v.Type(), v.Pointer())
+		}
+	}
+}
+
+// stringer reports the result of v's String method, if it has one and it
+// is safe to call. Values reached through unexported fields are skipped
+// because reflect does not allow calling their methods. If String panics,
+// as it may on a value with nil fields, v is dumped structurally instead.
+func stringer(v reflect.Value) (str string, ok bool) {
+	if !v.CanInterface() {
+		return "", false
+	}
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return "", false
+	}
+	if v.Kind() == reflect.Interface {
+		return "", false
+	}
+	s, ok := v.Interface().(fmt.Stringer)
+	if !ok {
+		return "", false
+	}
+	defer func() {
+		if recover() != nil {
+			str, ok = "", false
+		}
+	}()
+	return s.String(), true
+}
+
+// sortedKeys returns the keys of map v in a deterministic order.
+func sortedKeys(v reflect.Value) []reflect.Value {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch a.Kind() {
+		case reflect.String:
+			return a.String() < b.String()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		}
+		return fmt.Sprint(a) < fmt.Sprint(b)
+	})
+	return keys
+}
@@ -0,0 +1,47 @@
+package golib
+
+import "testing"
+
+type dumpVertex struct {
+	X, Y int
+}
+
+type dumpNode struct {
+	Name string
+	Next *dumpNode
+}
+
+type dumpName struct {
+	First *string
+}
+
+func (n dumpName) String() string { return *n.First }
+
+func TestDump(t *testing.T) {
+	loop := &dumpNode{Name: "a"}
+	loop.Next = loop
+
+	cases := []struct {
+		in   any
+		want string
+	}{
+		{nil, "nil"},
+		{42, "42"},
+		{"hi", `"hi"`},
+		{dumpVertex{1, 2}, "golib.dumpVertex{\n  X: 1,\n  Y: 2,\n}"},
+		{&dumpVertex{X: 1}, "&golib.dumpVertex{\n  X: 1,\n  Y: 0,\n}"},
+		{[]int{}, "[]int{}"},
+		{[]int(nil), "[]int(nil)"},
+		{[]string{"a"}, "[]string{\n  \"a\",\n}"},
+		{map[string]int{"b": 2, "a": 1}, "map[string]int{\n  \"a\": 1,\n  \"b\": 2,\n}"},
+		{(*dumpNode)(nil), "(*golib.dumpNode)(nil)"},
+		{dumpName{}, "golib.dumpName{\n  First: (*string)(nil),\n}"},
+		{loop, "&golib.dumpNode{\n  Name: \"a\",\n  Next: <cycle *golib.dumpNode>,\n}"},
+	}
+	for _, c := range cases {
+		got := Dump(c.in)
+		if got != c.want+"\n" {
+			t.Errorf("Dump(%#v) ==\n%s\nwant\n%s", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package golib
+
+// Lerp returns the value a fraction t of the way from a to b. t is not
+// clamped, so values outside [0, 1] extrapolate.
+func Lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// InverseLerp returns the fraction of the way v is from a to b, the
+// inverse of Lerp. It returns zero if a == b.
+func InverseLerp(a, b, v float64) float64 {
+	if a == b {
+		return 0
+	}
+	return (v - a) / (b - a)
+}
+
+// Remap maps v from the range [inLo, inHi] to [outLo, outHi], so that
+// Remap(5, 0, 10, 100, 200) is 150.
+func Remap(v, inLo, inHi, outLo, outHi float64) float64 {
+	return Lerp(outLo, outHi, InverseLerp(inLo, inHi, v))
+}
+
+// An Easing maps progress t in [0, 1] to an eased progress, with
+// Easing(0) == 0 and Easing(1) == 1. Use one with Lerp to animate a value:
+//
+//	x := Lerp(from, to, EaseInOutCubic(elapsed/duration))
+//
+// Of the Easing functions in this package, the "In" curves start slowly,
+// the "Out" curves end slowly, and the "InOut" curves do both.
+type Easing func(t float64) float64
+
+// Linear returns t unchanged.
+func Linear(t float64) float64 { return t }
+
+// EaseInQuad accelerates from zero velocity.
+func EaseInQuad(t float64) float64 { return t * t }
+
+// EaseOutQuad decelerates to zero velocity.
+func EaseOutQuad(t float64) float64 { return t * (2 - t) }
+
+// EaseInOutQuad accelerates until halfway, then decelerates.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseInCubic accelerates from zero velocity, more sharply than
+// EaseInQuad.
+func EaseInCubic(t float64) float64 { return t * t * t }
+
+// EaseOutCubic decelerates to zero velocity, more sharply than
+// EaseOutQuad.
+func EaseOutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// EaseInOutCubic accelerates until halfway, then decelerates.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
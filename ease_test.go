@@ -0,0 +1,73 @@
+package golib
+
+import "math"
+import "testing"
+
+func TestLerp(t *testing.T) {
+	cases := []struct {
+		a, b, t, want float64
+	}{
+		{0, 10, 0.5, 5},
+		{10, 20, 0, 10},
+		{10, 20, 1, 20},
+		{10, 20, 1.5, 25},
+		{5, -5, 0.25, 2.5},
+	}
+	for _, c := range cases {
+		if got := Lerp(c.a, c.b, c.t); got != c.want {
+			t.Errorf("Lerp(%v, %v, %v) == %v, want %v", c.a, c.b, c.t, got, c.want)
+		}
+		if got := InverseLerp(c.a, c.b, c.want); got != c.t {
+			t.Errorf("InverseLerp(%v, %v, %v) == %v, want %v", c.a, c.b, c.want, got, c.t)
+		}
+	}
+	if got := InverseLerp(3, 3, 7); got != 0 {
+		t.Errorf("InverseLerp(3, 3, 7) == %v", got)
+	}
+	if got := Remap(5, 0, 10, 100, 200); got != 150 {
+		t.Errorf("Remap(5, 0, 10, 100, 200) == %v", got)
+	}
+	if got := Remap(32, 32, 212, 0, 100); got != 0 {
+		t.Errorf("Remap(32, 32, 212, 0, 100) == %v", got)
+	}
+}
+
+func TestEasing(t *testing.T) {
+	cases := []struct {
+		name string
+		f    Easing
+		half float64
+	}{
+		{"Linear", Linear, 0.5},
+		{"EaseInQuad", EaseInQuad, 0.25},
+		{"EaseOutQuad", EaseOutQuad, 0.75},
+		{"EaseInOutQuad", EaseInOutQuad, 0.5},
+		{"EaseInCubic", EaseInCubic, 0.125},
+		{"EaseOutCubic", EaseOutCubic, 0.875},
+		{"EaseInOutCubic", EaseInOutCubic, 0.5},
+	}
+	for _, c := range cases {
+		if c.f(0) != 0 || c.f(1) != 1 {
+			t.Errorf("%s(0), %s(1) == %v, %v", c.name, c.name, c.f(0), c.f(1))
+		}
+		if got := c.f(0.5); math.Abs(got-c.half) > 1e-12 {
+			t.Errorf("%s(0.5) == %v, want %v", c.name, got, c.half)
+		}
+		// All the curves are monotonic on [0, 1].
+		prev := 0.0
+		for i := 1; i <= 100; i++ {
+			v := c.f(float64(i) / 100)
+			if v < prev {
+				t.Errorf("%s decreases at %v", c.name, float64(i)/100)
+				break
+			}
+			prev = v
+		}
+	}
+	if got := EaseInOutQuad(0.25); got != 0.125 {
+		t.Errorf("EaseInOutQuad(0.25) == %v", got)
+	}
+	if got := EaseInOutCubic(0.75); got != 0.9375 {
+		t.Errorf("EaseInOutCubic(0.75) == %v", got)
+	}
+}
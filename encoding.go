@@ -0,0 +1,98 @@
+package golib
+
+import "encoding/base32"
+import "encoding/base64"
+import "encoding/hex"
+import "errors"
+import "strings"
+
+// EncodeBase64 returns s in standard, padded base64.
+func EncodeBase64(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+// DecodeBase64 decodes standard base64, with or without padding.
+func DecodeBase64(s string) (string, error) {
+	return decode(base64.RawStdEncoding.DecodeString, strings.TrimRight(s, "="))
+}
+
+// EncodeBase64URL returns s in unpadded URL-safe base64, which can go in
+// a URL or file name as it is.
+func EncodeBase64URL(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+// DecodeBase64URL decodes URL-safe base64, with or without padding.
+func DecodeBase64URL(s string) (string, error) {
+	return decode(base64.RawURLEncoding.DecodeString, strings.TrimRight(s, "="))
+}
+
+// EncodeBase32 returns s in standard, padded base32.
+func EncodeBase32(s string) string { return base32.StdEncoding.EncodeToString([]byte(s)) }
+
+// DecodeBase32 decodes standard base32, with or without padding and in
+// either case.
+func DecodeBase32(s string) (string, error) {
+	return decode(base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString, strings.ToUpper(strings.TrimRight(s, "=")))
+}
+
+// EncodeHex returns s in lower-case hexadecimal.
+func EncodeHex(s string) string { return hex.EncodeToString([]byte(s)) }
+
+// DecodeHex decodes hexadecimal in either case.
+func DecodeHex(s string) (string, error) { return decode(hex.DecodeString, s) }
+
+func decode(fn func(string) ([]byte, error), s string) (string, error) {
+	b, err := fn(s)
+	return string(b), err
+}
+
+// ErrUnknownEncoding is returned by DetectAndDecode for input that is in
+// none of the encodings it knows.
+var ErrUnknownEncoding = errors.New("golib: unknown encoding")
+
+// DetectAndDecode decodes s as the first of hex, base32, base64 and
+// URL-safe base64 that it is valid in, and returns the name of that
+// encoding: "hex", "base32", "base64" or "base64url". Surrounding space is
+// ignored.
+//
+// Many short strings are valid in more than one encoding - "cafe" is hex
+// and base64 - so the answer is a guess. The encodings are tried from
+// strictest to loosest, so the guess is right for data that was really
+// encoded, but for input whose encoding is known, use its decoder.
+func DetectAndDecode(s string) (decoded, encoding string, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", "", ErrUnknownEncoding
+	}
+	try := []struct {
+		name  string
+		valid func(string) bool
+		fn    func(string) (string, error)
+	}{
+		{"hex", func(s string) bool { return len(s)%2 == 0 }, DecodeHex},
+		{"base32", isBase32, DecodeBase32},
+		{"base64", func(string) bool { return true }, DecodeBase64},
+		{"base64url", func(string) bool { return true }, DecodeBase64URL},
+	}
+	for _, t := range try {
+		if !t.valid(s) {
+			continue
+		}
+		if d, err := t.fn(s); err == nil {
+			return d, t.name, nil
+		}
+	}
+	return "", "", ErrUnknownEncoding
+}
+
+// isBase32 reports whether s is padded, upper-case base32 of whole
+// blocks, as encoders write it. Lower case or unpadded input is left to
+// base64, which it is far more likely to be.
+func isBase32(s string) bool {
+	if len(s)%8 != 0 {
+		return false
+	}
+	for _, c := range strings.TrimRight(s, "=") {
+		if !('A' <= c && c <= 'Z' || '2' <= c && c <= '7') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package golib
+
+import "errors"
+import "testing"
+
+func TestEncodings(t *testing.T) {
+	const s = "golib\xff?>"
+	cases := []struct {
+		name   string
+		encode func(string) string
+		decode func(string) (string, error)
+		want   string
+	}{
+		{"base64", EncodeBase64, DecodeBase64, "Z29saWL/Pz4="},
+		{"base64url", EncodeBase64URL, DecodeBase64URL, "Z29saWL_Pz4"},
+		{"base32", EncodeBase32, DecodeBase32, "M5XWY2LC747T4==="},
+		{"hex", EncodeHex, DecodeHex, "676f6c6962ff3f3e"},
+	}
+	for _, c := range cases {
+		if got := c.encode(s); got != c.want {
+			t.Errorf("%s: encoded %q, want %q", c.name, got, c.want)
+		}
+		if got, err := c.decode(c.want); got != s || err != nil {
+			t.Errorf("%s: decoded %q, %v", c.name, got, err)
+		}
+		if _, err := c.decode("!!"); err == nil {
+			t.Errorf("%s: decoded !!", c.name)
+		}
+	}
+
+	// Padding is optional, and base32 and hex ignore case.
+	lenient := []struct {
+		decode func(string) (string, error)
+		in     string
+	}{
+		{DecodeBase64, "Z29saWL/Pz4"},
+		{DecodeBase64URL, "Z29saWL_Pz4="},
+		{DecodeBase32, "m5xwy2lc747t4"},
+		{DecodeHex, "676F6C6962FF3F3E"},
+	}
+	for _, c := range lenient {
+		if got, err := c.decode(c.in); got != s || err != nil {
+			t.Errorf("decoding %q = %q, %v", c.in, got, err)
+		}
+	}
+}
+
+func TestDetectAndDecode(t *testing.T) {
+	cases := []struct{ in, want, enc string }{
+		{"68656c6c6f", "hello", "hex"},
+		{"NBSWY3DP", "hello", "base32"},
+		{"aGVsbG8=", "hello", "base64"},
+		{" aGVsbG8\n", "hello", "base64"},
+		{"Pz4_", "?>?", "base64url"},
+		{"Pz4/", "?>?", "base64"},
+	}
+	for _, c := range cases {
+		got, enc, err := DetectAndDecode(c.in)
+		if got != c.want || enc != c.enc || err != nil {
+			t.Errorf("DetectAndDecode(%q) = %q, %s, %v; want %q, %s", c.in, got, enc, err, c.want, c.enc)
+		}
+	}
+	for _, in := range []string{"", "not base64!", "a"} {
+		if _, _, err := DetectAndDecode(in); !errors.Is(err, ErrUnknownEncoding) {
+			t.Errorf("DetectAndDecode(%q) error %v", in, err)
+		}
+	}
+}
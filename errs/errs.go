@@ -0,0 +1,208 @@
+// Package errs adds a stack trace and structured fields to errors, while
+// keeping them usable with errors.Is, errors.As and errors.Unwrap.
+//
+// Wrap records where an error was first wrapped; later wraps add only
+// their message, so the trace points at the origin rather than at every
+// layer it passed through. Printed with %v an error reads as usual; %+v
+// prints each link of the chain on its own line, with fields and the
+// stack:
+//
+//	loading config
+//	reading "app.yaml" path=app.yaml
+//	open app.yaml: no such file or directory
+//	    main.readConfig
+//	        /src/app/config.go:41
+//	    ...
+package errs
+
+import "errors"
+import "fmt"
+import "io"
+import "runtime"
+import "strings"
+
+// New returns an error with message msg and the caller's stack.
+func New(msg string) error {
+	return &wrapped{msg: msg, stack: callers()}
+}
+
+// Wrap returns err with msg prepended to its message, as "msg: err", and
+// the caller's stack if err does not already carry one. Wrap(nil, msg)
+// is nil.
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	w := &wrapped{msg: msg, err: err}
+	if StackTrace(err) == nil {
+		w.stack = callers()
+	}
+	return w
+}
+
+// Wrapf is Wrap with a formatted message.
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	w := &wrapped{msg: fmt.Sprintf(format, args...), err: err}
+	if StackTrace(err) == nil {
+		w.stack = callers()
+	}
+	return w
+}
+
+// WithFields returns err with fields attached, given as alternating keys
+// and values: WithFields(err, "user", id, "attempt", n). The message is
+// unchanged. A key without a value gets nil. WithFields(nil, ...) is nil.
+func WithFields(err error, kv ...any) error {
+	if err == nil {
+		return nil
+	}
+	f := &withFields{err: err}
+	for i := 0; i < len(kv); i += 2 {
+		var v any
+		if i+1 < len(kv) {
+			v = kv[i+1]
+		}
+		f.fields = append(f.fields, Field{fmt.Sprint(kv[i]), v})
+	}
+	return f
+}
+
+// A Field is a key and value attached to an error.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Fields returns the fields attached anywhere in err's chain, innermost
+// first. Where a key is attached more than once, the outermost value
+// wins, in the position of the innermost.
+func Fields(err error) []Field {
+	var chain []*withFields
+	for ; err != nil; err = errors.Unwrap(err) {
+		if f, ok := err.(*withFields); ok {
+			chain = append(chain, f)
+		}
+	}
+	var fields []Field
+	index := map[string]int{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, f := range chain[i].fields {
+			if j, ok := index[f.Key]; ok {
+				fields[j].Value = f.Value
+				continue
+			}
+			index[f.Key] = len(fields)
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// StackTrace returns the frames of the stack recorded in err's chain by
+// New or Wrap, or nil if there is none.
+func StackTrace(err error) []runtime.Frame {
+	var w *wrapped
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*wrapped); ok && x.stack != nil {
+			w = x
+		}
+	}
+	if w == nil {
+		return nil
+	}
+	var frames []runtime.Frame
+	it := runtime.CallersFrames(w.stack)
+	for {
+		f, more := it.Next()
+		frames = append(frames, f)
+		if !more {
+			return frames
+		}
+	}
+}
+
+func callers() []uintptr {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs) // skip Callers, callers and its caller
+	return pcs[:n]
+}
+
+type wrapped struct {
+	msg   string
+	err   error
+	stack []uintptr
+}
+
+func (w *wrapped) Error() string {
+	if w.err == nil {
+		return w.msg
+	}
+	return w.msg + ": " + w.err.Error()
+}
+
+func (w *wrapped) Unwrap() error { return w.err }
+
+func (w *wrapped) Format(s fmt.State, verb rune) { format(w, s, verb) }
+
+type withFields struct {
+	err    error
+	fields []Field
+}
+
+func (f *withFields) Error() string { return f.err.Error() }
+
+func (f *withFields) Unwrap() error { return f.err }
+
+func (f *withFields) Format(s fmt.State, verb rune) { format(f, s, verb) }
+
+// format prints err for the fmt package: %+v gives the detail that
+// Detail does, and other verbs act as for any error.
+func format(err error, s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, Detail(err))
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	default:
+		io.WriteString(s, err.Error())
+	}
+}
+
+// Detail describes err over several lines: one for each link of the
+// chain, with its message and any fields attached to it, then the
+// recorded stack. The message of an error from elsewhere includes those
+// it wraps, so the links below it are not listed, though their fields
+// are.
+func Detail(err error) string {
+	var lines, pending []string // pending holds fields for the next link
+	covered := false            // whether a listed message includes the rest
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if f, ok := e.(*withFields); ok {
+			for _, f := range f.fields {
+				pending = append(pending, fmt.Sprintf("%s=%v", f.Key, f.Value))
+			}
+			continue
+		}
+		if covered {
+			continue
+		}
+		msg := e.Error()
+		if w, ok := e.(*wrapped); ok {
+			msg = w.msg
+		} else {
+			covered = true
+		}
+		lines = append(lines, strings.Join(append([]string{msg}, pending...), " "))
+		pending = nil
+	}
+	if len(pending) > 0 && len(lines) > 0 {
+		lines[len(lines)-1] += " " + strings.Join(pending, " ")
+	}
+	for _, f := range StackTrace(err) {
+		lines = append(lines, fmt.Sprintf("    %s\n        %s:%d", f.Function, f.File, f.Line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,79 @@
+package errs
+
+import "errors"
+import "fmt"
+import "io/fs"
+import "os"
+import "reflect"
+import "strings"
+import "testing"
+
+func readConfig(path string) error {
+	_, err := os.Open(path)
+	return WithFields(Wrapf(err, "reading %q", path), "path", path)
+}
+
+func TestWrap(t *testing.T) {
+	err := Wrap(readConfig("/nonexistent/app.yaml"), "loading config")
+	want := `loading config: reading "/nonexistent/app.yaml": open /nonexistent/app.yaml: no such file or directory`
+	if err.Error() != want {
+		t.Errorf("Error() = %q", err)
+	}
+	if fmt.Sprintf("%v", err) != want || fmt.Sprint(err) != want {
+		t.Errorf("%%v = %v", err)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Error("errors.Is does not see the cause")
+	}
+	var pe *fs.PathError
+	if !errors.As(err, &pe) {
+		t.Error("errors.As does not see the cause")
+	}
+	if Wrap(nil, "x") != nil || WithFields(nil, "k", 1) != nil {
+		t.Error("wrapping nil is not nil")
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	err := Wrap(readConfig("/nonexistent"), "outer")
+	frames := StackTrace(err)
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "errs.readConfig") {
+		t.Fatalf("stack starts at %v, want readConfig, where it was first wrapped", frames)
+	}
+	if StackTrace(errors.New("plain")) != nil {
+		t.Error("plain error has a stack")
+	}
+	if f := StackTrace(New("x")); len(f) == 0 || !strings.HasSuffix(f[0].Function, "TestStackTrace") {
+		t.Errorf("New stack starts at %v", f)
+	}
+}
+
+func TestFields(t *testing.T) {
+	err := WithFields(Wrap(WithFields(errors.New("x"), "a", 1, "b", 2), "w"), "b", 3, "c")
+	want := []Field{{"a", 1}, {"b", 3}, {"c", nil}}
+	if got := Fields(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %v, want %v", got, want)
+	}
+}
+
+func TestDetail(t *testing.T) {
+	err := Wrap(readConfig("/nonexistent/app.yaml"), "loading config")
+	got := fmt.Sprintf("%+v", err)
+	lines := strings.Split(got, "\n")
+	want := []string{
+		"loading config",
+		`reading "/nonexistent/app.yaml" path=/nonexistent/app.yaml`,
+		"open /nonexistent/app.yaml: no such file or directory",
+		"    github.com/lukehedger/golib/errs.readConfig",
+	}
+	if len(lines) < len(want) || !reflect.DeepEqual(lines[:len(want)], want) {
+		t.Errorf("%%+v =\n%s", got)
+	}
+
+	// An error from elsewhere lists its whole message once.
+	err = fmt.Errorf("handler: %w", WithFields(Wrap(errors.New("inner"), "middle"), "k", "v"))
+	got = Detail(err)
+	if first := strings.Split(got, "\n")[0]; first != "handler: middle: inner k=v" {
+		t.Errorf("Detail starts %q", first)
+	}
+}
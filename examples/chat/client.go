@@ -0,0 +1,65 @@
+package main
+
+import "bufio"
+import "context"
+import "errors"
+import "fmt"
+import "io"
+
+import "github.com/lukehedger/golib/websocket"
+
+// runClient connects to the room at url as nick, sending each line read
+// from in and printing each message received to out. It returns when in
+// is exhausted, ctx is done or the server closes the connection.
+func runClient(ctx context.Context, url, nick string, in io.Reader, out io.Writer) error {
+	c, err := websocket.Dial(ctx, url)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.WriteMessage(websocket.TextMessage, []byte(nick)); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		for {
+			_, msg, err := c.ReadMessage()
+			if err != nil {
+				done <- err
+				return
+			}
+			fmt.Fprintln(out, string(msg))
+		}
+	}()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(in)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-done:
+			var ce *websocket.CloseError
+			if errors.As(err, &ce) {
+				fmt.Fprintf(out, "* disconnected: %s\n", ce.Reason)
+				return nil
+			}
+			return err
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			if err := c.WriteMessage(websocket.TextMessage, []byte(line)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Chat is a chat room served over WebSockets, with a terminal client.
+//
+// Start a server, then connect any number of clients:
+//
+//	chat -serve localhost:8080
+//	chat -connect ws://localhost:8080/ws -nick gopher
+//
+// Messages typed into a client are broadcast to everyone in the room. The
+// commands /nick NAME, /who and /quit are also understood. Interrupting
+// the server tells every client it is going away before shutting down.
+package main
+
+import "context"
+import "flag"
+import "fmt"
+import "log"
+import "net/http"
+import "os"
+import "os/signal"
+import "syscall"
+
+import "github.com/lukehedger/golib/serve"
+
+func main() {
+	addr := flag.String("serve", "", "run a server on this address")
+	url := flag.String("connect", "", "connect to the server at this ws:// URL")
+	nick := flag.String("nick", os.Getenv("USER"), "nickname to use when connecting")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch {
+	case *addr != "":
+		err = runServer(ctx, *addr)
+	case *url != "":
+		err = runClient(ctx, *url, *nick, os.Stdin, os.Stdout)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "chat:", err)
+		os.Exit(1)
+	}
+}
+
+func runServer(ctx context.Context, addr string) error {
+	logger := log.New(os.Stderr, "chat: ", log.LstdFlags)
+	rm := newRoom()
+	mux := http.NewServeMux()
+	mux.Handle("/ws", rm)
+	s := &serve.Server{
+		Addr:       addr,
+		Handler:    serve.Chain(mux, serve.Recover(logger)),
+		OnShutdown: []func(){rm.close},
+	}
+	logger.Printf("listening on ws://%s/ws", addr)
+	return s.ListenAndServe(ctx)
+}
@@ -0,0 +1,158 @@
+package main
+
+import "errors"
+import "fmt"
+import "net/http"
+import "sort"
+import "strings"
+import "sync"
+
+import "github.com/lukehedger/golib/bus"
+import "github.com/lukehedger/golib/websocket"
+
+// A room relays every member's messages to all members through a bus.
+type room struct {
+	bus *bus.Bus[string]
+
+	mu      sync.Mutex
+	members map[string]*websocket.Conn
+}
+
+func newRoom() *room {
+	return &room{bus: bus.New[string](), members: map[string]*websocket.Conn{}}
+}
+
+// ServeHTTP upgrades the request and runs the member's session. The first
+// message a client sends is its nickname.
+func (rm *room) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	c.MaxMessageSize = 4096
+	defer c.Close()
+
+	nick, err := rm.join(c)
+	if err != nil {
+		return
+	}
+	// Buffered so that a slow client drops messages rather than stalling
+	// the room.
+	sub := rm.bus.Subscribe(64)
+	go func() {
+		for msg := range sub.C {
+			if c.WriteMessage(websocket.TextMessage, []byte(msg)) != nil {
+				return
+			}
+		}
+	}()
+	rm.bus.Publish("* " + nick + " joined")
+
+	defer func() {
+		sub.Close()
+		rm.leave(nick)
+		rm.bus.Publish("* " + nick + " left")
+	}()
+	for {
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			return
+		}
+		line := strings.TrimSpace(string(msg))
+		cmd, arg, _ := strings.Cut(line, " ")
+		switch cmd {
+		case "":
+		case "/quit":
+			return
+		case "/who":
+			rm.reply(c, "* in the room: "+strings.Join(rm.nicks(), ", "))
+		case "/nick":
+			if err := rm.rename(nick, arg, c); err != nil {
+				rm.reply(c, "* "+err.Error())
+				continue
+			}
+			rm.bus.Publish("* " + nick + " is now " + arg)
+			nick = arg
+		default:
+			rm.bus.Publish("<" + nick + "> " + line)
+		}
+	}
+}
+
+func (rm *room) reply(c *websocket.Conn, msg string) {
+	c.WriteMessage(websocket.TextMessage, []byte(msg))
+}
+
+// join reads nicknames from c until one is accepted.
+func (rm *room) join(c *websocket.Conn) (string, error) {
+	for {
+		_, msg, err := c.ReadMessage()
+		if err != nil {
+			return "", err
+		}
+		nick := strings.TrimSpace(string(msg))
+		if err := rm.rename("", nick, c); err != nil {
+			rm.reply(c, "* "+err.Error()+"; choose another nickname")
+			continue
+		}
+		rm.reply(c, "* welcome, "+nick)
+		return nick, nil
+	}
+}
+
+// rename registers c under nick, releasing old if it is not empty.
+func (rm *room) rename(old, nick string, c *websocket.Conn) error {
+	if err := validNick(nick); err != nil {
+		return err
+	}
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if _, taken := rm.members[nick]; taken {
+		return fmt.Errorf("nickname %s is taken", nick)
+	}
+	if old != "" {
+		delete(rm.members, old)
+	}
+	rm.members[nick] = c
+	return nil
+}
+
+func (rm *room) leave(nick string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	delete(rm.members, nick)
+}
+
+func (rm *room) nicks() []string {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	var nicks []string
+	for n := range rm.members {
+		nicks = append(nicks, n)
+	}
+	sort.Strings(nicks)
+	return nicks
+}
+
+// close tells every member the server is going away. It runs when the
+// server shuts down, because http.Server does not track hijacked
+// connections.
+func (rm *room) close() {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	for _, c := range rm.members {
+		c.CloseWith(websocket.CloseGoingAway, "server shutting down")
+	}
+}
+
+func validNick(nick string) error {
+	if nick == "" || len(nick) > 16 {
+		return errors.New("nicknames must be 1 to 16 characters")
+	}
+	for _, r := range nick {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return errors.New("nicknames may only use letters, digits, _ and -")
+		}
+	}
+	return nil
+}
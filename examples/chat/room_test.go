@@ -0,0 +1,80 @@
+package main
+
+import "context"
+import "net/http/httptest"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/websocket"
+
+type client struct {
+	t *testing.T
+	c *websocket.Conn
+}
+
+func connect(t *testing.T, srv *httptest.Server, nick string) *client {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cl := &client{t, c}
+	cl.send(nick)
+	return cl
+}
+
+func (cl *client) send(msg string) {
+	if err := cl.c.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+		cl.t.Fatal(err)
+	}
+}
+
+// expect reads messages until one equal to want arrives.
+func (cl *client) expect(want string) {
+	cl.t.Helper()
+	cl.c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, msg, err := cl.c.ReadMessage()
+		if err != nil {
+			cl.t.Fatalf("waiting for %q: %v", want, err)
+		}
+		if string(msg) == want {
+			return
+		}
+	}
+}
+
+func TestRoom(t *testing.T) {
+	rm := newRoom()
+	srv := httptest.NewServer(rm)
+	defer srv.Close()
+
+	alice := connect(t, srv, "alice")
+	alice.expect("* alice joined")
+
+	bob := connect(t, srv, "alice")
+	bob.expect("* nickname alice is taken; choose another nickname")
+	bob.send("bob")
+	bob.expect("* welcome, bob")
+	alice.expect("* bob joined")
+
+	bob.send("hello")
+	alice.expect("<bob> hello")
+	bob.expect("<bob> hello")
+
+	alice.send("/nick al")
+	bob.expect("* alice is now al")
+	bob.send("/who")
+	bob.expect("* in the room: al, bob")
+
+	bob.send("/quit")
+	alice.expect("* bob left")
+
+	rm.close()
+	_, _, err := alice.c.ReadMessage()
+	if ce, ok := err.(*websocket.CloseError); !ok || ce.Code != websocket.CloseGoingAway {
+		t.Errorf("after close: ReadMessage error %v, want going away", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Method and wildcard routing patterns need the Go 1.22 ServeMux, which
+// builds outside a module do not enable by default.
+//go:debug httpmuxgo121=0
+
+// Kvapi serves a kv.Store as a small REST API, wiring together the store,
+// the serve package's graceful shutdown and middleware, and expvar metrics.
+//
+//	GET    /keys        list the stored keys, one per line
+//	GET    /keys/{k}    read the value stored under k
+//	PUT    /keys/{k}    store the request body under k
+//	DELETE /keys/{k}    remove k
+//	GET    /debug/vars  expvar metrics, including request counts
+//
+// Interrupt the process to shut down; in-flight requests are allowed to
+// finish.
+package main
+
+import "context"
+import "errors"
+import "expvar"
+import "flag"
+import "io"
+import "log"
+import "net/http"
+import "os"
+import "os/signal"
+import "strings"
+import "syscall"
+
+import "github.com/lukehedger/golib/kv"
+import "github.com/lukehedger/golib/serve"
+
+const (
+	maxKeyLen    = 256
+	maxValueSize = 1 << 20
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "listen address")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "kvapi: ", log.LstdFlags)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s := &serve.Server{
+		Addr:    *addr,
+		Handler: newHandler(kv.New(), expvar.NewMap("kvapi"), logger),
+	}
+	logger.Printf("listening on %s", *addr)
+	if err := s.ListenAndServe(ctx); err != nil {
+		logger.Fatal(err)
+	}
+	logger.Print("shut down")
+}
+
+func newHandler(store *kv.Store, metrics *expvar.Map, logger *log.Logger) http.Handler {
+	a := &api{store}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /keys", a.list)
+	mux.HandleFunc("GET /keys/{k}", a.get)
+	mux.HandleFunc("PUT /keys/{k}", a.put)
+	mux.HandleFunc("DELETE /keys/{k}", a.delete)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	metrics.Set("keys", expvar.Func(func() any { return store.Len() }))
+	return serve.Chain(mux, serve.Logger(logger), serve.Metrics(metrics), serve.Recover(logger))
+}
+
+type api struct {
+	store *kv.Store
+}
+
+func (a *api) list(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, k := range a.store.Keys() {
+		io.WriteString(w, k+"\n")
+	}
+}
+
+func (a *api) get(w http.ResponseWriter, r *http.Request) {
+	key, ok := validKey(w, r)
+	if !ok {
+		return
+	}
+	v, err := a.store.Get(key)
+	if errors.Is(err, kv.ErrNotFound) {
+		http.Error(w, "key not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(v)
+}
+
+func (a *api) put(w http.ResponseWriter, r *http.Request) {
+	key, ok := validKey(w, r)
+	if !ok {
+		return
+	}
+	v, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxValueSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		http.Error(w, "value too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.store.Put(key, v)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *api) delete(w http.ResponseWriter, r *http.Request) {
+	key, ok := validKey(w, r)
+	if !ok {
+		return
+	}
+	if err := a.store.Delete(key); errors.Is(err, kv.ErrNotFound) {
+		http.Error(w, "key not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validKey returns the request's key, replying with 400 Bad Request if it
+// is too long or uses characters other than letters, digits, '.', '_' and
+// '-'.
+func validKey(w http.ResponseWriter, r *http.Request) (string, bool) {
+	key := r.PathValue("k")
+	valid := len(key) <= maxKeyLen && strings.Trim(key, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._-") == ""
+	if !valid {
+		http.Error(w, "invalid key", http.StatusBadRequest)
+	}
+	return key, valid
+}
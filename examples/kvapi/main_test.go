@@ -0,0 +1,56 @@
+package main
+
+import "expvar"
+import "io"
+import "log"
+import "net/http"
+import "net/http/httptest"
+import "strings"
+import "testing"
+
+import "github.com/lukehedger/golib/kv"
+
+func TestAPI(t *testing.T) {
+	metrics := new(expvar.Map).Init()
+	srv := httptest.NewServer(newHandler(kv.New(), metrics, log.New(io.Discard, "", 0)))
+	defer srv.Close()
+
+	cases := []struct {
+		method, path, body string
+		code               int
+		want               string
+	}{
+		{"GET", "/keys/greeting", "", http.StatusNotFound, "key not found\n"},
+		{"PUT", "/keys/greeting", "hello", http.StatusNoContent, ""},
+		{"PUT", "/keys/name", "gopher", http.StatusNoContent, ""},
+		{"GET", "/keys/greeting", "", http.StatusOK, "hello"},
+		{"GET", "/keys", "", http.StatusOK, "greeting\nname\n"},
+		{"DELETE", "/keys/greeting", "", http.StatusNoContent, ""},
+		{"DELETE", "/keys/greeting", "", http.StatusNotFound, "key not found\n"},
+		{"PUT", "/keys/bad key!", "x", http.StatusBadRequest, "invalid key\n"},
+		{"PUT", "/keys/big", strings.Repeat("x", maxValueSize+1), http.StatusRequestEntityTooLarge, "value too large\n"},
+		{"POST", "/keys/name", "", http.StatusMethodNotAllowed, "Method Not Allowed\n"},
+	}
+	for _, c := range cases {
+		req, err := http.NewRequest(c.method, srv.URL+c.path, strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != c.code || string(body) != c.want {
+			t.Errorf("%s %s: %d %q, want %d %q", c.method, c.path, resp.StatusCode, body, c.code, c.want)
+		}
+	}
+
+	if got := metrics.Get("requests").String(); got != "10" {
+		t.Errorf("requests metric == %s, want 10", got)
+	}
+	if got := metrics.Get("keys").String(); got != "1" {
+		t.Errorf("keys metric == %s, want 1", got)
+	}
+}
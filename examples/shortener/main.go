@@ -0,0 +1,157 @@
+// Method and wildcard routing patterns need the Go 1.22 ServeMux, which
+// builds outside a module do not enable by default.
+//go:debug httpmuxgo121=0
+
+// Shortener is a URL shortener built from the kv store with snapshot
+// persistence, the serve package, per-client rate limiting and expvar
+// metrics.
+//
+//	POST /shorten      shorten the URL in the "url" form value
+//	GET  /{id}         redirect to the URL stored under id
+//	GET  /debug/vars   expvar metrics
+//
+// Short IDs are random base58 strings, which avoid characters that are
+// easily confused such as 0, O, I and l.
+package main
+
+import "context"
+import "crypto/rand"
+import "errors"
+import "expvar"
+import "flag"
+import "fmt"
+import "log"
+import "net/http"
+import "net/url"
+import "os"
+import "os/signal"
+import "syscall"
+
+import "github.com/lukehedger/golib/kv"
+import "github.com/lukehedger/golib/ratelimit"
+import "github.com/lukehedger/golib/serve"
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "listen address")
+	data := flag.String("data", "shortener.json", "snapshot file")
+	rate := flag.Float64("rate", 1, "shorten requests per second allowed per client")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "shortener: ", log.LstdFlags)
+	store, err := kv.Open(*data)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sh := &shortener{
+		store:   store,
+		path:    *data,
+		base:    "http://" + *addr + "/",
+		limit:   ratelimit.NewKeyed(*rate, 5),
+		metrics: expvar.NewMap("shortener"),
+	}
+	s := &serve.Server{Addr: *addr, Handler: sh.handler(logger)}
+	logger.Printf("listening on %s with %d links", *addr, store.Len())
+	if err := s.ListenAndServe(ctx); err != nil {
+		logger.Fatal(err)
+	}
+}
+
+type shortener struct {
+	store   *kv.Store
+	path    string // snapshot file, or "" to keep links in memory only
+	base    string // prefix of returned short URLs
+	limit   *ratelimit.Keyed
+	metrics *expvar.Map
+}
+
+func (sh *shortener) handler(logger *log.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /shorten", ratelimit.Middleware(sh.limit, nil)(http.HandlerFunc(sh.shorten)))
+	mux.HandleFunc("GET /{id}", sh.redirect)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	return serve.Chain(mux, serve.Logger(logger), serve.Metrics(sh.metrics), serve.Recover(logger))
+}
+
+func (sh *shortener) shorten(w http.ResponseWriter, r *http.Request) {
+	target := r.FormValue("url")
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		http.Error(w, "url must be an absolute http or https URL", http.StatusBadRequest)
+		return
+	}
+
+	id, err := sh.newID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sh.store.Put(id, []byte(u.String()))
+	if sh.path != "" {
+		if err := sh.store.SaveFile(sh.path); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	sh.metrics.Add("shortened", 1)
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintln(w, sh.base+id)
+}
+
+func (sh *shortener) redirect(w http.ResponseWriter, r *http.Request) {
+	target, err := sh.store.Get(r.PathValue("id"))
+	if errors.Is(err, kv.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	sh.metrics.Add("redirects", 1)
+	http.Redirect(w, r, string(target), http.StatusFound)
+}
+
+// newID returns an unused random ID.
+func (sh *shortener) newID() (string, error) {
+	for range 10 {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		id := base58(b)
+		if _, err := sh.store.Get(id); errors.Is(err, kv.ErrNotFound) {
+			return id, nil
+		}
+	}
+	return "", errors.New("no free ID found")
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// base58 encodes b as a big-endian number in the Bitcoin base58 alphabet.
+func base58(b []byte) string {
+	var digits []byte
+	for _, c := range b {
+		carry := int(c)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+	// Leading zero bytes are written as '1' each.
+	for _, c := range b {
+		if c != 0 {
+			break
+		}
+		digits = append(digits, 0)
+	}
+	out := make([]byte, len(digits))
+	for i, d := range digits {
+		out[len(digits)-1-i] = base58Alphabet[d]
+	}
+	return string(out)
+}
@@ -0,0 +1,90 @@
+package main
+
+import "expvar"
+import "io"
+import "log"
+import "net/http"
+import "net/http/httptest"
+import "net/url"
+import "path/filepath"
+import "strings"
+import "testing"
+
+import "github.com/lukehedger/golib/kv"
+import "github.com/lukehedger/golib/ratelimit"
+
+func TestBase58(t *testing.T) {
+	cases := []struct {
+		in   []byte
+		want string
+	}{
+		{[]byte{0}, "1"},
+		{[]byte{57}, "z"},
+		{[]byte{58}, "21"},
+		{[]byte("hello world"), "StV1DL6CwTryKyV"},
+		{[]byte{0, 0, 1}, "112"},
+	}
+	for _, c := range cases {
+		if got := base58(c.in); got != c.want {
+			t.Errorf("base58(%v) == %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestShortener(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "links.json")
+	sh := &shortener{
+		store:   kv.New(),
+		path:    path,
+		limit:   ratelimit.NewKeyed(0.001, 2),
+		metrics: new(expvar.Map).Init(),
+	}
+	srv := httptest.NewServer(sh.handler(log.New(io.Discard, "", 0)))
+	defer srv.Close()
+	sh.base = srv.URL + "/"
+
+	resp, err := http.PostForm(srv.URL+"/shorten", url.Values{"url": {"https://go.dev/tour"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("POST /shorten: status %d: %s", resp.StatusCode, body)
+	}
+	short := strings.TrimSpace(string(body))
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err = client.Get(short)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "https://go.dev/tour" {
+		t.Errorf("GET %s: %d to %q, want 302 to the tour", short, resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	// The bucket of two tokens is now down to one.
+	codes := []int{http.StatusBadRequest, http.StatusTooManyRequests}
+	for _, want := range codes {
+		resp, err := http.PostForm(srv.URL+"/shorten", url.Values{"url": {"ftp://example.com"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("POST /shorten: status %d, want %d", resp.StatusCode, want)
+		}
+	}
+
+	reopened, err := kv.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := reopened.Len(); n != 1 {
+		t.Errorf("snapshot holds %d links, want 1", n)
+	}
+	if got := sh.metrics.Get("redirects").String(); got != "1" {
+		t.Errorf("redirects metric == %s, want 1", got)
+	}
+}
@@ -0,0 +1,212 @@
+// Package fake generates plausible, reproducible fake data such as names,
+// email addresses and placeholder text, for fixtures, demos and mock
+// servers.
+//
+// A Faker with a given seed always produces the same sequence of values,
+// so fixtures built from it are stable across runs.
+package fake
+
+import "fmt"
+import "strings"
+import "sync"
+import "text/template"
+import "time"
+
+import "github.com/lukehedger/golib"
+
+// Faker generates fake data. It is safe for concurrent use, though
+// concurrent callers see the deterministic sequence in an unpredictable
+// order.
+type Faker struct {
+	mu sync.Mutex
+	r  *golib.Fast
+}
+
+// New returns a Faker seeded with seed.
+func New(seed uint64) *Faker {
+	return &Faker{r: golib.NewFast(seed)}
+}
+
+// Int returns a random integer in the inclusive range [min, max].
+func (f *Faker) Int(min, max int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.r.Int(min, max)
+}
+
+// Float returns a random number in [min, max).
+func (f *Faker) Float(min, max float64) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return min + (max-min)*f.r.Float64()
+}
+
+// Bool returns true or false with equal probability.
+func (f *Faker) Bool() bool { return f.Int(0, 1) == 1 }
+
+func (f *Faker) pick(words []string) string { return words[f.Int(0, len(words)-1)] }
+
+// FirstName returns a given name.
+func (f *Faker) FirstName() string { return f.pick(firstNames) }
+
+// LastName returns a family name.
+func (f *Faker) LastName() string { return f.pick(lastNames) }
+
+// Name returns a full name.
+func (f *Faker) Name() string { return f.FirstName() + " " + f.LastName() }
+
+// Username returns a lower-case handle such as "ada.lovelace42".
+func (f *Faker) Username() string {
+	return strings.ToLower(f.FirstName()+"."+f.LastName()) + fmt.Sprint(f.Int(1, 99))
+}
+
+// Email returns an address at one of the domains reserved for examples.
+func (f *Faker) Email() string {
+	return f.Username() + "@" + f.pick(domains)
+}
+
+// Company returns a company name.
+func (f *Faker) Company() string {
+	return f.LastName() + " " + f.pick(companySuffixes)
+}
+
+// Street returns a street address such as "42 Elm Street".
+func (f *Faker) Street() string {
+	return fmt.Sprintf("%d %s %s", f.Int(1, 999), f.pick(streetNames), f.pick(streetTypes))
+}
+
+// City returns a city name.
+func (f *Faker) City() string { return f.pick(cities) }
+
+// Country returns a country name.
+func (f *Faker) Country() string { return f.pick(countries) }
+
+// Phone returns a number in the range reserved for fiction, such as
+// "+1-555-0142".
+func (f *Faker) Phone() string { return fmt.Sprintf("+1-555-01%02d", f.Int(0, 99)) }
+
+// URL returns an https URL on an example domain.
+func (f *Faker) URL() string {
+	return "https://" + f.pick(domains) + "/" + f.Word()
+}
+
+// IPv4 returns an address from the documentation ranges of RFC 5737.
+func (f *Faker) IPv4() string {
+	return fmt.Sprintf("%s.%d", f.pick([]string{"192.0.2", "198.51.100", "203.0.113"}), f.Int(1, 254))
+}
+
+// UUID returns a random version 4 UUID.
+func (f *Faker) UUID() string {
+	f.mu.Lock()
+	b := f.r.Bytes(16)
+	f.mu.Unlock()
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// Time returns a random time in [from, to), truncated to the second.
+func (f *Faker) Time(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	d := time.Duration(f.Float(0, float64(to.Sub(from))))
+	return from.Add(d).Truncate(time.Second)
+}
+
+// Date returns a random date in 2000–2029, formatted as YYYY-MM-DD.
+func (f *Faker) Date() string {
+	return f.Time(minTime, maxTime).Format(time.DateOnly)
+}
+
+var (
+	minTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxTime = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Word returns a lorem ipsum word.
+func (f *Faker) Word() string { return f.pick(lorem) }
+
+// Words returns n lorem ipsum words separated by spaces.
+func (f *Faker) Words(n int) string {
+	ws := make([]string, n)
+	for i := range ws {
+		ws[i] = f.Word()
+	}
+	return strings.Join(ws, " ")
+}
+
+// Sentence returns a capitalised sentence of 4 to 12 words.
+func (f *Faker) Sentence() string {
+	s := f.Words(f.Int(4, 12))
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// Paragraph returns 3 to 6 sentences.
+func (f *Faker) Paragraph() string {
+	ss := make([]string, f.Int(3, 6))
+	for i := range ss {
+		ss[i] = f.Sentence()
+	}
+	return strings.Join(ss, " ")
+}
+
+// Funcs returns the generators as template functions, named as the
+// methods but in lower camel case: {{name}}, {{email}}, {{int 1 10}},
+// {{words 3}} and so on.
+func (f *Faker) Funcs() template.FuncMap {
+	return template.FuncMap{
+		"int":       f.Int,
+		"float":     f.Float,
+		"bool":      f.Bool,
+		"firstName": f.FirstName,
+		"lastName":  f.LastName,
+		"name":      f.Name,
+		"username":  f.Username,
+		"email":     f.Email,
+		"company":   f.Company,
+		"street":    f.Street,
+		"city":      f.City,
+		"country":   f.Country,
+		"phone":     f.Phone,
+		"url":       f.URL,
+		"ipv4":      f.IPv4,
+		"uuid":      f.UUID,
+		"date":      f.Date,
+		"word":      f.Word,
+		"words":     f.Words,
+		"sentence":  f.Sentence,
+		"paragraph": f.Paragraph,
+	}
+}
+
+var (
+	firstNames = []string{
+		"Ada", "Alan", "Barbara", "Brian", "Charles", "Claude", "Dennis", "Donald",
+		"Edsger", "Frances", "Grace", "Hedy", "Ivan", "Jean", "John", "Ken",
+		"Leslie", "Linus", "Margaret", "Niklaus", "Radia", "Rob", "Sophie", "Tim",
+	}
+	lastNames = []string{
+		"Allen", "Babbage", "Backus", "Berners-Lee", "Dijkstra", "Hamilton", "Hopper", "Johnson",
+		"Kay", "Kernighan", "Knuth", "Lamarr", "Lamport", "Liskov", "Lovelace", "McCarthy",
+		"Perlman", "Pike", "Ritchie", "Shannon", "Sutherland", "Thompson", "Turing", "Wilson",
+	}
+	domains         = []string{"example.com", "example.net", "example.org"}
+	companySuffixes = []string{"Inc.", "Ltd", "LLC", "Group", "& Co.", "Labs", "Systems"}
+	streetNames     = []string{"Oak", "Elm", "Maple", "Cedar", "Pine", "Church", "Mill", "Station", "Park", "High"}
+	streetTypes     = []string{"Street", "Road", "Avenue", "Lane", "Way", "Close"}
+	cities          = []string{
+		"Amsterdam", "Berlin", "Bristol", "Chicago", "Dublin", "Edinburgh", "Kyoto", "Lisbon",
+		"London", "Melbourne", "Montreal", "Nairobi", "Oslo", "Paris", "Seoul", "Toronto",
+	}
+	countries = []string{
+		"Argentina", "Australia", "Brazil", "Canada", "France", "Germany", "India", "Ireland",
+		"Japan", "Kenya", "Netherlands", "New Zealand", "Norway", "Portugal", "Spain", "United Kingdom",
+	}
+	lorem = []string{
+		"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit",
+		"sed", "do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et",
+		"dolore", "magna", "aliqua", "enim", "ad", "minim", "veniam", "quis",
+		"nostrud", "exercitation", "ullamco", "laboris", "nisi", "aliquip", "ex", "ea",
+	}
+)
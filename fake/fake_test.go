@@ -0,0 +1,74 @@
+package fake
+
+import "regexp"
+import "strings"
+import "testing"
+import "text/template"
+import "time"
+
+func TestDeterministic(t *testing.T) {
+	a, b := New(7), New(7)
+	for range 20 {
+		if x, y := a.Name()+a.Email()+a.UUID(), b.Name()+b.Email()+b.UUID(); x != y {
+			t.Fatalf("same seed gave %q and %q", x, y)
+		}
+	}
+	if New(1).Paragraph() == New(2).Paragraph() {
+		t.Error("different seeds gave the same paragraph")
+	}
+}
+
+func TestFormats(t *testing.T) {
+	f := New(1)
+	cases := []struct {
+		name string
+		gen  func() string
+		re   string
+	}{
+		{"Name", f.Name, `^[A-Z][a-z]+ [A-Z][a-zA-Z-]+$`},
+		{"Email", f.Email, `^[a-z-]+\.[a-z-]+\d{1,2}@example\.(com|net|org)$`},
+		{"Phone", f.Phone, `^\+1-555-01\d\d$`},
+		{"UUID", f.UUID, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{"IPv4", f.IPv4, `^(192\.0\.2|198\.51\.100|203\.0\.113)\.\d+$`},
+		{"Date", f.Date, `^20[0-2]\d-\d\d-\d\d$`},
+		{"Street", f.Street, `^\d+ [A-Z][a-z]+ [A-Z][a-z]+$`},
+		{"Sentence", f.Sentence, `^[A-Z][a-z]*( [a-z]+){3,11}\.$`},
+		{"URL", f.URL, `^https://example\.(com|net|org)/[a-z]+$`},
+	}
+	for _, c := range cases {
+		re := regexp.MustCompile(c.re)
+		for range 50 {
+			if s := c.gen(); !re.MatchString(s) {
+				t.Errorf("%s() == %q, want match for %s", c.name, s, c.re)
+				break
+			}
+		}
+	}
+	for range 100 {
+		if n := f.Int(3, 5); n < 3 || n > 5 {
+			t.Fatalf("Int(3, 5) == %d", n)
+		}
+		if x := f.Float(1, 2); x < 1 || x >= 2 {
+			t.Fatalf("Float(1, 2) == %v", x)
+		}
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got := f.Time(from, from.Add(time.Hour)); got.Before(from) || !got.Before(from.Add(time.Hour)) {
+		t.Errorf("Time() == %v", got)
+	}
+	if got := f.Time(from, from); !got.Equal(from) {
+		t.Errorf("Time(from, from) == %v", got)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("").Funcs(New(3).Funcs()).Parse(`{{name}} <{{email}}> is {{int 20 30}}: {{words 3}}`))
+	var b strings.Builder
+	if err := tmpl.Execute(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	re := regexp.MustCompile(`^[A-Z]\S+ \S+ <\S+@example\.\w+> is [23]\d: \w+ \w+ \w+$`)
+	if !re.MatchString(b.String()) {
+		t.Errorf("template output %q", b.String())
+	}
+}
@@ -0,0 +1,289 @@
+// Package files serves a directory tree over HTTP with conditional
+// requests, gzip compression and optional directory listings.
+package files
+
+import "compress/gzip"
+import "errors"
+import "fmt"
+import "html/template"
+import "io"
+import "io/fs"
+import "mime"
+import "net/http"
+import "net/url"
+import "os"
+import "path"
+import "sort"
+import "strings"
+import "time"
+
+// Options configure a file server.
+type Options struct {
+	// Index renders an HTML listing for directories that have no
+	// index.html.
+	Index bool
+	// Gzip compresses text-like responses for clients that accept it.
+	Gzip bool
+	// Hidden serves files and directories whose names begin with a dot,
+	// which are otherwise reported as not found.
+	Hidden bool
+}
+
+// Dir returns a handler serving the directory dir. It is opened with
+// os.OpenRoot, so requests cannot escape it through ".." elements or
+// symbolic links.
+func Dir(dir string, opts Options) (http.Handler, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	return Handler(root.FS(), opts), nil
+}
+
+// Handler returns a handler serving fsys.
+func Handler(fsys fs.FS, opts Options) http.Handler {
+	return &server{fsys: fsys, opts: opts}
+}
+
+type server struct {
+	fsys fs.FS
+	opts Options
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// path.Clean removes any ".." that would climb above the root.
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+	if !s.opts.Hidden && hidden(name) {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		s.error(w, r, err)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		s.error(w, r, err)
+		return
+	}
+
+	if fi.IsDir() {
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			http.Redirect(w, r, path.Base(r.URL.Path)+"/", http.StatusMovedPermanently)
+			return
+		}
+		index := path.Join(name, "index.html")
+		if fi, err := fs.Stat(s.fsys, index); err == nil && !fi.IsDir() {
+			s.serveFile(w, r, index)
+			return
+		}
+		if !s.opts.Index {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveListing(w, r, name, fi)
+		return
+	}
+	s.serveFile(w, r, name)
+}
+
+func hidden(name string) bool {
+	for _, elem := range strings.Split(name, "/") {
+		if strings.HasPrefix(elem, ".") && elem != "." {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *server) error(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		http.NotFound(w, r)
+	case errors.Is(err, fs.ErrPermission):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	default:
+		// os.Root reports escapes and invalid names with its own errors.
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+	}
+}
+
+func (s *server) serveFile(w http.ResponseWriter, r *http.Request, name string) {
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		s.error(w, r, err)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		s.error(w, r, err)
+		return
+	}
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		http.Error(w, "file is not seekable", http.StatusInternalServerError)
+		return
+	}
+
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype != "" {
+		w.Header().Set("Content-Type", ctype)
+	}
+	etag := fmt.Sprintf(`"%x-%x"`, fi.ModTime().UnixNano(), fi.Size())
+
+	if s.opts.Gzip && compressible(ctype) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if acceptsGzip(r) && r.Header.Get("Range") == "" && r.Method != http.MethodHead {
+			// The compressed variant needs its own validator.
+			w.Header().Set("ETag", strings.TrimSuffix(etag, `"`)+`-gzip"`)
+			gw := &gzipWriter{ResponseWriter: w}
+			defer gw.Close()
+			w = gw
+		} else {
+			w.Header().Set("ETag", etag)
+		}
+	} else {
+		w.Header().Set("ETag", etag)
+	}
+	// ServeContent handles If-None-Match, If-Modified-Since and Range.
+	http.ServeContent(w, r, name, fi.ModTime(), content)
+}
+
+func compressible(ctype string) bool {
+	media, _, _ := strings.Cut(ctype, ";")
+	switch media {
+	case "application/javascript", "application/json", "application/xml", "image/svg+xml", "text/javascript":
+		return true
+	}
+	return strings.HasPrefix(media, "text/")
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip.
+func acceptsGzip(r *http.Request) bool {
+	for _, v := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(v), ";")
+		if strings.TrimSpace(coding) != "gzip" {
+			continue
+		}
+		return strings.ReplaceAll(params, " ", "") != "q=0"
+	}
+	return false
+}
+
+// gzipWriter compresses successful response bodies. Other responses, such
+// as 304 Not Modified, pass through untouched.
+type gzipWriter struct {
+	http.ResponseWriter
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (g *gzipWriter) WriteHeader(code int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	if code == http.StatusOK {
+		h := g.Header()
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		g.gz = gzip.NewWriter(g.ResponseWriter)
+	}
+	g.ResponseWriter.WriteHeader(code)
+}
+
+func (g *gzipWriter) Write(p []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.gz == nil {
+		return g.ResponseWriter.Write(p)
+	}
+	return g.gz.Write(p)
+}
+
+func (g *gzipWriter) Close() error {
+	if g.gz == nil {
+		return nil
+	}
+	return g.gz.Close()
+}
+
+var listing = template.Must(template.New("listing").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Index of {{.Path}}</title></head>
+<body>
+<h1>Index of {{.Path}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Modified</th></tr>
+{{if ne .Path "/"}}<tr><td><a href="../">../</a></td><td></td><td></td></tr>
+{{end}}{{range .Entries}}<tr><td><a href="{{.Href}}">{{.Name}}</a></td><td>{{.Size}}</td><td>{{.Modified}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type entry struct {
+	Name, Href, Size, Modified string
+}
+
+func (s *server) serveListing(w http.ResponseWriter, r *http.Request, name string, dir fs.FileInfo) {
+	des, err := fs.ReadDir(s.fsys, name)
+	if err != nil {
+		s.error(w, r, err)
+		return
+	}
+	sort.Slice(des, func(i, j int) bool {
+		// Directories first, then by name.
+		if des[i].IsDir() != des[j].IsDir() {
+			return des[i].IsDir()
+		}
+		return des[i].Name() < des[j].Name()
+	})
+
+	var entries []entry
+	for _, de := range des {
+		if !s.opts.Hidden && strings.HasPrefix(de.Name(), ".") {
+			continue
+		}
+		fi, err := de.Info()
+		if err != nil {
+			continue
+		}
+		e := entry{
+			Name:     de.Name(),
+			Href:     (&url.URL{Path: de.Name()}).String(),
+			Modified: fi.ModTime().UTC().Format(time.DateTime),
+		}
+		if de.IsDir() {
+			e.Name += "/"
+			e.Href += "/"
+		} else {
+			e.Size = fmt.Sprint(fi.Size())
+		}
+		entries = append(entries, e)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Last-Modified", dir.ModTime().UTC().Format(http.TimeFormat))
+	if r.Method == http.MethodHead {
+		return
+	}
+	listing.Execute(w, struct {
+		Path    string
+		Entries []entry
+	}{r.URL.Path, entries})
+}
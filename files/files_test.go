@@ -0,0 +1,121 @@
+package files
+
+import "compress/gzip"
+import "io"
+import "net/http"
+import "net/http/httptest"
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+func setup(t *testing.T, opts Options) http.Handler {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("hello.txt", strings.Repeat("hello ", 100))
+	write("docs/index.html", "<h1>docs</h1>")
+	write("src/main.go", "package main")
+	write(".secret", "shh")
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	os.WriteFile(outside, []byte("outside"), 0o644)
+	if err := os.Symlink(outside, filepath.Join(dir, "link.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := Dir(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func get(h http.Handler, path string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.URL.Path = path
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServe(t *testing.T) {
+	h := setup(t, Options{Index: true})
+	cases := []struct {
+		path string
+		code int
+		want string // substring of the body
+	}{
+		{"/hello.txt", 200, "hello hello"},
+		{"/docs/", 200, "<h1>docs</h1>"},
+		{"/docs", 301, ""},
+		{"/src/", 200, `<a href="main.go">main.go</a>`},
+		{"/", 200, `<a href="docs/">docs/</a>`},
+		{"/missing", 404, ""},
+		{"/.secret", 404, ""},
+		{"/../../etc/passwd", 404, ""},
+		{"/link.txt", 400, ""},
+	}
+	for _, c := range cases {
+		rec := get(h, c.path)
+		if rec.Code != c.code || !strings.Contains(rec.Body.String(), c.want) {
+			t.Errorf("GET %s: %d %q, want %d containing %q", c.path, rec.Code, rec.Body.String(), c.code, c.want)
+		}
+	}
+	if strings.Contains(get(h, "/").Body.String(), ".secret") {
+		t.Error("listing shows hidden file")
+	}
+	if rec := get(setup(t, Options{}), "/src/"); rec.Code != 404 {
+		t.Errorf("listing without Index option: status %d, want 404", rec.Code)
+	}
+}
+
+func TestConditional(t *testing.T) {
+	h := setup(t, Options{})
+	rec := get(h, "/hello.txt")
+	etag, modified := rec.Header().Get("ETag"), rec.Header().Get("Last-Modified")
+	if etag == "" || modified == "" {
+		t.Fatalf("missing validators: ETag %q, Last-Modified %q", etag, modified)
+	}
+	if rec := get(h, "/hello.txt", "If-None-Match", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: status %d, want 304", rec.Code)
+	}
+	if rec := get(h, "/hello.txt", "If-Modified-Since", modified); rec.Code != http.StatusNotModified {
+		t.Errorf("If-Modified-Since: status %d, want 304", rec.Code)
+	}
+}
+
+func TestGzip(t *testing.T) {
+	h := setup(t, Options{Gzip: true})
+	rec := get(h, "/hello.txt", "Accept-Encoding", "br, gzip")
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding == %q, want gzip", rec.Header().Get("Content-Encoding"))
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(zr)
+	if string(body) != strings.Repeat("hello ", 100) {
+		t.Errorf("decompressed body == %q", body)
+	}
+	if rec.Body.Len() >= 600 {
+		t.Errorf("compressed body is %d bytes, want it smaller than the original", rec.Body.Len())
+	}
+
+	for _, accept := range []string{"", "gzip;q=0", "deflate"} {
+		rec := get(h, "/hello.txt", "Accept-Encoding", accept)
+		if rec.Header().Get("Content-Encoding") != "" || rec.Body.Len() != 600 {
+			t.Errorf("Accept-Encoding %q: got encoding %q, %d bytes", accept, rec.Header().Get("Content-Encoding"), rec.Body.Len())
+		}
+	}
+}
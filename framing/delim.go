@@ -0,0 +1,98 @@
+package framing
+
+import "bufio"
+import "bytes"
+import "errors"
+import "io"
+
+// ErrDelimInFrame is returned by DelimWriter.WriteFrame for a frame that
+// contains the delimiter.
+var ErrDelimInFrame = errors.New("framing: frame contains delimiter")
+
+// DelimReader reads frames terminated by a delimiter, such as "\n" for a
+// line-based protocol. Frames are returned without the delimiter.
+//
+// Data after the last delimiter is returned as a final frame, as
+// bufio.Scanner does for an unterminated last line. After
+// ErrFrameTooBig the rest of the oversized frame is discarded, so
+// reading can continue with the frame that follows.
+type DelimReader struct {
+	// MaxFrameSize limits the size of a frame, excluding the
+	// delimiter. Zero means DefaultMaxFrameSize.
+	MaxFrameSize int
+
+	r     *bufio.Reader
+	delim []byte
+}
+
+// NewDelimReader returns a DelimReader reading frames from r terminated
+// by delim. It panics if delim is empty.
+func NewDelimReader(r io.Reader, delim string) *DelimReader {
+	if delim == "" {
+		panic("framing: empty delimiter")
+	}
+	return &DelimReader{r: bufio.NewReader(r), delim: []byte(delim)}
+}
+
+// ReadFrame returns the next frame in a newly allocated slice.
+func (d *DelimReader) ReadFrame() ([]byte, error) {
+	limit := d.MaxFrameSize
+	if limit <= 0 {
+		limit = DefaultMaxFrameSize
+	}
+	last := d.delim[len(d.delim)-1]
+	var frame []byte
+	tooBig := false
+	for {
+		chunk, err := d.r.ReadSlice(last)
+		frame = append(frame, chunk...)
+		end := err == nil && bytes.HasSuffix(frame, d.delim)
+		if end {
+			frame = frame[:len(frame)-len(d.delim)]
+		}
+		if len(frame) > limit && !tooBig {
+			tooBig = true
+		}
+		if tooBig && !end {
+			// Keep just enough to spot a delimiter split across reads.
+			frame = append(frame[:0], frame[max(len(frame)-len(d.delim), 0):]...)
+		}
+		switch {
+		case end || err == io.EOF && (tooBig || len(frame) > 0):
+			if tooBig {
+				return nil, ErrFrameTooBig
+			}
+			return frame, nil
+		case err != nil && err != bufio.ErrBufferFull:
+			return nil, err
+		}
+	}
+}
+
+// DelimWriter writes frames followed by a delimiter.
+type DelimWriter struct {
+	w     io.Writer
+	delim []byte
+	buf   []byte
+}
+
+// NewDelimWriter returns a DelimWriter writing frames to w terminated by
+// delim. It panics if delim is empty.
+func NewDelimWriter(w io.Writer, delim string) *DelimWriter {
+	if delim == "" {
+		panic("framing: empty delimiter")
+	}
+	return &DelimWriter{w: w, delim: []byte(delim)}
+}
+
+// WriteFrame writes p and the delimiter in a single call to the
+// underlying writer. It returns ErrDelimInFrame if p contains the
+// delimiter, since the frame could not be read back intact.
+func (d *DelimWriter) WriteFrame(p []byte) error {
+	if bytes.Contains(p, d.delim) {
+		return ErrDelimInFrame
+	}
+	d.buf = append(append(d.buf[:0], p...), d.delim...)
+	_, err := d.w.Write(d.buf)
+	return err
+}
@@ -0,0 +1,98 @@
+package framing
+
+import "bytes"
+import "io"
+import "reflect"
+import "strings"
+import "testing"
+
+func readAll(t *testing.T, r Reader) ([]string, error) {
+	t.Helper()
+	var frames []string
+	for {
+		f, err := r.ReadFrame()
+		if err == io.EOF {
+			return frames, nil
+		}
+		if err != nil {
+			return frames, err
+		}
+		frames = append(frames, string(f))
+	}
+}
+
+func TestDelimReader(t *testing.T) {
+	tests := []struct {
+		input, delim string
+		want         []string
+	}{
+		{"a\nb\n", "\n", []string{"a", "b"}},
+		{"a\n\nb", "\n", []string{"a", "", "b"}},
+		{"", "\n", nil},
+		{"one\r\ntwo\r\n", "\r\n", []string{"one", "two"}},
+		{"a\rb\r\nc", "\r\n", []string{"a\rb", "c"}},
+		{"x--y----z", "--", []string{"x", "y", "", "z"}},
+	}
+	for _, tt := range tests {
+		got, err := readAll(t, NewDelimReader(strings.NewReader(tt.input), tt.delim))
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q split on %q == %q, %v, want %q", tt.input, tt.delim, got, err, tt.want)
+		}
+	}
+}
+
+func TestDelimReaderTooBig(t *testing.T) {
+	long := strings.Repeat("x", 10000)
+	tests := []struct {
+		input string
+		max   int
+		want  []string
+	}{
+		{"abcd\nabcde\nab\n", 4, []string{"abcd", "!", "ab"}},
+		{long + "\r\nok\r\n", 100, []string{"!", "ok"}},
+		{"ok\n" + long, 100, []string{"ok", "!"}},
+		{long + "\n", 0, []string{long}},
+	}
+	for _, tt := range tests {
+		delim := "\n"
+		if strings.Contains(tt.input, "\r\n") {
+			delim = "\r\n"
+		}
+		r := NewDelimReader(strings.NewReader(tt.input), delim)
+		r.MaxFrameSize = tt.max
+		var got []string
+		for {
+			f, err := r.ReadFrame()
+			if err == io.EOF {
+				break
+			}
+			if err == ErrFrameTooBig {
+				got = append(got, "!")
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, string(f))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("max %d: read %.20q, want %.20q", tt.max, got, tt.want)
+		}
+	}
+}
+
+func TestDelimWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewDelimWriter(&buf, "\r\n")
+	for _, f := range []string{"one", "", "two\n"} {
+		if err := w.WriteFrame([]byte(f)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := buf.String(), "one\r\n\r\ntwo\n\r\n"; got != want {
+		t.Errorf("wrote %q, want %q", got, want)
+	}
+	if err := w.WriteFrame([]byte("a\r\nb")); err != ErrDelimInFrame {
+		t.Errorf("frame containing delimiter: err == %v, want ErrDelimInFrame", err)
+	}
+}
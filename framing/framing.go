@@ -0,0 +1,87 @@
+// Package framing splits a byte stream into discrete messages, or frames,
+// for protocols built directly on a net.Conn or other io.ReadWriter.
+//
+// Frames are either prefixed with their length (LengthReader and
+// LengthWriter) or terminated by a delimiter such as a newline
+// (DelimReader and DelimWriter). Readers refuse frames longer than a
+// maximum size, so that a peer cannot make them allocate without bound.
+// An Encoder and Decoder carry values over either kind of frame using a
+// Codec.
+package framing
+
+import "encoding/json"
+import "errors"
+
+// DefaultMaxFrameSize bounds incoming frames unless a reader's
+// MaxFrameSize says otherwise.
+const DefaultMaxFrameSize = 1 << 20
+
+// ErrFrameTooBig is returned when a frame exceeds the maximum size a
+// reader accepts or the largest length its prefix can hold.
+var ErrFrameTooBig = errors.New("framing: frame too big")
+
+// A Reader reads one frame at a time. ReadFrame returns io.EOF when the
+// stream ends cleanly between frames, and io.ErrUnexpectedEOF when it
+// ends part way through one.
+type Reader interface {
+	ReadFrame() ([]byte, error)
+}
+
+// A Writer writes one frame at a time.
+type Writer interface {
+	WriteFrame(p []byte) error
+}
+
+// A Codec converts values to and from frame payloads.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSON is a Codec that encodes values as JSON.
+var JSON Codec = jsonCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// An Encoder writes values as frames.
+type Encoder struct {
+	w Writer
+	c Codec
+}
+
+// NewEncoder returns an Encoder that writes to w using c.
+func NewEncoder(w Writer, c Codec) *Encoder {
+	return &Encoder{w, c}
+}
+
+// Encode writes v as a single frame.
+func (e *Encoder) Encode(v any) error {
+	p, err := e.c.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return e.w.WriteFrame(p)
+}
+
+// A Decoder reads values from frames.
+type Decoder struct {
+	r Reader
+	c Codec
+}
+
+// NewDecoder returns a Decoder that reads from r using c.
+func NewDecoder(r Reader, c Codec) *Decoder {
+	return &Decoder{r, c}
+}
+
+// Decode reads the next frame and stores its value in v.
+func (d *Decoder) Decode(v any) error {
+	p, err := d.r.ReadFrame()
+	if err != nil {
+		return err
+	}
+	return d.c.Unmarshal(p, v)
+}
@@ -0,0 +1,43 @@
+package framing
+
+import "bytes"
+import "io"
+import "testing"
+
+type message struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+func TestCodec(t *testing.T) {
+	var buf bytes.Buffer
+	pairs := []struct {
+		name string
+		w    Writer
+		r    func() Reader
+	}{
+		{"length", NewLengthWriter(&buf), func() Reader { return NewLengthReader(&buf) }},
+		{"delim", NewDelimWriter(&buf, "\n"), func() Reader { return NewDelimReader(&buf, "\n") }},
+	}
+	msgs := []message{{1, "hello"}, {2, "line\nbreak"}}
+	for _, p := range pairs {
+		buf.Reset()
+		enc := NewEncoder(p.w, JSON)
+		for _, m := range msgs {
+			if err := enc.Encode(m); err != nil {
+				t.Fatalf("%s: Encode: %v", p.name, err)
+			}
+		}
+		dec := NewDecoder(p.r(), JSON)
+		for _, want := range msgs {
+			var got message
+			if err := dec.Decode(&got); err != nil || got != want {
+				t.Errorf("%s: Decode == %+v, %v, want %+v", p.name, got, err, want)
+			}
+		}
+		var m message
+		if err := dec.Decode(&m); err != io.EOF {
+			t.Errorf("%s: Decode at end: err == %v, want io.EOF", p.name, err)
+		}
+	}
+}
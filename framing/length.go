@@ -0,0 +1,137 @@
+package framing
+
+import "encoding/binary"
+import "fmt"
+import "io"
+
+// LengthReader reads frames that start with their length as an unsigned
+// integer of PrefixSize bytes.
+//
+// After ErrFrameTooBig the reader is no longer aligned to a frame
+// boundary, and the stream should be abandoned.
+type LengthReader struct {
+	// PrefixSize is the size of the length prefix in bytes: 1, 2, 4 or
+	// 8. Zero means 4.
+	PrefixSize int
+	// Order is the byte order of the prefix. Nil means big-endian.
+	Order binary.ByteOrder
+	// MaxFrameSize limits the size of a frame. Zero means
+	// DefaultMaxFrameSize.
+	MaxFrameSize int
+
+	r   io.Reader
+	hdr [8]byte
+}
+
+// NewLengthReader returns a LengthReader reading from r with 4-byte
+// big-endian prefixes.
+func NewLengthReader(r io.Reader) *LengthReader {
+	return &LengthReader{r: r}
+}
+
+// ReadFrame returns the next frame in a newly allocated slice.
+func (l *LengthReader) ReadFrame() ([]byte, error) {
+	size, err := prefixSize(l.PrefixSize)
+	if err != nil {
+		return nil, err
+	}
+	hdr := l.hdr[:size]
+	if _, err := io.ReadFull(l.r, hdr); err != nil {
+		return nil, err
+	}
+	n := decodeLength(byteOrder(l.Order), hdr)
+	limit := l.MaxFrameSize
+	if limit <= 0 {
+		limit = DefaultMaxFrameSize
+	}
+	if n > uint64(limit) {
+		return nil, ErrFrameTooBig
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(l.r, p); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// LengthWriter writes frames that start with their length, in the format
+// read by a LengthReader with the same PrefixSize and Order.
+type LengthWriter struct {
+	// PrefixSize is the size of the length prefix in bytes: 1, 2, 4 or
+	// 8. Zero means 4.
+	PrefixSize int
+	// Order is the byte order of the prefix. Nil means big-endian.
+	Order binary.ByteOrder
+
+	w   io.Writer
+	buf []byte
+}
+
+// NewLengthWriter returns a LengthWriter writing to w with 4-byte
+// big-endian prefixes.
+func NewLengthWriter(w io.Writer) *LengthWriter {
+	return &LengthWriter{w: w}
+}
+
+// WriteFrame writes p with its length prefix in a single call to the
+// underlying writer. It returns ErrFrameTooBig if the prefix cannot hold
+// len(p).
+func (l *LengthWriter) WriteFrame(p []byte) error {
+	size, err := prefixSize(l.PrefixSize)
+	if err != nil {
+		return err
+	}
+	if size < 8 && uint64(len(p)) >= 1<<(8*size) {
+		return ErrFrameTooBig
+	}
+	l.buf = append(l.buf[:0], make([]byte, size)...)
+	encodeLength(byteOrder(l.Order), l.buf, uint64(len(p)))
+	l.buf = append(l.buf, p...)
+	_, err = l.w.Write(l.buf)
+	return err
+}
+
+func prefixSize(n int) (int, error) {
+	switch n {
+	case 0:
+		return 4, nil
+	case 1, 2, 4, 8:
+		return n, nil
+	}
+	return 0, fmt.Errorf("framing: bad prefix size %d", n)
+}
+
+func byteOrder(o binary.ByteOrder) binary.ByteOrder {
+	if o == nil {
+		return binary.BigEndian
+	}
+	return o
+}
+
+func decodeLength(o binary.ByteOrder, b []byte) uint64 {
+	switch len(b) {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(o.Uint16(b))
+	case 4:
+		return uint64(o.Uint32(b))
+	}
+	return o.Uint64(b)
+}
+
+func encodeLength(o binary.ByteOrder, b []byte, n uint64) {
+	switch len(b) {
+	case 1:
+		b[0] = byte(n)
+	case 2:
+		o.PutUint16(b, uint16(n))
+	case 4:
+		o.PutUint32(b, uint32(n))
+	default:
+		o.PutUint64(b, n)
+	}
+}
@@ -0,0 +1,88 @@
+package framing
+
+import "bytes"
+import "encoding/binary"
+import "errors"
+import "io"
+import "reflect"
+import "testing"
+
+func TestLengthRoundTrip(t *testing.T) {
+	frames := [][]byte{[]byte("hello"), {}, bytes.Repeat([]byte("x"), 300)}
+	for _, size := range []int{0, 2, 4, 8} {
+		for _, order := range []binary.ByteOrder{nil, binary.LittleEndian} {
+			var buf bytes.Buffer
+			w := NewLengthWriter(&buf)
+			w.PrefixSize, w.Order = size, order
+			for _, f := range frames {
+				if err := w.WriteFrame(f); err != nil {
+					t.Fatalf("size %d: WriteFrame: %v", size, err)
+				}
+			}
+			r := NewLengthReader(&buf)
+			r.PrefixSize, r.Order = size, order
+			var got [][]byte
+			for {
+				f, err := r.ReadFrame()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("size %d: ReadFrame: %v", size, err)
+				}
+				got = append(got, f)
+			}
+			if !reflect.DeepEqual(got, frames) {
+				t.Errorf("size %d, order %v: read %q, want %q", size, order, got, frames)
+			}
+		}
+	}
+}
+
+func TestLengthFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewLengthWriter(&buf).WriteFrame([]byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.Bytes(), []byte{0, 0, 0, 2, 'h', 'i'}; !bytes.Equal(got, want) {
+		t.Errorf("wrote % x, want % x", got, want)
+	}
+}
+
+func TestLengthErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		max   int
+		err   error
+	}{
+		{"empty", nil, 0, io.EOF},
+		{"short prefix", []byte{0, 0}, 0, io.ErrUnexpectedEOF},
+		{"short body", []byte{0, 0, 0, 5, 'a'}, 0, io.ErrUnexpectedEOF},
+		{"missing body", []byte{0, 0, 0, 5}, 0, io.ErrUnexpectedEOF},
+		{"too big", []byte{0, 0, 0, 5, 'a', 'b', 'c', 'd', 'e'}, 4, ErrFrameTooBig},
+		{"default max", []byte{0xff, 0xff, 0xff, 0xff}, 0, ErrFrameTooBig},
+	}
+	for _, tt := range tests {
+		r := NewLengthReader(bytes.NewReader(tt.input))
+		r.MaxFrameSize = tt.max
+		if _, err := r.ReadFrame(); !errors.Is(err, tt.err) {
+			t.Errorf("%s: err == %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestLengthWriterLimits(t *testing.T) {
+	w := NewLengthWriter(io.Discard)
+	w.PrefixSize = 1
+	if err := w.WriteFrame(make([]byte, 255)); err != nil {
+		t.Errorf("255-byte frame with 1-byte prefix: %v", err)
+	}
+	if err := w.WriteFrame(make([]byte, 256)); err != ErrFrameTooBig {
+		t.Errorf("256-byte frame with 1-byte prefix: err == %v, want ErrFrameTooBig", err)
+	}
+	w.PrefixSize = 3
+	if err := w.WriteFrame(nil); err == nil {
+		t.Errorf("3-byte prefix accepted")
+	}
+}
@@ -0,0 +1,129 @@
+package golib
+
+import "fmt"
+import "sync"
+
+// Group deduplicates concurrent calls: callers of Do with the same key
+// while a call for it is running wait for that call and share its result
+// rather than making their own. Unlike Memoize, nothing is kept once the
+// call returns, so the next Do runs fn again. The zero value is ready to
+// use.
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*groupCall[V]
+}
+
+// GroupResult is the result of a call delivered by Group.DoChan.
+type GroupResult[V any] struct {
+	Val    V
+	Err    error
+	Shared bool // whether the result went to more than one caller
+}
+
+type groupCall[V any] struct {
+	done   chan struct{}
+	val    V
+	err    error
+	panic  any // the value fn panicked with, if it did
+	dups   int
+	chans  []chan<- GroupResult[V]
+	forgot bool
+}
+
+// Do runs fn for key and returns its result, unless a call for key is
+// already running, in which case it waits for that call and returns its
+// result. shared reports whether the result went to more than one caller.
+// If fn panics, every caller waiting in Do panics with the same value.
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		<-c.done
+		if c.panic != nil {
+			panic(c.panic)
+		}
+		return c.val, c.err, true
+	}
+	c := g.start(key)
+	g.mu.Unlock()
+
+	g.run(key, c, fn)
+	if c.panic != nil {
+		panic(c.panic)
+	}
+	return c.val, c.err, c.dups > 0
+}
+
+// DoChan is like Do but returns at once, with a channel that receives the
+// result. If fn panics, the panic is not recovered, as for a go statement.
+func (g *Group[K, V]) DoChan(key K, fn func() (V, error)) <-chan GroupResult[V] {
+	ch := make(chan GroupResult[V], 1)
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		c.chans = append(c.chans, ch)
+		g.mu.Unlock()
+		return ch
+	}
+	c := g.start(key)
+	c.chans = append(c.chans, ch)
+	g.mu.Unlock()
+
+	go func() {
+		g.run(key, c, fn)
+		if c.panic != nil {
+			panic(c.panic)
+		}
+	}()
+	return ch
+}
+
+// Forget makes the next Do or DoChan for key run fn again rather than
+// join a call already running. Callers already waiting still get that
+// call's result.
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c, ok := g.calls[key]; ok {
+		c.forgot = true
+		delete(g.calls, key)
+	}
+}
+
+// start records a new call for key. g.mu must be held.
+func (g *Group[K, V]) start(key K) *groupCall[V] {
+	c := &groupCall[V]{done: make(chan struct{})}
+	if g.calls == nil {
+		g.calls = map[K]*groupCall[V]{}
+	}
+	g.calls[key] = c
+	return c
+}
+
+// run calls fn and hands its result, or the value it panicked with, to
+// every caller waiting on c.
+func (g *Group[K, V]) run(key K, c *groupCall[V], fn func() (V, error)) {
+	defer func() {
+		if p := recover(); p != nil {
+			c.panic = p
+			if err, ok := p.(error); ok {
+				c.err = fmt.Errorf("golib: Group call panicked: %w", err)
+			} else {
+				c.err = fmt.Errorf("golib: Group call panicked: %v", p)
+			}
+		}
+		g.mu.Lock()
+		if !c.forgot {
+			delete(g.calls, key)
+		}
+		chans := c.chans
+		shared := c.dups > 0
+		g.mu.Unlock()
+		close(c.done)
+		for _, ch := range chans {
+			ch <- GroupResult[V]{c.val, c.err, shared}
+		}
+	}()
+	c.val, c.err = fn()
+}
@@ -0,0 +1,89 @@
+package golib
+
+import "errors"
+import "sync"
+import "sync/atomic"
+import "testing"
+import "time"
+
+func TestGroupDo(t *testing.T) {
+	var g Group[string, int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, s := g.Do("k", fn)
+			if v != 42 || err != nil {
+				t.Errorf("Do = %d, %v", v, err)
+			}
+			if s {
+				shared.Add(1)
+			}
+		}()
+	}
+	for waiting := 0; waiting < 4; time.Sleep(time.Millisecond) {
+		g.mu.Lock()
+		if c := g.calls["k"]; c != nil {
+			waiting = c.dups
+		}
+		g.mu.Unlock()
+	}
+	close(release)
+	wg.Wait()
+	if calls.Load() != 1 || shared.Load() != 5 {
+		t.Errorf("%d calls, %d shared", calls.Load(), shared.Load())
+	}
+
+	// Nothing is kept: a later Do calls fn again.
+	if _, _, s := g.Do("k", fn); s || calls.Load() != 2 {
+		t.Errorf("result kept after the call returned")
+	}
+}
+
+func TestGroupDoChan(t *testing.T) {
+	var g Group[int, string]
+	boom := errors.New("boom")
+	release := make(chan struct{})
+	a := g.DoChan(1, func() (string, error) { <-release; return "", boom })
+	b := g.DoChan(1, func() (string, error) { t.Error("second fn ran"); return "", nil })
+	close(release)
+	for _, ch := range []<-chan GroupResult[string]{a, b} {
+		if r := <-ch; !errors.Is(r.Err, boom) || !r.Shared {
+			t.Errorf("result %+v", r)
+		}
+	}
+}
+
+func TestGroupForget(t *testing.T) {
+	var g Group[string, int]
+	release := make(chan struct{})
+	first := g.DoChan("k", func() (int, error) { <-release; return 1, nil })
+	g.Forget("k")
+	if v, _, _ := g.Do("k", func() (int, error) { return 2, nil }); v != 2 {
+		t.Errorf("Do after Forget = %d", v)
+	}
+	close(release)
+	if r := <-first; r.Val != 1 {
+		t.Errorf("forgotten call returned %d", r.Val)
+	}
+}
+
+func TestGroupPanic(t *testing.T) {
+	var g Group[string, int]
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Errorf("recovered %v", p)
+		}
+	}()
+	g.Do("k", func() (int, error) { panic("boom") })
+}
@@ -0,0 +1,85 @@
+// Package hashx computes the common checksums of strings, byte slices,
+// readers and files in one call, in the hexadecimal form that is usually
+// wanted, rather than through a hash.Hash, a copy and an encoding each
+// time. It also signs and verifies messages and expiring tokens with
+// HMAC.
+//
+// MD5 is here for checking against systems that still use it; it is not
+// safe against deliberate collisions.
+package hashx
+
+import "crypto/md5"
+import "crypto/sha256"
+import "crypto/sha512"
+import "encoding/hex"
+import "fmt"
+import "hash"
+import "hash/crc32"
+import "io"
+import "os"
+
+// Data is what the hash functions take: a string or a byte slice.
+type Data interface {
+	~string | ~[]byte
+}
+
+// SHA256Hex returns the SHA-256 of data in hexadecimal.
+func SHA256Hex[T Data](data T) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// SHA512Hex returns the SHA-512 of data in hexadecimal.
+func SHA512Hex[T Data](data T) string {
+	sum := sha512.Sum512([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// MD5Hex returns the MD5 of data in hexadecimal.
+func MD5Hex[T Data](data T) string {
+	sum := md5.Sum([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// CRC32 returns the IEEE CRC-32 of data, as used by gzip and zip.
+func CRC32[T Data](data T) uint32 {
+	return crc32.ChecksumIEEE([]byte(data))
+}
+
+// SHA256Reader returns the SHA-256 of everything read from r, in
+// hexadecimal. It streams, so r may be larger than memory.
+func SHA256Reader(r io.Reader) (string, error) { return hexReader(sha256.New(), r) }
+
+// SHA512Reader returns the SHA-512 of everything read from r, in
+// hexadecimal.
+func SHA512Reader(r io.Reader) (string, error) { return hexReader(sha512.New(), r) }
+
+// MD5Reader returns the MD5 of everything read from r, in hexadecimal.
+func MD5Reader(r io.Reader) (string, error) { return hexReader(md5.New(), r) }
+
+// CRC32Reader returns the IEEE CRC-32 of everything read from r.
+func CRC32Reader(r io.Reader) (uint32, error) {
+	h := crc32.NewIEEE()
+	if _, err := io.Copy(h, r); err != nil {
+		return 0, fmt.Errorf("hashx: %w", err)
+	}
+	return h.Sum32(), nil
+}
+
+func hexReader(h hash.Hash, r io.Reader) (string, error) {
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("hashx: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFile returns the SHA-256 of the file at path in hexadecimal, the
+// same as sha256sum(1) prints.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return SHA256Reader(f)
+}
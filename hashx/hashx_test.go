@@ -0,0 +1,73 @@
+package hashx
+
+import "errors"
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+import "testing/iotest"
+
+func TestHex(t *testing.T) {
+	cases := []struct {
+		name      string
+		fn        func(string) string
+		empty, in string
+	}{
+		{"SHA256Hex", SHA256Hex[string],
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+			"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"SHA512Hex", SHA512Hex[string],
+			"cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e",
+			"9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"},
+		{"MD5Hex", MD5Hex[string],
+			"d41d8cd98f00b204e9800998ecf8427e",
+			"5d41402abc4b2a76b9719d911017c592"},
+	}
+	for _, c := range cases {
+		if got := c.fn(""); got != c.empty {
+			t.Errorf("%s(\"\") = %s", c.name, got)
+		}
+		if got := c.fn("hello"); got != c.in {
+			t.Errorf("%s(hello) = %s", c.name, got)
+		}
+	}
+	if SHA256Hex([]byte("hello")) != SHA256Hex("hello") {
+		t.Error("bytes and string differ")
+	}
+	if got := CRC32("hello"); got != 0x3610a686 {
+		t.Errorf("CRC32(hello) = %#x", got)
+	}
+}
+
+func TestReader(t *testing.T) {
+	s := strings.Repeat("golib ", 10000)
+	if got, err := SHA256Reader(strings.NewReader(s)); got != SHA256Hex(s) || err != nil {
+		t.Errorf("SHA256Reader = %s, %v", got, err)
+	}
+	if got, err := SHA512Reader(strings.NewReader(s)); got != SHA512Hex(s) || err != nil {
+		t.Errorf("SHA512Reader = %s, %v", got, err)
+	}
+	if got, err := MD5Reader(strings.NewReader(s)); got != MD5Hex(s) || err != nil {
+		t.Errorf("MD5Reader = %s, %v", got, err)
+	}
+	if got, err := CRC32Reader(strings.NewReader(s)); got != CRC32(s) || err != nil {
+		t.Errorf("CRC32Reader = %#x, %v", got, err)
+	}
+	boom := errors.New("boom")
+	if _, err := SHA256Reader(iotest.ErrReader(boom)); !errors.Is(err, boom) {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := HashFile(path); got != SHA256Hex("hello") || err != nil {
+		t.Errorf("HashFile = %s, %v", got, err)
+	}
+	if _, err := HashFile(path + "x"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package hashx
+
+import "encoding/hex"
+import "errors"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+func TestHMAC(t *testing.T) {
+	// RFC 4231, test case 2.
+	key, msg := []byte("Jefe"), []byte("what do ya want for nothing?")
+	want := map[Algo]string{
+		SHA256: "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		SHA512: "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737",
+		SHA1:   "effcdf6ae5eb2fa2d27416d5f184df9c259a7c79",
+	}
+	for algo, w := range want {
+		mac := HMACSign(key, msg, algo)
+		if hex.EncodeToString(mac) != w {
+			t.Errorf("%v: %x", algo, mac)
+		}
+		if !HMACVerify(key, msg, mac, algo) {
+			t.Errorf("%v: own MAC rejected", algo)
+		}
+		mac[0] ^= 1
+		if HMACVerify(key, msg, mac, algo) {
+			t.Errorf("%v: altered MAC accepted", algo)
+		}
+	}
+	if HMACVerify(key, msg, HMACSign(key, msg, SHA512), SHA256) {
+		t.Error("MAC of another algorithm accepted")
+	}
+}
+
+func TestTimestampedToken(t *testing.T) {
+	clk := clock.NewFake(time.Unix(1700000000, 0))
+	tt := TimestampedToken{Key: []byte("secret"), Clock: clk}
+	tok := tt.Sign("user.42", time.Hour)
+	if strings.ContainsAny(tok, "+/=") {
+		t.Errorf("token %q is not URL-safe", tok)
+	}
+	if p, err := tt.Verify(tok); p != "user.42" || err != nil {
+		t.Errorf("Verify = %q, %v", p, err)
+	}
+
+	other := TimestampedToken{Key: []byte("other"), Clock: clk}
+	if _, err := other.Verify(tok); !errors.Is(err, ErrSignature) {
+		t.Errorf("wrong key: %v", err)
+	}
+	i := strings.IndexByte(tok, '.')
+	forged := tok[:i] + ".9999999999" + tok[strings.LastIndexByte(tok, '.'):]
+	if _, err := tt.Verify(forged); !errors.Is(err, ErrSignature) {
+		t.Errorf("extended expiry: %v", err)
+	}
+	for _, bad := range []string{"", "abc", "abc.!!"} {
+		if _, err := tt.Verify(bad); !errors.Is(err, ErrMalformed) {
+			t.Errorf("Verify(%q) = %v", bad, err)
+		}
+	}
+
+	clk.Advance(time.Hour)
+	if _, err := tt.Verify(tok); !errors.Is(err, ErrExpired) {
+		t.Errorf("after an hour: %v", err)
+	}
+}
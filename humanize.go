@@ -0,0 +1,124 @@
+package golib
+
+import "fmt"
+import "math"
+import "strconv"
+import "strings"
+import "unicode"
+
+// countSuffixes are the short-scale suffixes used by HumanizeInt.
+var countSuffixes = []string{"", "K", "M", "B", "T", "Q"}
+
+// HumanizeInt abbreviates n to at most one decimal place with a K, M, B
+// (billion), T or Q suffix: 1234567 becomes "1.2M" and 1000 becomes "1K".
+// Values below 1000 are returned in full.
+func HumanizeInt(n int64) string {
+	if n > -1000 && n < 1000 {
+		return strconv.FormatInt(n, 10)
+	}
+	return humanize(float64(n), 1000, countSuffixes, "")
+}
+
+// HumanizeBytes formats a byte count with IEC binary units, so that 1536
+// becomes "1.5 KiB". See HumanizeBytesSI for decimal units.
+func HumanizeBytes(n int64) string {
+	if n > -1024 && n < 1024 {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	return humanize(float64(n), 1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}, " ")
+}
+
+// HumanizeBytesSI formats a byte count with SI decimal units, as disk
+// and network vendors do, so that 1500 becomes "1.5 kB".
+func HumanizeBytesSI(n int64) string {
+	if n > -1000 && n < 1000 {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	return humanize(float64(n), 1000, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}, " ")
+}
+
+// humanize scales v down by base until it is below base and formats it
+// with one decimal place, dropping a trailing ".0".
+func humanize(v, base float64, suffixes []string, sep string) string {
+	i := 0
+	for math.Abs(v) >= base && i < len(suffixes)-1 {
+		v /= base
+		i++
+	}
+	// Rounding can carry into the next unit: 999.96K is 1M, not 1000K.
+	if math.Abs(math.Round(v*10)/10) >= base && i < len(suffixes)-1 {
+		v /= base
+		i++
+	}
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0") + sep + suffixes[i]
+}
+
+// ParseHumanInt parses an abbreviated count as written by HumanizeInt,
+// such as "1.2M" or "15k". Suffixes are case-insensitive.
+func ParseHumanInt(s string) (int64, error) {
+	num, unit := splitUnit(s)
+	mult := -1.0
+	for i, suf := range countSuffixes {
+		if strings.EqualFold(unit, suf) {
+			mult = math.Pow(1000, float64(i))
+		}
+	}
+	if mult < 0 {
+		return 0, fmt.Errorf("golib: unknown suffix %q in %q", unit, s)
+	}
+	return scaleNumber(s, num, mult)
+}
+
+// byteUnits maps lower-cased unit names to their sizes. Both "k" and "kB"
+// mean 1000, as in SI; "KiB" means 1024.
+var byteUnits = map[string]float64{
+	"": 1, "b": 1,
+	"k": 1e3, "kb": 1e3, "m": 1e6, "mb": 1e6, "g": 1e9, "gb": 1e9,
+	"t": 1e12, "tb": 1e12, "p": 1e15, "pb": 1e15, "e": 1e18, "eb": 1e18,
+	"kib": 1 << 10, "mib": 1 << 20, "gib": 1 << 30,
+	"tib": 1 << 40, "pib": 1 << 50, "eib": 1 << 60,
+}
+
+// ParseBytes parses a byte size such as "2GB", "1.5 KiB" or "512".
+// Decimal units (kB, MB, ...) are powers of 1000 and binary units (KiB,
+// MiB, ...) powers of 1024. Units are case-insensitive.
+func ParseBytes(s string) (int64, error) {
+	num, unit := splitUnit(s)
+	mult, ok := byteUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("golib: unknown byte unit %q in %q", unit, s)
+	}
+	return scaleNumber(s, num, mult)
+}
+
+// splitUnit splits s into its leading number and trailing unit, trimming
+// the space between them.
+func splitUnit(s string) (num, unit string) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.' && r != '-' && r != '+'
+	})
+	if i < 0 {
+		return s, ""
+	}
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i:])
+}
+
+func scaleNumber(s, num string, mult float64) (int64, error) {
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("golib: invalid number in %q", s)
+	}
+	v = math.Round(v * mult)
+	if v >= math.MaxInt64 || v < math.MinInt64 {
+		return 0, fmt.Errorf("golib: %q overflows int64", s)
+	}
+	// Parse integers exactly, since float64 loses precision above 2^53.
+	if mult == 1 {
+		if i, err := strconv.ParseInt(num, 10, 64); err == nil {
+			return i, nil
+		}
+	}
+	return int64(v), nil
+}
@@ -0,0 +1,103 @@
+package golib
+
+import "math"
+import "testing"
+
+func TestHumanizeInt(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{-999, "-999"},
+		{1000, "1K"},
+		{1234, "1.2K"},
+		{1234567, "1.2M"},
+		{-1500000, "-1.5M"},
+		{999960, "1M"},
+		{2500000000, "2.5B"},
+		{math.MaxInt64, "9223.4Q"},
+	}
+	for _, c := range cases {
+		if got := HumanizeInt(c.n); got != c.want {
+			t.Errorf("HumanizeInt(%d) == %q, want %q", c.n, got, c.want)
+		}
+	}
+}
+
+func TestHumanizeBytes(t *testing.T) {
+	cases := []struct {
+		n       int64
+		iec, si string
+	}{
+		{0, "0 B", "0 B"},
+		{999, "999 B", "999 B"},
+		{1000, "1000 B", "1 kB"},
+		{1024, "1 KiB", "1 kB"},
+		{1536, "1.5 KiB", "1.5 kB"},
+		{1 << 20, "1 MiB", "1 MB"},
+		{5 << 30, "5 GiB", "5.4 GB"},
+		{math.MaxInt64, "8 EiB", "9.2 EB"},
+	}
+	for _, c := range cases {
+		if got := HumanizeBytes(c.n); got != c.iec {
+			t.Errorf("HumanizeBytes(%d) == %q, want %q", c.n, got, c.iec)
+		}
+		if got := HumanizeBytesSI(c.n); got != c.si {
+			t.Errorf("HumanizeBytesSI(%d) == %q, want %q", c.n, got, c.si)
+		}
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int64
+	}{
+		{"512", 512},
+		{"2GB", 2000000000},
+		{"2 gb", 2000000000},
+		{"1.5 KiB", 1536},
+		{"10k", 10000},
+		{"1MiB", 1 << 20},
+		{"100 B", 100},
+		{"9223372036854775000", 9223372036854775000},
+	}
+	for _, c := range cases {
+		if got, err := ParseBytes(c.in); got != c.want || err != nil {
+			t.Errorf("ParseBytes(%q) == %d, %v, want %d", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"", "GB", "12 parsecs", "1..2MB", "20EiB"} {
+		if _, err := ParseBytes(in); err == nil {
+			t.Errorf("ParseBytes(%q) succeeded", in)
+		}
+	}
+}
+
+func TestParseHumanInt(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int64
+	}{
+		{"42", 42},
+		{"1.2M", 1200000},
+		{"15k", 15000},
+		{"-2.5B", -2500000000},
+	}
+	for _, c := range cases {
+		if got, err := ParseHumanInt(c.in); got != c.want || err != nil {
+			t.Errorf("ParseHumanInt(%q) == %d, %v, want %d", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"1.2X", "M"} {
+		if _, err := ParseHumanInt(in); err == nil {
+			t.Errorf("ParseHumanInt(%q) succeeded", in)
+		}
+	}
+	// HumanizeInt output parses back to within its rounding.
+	if got, _ := ParseHumanInt(HumanizeInt(1234567)); got != 1200000 {
+		t.Errorf("round trip == %d", got)
+	}
+}
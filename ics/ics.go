@@ -0,0 +1,181 @@
+// Package ics writes and reads iCalendar (RFC 5545) files containing
+// events.
+//
+// Only the common subset is supported: VEVENT components with a summary,
+// description, location, start and end (UTC, a named time zone, or an
+// all-day date) and a simple RRULE (see Rule). Time zones are written as
+// IANA TZID parameters without VTIMEZONE definitions, which mainstream
+// calendar clients accept.
+package ics
+
+import "bufio"
+import "fmt"
+import "io"
+import "strings"
+import "time"
+
+// Calendar is a VCALENDAR object.
+type Calendar struct {
+	ProdID string // identifies the producing software; defaults to DefaultProdID
+	Name   string // written as X-WR-CALNAME
+	Events []Event
+}
+
+// DefaultProdID is the PRODID written when Calendar.ProdID is empty.
+const DefaultProdID = "-//lukehedger//golib ics//EN"
+
+// Event is a VEVENT component.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start, End  time.Time // End is exclusive; for all-day events it is the day after the last day
+	AllDay      bool      // Start and End are dates; their clock times are ignored
+	Stamp       time.Time // DTSTAMP; defaults to the time of writing
+	Rule        *Rule     // recurrence, or nil for a single occurrence
+}
+
+// Add appends an event and returns c.
+func (c *Calendar) Add(e Event) *Calendar {
+	c.Events = append(c.Events, e)
+	return c
+}
+
+// WriteTo writes c in iCalendar format.
+func (c *Calendar) WriteTo(w io.Writer) (int64, error) {
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	p := printer{w: bw}
+
+	prodID := c.ProdID
+	if prodID == "" {
+		prodID = DefaultProdID
+	}
+	p.line("BEGIN", nil, "VCALENDAR")
+	p.line("VERSION", nil, "2.0")
+	p.line("PRODID", nil, prodID)
+	p.line("CALSCALE", nil, "GREGORIAN")
+	if c.Name != "" {
+		p.line("X-WR-CALNAME", nil, escape(c.Name))
+	}
+	for _, e := range c.Events {
+		if err := e.write(&p); err != nil {
+			return cw.n, err
+		}
+	}
+	p.line("END", nil, "VCALENDAR")
+	if err := bw.Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+// String returns c in iCalendar format.
+func (c *Calendar) String() string {
+	var b strings.Builder
+	c.WriteTo(&b)
+	return b.String()
+}
+
+func (e *Event) write(p *printer) error {
+	if e.UID == "" {
+		return fmt.Errorf("ics: event %q has no UID", e.Summary)
+	}
+	if e.Start.IsZero() {
+		return fmt.Errorf("ics: event %s has no start", e.UID)
+	}
+	stamp := e.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	p.line("BEGIN", nil, "VEVENT")
+	p.line("UID", nil, escape(e.UID))
+	p.line("DTSTAMP", nil, stamp.UTC().Format(utcLayout))
+	p.time("DTSTART", e.Start, e.AllDay)
+	if !e.End.IsZero() {
+		p.time("DTEND", e.End, e.AllDay)
+	}
+	if e.Summary != "" {
+		p.line("SUMMARY", nil, escape(e.Summary))
+	}
+	if e.Description != "" {
+		p.line("DESCRIPTION", nil, escape(e.Description))
+	}
+	if e.Location != "" {
+		p.line("LOCATION", nil, escape(e.Location))
+	}
+	if e.Rule != nil {
+		p.line("RRULE", nil, e.Rule.String())
+	}
+	p.line("END", nil, "VEVENT")
+	return nil
+}
+
+const (
+	dateLayout  = "20060102"
+	localLayout = "20060102T150405"
+	utcLayout   = "20060102T150405Z"
+)
+
+type param struct{ name, value string }
+
+type printer struct {
+	w *bufio.Writer
+}
+
+func (p *printer) time(name string, t time.Time, allDay bool) {
+	switch {
+	case allDay:
+		p.line(name, []param{{"VALUE", "DATE"}}, t.Format(dateLayout))
+	case t.Location() == time.UTC:
+		p.line(name, nil, t.Format(utcLayout))
+	case t.Location() == time.Local || t.Location().String() == "":
+		// The local zone has no portable name; pin the instant in UTC.
+		p.line(name, nil, t.UTC().Format(utcLayout))
+	default:
+		p.line(name, []param{{"TZID", t.Location().String()}}, t.Format(localLayout))
+	}
+}
+
+// line writes a content line, folding it so that no physical line
+// exceeds 75 octets, without splitting a UTF-8 sequence.
+func (p *printer) line(name string, params []param, value string) {
+	var b strings.Builder
+	b.WriteString(name)
+	for _, pr := range params {
+		fmt.Fprintf(&b, ";%s=%s", pr.name, pr.value)
+	}
+	b.WriteByte(':')
+	b.WriteString(value)
+
+	s := b.String()
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 { // continuation byte
+			cut--
+		}
+		p.w.WriteString(s[:cut])
+		p.w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // the leading space counts
+	}
+	p.w.WriteString(s)
+	p.w.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(s string) string { return escaper.Replace(s) }
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
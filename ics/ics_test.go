@@ -0,0 +1,86 @@
+package ics
+
+import "strings"
+import "testing"
+import "time"
+
+func TestCalendarWriteTo(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip(err)
+	}
+	stamp := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	c := &Calendar{Name: "Team"}
+	c.Add(Event{
+		UID:     "standup@example.com",
+		Summary: "Standup; daily, short",
+		Start:   time.Date(2024, 1, 8, 9, 30, 0, 0, london),
+		End:     time.Date(2024, 1, 8, 9, 45, 0, 0, london),
+		Stamp:   stamp,
+		Rule:    &Rule{Freq: Weekly, Count: 4},
+	}).Add(Event{
+		UID:         "offsite@example.com",
+		Summary:     "Offsite",
+		Description: "Line one\nline two",
+		Start:       time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC),
+		AllDay:      true,
+		Stamp:       stamp,
+	})
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + DefaultProdID,
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Team",
+		"BEGIN:VEVENT",
+		"UID:standup@example.com",
+		"DTSTAMP:20240101T090000Z",
+		"DTSTART;TZID=Europe/London:20240108T093000",
+		"DTEND;TZID=Europe/London:20240108T094500",
+		`SUMMARY:Standup\; daily\, short`,
+		"RRULE:FREQ=WEEKLY;COUNT=4",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:offsite@example.com",
+		"DTSTAMP:20240101T090000Z",
+		"DTSTART;VALUE=DATE:20240201",
+		"DTEND;VALUE=DATE:20240203",
+		"SUMMARY:Offsite",
+		`DESCRIPTION:Line one\nline two`,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := c.String(); got != want {
+		t.Errorf("String() ==\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFolding(t *testing.T) {
+	long := strings.Repeat("é", 60)
+	c := &Calendar{Events: []Event{{UID: "x", Summary: long, Start: time.Unix(0, 0).UTC(), Stamp: time.Unix(0, 0)}}}
+	out := c.String()
+	for _, l := range strings.Split(out, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line of %d octets: %q", len(l), l)
+		}
+	}
+	parsed, err := Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.Events[0].Summary; got != long {
+		t.Errorf("round-tripped summary == %q", got)
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	for _, e := range []Event{{Start: time.Now()}, {UID: "x"}} {
+		var b strings.Builder
+		if _, err := (&Calendar{Events: []Event{e}}).WriteTo(&b); err == nil {
+			t.Errorf("WriteTo(%+v) succeeded", e)
+		}
+	}
+}
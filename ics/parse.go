@@ -0,0 +1,159 @@
+package ics
+
+import "bufio"
+import "fmt"
+import "io"
+import "strings"
+import "time"
+
+// Parse reads an iCalendar stream. Components other than VEVENT, and
+// properties it does not model, are skipped. Times with a TZID parameter
+// are loaded from the system time zone database; floating times (no zone)
+// are interpreted in time.Local.
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	cal := &Calendar{}
+	var ev *Event
+	var depth []string
+	for i, l := range lines {
+		name, params, value, err := splitLine(l)
+		if err != nil {
+			return nil, fmt.Errorf("ics: line %d: %w", i+1, err)
+		}
+		switch name {
+		case "BEGIN":
+			depth = append(depth, strings.ToUpper(value))
+			if strings.EqualFold(value, "VEVENT") {
+				ev = &Event{}
+			}
+			continue
+		case "END":
+			if len(depth) == 0 || depth[len(depth)-1] != strings.ToUpper(value) {
+				return nil, fmt.Errorf("ics: line %d: unexpected END:%s", i+1, value)
+			}
+			depth = depth[:len(depth)-1]
+			if strings.EqualFold(value, "VEVENT") && ev != nil {
+				cal.Events = append(cal.Events, *ev)
+				ev = nil
+			}
+			continue
+		}
+
+		if len(depth) == 1 && depth[0] == "VCALENDAR" {
+			switch name {
+			case "PRODID":
+				cal.ProdID = value
+			case "X-WR-CALNAME":
+				cal.Name = unescape(value)
+			}
+			continue
+		}
+		if ev == nil || depth[len(depth)-1] != "VEVENT" {
+			continue // inside VALARM, VTIMEZONE, etc.
+		}
+		switch name {
+		case "UID":
+			ev.UID = unescape(value)
+		case "SUMMARY":
+			ev.Summary = unescape(value)
+		case "DESCRIPTION":
+			ev.Description = unescape(value)
+		case "LOCATION":
+			ev.Location = unescape(value)
+		case "DTSTAMP", "DTSTART", "DTEND":
+			t, err := parseTime(value, params, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("ics: line %d: %s: %w", i+1, name, err)
+			}
+			switch name {
+			case "DTSTAMP":
+				ev.Stamp = t
+			case "DTSTART":
+				ev.Start = t
+				ev.AllDay = params["VALUE"] == "DATE" || len(value) == len(dateLayout)
+			case "DTEND":
+				ev.End = t
+			}
+		case "RRULE":
+			if ev.Rule, err = ParseRule(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if len(depth) > 0 {
+		return nil, fmt.Errorf("ics: unterminated %s", depth[len(depth)-1])
+	}
+	return cal, nil
+}
+
+// unfold reads physical lines and joins folded continuations.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		l := strings.TrimSuffix(sc.Text(), "\r")
+		if l == "" {
+			continue
+		}
+		if (l[0] == ' ' || l[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines, sc.Err()
+}
+
+// splitLine splits a content line into its upper-cased name, parameters
+// and raw value.
+func splitLine(l string) (string, map[string]string, string, error) {
+	// The value starts at the first colon outside a quoted parameter value.
+	quoted := false
+	colon := -1
+	for i := 0; i < len(l) && colon < 0; i++ {
+		switch l[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", fmt.Errorf("missing ':' in %q", l)
+	}
+	head, value := l[:colon], l[colon+1:]
+	fields := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, f := range fields[1:] {
+		k, v, _ := strings.Cut(f, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(fields[0]), params, value, nil
+}
+
+func parseTime(value string, params map[string]string, floating *time.Location) (time.Time, error) {
+	if len(value) == len(dateLayout) {
+		return time.ParseInLocation(dateLayout, value, floating)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(utcLayout, value)
+	}
+	loc := floating
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.ParseInLocation(localLayout, value, loc)
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+func unescape(s string) string { return unescaper.Replace(s) }
@@ -0,0 +1,81 @@
+package ics
+
+import "strings"
+import "testing"
+import "time"
+
+const sample = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//EN\r\n" +
+	"BEGIN:VTIMEZONE\r\n" +
+	"TZID:America/New_York\r\n" +
+	"BEGIN:STANDARD\r\n" +
+	"DTSTART:19701101T020000\r\n" +
+	"END:STANDARD\r\n" +
+	"END:VTIMEZONE\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:1@example.com\r\n" +
+	"DTSTAMP:20240101T000000Z\r\n" +
+	"DTSTART;TZID=\"America/New_York\":20240315T140000\r\n" +
+	"DTEND;TZID=America/New_York:20240315T150000\r\n" +
+	"SUMMARY:Review\\, Q1\r\n" +
+	"DESCRIPTION:Agenda:\\n1. Numbers\\n2. Plans and a very long line that is fo\r\n" +
+	" lded\r\n" +
+	"RRULE:FREQ=MONTHLY;INTERVAL=2;UNTIL=20240901T000000Z\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"DESCRIPTION:Reminder\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:2@example.com\r\n" +
+	"DTSTART;VALUE=DATE:20240704\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	c, err := Parse(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ProdID != "-//Example//EN" || len(c.Events) != 2 {
+		t.Fatalf("Parse() == %+v", c)
+	}
+	e := c.Events[0]
+	if e.Summary != "Review, Q1" {
+		t.Errorf("Summary == %q", e.Summary)
+	}
+	if e.Description != "Agenda:\n1. Numbers\n2. Plans and a very long line that is folded" {
+		t.Errorf("Description == %q", e.Description)
+	}
+	if want := time.Date(2024, 3, 15, 14, 0, 0, 0, ny); !e.Start.Equal(want) || e.Start.Location().String() != "America/New_York" {
+		t.Errorf("Start == %v, want %v", e.Start, want)
+	}
+	if e.End.Sub(e.Start) != time.Hour {
+		t.Errorf("End == %v", e.End)
+	}
+	if e.Rule == nil || e.Rule.Freq != Monthly || e.Rule.Interval != 2 {
+		t.Errorf("Rule == %+v", e.Rule)
+	}
+	if h := c.Events[1]; !h.AllDay || h.Start.Format(dateLayout) != "20240704" {
+		t.Errorf("all-day event == %+v", h)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n",
+		"BEGIN:VCALENDAR\r\nEND:VEVENT\r\n",
+		"BEGIN:VCALENDAR\r\nno colon\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:tomorrow\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+		"BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nRRULE:FREQ=HOURLY\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if _, err := Parse(strings.NewReader(in)); err == nil {
+			t.Errorf("Parse(%q) succeeded", in)
+		}
+	}
+}
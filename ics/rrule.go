@@ -0,0 +1,126 @@
+package ics
+
+import "fmt"
+import "strconv"
+import "strings"
+import "time"
+
+// Frequency is how often a Rule repeats.
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// Rule is a simple recurrence rule: an event repeating every Interval
+// days, weeks, months or years, for Count occurrences or until Until.
+// BYDAY, BYMONTH and the other BY* parts of RFC 5545 are not supported.
+type Rule struct {
+	Freq     Frequency
+	Interval int       // defaults to 1
+	Count    int       // total occurrences including the first; 0 for no limit
+	Until    time.Time // last possible start; zero for no limit
+}
+
+// String returns the rule in RRULE value syntax, e.g.
+// "FREQ=WEEKLY;INTERVAL=2;COUNT=10".
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(utcLayout))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseRule parses an RRULE value.
+func ParseRule(s string) (*Rule, error) {
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("ics: bad RRULE part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(v))
+			switch r.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				return nil, fmt.Errorf("ics: unsupported RRULE frequency %q", v)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(v)
+			if err == nil && r.Interval < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(v)
+			if err == nil && r.Count < 1 {
+				err = fmt.Errorf("must be positive")
+			}
+		case "UNTIL":
+			r.Until, err = parseTime(v, nil, time.UTC)
+		default:
+			return nil, fmt.Errorf("ics: unsupported RRULE part %s", k)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ics: RRULE %s: %w", k, err)
+		}
+	}
+	if r.Freq == "" {
+		return nil, fmt.Errorf("ics: RRULE %q has no FREQ", s)
+	}
+	return r, nil
+}
+
+// Occurrences returns the start times of e that fall before end, in
+// order. An event without a rule has at most one occurrence. Monthly and
+// yearly rules skip dates that do not exist, such as the 31st of a short
+// month, as RFC 5545 requires.
+func (e *Event) Occurrences(end time.Time) []time.Time {
+	if e.Rule == nil {
+		if e.Start.Before(end) {
+			return []time.Time{e.Start}
+		}
+		return nil
+	}
+	r := e.Rule
+	interval := max(r.Interval, 1)
+	var out []time.Time
+	for n := 0; ; n++ {
+		if r.Count > 0 && len(out) >= r.Count {
+			break
+		}
+		var t time.Time
+		switch r.Freq {
+		case Daily:
+			t = e.Start.AddDate(0, 0, n*interval)
+		case Weekly:
+			t = e.Start.AddDate(0, 0, 7*n*interval)
+		case Monthly:
+			t = e.Start.AddDate(0, n*interval, 0)
+		case Yearly:
+			t = e.Start.AddDate(n*interval, 0, 0)
+		default:
+			return out
+		}
+		if !t.Before(end) || (!r.Until.IsZero() && t.After(r.Until)) {
+			break
+		}
+		if t.Day() != e.Start.Day() && (r.Freq == Monthly || r.Freq == Yearly) {
+			continue // AddDate normalised a nonexistent date into the next month
+		}
+		out = append(out, t)
+	}
+	return out
+}
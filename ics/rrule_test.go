@@ -0,0 +1,74 @@
+package ics
+
+import "testing"
+import "time"
+
+func TestRuleString(t *testing.T) {
+	cases := []struct {
+		rule Rule
+		want string
+	}{
+		{Rule{Freq: Daily}, "FREQ=DAILY"},
+		{Rule{Freq: Weekly, Interval: 2, Count: 10}, "FREQ=WEEKLY;INTERVAL=2;COUNT=10"},
+		{Rule{Freq: Yearly, Until: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}, "FREQ=YEARLY;UNTIL=20300101T000000Z"},
+	}
+	for _, c := range cases {
+		if got := c.rule.String(); got != c.want {
+			t.Errorf("String() == %q, want %q", got, c.want)
+		}
+		r, err := ParseRule(c.want)
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", c.want, err)
+		} else if r.String() != c.want {
+			t.Errorf("ParseRule(%q) round-trips to %q", c.want, r.String())
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	for _, s := range []string{
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=0",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=-2",
+		"FREQ=DAILY;COUNT=x",
+	} {
+		if r, err := ParseRule(s); err == nil {
+			t.Errorf("ParseRule(%q) == %v, want an error", s, r)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 10, 0, 0, 0, time.UTC) }
+	cases := []struct {
+		name string
+		e    Event
+		end  time.Time
+		want []time.Time
+	}{
+		{"single", Event{Start: day(2024, 1, 1)}, day(2025, 1, 1), []time.Time{day(2024, 1, 1)}},
+		{"count", Event{Start: day(2024, 1, 1), Rule: &Rule{Freq: Weekly, Count: 3}}, day(2025, 1, 1),
+			[]time.Time{day(2024, 1, 1), day(2024, 1, 8), day(2024, 1, 15)}},
+		{"until", Event{Start: day(2024, 1, 1), Rule: &Rule{Freq: Daily, Interval: 2, Until: day(2024, 1, 5)}}, day(2025, 1, 1),
+			[]time.Time{day(2024, 1, 1), day(2024, 1, 3), day(2024, 1, 5)}},
+		{"end", Event{Start: day(2024, 1, 1), Rule: &Rule{Freq: Daily}}, day(2024, 1, 3),
+			[]time.Time{day(2024, 1, 1), day(2024, 1, 2)}},
+		{"month end", Event{Start: day(2024, 1, 31), Rule: &Rule{Freq: Monthly, Count: 3}}, day(2025, 1, 1),
+			[]time.Time{day(2024, 1, 31), day(2024, 3, 31), day(2024, 5, 31)}},
+		{"leap day", Event{Start: day(2024, 2, 29), Rule: &Rule{Freq: Yearly}}, day(2033, 1, 1),
+			[]time.Time{day(2024, 2, 29), day(2028, 2, 29), day(2032, 2, 29)}},
+	}
+	for _, c := range cases {
+		got := c.e.Occurrences(c.end)
+		if len(got) != len(c.want) {
+			t.Errorf("%s: Occurrences() == %v, want %v", c.name, got, c.want)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(c.want[i]) {
+				t.Errorf("%s: Occurrences()[%d] == %v, want %v", c.name, i, got[i], c.want[i])
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package imaging
+
+import "bytes"
+import "encoding/binary"
+import "errors"
+import "image"
+
+var errNoOrientation = errors.New("imaging: no EXIF orientation")
+
+// Orientation returns the EXIF orientation tag, 1 to 8, from the start of
+// a JPEG file. It returns an error if data is not a JPEG or has no
+// orientation tag.
+func Orientation(data []byte) (int, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 0, errNoOrientation
+	}
+	p := data[2:]
+	for len(p) >= 4 && p[0] == 0xFF {
+		marker := p[1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan, end of image
+			break
+		}
+		n := int(binary.BigEndian.Uint16(p[2:4]))
+		if n < 2 || len(p) < 2+n {
+			break
+		}
+		seg := p[4 : 2+n]
+		if marker == 0xE1 && bytes.HasPrefix(seg, []byte("Exif\x00\x00")) {
+			return tiffOrientation(seg[6:])
+		}
+		p = p[2+n:]
+	}
+	return 0, errNoOrientation
+}
+
+// tiffOrientation finds tag 0x0112 in IFD0 of a TIFF structure.
+func tiffOrientation(t []byte) (int, error) {
+	if len(t) < 8 {
+		return 0, errNoOrientation
+	}
+	var bo binary.ByteOrder
+	switch string(t[:2]) {
+	case "II":
+		bo = binary.LittleEndian
+	case "MM":
+		bo = binary.BigEndian
+	default:
+		return 0, errNoOrientation
+	}
+	ifd := int(bo.Uint32(t[4:8]))
+	if ifd+2 > len(t) || ifd < 8 {
+		return 0, errNoOrientation
+	}
+	count := int(bo.Uint16(t[ifd:]))
+	for i := range count {
+		e := ifd + 2 + 12*i
+		if e+12 > len(t) {
+			break
+		}
+		if bo.Uint16(t[e:]) == 0x0112 {
+			o := int(bo.Uint16(t[e+8:]))
+			if o < 1 || o > 8 {
+				return 0, errNoOrientation
+			}
+			return o, nil
+		}
+	}
+	return 0, errNoOrientation
+}
+
+// Orient transforms img so that an image stored with the given EXIF
+// orientation displays upright. Orientation 1, or any value outside 1 to
+// 8, returns img unchanged.
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	src := toRGBA(img)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w // rotated a quarter turn
+	}
+	// from maps a destination pixel to its source pixel.
+	from := map[int]func(x, y int) (int, int){
+		2: func(x, y int) (int, int) { return w - 1 - x, y },
+		3: func(x, y int) (int, int) { return w - 1 - x, h - 1 - y },
+		4: func(x, y int) (int, int) { return x, h - 1 - y },
+		5: func(x, y int) (int, int) { return y, x },
+		6: func(x, y int) (int, int) { return y, h - 1 - x },
+		7: func(x, y int) (int, int) { return w - 1 - y, h - 1 - x },
+		8: func(x, y int) (int, int) { return w - 1 - y, x },
+	}[orientation]
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := range dh {
+		for x := range dw {
+			sx, sy := from(x, y)
+			s, d := src.PixOffset(sx, sy), dst.PixOffset(x, y)
+			copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,86 @@
+package imaging
+
+import "encoding/binary"
+import "image"
+import "image/color"
+import "testing"
+
+// withOrientation inserts an EXIF APP1 segment holding an orientation tag
+// after the SOI marker of a JPEG.
+func withOrientation(jpg []byte, o int, bo binary.ByteOrder) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if bo == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	bo.PutUint16(tiff[2:], 42)
+	bo.PutUint32(tiff[4:], 8)
+	bo.PutUint16(tiff[8:], 1) // one entry
+	bo.PutUint16(tiff[10:], 0x0112)
+	bo.PutUint16(tiff[12:], 3) // SHORT
+	bo.PutUint32(tiff[14:], 1)
+	bo.PutUint16(tiff[18:], uint16(o))
+
+	seg := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(seg)+2))
+	out := append([]byte{0xFF, 0xD8}, app1...)
+	out = append(out, seg...)
+	return append(out, jpg[2:]...)
+}
+
+func TestOrientation(t *testing.T) {
+	jpg := []byte{0xFF, 0xD8, 0xFF, 0xDA, 0, 2}
+	for _, bo := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		for o := 1; o <= 8; o++ {
+			got, err := Orientation(withOrientation(jpg, o, bo))
+			if err != nil || got != o {
+				t.Errorf("%v orientation %d: got %d, %v", bo, o, got, err)
+			}
+		}
+	}
+	for _, data := range [][]byte{nil, []byte("\x89PNG"), jpg, withOrientation(jpg, 9, binary.BigEndian)} {
+		if _, err := Orientation(data); err == nil {
+			t.Errorf("Orientation(%q) succeeded", data)
+		}
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image whose pixels are numbered by their red channel:
+	//	1 2 3
+	//	4 5 6
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for i := range 6 {
+		src.Set(i%3, i/3, color.RGBA{uint8(i + 1), 0, 0, 255})
+	}
+	cases := []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{1, [][]uint8{{1, 2, 3}, {4, 5, 6}}},
+		{2, [][]uint8{{3, 2, 1}, {6, 5, 4}}},
+		{3, [][]uint8{{6, 5, 4}, {3, 2, 1}}},
+		{4, [][]uint8{{4, 5, 6}, {1, 2, 3}}},
+		{5, [][]uint8{{1, 4}, {2, 5}, {3, 6}}},
+		{6, [][]uint8{{4, 1}, {5, 2}, {6, 3}}},
+		{7, [][]uint8{{6, 3}, {5, 2}, {4, 1}}},
+		{8, [][]uint8{{3, 6}, {2, 5}, {1, 4}}},
+	}
+	for _, c := range cases {
+		img := Orient(src, c.orientation)
+		b := img.Bounds()
+		if b.Dy() != len(c.want) || b.Dx() != len(c.want[0]) {
+			t.Errorf("Orient(%d) bounds %v", c.orientation, b)
+			continue
+		}
+		for y, row := range c.want {
+			for x, want := range row {
+				if r, _, _, _ := img.At(x, y).RGBA(); uint8(r>>8) != want {
+					t.Errorf("Orient(%d) at (%d, %d) == %d, want %d", c.orientation, x, y, r>>8, want)
+				}
+			}
+		}
+	}
+}
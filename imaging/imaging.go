@@ -0,0 +1,106 @@
+// Package imaging resizes, crops and converts images between PNG, JPEG
+// and GIF.
+//
+// Decode reads the EXIF orientation of JPEG images and rotates them
+// upright, so images from phone cameras come out the way they were shot.
+// Decode and Encode work on io.Reader and io.Writer, so a conversion can
+// stream from a request body to a response:
+//
+//	img, _, err := imaging.Decode(r.Body)
+//	...
+//	err = imaging.Encode(w, imaging.Thumbnail(img, 256, 256), imaging.JPEG, nil)
+package imaging
+
+import "bufio"
+import "fmt"
+import "image"
+import "image/gif"
+import "image/jpeg"
+import "image/png"
+import "io"
+import "path/filepath"
+import "strings"
+
+// Format is an image file format.
+type Format string
+
+const (
+	PNG  Format = "png"
+	JPEG Format = "jpeg"
+	GIF  Format = "gif"
+)
+
+// FormatFromFilename returns the format implied by a file extension such
+// as ".png" or ".jpg".
+func FormatFromFilename(name string) (Format, error) {
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".png":
+		return PNG, nil
+	case ".jpg", ".jpeg":
+		return JPEG, nil
+	case ".gif":
+		return GIF, nil
+	default:
+		return "", fmt.Errorf("imaging: unsupported file extension %q", ext)
+	}
+}
+
+// Options control encoding.
+type Options struct {
+	Quality int // JPEG quality, 1 to 100; defaults to 85
+}
+
+// exifWindow is how much of the stream Decode inspects for EXIF data,
+// which JPEG writers place before the image data.
+const exifWindow = 64 << 10
+
+// Decode reads a PNG, JPEG or GIF image from r and reports its format.
+// JPEG images are rotated and flipped according to their EXIF orientation
+// tag.
+func Decode(r io.Reader) (image.Image, Format, error) {
+	br := bufio.NewReaderSize(r, exifWindow)
+	head, _ := br.Peek(exifWindow) // a short image returns what there is
+	orientation := 1
+	if o, err := Orientation(head); err == nil {
+		orientation = o
+	}
+
+	img, name, err := image.Decode(br)
+	if err != nil {
+		return nil, "", fmt.Errorf("imaging: %w", err)
+	}
+	return Orient(img, orientation), Format(name), nil
+}
+
+// Encode writes img to w in format f. GIF output is reduced to a
+// 256-colour palette with dithering.
+func Encode(w io.Writer, img image.Image, f Format, opts *Options) error {
+	var err error
+	switch f {
+	case PNG:
+		err = png.Encode(w, img)
+	case JPEG:
+		q := 85
+		if opts != nil && opts.Quality > 0 {
+			q = min(opts.Quality, 100)
+		}
+		err = jpeg.Encode(w, img, &jpeg.Options{Quality: q})
+	case GIF:
+		err = gif.Encode(w, img, nil)
+	default:
+		return fmt.Errorf("imaging: unsupported format %q", f)
+	}
+	if err != nil {
+		return fmt.Errorf("imaging: %w", err)
+	}
+	return nil
+}
+
+// Convert decodes an image from r and re-encodes it to w in format f.
+func Convert(w io.Writer, r io.Reader, f Format, opts *Options) error {
+	img, _, err := Decode(r)
+	if err != nil {
+		return err
+	}
+	return Encode(w, img, f, opts)
+}
@@ -0,0 +1,65 @@
+package imaging
+
+import "bytes"
+import "encoding/binary"
+import "image"
+import "testing"
+
+func TestEncodeDecode(t *testing.T) {
+	src := checker(6, 4)
+	for _, f := range []Format{PNG, JPEG, GIF} {
+		var b bytes.Buffer
+		if err := Encode(&b, src, f, &Options{Quality: 95}); err != nil {
+			t.Fatalf("Encode(%s): %v", f, err)
+		}
+		img, got, err := Decode(&b)
+		if err != nil {
+			t.Fatalf("Decode(%s): %v", f, err)
+		}
+		if got != f || img.Bounds() != src.Bounds() {
+			t.Errorf("Decode(%s) == %s image %v", f, got, img.Bounds())
+		}
+	}
+	if err := Encode(new(bytes.Buffer), src, "bmp", nil); err == nil {
+		t.Error("Encode(bmp) succeeded")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	var png, gif bytes.Buffer
+	if err := Encode(&png, checker(3, 3), PNG, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Convert(&gif, &png, GIF, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, f, err := Decode(&gif); err != nil || f != GIF {
+		t.Errorf("converted image decodes as %q, %v", f, err)
+	}
+}
+
+func TestDecodeOrientation(t *testing.T) {
+	var b bytes.Buffer
+	if err := Encode(&b, checker(6, 4), JPEG, nil); err != nil {
+		t.Fatal(err)
+	}
+	img, _, err := Decode(bytes.NewReader(withOrientation(b.Bytes(), 6, binary.BigEndian)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 4, 6) {
+		t.Errorf("rotated bounds == %v, want 4x6", img.Bounds())
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	cases := map[string]Format{"a.PNG": PNG, "b.jpg": JPEG, "c.jpeg": JPEG, "d.gif": GIF}
+	for name, want := range cases {
+		if got, err := FormatFromFilename(name); got != want || err != nil {
+			t.Errorf("FormatFromFilename(%q) == %q, %v", name, got, err)
+		}
+	}
+	if _, err := FormatFromFilename("e.webp"); err == nil {
+		t.Error("FormatFromFilename(webp) succeeded")
+	}
+}
@@ -0,0 +1,106 @@
+package imaging
+
+import "image"
+import "image/draw"
+import "math"
+
+// Filter selects how Resize samples the source image.
+type Filter int
+
+const (
+	// Nearest copies the closest source pixel: fast, and keeps hard edges
+	// in pixel art, but aliases when shrinking photos.
+	Nearest Filter = iota
+	// Bilinear blends the four closest source pixels.
+	Bilinear
+)
+
+// Resize scales img to width by height pixels. If either is zero it is
+// chosen to preserve the aspect ratio.
+func Resize(img image.Image, width, height int, f Filter) *image.RGBA {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	switch {
+	case width <= 0 && height <= 0:
+		width, height = sw, sh
+	case width <= 0:
+		width = max(1, int(math.Round(float64(sw)*float64(height)/float64(sh))))
+	case height <= 0:
+		height = max(1, int(math.Round(float64(sh)*float64(width)/float64(sw))))
+	}
+
+	src := toRGBA(img)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if sw == 0 || sh == 0 {
+		return dst
+	}
+	xScale := float64(sw) / float64(width)
+	yScale := float64(sh) / float64(height)
+
+	for y := range height {
+		for x := range width {
+			// Sample at the centre of the destination pixel.
+			fx := (float64(x)+0.5)*xScale - 0.5
+			fy := (float64(y)+0.5)*yScale - 0.5
+			d := dst.PixOffset(x, y)
+			if f == Nearest {
+				sx := min(int(fx+0.5), sw-1)
+				sy := min(int(fy+0.5), sh-1)
+				s := src.PixOffset(max(sx, 0), max(sy, 0))
+				copy(dst.Pix[d:d+4], src.Pix[s:s+4])
+				continue
+			}
+			x0, y0 := int(math.Floor(fx)), int(math.Floor(fy))
+			tx, ty := fx-float64(x0), fy-float64(y0)
+			p00 := src.PixOffset(clampInt(x0, sw), clampInt(y0, sh))
+			p10 := src.PixOffset(clampInt(x0+1, sw), clampInt(y0, sh))
+			p01 := src.PixOffset(clampInt(x0, sw), clampInt(y0+1, sh))
+			p11 := src.PixOffset(clampInt(x0+1, sw), clampInt(y0+1, sh))
+			for c := range 4 {
+				top := float64(src.Pix[p00+c])*(1-tx) + float64(src.Pix[p10+c])*tx
+				bottom := float64(src.Pix[p01+c])*(1-tx) + float64(src.Pix[p11+c])*tx
+				dst.Pix[d+c] = uint8(math.Round(top*(1-ty) + bottom*ty))
+			}
+		}
+	}
+	return dst
+}
+
+// Thumbnail shrinks img to fit within maxWidth by maxHeight, preserving
+// its aspect ratio. Images that already fit are returned unchanged.
+func Thumbnail(img image.Image, maxWidth, maxHeight int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= maxWidth && b.Dy() <= maxHeight {
+		return img
+	}
+	scale := min(float64(maxWidth)/float64(b.Dx()), float64(maxHeight)/float64(b.Dy()))
+	w := max(1, int(math.Round(float64(b.Dx())*scale)))
+	h := max(1, int(math.Round(float64(b.Dy())*scale)))
+	return Resize(img, w, h, Bilinear)
+}
+
+// Crop returns the part of img inside r, which is intersected with the
+// image bounds. The result's bounds start at (0, 0) and it does not share
+// pixels with img.
+func Crop(img image.Image, r image.Rectangle) *image.RGBA {
+	r = r.Intersect(img.Bounds())
+	dst := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, r.Min, draw.Src)
+	return dst
+}
+
+// toRGBA returns img as an *image.RGBA whose bounds start at (0, 0),
+// converting only if needed.
+func toRGBA(img image.Image) *image.RGBA {
+	if m, ok := img.(*image.RGBA); ok && m.Bounds().Min == (image.Point{}) {
+		return m
+	}
+	b := img.Bounds()
+	m := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(m, m.Bounds(), img, b.Min, draw.Src)
+	return m
+}
+
+func clampInt(v, n int) int {
+	return min(max(v, 0), n-1)
+}
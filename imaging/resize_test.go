@@ -0,0 +1,81 @@
+package imaging
+
+import "image"
+import "image/color"
+import "testing"
+
+func checker(w, h int) *image.RGBA {
+	m := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if (x+y)%2 == 0 {
+				m.Set(x, y, color.White)
+			} else {
+				m.Set(x, y, color.Black)
+			}
+		}
+	}
+	return m
+}
+
+func TestResize(t *testing.T) {
+	src := checker(4, 2)
+	cases := []struct {
+		w, h         int
+		wantW, wantH int
+	}{
+		{8, 4, 8, 4},
+		{2, 0, 2, 1},
+		{0, 6, 12, 6},
+		{0, 0, 4, 2},
+	}
+	for _, c := range cases {
+		for _, f := range []Filter{Nearest, Bilinear} {
+			b := Resize(src, c.w, c.h, f).Bounds()
+			if b.Dx() != c.wantW || b.Dy() != c.wantH {
+				t.Errorf("Resize(%d, %d, %d) is %dx%d, want %dx%d", c.w, c.h, f, b.Dx(), b.Dy(), c.wantW, c.wantH)
+			}
+		}
+	}
+
+	// Doubling with Nearest repeats each pixel.
+	up := Resize(src, 8, 4, Nearest)
+	for y := range 4 {
+		for x := range 8 {
+			if up.RGBAAt(x, y) != src.RGBAAt(x/2, y/2) {
+				t.Fatalf("Nearest at (%d, %d) == %v, want %v", x, y, up.RGBAAt(x, y), src.RGBAAt(x/2, y/2))
+			}
+		}
+	}
+
+	// Halving a checkerboard with Bilinear averages to mid grey.
+	down := Resize(checker(4, 4), 2, 2, Bilinear)
+	if c := down.RGBAAt(0, 0); c.R < 120 || c.R > 135 || c.A != 255 {
+		t.Errorf("Bilinear halve == %v, want mid grey", c)
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	src := checker(400, 200)
+	if b := Thumbnail(src, 100, 100).Bounds(); b.Dx() != 100 || b.Dy() != 50 {
+		t.Errorf("Thumbnail bounds == %v", b)
+	}
+	if got := Thumbnail(src, 500, 500); got != image.Image(src) {
+		t.Error("Thumbnail resized an image that already fits")
+	}
+}
+
+func TestCrop(t *testing.T) {
+	src := checker(4, 4)
+	got := Crop(src, image.Rect(1, 0, 10, 2))
+	if b := got.Bounds(); b != image.Rect(0, 0, 3, 2) {
+		t.Fatalf("Crop bounds == %v", b)
+	}
+	if got.RGBAAt(0, 0) != src.RGBAAt(1, 0) {
+		t.Error("Crop did not start at the rectangle's corner")
+	}
+	got.Set(0, 0, color.RGBA{1, 2, 3, 4})
+	if src.RGBAAt(1, 0) == (color.RGBA{1, 2, 3, 4}) {
+		t.Error("Crop shares pixels with the source")
+	}
+}
@@ -0,0 +1,94 @@
+// Package iox holds small io primitives that many packages need and that
+// the io package lacks: writers and readers that count, limit, tee and
+// fan out, with a choice of what to do when one of several fails.
+package iox
+
+import "errors"
+import "io"
+import "sync/atomic"
+
+// ErrLimitExceeded is returned by a LimitedWriter that has run out of
+// room.
+var ErrLimitExceeded = errors.New("iox: write limit exceeded")
+
+// CountingReader counts the bytes read through it. The count is safe to
+// read while another goroutine reads.
+type CountingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+// NewCountingReader returns a CountingReader reading from r.
+func NewCountingReader(r io.Reader) *CountingReader { return &CountingReader{r: r} }
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// Count returns the number of bytes read so far.
+func (c *CountingReader) Count() int64 { return c.n.Load() }
+
+// CountingWriter counts the bytes written through it. The count is safe
+// to read while another goroutine writes.
+type CountingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+// NewCountingWriter returns a CountingWriter writing to w, which may be
+// nil to count bytes without writing them anywhere.
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	if w == nil {
+		w = io.Discard
+	}
+	return &CountingWriter{w: w}
+}
+
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// Count returns the number of bytes written so far.
+func (c *CountingWriter) Count() int64 { return c.n.Load() }
+
+// LimitedWriter writes to W at most N bytes in total, decreasing N as it
+// goes, like io.LimitedReader. A write that does not fit writes as much
+// as does and returns ErrLimitExceeded.
+type LimitedWriter struct {
+	W io.Writer
+	N int64
+}
+
+// LimitWriter returns a LimitedWriter writing at most n bytes to w.
+func LimitWriter(w io.Writer, n int64) *LimitedWriter { return &LimitedWriter{w, n} }
+
+func (l *LimitedWriter) Write(p []byte) (int, error) {
+	if l.N <= 0 {
+		return 0, ErrLimitExceeded
+	}
+	over := int64(len(p)) > l.N
+	if over {
+		p = p[:l.N]
+	}
+	n, err := l.W.Write(p)
+	l.N -= int64(n)
+	if err == nil && over {
+		err = ErrLimitExceeded
+	}
+	return n, err
+}
+
+// TeeReadCloser is like io.TeeReader, writing to w what it reads from rc,
+// but also passes Close on to rc.
+func TeeReadCloser(rc io.ReadCloser, w io.Writer) io.ReadCloser {
+	return &teeReadCloser{io.TeeReader(rc, w), rc}
+}
+
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,57 @@
+package iox
+
+import "bytes"
+import "io"
+import "strings"
+import "testing"
+
+func TestCounting(t *testing.T) {
+	r := NewCountingReader(strings.NewReader("hello, world"))
+	w := NewCountingWriter(nil)
+	if _, err := io.Copy(w, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Count() != 12 || w.Count() != 12 {
+		t.Errorf("counts == %d, %d, want 12", r.Count(), w.Count())
+	}
+}
+
+func TestLimitWriter(t *testing.T) {
+	var b bytes.Buffer
+	w := LimitWriter(&b, 5)
+	if n, err := w.Write([]byte("abc")); n != 3 || err != nil {
+		t.Errorf("Write(abc) == %d, %v", n, err)
+	}
+	if n, err := w.Write([]byte("defg")); n != 2 || err != ErrLimitExceeded {
+		t.Errorf("Write(defg) == %d, %v, want 2, ErrLimitExceeded", n, err)
+	}
+	if n, err := w.Write([]byte("h")); n != 0 || err != ErrLimitExceeded {
+		t.Errorf("Write(h) == %d, %v, want 0, ErrLimitExceeded", n, err)
+	}
+	if b.String() != "abcde" || w.N != 0 {
+		t.Errorf("wrote %q with %d left", b.String(), w.N)
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestTeeReadCloser(t *testing.T) {
+	var b bytes.Buffer
+	src := &closeRecorder{Reader: strings.NewReader("data")}
+	rc := TeeReadCloser(src, &b)
+	got, _ := io.ReadAll(rc)
+	if err := rc.Close(); err != nil || !src.closed {
+		t.Errorf("Close == %v, closed %v", err, src.closed)
+	}
+	if string(got) != "data" || b.String() != "data" {
+		t.Errorf("read %q, teed %q", got, b.String())
+	}
+}
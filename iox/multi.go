@@ -0,0 +1,104 @@
+package iox
+
+import "errors"
+import "fmt"
+import "io"
+
+// A Policy says what a MultiWriter does when one of its writers fails.
+type Policy int
+
+const (
+	// FailFast stops at the first failing writer and returns its
+	// error, as io.MultiWriter does.
+	FailFast Policy = iota
+	// Continue drops a failing writer and carries on with the rest,
+	// reporting an error only once every writer has failed. Err returns
+	// the errors collected on the way.
+	Continue
+)
+
+// MultiWriter duplicates each write to several writers. A writer that
+// writes less than it was given without an error fails with
+// io.ErrShortWrite. It is not safe for concurrent use.
+type MultiWriter struct {
+	policy  Policy
+	writers []io.Writer
+	errs    []error // per writer, nil while it is healthy
+	failed  error   // under FailFast, the error that stopped m
+}
+
+// NewMultiWriter returns a MultiWriter writing to writers under policy.
+func NewMultiWriter(policy Policy, writers ...io.Writer) *MultiWriter {
+	return &MultiWriter{policy: policy, writers: writers, errs: make([]error, len(writers))}
+}
+
+// Write writes p to each healthy writer. Under FailFast, once a writer
+// has failed every later Write returns its error and writes nothing.
+// Under Continue it returns len(p) and no error while any writer
+// succeeds.
+func (m *MultiWriter) Write(p []byte) (int, error) {
+	if m.failed != nil {
+		return 0, m.failed
+	}
+	ok := false
+	for i, w := range m.writers {
+		if m.errs[i] != nil {
+			continue
+		}
+		n, err := w.Write(p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		if err == nil {
+			ok = true
+			continue
+		}
+		m.errs[i] = fmt.Errorf("iox: writer %d: %w", i, err)
+		if m.policy == FailFast {
+			m.failed = err
+			return n, err
+		}
+	}
+	if !ok && len(m.writers) > 0 {
+		return 0, m.Err()
+	}
+	return len(p), nil
+}
+
+// Err returns the errors of the writers that have failed, joined, or nil
+// if none has.
+func (m *MultiWriter) Err() error { return errors.Join(m.errs...) }
+
+// Healthy returns the number of writers that have not failed.
+func (m *MultiWriter) Healthy() int {
+	n := 0
+	for _, err := range m.errs {
+		if err == nil {
+			n++
+		}
+	}
+	return n
+}
+
+// MultiReadCloser is like io.MultiReader, reading from each of readers in
+// turn, but Close closes them all, returning their errors joined.
+func MultiReadCloser(readers ...io.ReadCloser) io.ReadCloser {
+	rs := make([]io.Reader, len(readers))
+	for i, r := range readers {
+		rs[i] = r
+	}
+	return &multiReadCloser{io.MultiReader(rs...), readers}
+}
+
+type multiReadCloser struct {
+	io.Reader
+	closers []io.ReadCloser
+}
+
+func (m *multiReadCloser) Close() error {
+	var errs []error
+	for _, c := range m.closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
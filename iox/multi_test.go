@@ -0,0 +1,68 @@
+package iox
+
+import "bytes"
+import "errors"
+import "io"
+import "strings"
+import "testing"
+
+var errBroken = errors.New("broken")
+
+type brokenWriter struct{}
+
+func (brokenWriter) Write([]byte) (int, error) { return 0, errBroken }
+
+type shortWriter struct{}
+
+func (shortWriter) Write(p []byte) (int, error) { return len(p) / 2, nil }
+
+func TestMultiWriterFailFast(t *testing.T) {
+	var a, b bytes.Buffer
+	m := NewMultiWriter(FailFast, &a, brokenWriter{}, &b)
+	if _, err := m.Write([]byte("x")); err != errBroken {
+		t.Errorf("Write error == %v, want errBroken", err)
+	}
+	if a.String() != "x" || b.Len() != 0 {
+		t.Errorf("wrote %q and %q, want x and nothing", a.String(), b.String())
+	}
+	for range 2 {
+		if n, err := m.Write([]byte("y")); n != 0 || err != errBroken {
+			t.Errorf("Write after failure == %d, %v, want 0, errBroken", n, err)
+		}
+	}
+	if a.String() != "x" {
+		t.Errorf("wrote %q after failure", a.String())
+	}
+}
+
+func TestMultiWriterContinue(t *testing.T) {
+	var a, b bytes.Buffer
+	m := NewMultiWriter(Continue, &a, brokenWriter{}, shortWriter{}, &b)
+	for _, s := range []string{"he", "llo"} {
+		if n, err := m.Write([]byte(s)); n != len(s) || err != nil {
+			t.Errorf("Write(%q) == %d, %v", s, n, err)
+		}
+	}
+	if a.String() != "hello" || b.String() != "hello" || m.Healthy() != 2 {
+		t.Errorf("wrote %q and %q with %d healthy", a.String(), b.String(), m.Healthy())
+	}
+	err := m.Err()
+	if !errors.Is(err, errBroken) || !errors.Is(err, io.ErrShortWrite) || !strings.Contains(err.Error(), "writer 2") {
+		t.Errorf("Err() == %v", err)
+	}
+
+	m = NewMultiWriter(Continue, brokenWriter{}, brokenWriter{})
+	if n, err := m.Write([]byte("x")); n != 0 || !errors.Is(err, errBroken) {
+		t.Errorf("Write with every writer broken == %d, %v", n, err)
+	}
+}
+
+func TestMultiReadCloser(t *testing.T) {
+	a := &closeRecorder{Reader: strings.NewReader("ab")}
+	b := &closeRecorder{Reader: strings.NewReader("cd")}
+	rc := MultiReadCloser(a, b)
+	got, _ := io.ReadAll(rc)
+	if err := rc.Close(); string(got) != "abcd" || err != nil || !a.closed || !b.closed {
+		t.Errorf("read %q, Close == %v, closed %v %v", got, err, a.closed, b.closed)
+	}
+}
@@ -0,0 +1,144 @@
+// Package kv provides an in-memory key/value store that is safe for
+// concurrent use and can be persisted to JSON snapshots.
+package kv
+
+import "encoding/json"
+import "errors"
+import "fmt"
+import "io"
+import "io/fs"
+import "os"
+import "path/filepath"
+import "sort"
+import "sync"
+
+// ErrNotFound is returned when a key is not in the store.
+var ErrNotFound = errors.New("kv: key not found")
+
+// Store maps string keys to byte values. The zero value is an empty store
+// ready to use.
+type Store struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+}
+
+// New returns an empty store.
+func New() *Store {
+	return &Store{}
+}
+
+// Get returns a copy of the value stored under key.
+func (s *Store) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.data[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), v...), nil
+}
+
+// Put stores a copy of value under key, replacing any existing value.
+func (s *Store) Put(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.data == nil {
+		s.data = map[string][]byte{}
+	}
+	s.data[key] = append([]byte(nil), value...)
+}
+
+// Delete removes key, returning ErrNotFound if it was not present.
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.data[key]; !ok {
+		return ErrNotFound
+	}
+	delete(s.data, key)
+	return nil
+}
+
+// Keys returns the stored keys in sorted order.
+func (s *Store) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.data))
+	for k := range s.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Len returns the number of stored keys.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.data)
+}
+
+// Save writes a snapshot of the store to w as a JSON object mapping keys to
+// base64-encoded values.
+func (s *Store) Save(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data := s.data
+	if data == nil {
+		data = map[string][]byte{}
+	}
+	return json.NewEncoder(w).Encode(data)
+}
+
+// Load replaces the contents of the store with a snapshot written by Save.
+func (s *Store) Load(r io.Reader) error {
+	var data map[string][]byte
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("kv: loading snapshot: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+	return nil
+}
+
+// SaveFile writes a snapshot to path atomically: it is written to a
+// temporary file in the same directory and renamed into place, so a crash
+// never leaves a partial snapshot behind.
+func (s *Store) SaveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // no-op after a successful rename
+	if err := s.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Open returns a store loaded from the snapshot at path, or an empty store
+// if the file does not exist yet.
+func Open(path string) (*Store, error) {
+	s := New()
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if err := s.Load(f); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
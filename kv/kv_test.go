@@ -0,0 +1,83 @@
+package kv
+
+import "bytes"
+import "path/filepath"
+import "reflect"
+import "strings"
+import "sync"
+import "testing"
+
+func TestStore(t *testing.T) {
+	var s Store
+	if _, err := s.Get("a"); err != ErrNotFound {
+		t.Errorf("Get on empty store: err == %v, want ErrNotFound", err)
+	}
+
+	buf := []byte("one")
+	s.Put("a", buf)
+	buf[0] = 'x' // the store must hold its own copy
+	s.Put("b", []byte("two"))
+	if v, err := s.Get("a"); err != nil || string(v) != "one" {
+		t.Errorf("Get(a) == %q, %v, want \"one\", nil", v, err)
+	}
+	if got, want := s.Keys(), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() == %q, want %q", got, want)
+	}
+
+	if err := s.Delete("a"); err != nil {
+		t.Errorf("Delete(a) == %v", err)
+	}
+	if err := s.Delete("a"); err != ErrNotFound {
+		t.Errorf("second Delete(a) == %v, want ErrNotFound", err)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() == %d, want 1", s.Len())
+	}
+}
+
+func TestStoreConcurrent(t *testing.T) {
+	s := New()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Put("k", []byte{byte(j)})
+				s.Get("k")
+			}
+		}()
+	}
+	wg.Wait()
+	if s.Len() != 1 {
+		t.Errorf("Len() == %d, want 1", s.Len())
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	if err != nil || s.Len() != 0 {
+		t.Fatalf("Open of missing file == %v, %v, want an empty store", s, err)
+	}
+	s.Put("a", []byte{0, 1, 2})
+	s.Put("b", []byte("text"))
+	if err := s.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range s.Keys() {
+		want, _ := s.Get(k)
+		if got, err := loaded.Get(k); err != nil || !bytes.Equal(got, want) {
+			t.Errorf("loaded Get(%q) == %v, %v, want %v", k, got, err, want)
+		}
+	}
+
+	if err := loaded.Load(strings.NewReader("not json")); err == nil {
+		t.Error("Load of invalid snapshot returned no error")
+	}
+}
@@ -0,0 +1,44 @@
+package golib
+
+import "sync"
+import "sync/atomic"
+
+// Lazy is a value computed on first use. Unlike a sync.Once, it returns
+// the initializer's error and does not remember failure: a Get after an
+// error tries again. It is safe for concurrent use.
+type Lazy[T any] struct {
+	init func() (T, error)
+	mu   sync.Mutex // held while init runs
+	v    atomic.Pointer[T]
+}
+
+// NewLazy returns a Lazy whose value is computed by init.
+func NewLazy[T any](init func() (T, error)) *Lazy[T] {
+	return &Lazy[T]{init: init}
+}
+
+// Get returns the value, calling init if it has not yet succeeded.
+// Concurrent calls wait for one call of init.
+func (l *Lazy[T]) Get() (T, error) {
+	if p := l.v.Load(); p != nil {
+		return *p, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if p := l.v.Load(); p != nil {
+		return *p, nil
+	}
+	v, err := l.init()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	l.v.Store(&v)
+	return v, nil
+}
+
+// Reset discards the value, so that the next Get calls init again.
+func (l *Lazy[T]) Reset() { l.v.Store(nil) }
+
+// Loaded reports whether the value has been computed.
+func (l *Lazy[T]) Loaded() bool { return l.v.Load() != nil }
@@ -0,0 +1,50 @@
+package golib
+
+import "errors"
+import "sync"
+import "sync/atomic"
+import "testing"
+
+func TestLazy(t *testing.T) {
+	var calls atomic.Int32
+	l := NewLazy(func() (int, error) { return int(calls.Add(1)), nil })
+	if l.Loaded() {
+		t.Fatal("loaded before Get")
+	}
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := l.Get(); v != 1 || err != nil {
+				t.Errorf("Get = %d, %v", v, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 || !l.Loaded() {
+		t.Fatalf("%d calls, loaded %v", calls.Load(), l.Loaded())
+	}
+	l.Reset()
+	if v, _ := l.Get(); v != 2 {
+		t.Errorf("Get after Reset = %d", v)
+	}
+}
+
+func TestLazyError(t *testing.T) {
+	boom := errors.New("boom")
+	fail := true
+	l := NewLazy(func() (string, error) {
+		if fail {
+			return "", boom
+		}
+		return "ok", nil
+	})
+	if _, err := l.Get(); !errors.Is(err, boom) || l.Loaded() {
+		t.Fatalf("Get error %v, loaded %v", err, l.Loaded())
+	}
+	fail = false
+	if v, err := l.Get(); v != "ok" || err != nil {
+		t.Errorf("Get after error = %q, %v", v, err)
+	}
+}
@@ -0,0 +1,23 @@
+package lessons
+
+import "github.com/lukehedger/golib"
+
+// Default holds the demos of package golib.
+var Default = mustRegister(
+	Lesson{Name: "variables", Summary: "Declarations, zero values and constants", Run: golib.Variables},
+	Lesson{Name: "if", Summary: "Conditions with a short statement", Requires: []string{"variables"}, Run: func() { golib.Conditioner(7) }},
+	Lesson{Name: "for", Summary: "Go's only loop, with and without its clauses", Requires: []string{"variables"}, Run: golib.Looper},
+	Lesson{Name: "switch", Summary: "Switch with and without a condition", Requires: []string{"if"}, Run: golib.Switcheroo},
+	Lesson{Name: "pointers", Summary: "Taking addresses and dereferencing", Requires: []string{"variables"}, Run: golib.Pointers},
+	Lesson{Name: "structs", Summary: "Fields, literals and struct pointers", Requires: []string{"pointers"}, Run: golib.Structs},
+)
+
+func mustRegister(lessons ...Lesson) *Registry {
+	r := &Registry{}
+	for _, l := range lessons {
+		if err := r.Register(l); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
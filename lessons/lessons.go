@@ -0,0 +1,199 @@
+// Package lessons registers the golib demos as lessons that can declare
+// prerequisites, such as pointers before structs, and orders them into a
+// learning path in which every lesson comes after those it requires.
+package lessons
+
+import "errors"
+import "fmt"
+import "slices"
+import "strings"
+
+// ErrUnknown is returned for a lesson name that is not registered.
+var ErrUnknown = errors.New("lessons: unknown lesson")
+
+// A Lesson is a demo with the lessons it builds on.
+type Lesson struct {
+	Name    string
+	Summary string
+	// Requires names the lessons to take first.
+	Requires []string
+	// Run runs the demo, which prints to standard output.
+	Run func()
+}
+
+// A CycleError reports lessons that require each other in a cycle, so
+// that no order puts each after its prerequisites.
+type CycleError struct {
+	// Cycle names the lessons in the cycle, each requiring the next and
+	// the last requiring the first.
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "lessons: prerequisite cycle: " + strings.Join(e.Cycle, " -> ") + " -> " + e.Cycle[0]
+}
+
+// Registry holds lessons by name. The zero value is empty and ready to
+// use. It is not safe for concurrent use.
+type Registry struct {
+	lessons []Lesson // in registration order
+	index   map[string]int
+}
+
+// Register adds l. Its prerequisites need not be registered yet, but must
+// be by the time a path is computed. It is an error to register a name
+// twice.
+func (r *Registry) Register(l Lesson) error {
+	if _, ok := r.index[l.Name]; ok {
+		return fmt.Errorf("lessons: %q registered twice", l.Name)
+	}
+	if r.index == nil {
+		r.index = map[string]int{}
+	}
+	r.index[l.Name] = len(r.lessons)
+	r.lessons = append(r.lessons, l)
+	return nil
+}
+
+// Lookup returns the lesson called name.
+func (r *Registry) Lookup(name string) (Lesson, bool) {
+	i, ok := r.index[name]
+	if !ok {
+		return Lesson{}, false
+	}
+	return r.lessons[i], true
+}
+
+// Lessons returns the lessons in the order they were registered.
+func (r *Registry) Lessons() []Lesson { return slices.Clone(r.lessons) }
+
+// Path returns every lesson in a learning order: each after the lessons it
+// requires. The order is stable: of the lessons ready to take at any
+// point, the one registered first comes first, so a registry registered
+// in a sensible order keeps it. It returns a *CycleError if prerequisites
+// form a cycle, or an error wrapping ErrUnknown for a prerequisite that is
+// not registered.
+func (r *Registry) Path() ([]Lesson, error) {
+	all := make([]bool, len(r.lessons))
+	for i := range all {
+		all[i] = true
+	}
+	return r.path(all)
+}
+
+// PathTo returns the learning path to the lesson called name: it and
+// everything it requires, directly or not, in the order Path gives them.
+func (r *Registry) PathTo(name string) ([]Lesson, error) {
+	i, ok := r.index[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknown, name)
+	}
+	want := make([]bool, len(r.lessons))
+	stack := []int{i}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if want[i] {
+			continue
+		}
+		want[i] = true
+		deps, err := r.requires(i)
+		if err != nil {
+			return nil, err
+		}
+		stack = append(stack, deps...)
+	}
+	return r.path(want)
+}
+
+// requires returns the indexes of lesson i's prerequisites.
+func (r *Registry) requires(i int) ([]int, error) {
+	var deps []int
+	for _, name := range r.lessons[i].Requires {
+		j, ok := r.index[name]
+		if !ok {
+			return nil, fmt.Errorf("%w %q, required by %q", ErrUnknown, name, r.lessons[i].Name)
+		}
+		deps = append(deps, j)
+	}
+	return deps, nil
+}
+
+// path sorts the wanted lessons topologically, taking the earliest
+// registered of those whose prerequisites are all done each time.
+func (r *Registry) path(want []bool) ([]Lesson, error) {
+	pending := make([]int, len(r.lessons)) // prerequisites not yet taken
+	dependents := make([][]int, len(r.lessons))
+	for i := range r.lessons {
+		if !want[i] {
+			continue
+		}
+		deps, err := r.requires(i)
+		if err != nil {
+			return nil, err
+		}
+		for _, j := range deps {
+			if want[j] {
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	var ready, path []int
+	for i := range r.lessons {
+		if want[i] && pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	for len(ready) > 0 {
+		i := ready[0] // ready is kept sorted, so this was registered first
+		ready = ready[1:]
+		path = append(path, i)
+		for _, j := range dependents[i] {
+			if pending[j]--; pending[j] == 0 {
+				k, _ := slices.BinarySearch(ready, j)
+				ready = slices.Insert(ready, k, j)
+			}
+		}
+	}
+
+	for i := range r.lessons {
+		if want[i] && pending[i] > 0 {
+			return nil, &CycleError{r.cycle(i, pending)}
+		}
+	}
+	lessons := make([]Lesson, len(path))
+	for k, i := range path {
+		lessons[k] = r.lessons[i]
+	}
+	return lessons, nil
+}
+
+// cycle follows prerequisites that were never taken from lesson i until
+// one repeats, and returns the names around the loop. Each such lesson
+// has at least one such prerequisite, so the walk must loop.
+func (r *Registry) cycle(i int, pending []int) []string {
+	seen := map[int]int{} // lesson to its position on the walk
+	var walk []int
+	for {
+		if k, ok := seen[i]; ok {
+			walk = walk[k:]
+			break
+		}
+		seen[i] = len(walk)
+		walk = append(walk, i)
+		deps, _ := r.requires(i)
+		for _, j := range deps {
+			if pending[j] > 0 {
+				i = j
+				break
+			}
+		}
+	}
+	names := make([]string, len(walk))
+	for k, i := range walk {
+		names[k] = r.lessons[i].Name
+	}
+	return names
+}
@@ -0,0 +1,85 @@
+package lessons
+
+import "errors"
+import "slices"
+import "testing"
+
+func names(ls []Lesson) []string {
+	var s []string
+	for _, l := range ls {
+		s = append(s, l.Name)
+	}
+	return s
+}
+
+func registry(t *testing.T, lessons ...Lesson) *Registry {
+	t.Helper()
+	r := &Registry{}
+	for _, l := range lessons {
+		if err := r.Register(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return r
+}
+
+func TestPath(t *testing.T) {
+	r := registry(t,
+		Lesson{Name: "structs", Requires: []string{"pointers"}},
+		Lesson{Name: "maps", Requires: []string{"variables"}},
+		Lesson{Name: "pointers", Requires: []string{"variables"}},
+		Lesson{Name: "variables"},
+		Lesson{Name: "methods", Requires: []string{"structs", "pointers"}},
+	)
+	path, err := r.Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ties go to the lesson registered first.
+	if got, want := names(path), []string{"variables", "maps", "pointers", "structs", "methods"}; !slices.Equal(got, want) {
+		t.Errorf("Path() == %q, want %q", got, want)
+	}
+
+	path, err = r.PathTo("structs")
+	if got, want := names(path), []string{"variables", "pointers", "structs"}; err != nil || !slices.Equal(got, want) {
+		t.Errorf("PathTo(structs) == %q, %v, want %q", got, err, want)
+	}
+	if _, err := r.PathTo("generics"); !errors.Is(err, ErrUnknown) {
+		t.Errorf("PathTo(generics) error == %v, want ErrUnknown", err)
+	}
+}
+
+func TestPathErrors(t *testing.T) {
+	r := registry(t,
+		Lesson{Name: "a"},
+		Lesson{Name: "b", Requires: []string{"d"}},
+		Lesson{Name: "c", Requires: []string{"b"}},
+		Lesson{Name: "d", Requires: []string{"c", "a"}},
+	)
+	_, err := r.Path()
+	var ce *CycleError
+	if !errors.As(err, &ce) || len(ce.Cycle) != 3 {
+		t.Fatalf("Path() error == %v, want a cycle of three", err)
+	}
+	if _, err := r.PathTo("a"); err != nil {
+		t.Errorf("PathTo(a) outside the cycle: %v", err)
+	}
+
+	r = registry(t, Lesson{Name: "a", Requires: []string{"missing"}})
+	if _, err := r.Path(); !errors.Is(err, ErrUnknown) {
+		t.Errorf("Path() with missing prerequisite: %v", err)
+	}
+	if err := r.Register(Lesson{Name: "a"}); err == nil {
+		t.Error("Register accepted a duplicate")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	path, err := Default.Path()
+	if err != nil || len(path) != len(Default.Lessons()) {
+		t.Fatalf("Default.Path() == %d lessons, %v", len(path), err)
+	}
+	if l, ok := Default.Lookup("structs"); !ok || l.Run == nil {
+		t.Error("Default has no runnable structs lesson")
+	}
+}
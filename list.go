@@ -0,0 +1,196 @@
+package golib
+
+import "iter"
+
+// List is a doubly linked list of T, a typed alternative to
+// container/list. The zero value is an empty list ready to use.
+//
+// Unlike container/list, elements do not record which list they are in,
+// so that whole lists can be spliced together in constant time. Methods
+// that take an element require it to be in the list they are called on,
+// except Remove, which ignores an element that has already been removed.
+type List[T any] struct {
+	head, tail *Element[T]
+	len        int
+}
+
+// An Element is an element of a List.
+type Element[T any] struct {
+	Value      T
+	next, prev *Element[T]
+	linked     bool
+}
+
+// Next returns the element after e, or nil if e is the last.
+func (e *Element[T]) Next() *Element[T] { return e.next }
+
+// Prev returns the element before e, or nil if e is the first.
+func (e *Element[T]) Prev() *Element[T] { return e.prev }
+
+// NewList returns a list of values, in order.
+func NewList[T any](values ...T) *List[T] {
+	l := &List[T]{}
+	for _, v := range values {
+		l.PushBack(v)
+	}
+	return l
+}
+
+// Len returns the number of elements in l.
+func (l *List[T]) Len() int { return l.len }
+
+// Front returns the first element of l, or nil if l is empty.
+func (l *List[T]) Front() *Element[T] { return l.head }
+
+// Back returns the last element of l, or nil if l is empty.
+func (l *List[T]) Back() *Element[T] { return l.tail }
+
+// PushFront inserts v at the front of l and returns its element.
+func (l *List[T]) PushFront(v T) *Element[T] { return l.insert(&Element[T]{Value: v}, nil, l.head) }
+
+// PushBack inserts v at the back of l and returns its element.
+func (l *List[T]) PushBack(v T) *Element[T] { return l.insert(&Element[T]{Value: v}, l.tail, nil) }
+
+// InsertBefore inserts v immediately before mark and returns its element.
+func (l *List[T]) InsertBefore(v T, mark *Element[T]) *Element[T] {
+	return l.insert(&Element[T]{Value: v}, mark.prev, mark)
+}
+
+// InsertAfter inserts v immediately after mark and returns its element.
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	return l.insert(&Element[T]{Value: v}, mark, mark.next)
+}
+
+// Remove removes e from l and returns its value.
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.linked {
+		l.unlink(e)
+	}
+	return e.Value
+}
+
+// MoveToFront moves e to the front of l.
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if l.head != e {
+		l.insert(l.unlink(e), nil, l.head)
+	}
+}
+
+// MoveToBack moves e to the back of l.
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if l.tail != e {
+		l.insert(l.unlink(e), l.tail, nil)
+	}
+}
+
+// MoveBefore moves e to immediately before mark.
+func (l *List[T]) MoveBefore(e, mark *Element[T]) {
+	if e != mark && e.next != mark {
+		l.unlink(e)
+		l.insert(e, mark.prev, mark)
+	}
+}
+
+// MoveAfter moves e to immediately after mark.
+func (l *List[T]) MoveAfter(e, mark *Element[T]) {
+	if e != mark && e.prev != mark {
+		l.unlink(e)
+		l.insert(e, mark, mark.next)
+	}
+}
+
+// SpliceFront moves every element of other to the front of l, in order,
+// leaving other empty. The elements stay valid and are now in l.
+func (l *List[T]) SpliceFront(other *List[T]) { l.splice(other, nil, l.head) }
+
+// SpliceBack moves every element of other to the back of l, in order,
+// leaving other empty.
+func (l *List[T]) SpliceBack(other *List[T]) { l.splice(other, l.tail, nil) }
+
+// SpliceAfter moves every element of other to immediately after mark, in
+// order, leaving other empty.
+func (l *List[T]) SpliceAfter(mark *Element[T], other *List[T]) { l.splice(other, mark, mark.next) }
+
+// All yields the values of l from front to back. l must not be changed
+// during the iteration except by removing the element just yielded.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.head; e != nil; {
+			next := e.next
+			if !yield(e.Value) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// Backward yields the values of l from back to front, under the same
+// rules as All.
+func (l *List[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := l.tail; e != nil; {
+			prev := e.prev
+			if !yield(e.Value) {
+				return
+			}
+			e = prev
+		}
+	}
+}
+
+// insert links e between prev and next, either of which may be nil at
+// the ends of the list.
+func (l *List[T]) insert(e, prev, next *Element[T]) *Element[T] {
+	e.prev, e.next, e.linked = prev, next, true
+	if prev == nil {
+		l.head = e
+	} else {
+		prev.next = e
+	}
+	if next == nil {
+		l.tail = e
+	} else {
+		next.prev = e
+	}
+	l.len++
+	return e
+}
+
+// unlink removes e from l and returns it.
+func (l *List[T]) unlink(e *Element[T]) *Element[T] {
+	if e.prev == nil {
+		l.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		l.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+	e.prev, e.next, e.linked = nil, nil, false
+	l.len--
+	return e
+}
+
+// splice moves the elements of other between prev and next in l.
+func (l *List[T]) splice(other *List[T], prev, next *Element[T]) {
+	if other == l || other.len == 0 {
+		return
+	}
+	first, last := other.head, other.tail
+	first.prev, last.next = prev, next
+	if prev == nil {
+		l.head = first
+	} else {
+		prev.next = first
+	}
+	if next == nil {
+		l.tail = last
+	} else {
+		next.prev = last
+	}
+	l.len += other.len
+	*other = List[T]{}
+}
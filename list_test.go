@@ -0,0 +1,97 @@
+package golib
+
+import "slices"
+import "testing"
+
+// checkList verifies l's links in both directions against want.
+func checkList[T comparable](t *testing.T, l *List[T], want ...T) {
+	t.Helper()
+	if got := slices.Collect(l.All()); !slices.Equal(got, want) || l.Len() != len(want) {
+		t.Fatalf("list == %v with Len %d, want %v", got, l.Len(), want)
+	}
+	back := slices.Collect(l.Backward())
+	slices.Reverse(back)
+	if !slices.Equal(back, want) {
+		t.Fatalf("list backward == %v, want %v reversed", back, want)
+	}
+}
+
+func TestList(t *testing.T) {
+	var l List[int]
+	checkList(t, &l)
+	two := l.PushBack(2)
+	one := l.PushFront(1)
+	four := l.PushBack(4)
+	l.InsertAfter(3, two)
+	l.InsertBefore(0, one)
+	checkList(t, &l, 0, 1, 2, 3, 4)
+
+	if v := l.Remove(two); v != 2 {
+		t.Errorf("Remove == %d, want 2", v)
+	}
+	l.Remove(two) // already removed: no-op
+	checkList(t, &l, 0, 1, 3, 4)
+
+	l.MoveToFront(four)
+	checkList(t, &l, 4, 0, 1, 3)
+	l.MoveToBack(four)
+	checkList(t, &l, 0, 1, 3, 4)
+	l.MoveToBack(four)
+	checkList(t, &l, 0, 1, 3, 4)
+	l.MoveBefore(four, one)
+	checkList(t, &l, 0, 4, 1, 3)
+	l.MoveAfter(four, l.Back())
+	checkList(t, &l, 0, 1, 3, 4)
+	l.MoveAfter(one, four)
+	checkList(t, &l, 0, 3, 4, 1)
+
+	if l.Front().Value != 0 || l.Back().Prev().Value != 4 || l.Back().Next() != nil {
+		t.Error("Front, Back, Next or Prev wrong")
+	}
+
+	l.Remove(l.Front())
+	l.Remove(l.Back())
+	checkList(t, &l, 3, 4)
+	l.Remove(l.Front())
+	l.Remove(l.Front())
+	checkList(t, &l)
+	if l.Front() != nil || l.Back() != nil {
+		t.Error("empty list has elements")
+	}
+}
+
+func TestListSplice(t *testing.T) {
+	l := NewList(1, 2)
+	other := NewList(3, 4)
+	e := other.Front()
+	l.SpliceBack(other)
+	checkList(t, l, 1, 2, 3, 4)
+	checkList(t, other)
+
+	// The moved elements stay valid in l.
+	l.MoveToFront(e)
+	checkList(t, l, 3, 1, 2, 4)
+
+	l.SpliceFront(NewList(5, 6))
+	checkList(t, l, 5, 6, 3, 1, 2, 4)
+	l.SpliceAfter(e, NewList(7))
+	checkList(t, l, 5, 6, 3, 7, 1, 2, 4)
+	l.SpliceAfter(l.Back(), NewList(8, 9))
+	checkList(t, l, 5, 6, 3, 7, 1, 2, 4, 8, 9)
+	l.SpliceBack(NewList[int]())
+	l.SpliceBack(l)
+	checkList(t, l, 5, 6, 3, 7, 1, 2, 4, 8, 9)
+}
+
+func TestListRemoveDuringAll(t *testing.T) {
+	l := NewList(1, 2, 3, 4)
+	e := l.Front()
+	for v := range l.All() {
+		next := e.Next()
+		if v%2 == 0 {
+			l.Remove(e)
+		}
+		e = next
+	}
+	checkList(t, l, 1, 3)
+}
@@ -0,0 +1,210 @@
+// Package loadtest drives an HTTP endpoint with concurrent requests at a
+// target rate and reports throughput, status codes and latency
+// percentiles.
+package loadtest
+
+import "bytes"
+import "context"
+import "errors"
+import "fmt"
+import "io"
+import "net/http"
+import "net/url"
+import "slices"
+import "sync"
+import "sync/atomic"
+import "time"
+
+import "github.com/lukehedger/golib/ratelimit"
+import "github.com/lukehedger/golib/stats"
+
+// Defaults for the zero fields of a Config.
+const (
+	DefaultWorkers  = 10
+	DefaultDuration = 10 * time.Second
+	DefaultTimeout  = 10 * time.Second
+)
+
+// Config describes a load test.
+type Config struct {
+	Method string // default GET
+	URL    string
+	Header http.Header
+	Body   []byte
+
+	// Rate is the target number of requests per second across all
+	// workers. Zero sends requests as fast as the workers can.
+	Rate float64
+	// Workers is the number of concurrent requests.
+	Workers int
+	// Duration is how long to send requests for. If both Duration and
+	// Requests are zero, DefaultDuration is used.
+	Duration time.Duration
+	// Requests, if positive, stops the test after that many requests.
+	Requests int
+	// Timeout bounds each request.
+	Timeout time.Duration
+	// Client sends the requests. If nil, a client is created whose
+	// transport keeps one idle connection per worker.
+	Client *http.Client
+}
+
+// Result is the outcome of a load test. Requests that received a
+// response of any status, including 5xx, are counted in Latencies and
+// Statuses; requests that failed without a response are counted in
+// Errors.
+type Result struct {
+	Requests  int
+	Latencies []time.Duration // sorted
+	Statuses  map[int]int
+	Errors    map[string]int // by error message
+	Bytes     int64          // response body bytes read
+	Elapsed   time.Duration
+}
+
+// Failed returns the number of requests that got no response.
+func (r *Result) Failed() int {
+	n := 0
+	for _, c := range r.Errors {
+		n += c
+	}
+	return n
+}
+
+// RPS returns the achieved throughput in requests per second.
+func (r *Result) RPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// Percentile returns the pth percentile latency, for p in [0, 100], or
+// zero if no request got a response.
+func (r *Result) Percentile(p float64) time.Duration {
+	v, err := stats.Percentile(r.Latencies, p)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(v)
+}
+
+// Run performs the load test described by cfg. It returns early if ctx
+// is done, with the results so far and ctx.Err().
+func Run(ctx context.Context, cfg Config) (*Result, error) {
+	if cfg.Method == "" {
+		cfg.Method = http.MethodGet
+	}
+	if _, err := url.ParseRequestURI(cfg.URL); err != nil {
+		return nil, fmt.Errorf("loadtest: %w", err)
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.Duration <= 0 && cfg.Requests <= 0 {
+		cfg.Duration = DefaultDuration
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: cfg.Workers}}
+	}
+
+	// stop ends the test; requests in flight when it fires are allowed to
+	// finish, so the last few are not miscounted as failures.
+	stop := ctx
+	if cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		stop, cancel = context.WithTimeout(ctx, cfg.Duration)
+		defer cancel()
+	}
+	var limiter *ratelimit.Limiter
+	if cfg.Rate > 0 {
+		limiter = ratelimit.New(cfg.Rate, 1)
+	}
+
+	var (
+		claimed atomic.Int64
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		res     = &Result{Statuses: map[int]int{}, Errors: map[string]int{}}
+	)
+	start := time.Now()
+	for range cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := &Result{Statuses: map[int]int{}, Errors: map[string]int{}}
+			defer func() {
+				mu.Lock()
+				res.merge(w)
+				mu.Unlock()
+			}()
+			for stop.Err() == nil {
+				if cfg.Requests > 0 && claimed.Add(1) > int64(cfg.Requests) {
+					return
+				}
+				if limiter != nil && limiter.Wait(stop) != nil {
+					return
+				}
+				w.do(ctx, client, &cfg)
+			}
+		}()
+	}
+	wg.Wait()
+	res.Elapsed = time.Since(start)
+	slices.Sort(res.Latencies)
+	return res, ctx.Err()
+}
+
+// do sends one request and records its outcome in r.
+func (r *Result) do(ctx context.Context, client *http.Client, cfg *Config) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, cfg.URL, bytes.NewReader(cfg.Body))
+	if err != nil {
+		r.Requests++
+		r.Errors[err.Error()]++
+		return
+	}
+	for k, vs := range cfg.Header {
+		req.Header[k] = vs
+	}
+	if host := cfg.Header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	t := time.Now()
+	resp, err := client.Do(req)
+	if err == nil {
+		var n int64
+		n, err = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		r.Bytes += n
+	}
+	r.Requests++
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		r.Errors[err.Error()]++
+		return
+	}
+	r.Latencies = append(r.Latencies, time.Since(t))
+	r.Statuses[resp.StatusCode]++
+}
+
+func (r *Result) merge(w *Result) {
+	r.Requests += w.Requests
+	r.Latencies = append(r.Latencies, w.Latencies...)
+	r.Bytes += w.Bytes
+	for k, n := range w.Statuses {
+		r.Statuses[k] += n
+	}
+	for k, n := range w.Errors {
+		r.Errors[k] += n
+	}
+}
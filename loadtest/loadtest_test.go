@@ -0,0 +1,96 @@
+package loadtest
+
+import "context"
+import "io"
+import "net/http"
+import "net/http/httptest"
+import "sync/atomic"
+import "testing"
+import "time"
+
+func TestRunRequests(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("X-Test") != "1" {
+			t.Errorf("got %s with X-Test %q", r.Method, r.Header.Get("X-Test"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "ping" {
+			t.Errorf("body == %q", body)
+		}
+		if hits.Add(1)%5 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		io.WriteString(w, "pong")
+	}))
+	defer srv.Close()
+
+	res, err := Run(context.Background(), Config{
+		Method:   http.MethodPost,
+		URL:      srv.URL,
+		Header:   http.Header{"X-Test": {"1"}},
+		Body:     []byte("ping"),
+		Workers:  4,
+		Requests: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requests != 50 || hits.Load() != 50 || len(res.Latencies) != 50 || res.Failed() != 0 {
+		t.Errorf("Requests %d, hits %d, latencies %d, failed %d", res.Requests, hits.Load(), len(res.Latencies), res.Failed())
+	}
+	if res.Statuses[200] != 40 || res.Statuses[500] != 10 {
+		t.Errorf("Statuses == %v", res.Statuses)
+	}
+	if res.Bytes != 200 {
+		t.Errorf("Bytes == %d", res.Bytes)
+	}
+	if res.Percentile(0) != res.Latencies[0] || res.Percentile(100) != res.Latencies[49] || res.Percentile(50) <= 0 {
+		t.Errorf("Percentile(0, 50, 100) == %v, %v, %v", res.Percentile(0), res.Percentile(50), res.Percentile(100))
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	res, err := Run(context.Background(), Config{URL: srv.URL, Rate: 50, Workers: 5, Duration: 400 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The bucket starts with one token, then refills at 50/s.
+	if res.Requests < 15 || res.Requests > 25 {
+		t.Errorf("Requests == %d at 50/s for 400ms", res.Requests)
+	}
+	if rps := res.RPS(); rps < 35 || rps > 60 {
+		t.Errorf("RPS() == %v", rps)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	res, err := Run(context.Background(), Config{URL: srv.URL, Workers: 2, Requests: 4, Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Failed() != 4 || len(res.Latencies) != 0 || res.Errors[context.DeadlineExceeded.Error()] != 4 {
+		t.Errorf("Errors == %v", res.Errors)
+	}
+	if res.Percentile(50) != 0 {
+		t.Errorf("Percentile(50) with no responses == %v", res.Percentile(50))
+	}
+
+	if _, err := Run(context.Background(), Config{URL: "not a url"}); err == nil {
+		t.Error("Run accepted a bad URL")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := Run(ctx, Config{URL: srv.URL, Duration: time.Hour}); err != context.DeadlineExceeded {
+		t.Errorf("Run with a cancelled context: err == %v", err)
+	}
+}
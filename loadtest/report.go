@@ -0,0 +1,74 @@
+package loadtest
+
+import "fmt"
+import "io"
+import "slices"
+import "strings"
+import "time"
+
+import "github.com/lukehedger/golib"
+import "github.com/lukehedger/golib/stats"
+import "github.com/lukehedger/golib/termplot"
+
+// WriteTo writes a plain-text report of r: throughput, status codes,
+// errors, latency percentiles and a latency histogram.
+func (r *Result) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "requests: %d in %s (%.1f/s), failed: %d, received: %s\n",
+		r.Requests, r.Elapsed.Round(time.Millisecond), r.RPS(), r.Failed(), golib.HumanizeBytes(r.Bytes))
+
+	if len(r.Statuses) > 0 {
+		b.WriteString("\n")
+		t := new(golib.Table).SetHeaders("STATUS", "COUNT").SetAlign(1, golib.AlignRight)
+		codes := make([]int, 0, len(r.Statuses))
+		for c := range r.Statuses {
+			codes = append(codes, c)
+		}
+		slices.Sort(codes)
+		for _, c := range codes {
+			t.AddRow(c, r.Statuses[c])
+		}
+		t.Render(&b)
+	}
+
+	if len(r.Errors) > 0 {
+		b.WriteString("\n")
+		t := new(golib.Table).SetHeaders("ERROR", "COUNT").SetAlign(1, golib.AlignRight).SetMaxWidth(70)
+		msgs := make([]string, 0, len(r.Errors))
+		for m := range r.Errors {
+			msgs = append(msgs, m)
+		}
+		slices.SortFunc(msgs, func(a, b string) int { return r.Errors[b] - r.Errors[a] })
+		for _, m := range msgs {
+			t.AddRow(m, r.Errors[m])
+		}
+		t.Render(&b)
+	}
+
+	if len(r.Latencies) > 0 {
+		b.WriteString("\n")
+		t := new(golib.Table).SetHeaders("MIN", "P50", "P90", "P95", "P99", "MAX")
+		row := []any{r.Latencies[0]}
+		for _, p := range []float64{50, 90, 95, 99} {
+			row = append(row, r.Percentile(p))
+		}
+		row = append(row, r.Latencies[len(r.Latencies)-1])
+		for i := range row {
+			row[i] = row[i].(time.Duration).Round(10 * time.Microsecond)
+			t.SetAlign(i, golib.AlignRight)
+		}
+		t.AddRow(row...)
+		t.Render(&b)
+
+		ms := make([]float64, len(r.Latencies))
+		for i, d := range r.Latencies {
+			ms[i] = float64(d) / float64(time.Millisecond)
+		}
+		bins, _ := stats.Histogram(ms, 10)
+		b.WriteString("\nlatency (ms)\n")
+		termplot.Histogram(&b, bins, 40)
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
@@ -0,0 +1,33 @@
+package loadtest
+
+import "strings"
+import "testing"
+import "time"
+
+func TestResultWriteTo(t *testing.T) {
+	r := &Result{
+		Requests:  4,
+		Latencies: []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond},
+		Statuses:  map[int]int{200: 2, 503: 1},
+		Errors:    map[string]int{"connection refused": 1},
+		Bytes:     2048,
+		Elapsed:   2 * time.Second,
+	}
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"requests: 4 in 2s (2.0/s), failed: 1, received: 2 KiB\n",
+		"STATUS  COUNT\n200         2\n503         1\n",
+		"connection refused      1\n",
+		"MIN  P50    P90    P95     P99  MAX\n",
+		"1ms  2ms  2.8ms  2.9ms  2.98ms  3ms\n",
+		"latency (ms)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package loganalyze
+
+import "bufio"
+import "cmp"
+import "io"
+import "regexp"
+import "slices"
+import "time"
+
+// DefaultTop is the number of messages reported when Options.Top is zero.
+const DefaultTop = 10
+
+// Options configures an Analyzer. The zero value is ready to use.
+type Options struct {
+	// Window is the width of the time buckets for error rates. Zero
+	// disables them.
+	Window time.Duration
+	// Top is the number of distinct messages to keep in Report.Top.
+	// Zero means DefaultTop.
+	Top int
+	// Since and Until, if non-zero, discard entries outside the range
+	// [Since, Until). Entries without a time are always kept.
+	Since, Until time.Time
+}
+
+// A Count is a value and the number of entries that had it.
+type Count[T any] struct {
+	Value T
+	N     int
+}
+
+// A Window is the entries in a time bucket.
+type Window struct {
+	Start  time.Time
+	Total  int
+	Errors int
+}
+
+// ErrorRate returns the fraction of entries in w that are errors.
+func (w Window) ErrorRate() float64 {
+	if w.Total == 0 {
+		return 0
+	}
+	return float64(w.Errors) / float64(w.Total)
+}
+
+// A Report summarises the entries given to an Analyzer.
+type Report struct {
+	Lines    int       // lines read, whether parsed or not
+	Unparsed int       // lines no parser matched
+	Entries  int       // entries counted, after the time range
+	Errors   int       // entries for which Entry.IsError is true
+	First    time.Time // earliest entry time
+	Last     time.Time // latest entry time
+
+	Levels   []Count[string] // by count, most frequent first
+	Statuses []Count[int]    // by status code
+	Top      []Count[string] // message templates; see Template
+	Windows  []Window        // in time order, including empty windows
+	Window   time.Duration
+}
+
+// An Analyzer accumulates entries into a Report.
+type Analyzer struct {
+	opts     Options
+	report   Report
+	levels   map[string]int
+	statuses map[int]int
+	messages map[string]int
+	windows  map[time.Time]*Window
+}
+
+// NewAnalyzer returns an Analyzer with the given options.
+func NewAnalyzer(opts Options) *Analyzer {
+	if opts.Top <= 0 {
+		opts.Top = DefaultTop
+	}
+	return &Analyzer{
+		opts:     opts,
+		levels:   map[string]int{},
+		statuses: map[int]int{},
+		messages: map[string]int{},
+		windows:  map[time.Time]*Window{},
+	}
+}
+
+// Add records a parsed entry.
+func (a *Analyzer) Add(e Entry) {
+	r := &a.report
+	if !e.Time.IsZero() {
+		if !a.opts.Since.IsZero() && e.Time.Before(a.opts.Since) ||
+			!a.opts.Until.IsZero() && !e.Time.Before(a.opts.Until) {
+			return
+		}
+		if r.First.IsZero() || e.Time.Before(r.First) {
+			r.First = e.Time
+		}
+		if e.Time.After(r.Last) {
+			r.Last = e.Time
+		}
+	}
+	r.Entries++
+	isErr := e.IsError()
+	if isErr {
+		r.Errors++
+	}
+	if e.Level != "" {
+		a.levels[e.Level]++
+	}
+	if e.Status != 0 {
+		a.statuses[e.Status]++
+	}
+	if e.Message != "" {
+		a.messages[Template(e.Message)]++
+	}
+	if a.opts.Window > 0 && !e.Time.IsZero() {
+		start := e.Time.Truncate(a.opts.Window)
+		w := a.windows[start]
+		if w == nil {
+			w = &Window{Start: start}
+			a.windows[start] = w
+		}
+		w.Total++
+		if isErr {
+			w.Errors++
+		}
+	}
+}
+
+// Read parses each line of r with p and adds the entries, counting lines
+// p rejects as unparsed.
+func (a *Analyzer) Read(r io.Reader, p Parser) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" {
+			continue
+		}
+		a.report.Lines++
+		e, ok := p.Parse(line)
+		if !ok {
+			a.report.Unparsed++
+			continue
+		}
+		a.Add(e)
+	}
+	return s.Err()
+}
+
+// Report returns a summary of the entries added so far.
+func (a *Analyzer) Report() *Report {
+	r := a.report
+	r.Levels = counts(a.levels, func(x, y Count[string]) int {
+		return cmp.Or(cmp.Compare(y.N, x.N), cmp.Compare(x.Value, y.Value))
+	})
+	r.Statuses = counts(a.statuses, func(x, y Count[int]) int { return cmp.Compare(x.Value, y.Value) })
+	r.Top = counts(a.messages, func(x, y Count[string]) int {
+		return cmp.Or(cmp.Compare(y.N, x.N), cmp.Compare(x.Value, y.Value))
+	})
+	r.Top = r.Top[:min(len(r.Top), a.opts.Top)]
+
+	r.Window = a.opts.Window
+	if len(a.windows) > 0 {
+		first := r.First.Truncate(r.Window)
+		for t := first; !t.After(r.Last); t = t.Add(r.Window) {
+			if w := a.windows[t]; w != nil {
+				r.Windows = append(r.Windows, *w)
+			} else {
+				r.Windows = append(r.Windows, Window{Start: t})
+			}
+		}
+	}
+	return &r
+}
+
+func counts[T comparable](m map[T]int, order func(a, b Count[T]) int) []Count[T] {
+	cs := make([]Count[T], 0, len(m))
+	for v, n := range m {
+		cs = append(cs, Count[T]{v, n})
+	}
+	slices.SortFunc(cs, order)
+	return cs
+}
+
+// Analyze reads r with p and returns the report.
+func Analyze(r io.Reader, p Parser, opts Options) (*Report, error) {
+	a := NewAnalyzer(opts)
+	if err := a.Read(r, p); err != nil {
+		return nil, err
+	}
+	return a.Report(), nil
+}
+
+var variable = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b|\b0x[0-9a-fA-F]+\b|\d+(?:\.\d+)+|\b[0-9a-fA-F]*[0-9][0-9a-fA-F]*\b|\d+`)
+
+// Template returns msg with the parts that usually vary between otherwise
+// identical messages, such as numbers, hex IDs and UUIDs, replaced by
+// "*", so that "timeout after 30s on conn 17" and "timeout after 5s on
+// conn 4" group together.
+func Template(msg string) string {
+	return variable.ReplaceAllString(msg, "*")
+}
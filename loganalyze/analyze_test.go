@@ -0,0 +1,80 @@
+package loganalyze
+
+import "reflect"
+import "strings"
+import "testing"
+import "time"
+
+const sample = `2024-05-01 12:00:05 INFO request served in 30ms
+2024-05-01 12:00:10 INFO request served in 12ms
+2024-05-01 12:00:40 ERROR timeout on conn 17
+2024-05-01 12:02:15 ERROR timeout on conn 4
+garbage
+
+2024-05-01 12:02:30 WARN retrying 0xdeadbeef
+2024-05-01 12:02:59 INFO request served in 7ms
+`
+
+func TestAnalyze(t *testing.T) {
+	r, err := Analyze(strings.NewReader(sample), Auto, Options{Window: time.Minute, Top: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Lines != 7 || r.Unparsed != 1 || r.Entries != 6 || r.Errors != 2 {
+		t.Errorf("Lines, Unparsed, Entries, Errors == %d, %d, %d, %d", r.Lines, r.Unparsed, r.Entries, r.Errors)
+	}
+	wantLevels := []Count[string]{{"INFO", 3}, {"ERROR", 2}, {"WARN", 1}}
+	if !reflect.DeepEqual(r.Levels, wantLevels) {
+		t.Errorf("Levels == %v, want %v", r.Levels, wantLevels)
+	}
+	wantTop := []Count[string]{{"request served in *ms", 3}, {"timeout on conn *", 2}}
+	if !reflect.DeepEqual(r.Top, wantTop) {
+		t.Errorf("Top == %v, want %v", r.Top, wantTop)
+	}
+
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	wantWindows := []Window{
+		{base, 3, 1},
+		{base.Add(time.Minute), 0, 0},
+		{base.Add(2 * time.Minute), 3, 1},
+	}
+	if !reflect.DeepEqual(r.Windows, wantWindows) {
+		t.Errorf("Windows == %v, want %v", r.Windows, wantWindows)
+	}
+	if got := r.Windows[0].ErrorRate(); got != 1.0/3 {
+		t.Errorf("ErrorRate() == %v", got)
+	}
+	if r.Windows[1].ErrorRate() != 0 {
+		t.Error("ErrorRate() of an empty window is not zero")
+	}
+}
+
+func TestAnalyzeRange(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	opts := Options{Since: base.Add(30 * time.Second), Until: base.Add(2*time.Minute + 30*time.Second)}
+	r, err := Analyze(strings.NewReader(sample), Auto, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Entries != 2 || r.Errors != 2 || r.Windows != nil {
+		t.Errorf("Entries, Errors, Windows == %d, %d, %v", r.Entries, r.Errors, r.Windows)
+	}
+	if !r.First.Equal(base.Add(40*time.Second)) || !r.Last.Equal(base.Add(135*time.Second)) {
+		t.Errorf("First, Last == %v, %v", r.First, r.Last)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"GET /users/123 HTTP/1.1", "GET /users/* HTTP/*"},
+		{"job 5f2b1c9e-0d1a-4e6b-9f3a-2c7d8e9f0a1b failed", "job * failed"},
+		{"bad checksum 0xFF00 in a1b2c3", "bad checksum * in *"},
+		{"cafe is not a number", "cafe is not a number"},
+		{"took 1.5s", "took *s"},
+	}
+	for _, c := range cases {
+		if got := Template(c.in); got != c.want {
+			t.Errorf("Template(%q) == %q, want %q", c.in, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Package loganalyze parses log files and summarises them: counts by
+// level and HTTP status, the most frequent messages, and error rates over
+// time.
+//
+// Lines are parsed by a Parser. Regexp handles any line-oriented format
+// given a pattern with named groups, JSON handles structured logs such as
+// those written by log/slog, and Auto recognises both common formats
+// without configuration.
+package loganalyze
+
+import "encoding/json"
+import "fmt"
+import "regexp"
+import "strconv"
+import "strings"
+import "time"
+
+// An Entry is one parsed log line. Fields the format does not provide are
+// left zero.
+type Entry struct {
+	Time    time.Time
+	Level   string // upper case, e.g. "ERROR"; see Entry.IsError
+	Status  int    // HTTP status code
+	Message string
+	Fields  map[string]string // any other named groups or JSON keys
+}
+
+// IsError reports whether e records a failure: a level of ERROR, FATAL,
+// CRITICAL or PANIC, or a 5xx status.
+func (e Entry) IsError() bool {
+	switch e.Level {
+	case "ERROR", "FATAL", "CRITICAL", "PANIC":
+		return true
+	}
+	return e.Status >= 500
+}
+
+// A Parser parses a single log line. It reports false if the line is not
+// in its format.
+type Parser interface {
+	Parse(line string) (Entry, bool)
+}
+
+// TimeLayouts are the layouts tried, in order, when a parser has no
+// layout of its own.
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006/01/02 15:04:05.999999999",
+	"02/Jan/2006:15:04:05 -0700",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.ANSIC,
+}
+
+func parseTime(s, layout string) (time.Time, bool) {
+	if layout != "" {
+		t, err := time.Parse(layout, s)
+		return t, err == nil
+	}
+	for _, l := range TimeLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalizeLevel maps level names and their common abbreviations to a
+// canonical upper-case form.
+func normalizeLevel(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	switch s {
+	case "WARNING":
+		return "WARN"
+	case "ERR", "EROR":
+		return "ERROR"
+	case "CRIT":
+		return "CRITICAL"
+	case "DBG":
+		return "DEBUG"
+	case "INF":
+		return "INFO"
+	}
+	return s
+}
+
+// Regexp parses lines matching a regular expression. The named groups
+// "time", "level", "status" and "msg" fill the corresponding Entry fields;
+// any other named groups go into Entry.Fields.
+type Regexp struct {
+	Pattern *regexp.Regexp
+	// TimeLayout is the layout of the "time" group, as for time.Parse.
+	// If empty, each of TimeLayouts is tried.
+	TimeLayout string
+}
+
+// NewRegexp compiles pattern into a Regexp parser.
+func NewRegexp(pattern, timeLayout string) (*Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("loganalyze: %w", err)
+	}
+	return &Regexp{re, timeLayout}, nil
+}
+
+// Parse implements Parser. A line whose "time" or "status" group is
+// present but malformed does not match.
+func (p *Regexp) Parse(line string) (Entry, bool) {
+	m := p.Pattern.FindStringSubmatch(line)
+	if m == nil {
+		return Entry{}, false
+	}
+	var e Entry
+	for i, name := range p.Pattern.SubexpNames() {
+		v := m[i]
+		switch name {
+		case "":
+		case "time":
+			t, ok := parseTime(v, p.TimeLayout)
+			if !ok {
+				return Entry{}, false
+			}
+			e.Time = t
+		case "level":
+			e.Level = normalizeLevel(v)
+		case "status":
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return Entry{}, false
+			}
+			e.Status = n
+		case "msg":
+			e.Message = v
+		default:
+			if e.Fields == nil {
+				e.Fields = map[string]string{}
+			}
+			e.Fields[name] = v
+		}
+	}
+	return e, true
+}
+
+// Combined parses the Combined Log Format written by Apache and nginx,
+// and its Common Log Format subset. The request line is the message.
+var Combined = &Regexp{
+	Pattern:    regexp.MustCompile(`^(?P<host>\S+) \S+ (?P<user>\S+) \[(?P<time>[^\]]+)\] "(?P<msg>[^"]*)" (?P<status>\d{3}) (?P<size>\S+)(?: "(?P<referer>[^"]*)" "(?P<agent>[^"]*)")?`),
+	TimeLayout: "02/Jan/2006:15:04:05 -0700",
+}
+
+// Plain parses lines of the form "<time> <level> <message>", as written
+// by many loggers, with optional brackets around the level or a colon after it.
+var Plain = &Regexp{
+	Pattern: regexp.MustCompile(`^(?P<time>\d{4}[-/]\d\d[-/]\d\d[T ]\d\d:\d\d:\d\d\S*)\s+\[?(?P<level>[A-Za-z]+)\]?:?\s+(?P<msg>.*)$`),
+}
+
+// JSON parses lines holding one JSON object each. Keys are matched
+// exactly; the zero value uses the key names written by log/slog.
+type JSON struct {
+	TimeKey    string // default "time"
+	LevelKey   string // default "level"
+	MessageKey string // default "msg"
+	StatusKey  string // default "status"
+	// TimeLayout is the layout of string times. If empty, each of
+	// TimeLayouts is tried. Numeric times are Unix seconds, which may be
+	// fractional.
+	TimeLayout string
+}
+
+// Parse implements Parser.
+func (p *JSON) Parse(line string) (Entry, bool) {
+	var obj map[string]any
+	d := json.NewDecoder(strings.NewReader(line))
+	d.UseNumber()
+	if err := d.Decode(&obj); err != nil {
+		return Entry{}, false
+	}
+	key := func(k, def string) string {
+		if k == "" {
+			return def
+		}
+		return k
+	}
+	timeKey, levelKey := key(p.TimeKey, "time"), key(p.LevelKey, "level")
+	msgKey, statusKey := key(p.MessageKey, "msg"), key(p.StatusKey, "status")
+
+	var e Entry
+	for k, v := range obj {
+		s := jsonText(v)
+		switch k {
+		case timeKey:
+			if n, ok := v.(json.Number); ok {
+				f, _ := n.Float64()
+				sec := int64(f)
+				e.Time = time.Unix(sec, int64((f-float64(sec))*1e9)).UTC()
+			} else if t, ok := parseTime(s, p.TimeLayout); ok {
+				e.Time = t
+			}
+		case levelKey:
+			e.Level = normalizeLevel(s)
+		case msgKey:
+			e.Message = s
+		case statusKey:
+			e.Status, _ = strconv.Atoi(s)
+		default:
+			if e.Fields == nil {
+				e.Fields = map[string]string{}
+			}
+			e.Fields[k] = s
+		}
+	}
+	return e, true
+}
+
+// jsonText returns a JSON value as text: strings unquoted, other values
+// re-encoded.
+func jsonText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// Auto parses lines as JSON if they begin with "{", and otherwise tries
+// Combined and then Plain.
+var Auto Parser = autoParser{}
+
+type autoParser struct{}
+
+func (autoParser) Parse(line string) (Entry, bool) {
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		return (&JSON{}).Parse(line)
+	}
+	if e, ok := Combined.Parse(line); ok {
+		return e, true
+	}
+	return Plain.Parse(line)
+}
@@ -0,0 +1,98 @@
+package loganalyze
+
+import "reflect"
+import "testing"
+import "time"
+
+func TestAuto(t *testing.T) {
+	cases := []struct {
+		line string
+		want Entry
+	}{
+		{
+			`127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326`,
+			Entry{
+				Time:    time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC),
+				Status:  200,
+				Message: "GET /apache_pb.gif HTTP/1.0",
+				Fields:  map[string]string{"host": "127.0.0.1", "user": "frank", "size": "2326", "referer": "", "agent": ""},
+			},
+		},
+		{
+			`{"time":"2024-05-01T12:00:00Z","level":"WARN","msg":"slow query","ms":1500,"ok":true}`,
+			Entry{
+				Time:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+				Level:   "WARN",
+				Message: "slow query",
+				Fields:  map[string]string{"ms": "1500", "ok": "true"},
+			},
+		},
+		{
+			`2024-05-01 12:00:01 [error] connection refused`,
+			Entry{
+				Time:    time.Date(2024, 5, 1, 12, 0, 1, 0, time.UTC),
+				Level:   "ERROR",
+				Message: "connection refused",
+			},
+		},
+		{
+			`2024/05/01 12:00:02 WARNING: disk 91% full`,
+			Entry{
+				Time:    time.Date(2024, 5, 1, 12, 0, 2, 0, time.UTC),
+				Level:   "WARN",
+				Message: "disk 91% full",
+			},
+		},
+	}
+	for _, c := range cases {
+		got, ok := Auto.Parse(c.line)
+		if !ok {
+			t.Errorf("Parse(%q) failed", c.line)
+			continue
+		}
+		if !got.Time.Equal(c.want.Time) {
+			t.Errorf("Parse(%q).Time == %v, want %v", c.line, got.Time, c.want.Time)
+		}
+		got.Time = c.want.Time
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Parse(%q) ==\n%#v\nwant\n%#v", c.line, got, c.want)
+		}
+	}
+	for _, line := range []string{"hello", "{not json", "2024-05-01 nope"} {
+		if _, ok := Auto.Parse(line); ok {
+			t.Errorf("Parse(%q) succeeded", line)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	p := &JSON{TimeKey: "ts", LevelKey: "severity", MessageKey: "message", StatusKey: "code"}
+	e, ok := p.Parse(`{"ts":1714564800.5,"severity":"crit","message":"down","code":503}`)
+	if !ok {
+		t.Fatal("Parse failed")
+	}
+	want := time.Date(2024, 5, 1, 12, 0, 0, 5e8, time.UTC)
+	if !e.Time.Equal(want) || e.Level != "CRITICAL" || e.Message != "down" || e.Status != 503 || e.Fields != nil {
+		t.Errorf("Parse() == %+v", e)
+	}
+	if !e.IsError() {
+		t.Error("IsError() == false")
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	p, err := NewRegexp(`^(?P<time>\S+) (?P<status>\d+) (?P<msg>.*)$`, time.Kitchen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := p.Parse("3:04PM 404 not found")
+	if !ok || e.Status != 404 || e.Message != "not found" || e.Time.Hour() != 15 || e.IsError() {
+		t.Errorf("Parse() == %+v, %v", e, ok)
+	}
+	if _, ok := p.Parse("noon 404 not found"); ok {
+		t.Error("Parse accepted a bad time")
+	}
+	if _, err := NewRegexp("(", ""); err == nil {
+		t.Error("NewRegexp accepted a bad pattern")
+	}
+}
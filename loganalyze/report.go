@@ -0,0 +1,75 @@
+package loganalyze
+
+import "fmt"
+import "io"
+import "strconv"
+import "strings"
+import "time"
+
+import "github.com/lukehedger/golib"
+import "github.com/lukehedger/golib/stats"
+import "github.com/lukehedger/golib/termplot"
+
+// WriteTo renders r as a plain-text report for a terminal.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "lines: %d, entries: %d", r.Lines, r.Entries)
+	if r.Unparsed > 0 {
+		fmt.Fprintf(&b, ", unparsed: %d", r.Unparsed)
+	}
+	fmt.Fprintf(&b, ", errors: %d (%.1f%%)\n", r.Errors, golib.Percent(r.Errors, r.Entries))
+	if !r.First.IsZero() {
+		fmt.Fprintf(&b, "from %s to %s (%s)\n", r.First.Format(time.DateTime), r.Last.Format(time.DateTime), r.Last.Sub(r.First))
+	}
+
+	section := func(title string, headers []string, rows [][]any) {
+		if len(rows) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s\n", title)
+		t := new(golib.Table).SetHeaders(headers...).SetAlign(1, golib.AlignRight).SetAlign(2, golib.AlignRight)
+		for _, row := range rows {
+			t.AddRow(row...)
+		}
+		t.Render(&b)
+	}
+	percent := func(n int) string {
+		return strconv.FormatFloat(golib.Percent(n, r.Entries), 'f', 1, 64) + "%"
+	}
+
+	var rows [][]any
+	for _, c := range r.Levels {
+		rows = append(rows, []any{c.Value, c.N, percent(c.N)})
+	}
+	section("Levels", []string{"LEVEL", "COUNT", "SHARE"}, rows)
+
+	rows = nil
+	for _, c := range r.Statuses {
+		rows = append(rows, []any{c.Value, c.N, percent(c.N)})
+	}
+	section("Statuses", []string{"STATUS", "COUNT", "SHARE"}, rows)
+
+	rows = nil
+	for _, c := range r.Top {
+		rows = append(rows, []any{golib.Truncate(c.Value, 70, "…"), c.N, percent(c.N)})
+	}
+	section("Top messages", []string{"MESSAGE", "COUNT", "SHARE"}, rows)
+
+	if len(r.Windows) > 0 {
+		rates := make([]float64, len(r.Windows))
+		var o stats.Online
+		worst := r.Windows[0]
+		for i, win := range r.Windows {
+			rates[i] = win.ErrorRate()
+			o.Add(rates[i])
+			if rates[i] > worst.ErrorRate() {
+				worst = win
+			}
+		}
+		fmt.Fprintf(&b, "\nError rate per %s\n%s\n", r.Window, termplot.Sparkline(rates, 60))
+		fmt.Fprintf(&b, "mean %.1f%%, max %.1f%% at %s\n", 100*o.Mean(), 100*o.Max(), worst.Start.Format(time.DateTime))
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
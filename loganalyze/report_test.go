@@ -0,0 +1,32 @@
+package loganalyze
+
+import "strings"
+import "testing"
+import "time"
+
+func TestReportWriteTo(t *testing.T) {
+	r, err := Analyze(strings.NewReader(sample), Auto, Options{Window: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if _, err := r.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, want := range []string{
+		"lines: 7, entries: 6, unparsed: 1, errors: 2 (33.3%)\n",
+		"from 2024-05-01 12:00:05 to 2024-05-01 12:02:59 (2m54s)\n",
+		"LEVEL  COUNT  SHARE\nINFO       3  50.0%\n",
+		"request served in *ms      3  50.0%\n",
+		"Error rate per 1m0s\n",
+		"mean 22.2%, max 33.3% at 2024-05-01 12:00:00\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Statuses") {
+		t.Errorf("report has an empty Statuses section:\n%s", out)
+	}
+}
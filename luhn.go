@@ -0,0 +1,59 @@
+package golib
+
+import "fmt"
+import "strings"
+
+// LuhnValid reports whether s, a number such as a payment card or IMEI,
+// passes the Luhn check: its last digit is the check digit of the rest.
+// Spaces and hyphens between digits are ignored; any other non-digit, or
+// fewer than two digits, makes s invalid.
+func LuhnValid(s string) bool {
+	s = strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(s) < 2 || !isDigits(s) {
+		return false
+	}
+	return luhnCheckDigit(s[:len(s)-1]) == s[len(s)-1]
+}
+
+// LuhnGenerate returns a random number of length digits that starts with
+// prefix and passes LuhnValid, such as a test card number: "4" and 16
+// give a Visa-like number. It panics if prefix is not all digits or
+// leaves no room for the check digit.
+func LuhnGenerate(prefix string, length int) string { return LuhnGenerateWith(secure, prefix, length) }
+
+// LuhnGenerateWith is LuhnGenerate drawing from f, for repeatable
+// fixtures.
+func LuhnGenerateWith(f *Fast, prefix string, length int) string {
+	if !isDigits(prefix) || length <= len(prefix) {
+		panic(fmt.Sprintf("golib: cannot make a %d-digit Luhn number starting %q", length, prefix))
+	}
+	body := prefix + f.String(length-len(prefix)-1, Digits)
+	return body + string(luhnCheckDigit(body))
+}
+
+// luhnCheckDigit returns the digit to append to s to make its Luhn sum a
+// multiple of ten. Counting from the right of s, every other digit
+// starting with the last is doubled.
+func luhnCheckDigit(s string) byte {
+	sum, double := 0, true
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigits(s string) bool {
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package golib
+
+import "strings"
+import "testing"
+
+func TestLuhnValid(t *testing.T) {
+	valid := []string{"4111111111111111", "4111 1111 1111 1111", "5500-0000-0000-0004", "79927398713", "00", "490154203237518"}
+	for _, s := range valid {
+		if !LuhnValid(s) {
+			t.Errorf("LuhnValid(%q) = false", s)
+		}
+	}
+	invalid := []string{"4111111111111112", "79927398710", "", "0", "4111a11111111111", "41111111 1111111x"}
+	for _, s := range invalid {
+		if LuhnValid(s) {
+			t.Errorf("LuhnValid(%q) = true", s)
+		}
+	}
+}
+
+func TestLuhnGenerate(t *testing.T) {
+	for range 100 {
+		s := LuhnGenerate("4", 16)
+		if len(s) != 16 || !strings.HasPrefix(s, "4") || !LuhnValid(s) {
+			t.Fatalf("LuhnGenerate = %s", s)
+		}
+	}
+	if s := LuhnGenerate("7992739871", 11); s != "79927398713" {
+		t.Errorf("only the check digit to add: %s", s)
+	}
+	a, b := LuhnGenerateWith(NewFast(1), "", 12), LuhnGenerateWith(NewFast(1), "", 12)
+	if a != b {
+		t.Errorf("same seed gave %s and %s", a, b)
+	}
+	for _, c := range []struct {
+		prefix string
+		length int
+	}{{"4", 1}, {"4x", 16}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("LuhnGenerate(%q, %d) did not panic", c.prefix, c.length)
+				}
+			}()
+			LuhnGenerate(c.prefix, c.length)
+		}()
+	}
+}
@@ -0,0 +1,290 @@
+// Package mail composes MIME email messages and sends them over SMTP.
+//
+// A Message holds a plain-text body, an HTML body, or both (sent as
+// multipart/alternative), plus any attachments (wrapped in
+// multipart/mixed). Send delivers through a Transport: SMTP for real
+// servers, Mock for tests.
+package mail
+
+import "bytes"
+import "encoding/base64"
+import "errors"
+import "fmt"
+import "io"
+import "mime"
+import "mime/multipart"
+import "mime/quotedprintable"
+import "net/textproto"
+import "path/filepath"
+import "sort"
+import "strings"
+import "time"
+
+import netmail "net/mail"
+
+// Message is an email message. Addresses may be bare ("ann@example.com")
+// or include a display name ("Ann <ann@example.com>"). Bcc recipients
+// receive the message but are not listed in its headers. WriteTo rejects
+// addresses it cannot parse, header values containing line breaks, and
+// Headers that would replace or repeat a header set from the other
+// fields, such as From, Subject or Content-Type.
+type Message struct {
+	From        string
+	To, Cc, Bcc []string
+	ReplyTo     string
+	Subject     string
+	Date        time.Time // defaults to the time of writing
+	Text        string
+	HTML        string
+	Headers     map[string]string // extra headers, e.g. "X-Campaign"
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a Message.
+type Attachment struct {
+	Filename    string
+	ContentType string // guessed from Filename if empty
+	Data        []byte
+	Inline      bool // shown in the body (e.g. an image referenced by cid:) rather than as a download
+}
+
+// Attach adds an attachment and returns m.
+func (m *Message) Attach(filename string, data []byte) *Message {
+	m.Attachments = append(m.Attachments, Attachment{Filename: filename, Data: data})
+	return m
+}
+
+// Recipients returns the bare addresses of every To, Cc and Bcc recipient,
+// as used for the SMTP envelope.
+func (m *Message) Recipients() ([]string, error) {
+	var rcpts []string
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		for _, s := range list {
+			a, err := netmail.ParseAddress(s)
+			if err != nil {
+				return nil, fmt.Errorf("mail: recipient %q: %w", s, err)
+			}
+			rcpts = append(rcpts, a.Address)
+		}
+	}
+	if len(rcpts) == 0 {
+		return nil, errors.New("mail: no recipients")
+	}
+	return rcpts, nil
+}
+
+// Bytes returns the message in RFC 5322 wire format.
+func (m *Message) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := m.WriteTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// WriteTo writes the message in RFC 5322 wire format to w.
+func (m *Message) WriteTo(w io.Writer) (int64, error) {
+	if m.From == "" {
+		return 0, errors.New("mail: message has no From address")
+	}
+	var b bytes.Buffer
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	h := textproto.MIMEHeader{}
+	for _, f := range []struct {
+		key  string
+		list []string
+	}{
+		{"From", []string{m.From}},
+		{"To", m.To},
+		{"Cc", m.Cc},
+		{"Reply-To", []string{m.ReplyTo}},
+	} {
+		v, err := addressList(f.key, f.list)
+		if err != nil {
+			return 0, err
+		}
+		if v != "" {
+			h.Set(f.key, v)
+		}
+	}
+	if err := checkHeader("Subject", m.Subject); err != nil {
+		return 0, err
+	}
+	h.Set("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	h.Set("Date", date.Format(time.RFC1123Z))
+	h.Set("MIME-Version", "1.0")
+	for k, v := range m.Headers {
+		if err := checkHeader(k, v); err != nil {
+			return 0, err
+		}
+		ck := textproto.CanonicalMIMEHeaderKey(k)
+		if reserved[ck] || strings.HasPrefix(ck, "Content-") {
+			return 0, fmt.Errorf("mail: header %s is set by Message and cannot be in Headers", ck)
+		}
+		if _, dup := h[ck]; dup {
+			return 0, fmt.Errorf("mail: header %s appears twice in Headers", ck)
+		}
+		h.Set(ck, mime.QEncoding.Encode("utf-8", v))
+	}
+
+	body, err := m.body()
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range body.header {
+		h[k] = v
+	}
+	writeHeader(&b, h)
+	b.Write(body.content)
+
+	n, err := w.Write(b.Bytes())
+	return int64(n), err
+}
+
+// A part is a MIME entity: its own headers and encoded content.
+type part struct {
+	header  textproto.MIMEHeader
+	content []byte
+}
+
+func (m *Message) body() (part, error) {
+	var alts []part
+	if m.Text != "" || m.HTML == "" {
+		alts = append(alts, textPart("text/plain", m.Text))
+	}
+	if m.HTML != "" {
+		alts = append(alts, textPart("text/html", m.HTML))
+	}
+	body := alts[0]
+	if len(alts) > 1 {
+		body = multipartOf("alternative", alts)
+	}
+	if len(m.Attachments) == 0 {
+		return body, nil
+	}
+
+	parts := []part{body}
+	for _, a := range m.Attachments {
+		if a.Filename == "" {
+			return part{}, errors.New("mail: attachment has no filename")
+		}
+		if strings.ContainsAny(a.Filename, "\r\n") {
+			return part{}, fmt.Errorf("mail: attachment filename %q contains a line break", a.Filename)
+		}
+		parts = append(parts, attachmentPart(a))
+	}
+	return multipartOf("mixed", parts), nil
+}
+
+func textPart(contentType, s string) part {
+	var b bytes.Buffer
+	qp := quotedprintable.NewWriter(&b)
+	qp.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(s, "\r\n", "\n"), "\n", "\r\n")))
+	qp.Close()
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", contentType+"; charset=utf-8")
+	h.Set("Content-Transfer-Encoding", "quoted-printable")
+	return part{h, b.Bytes()}
+}
+
+func attachmentPart(a Attachment) part {
+	ct := a.ContentType
+	if ct == "" {
+		ct = mime.TypeByExtension(filepath.Ext(a.Filename))
+	}
+	ct, params, err := mime.ParseMediaType(ct)
+	if err != nil {
+		ct, params = "application/octet-stream", map[string]string{}
+	}
+	params["name"] = a.Filename
+	disposition := "attachment"
+	if a.Inline {
+		disposition = "inline"
+	}
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", mime.FormatMediaType(ct, params))
+	h.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	h.Set("Content-Transfer-Encoding", "base64")
+	if a.Inline {
+		h.Set("Content-ID", "<"+a.Filename+">")
+	}
+
+	// Base64 with lines of 76 characters, as RFC 2045 requires.
+	enc := base64.StdEncoding.EncodeToString(a.Data)
+	var b bytes.Buffer
+	for len(enc) > 76 {
+		b.WriteString(enc[:76] + "\r\n")
+		enc = enc[76:]
+	}
+	b.WriteString(enc + "\r\n")
+	return part{h, b.Bytes()}
+}
+
+func multipartOf(subtype string, parts []part) part {
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	for _, p := range parts {
+		w, _ := mw.CreatePart(p.header) // writes to a bytes.Buffer cannot fail
+		w.Write(p.content)
+	}
+	mw.Close()
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Type", "multipart/"+subtype+"; boundary="+mw.Boundary())
+	return part{h, b.Bytes()}
+}
+
+// addressList parses the addresses for header key and formats them as
+// RFC 5322 requires, encoding non-ASCII display names as RFC 2047 words.
+// Empty entries are skipped.
+func addressList(key string, list []string) (string, error) {
+	var out []string
+	for _, s := range list {
+		if s == "" {
+			continue
+		}
+		a, err := netmail.ParseAddress(s)
+		if err != nil {
+			return "", fmt.Errorf("mail: %s address %q: %w", key, s, err)
+		}
+		out = append(out, a.String())
+	}
+	return strings.Join(out, ", "), nil
+}
+
+// checkHeader rejects a header whose name is not a valid field name or
+// whose value contains a line break, either of which would let a caller
+// inject headers of their own.
+// reserved are the headers that WriteTo sets from Message's fields, or
+// that would reveal Bcc recipients, which Headers may not contain. Any
+// Content- header is reserved too, as the body sets them.
+var reserved = map[string]bool{
+	"From": true, "To": true, "Cc": true, "Bcc": true, "Reply-To": true,
+	"Subject": true, "Date": true, "Mime-Version": true,
+}
+
+func checkHeader(key, value string) error {
+	if key == "" || strings.ContainsFunc(key, func(r rune) bool { return r <= ' ' || r > '~' || r == ':' }) {
+		return fmt.Errorf("mail: invalid header name %q", key)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("mail: header %s contains a line break", key)
+	}
+	return nil
+}
+
+func writeHeader(b *bytes.Buffer, h textproto.MIMEHeader) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			fmt.Fprintf(b, "%s: %s\r\n", k, v)
+		}
+	}
+	b.WriteString("\r\n")
+}
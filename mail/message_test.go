@@ -0,0 +1,188 @@
+package mail
+
+import "bytes"
+import "io"
+import "mime"
+import "mime/multipart"
+import "mime/quotedprintable"
+import "strings"
+import "testing"
+import "time"
+
+import netmail "net/mail"
+
+func TestMessageWriteTo(t *testing.T) {
+	m := &Message{
+		From:    "Ann <ann@example.com>",
+		To:      []string{"bob@example.com"},
+		Bcc:     []string{"eve@example.com"},
+		Subject: "Héllo",
+		Date:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Text:    "Hi Bob,\nsee attached.",
+		HTML:    "<p>Hi Bob,</p>",
+		Headers: map[string]string{"X-Campaign": "q1"},
+	}
+	m.Attach("notes.txt", []byte("some notes"))
+
+	raw, err := m.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	for k, want := range map[string]string{
+		"From":       `"Ann" <ann@example.com>`,
+		"To":         "<bob@example.com>",
+		"Bcc":        "",
+		"Date":       "Tue, 02 Jan 2024 03:04:05 +0000",
+		"X-Campaign": "q1",
+	} {
+		if got := msg.Header.Get(k); got != want {
+			t.Errorf("header %s == %q, want %q", k, got, want)
+		}
+	}
+	if subject != "Héllo" {
+		t.Errorf("Subject == %q", subject)
+	}
+
+	// multipart/mixed { multipart/alternative { text, html }, attachment }
+	mt, params, _ := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if mt != "multipart/mixed" {
+		t.Fatalf("Content-Type == %q", mt)
+	}
+	mixed := multipart.NewReader(msg.Body, params["boundary"])
+	alt, err := mixed.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mt, params, _ = mime.ParseMediaType(alt.Header.Get("Content-Type"))
+	if mt != "multipart/alternative" {
+		t.Fatalf("first part Content-Type == %q", mt)
+	}
+	alts := multipart.NewReader(alt, params["boundary"])
+	for _, want := range []struct{ ct, body string }{
+		{"text/plain", "Hi Bob,\r\nsee attached."},
+		{"text/html", "<p>Hi Bob,</p>"},
+	} {
+		p, err := alts.NextRawPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ct, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type")); ct != want.ct {
+			t.Errorf("alternative Content-Type == %q, want %q", ct, want.ct)
+		}
+		body, _ := io.ReadAll(quotedprintable.NewReader(p))
+		if string(body) != want.body {
+			t.Errorf("%s body == %q, want %q", want.ct, body, want.body)
+		}
+	}
+
+	att, err := mixed.NextPart()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if att.FileName() != "notes.txt" {
+		t.Errorf("attachment filename == %q", att.FileName())
+	}
+	if !strings.HasPrefix(att.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("attachment Content-Type == %q", att.Header.Get("Content-Type"))
+	}
+	body, _ := io.ReadAll(att)
+	if string(body) != "c29tZSBub3Rlcw==\r\n" {
+		t.Errorf("attachment body == %q", body)
+	}
+}
+
+func TestMessageSinglePart(t *testing.T) {
+	m := &Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "plain"}
+	raw, err := m.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ct := msg.Header.Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("Content-Type == %q", ct)
+	}
+	if _, err := (&Message{To: []string{"b@example.com"}}).Bytes(); err == nil {
+		t.Error("message without From rendered")
+	}
+}
+
+func TestMessageAddresses(t *testing.T) {
+	m := &Message{
+		From:    "Zoë Ångström <zoe@example.com>",
+		To:      []string{"bob@example.com", "Cat <cat@example.com>"},
+		ReplyTo: "help@example.com",
+	}
+	raw, err := m.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from := msg.Header.Get("From"); !strings.HasPrefix(from, "=?utf-8?") {
+		t.Errorf("From == %q, want an encoded display name", from)
+	}
+	from, err := msg.Header.AddressList("From")
+	if err != nil || len(from) != 1 || from[0].Name != "Zoë Ångström" {
+		t.Errorf("From decodes to %v, %v", from, err)
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 2 || to[1].Address != "cat@example.com" {
+		t.Errorf("To decodes to %v, %v", to, err)
+	}
+	if got := msg.Header.Get("Reply-To"); got != "<help@example.com>" {
+		t.Errorf("Reply-To == %q", got)
+	}
+}
+
+func TestMessageHeaderInjection(t *testing.T) {
+	inject := "ann@example.com\r\nBcc: eve@example.com"
+	tests := []struct {
+		name string
+		m    Message
+	}{
+		{"From", Message{From: inject}},
+		{"To", Message{From: "a@example.com", To: []string{inject}}},
+		{"Cc", Message{From: "a@example.com", Cc: []string{"not an address"}}},
+		{"Reply-To", Message{From: "a@example.com", ReplyTo: inject}},
+		{"Subject", Message{From: "a@example.com", Subject: "hi\r\nBcc: eve@example.com"}},
+		{"header value", Message{From: "a@example.com", Headers: map[string]string{"X-Tag": "a\nBcc: eve@example.com"}}},
+		{"header name", Message{From: "a@example.com", Headers: map[string]string{"X-Tag\r\nBcc": "eve@example.com"}}},
+		{"header colon", Message{From: "a@example.com", Headers: map[string]string{"Bcc: eve@example.com\r\nX": "y"}}},
+		{"reserved From", Message{From: "a@example.com", Headers: map[string]string{"from": "eve@example.com"}}},
+		{"reserved Bcc", Message{From: "a@example.com", Headers: map[string]string{"Bcc": "eve@example.com"}}},
+		{"reserved MIME-Version", Message{From: "a@example.com", Headers: map[string]string{"MIME-Version": "2.0"}}},
+		{"reserved Content-Type", Message{From: "a@example.com", Headers: map[string]string{"content-type": "text/html"}}},
+		{"duplicate", Message{From: "a@example.com", Headers: map[string]string{"X-Tag": "a", "x-tag": "b"}}},
+		{"filename", Message{From: "a@example.com", Attachments: []Attachment{{Filename: "a\r\nBcc: x", Data: []byte("x")}}}},
+	}
+	for _, tt := range tests {
+		raw, err := tt.m.Bytes()
+		if err == nil {
+			t.Errorf("%s: rendered %q", tt.name, raw)
+		}
+	}
+}
+
+func TestRecipients(t *testing.T) {
+	m := &Message{To: []string{"Bob <bob@example.com>"}, Cc: []string{"c@example.com"}, Bcc: []string{"d@example.com"}}
+	got, err := m.Recipients()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(got, ",") != "bob@example.com,c@example.com,d@example.com" {
+		t.Errorf("Recipients() == %q", got)
+	}
+	if _, err := (&Message{}).Recipients(); err == nil {
+		t.Error("Recipients() of empty message succeeded")
+	}
+}
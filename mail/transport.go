@@ -0,0 +1,156 @@
+package mail
+
+import "context"
+import "crypto/tls"
+import "errors"
+import "fmt"
+import "net"
+import "net/smtp"
+import "sync"
+
+import netmail "net/mail"
+
+// Transport delivers messages.
+type Transport interface {
+	Send(ctx context.Context, m *Message) error
+}
+
+// SMTP is a Transport that delivers to an SMTP server, upgrading the
+// connection with STARTTLS when the server offers it.
+type SMTP struct {
+	Addr       string      // host:port, e.g. "smtp.example.com:587"
+	Auth       smtp.Auth   // optional; smtp.PlainAuth for most servers
+	TLSConfig  *tls.Config // for STARTTLS; ServerName defaults to Addr's host
+	RequireTLS bool        // fail rather than send in the clear if STARTTLS is unavailable
+	LocalName  string      // name sent in EHLO; defaults to "localhost"
+}
+
+// ErrNoTLS is returned by SMTP.Send when RequireTLS is set and the server
+// does not offer STARTTLS.
+var ErrNoTLS = errors.New("mail: server does not support STARTTLS")
+
+// Send delivers m. The context bounds dialling and the whole SMTP
+// conversation.
+func (s *SMTP) Send(ctx context.Context, m *Message) error {
+	from, err := netmail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("mail: sender %q: %w", m.From, err)
+	}
+	rcpts, err := m.Recipients()
+	if err != nil {
+		return err
+	}
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(s.Addr)
+	if err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.Addr)
+	if err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	defer c.Close()
+	name := s.LocalName
+	if name == "" {
+		name = "localhost"
+	}
+	if err := c.Hello(name); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		cfg := s.TLSConfig.Clone()
+		if cfg == nil {
+			cfg = &tls.Config{}
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = host
+		}
+		if err := c.StartTLS(cfg); err != nil {
+			return fmt.Errorf("mail: %w", err)
+		}
+	} else if s.RequireTLS {
+		return ErrNoTLS
+	}
+	if s.Auth != nil {
+		if err := c.Auth(s.Auth); err != nil {
+			return fmt.Errorf("mail: %w", err)
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	for _, r := range rcpts {
+		if err := c.Rcpt(r); err != nil {
+			return fmt.Errorf("mail: recipient %s: %w", r, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mail: %w", err)
+	}
+	return c.Quit()
+}
+
+// Mock is a Transport that records messages instead of sending them. The
+// zero value is ready to use.
+type Mock struct {
+	// Err, if set, is returned by Send and the message is not recorded.
+	Err error
+
+	mu   sync.Mutex
+	sent []*Message
+}
+
+// Send records m after checking that it renders.
+func (t *Mock) Send(ctx context.Context, m *Message) error {
+	if t.Err != nil {
+		return t.Err
+	}
+	if _, err := m.Recipients(); err != nil {
+		return err
+	}
+	if _, err := m.Bytes(); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sent = append(t.sent, m)
+	return nil
+}
+
+// Sent returns the messages sent so far, oldest first.
+func (t *Mock) Sent() []*Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*Message(nil), t.sent...)
+}
+
+// Reset forgets all sent messages.
+func (t *Mock) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sent = nil
+}
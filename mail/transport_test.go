@@ -0,0 +1,124 @@
+package mail
+
+import "context"
+import "errors"
+import "net"
+import "net/textproto"
+import "strings"
+import "testing"
+import "time"
+
+// fakeSMTP accepts one session on a loopback listener and records the
+// envelope and data. It does not offer STARTTLS or AUTH.
+type fakeSMTP struct {
+	addr  string
+	from  string
+	rcpts []string
+	data  string
+	done  chan struct{}
+}
+
+func startFakeSMTP(t *testing.T) *fakeSMTP {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	s := &fakeSMTP{addr: ln.Addr().String(), done: make(chan struct{})}
+	go func() {
+		defer close(s.done)
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		tp.PrintfLine("220 fake ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			verb, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(verb) {
+			case "EHLO":
+				tp.PrintfLine("250 fake")
+			case "MAIL":
+				s.from = arg
+				tp.PrintfLine("250 ok")
+			case "RCPT":
+				s.rcpts = append(s.rcpts, arg)
+				tp.PrintfLine("250 ok")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				b, _ := tp.ReadDotBytes()
+				s.data = string(b)
+				tp.PrintfLine("250 queued")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 unsupported")
+			}
+		}
+	}()
+	return s
+}
+
+func TestSMTPSend(t *testing.T) {
+	s := startFakeSMTP(t)
+	m := &Message{
+		From:    "Ann <ann@example.com>",
+		To:      []string{"bob@example.com"},
+		Bcc:     []string{"eve@example.com"},
+		Subject: "hi",
+		Text:    "hello",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := (&SMTP{Addr: s.addr}).Send(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	<-s.done
+	if s.from != "FROM:<ann@example.com>" {
+		t.Errorf("MAIL %s", s.from)
+	}
+	if strings.Join(s.rcpts, " ") != "TO:<bob@example.com> TO:<eve@example.com>" {
+		t.Errorf("RCPT %q", s.rcpts)
+	}
+	if !strings.Contains(s.data, "Subject: hi\n") || strings.Contains(s.data, "eve@") {
+		t.Errorf("DATA ==\n%s", s.data)
+	}
+}
+
+func TestSMTPRequireTLS(t *testing.T) {
+	s := startFakeSMTP(t)
+	m := &Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "x"}
+	err := (&SMTP{Addr: s.addr, RequireTLS: true}).Send(context.Background(), m)
+	if !errors.Is(err, ErrNoTLS) {
+		t.Errorf("Send() == %v, want ErrNoTLS", err)
+	}
+}
+
+func TestMock(t *testing.T) {
+	var mock Mock
+	var tr Transport = &mock
+	m := &Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "x"}
+	if err := tr.Send(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Send(context.Background(), &Message{From: "a@example.com"}); err == nil {
+		t.Error("Send() without recipients succeeded")
+	}
+	if sent := mock.Sent(); len(sent) != 1 || sent[0] != m {
+		t.Errorf("Sent() == %v", sent)
+	}
+	mock.Err = errors.New("down")
+	if err := tr.Send(context.Background(), m); err != mock.Err {
+		t.Errorf("Send() == %v, want %v", err, mock.Err)
+	}
+	mock.Reset()
+	if len(mock.Sent()) != 0 {
+		t.Error("Reset() kept messages")
+	}
+}
@@ -0,0 +1,182 @@
+// Package manifest records the SHA-256 checksum of every file in a tree,
+// in the format of sha256sum(1), and verifies trees against such
+// manifests.
+//
+// A manifest written by this package can be checked with
+// "sha256sum -c SHA256SUMS" from the tree's root, and vice versa.
+package manifest
+
+import "bufio"
+import "crypto/sha256"
+import "encoding/hex"
+import "fmt"
+import "io"
+import "io/fs"
+import "runtime"
+import "slices"
+import "strings"
+import "sync"
+
+// Manifest maps slash-separated file paths, relative to the tree root, to
+// their hex-encoded SHA-256 checksums.
+type Manifest map[string]string
+
+// Build hashes every regular file in fsys using workers goroutines, or
+// runtime.NumCPU() if workers is zero or less. Symbolic links and other
+// special files are skipped, as is any file for which skip returns true;
+// skip may be nil.
+func Build(fsys fs.FS, workers int, skip func(path string) bool) (Manifest, error) {
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() && (skip == nil || !skip(path)) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashAll(fsys, paths, workers)
+}
+
+// hashAll hashes paths concurrently. It returns the first error, after
+// all workers have stopped.
+func hashAll(fsys fs.FS, paths []string, workers int) (Manifest, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	jobs := make(chan string)
+	var (
+		mu       sync.Mutex
+		m        = make(Manifest, len(paths))
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	for range min(workers, max(len(paths), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				sum, err := HashFile(fsys, p)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				m[p] = sum
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, p := range paths {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return m, nil
+}
+
+// HashFile returns the hex-encoded SHA-256 checksum of the named file.
+func HashFile(fsys fs.FS, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("manifest: %s: %w", name, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Paths returns the manifest's paths in sorted order.
+func (m Manifest) Paths() []string {
+	paths := make([]string, 0, len(m))
+	for p := range m {
+		paths = append(paths, p)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// WriteTo writes m in sha256sum format, one "checksum  path" line per
+// file, sorted by path.
+func (m Manifest) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var n int64
+	for _, p := range m.Paths() {
+		k, _ := fmt.Fprintf(bw, "%s  %s\n", m[p], p)
+		n += int64(k)
+	}
+	return n, bw.Flush()
+}
+
+// Parse reads a manifest in sha256sum format. Lines in binary mode
+// ("checksum *path") are accepted too, and blank lines are skipped.
+func Parse(r io.Reader) (Manifest, error) {
+	m := Manifest{}
+	s := bufio.NewScanner(r)
+	for line := 1; s.Scan(); line++ {
+		l := strings.TrimRight(s.Text(), "\r")
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		sum, path, ok := strings.Cut(l, " ")
+		if !ok || len(sum) != 64 || len(path) < 2 || (path[0] != ' ' && path[0] != '*') {
+			return nil, fmt.Errorf("manifest: line %d: malformed", line)
+		}
+		if _, err := hex.DecodeString(sum); err != nil {
+			return nil, fmt.Errorf("manifest: line %d: bad checksum", line)
+		}
+		m[strings.TrimPrefix(path[1:], "./")] = strings.ToLower(sum)
+	}
+	return m, s.Err()
+}
+
+// Diff is the difference between a manifest and a tree.
+type Diff struct {
+	Added    []string // in the tree but not the manifest
+	Removed  []string // in the manifest but not the tree
+	Modified []string // in both, with different checksums
+}
+
+// Clean reports whether the tree matches the manifest exactly.
+func (d *Diff) Clean() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// Verify hashes fsys with workers goroutines and compares it with m. Files
+// for which skip returns true, such as the manifest itself, are ignored.
+func Verify(fsys fs.FS, m Manifest, workers int, skip func(path string) bool) (*Diff, error) {
+	current, err := Build(fsys, workers, skip)
+	if err != nil {
+		return nil, err
+	}
+	return Compare(m, current), nil
+}
+
+// Compare returns the changes from old to cur.
+func Compare(old, cur Manifest) *Diff {
+	d := &Diff{}
+	for _, p := range cur.Paths() {
+		sum, ok := old[p]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, p)
+		case sum != cur[p]:
+			d.Modified = append(d.Modified, p)
+		}
+	}
+	for _, p := range old.Paths() {
+		if _, ok := cur[p]; !ok {
+			d.Removed = append(d.Removed, p)
+		}
+	}
+	return d
+}
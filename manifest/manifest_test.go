@@ -0,0 +1,80 @@
+package manifest
+
+import "reflect"
+import "strings"
+import "testing"
+import "testing/fstest"
+
+// Checksums of "hello\n" and "world\n", as printed by sha256sum.
+const (
+	helloSum = "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"
+	worldSum = "e258d248fda94c63753607f7c4494ee0fcbe92f1a76bfdac795c9d84101eb317"
+)
+
+func TestBuildAndWrite(t *testing.T) {
+	fsys := fstest.MapFS{
+		"b/world.txt": {Data: []byte("world\n")},
+		"hello.txt":   {Data: []byte("hello\n")},
+		"SHA256SUMS":  {Data: []byte("stale")},
+	}
+	m, err := Build(fsys, 2, func(p string) bool { return p == "SHA256SUMS" })
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := worldSum + "  b/world.txt\n" + helloSum + "  hello.txt\n"
+	if b.String() != want {
+		t.Errorf("WriteTo() ==\n%s\nwant\n%s", b.String(), want)
+	}
+
+	parsed, err := Parse(strings.NewReader(b.String()))
+	if err != nil || !reflect.DeepEqual(parsed, m) {
+		t.Errorf("Parse(WriteTo()) == %v, %v", parsed, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	in := strings.ToUpper(helloSum) + " *./hello.txt\r\n\n" + worldSum + "  dir/with space.txt\n"
+	m, err := Parse(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Manifest{"hello.txt": helloSum, "dir/with space.txt": worldSum}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("Parse() == %v, want %v", m, want)
+	}
+	for _, bad := range []string{"nothex  x\n", helloSum + "\n", helloSum + "x file\n", strings.Repeat("g", 64) + "  x\n"} {
+		if _, err := Parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("Parse(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	m := Manifest{"hello.txt": helloSum, "gone.txt": helloSum, "changed.txt": helloSum}
+	fsys := fstest.MapFS{
+		"hello.txt":   {Data: []byte("hello\n")},
+		"changed.txt": {Data: []byte("world\n")},
+		"new.txt":     {Data: []byte("new\n")},
+	}
+	d, err := Verify(fsys, m, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Diff{Added: []string{"new.txt"}, Removed: []string{"gone.txt"}, Modified: []string{"changed.txt"}}
+	if !reflect.DeepEqual(d, want) {
+		t.Errorf("Verify() == %+v, want %+v", d, want)
+	}
+	if d.Clean() {
+		t.Error("Clean() == true")
+	}
+	delete(fsys, "new.txt")
+	fsys["changed.txt"] = &fstest.MapFile{Data: []byte("hello\n")}
+	fsys["gone.txt"] = &fstest.MapFile{Data: []byte("hello\n")}
+	if d, _ := Verify(fsys, m, 1, nil); !d.Clean() {
+		t.Errorf("Verify() after restoring == %+v", d)
+	}
+}
@@ -0,0 +1,198 @@
+package golib
+
+import "cmp"
+import "errors"
+
+// Signed is any signed integer type.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is any unsigned integer type.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Integer is any integer type.
+type Integer interface {
+	Signed | Unsigned
+}
+
+// Number is any integer or floating-point type.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Min returns the smaller of a and b.
+func Min[T cmp.Ordered](a, b T) T {
+	if b < a {
+		return b
+	}
+	return a
+}
+
+// Max returns the larger of a and b.
+func Max[T cmp.Ordered](a, b T) T {
+	if b > a {
+		return b
+	}
+	return a
+}
+
+// Clamp limits v to the range [lo, hi].
+func Clamp[T cmp.Ordered](v, lo, hi T) T {
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return Min(Max(v, lo), hi)
+}
+
+// MinOf returns the smallest element of xs. It reports false if xs is
+// empty.
+func MinOf[T cmp.Ordered](xs []T) (T, bool) {
+	if len(xs) == 0 {
+		var zero T
+		return zero, false
+	}
+	m := xs[0]
+	for _, x := range xs[1:] {
+		m = Min(m, x)
+	}
+	return m, true
+}
+
+// MaxOf returns the largest element of xs. It reports false if xs is
+// empty.
+func MaxOf[T cmp.Ordered](xs []T) (T, bool) {
+	if len(xs) == 0 {
+		var zero T
+		return zero, false
+	}
+	m := xs[0]
+	for _, x := range xs[1:] {
+		m = Max(m, x)
+	}
+	return m, true
+}
+
+// SumOf returns the sum of xs, which is zero for an empty slice. As with
+// the + operator, integer sums wrap on overflow.
+func SumOf[T Number](xs []T) T {
+	var s T
+	for _, x := range xs {
+		s += x
+	}
+	return s
+}
+
+// AverageOf returns the arithmetic mean of xs. It reports false if xs is
+// empty. The sum is accumulated as a float64 so integer inputs cannot
+// overflow.
+func AverageOf[T Number](xs []T) (float64, bool) {
+	if len(xs) == 0 {
+		return 0, false
+	}
+	var s float64
+	for _, x := range xs {
+		s += float64(x)
+	}
+	return s / float64(len(xs)), true
+}
+
+// Abs returns the absolute value of x. As with negation, Abs of the most
+// negative value of T overflows and returns it unchanged.
+func Abs[T Signed](x T) T {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// GCD returns the greatest common divisor of xs, which is always
+// non-negative. GCD of no arguments, or of all zeros, is zero.
+func GCD[T Integer](xs ...T) T {
+	var g T
+	for _, x := range xs {
+		for x != 0 {
+			g, x = x, g%x
+		}
+		if g < 0 {
+			g = -g
+		}
+	}
+	return g
+}
+
+// LCM returns the least common multiple of xs, which is always
+// non-negative. LCM is zero if any argument is zero and one if there are
+// no arguments. Like integer multiplication, it wraps on overflow.
+func LCM[T Integer](xs ...T) T {
+	var l T = 1
+	for _, x := range xs {
+		if x == 0 {
+			return 0
+		}
+		if x < 0 {
+			x = -x
+		}
+		l = l / GCD(l, x) * x
+	}
+	return l
+}
+
+// IntPow returns base raised to the power exp, computed by repeated
+// squaring. It reports false if the result overflows T.
+func IntPow[T Integer](base T, exp uint) (T, bool) {
+	var r T = 1
+	ok := true
+	for exp > 0 {
+		if exp&1 == 1 {
+			r, ok = mulChecked(r, base, ok)
+		}
+		exp >>= 1
+		if exp > 0 {
+			base, ok = mulChecked(base, base, ok)
+		}
+	}
+	return r, ok
+}
+
+// mulChecked returns a*b, and ok unless the product overflowed.
+func mulChecked[T Integer](a, b T, ok bool) (T, bool) {
+	p := a * b
+	if a == 0 || b == 0 {
+		return p, ok
+	}
+	// Signed overflow can leave p/b == a (e.g. -1 * MinInt64), so also
+	// check the sign of the product.
+	if p/b != a || ((a < 0) != (b < 0)) != (p < 0) {
+		return p, false
+	}
+	return p, ok
+}
+
+// ErrDivideByZero is returned by Divide when the divisor is zero.
+var ErrDivideByZero = errors.New("golib: division by zero")
+
+// Divide returns a/b, or ErrDivideByZero if b is zero, where the /
+// operator would return ±Inf or NaN.
+func Divide(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	return a / b, nil
+}
+
+// Ratio returns a/b as a float64, or zero if b is zero.
+func Ratio[T Number](a, b T) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Percent returns part as a percentage of whole, or zero if whole is
+// zero, so that 0 of 0 tasks done reads as 0%.
+func Percent[T Number](part, whole T) float64 {
+	return 100 * Ratio(part, whole)
+}
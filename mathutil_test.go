@@ -0,0 +1,153 @@
+package golib
+
+import "math"
+import "testing"
+
+func TestMinMaxClamp(t *testing.T) {
+	cases := []struct {
+		got, want int
+	}{
+		{Min(3, 7), 3},
+		{Max(3, 7), 7},
+		{Clamp(5, 0, 10), 5},
+		{Clamp(-5, 0, 10), 0},
+		{Clamp(15, 0, 10), 10},
+		{Clamp(15, 10, 0), 10},
+	}
+	for i, c := range cases {
+		if c.got != c.want {
+			t.Errorf("case %d: got %d, want %d", i, c.got, c.want)
+		}
+	}
+	if got := Max("apple", "banana"); got != "banana" {
+		t.Errorf("Max(apple, banana) == %q", got)
+	}
+}
+
+func TestAggregates(t *testing.T) {
+	xs := []int{4, -2, 9, 1}
+	if m, ok := MinOf(xs); m != -2 || !ok {
+		t.Errorf("MinOf(%v) == %d, %v", xs, m, ok)
+	}
+	if m, ok := MaxOf(xs); m != 9 || !ok {
+		t.Errorf("MaxOf(%v) == %d, %v", xs, m, ok)
+	}
+	if s := SumOf(xs); s != 12 {
+		t.Errorf("SumOf(%v) == %d", xs, s)
+	}
+	if a, ok := AverageOf(xs); a != 3 || !ok {
+		t.Errorf("AverageOf(%v) == %v, %v", xs, a, ok)
+	}
+	if a, ok := AverageOf([]uint8{200, 200}); a != 200 || !ok {
+		t.Errorf("AverageOf overflowing uint8s == %v, %v", a, ok)
+	}
+
+	var empty []float64
+	if _, ok := MinOf(empty); ok {
+		t.Error("MinOf(empty) reported ok")
+	}
+	if _, ok := AverageOf(empty); ok {
+		t.Error("AverageOf(empty) reported ok")
+	}
+	if s := SumOf(empty); s != 0 {
+		t.Errorf("SumOf(empty) == %v", s)
+	}
+}
+
+func TestGCDLCM(t *testing.T) {
+	cases := []struct {
+		xs       []int
+		gcd, lcm int
+	}{
+		{nil, 0, 1},
+		{[]int{12}, 12, 12},
+		{[]int{12, 18}, 6, 36},
+		{[]int{-12, 18}, 6, 36},
+		{[]int{4, 6, 10}, 2, 60},
+		{[]int{0, 5}, 5, 0},
+		{[]int{7, 13}, 1, 91},
+	}
+	for _, c := range cases {
+		if got := GCD(c.xs...); got != c.gcd {
+			t.Errorf("GCD(%v) == %d, want %d", c.xs, got, c.gcd)
+		}
+		if got := LCM(c.xs...); got != c.lcm {
+			t.Errorf("LCM(%v) == %d, want %d", c.xs, got, c.lcm)
+		}
+	}
+	if got := GCD[uint8](200, 150); got != 50 {
+		t.Errorf("GCD[uint8](200, 150) == %d", got)
+	}
+}
+
+func TestIntPow(t *testing.T) {
+	cases := []struct {
+		base int64
+		exp  uint
+		want int64
+		ok   bool
+	}{
+		{2, 0, 1, true},
+		{2, 10, 1024, true},
+		{-3, 3, -27, true},
+		{0, 5, 0, true},
+		{2, 62, 1 << 62, true},
+		{2, 63, 0, false},
+		{-2, 63, -1 << 63, true},
+		{10, 19, 0, false},
+		{-1, 1001, -1, true},
+	}
+	for _, c := range cases {
+		got, ok := IntPow(c.base, c.exp)
+		if ok != c.ok || (ok && got != c.want) {
+			t.Errorf("IntPow(%d, %d) == %d, %v, want %d, %v", c.base, c.exp, got, ok, c.want, c.ok)
+		}
+	}
+	if _, ok := IntPow[uint8](2, 8); ok {
+		t.Error("IntPow[uint8](2, 8) did not overflow")
+	}
+	if got, ok := IntPow[uint8](3, 5); got != 243 || !ok {
+		t.Errorf("IntPow[uint8](3, 5) == %d, %v", got, ok)
+	}
+}
+
+func TestAbs(t *testing.T) {
+	if Abs(-4) != 4 || Abs(4) != 4 || Abs[int8](-128) != -128 {
+		t.Error("Abs")
+	}
+}
+
+func TestDivide(t *testing.T) {
+	if q, err := Divide(7, 2); q != 3.5 || err != nil {
+		t.Errorf("Divide(7, 2) == %v, %v", q, err)
+	}
+	for _, b := range []float64{0, math.Copysign(0, -1)} {
+		if q, err := Divide(1, b); q != 0 || err != ErrDivideByZero {
+			t.Errorf("Divide(1, %v) == %v, %v", b, q, err)
+		}
+	}
+}
+
+func TestRatioPercent(t *testing.T) {
+	cases := []struct {
+		a, b           int
+		ratio, percent float64
+	}{
+		{1, 4, 0.25, 25},
+		{3, 2, 1.5, 150},
+		{-1, 2, -0.5, -50},
+		{0, 0, 0, 0},
+		{5, 0, 0, 0},
+	}
+	for _, c := range cases {
+		if got := Ratio(c.a, c.b); got != c.ratio {
+			t.Errorf("Ratio(%d, %d) == %v, want %v", c.a, c.b, got, c.ratio)
+		}
+		if got := Percent(c.a, c.b); got != c.percent {
+			t.Errorf("Percent(%d, %d) == %v, want %v", c.a, c.b, got, c.percent)
+		}
+	}
+	if got := Percent(0.5, 2.0); got != 25 {
+		t.Errorf("Percent(0.5, 2.0) == %v", got)
+	}
+}
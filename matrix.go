@@ -0,0 +1,239 @@
+package golib
+
+import "errors"
+import "fmt"
+import "math"
+import "strings"
+
+// ErrShape is returned by Matrix operations on matrices of incompatible
+// sizes.
+var ErrShape = errors.New("golib: incompatible matrix dimensions")
+
+// Matrix is a dense matrix of float64s. Operations return new matrices and
+// never modify their operands.
+type Matrix struct {
+	rows, cols int
+	data       []float64 // row-major
+}
+
+// NewMatrix returns a rows×cols matrix of zeros. It panics if either
+// dimension is negative.
+func NewMatrix(rows, cols int) *Matrix {
+	if rows < 0 || cols < 0 {
+		panic("golib: negative matrix dimension")
+	}
+	return &Matrix{rows, cols, make([]float64, rows*cols)}
+}
+
+// MatrixOf returns a matrix with the given rows. It panics if the rows
+// differ in length.
+func MatrixOf(rows ...[]float64) *Matrix {
+	cols := 0
+	if len(rows) > 0 {
+		cols = len(rows[0])
+	}
+	m := NewMatrix(len(rows), cols)
+	for i, row := range rows {
+		if len(row) != cols {
+			panic("golib: ragged matrix rows")
+		}
+		copy(m.data[i*cols:], row)
+	}
+	return m
+}
+
+// Identity returns the n×n identity matrix.
+func Identity(n int) *Matrix {
+	m := NewMatrix(n, n)
+	for i := range n {
+		m.data[i*n+i] = 1
+	}
+	return m
+}
+
+// Rows returns the number of rows in m.
+func (m *Matrix) Rows() int { return m.rows }
+
+// Cols returns the number of columns in m.
+func (m *Matrix) Cols() int { return m.cols }
+
+// At returns the element in row i and column j, counting from zero.
+func (m *Matrix) At(i, j int) float64 {
+	return m.data[m.index(i, j)]
+}
+
+// Set sets the element in row i and column j to v.
+func (m *Matrix) Set(i, j int, v float64) {
+	m.data[m.index(i, j)] = v
+}
+
+func (m *Matrix) index(i, j int) int {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(fmt.Sprintf("golib: index (%d, %d) out of range for %d×%d matrix", i, j, m.rows, m.cols))
+	}
+	return i*m.cols + j
+}
+
+// Add returns m+b.
+func (m *Matrix) Add(b *Matrix) (*Matrix, error) {
+	if m.rows != b.rows || m.cols != b.cols {
+		return nil, ErrShape
+	}
+	r := NewMatrix(m.rows, m.cols)
+	for i := range r.data {
+		r.data[i] = m.data[i] + b.data[i]
+	}
+	return r, nil
+}
+
+// Scale returns m with every element multiplied by k.
+func (m *Matrix) Scale(k float64) *Matrix {
+	r := NewMatrix(m.rows, m.cols)
+	for i, v := range m.data {
+		r.data[i] = k * v
+	}
+	return r
+}
+
+// Mul returns the matrix product m×b, which requires m to have as many
+// columns as b has rows.
+func (m *Matrix) Mul(b *Matrix) (*Matrix, error) {
+	if m.cols != b.rows {
+		return nil, ErrShape
+	}
+	r := NewMatrix(m.rows, b.cols)
+	for i := range m.rows {
+		for k := range m.cols {
+			a := m.data[i*m.cols+k]
+			for j := range b.cols {
+				r.data[i*r.cols+j] += a * b.data[k*b.cols+j]
+			}
+		}
+	}
+	return r, nil
+}
+
+// Transpose returns the transpose of m.
+func (m *Matrix) Transpose() *Matrix {
+	r := NewMatrix(m.cols, m.rows)
+	for i := range m.rows {
+		for j := range m.cols {
+			r.data[j*r.cols+i] = m.data[i*m.cols+j]
+		}
+	}
+	return r
+}
+
+// Determinant returns the determinant of a square matrix, computed by
+// Gaussian elimination with partial pivoting. The determinant of a 0×0
+// matrix is 1.
+func (m *Matrix) Determinant() (float64, error) {
+	if m.rows != m.cols {
+		return 0, ErrShape
+	}
+	n := m.rows
+	a := append([]float64(nil), m.data...)
+	det := 1.0
+	for c := range n {
+		p := c
+		for r := c + 1; r < n; r++ {
+			if math.Abs(a[r*n+c]) > math.Abs(a[p*n+c]) {
+				p = r
+			}
+		}
+		if a[p*n+c] == 0 {
+			return 0, nil
+		}
+		if p != c {
+			for j := range n {
+				a[c*n+j], a[p*n+j] = a[p*n+j], a[c*n+j]
+			}
+			det = -det
+		}
+		det *= a[c*n+c]
+		for r := c + 1; r < n; r++ {
+			f := a[r*n+c] / a[c*n+c]
+			for j := c; j < n; j++ {
+				a[r*n+j] -= f * a[c*n+j]
+			}
+		}
+	}
+	return det, nil
+}
+
+// Equal reports whether m and b have the same size and elements.
+func (m *Matrix) Equal(b *Matrix) bool {
+	if m.rows != b.rows || m.cols != b.cols {
+		return false
+	}
+	for i, v := range m.data {
+		if b.data[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats m one row per line, with columns aligned.
+func (m *Matrix) String() string {
+	t := new(Table)
+	for i := range m.rows {
+		row := make([]any, m.cols)
+		for j := range m.cols {
+			row[j] = m.data[i*m.cols+j]
+		}
+		t.AddRow(row...)
+	}
+	for j := range m.cols {
+		t.SetAlign(j, AlignRight)
+	}
+	var b strings.Builder
+	t.Render(&b)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Rotation returns the 3×3 matrix that rotates points anticlockwise by
+// theta radians about the origin, for use with Transform.
+func Rotation(theta float64) *Matrix {
+	sin, cos := math.Sincos(theta)
+	return MatrixOf(
+		[]float64{cos, -sin, 0},
+		[]float64{sin, cos, 0},
+		[]float64{0, 0, 1},
+	)
+}
+
+// Scaling returns the 3×3 matrix that scales points by sx and sy.
+func Scaling(sx, sy float64) *Matrix {
+	return MatrixOf(
+		[]float64{sx, 0, 0},
+		[]float64{0, sy, 0},
+		[]float64{0, 0, 1},
+	)
+}
+
+// Translation returns the 3×3 matrix that moves points by dx and dy.
+func Translation(dx, dy float64) *Matrix {
+	return MatrixOf(
+		[]float64{1, 0, dx},
+		[]float64{0, 1, dy},
+		[]float64{0, 0, 1},
+	)
+}
+
+// Transform applies m to the point v. A 2×2 matrix is applied directly; a
+// 3×3 matrix is treated as an affine transform in homogeneous
+// coordinates, so transforms built with Rotation, Scaling and Translation
+// compose with Mul. Any other size returns ErrShape.
+func (m *Matrix) Transform(v Vector) (Vector, error) {
+	switch {
+	case m.rows == 2 && m.cols == 2:
+		d := m.data
+		return Vector{d[0]*v.X + d[1]*v.Y, d[2]*v.X + d[3]*v.Y}, nil
+	case m.rows == 3 && m.cols == 3:
+		d := m.data
+		w := d[6]*v.X + d[7]*v.Y + d[8]
+		return Vector{(d[0]*v.X + d[1]*v.Y + d[2]) / w, (d[3]*v.X + d[4]*v.Y + d[5]) / w}, nil
+	}
+	return Vector{}, ErrShape
+}
@@ -0,0 +1,98 @@
+package golib
+
+import "math"
+import "testing"
+
+func TestMatrixArithmetic(t *testing.T) {
+	a := MatrixOf([]float64{1, 2, 3}, []float64{4, 5, 6})
+	b := MatrixOf([]float64{7, 8}, []float64{9, 10}, []float64{11, 12})
+
+	p, err := a.Mul(b)
+	if err != nil || !p.Equal(MatrixOf([]float64{58, 64}, []float64{139, 154})) {
+		t.Errorf("Mul() ==\n%v, %v", p, err)
+	}
+	if _, err := a.Mul(a); err != ErrShape {
+		t.Errorf("Mul() of 2×3 by 2×3: err == %v", err)
+	}
+
+	s, err := a.Add(a)
+	if err != nil || !s.Equal(a.Scale(2)) {
+		t.Errorf("Add() ==\n%v, %v", s, err)
+	}
+	if _, err := a.Add(b); err != ErrShape {
+		t.Errorf("Add() of 2×3 and 3×2: err == %v", err)
+	}
+
+	tr := a.Transpose()
+	if tr.Rows() != 3 || tr.Cols() != 2 || tr.At(2, 0) != 3 || tr.At(0, 1) != 4 {
+		t.Errorf("Transpose() ==\n%v", tr)
+	}
+
+	if p, _ := b.Mul(Identity(2)); !p.Equal(b) {
+		t.Error("b×I != b")
+	}
+
+	m := NewMatrix(2, 2)
+	m.Set(1, 0, 7)
+	if m.At(1, 0) != 7 || m.At(0, 1) != 0 {
+		t.Errorf("Set/At: %v", m)
+	}
+	if got := a.String(); got != "1  2  3\n4  5  6" {
+		t.Errorf("String() == %q", got)
+	}
+}
+
+func TestMatrixIndexPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("At(2, 0) on a 2×2 matrix did not panic")
+		}
+	}()
+	NewMatrix(2, 2).At(2, 0)
+}
+
+func TestDeterminant(t *testing.T) {
+	cases := []struct {
+		m    *Matrix
+		want float64
+	}{
+		{NewMatrix(0, 0), 1},
+		{MatrixOf([]float64{5}), 5},
+		{MatrixOf([]float64{1, 2}, []float64{3, 4}), -2},
+		{MatrixOf([]float64{0, 1}, []float64{1, 0}), -1},
+		{MatrixOf([]float64{2, 0, 1}, []float64{1, 3, 2}, []float64{1, 1, 2}), 6},
+		{MatrixOf([]float64{1, 2, 3}, []float64{4, 5, 6}, []float64{7, 8, 9}), 0},
+		{Identity(5).Scale(2), 32},
+	}
+	for _, c := range cases {
+		got, err := c.m.Determinant()
+		if err != nil || math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("Determinant() of\n%v\n== %v, %v, want %v", c.m, got, err, c.want)
+		}
+	}
+	if _, err := NewMatrix(2, 3).Determinant(); err != ErrShape {
+		t.Errorf("Determinant() of 2×3: err == %v", err)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	near := func(a, b Vector) bool {
+		return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+	}
+
+	// Scale, then rotate a quarter turn, then translate.
+	m, _ := Rotation(math.Pi / 2).Mul(Scaling(2, 3))
+	m, _ = Translation(10, 0).Mul(m)
+	got, err := m.Transform(Vector{1, 1})
+	if err != nil || !near(got, Vector{7, 2}) {
+		t.Errorf("Transform() == %v, %v, want {7 2}", got, err)
+	}
+
+	got, err = MatrixOf([]float64{0, -1}, []float64{1, 0}).Transform(Vector{1, 0})
+	if err != nil || !near(got, Vector{0, 1}) {
+		t.Errorf("Transform() with 2×2 == %v, %v", got, err)
+	}
+	if _, err := NewMatrix(4, 4).Transform(Vector{}); err != ErrShape {
+		t.Errorf("Transform() with 4×4: err == %v", err)
+	}
+}
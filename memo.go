@@ -0,0 +1,120 @@
+package golib
+
+import "sync"
+import "time"
+
+// MemoOptions configure Memoize. The zero value caches every result for
+// ever.
+type MemoOptions struct {
+	// TTL is how long a result is reused. Zero means for ever.
+	TTL time.Duration
+	// MaxSize is the most results kept; beyond it the least recently
+	// used is dropped. Zero means no limit.
+	MaxSize int
+	// Dedupe makes concurrent calls for a key that is not cached wait
+	// for one call of the function and share its result, rather than
+	// each calling it.
+	Dedupe bool
+	// Now tells the time for TTL. Nil means time.Now.
+	Now func() time.Time
+}
+
+// Memoize returns a function that returns fn(k), calling fn only for keys
+// whose result is not cached. fn should be a pure function of k. The
+// returned function is safe for concurrent use; fn runs outside the
+// cache's lock. If fn panics nothing is cached, and calls waiting on it
+// under Dedupe call fn themselves.
+func Memoize[K comparable, V any](fn func(K) V, opts MemoOptions) func(K) V {
+	m := &memo[K, V]{fn: fn, opts: opts, entries: map[K]*memoEntry[K, V]{}, order: NewList[K]()}
+	if m.opts.Now == nil {
+		m.opts.Now = time.Now
+	}
+	if opts.Dedupe {
+		m.inflight = map[K]*memoCall[V]{}
+	}
+	return m.call
+}
+
+type memo[K comparable, V any] struct {
+	fn   func(K) V
+	opts MemoOptions
+
+	mu       sync.Mutex
+	entries  map[K]*memoEntry[K, V]
+	order    *List[K] // most recently used first
+	inflight map[K]*memoCall[V]
+}
+
+type memoEntry[K comparable, V any] struct {
+	value   V
+	expires time.Time // zero if it never does
+	elem    *Element[K]
+}
+
+// memoCall is a call of fn that others may wait for.
+type memoCall[V any] struct {
+	done  chan struct{}
+	value V
+	ok    bool // whether fn returned rather than panicked
+}
+
+func (m *memo[K, V]) call(k K) V {
+	m.mu.Lock()
+	if e, ok := m.entries[k]; ok {
+		if e.expires.IsZero() || m.opts.Now().Before(e.expires) {
+			m.order.MoveToFront(e.elem)
+			m.mu.Unlock()
+			return e.value
+		}
+		m.order.Remove(e.elem)
+		delete(m.entries, k)
+	}
+	var c *memoCall[V]
+	if m.inflight != nil {
+		if c, ok := m.inflight[k]; ok {
+			m.mu.Unlock()
+			<-c.done
+			if c.ok {
+				return c.value
+			}
+			return m.fn(k)
+		}
+		c = &memoCall[V]{done: make(chan struct{})}
+		m.inflight[k] = c
+	}
+	m.mu.Unlock()
+
+	if c != nil {
+		defer func() {
+			m.mu.Lock()
+			delete(m.inflight, k)
+			m.mu.Unlock()
+			close(c.done)
+		}()
+	}
+	v := m.fn(k)
+	m.store(k, v)
+	if c != nil {
+		c.value, c.ok = v, true
+	}
+	return v
+}
+
+// store caches v for k, dropping the least recently used result if the
+// cache is full.
+func (m *memo[K, V]) store(k K, v V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := &memoEntry[K, V]{value: v}
+	if m.opts.TTL > 0 {
+		e.expires = m.opts.Now().Add(m.opts.TTL)
+	}
+	if old, ok := m.entries[k]; ok {
+		m.order.Remove(old.elem)
+	}
+	e.elem = m.order.PushFront(k)
+	m.entries[k] = e
+	if m.opts.MaxSize > 0 && m.order.Len() > m.opts.MaxSize {
+		delete(m.entries, m.order.Remove(m.order.Back()))
+	}
+}
@@ -0,0 +1,94 @@
+package golib
+
+import "sync"
+import "sync/atomic"
+import "testing"
+import "time"
+
+func TestMemoize(t *testing.T) {
+	calls := map[int]int{}
+	square := Memoize(func(n int) int { calls[n]++; return n * n }, MemoOptions{})
+	for range 3 {
+		if got := square(4); got != 16 {
+			t.Fatalf("square(4) = %d", got)
+		}
+	}
+	if calls[4] != 1 {
+		t.Errorf("fn called %d times for one key", calls[4])
+	}
+}
+
+func TestMemoizeTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+	calls := 0
+	f := Memoize(func(string) int { calls++; return calls }, MemoOptions{TTL: time.Minute, Now: func() time.Time { return now }})
+	f("a")
+	now = now.Add(time.Minute - 1)
+	if got := f("a"); got != 1 {
+		t.Errorf("before TTL got %d", got)
+	}
+	now = now.Add(1)
+	if got := f("a"); got != 2 {
+		t.Errorf("after TTL got %d", got)
+	}
+}
+
+func TestMemoizeMaxSize(t *testing.T) {
+	calls := map[string]int{}
+	f := Memoize(func(k string) string { calls[k]++; return k }, MemoOptions{MaxSize: 2})
+	for _, k := range []string{"a", "b", "a", "c", "a", "b"} {
+		f(k)
+	}
+	// c evicted b, the least recently used; a stayed.
+	want := map[string]int{"a": 1, "b": 2, "c": 1}
+	for k, n := range want {
+		if calls[k] != n {
+			t.Errorf("fn(%q) called %d times, want %d", k, calls[k], n)
+		}
+	}
+}
+
+func TestMemoizeDedupe(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	f := Memoize(func(k int) int {
+		calls.Add(1)
+		<-release
+		return k + 1
+	}, MemoOptions{Dedupe: true})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := f(1); got != 2 {
+				t.Errorf("f(1) = %d", got)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times", n)
+	}
+}
+
+func TestMemoizePanic(t *testing.T) {
+	fail := true
+	f := Memoize(func(k int) int {
+		if fail {
+			panic("boom")
+		}
+		return k
+	}, MemoOptions{Dedupe: true})
+	func() {
+		defer func() { recover() }()
+		f(1)
+	}()
+	fail = false
+	if got := f(1); got != 1 {
+		t.Errorf("f(1) after panic = %d", got)
+	}
+}
@@ -0,0 +1,231 @@
+// Package migrate evolves the data in a kv store through numbered
+// migrations, recording the applied version in the store itself.
+//
+// Each migration runs against a copy of the store, which replaces the
+// original only if the migration succeeds, so a failing migration never
+// leaves the data half-changed. A dry run performs the same work on a
+// copy and throws it away, reporting what would have happened.
+package migrate
+
+import "bytes"
+import "errors"
+import "fmt"
+import "strconv"
+
+import "github.com/lukehedger/golib/kv"
+
+// VersionKey is the store key holding the applied version, as a decimal
+// integer. A store without it is at version 0.
+const VersionKey = "_migrate/version"
+
+// Latest, passed as a target version, means the highest registered
+// version.
+const Latest = -1
+
+// ErrIrreversible is returned when migrating down through a migration
+// that has no Down function.
+var ErrIrreversible = errors.New("migrate: migration has no down function")
+
+// A Migration changes the store from version Version-1 to Version (Up),
+// and optionally back again (Down).
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(s *kv.Store) error
+	Down    func(s *kv.Store) error
+}
+
+// Direction is the direction of a Step.
+type Direction int
+
+const (
+	Up Direction = iota
+	Down
+)
+
+func (d Direction) String() string {
+	if d == Down {
+		return "down"
+	}
+	return "up"
+}
+
+// A Step is a migration applied, or to be applied in a dry run.
+type Step struct {
+	Version   int
+	Name      string
+	Direction Direction
+}
+
+func (s Step) String() string {
+	return fmt.Sprintf("%s %d %s", s.Direction, s.Version, s.Name)
+}
+
+// Migrator holds the registered migrations. The zero value has none.
+type Migrator struct {
+	migrations []Migration
+}
+
+// New returns a Migrator with the given migrations, as by Register.
+func New(ms ...Migration) (*Migrator, error) {
+	m := &Migrator{}
+	for _, mig := range ms {
+		if err := m.Register(mig); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Register adds a migration. Versions must be registered in increasing
+// order, starting from 1 with no gaps, and every migration needs an Up
+// function.
+func (m *Migrator) Register(mig Migration) error {
+	if want := len(m.migrations) + 1; mig.Version != want {
+		return fmt.Errorf("migrate: registered version %d, want %d", mig.Version, want)
+	}
+	if mig.Up == nil {
+		return fmt.Errorf("migrate: version %d has no up function", mig.Version)
+	}
+	m.migrations = append(m.migrations, mig)
+	return nil
+}
+
+// Latest returns the highest registered version.
+func (m *Migrator) Latest() int { return len(m.migrations) }
+
+// Version returns the version recorded in s.
+func Version(s *kv.Store) (int, error) {
+	b, err := s.Get(VersionKey)
+	if errors.Is(err, kv.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.Atoi(string(b))
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("migrate: bad version %q in store", b)
+	}
+	return v, nil
+}
+
+// Plan returns the steps that would take s to the target version,
+// without running them.
+func (m *Migrator) Plan(s *kv.Store, target int) ([]Step, error) {
+	if target == Latest {
+		target = m.Latest()
+	}
+	if target < 0 || target > m.Latest() {
+		return nil, fmt.Errorf("migrate: no version %d", target)
+	}
+	cur, err := Version(s)
+	if err != nil {
+		return nil, err
+	}
+	if cur > m.Latest() {
+		return nil, fmt.Errorf("migrate: store is at version %d, newer than any registered", cur)
+	}
+	var steps []Step
+	for v := cur + 1; v <= target; v++ {
+		mig := m.migrations[v-1]
+		steps = append(steps, Step{v, mig.Name, Up})
+	}
+	for v := cur; v > target; v-- {
+		mig := m.migrations[v-1]
+		if mig.Down == nil {
+			return nil, fmt.Errorf("%w: version %d %s", ErrIrreversible, v, mig.Name)
+		}
+		steps = append(steps, Step{v, mig.Name, Down})
+	}
+	return steps, nil
+}
+
+// Options configure Migrate.
+type Options struct {
+	// DryRun runs the migrations against a copy of the store and leaves
+	// the store itself unchanged.
+	DryRun bool
+	// Logf, if set, is called before each step.
+	Logf func(format string, args ...any)
+}
+
+// Migrate runs the migrations that take s to the target version,
+// recording each version as it is reached, and returns the steps taken.
+// If a step fails, s is left at the last version reached and the steps
+// completed so far are returned with the error.
+func (m *Migrator) Migrate(s *kv.Store, target int, opts Options) ([]Step, error) {
+	return m.run(s, target, opts, nil)
+}
+
+// MigrateFile migrates the kv snapshot at path, as written by
+// kv.Store.SaveFile, saving it after each step. A file that does not
+// exist is treated as an empty store at version 0. With DryRun, the file
+// is not written.
+func (m *Migrator) MigrateFile(path string, target int, opts Options) ([]Step, error) {
+	s, err := kv.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return m.run(s, target, opts, func(s *kv.Store) error { return s.SaveFile(path) })
+}
+
+// run is Migrate, calling after, if set, once each step has been applied
+// for real.
+func (m *Migrator) run(s *kv.Store, target int, opts Options, after func(*kv.Store) error) ([]Step, error) {
+	steps, err := m.Plan(s, target)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		if s, err = clone(s); err != nil {
+			return nil, err
+		}
+		after = nil
+	}
+	for i, step := range steps {
+		if opts.Logf != nil {
+			opts.Logf("migrate: %v", step)
+		}
+		if err := apply(s, m.migrations[step.Version-1], step.Direction); err != nil {
+			return steps[:i], fmt.Errorf("migrate: %v: %w", step, err)
+		}
+		if after != nil {
+			if err := after(s); err != nil {
+				return steps[:i], err
+			}
+		}
+	}
+	return steps, nil
+}
+
+// apply runs one migration on a copy of s and copies the result back.
+func apply(s *kv.Store, mig Migration, dir Direction) error {
+	c, err := clone(s)
+	if err != nil {
+		return err
+	}
+	f, version := mig.Up, mig.Version
+	if dir == Down {
+		f, version = mig.Down, mig.Version-1
+	}
+	if err := f(c); err != nil {
+		return err
+	}
+	c.Put(VersionKey, []byte(strconv.Itoa(version)))
+
+	var b bytes.Buffer
+	if err := c.Save(&b); err != nil {
+		return err
+	}
+	return s.Load(&b)
+}
+
+func clone(s *kv.Store) (*kv.Store, error) {
+	var b bytes.Buffer
+	if err := s.Save(&b); err != nil {
+		return nil, err
+	}
+	c := kv.New()
+	return c, c.Load(&b)
+}
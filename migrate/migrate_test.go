@@ -0,0 +1,232 @@
+package migrate
+
+import "errors"
+import "path/filepath"
+import "reflect"
+import "strings"
+import "testing"
+
+import "github.com/lukehedger/golib/kv"
+
+// migrations renames key "name" to "title" and then upper-cases it.
+func migrations(t *testing.T) *Migrator {
+	t.Helper()
+	m, err := New(
+		Migration{
+			Version: 1,
+			Name:    "rename",
+			Up:      func(s *kv.Store) error { return rename(s, "name", "title") },
+			Down:    func(s *kv.Store) error { return rename(s, "title", "name") },
+		},
+		Migration{
+			Version: 2,
+			Name:    "upper",
+			Up: func(s *kv.Store) error {
+				v, err := s.Get("title")
+				if err != nil {
+					return err
+				}
+				s.Put("title", []byte(strings.ToUpper(string(v))))
+				return nil
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func rename(s *kv.Store, from, to string) error {
+	v, err := s.Get(from)
+	if err != nil {
+		return err
+	}
+	s.Put(to, v)
+	return s.Delete(from)
+}
+
+func get(t *testing.T, s *kv.Store, key string) string {
+	t.Helper()
+	v, err := s.Get(key)
+	if err != nil {
+		return "<" + err.Error() + ">"
+	}
+	return string(v)
+}
+
+func TestMigrate(t *testing.T) {
+	m := migrations(t)
+	s := kv.New()
+	s.Put("name", []byte("go"))
+
+	steps, err := m.Migrate(s, Latest, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Step{{1, "rename", Up}, {2, "upper", Up}}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps == %v, want %v", steps, want)
+	}
+	if v, _ := Version(s); v != 2 {
+		t.Errorf("Version == %d, want 2", v)
+	}
+	if got := get(t, s, "title"); got != "GO" {
+		t.Errorf("title == %q, want \"GO\"", got)
+	}
+
+	// Already current: nothing to do.
+	if steps, err := m.Migrate(s, Latest, Options{}); err != nil || len(steps) != 0 {
+		t.Errorf("second Migrate == %v, %v, want no steps", steps, err)
+	}
+}
+
+func TestMigrateDown(t *testing.T) {
+	m := migrations(t)
+	s := kv.New()
+	s.Put("name", []byte("go"))
+	if _, err := m.Migrate(s, 1, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := m.Migrate(s, 0, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Step{{1, "rename", Down}}; !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps == %v, want %v", steps, want)
+	}
+	if v, _ := Version(s); v != 0 {
+		t.Errorf("Version == %d, want 0", v)
+	}
+	if got := get(t, s, "name"); got != "go" {
+		t.Errorf("name == %q, want \"go\"", got)
+	}
+
+	// Version 2 has no Down, so migrating back from it fails up front.
+	if _, err := m.Migrate(s, Latest, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Migrate(s, 0, Options{}); !errors.Is(err, ErrIrreversible) {
+		t.Errorf("Migrate down through version 2: err == %v, want ErrIrreversible", err)
+	}
+	if v, _ := Version(s); v != 2 {
+		t.Errorf("Version after refused down == %d, want 2", v)
+	}
+}
+
+func TestMigrateDryRun(t *testing.T) {
+	m := migrations(t)
+	s := kv.New()
+	s.Put("name", []byte("go"))
+
+	var logged []string
+	logf := func(format string, args ...any) { logged = append(logged, format) }
+	steps, err := m.Migrate(s, Latest, Options{DryRun: true, Logf: logf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || len(logged) != 2 {
+		t.Errorf("dry run took %d steps and logged %d, want 2 and 2", len(steps), len(logged))
+	}
+	if v, _ := Version(s); v != 0 {
+		t.Errorf("Version after dry run == %d, want 0", v)
+	}
+	if got := get(t, s, "name"); got != "go" {
+		t.Errorf("name after dry run == %q, want \"go\"", got)
+	}
+}
+
+func TestMigrateFailure(t *testing.T) {
+	m := migrations(t)
+	s := kv.New()
+	s.Put("name", []byte("go"))
+	boom := errors.New("boom")
+	err := m.Register(Migration{
+		Version: 3,
+		Name:    "broken",
+		Up: func(s *kv.Store) error {
+			s.Put("half", []byte("done"))
+			return boom
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := m.Migrate(s, Latest, Options{})
+	if !errors.Is(err, boom) {
+		t.Fatalf("err == %v, want boom", err)
+	}
+	if len(steps) != 2 {
+		t.Errorf("completed %d steps, want 2", len(steps))
+	}
+	if v, _ := Version(s); v != 2 {
+		t.Errorf("Version == %d, want 2", v)
+	}
+	if _, err := s.Get("half"); err != kv.ErrNotFound {
+		t.Errorf("failed migration left its changes behind")
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	m := migrations(t)
+	path := filepath.Join(t.TempDir(), "data.json")
+	s := kv.New()
+	s.Put("name", []byte("go"))
+	if err := s.SaveFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.MigrateFile(path, Latest, Options{DryRun: true}); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := kv.Open(path); get(t, s, "name") != "go" {
+		t.Errorf("dry run changed the file")
+	}
+
+	if _, err := m.MigrateFile(path, Latest, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := kv.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := Version(s); v != 2 || get(t, s, "title") != "GO" {
+		t.Errorf("file at version %d with title %q, want 2 and \"GO\"", v, get(t, s, "title"))
+	}
+}
+
+func TestRegister(t *testing.T) {
+	up := func(*kv.Store) error { return nil }
+	tests := []struct {
+		name string
+		ms   []Migration
+	}{
+		{"gap", []Migration{{Version: 1, Up: up}, {Version: 3, Up: up}}},
+		{"zero", []Migration{{Version: 0, Up: up}}},
+		{"duplicate", []Migration{{Version: 1, Up: up}, {Version: 1, Up: up}}},
+		{"no up", []Migration{{Version: 1}}},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.ms...); err == nil {
+			t.Errorf("%s: New succeeded", tt.name)
+		}
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	m := migrations(t)
+	s := kv.New()
+	if _, err := m.Plan(s, 5); err == nil {
+		t.Errorf("Plan to unknown version succeeded")
+	}
+	s.Put(VersionKey, []byte("9"))
+	if _, err := m.Plan(s, Latest); err == nil {
+		t.Errorf("Plan from a newer version succeeded")
+	}
+	s.Put(VersionKey, []byte("x"))
+	if _, err := Version(s); err == nil {
+		t.Errorf("Version with a bad record succeeded")
+	}
+}
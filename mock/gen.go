@@ -0,0 +1,187 @@
+package mock
+
+import "bytes"
+import "fmt"
+import "go/ast"
+import "go/format"
+import "go/parser"
+import "go/printer"
+import "go/token"
+import "path/filepath"
+import "slices"
+import "strconv"
+import "strings"
+
+// Generate returns the source of a mock of the interface called name in
+// the package in dir. The mock is called Mock followed by the interface's
+// name and belongs to the same package; write it to a _test.go file
+// there. Interfaces that embed others or have type parameters are not
+// supported.
+func Generate(dir, name string) ([]byte, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range f.Decls {
+			d, ok := d.(*ast.GenDecl)
+			if !ok || d.Tok != token.TYPE {
+				continue
+			}
+			for _, s := range d.Specs {
+				s := s.(*ast.TypeSpec)
+				if s.Name.Name != name {
+					continue
+				}
+				it, ok := s.Type.(*ast.InterfaceType)
+				if !ok {
+					return nil, fmt.Errorf("mock: %s is not an interface", name)
+				}
+				if s.TypeParams != nil {
+					return nil, fmt.Errorf("mock: %s has type parameters", name)
+				}
+				return generate(fset, f, name, it)
+			}
+		}
+	}
+	return nil, fmt.Errorf("mock: no type %s in %s", name, dir)
+}
+
+func generate(fset *token.FileSet, f *ast.File, name string, it *ast.InterfaceType) ([]byte, error) {
+	expr := func(e ast.Expr) string {
+		var b bytes.Buffer
+		printer.Fprint(&b, fset, e)
+		return b.String()
+	}
+
+	var body bytes.Buffer
+	used := map[string]bool{} // package names in the method signatures
+	mock := "Mock" + name
+	fmt.Fprintf(&body, "// %s is a mock %s.\ntype %s struct {\n\tmock.Mock\n}\n", mock, name, mock)
+	for _, m := range it.Methods.List {
+		ft, ok := m.Type.(*ast.FuncType)
+		if !ok {
+			return nil, fmt.Errorf("mock: %s embeds %s, which is not supported", name, expr(m.Type))
+		}
+		ast.Inspect(ft, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok {
+					used[id.Name] = true
+				}
+			}
+			return true
+		})
+
+		// Parameters keep their names unless they are missing, blank, or
+		// would clash with the receiver, the ret local or a package the
+		// body refers to; those become a0, a1 and so on.
+		var types, args []string
+		taken := map[string]bool{}
+		for _, p := range ft.Params.List {
+			names := p.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{nil}
+			}
+			for _, n := range names {
+				arg := ""
+				if n != nil && n.Name != "_" && n.Name != "m" && n.Name != "ret" && n.Name != "mock" && !used[n.Name] {
+					arg = n.Name
+					taken[arg] = true
+				}
+				types = append(types, expr(p.Type))
+				args = append(args, arg)
+			}
+		}
+		var params []string
+		for i, arg := range args {
+			if arg == "" {
+				arg = "a" + strconv.Itoa(i)
+				for taken[arg] {
+					arg += "_"
+				}
+				taken[arg] = true
+				args[i] = arg
+			}
+			params = append(params, arg+" "+types[i])
+		}
+		var results []string
+		if ft.Results != nil {
+			for _, r := range ft.Results.List {
+				for range max(len(r.Names), 1) {
+					results = append(results, expr(r.Type))
+				}
+			}
+		}
+
+		for _, method := range m.Names {
+			call := fmt.Sprintf("m.Called(%q", method.Name)
+			for _, a := range args {
+				call += ", " + a
+			}
+			call += ")"
+			sig := "(" + strings.Join(params, ", ") + ")"
+			switch len(results) {
+			case 0:
+			case 1:
+				sig += " " + results[0]
+			default:
+				sig += " (" + strings.Join(results, ", ") + ")"
+			}
+			fmt.Fprintf(&body, "\nfunc (m *%s) %s%s {\n", mock, method.Name, sig)
+			if len(results) == 0 {
+				fmt.Fprintf(&body, "\t%s\n}\n", call)
+				continue
+			}
+			fmt.Fprintf(&body, "\tret := %s\n\treturn ", call)
+			for i, r := range results {
+				if i > 0 {
+					body.WriteString(", ")
+				}
+				fmt.Fprintf(&body, "mock.Result[%s](ret, %d)", r, i)
+			}
+			body.WriteString("\n}\n")
+		}
+	}
+
+	// Standard library imports come first, then the rest, as in this repo.
+	var std []string
+	other := []string{strconv.Quote("github.com/lukehedger/golib/mock")}
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		pkg, spec := filepath.Base(path), imp.Path.Value
+		if imp.Name != nil {
+			pkg, spec = imp.Name.Name, imp.Name.Name+" "+spec
+		}
+		if !used[pkg] {
+			continue
+		}
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	slices.Sort(std)
+	slices.Sort(other)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by golib mockgen; DO NOT EDIT.\n\npackage %s\n\n", f.Name.Name)
+	for _, group := range [][]string{std, other} {
+		for _, imp := range group {
+			fmt.Fprintf(&out, "import %s\n", imp)
+		}
+		if len(group) > 0 {
+			out.WriteString("\n")
+		}
+	}
+	out.Write(body.Bytes())
+	return format.Source(out.Bytes())
+}
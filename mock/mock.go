@@ -0,0 +1,205 @@
+// Package mock records calls to test doubles and checks them against
+// expectations, in place of hand-written fakes.
+//
+// A mock of an interface embeds Mock and passes each call to Called,
+// which records the arguments and returns the results set with On:
+//
+//	type MockSink struct{ mock.Mock }
+//
+//	func (m *MockSink) Put(key string, value []byte) error {
+//		ret := m.Called("Put", key, value)
+//		return mock.Result[error](ret, 0)
+//	}
+//
+// Generate writes such mocks from an interface's source, and the golib
+// mockgen command runs it. A test then sets expectations, runs the code
+// under test and verifies:
+//
+//	m := new(MockSink)
+//	m.On("Put", "a", mock.Any).Return(nil).Times(1)
+//	...
+//	m.Verify(t)
+package mock
+
+import "fmt"
+import "reflect"
+import "strings"
+import "sync"
+
+// Any matches any argument.
+var Any = Match(func(any) bool { return true })
+
+// A Matcher matches an argument more loosely than equality.
+type Matcher struct {
+	match func(any) bool
+}
+
+// Match returns a Matcher for the arguments for which fn returns true.
+func Match(fn func(arg any) bool) Matcher { return Matcher{fn} }
+
+// TB is the part of testing.TB that Verify uses.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// A Call is an expected call, set up with Mock.On.
+type Call struct {
+	method  string
+	args    []any
+	returns []any
+	run     func(args []any)
+	times   int // 0 means at least once
+	calls   int
+}
+
+// Return sets the results of the call.
+func (c *Call) Return(results ...any) *Call {
+	c.returns = results
+	return c
+}
+
+// Times says the call must be made exactly n times. Without it, the call
+// may be made any number of times but at least once.
+func (c *Call) Times(n int) *Call {
+	c.times = n
+	return c
+}
+
+// Run sets fn to be called with the arguments of each matching call,
+// before its results are returned. It may, for instance, fill in a
+// pointer argument.
+func (c *Call) Run(fn func(args []any)) *Call {
+	c.run = fn
+	return c
+}
+
+func (c *Call) String() string {
+	return describe(c.method, c.args)
+}
+
+// Mock records calls and matches them to expectations. The zero value
+// expects nothing. It is safe for concurrent use.
+type Mock struct {
+	mu       sync.Mutex
+	expected []*Call
+	calls    map[string][][]any
+	errs     []string
+}
+
+// On expects a call to method with arguments equal to args, or matched by
+// them where they are Matchers, and returns the Call to set its results.
+// When more than one expectation matches a call, the first set up that has
+// not been used up is taken.
+func (m *Mock) On(method string, args ...any) *Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := &Call{method: method, args: args}
+	m.expected = append(m.expected, c)
+	return c
+}
+
+// Results are the values set with Return for a call, read with Result.
+type Results struct {
+	Method string
+	Values []any
+}
+
+// Called records a call to method with args and returns the results of
+// the expectation it matches. An unexpected call returns no results, which
+// Result turns into zero values, and fails Verify.
+func (m *Mock) Called(method string, args ...any) Results {
+	m.mu.Lock()
+	if m.calls == nil {
+		m.calls = map[string][][]any{}
+	}
+	m.calls[method] = append(m.calls[method], args)
+	var found *Call
+	for _, c := range m.expected {
+		if c.method == method && matches(c.args, args) && (c.times == 0 || c.calls < c.times) {
+			found = c
+			break
+		}
+	}
+	if found == nil {
+		m.errs = append(m.errs, "unexpected call "+describe(method, args))
+		m.mu.Unlock()
+		return Results{Method: method}
+	}
+	found.calls++
+	m.mu.Unlock()
+	if found.run != nil {
+		found.run(args)
+	}
+	return Results{Method: method, Values: found.returns}
+}
+
+// Calls returns the arguments of each call to method, in order, to check
+// what the code under test passed.
+func (m *Mock) Calls(method string) [][]any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([][]any(nil), m.calls[method]...)
+}
+
+// Verify fails t for each unexpected call and each expectation not met.
+func (m *Mock) Verify(t TB) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.errs {
+		t.Errorf("mock: %s", e)
+	}
+	for _, c := range m.expected {
+		switch {
+		case c.times == 0 && c.calls == 0:
+			t.Errorf("mock: expected call %s was not made", c)
+		case c.times > 0 && c.calls != c.times:
+			t.Errorf("mock: expected call %s made %d times, want %d", c, c.calls, c.times)
+		}
+	}
+}
+
+// Result returns result i as a T, or the zero T if there is no such
+// result or it is nil. It panics if the result is not a T, as when
+// Return(5) is given for an int64, rather than let a test pass with a
+// misconfigured mock.
+func Result[T any](r Results, i int) T {
+	var zero T
+	if i >= len(r.Values) || r.Values[i] == nil {
+		return zero
+	}
+	v, ok := r.Values[i].(T)
+	if !ok {
+		panic(fmt.Sprintf("mock: %s result %d is %T, not %v", r.Method, i, r.Values[i], reflect.TypeFor[T]()))
+	}
+	return v
+}
+
+func matches(want, got []any) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i, w := range want {
+		if m, ok := w.(Matcher); ok {
+			if !m.match(got[i]) {
+				return false
+			}
+		} else if !reflect.DeepEqual(w, got[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func describe(method string, args []any) string {
+	s := make([]string, len(args))
+	for i, a := range args {
+		if _, ok := a.(Matcher); ok {
+			s[i] = "<matcher>"
+		} else {
+			s[i] = fmt.Sprintf("%#v", a)
+		}
+	}
+	return method + "(" + strings.Join(s, ", ") + ")"
+}
@@ -0,0 +1,137 @@
+package mock
+
+import "fmt"
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+// recorder is a TB that keeps its errors.
+type recorder struct{ errs []string }
+
+func (r *recorder) Helper() {}
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errs = append(r.errs, fmt.Sprintf(format, args...))
+}
+
+type store struct{ Mock }
+
+func (s *store) Get(key string) (string, error) {
+	ret := s.Called("Get", key)
+	return Result[string](ret, 0), Result[error](ret, 1)
+}
+
+func TestMock(t *testing.T) {
+	s := new(store)
+	s.On("Get", "a").Return("1", nil).Times(2)
+	s.On("Get", Any).Return("", os.ErrNotExist)
+	var seen []any
+	s.On("Put").Run(func(args []any) { seen = args })
+
+	for i, k := range []string{"a", "a", "a", "b"} {
+		v, err := s.Get(k)
+		if i < 2 {
+			if v != "1" || err != nil {
+				t.Errorf("call %d: Get(%q) = %q, %v", i, k, v, err)
+			}
+		} else if err != os.ErrNotExist {
+			t.Errorf("call %d: Get(%q) = %q, %v; want ErrNotExist", i, k, v, err)
+		}
+	}
+	if got := len(s.Calls("Get")); got != 4 {
+		t.Errorf("recorded %d calls", got)
+	}
+	s.Called("Delete", "x")
+
+	var r recorder
+	s.Verify(&r)
+	want := []string{
+		`mock: unexpected call Delete("x")`,
+		"mock: expected call Put() was not made",
+	}
+	if strings.Join(r.errs, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Verify reported\n%s\nwant\n%s", strings.Join(r.errs, "\n"), strings.Join(want, "\n"))
+	}
+	if seen != nil {
+		t.Error("Run called without a call")
+	}
+}
+
+func TestResultType(t *testing.T) {
+	r := Results{Method: "Count", Values: []any{5, nil}}
+	if Result[error](r, 1) != nil || Result[int](r, 2) != 0 {
+		t.Error("nil or missing result is not zero")
+	}
+	defer func() {
+		if got := recover(); got != "mock: Count result 0 is int, not int64" {
+			t.Errorf("panic %v", got)
+		}
+	}()
+	Result[int64](r, 0)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	src := `package store
+
+import "context"
+import t "time"
+
+import "example.com/other"
+
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Expire(string, t.Duration)
+	Watch(keys ...string) <-chan other.Event
+	Put(m string, ret int, mock bool, other other.Event, a1 string, _ int) (ret2 int, err error)
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "store.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Generate(dir, "Store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `// Code generated by golib mockgen; DO NOT EDIT.
+
+package store
+
+import "context"
+import t "time"
+
+import "example.com/other"
+import "github.com/lukehedger/golib/mock"
+
+// MockStore is a mock Store.
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Get(ctx context.Context, key string) ([]byte, error) {
+	ret := m.Called("Get", ctx, key)
+	return mock.Result[[]byte](ret, 0), mock.Result[error](ret, 1)
+}
+
+func (m *MockStore) Expire(a0 string, a1 t.Duration) {
+	m.Called("Expire", a0, a1)
+}
+
+func (m *MockStore) Watch(keys ...string) <-chan other.Event {
+	ret := m.Called("Watch", keys)
+	return mock.Result[<-chan other.Event](ret, 0)
+}
+
+func (m *MockStore) Put(a0 string, a1_ int, a2 bool, a3 other.Event, a1 string, a5 int) (int, error) {
+	ret := m.Called("Put", a0, a1_, a2, a3, a1, a5)
+	return mock.Result[int](ret, 0), mock.Result[error](ret, 1)
+}
+`
+	if string(got) != want {
+		t.Errorf("Generate =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := Generate(dir, "Missing"); err == nil {
+		t.Error("no error for a missing interface")
+	}
+}
@@ -0,0 +1,229 @@
+// Package mockserve serves canned HTTP responses from a list of routes,
+// typically loaded from a YAML or JSON file, so that frontends and tests
+// can run without a real backend. Response bodies are templates that can
+// echo the request and draw on the fake package for plausible data.
+package mockserve
+
+import "encoding/json"
+import "fmt"
+import "io"
+import "net/http"
+import "net/url"
+import "slices"
+import "strings"
+import "text/template"
+import "time"
+
+import "github.com/lukehedger/golib/fake"
+
+// Request is the data available to body templates.
+type Request struct {
+	Method string
+	Path   string
+	Params map[string]string // from the route's {name} segments
+	Query  url.Values
+	Header http.Header
+	Body   string
+}
+
+// Handler serves a list of routes.
+type Handler struct {
+	routes []compiled
+	fake   *fake.Faker
+}
+
+type compiled struct {
+	Route
+	segments []string
+	body     any // the body with each string replaced by a *template.Template
+	json     bool
+}
+
+// New returns a Handler for routes, whose fake data is generated from
+// seed. Requests are matched against the routes in order; the first
+// whose method and path both match is served. A request whose path
+// matches only routes for other methods gets 405 Method Not Allowed, and
+// one matching no route gets 404 Not Found.
+func New(routes []Route, seed uint64) (*Handler, error) {
+	h := &Handler{fake: fake.New(seed)}
+	for i, rt := range routes {
+		c := compiled{Route: rt, segments: strings.Split(rt.Path, "/")}
+		for j, s := range c.segments {
+			if strings.HasSuffix(s, "...}") && j != len(c.segments)-1 {
+				return nil, fmt.Errorf("mockserve: route %d: %s must be the last segment", i+1, s)
+			}
+		}
+		var err error
+		_, isString := rt.Body.(string)
+		c.json = rt.Body != nil && !isString
+		if c.body, err = h.compile(rt.Body); err != nil {
+			return nil, fmt.Errorf("mockserve: route %d: %w", i+1, err)
+		}
+		h.routes = append(h.routes, c)
+	}
+	return h, nil
+}
+
+// compile replaces each string within v with a parsed template.
+func (h *Handler) compile(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return template.New("").Funcs(h.fake.Funcs()).Option("missingkey=zero").Parse(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, x := range v {
+			c, err := h.compile(x)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = c
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, x := range v {
+			c, err := h.compile(x)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = c
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// render executes the templates within v.
+func render(v any, req *Request) (any, error) {
+	switch v := v.(type) {
+	case *template.Template:
+		var b strings.Builder
+		err := v.Execute(&b, req)
+		return b.String(), err
+	case []any:
+		out := make([]any, len(v))
+		for i, x := range v {
+			r, err := render(x, req)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, x := range v {
+			r, err := render(x, req)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// match reports whether path matches the route's segments, returning the
+// parameters.
+func (c *compiled) match(path string) (map[string]string, bool) {
+	parts := strings.Split(path, "/")
+	params := map[string]string{}
+	for i, seg := range c.segments {
+		if i >= len(parts) {
+			return nil, false
+		}
+		if name, ok := strings.CutPrefix(seg, "{"); ok && strings.HasSuffix(name, "...}") {
+			params[strings.TrimSuffix(name, "...}")] = strings.Join(parts[i:], "/")
+			return params, true
+		}
+		if name, ok := strings.CutPrefix(seg, "{"); ok && strings.HasSuffix(name, "}") {
+			if parts[i] == "" {
+				return nil, false
+			}
+			params[strings.TrimSuffix(name, "}")] = parts[i]
+			continue
+		}
+		if seg != parts[i] {
+			return nil, false
+		}
+	}
+	return params, len(parts) == len(c.segments)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var allow []string
+	for i := range h.routes {
+		c := &h.routes[i]
+		params, ok := c.match(r.URL.Path)
+		if !ok {
+			continue
+		}
+		if c.Method != "" && c.Method != r.Method {
+			allow = append(allow, c.Method)
+			continue
+		}
+		h.serve(w, r, c, params)
+		return
+	}
+	if len(allow) > 0 {
+		slices.Sort(allow)
+		w.Header().Set("Allow", strings.Join(slices.Compact(allow), ", "))
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, c *compiled, params map[string]string) {
+	body, _ := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	req := &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Params: params,
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Body:   string(body),
+	}
+	out, err := render(c.body, req)
+	if err != nil {
+		http.Error(w, "mockserve: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var data []byte
+	if c.json {
+		if data, err = json.Marshal(out); err != nil {
+			http.Error(w, "mockserve: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+	} else if s, ok := out.(string); ok {
+		data = []byte(s)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	for k, v := range c.Headers {
+		w.Header().Set(k, v)
+	}
+
+	delay := c.Latency
+	if c.Jitter > 0 {
+		delay += time.Duration(h.fake.Float(0, float64(c.Jitter)))
+	}
+	if delay > 0 {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	status := c.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(data)
+}
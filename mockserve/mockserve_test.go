@@ -0,0 +1,131 @@
+package mockserve
+
+import "context"
+import "encoding/json"
+import "io"
+import "net/http"
+import "net/http/httptest"
+import "reflect"
+import "regexp"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/convert"
+
+func newServer(t *testing.T, routes []Route) *httptest.Server {
+	t.Helper()
+	h, err := New(routes, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, method, url, body string) (*http.Response, string) {
+	t.Helper()
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return resp, string(b)
+}
+
+func TestHandler(t *testing.T) {
+	routes, err := Load(strings.NewReader(routesYAML), convert.YAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(t, routes)
+
+	start := time.Now()
+	resp, body := do(t, "GET", srv.URL+"/users/42?tag=x", "")
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Errorf("response took %v, want at least the 20ms latency", d)
+	}
+	if resp.StatusCode != 200 || resp.Header.Get("Content-Type") != "application/json" || resp.Header.Get("X-Mock") != "yes" {
+		t.Errorf("GET /users/42: %d %v", resp.StatusCode, resp.Header)
+	}
+	var got map[string]any
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"id": "42", "tags": []any{"a", "x"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GET /users/42 body == %v, want %v", got, want)
+	}
+
+	if resp, body := do(t, "POST", srv.URL+"/echo", "hi"); resp.StatusCode != 200 || body != "you sent hi" ||
+		resp.Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("POST /echo == %d %q", resp.StatusCode, body)
+	}
+	if resp, body := do(t, "DELETE", srv.URL+"/health", ""); resp.StatusCode != 204 || body != "" {
+		t.Errorf("DELETE /health == %d %q", resp.StatusCode, body)
+	}
+	if resp, _ := do(t, "GET", srv.URL+"/echo", ""); resp.StatusCode != 405 || resp.Header.Get("Allow") != "POST" {
+		t.Errorf("GET /echo == %d, Allow %q", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+	for _, path := range []string{"/users", "/users/", "/users/1/2", "/nope"} {
+		if resp, _ := do(t, "GET", srv.URL+path, ""); resp.StatusCode != 404 {
+			t.Errorf("GET %s == %d, want 404", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestHandlerFake(t *testing.T) {
+	srv := newServer(t, []Route{
+		{Path: "/files/{rest...}", Body: "{{.Params.rest}}"},
+		{Path: "/person", Body: map[string]any{"name": "{{name}}", "age": 30, "email": "{{email}}"}},
+	})
+	if _, body := do(t, "GET", srv.URL+"/files/a/b.txt", ""); body != "a/b.txt" {
+		t.Errorf("GET /files/a/b.txt == %q", body)
+	}
+	if _, body := do(t, "GET", srv.URL+"/files/", ""); body != "" {
+		t.Errorf("GET /files/ == %q", body)
+	}
+	if resp, _ := do(t, "GET", srv.URL+"/files", ""); resp.StatusCode != 404 {
+		t.Errorf("GET /files == %d, want 404", resp.StatusCode)
+	}
+
+	_, body := do(t, "GET", srv.URL+"/person", "")
+	var p struct {
+		Name  string
+		Age   int
+		Email string
+	}
+	if err := json.Unmarshal([]byte(body), &p); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^\w+ \S+$`).MatchString(p.Name) || p.Age != 30 || !strings.Contains(p.Email, "@example.") {
+		t.Errorf("GET /person == %s", body)
+	}
+	if _, again := do(t, "GET", srv.URL+"/person", ""); again == body {
+		t.Error("fake data did not vary between requests")
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	if _, err := New([]Route{{Path: "/a/{rest...}/b"}}, 0); err == nil {
+		t.Error("New accepted {rest...} before the last segment")
+	}
+	if _, err := New([]Route{{Path: "/a", Body: "{{"}}, 0); err == nil {
+		t.Error("New accepted a bad template")
+	}
+
+	// A client that gives up stops the latency wait.
+	h, _ := New([]Route{{Path: "/slow", Latency: time.Hour}}, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, "GET", "/slow", nil)
+	done := make(chan struct{})
+	go func() { h.ServeHTTP(httptest.NewRecorder(), req); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Error("ServeHTTP ignored the cancelled request")
+	}
+}
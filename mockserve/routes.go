@@ -0,0 +1,141 @@
+package mockserve
+
+import "fmt"
+import "io"
+import "os"
+import "strings"
+import "time"
+
+import "github.com/lukehedger/golib/convert"
+
+// A Route is a canned response.
+type Route struct {
+	// Method is the request method to match, or empty for any.
+	Method string
+	// Path is the request path to match. A segment "{name}" matches any
+	// one segment, and a final "{name...}" matches the rest of the path;
+	// either is available to templates as .Params.name.
+	Path string
+	// Status is the response status; zero means 200.
+	Status int
+	// Latency delays the response, plus a random extra of up to Jitter.
+	Latency, Jitter time.Duration
+	// Headers are set on the response.
+	Headers map[string]string
+	// Body is the response body. A string is executed as a text/template
+	// and sent as text/plain unless Headers sets a Content-Type. Any other
+	// value is sent as JSON, after executing each string within it as a
+	// template. Templates see a Request as dot and can call the fake
+	// package generators, as in {{name}} or {{int 1 100}}.
+	Body any
+}
+
+// Load reads routes from a file in format f, which holds one record per
+// route with the keys method, path, status, latency, jitter, headers and
+// body. Durations are strings such as "150ms". In YAML, headers and
+// structured bodies are written as JSON-style flow collections:
+//
+//	---
+//	- method: GET
+//	  path: /users/{id}
+//	  latency: 50ms
+//	  body: {"id": "{{.Params.id}}", "name": "{{name}}"}
+func Load(r io.Reader, f convert.Format) ([]Route, error) {
+	rd, err := convert.NewReader(r, f, convert.Options{Infer: true})
+	if err != nil {
+		return nil, err
+	}
+	var routes []Route
+	for {
+		rec, err := rd.Read()
+		if err == io.EOF {
+			return routes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rt, err := route(rec)
+		if err != nil {
+			return nil, fmt.Errorf("mockserve: route %d: %w", len(routes)+1, err)
+		}
+		routes = append(routes, rt)
+	}
+}
+
+// LoadFile reads routes from the named file, choosing the format from its
+// extension.
+func LoadFile(name string) ([]Route, error) {
+	f, err := convert.FormatFromFilename(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file, f)
+}
+
+func route(rec convert.Record) (Route, error) {
+	var rt Route
+	for _, f := range rec {
+		var err error
+		switch f.Key {
+		case "method":
+			s, ok := f.Value.(string)
+			if !ok {
+				return rt, fmt.Errorf("method must be a string")
+			}
+			rt.Method = strings.ToUpper(s)
+		case "path":
+			s, ok := f.Value.(string)
+			if !ok || !strings.HasPrefix(s, "/") {
+				return rt, fmt.Errorf("path must begin with /")
+			}
+			rt.Path = s
+		case "status":
+			n, ok := f.Value.(int64)
+			if !ok || n < 100 || n > 999 {
+				return rt, fmt.Errorf("bad status %v", f.Value)
+			}
+			rt.Status = int(n)
+		case "latency":
+			rt.Latency, err = duration(f.Value)
+		case "jitter":
+			rt.Jitter, err = duration(f.Value)
+		case "headers":
+			m, ok := f.Value.(map[string]any)
+			if !ok {
+				return rt, fmt.Errorf("headers must be a mapping")
+			}
+			rt.Headers = map[string]string{}
+			for k, v := range m {
+				rt.Headers[k] = fmt.Sprint(v)
+			}
+		case "body":
+			rt.Body = f.Value
+		default:
+			return rt, fmt.Errorf("unknown key %q", f.Key)
+		}
+		if err != nil {
+			return rt, err
+		}
+	}
+	if rt.Path == "" {
+		return rt, fmt.Errorf("no path")
+	}
+	return rt, nil
+}
+
+func duration(v any) (time.Duration, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("durations must be strings such as \"100ms\", got %v", v)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("bad duration %q", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,67 @@
+package mockserve
+
+import "reflect"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/convert"
+
+const routesYAML = `# A mock user service.
+- method: get
+  path: /users/{id}
+  latency: 20ms
+  jitter: 5ms
+  headers: {"X-Mock": "yes", "X-Count": 2}
+  body: {"id": "{{.Params.id}}", "tags": ["a", "{{.Query.Get \"tag\"}}"]}
+- path: /health
+  status: 204
+- method: POST
+  path: /echo
+  body: "you sent {{.Body}}"
+`
+
+func TestLoad(t *testing.T) {
+	routes, err := Load(strings.NewReader(routesYAML), convert.YAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Route{
+		{
+			Method:  "GET",
+			Path:    "/users/{id}",
+			Latency: 20 * time.Millisecond,
+			Jitter:  5 * time.Millisecond,
+			Headers: map[string]string{"X-Mock": "yes", "X-Count": "2"},
+			Body:    map[string]any{"id": "{{.Params.id}}", "tags": []any{"a", `{{.Query.Get "tag"}}`}},
+		},
+		{Path: "/health", Status: 204},
+		{Method: "POST", Path: "/echo", Body: "you sent {{.Body}}"},
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("Load() ==\n%#v\nwant\n%#v", routes, want)
+	}
+
+	json := `[{"path": "/a", "status": 201, "body": {"ok": true}}]`
+	routes, err = Load(strings.NewReader(json), convert.JSON)
+	if err != nil || len(routes) != 1 || routes[0].Status != 201 || !reflect.DeepEqual(routes[0].Body, map[string]any{"ok": true}) {
+		t.Errorf("Load(JSON) == %#v, %v", routes, err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	cases := []string{
+		"- method: GET\n",
+		"- path: users\n",
+		"- path: /a\n  status: 42\n",
+		"- path: /a\n  latency: 100\n",
+		"- path: /a\n  latency: soon\n",
+		"- path: /a\n  headers: nope\n",
+		"- path: /a\n  bodyy: typo\n",
+	}
+	for _, c := range cases {
+		if _, err := Load(strings.NewReader(c), convert.YAML); err == nil {
+			t.Errorf("Load(%q) succeeded", c)
+		}
+	}
+}
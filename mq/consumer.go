@@ -0,0 +1,195 @@
+package mq
+
+import "context"
+import "errors"
+import "slices"
+import "time"
+
+// ErrNotPending is returned by Delivery.Ack and Delivery.Nack when the
+// delivery has already been acked, nacked or timed out.
+var ErrNotPending = errors.New("mq: delivery is no longer pending")
+
+// group is a consumer group's state in one topic.
+type group struct {
+	next     int64               // next offset never yet delivered
+	attempts map[int64]int       // deliveries of each unacked offset
+	inflight map[int64]time.Time // ack deadline of each delivered offset
+	retry    []int64             // offsets awaiting redelivery, in order
+	skipped  int64
+	dead     int64
+}
+
+// A Consumer receives messages on behalf of a consumer group. Consumers
+// in the same group share its messages, each receiving a different one.
+type Consumer struct {
+	b     *Broker
+	topic string
+	group string
+}
+
+// Subscribe returns a consumer in group for topic, creating either if
+// needed. A new group starts at the oldest retained message.
+func (b *Broker) Subscribe(topic, group string) *Consumer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.group(b.topic(topic), group)
+	return &Consumer{b: b, topic: topic, group: group}
+}
+
+// group returns the named group of t, creating it if needed. b.mu must be
+// held.
+func (b *Broker) group(t *topic, name string) *group {
+	g, ok := t.groups[name]
+	if !ok {
+		g = &group{next: t.first, attempts: map[int64]int{}, inflight: map[int64]time.Time{}}
+		t.groups[name] = g
+	}
+	return g
+}
+
+// A Delivery is a message handed to a consumer, which must Ack it once
+// processed or Nack it to have it redelivered.
+type Delivery struct {
+	Message
+	// Attempt counts deliveries of this message to the group, from 1.
+	Attempt int
+
+	c *Consumer
+}
+
+// Receive waits for the next message for the consumer's group: a message
+// due for redelivery if there is one, otherwise the next new message.
+func (c *Consumer) Receive(ctx context.Context) (*Delivery, error) {
+	b := c.b
+	for {
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			return nil, ErrClosed
+		}
+		t := b.topic(c.topic)
+		g := b.group(t, c.group)
+		d, ok := b.take(t, g)
+		wake := b.wake
+		var wait time.Duration = -1
+		for _, dl := range g.inflight {
+			if d := dl.Sub(b.now()); wait < 0 || d < wait {
+				wait = max(d, 0)
+			}
+		}
+		b.mu.Unlock()
+		if ok {
+			d.c = c
+			return d, nil
+		}
+
+		if err := sleep(ctx, wake, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits until wake is closed, d has passed (if d is not negative)
+// or ctx is done.
+func sleep(ctx context.Context, wake <-chan struct{}, d time.Duration) error {
+	var timeout <-chan time.Time
+	if d >= 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-wake:
+	case <-timeout:
+	}
+	return nil
+}
+
+// take hands out the group's next message, if any. b.mu must be held.
+func (b *Broker) take(t *topic, g *group) (*Delivery, bool) {
+	now := b.now()
+	var expired []int64
+	for off, dl := range g.inflight {
+		if !now.Before(dl) {
+			expired = append(expired, off)
+		}
+	}
+	slices.Sort(expired)
+	for _, off := range expired {
+		delete(g.inflight, off)
+		g.retry = append(g.retry, off)
+	}
+
+	for {
+		var off int64
+		switch {
+		case len(g.retry) > 0:
+			off, g.retry = g.retry[0], g.retry[1:]
+		case g.next < t.end():
+			if g.next < t.first {
+				g.skipped += t.first - g.next
+				g.next = t.first
+			}
+			off = g.next
+			g.next++
+		default:
+			return nil, false
+		}
+
+		m, ok := t.get(off)
+		if !ok {
+			// Dropped by retention while awaiting redelivery.
+			delete(g.attempts, off)
+			g.skipped++
+			continue
+		}
+		if b.opts.MaxAttempts > 0 && g.attempts[off] >= b.opts.MaxAttempts {
+			delete(g.attempts, off)
+			g.dead++
+			if b.opts.DeadLetter != "" && b.opts.DeadLetter != t.name {
+				b.publish(b.opts.DeadLetter, m.Value)
+				b.signal()
+			}
+			continue
+		}
+		g.attempts[off]++
+		g.inflight[off] = now.Add(b.opts.AckTimeout)
+		return &Delivery{Message: m, Attempt: g.attempts[off]}, true
+	}
+}
+
+// Ack marks the message as processed, so that it is not delivered to the
+// group again. It returns ErrNotPending if the ack timeout has already
+// passed, in which case the message may be redelivered.
+func (d *Delivery) Ack() error {
+	return d.settle(true)
+}
+
+// Nack returns the message to the group for immediate redelivery.
+func (d *Delivery) Nack() error {
+	return d.settle(false)
+}
+
+func (d *Delivery) settle(ack bool) error {
+	b := d.c.b
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	g := b.group(b.topic(d.Topic), d.c.group)
+	dl, ok := g.inflight[d.Offset]
+	if !ok || g.attempts[d.Offset] != d.Attempt || !b.now().Before(dl) {
+		return ErrNotPending
+	}
+	delete(g.inflight, d.Offset)
+	if ack {
+		delete(g.attempts, d.Offset)
+		return nil
+	}
+	g.retry = append(g.retry, d.Offset)
+	b.signal()
+	return nil
+}
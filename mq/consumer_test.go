@@ -0,0 +1,151 @@
+package mq
+
+import "context"
+import "sync"
+import "testing"
+import "time"
+
+func TestGroups(t *testing.T) {
+	b := New(Options{})
+	for _, v := range []string{"a", "b"} {
+		b.Publish("t", []byte(v))
+	}
+	// Each group sees every message.
+	for _, name := range []string{"g1", "g2"} {
+		c := b.Subscribe("t", name)
+		for _, want := range []string{"a", "b"} {
+			d := receive(t, c)
+			if string(d.Value) != want {
+				t.Errorf("%s received %q, want %q", name, d.Value, want)
+			}
+			d.Ack()
+		}
+	}
+}
+
+func TestConsumersShareGroup(t *testing.T) {
+	b := New(Options{})
+	const n = 100
+	for range n {
+		b.Publish("t", []byte("x"))
+	}
+	var (
+		mu   sync.Mutex
+		seen = map[int64]int{}
+		wg   sync.WaitGroup
+	)
+	for range 4 {
+		c := b.Subscribe("t", "g")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				d, err := c.Receive(ctx)
+				cancel()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				seen[d.Offset]++
+				mu.Unlock()
+				d.Ack()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != n {
+		t.Errorf("received %d distinct messages, want %d", len(seen), n)
+	}
+	for off, k := range seen {
+		if k != 1 {
+			t.Errorf("offset %d delivered %d times", off, k)
+		}
+	}
+}
+
+func TestNack(t *testing.T) {
+	b := New(Options{})
+	b.Publish("t", []byte("a"))
+	b.Publish("t", []byte("b"))
+	c := b.Subscribe("t", "g")
+
+	d := receive(t, c)
+	if err := d.Nack(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Ack(); err != ErrNotPending {
+		t.Errorf("Ack after Nack: err == %v, want ErrNotPending", err)
+	}
+	// The nacked message comes back before new ones.
+	d = receive(t, c)
+	if string(d.Value) != "a" || d.Attempt != 2 {
+		t.Errorf("redelivery == %q attempt %d, want \"a\" attempt 2", d.Value, d.Attempt)
+	}
+	d.Ack()
+	if d := receive(t, c); string(d.Value) != "b" {
+		t.Errorf("next message == %q, want \"b\"", d.Value)
+	}
+}
+
+func TestAckTimeout(t *testing.T) {
+	b := New(Options{AckTimeout: 20 * time.Millisecond})
+	c := b.Subscribe("t", "g")
+	b.Publish("t", []byte("a"))
+
+	first := receive(t, c)
+	// The receive below blocks until the first delivery times out.
+	second := receive(t, c)
+	if second.Offset != first.Offset || second.Attempt != 2 {
+		t.Errorf("redelivery == offset %d attempt %d, want %d attempt 2", second.Offset, second.Attempt, first.Offset)
+	}
+	if err := first.Ack(); err != ErrNotPending {
+		t.Errorf("late Ack: err == %v, want ErrNotPending", err)
+	}
+	if err := second.Ack(); err != nil {
+		t.Errorf("Ack of redelivery: %v", err)
+	}
+}
+
+func TestMaxAttempts(t *testing.T) {
+	b := New(Options{MaxAttempts: 2, DeadLetter: "dead"})
+	dead := b.Subscribe("dead", "g")
+	c := b.Subscribe("t", "g")
+	b.Publish("t", []byte("poison"))
+	b.Publish("t", []byte("ok"))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		d := receive(t, c)
+		if string(d.Value) != "poison" || d.Attempt != attempt {
+			t.Fatalf("received %q attempt %d, want poison attempt %d", d.Value, d.Attempt, attempt)
+		}
+		d.Nack()
+	}
+	if d := receive(t, c); string(d.Value) != "ok" {
+		t.Errorf("after dead-lettering received %q, want \"ok\"", d.Value)
+	}
+	if d := receive(t, dead); string(d.Value) != "poison" {
+		t.Errorf("dead letter == %q, want \"poison\"", d.Value)
+	}
+	if s := b.Stats("t", "g"); s.DeadLettered != 1 {
+		t.Errorf("DeadLettered == %d, want 1", s.DeadLettered)
+	}
+}
+
+func TestReceiveContext(t *testing.T) {
+	b := New(Options{})
+	c := b.Subscribe("t", "g")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.Receive(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Receive on empty topic: err == %v, want DeadlineExceeded", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		b.Publish("t", []byte("late"))
+	}()
+	if d := receive(t, c); string(d.Value) != "late" {
+		t.Errorf("received %q, want \"late\"", d.Value)
+	}
+}
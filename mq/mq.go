@@ -0,0 +1,201 @@
+// Package mq is an in-memory message queue with named topics and
+// consumer groups.
+//
+// Each topic is an append-only log of messages identified by offset. A
+// consumer group reads a topic at its own pace: every message is handed
+// to one consumer in the group, and redelivered if that consumer nacks
+// it or does not ack it within the ack timeout, so delivery is at least
+// once. Topics keep a bounded number of messages; a group that falls
+// further behind than that skips the messages it missed.
+package mq
+
+import "errors"
+import "sync"
+import "time"
+
+// Defaults for the zero fields of Options.
+const (
+	DefaultRetention  = 10000
+	DefaultAckTimeout = 30 * time.Second
+)
+
+// ErrClosed is returned by operations on a closed Broker.
+var ErrClosed = errors.New("mq: broker closed")
+
+// Options configure a Broker.
+type Options struct {
+	// Retention is the number of messages each topic keeps. Zero means
+	// DefaultRetention.
+	Retention int
+	// AckTimeout is how long a consumer has to ack a message before it
+	// is redelivered. Zero means DefaultAckTimeout.
+	AckTimeout time.Duration
+	// MaxAttempts limits how many times a message is delivered to a
+	// group. A message that has used them all is moved to DeadLetter,
+	// if set, or dropped. Zero means no limit.
+	MaxAttempts int
+	// DeadLetter names the topic that receives messages which exhausted
+	// MaxAttempts.
+	DeadLetter string
+}
+
+// A Message is an entry in a topic.
+type Message struct {
+	Topic  string
+	Offset int64
+	Value  []byte
+	Time   time.Time
+}
+
+// Broker holds topics and their consumer groups. It is safe for
+// concurrent use.
+type Broker struct {
+	opts Options
+	now  func() time.Time
+
+	mu     sync.Mutex
+	topics map[string]*topic
+	wake   chan struct{} // closed and replaced when consumers may make progress
+	closed bool
+}
+
+type topic struct {
+	name   string
+	first  int64     // offset of msgs[head]
+	msgs   []Message // msgs[head:] are retained
+	head   int
+	groups map[string]*group
+}
+
+// New returns a Broker configured by opts.
+func New(opts Options) *Broker {
+	if opts.Retention <= 0 {
+		opts.Retention = DefaultRetention
+	}
+	if opts.AckTimeout <= 0 {
+		opts.AckTimeout = DefaultAckTimeout
+	}
+	return &Broker{
+		opts:   opts,
+		now:    time.Now,
+		topics: map[string]*topic{},
+		wake:   make(chan struct{}),
+	}
+}
+
+// Publish appends value to topic, creating the topic if needed, and
+// returns its offset. The broker keeps its own copy of value.
+func (b *Broker) Publish(topic string, value []byte) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, ErrClosed
+	}
+	off := b.publish(topic, append([]byte(nil), value...))
+	b.signal()
+	return off, nil
+}
+
+// publish appends value to the named topic. b.mu must be held.
+func (b *Broker) publish(name string, value []byte) int64 {
+	t := b.topic(name)
+	off := t.end()
+	t.msgs = append(t.msgs, Message{Topic: name, Offset: off, Value: value, Time: b.now()})
+	if n := len(t.msgs) - t.head; n > b.opts.Retention {
+		drop := n - b.opts.Retention
+		clear(t.msgs[t.head : t.head+drop])
+		t.head += drop
+		t.first += int64(drop)
+		// Compact once the dropped prefix is as long as what remains.
+		if t.head >= len(t.msgs)-t.head {
+			t.msgs = append(t.msgs[:0], t.msgs[t.head:]...)
+			t.head = 0
+		}
+	}
+	return off
+}
+
+// topic returns the named topic, creating it if needed. b.mu must be
+// held.
+func (b *Broker) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{name: name, groups: map[string]*group{}}
+		b.topics[name] = t
+	}
+	return t
+}
+
+func (t *topic) end() int64 { return t.first + int64(len(t.msgs)-t.head) }
+
+// get returns the message at off, if it is still retained.
+func (t *topic) get(off int64) (Message, bool) {
+	if off < t.first || off >= t.end() {
+		return Message{}, false
+	}
+	return t.msgs[t.head+int(off-t.first)], true
+}
+
+// signal wakes consumers waiting for messages. b.mu must be held.
+func (b *Broker) signal() {
+	close(b.wake)
+	b.wake = make(chan struct{})
+}
+
+// Close closes the broker. Pending and future Receive calls return
+// ErrClosed.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		b.signal()
+	}
+}
+
+// Topics returns the names of the topics, in no particular order.
+func (b *Broker) Topics() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	names := make([]string, 0, len(b.topics))
+	for name := range b.topics {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Stats describes a consumer group's position in its topic.
+type Stats struct {
+	// Lag is the number of retained messages the group has not yet
+	// received.
+	Lag int64
+	// Pending is the number of messages delivered but not yet acked,
+	// including those waiting to be redelivered.
+	Pending int
+	// Skipped counts messages dropped by retention before the group
+	// received them, or before it acked them.
+	Skipped int64
+	// DeadLettered counts messages that exhausted MaxAttempts.
+	DeadLettered int64
+}
+
+// Stats returns the position of group in topic. A group that has never
+// subscribed has all retained messages as lag.
+func (b *Broker) Stats(topic, group string) Stats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.topics[topic]
+	if !ok {
+		return Stats{}
+	}
+	g, ok := t.groups[group]
+	if !ok {
+		return Stats{Lag: t.end() - t.first}
+	}
+	return Stats{
+		Lag:          t.end() - max(g.next, t.first),
+		Pending:      len(g.attempts),
+		Skipped:      g.skipped,
+		DeadLettered: g.dead,
+	}
+}
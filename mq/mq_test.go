@@ -0,0 +1,89 @@
+package mq
+
+import "context"
+import "testing"
+import "time"
+
+func receive(t *testing.T, c *Consumer) *Delivery {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	d, err := c.Receive(ctx)
+	if err != nil {
+		t.Fatalf("Receive: %v", err)
+	}
+	return d
+}
+
+func TestPublish(t *testing.T) {
+	b := New(Options{})
+	for i, v := range []string{"a", "b", "c"} {
+		off, err := b.Publish("t", []byte(v))
+		if err != nil || off != int64(i) {
+			t.Errorf("Publish(%q) == %d, %v, want %d", v, off, err, i)
+		}
+	}
+	buf := []byte("x")
+	b.Publish("t", buf)
+	buf[0] = 'y' // the broker must hold its own copy
+
+	c := b.Subscribe("t", "g")
+	for _, want := range []string{"a", "b", "c", "x"} {
+		d := receive(t, c)
+		if string(d.Value) != want || d.Topic != "t" || d.Attempt != 1 {
+			t.Errorf("received %+v, want %q on attempt 1", d, want)
+		}
+		d.Ack()
+	}
+	if s := b.Stats("t", "g"); s != (Stats{}) {
+		t.Errorf("Stats after acking everything == %+v", s)
+	}
+
+	b.Close()
+	if _, err := b.Publish("t", nil); err != ErrClosed {
+		t.Errorf("Publish after Close: err == %v, want ErrClosed", err)
+	}
+	if _, err := c.Receive(context.Background()); err != ErrClosed {
+		t.Errorf("Receive after Close: err == %v, want ErrClosed", err)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	b := New(Options{Retention: 3})
+	c := b.Subscribe("t", "g")
+	for _, v := range []string{"a", "b", "c", "d", "e"} {
+		b.Publish("t", []byte(v))
+	}
+	if s := b.Stats("t", "g"); s.Lag != 3 {
+		t.Errorf("Lag == %d, want 3", s.Lag)
+	}
+	if d := receive(t, c); string(d.Value) != "c" || d.Offset != 2 {
+		t.Errorf("first retained message == %q at %d, want \"c\" at 2", d.Value, d.Offset)
+	}
+	if s := b.Stats("t", "g"); s.Skipped != 2 {
+		t.Errorf("Skipped == %d, want 2", s.Skipped)
+	}
+
+	// Retention holds over many publishes.
+	for range 100 {
+		b.Publish("t", []byte("z"))
+	}
+	tp := b.topics["t"]
+	if n := len(tp.msgs) - tp.head; n != 3 || len(tp.msgs) > 6 {
+		t.Errorf("topic holds %d messages in a slice of %d, want 3 in at most 6", n, len(tp.msgs))
+	}
+}
+
+func TestStatsUnknown(t *testing.T) {
+	b := New(Options{})
+	if s := b.Stats("nope", "g"); s != (Stats{}) {
+		t.Errorf("Stats of unknown topic == %+v", s)
+	}
+	b.Publish("t", nil)
+	if s := b.Stats("t", "new"); s.Lag != 1 {
+		t.Errorf("Stats of unsubscribed group == %+v, want lag 1", s)
+	}
+	if got := b.Topics(); len(got) != 1 || got[0] != "t" {
+		t.Errorf("Topics() == %q", got)
+	}
+}
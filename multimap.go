@@ -0,0 +1,115 @@
+package golib
+
+import "iter"
+import "slices"
+
+// MultiMap maps each key to a list of values, replacing hand-managed
+// map[K][]V. Keys are kept in the order they were first added, so
+// iteration is deterministic. A key with no values is removed. The zero
+// value is not usable; call NewMultiMap. A MultiMap is not safe for
+// concurrent use.
+type MultiMap[K, V comparable] struct {
+	entries map[K]*multiEntry[K, V]
+	keys    List[K]
+}
+
+type multiEntry[K, V comparable] struct {
+	values []V
+	elem   *Element[K]
+}
+
+// NewMultiMap returns an empty MultiMap.
+func NewMultiMap[K, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{entries: map[K]*multiEntry[K, V]{}}
+}
+
+// Len returns the number of keys in m.
+func (m *MultiMap[K, V]) Len() int { return len(m.entries) }
+
+// Count returns the number of values in m, over all keys.
+func (m *MultiMap[K, V]) Count() int {
+	n := 0
+	for _, e := range m.entries {
+		n += len(e.values)
+	}
+	return n
+}
+
+// Add appends values to the list for k.
+func (m *MultiMap[K, V]) Add(k K, values ...V) {
+	if len(values) == 0 {
+		return
+	}
+	e, ok := m.entries[k]
+	if !ok {
+		e = &multiEntry[K, V]{elem: m.keys.PushBack(k)}
+		m.entries[k] = e
+	}
+	e.values = append(e.values, values...)
+}
+
+// Get returns a copy of the values for k, in the order they were added,
+// or nil if there are none.
+func (m *MultiMap[K, V]) Get(k K) []V {
+	if e, ok := m.entries[k]; ok {
+		return slices.Clone(e.values)
+	}
+	return nil
+}
+
+// Has reports whether k has any values.
+func (m *MultiMap[K, V]) Has(k K) bool {
+	_, ok := m.entries[k]
+	return ok
+}
+
+// Contains reports whether v is among the values for k.
+func (m *MultiMap[K, V]) Contains(k K, v V) bool {
+	e, ok := m.entries[k]
+	return ok && slices.Contains(e.values, v)
+}
+
+// Remove removes k and returns its values.
+func (m *MultiMap[K, V]) Remove(k K) []V {
+	e, ok := m.entries[k]
+	if !ok {
+		return nil
+	}
+	m.keys.Remove(e.elem)
+	delete(m.entries, k)
+	return e.values
+}
+
+// RemoveValue removes every occurrence of v from the values for k,
+// removing k if none are left, and returns how many it removed.
+func (m *MultiMap[K, V]) RemoveValue(k K, v V) int {
+	e, ok := m.entries[k]
+	if !ok {
+		return 0
+	}
+	n := len(e.values)
+	e.values = slices.DeleteFunc(e.values, func(x V) bool { return x == v })
+	removed := n - len(e.values)
+	if len(e.values) == 0 {
+		m.Remove(k)
+	}
+	return removed
+}
+
+// Keys yields the keys of m in the order they were first added.
+func (m *MultiMap[K, V]) Keys() iter.Seq[K] { return m.keys.All() }
+
+// All yields each key with each of its values, keys in the order they
+// were first added and values in the order they were added. m must not
+// be changed during the iteration.
+func (m *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.keys.Front(); e != nil; e = e.Next() {
+			for _, v := range m.entries[e.Value].values {
+				if !yield(e.Value, v) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package golib
+
+import "slices"
+import "testing"
+
+func TestMultiMap(t *testing.T) {
+	m := NewMultiMap[string, int]()
+	m.Add("b", 1, 2)
+	m.Add("a", 3)
+	m.Add("b", 2)
+	m.Add("c")
+	if m.Len() != 2 || m.Count() != 4 || m.Has("c") {
+		t.Fatalf("Len, Count == %d, %d, want 2, 4 with no key c", m.Len(), m.Count())
+	}
+	if got := m.Get("b"); !slices.Equal(got, []int{1, 2, 2}) {
+		t.Errorf("Get(b) == %v", got)
+	}
+	if m.Get("z") != nil || !m.Contains("a", 3) || m.Contains("a", 4) {
+		t.Error("Get or Contains wrong")
+	}
+
+	var got []string
+	for k, v := range m.All() {
+		got = append(got, k+string(rune('0'+v)))
+	}
+	if want := []string{"b1", "b2", "b2", "a3"}; !slices.Equal(got, want) {
+		t.Errorf("All() == %q, want %q", got, want)
+	}
+
+	if n := m.RemoveValue("b", 2); n != 2 {
+		t.Errorf("RemoveValue(b, 2) == %d, want 2", n)
+	}
+	if n := m.RemoveValue("a", 3); n != 1 || m.Has("a") {
+		t.Errorf("RemoveValue(a, 3) == %d, key kept %v", n, m.Has("a"))
+	}
+	m.Add("a", 4)
+	if keys := slices.Collect(m.Keys()); !slices.Equal(keys, []string{"b", "a"}) {
+		t.Errorf("Keys() == %q, want [b a]", keys)
+	}
+	if vs := m.Remove("b"); !slices.Equal(vs, []int{1}) || m.Has("b") || m.Remove("b") != nil {
+		t.Errorf("Remove(b) == %v", vs)
+	}
+}
@@ -0,0 +1,57 @@
+package golib
+
+import "fmt"
+import "runtime/debug"
+
+// Must returns v, panicking if err is not nil. It suits initialization
+// that cannot fail in a correct program:
+//
+//	var tmpl = Must(template.New("page").Parse(page))
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Must2 is Must for functions that return two values and an error.
+func Must2[A, B any](a A, b B, err error) (A, B) {
+	if err != nil {
+		panic(err)
+	}
+	return a, b
+}
+
+// A PanicError is a recovered panic turned into an error.
+type PanicError struct {
+	Value any
+	Stack []byte // of the goroutine that panicked
+}
+
+func (e *PanicError) Error() string { return fmt.Sprintf("panic: %v", e.Value) }
+
+// Unwrap returns the panic value if it is an error, so that errors.Is
+// sees through a Must that panicked.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Try calls fn and returns its error, or a *PanicError if it panics.
+func Try(fn func() error) (err error) {
+	defer Recover(&err)
+	return fn()
+}
+
+// Recover, deferred, turns a panic into a *PanicError stored in *err, at a
+// boundary such as an exported function whose internals use Must:
+//
+//	func Load(path string) (cfg Config, err error) {
+//		defer golib.Recover(&err)
+//		...
+//	}
+func Recover(err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{Value: v, Stack: debug.Stack()}
+	}
+}
@@ -0,0 +1,36 @@
+package golib
+
+import "errors"
+import "strconv"
+import "testing"
+
+func TestMust(t *testing.T) {
+	if got := Must(strconv.Atoi("12")); got != 12 {
+		t.Errorf("Must == %d", got)
+	}
+	split := func(s string) (string, string, error) { return s[:1], s[1:], nil }
+	if a, b := Must2(split("xy")); a != "x" || b != "y" {
+		t.Errorf("Must2 == %q, %q", a, b)
+	}
+	err := Try(func() error {
+		Must(strconv.Atoi("x"))
+		return nil
+	})
+	var pe *PanicError
+	if !errors.As(err, &pe) || !errors.Is(err, strconv.ErrSyntax) || len(pe.Stack) == 0 {
+		t.Errorf("Try == %v", err)
+	}
+}
+
+func TestTry(t *testing.T) {
+	boom := errors.New("boom")
+	if err := Try(func() error { return boom }); err != boom {
+		t.Errorf("Try == %v, want the returned error", err)
+	}
+	if err := Try(func() error { return nil }); err != nil {
+		t.Errorf("Try == %v", err)
+	}
+	if err := Try(func() error { panic("oops") }); err == nil || err.Error() != "panic: oops" {
+		t.Errorf("Try == %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package golib
+
+import "cmp"
+import "slices"
+import "strings"
+import "unicode"
+import "unicode/utf8"
+
+// NaturalCompare compares a and b in natural order, treating each run of
+// ASCII digits as a number, so that "file2.txt" sorts before
+// "file10.txt". It returns -1, 0 or +1. Numbers equal in value but
+// written with different leading zeros order the shorter form first;
+// strings that are otherwise equal fall back to byte order, so the
+// result is 0 only when a == b.
+func NaturalCompare(a, b string) int {
+	return naturalCompare(a, b, false)
+}
+
+// NaturalCompareFold is like NaturalCompare but ignores case, using byte
+// order only to break ties.
+func NaturalCompareFold(a, b string) int {
+	return naturalCompare(a, b, true)
+}
+
+// NaturalLess reports whether a sorts before b in natural order.
+func NaturalLess(a, b string) bool { return NaturalCompare(a, b) < 0 }
+
+// SortNatural sorts s in natural order.
+func SortNatural(s []string) { slices.SortFunc(s, NaturalCompare) }
+
+// SortNaturalFold sorts s in case-insensitive natural order.
+func SortNaturalFold(s []string) { slices.SortFunc(s, NaturalCompareFold) }
+
+func naturalCompare(a, b string, fold bool) int {
+	zeros := 0 // the first difference in leading zeros, as a tie-breaker
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			na, nb := digitRun(a[i:]), digitRun(b[j:])
+			ta, tb := strings.TrimLeft(na, "0"), strings.TrimLeft(nb, "0")
+			if c := cmp.Compare(len(ta), len(tb)); c != 0 {
+				return c
+			}
+			if c := strings.Compare(ta, tb); c != 0 {
+				return c
+			}
+			if zeros == 0 {
+				zeros = cmp.Compare(len(na), len(nb))
+			}
+			i, j = i+len(na), j+len(nb)
+			continue
+		}
+		ra, wa := utf8.DecodeRuneInString(a[i:])
+		rb, wb := utf8.DecodeRuneInString(b[j:])
+		if fold {
+			ra, rb = unicode.ToLower(ra), unicode.ToLower(rb)
+		}
+		if c := cmp.Compare(ra, rb); c != 0 {
+			return c
+		}
+		i, j = i+wa, j+wb
+	}
+	if c := cmp.Compare(len(a)-i, len(b)-j); c != 0 {
+		return c
+	}
+	if zeros != 0 {
+		return zeros
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+// digitRun returns the run of digits at the start of s.
+func digitRun(s string) string {
+	n := 0
+	for n < len(s) && isDigit(s[n]) {
+		n++
+	}
+	return s[:n]
+}
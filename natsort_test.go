@@ -0,0 +1,50 @@
+package golib
+
+import "slices"
+import "testing"
+
+func TestNaturalCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"file2.txt", "file10.txt", -1},
+		{"file10.txt", "file2.txt", 1},
+		{"a1b2", "a1b10", -1},
+		{"10", "9", 1},
+		{"x", "x", 0},
+		{"", "a", -1},
+		{"a", "a1", -1},
+		{"007", "7", 1},     // equal value: fewer leading zeros first
+		{"a07b", "a7c", -1}, // but later text decides first
+		{"99999999999999999999999", "100000000000000000000000", -1}, // beyond int64
+		{"img12", "IMG2", 1},
+		{"é2", "é10", -1},
+	}
+	for _, c := range cases {
+		if got := NaturalCompare(c.a, c.b); got != c.want {
+			t.Errorf("NaturalCompare(%q, %q) == %d, want %d", c.a, c.b, got, c.want)
+		}
+		if got := NaturalCompare(c.b, c.a); got != -c.want {
+			t.Errorf("NaturalCompare(%q, %q) == %d, want %d", c.b, c.a, got, -c.want)
+		}
+	}
+	if NaturalCompareFold("IMG2", "img10") != -1 || NaturalCompareFold("abc", "ABC") == 0 {
+		t.Error("NaturalCompareFold does not ignore case with a byte-order tie-break")
+	}
+	if !NaturalLess("v1.9", "v1.10") {
+		t.Error("NaturalLess(v1.9, v1.10) == false")
+	}
+}
+
+func TestSortNatural(t *testing.T) {
+	s := []string{"file10", "File3", "file2", "file1", "file02"}
+	SortNatural(s)
+	if want := []string{"File3", "file1", "file2", "file02", "file10"}; !slices.Equal(s, want) {
+		t.Errorf("SortNatural() == %q, want %q", s, want)
+	}
+	SortNaturalFold(s)
+	if want := []string{"file1", "file2", "file02", "File3", "file10"}; !slices.Equal(s, want) {
+		t.Errorf("SortNaturalFold() == %q, want %q", s, want)
+	}
+}
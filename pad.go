@@ -0,0 +1,100 @@
+package golib
+
+import "strings"
+import "unicode"
+
+// wideRanges lists the East Asian Wide and Fullwidth blocks, plus emoji,
+// that terminals draw two columns wide.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC},
+	{0x2E80, 0x303E}, {0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF}, {0xA960, 0xA97F}, {0xAC00, 0xD7A3}, {0xF900, 0xFAFF},
+	{0xFE10, 0xFE19}, {0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F}, {0x1F900, 0x1F9FF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// RuneWidth returns the number of terminal columns r occupies: 0 for
+// control characters and combining marks, 2 for wide East Asian characters
+// and emoji, and 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || unicode.IsControl(r):
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r < 0x1100:
+		return 1
+	}
+	for _, w := range wideRanges {
+		if r < w.lo {
+			break
+		}
+		if r <= w.hi {
+			return 2
+		}
+	}
+	return 1
+}
+
+// StringWidth returns the number of terminal columns s occupies.
+func StringWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// padding returns runs of pad filling n columns. If pad is wider than
+// one column and does not divide n, the remainder is filled with spaces.
+func padding(n int, pad rune) string {
+	if n <= 0 {
+		return ""
+	}
+	w := max(RuneWidth(pad), 1)
+	return strings.Repeat(string(pad), n/w) + strings.Repeat(" ", n%w)
+}
+
+// PadLeft right-aligns s in a field width columns wide by prepending pad.
+// Width is measured in terminal columns, so wide characters count twice.
+// Strings already at least width columns wide are returned unchanged.
+func PadLeft(s string, width int, pad rune) string {
+	return padding(width-StringWidth(s), pad) + s
+}
+
+// PadRight left-aligns s in a field width columns wide by appending pad.
+func PadRight(s string, width int, pad rune) string {
+	return s + padding(width-StringWidth(s), pad)
+}
+
+// Center centres s in a field width columns wide. When the padding cannot
+// be split evenly the extra column goes on the right.
+func Center(s string, width int, pad rune) string {
+	gap := width - StringWidth(s)
+	if gap <= 0 {
+		return s
+	}
+	return padding(gap/2, pad) + s + padding(gap-gap/2, pad)
+}
+
+// TruncateWidth is like Truncate but measures s and suffix in terminal
+// columns, so that the result is at most width columns wide. A wide
+// character that would straddle the limit is dropped, leaving the result
+// one column short.
+func TruncateWidth(s string, width int, suffix string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	keep := width - StringWidth(suffix)
+	if keep < 0 {
+		keep, suffix = max(width, 0), ""
+	}
+	n := 0
+	for i, r := range s {
+		if n+RuneWidth(r) > keep {
+			return s[:i] + suffix
+		}
+		n += RuneWidth(r)
+	}
+	return s + suffix
+}
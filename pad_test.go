@@ -0,0 +1,75 @@
+package golib
+
+import "testing"
+
+func TestStringWidth(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"世界", 4},
+		{"é", 1}, // e + combining acute accent
+		{"한국어", 6},
+		{"ｆｕｌｌ", 8},
+		{"🎻", 2},
+	}
+	for _, c := range cases {
+		if got := StringWidth(c.in); got != c.want {
+			t.Errorf("StringWidth(%q) == %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	cases := []struct {
+		fn    func(string, int, rune) string
+		name  string
+		in    string
+		width int
+		pad   rune
+		want  string
+	}{
+		{PadLeft, "PadLeft", "42", 5, '0', "00042"},
+		{PadLeft, "PadLeft", "世界", 6, ' ', "  世界"},
+		{PadLeft, "PadLeft", "toolong", 3, ' ', "toolong"},
+		{PadRight, "PadRight", "ab", 4, '.', "ab.."},
+		{PadRight, "PadRight", "世", 5, '　', "世　 "},
+		{Center, "Center", "ab", 7, '*', "**ab***"},
+		{Center, "Center", "世界", 8, '-', "--世界--"},
+	}
+	for _, c := range cases {
+		if got := c.fn(c.in, c.width, c.pad); got != c.want {
+			t.Errorf("%s(%q, %d, %q) == %q, want %q", c.name, c.in, c.width, c.pad, got, c.want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	cases := []struct {
+		in     string
+		width  int
+		suffix string
+		want   string
+	}{
+		{"hello", 10, "…", "hello"},
+		{"hello world", 8, "…", "hello w…"},
+		{"世界世界", 8, "…", "世界世界"},
+		{"世界世界", 5, "…", "世界…"},
+		{"世界世界", 4, "…", "世…"},
+		{"世界", 3, "...", "..."},
+		{"世界", 1, "...", ""},
+		{"日本語", 2, "", "日"},
+		{"ab", 0, "…", ""},
+	}
+	for _, c := range cases {
+		got := TruncateWidth(c.in, c.width, c.suffix)
+		if got != c.want {
+			t.Errorf("TruncateWidth(%q, %d, %q) == %q, want %q", c.in, c.width, c.suffix, got, c.want)
+		}
+		if StringWidth(got) > c.width && c.width >= 0 {
+			t.Errorf("TruncateWidth(%q, %d, %q) is %d columns wide", c.in, c.width, c.suffix, StringWidth(got))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package golib
+
+import "encoding/json"
+import "fmt"
+
+// Pair holds two values of possibly different types. It marshals to JSON
+// as a two-element array.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// NewPair returns a Pair of a and b.
+func NewPair[A, B any](a A, b B) Pair[A, B] {
+	return Pair[A, B]{a, b}
+}
+
+// Values returns the elements of p, for use as multiple return values.
+func (p Pair[A, B]) Values() (A, B) { return p.First, p.Second }
+
+// Swap returns p with its elements exchanged.
+func (p Pair[A, B]) Swap() Pair[B, A] { return Pair[B, A]{p.Second, p.First} }
+
+// String formats p as "(first, second)".
+func (p Pair[A, B]) String() string { return fmt.Sprintf("(%v, %v)", p.First, p.Second) }
+
+// MarshalJSON encodes p as [first, second].
+func (p Pair[A, B]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{p.First, p.Second})
+}
+
+// UnmarshalJSON decodes a two-element array into p.
+func (p *Pair[A, B]) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 2 {
+		return fmt.Errorf("golib: pair: want 2 elements, got %d", len(raw))
+	}
+	var q Pair[A, B]
+	for i, dst := range []any{&q.First, &q.Second} {
+		if err := json.Unmarshal(raw[i], dst); err != nil {
+			return err
+		}
+	}
+	*p = q
+	return nil
+}
+
+// Triple holds three values of possibly different types. It marshals to
+// JSON as a three-element array.
+type Triple[A, B, C any] struct {
+	First  A
+	Second B
+	Third  C
+}
+
+// NewTriple returns a Triple of a, b and c.
+func NewTriple[A, B, C any](a A, b B, c C) Triple[A, B, C] {
+	return Triple[A, B, C]{a, b, c}
+}
+
+// Values returns the elements of t, for use as multiple return values.
+func (t Triple[A, B, C]) Values() (A, B, C) { return t.First, t.Second, t.Third }
+
+// String formats t as "(first, second, third)".
+func (t Triple[A, B, C]) String() string {
+	return fmt.Sprintf("(%v, %v, %v)", t.First, t.Second, t.Third)
+}
+
+// MarshalJSON encodes t as [first, second, third].
+func (t Triple[A, B, C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{t.First, t.Second, t.Third})
+}
+
+// UnmarshalJSON decodes a three-element array into t.
+func (t *Triple[A, B, C]) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("golib: triple: want 3 elements, got %d", len(raw))
+	}
+	var u Triple[A, B, C]
+	for i, dst := range []any{&u.First, &u.Second, &u.Third} {
+		if err := json.Unmarshal(raw[i], dst); err != nil {
+			return err
+		}
+	}
+	*t = u
+	return nil
+}
@@ -0,0 +1,53 @@
+package golib
+
+import "encoding/json"
+import "testing"
+
+func TestPair(t *testing.T) {
+	p := NewPair("answer", 42)
+	if s, n := p.Values(); s != "answer" || n != 42 {
+		t.Errorf("Values() == %q, %d", s, n)
+	}
+	if q := p.Swap(); q != NewPair(42, "answer") {
+		t.Errorf("Swap() == %v", q)
+	}
+	if got := p.String(); got != "(answer, 42)" {
+		t.Errorf("String() == %q", got)
+	}
+
+	b, err := json.Marshal([]Pair[string, int]{p})
+	if err != nil || string(b) != `[["answer",42]]` {
+		t.Errorf("Marshal() == %s, %v", b, err)
+	}
+	var back []Pair[string, int]
+	if err := json.Unmarshal(b, &back); err != nil || len(back) != 1 || back[0] != p {
+		t.Errorf("Unmarshal() == %v, %v", back, err)
+	}
+	for _, bad := range []string{`["a"]`, `["a", 1, 2]`, `[1, 1]`, `{"First": "a"}`} {
+		var q Pair[string, int]
+		if err := json.Unmarshal([]byte(bad), &q); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", bad)
+		}
+	}
+}
+
+func TestTriple(t *testing.T) {
+	tr := NewTriple(1, "b", true)
+	if a, b, c := tr.Values(); a != 1 || b != "b" || !c {
+		t.Errorf("Values() == %v, %v, %v", a, b, c)
+	}
+	if got := tr.String(); got != "(1, b, true)" {
+		t.Errorf("String() == %q", got)
+	}
+	b, err := json.Marshal(tr)
+	if err != nil || string(b) != `[1,"b",true]` {
+		t.Errorf("Marshal() == %s, %v", b, err)
+	}
+	var back Triple[int, string, bool]
+	if err := json.Unmarshal(b, &back); err != nil || back != tr {
+		t.Errorf("Unmarshal() == %v, %v", back, err)
+	}
+	if err := json.Unmarshal([]byte(`[1, "b"]`), &back); err == nil {
+		t.Error("Unmarshal of two elements succeeded")
+	}
+}
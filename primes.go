@@ -0,0 +1,103 @@
+package golib
+
+import "math/bits"
+
+// IsPrime reports whether n is prime. It uses the Miller-Rabin test with a
+// fixed set of witnesses that is known to be deterministic for every
+// 64-bit integer, so the result is exact.
+func IsPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range millerRabinBases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	// Write n-1 as d * 2^s with d odd.
+	d := n - 1
+	s := bits.TrailingZeros64(d)
+	d >>= s
+
+	for _, a := range millerRabinBases {
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		composite := true
+		for range s - 1 {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+		if composite {
+			return false
+		}
+	}
+	return true
+}
+
+// The first twelve primes are sufficient witnesses for all n < 2^64.
+var millerRabinBases = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// mulMod returns a*b mod m without overflowing.
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	return bits.Rem64(hi, lo, m)
+}
+
+// powMod returns a^e mod m.
+func powMod(a, e, m uint64) uint64 {
+	r := uint64(1)
+	a %= m
+	for e > 0 {
+		if e&1 == 1 {
+			r = mulMod(r, a, m)
+		}
+		a = mulMod(a, a, m)
+		e >>= 1
+	}
+	return r
+}
+
+// NextPrime returns the smallest prime greater than n, or 0 if there is
+// none that fits in a uint64.
+func NextPrime(n uint64) uint64 {
+	if n < 2 {
+		return 2
+	}
+	// Step through odd candidates.
+	c := n + 1
+	if c%2 == 0 {
+		c++
+	}
+	for ; c > n; c += 2 { // c wraps below n on overflow
+		if IsPrime(c) {
+			return c
+		}
+	}
+	return 0
+}
+
+// SieveOfEratosthenes returns the primes less than or equal to n, in
+// increasing order.
+func SieveOfEratosthenes(n int) []int {
+	if n < 2 {
+		return nil
+	}
+	composite := make([]bool, n+1)
+	var primes []int
+	for i := 2; i <= n; i++ {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, i)
+		for j := i * i; j <= n; j += i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
@@ -0,0 +1,58 @@
+package golib
+
+import "slices"
+import "testing"
+
+func TestIsPrime(t *testing.T) {
+	primes := SieveOfEratosthenes(10000)
+	for n := uint64(0); n <= 10000; n++ {
+		_, want := slices.BinarySearch(primes, int(n))
+		if got := IsPrime(n); got != want {
+			t.Errorf("IsPrime(%d) == %v, want %v", n, got, want)
+		}
+	}
+
+	cases := []struct {
+		n    uint64
+		want bool
+	}{
+		{2147483647, true},            // 2^31-1
+		{2305843009213693951, true},   // 2^61-1
+		{18446744073709551557, true},  // largest 64-bit prime
+		{3215031751, false},           // strong pseudoprime to bases 2, 3, 5, 7
+		{3825123056546413051, false},  // strong pseudoprime to the first nine prime bases
+		{18446744073709551615, false}, // 2^64-1
+		{1000000007 * 998244353, false},
+	}
+	for _, c := range cases {
+		if got := IsPrime(c.n); got != c.want {
+			t.Errorf("IsPrime(%d) == %v, want %v", c.n, got, c.want)
+		}
+	}
+}
+
+func TestNextPrime(t *testing.T) {
+	cases := []struct{ n, want uint64 }{
+		{0, 2},
+		{2, 3},
+		{3, 5},
+		{13, 17},
+		{1000000000, 1000000007},
+		{18446744073709551556, 18446744073709551557},
+		{18446744073709551557, 0},
+	}
+	for _, c := range cases {
+		if got := NextPrime(c.n); got != c.want {
+			t.Errorf("NextPrime(%d) == %d, want %d", c.n, got, c.want)
+		}
+	}
+}
+
+func TestSieveOfEratosthenes(t *testing.T) {
+	if got := SieveOfEratosthenes(30); !slices.Equal(got, []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}) {
+		t.Errorf("SieveOfEratosthenes(30) == %v", got)
+	}
+	if got := SieveOfEratosthenes(1); got != nil {
+		t.Errorf("SieveOfEratosthenes(1) == %v", got)
+	}
+}
@@ -0,0 +1,284 @@
+package qr
+
+// symbol is a Code under construction, tracking which modules belong to
+// function patterns and so must not be masked.
+type symbol struct {
+	size     int
+	dark     []bool
+	function []bool
+}
+
+func (s *symbol) set(x, y int, dark bool) {
+	s.dark[y*s.size+x] = dark
+	s.function[y*s.size+x] = true
+}
+
+// newSymbol lays out codewords in a symbol of version, trying every mask
+// and keeping the one with the lowest penalty.
+func newSymbol(version int, level Level, codewords []byte) *Code {
+	size := 17 + 4*version
+	s := &symbol{size: size, dark: make([]bool, size*size), function: make([]bool, size*size)}
+	s.drawFunctionPatterns(version)
+	s.drawCodewords(codewords)
+
+	best, bestPenalty := 0, -1
+	for mask := range 8 {
+		t := s.clone()
+		t.applyMask(mask)
+		t.drawFormat(level, mask)
+		if p := t.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+	}
+	s.applyMask(best)
+	s.drawFormat(level, best)
+	return &Code{Version: version, Level: level, Size: size, Mask: best, modules: s.dark}
+}
+
+func (s *symbol) clone() *symbol {
+	return &symbol{
+		size:     s.size,
+		dark:     append([]bool(nil), s.dark...),
+		function: s.function,
+	}
+}
+
+func (s *symbol) drawFunctionPatterns(version int) {
+	// Timing patterns.
+	for i := range s.size {
+		s.set(6, i, i%2 == 0)
+		s.set(i, 6, i%2 == 0)
+	}
+
+	// Finder patterns and their separators, in three corners.
+	for _, c := range [][2]int{{3, 3}, {s.size - 4, 3}, {3, s.size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x < 0 || y < 0 || x >= s.size || y >= s.size {
+					continue
+				}
+				d := max(abs(dx), abs(dy))
+				s.set(x, y, d != 2 && d != 4)
+			}
+		}
+	}
+
+	// Alignment patterns, except where they would overlap a finder.
+	pos := alignmentPositions(version, s.size)
+	last := len(pos) - 1
+	for i, x := range pos {
+		for j, y := range pos {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					s.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; drawFormat fills them in.
+	s.drawFormat(0, 0)
+
+	// Version information, for version 7 and up.
+	if version >= 7 {
+		bits := versionBits(version)
+		for i := range 18 {
+			dark := bits>>i&1 == 1
+			a, b := s.size-11+i%3, i/3
+			s.set(a, b, dark)
+			s.set(b, a, dark)
+		}
+	}
+}
+
+// alignmentPositions returns the row and column centres of the alignment
+// patterns for version.
+func alignmentPositions(version, size int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, size-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// versionBits returns the 18-bit BCH-coded version information.
+func versionBits(version int) int {
+	rem := version
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// formatBits returns the 15-bit BCH-coded format information.
+func formatBits(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormat writes both copies of the format information, plus the dark
+// module that always sits beside the lower-left finder.
+func (s *symbol) drawFormat(level Level, mask int) {
+	bits := formatBits(level, mask)
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		s.set(8, i, bit(i))
+	}
+	s.set(8, 7, bit(6))
+	s.set(8, 8, bit(7))
+	s.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		s.set(14-i, 8, bit(i))
+	}
+
+	for i := range 8 {
+		s.set(s.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.set(8, s.size-15+i, bit(i))
+	}
+	s.set(8, s.size-8, true)
+}
+
+// drawCodewords places codewords in the two-module-wide zigzag that runs
+// up and down the symbol from the bottom-right corner, skipping function
+// modules. Remainder modules are left light.
+func (s *symbol) drawCodewords(codewords []byte) {
+	i := 0
+	for right := s.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := range s.size {
+			y := vert
+			if upward {
+				y = s.size - 1 - vert
+			}
+			for j := range 2 {
+				x := right - j
+				if s.function[y*s.size+x] || i >= len(codewords)*8 {
+					continue
+				}
+				s.dark[y*s.size+x] = codewords[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+var masks = [8]func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+func (s *symbol) applyMask(mask int) {
+	f := masks[mask]
+	for y := range s.size {
+		for x := range s.size {
+			if i := y*s.size + x; !s.function[i] && f(x, y) {
+				s.dark[i] = !s.dark[i]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol by the four rules of ISO/IEC 18004 section
+// 7.8.3; lower is easier to scan.
+func (s *symbol) penalty() int {
+	n := s.size
+	at := func(x, y int) bool { return s.dark[y*n+x] }
+	p := 0
+
+	// Rule 1: runs of five or more same-coloured modules, and rule 3:
+	// finder-like 1:1:3:1:1 patterns with four light modules on one side,
+	// both scanned along rows and then columns.
+	finderA := []bool{true, false, true, true, true, false, true, false, false, false, false}
+	finderB := []bool{false, false, false, false, true, false, true, true, true, false, true}
+	for pass := range 2 {
+		get := at
+		if pass == 1 {
+			get = func(x, y int) bool { return at(y, x) }
+		}
+		for y := range n {
+			run := 1
+			for x := 1; x < n; x++ {
+				if get(x, y) == get(x-1, y) {
+					run++
+					if run == 5 {
+						p += 3
+					} else if run > 5 {
+						p++
+					}
+				} else {
+					run = 1
+				}
+			}
+			for x := -4; x+len(finderA) <= n+4; x++ {
+				a, b := true, true
+				for k := range finderA {
+					xx := x + k
+					v := xx >= 0 && xx < n && get(xx, y) // outside is light
+					a = a && v == finderA[k]
+					b = b && v == finderB[k]
+				}
+				if a {
+					p += 40
+				}
+				if b {
+					p += 40
+				}
+			}
+		}
+	}
+
+	// Rule 2: 2x2 blocks of one colour.
+	for y := range n - 1 {
+		for x := range n - 1 {
+			c := at(x, y)
+			if c == at(x+1, y) && c == at(x, y+1) && c == at(x+1, y+1) {
+				p += 3
+			}
+		}
+	}
+
+	// Rule 4: deviation of the dark proportion from 50%, 10 points per 5%.
+	dark := 0
+	for _, d := range s.dark {
+		if d {
+			dark++
+		}
+	}
+	total := n * n
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	p += k * 10
+	return p
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,218 @@
+// Package qr encodes text as QR codes (ISO/IEC 18004) and renders them as
+// PNG images or Unicode block art for terminals.
+//
+// The encoder picks the smallest version (1 to 40) that holds the text at
+// the requested error-correction level, using numeric, alphanumeric or
+// byte mode for the whole text, and chooses the mask with the lowest
+// penalty score as the standard prescribes.
+package qr
+
+import "errors"
+import "fmt"
+import "strings"
+
+// Level is an error-correction level: the fraction of the symbol that can
+// be damaged and still decoded.
+type Level int
+
+const (
+	Low      Level = iota // recovers about 7% of codewords
+	Medium                // about 15%
+	Quartile              // about 25%
+	High                  // about 30%
+)
+
+func (l Level) String() string {
+	switch l {
+	case Low:
+		return "L"
+	case Medium:
+		return "M"
+	case Quartile:
+		return "Q"
+	case High:
+		return "H"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// ParseLevel parses "L", "M", "Q" or "H", in either case.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToUpper(s) {
+	case "L":
+		return Low, nil
+	case "M":
+		return Medium, nil
+	case "Q":
+		return Quartile, nil
+	case "H":
+		return High, nil
+	}
+	return 0, fmt.Errorf("qr: unknown error-correction level %q", s)
+}
+
+// formatBits are the two bits identifying l in the format information.
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// ErrTooLong is returned by Encode when the text does not fit in a
+// version 40 symbol at the requested level.
+var ErrTooLong = errors.New("qr: text too long")
+
+// Code is an encoded QR symbol.
+type Code struct {
+	Version int   // 1 to 40
+	Level   Level // error-correction level
+	Size    int   // modules per side: 17 + 4*Version
+	Mask    int   // data mask pattern, 0 to 7
+
+	modules []bool // Size*Size, row-major; true is dark
+}
+
+// Dark reports whether the module at column x, row y is dark. Coordinates
+// outside the symbol, including the quiet zone, are light.
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+	return c.modules[y*c.Size+x]
+}
+
+// Encode encodes text at error-correction level.
+func Encode(text string, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, fmt.Errorf("qr: invalid level %d", int(level))
+	}
+	data := []byte(text)
+	m := modeFor(data)
+
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if bitsNeeded(m, v, len(data)) <= 8*dataCodewords(v, level) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := encodeData(m, data, version, level)
+	return newSymbol(version, level, interleave(codewords, version, level)), nil
+}
+
+// A mode is a way of packing characters into bits.
+type mode int
+
+const (
+	numeric mode = iota
+	alphanumeric
+	byteMode
+)
+
+const alnumChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// modeFor returns the most compact mode able to encode all of data.
+func modeFor(data []byte) mode {
+	m := numeric
+	for _, b := range data {
+		switch {
+		case b >= '0' && b <= '9':
+		case strings.IndexByte(alnumChars, b) >= 0:
+			m = max(m, alphanumeric)
+		default:
+			return byteMode
+		}
+	}
+	return m
+}
+
+func (m mode) indicator() uint32 {
+	return [...]uint32{0b0001, 0b0010, 0b0100}[m]
+}
+
+// countBits returns the width of the character count field.
+func (m mode) countBits(version int) int {
+	i := 0
+	switch {
+	case version >= 27:
+		i = 2
+	case version >= 10:
+		i = 1
+	}
+	return [...][3]int{{10, 12, 14}, {9, 11, 13}, {8, 16, 16}}[m][i]
+}
+
+func bitsNeeded(m mode, version, n int) int {
+	bits := 4 + m.countBits(version)
+	switch m {
+	case numeric:
+		bits += n/3*10 + [...]int{0, 4, 7}[n%3]
+	case alphanumeric:
+		bits += n/2*11 + n%2*6
+	default:
+		bits += 8 * n
+	}
+	if n >= 1<<m.countBits(version) {
+		return 1 << 30 // the count does not fit
+	}
+	return bits
+}
+
+type bitBuffer struct {
+	bytes []byte
+	n     int // bits used
+}
+
+func (b *bitBuffer) append(v uint32, width int) {
+	for i := width - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if v>>i&1 == 1 {
+			b.bytes[b.n/8] |= 0x80 >> (b.n % 8)
+		}
+		b.n++
+	}
+}
+
+// encodeData returns the data codewords for version and level: the
+// segment followed by the terminator and padding.
+func encodeData(m mode, data []byte, version int, level Level) []byte {
+	var b bitBuffer
+	b.append(m.indicator(), 4)
+	b.append(uint32(len(data)), m.countBits(version))
+	switch m {
+	case numeric:
+		for i := 0; i < len(data); i += 3 {
+			group := data[i:min(i+3, len(data))]
+			var v uint32
+			for _, d := range group {
+				v = v*10 + uint32(d-'0')
+			}
+			b.append(v, len(group)*3+1)
+		}
+	case alphanumeric:
+		for i := 0; i < len(data); i += 2 {
+			v := uint32(strings.IndexByte(alnumChars, data[i]))
+			if i+1 < len(data) {
+				b.append(v*45+uint32(strings.IndexByte(alnumChars, data[i+1])), 11)
+			} else {
+				b.append(v, 6)
+			}
+		}
+	default:
+		for _, c := range data {
+			b.append(uint32(c), 8)
+		}
+	}
+
+	capacity := 8 * dataCodewords(version, level)
+	b.append(0, min(4, capacity-b.n))
+	b.append(0, (8-b.n%8)%8)
+	for pad := uint32(0xEC); b.n < capacity; pad ^= 0xEC ^ 0x11 {
+		b.append(pad, 8)
+	}
+	return b.bytes
+}
@@ -0,0 +1,141 @@
+package qr
+
+import "bytes"
+import "strings"
+import "testing"
+
+func TestEncodeData(t *testing.T) {
+	// "HELLO WORLD" at 1-M, from the worked example at thonky.com.
+	if m := modeFor([]byte("HELLO WORLD")); m != alphanumeric {
+		t.Fatalf("mode == %d", m)
+	}
+	got := encodeData(alphanumeric, []byte("HELLO WORLD"), 1, Medium)
+	want := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeData() == %v, want %v", got, want)
+	}
+
+	// "01234567" at 1-M, from ISO/IEC 18004 Annex I.
+	got = encodeData(numeric, []byte("01234567"), 1, Medium)
+	want = []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeData() == %x, want %x", got, want)
+	}
+}
+
+func TestFormatAndVersionBits(t *testing.T) {
+	if got := formatBits(Low, 4); got != 0b110011000101111 {
+		t.Errorf("formatBits(L, 4) == %015b", got)
+	}
+	if got := formatBits(Medium, 0); got != 0b101010000010010 {
+		t.Errorf("formatBits(M, 0) == %015b", got)
+	}
+	if got := versionBits(7); got != 0b000111110010010100 {
+		t.Errorf("versionBits(7) == %018b", got)
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	cases := []struct {
+		text    string
+		level   Level
+		version int
+	}{
+		{"12345", Low, 1},
+		{strings.Repeat("A", 25), Low, 1},
+		{strings.Repeat("A", 26), Low, 2},
+		{strings.Repeat("a", 17), Low, 1},
+		{strings.Repeat("a", 18), Low, 2},
+		{strings.Repeat("a", 7), High, 1},
+		{"otpauth://totp/Example:ann@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Example", Medium, 5},
+		{strings.Repeat("a", 2953), Low, 40},
+	}
+	for _, c := range cases {
+		code, err := Encode(c.text, c.level)
+		if err != nil {
+			t.Errorf("Encode(%.20q, %v): %v", c.text, c.level, err)
+			continue
+		}
+		if code.Version != c.version || code.Size != 17+4*c.version {
+			t.Errorf("Encode(%.20q, %v) is version %d, want %d", c.text, c.level, code.Version, c.version)
+		}
+	}
+	if _, err := Encode(strings.Repeat("a", 2954), Low); err != ErrTooLong {
+		t.Errorf("Encode(2954 bytes) == %v, want ErrTooLong", err)
+	}
+}
+
+// TestRoundTrip reads a symbol back the way a decoder would: format
+// information from both copies, then the unmasked codewords in zigzag
+// order, which must match the interleaved codewords that were encoded.
+func TestRoundTrip(t *testing.T) {
+	for _, text := range []string{"hello, world", "HTTPS://EXAMPLE.COM/", strings.Repeat("Go is fun! ", 40)} {
+		for l := Low; l <= High; l++ {
+			code, err := Encode(text, l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := code.Size
+			bit := func(x, y int, i int) int {
+				if code.Dark(x, y) {
+					return 1 << i
+				}
+				return 0
+			}
+			var f1, f2 int
+			for i := 0; i <= 5; i++ {
+				f1 |= bit(8, i, i)
+			}
+			f1 |= bit(8, 7, 6) | bit(8, 8, 7) | bit(7, 8, 8)
+			for i := 9; i < 15; i++ {
+				f1 |= bit(14-i, 8, i)
+			}
+			for i := range 8 {
+				f2 |= bit(n-1-i, 8, i)
+			}
+			for i := 8; i < 15; i++ {
+				f2 |= bit(8, n-15+i, i)
+			}
+			if want := formatBits(l, code.Mask); f1 != want || f2 != want {
+				t.Fatalf("%v: format bits %015b, %015b, want %015b", l, f1, f2, want)
+			}
+			if !code.Dark(8, n-8) {
+				t.Errorf("%v: dark module missing", l)
+			}
+
+			// Rebuild the function-module map and read the zigzag.
+			s := &symbol{size: n, dark: make([]bool, n*n), function: make([]bool, n*n)}
+			s.drawFunctionPatterns(code.Version)
+			for i := range s.dark {
+				s.dark[i] = code.modules[i]
+			}
+			s.applyMask(code.Mask)
+			var raw bitBuffer
+			for right := n - 1; right >= 1; right -= 2 {
+				if right == 6 {
+					right = 5
+				}
+				upward := (right+1)&2 == 0
+				for vert := range n {
+					y := vert
+					if upward {
+						y = n - 1 - vert
+					}
+					for j := range 2 {
+						if x := right - j; !s.function[y*n+x] {
+							v := uint32(0)
+							if s.dark[y*n+x] {
+								v = 1
+							}
+							raw.append(v, 1)
+						}
+					}
+				}
+			}
+			total := rawModules(code.Version) / 8
+			if got := interleave(encodeData(modeFor([]byte(text)), []byte(text), code.Version, l), code.Version, l); !bytes.Equal(raw.bytes[:total], got) {
+				t.Errorf("%v: codewords read back differ", l)
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package qr
+
+import "bytes"
+import "image"
+import "image/color"
+import "image/png"
+import "strings"
+
+// QuietZone is the width, in modules, of the light border that renderers
+// add around the symbol. Scanners need it to find the code.
+const QuietZone = 4
+
+// Image returns the symbol as a black-and-white image with scale pixels
+// per module, including the quiet zone.
+func (c *Code) Image(scale int) image.Image {
+	scale = max(scale, 1)
+	side := (c.Size + 2*QuietZone) * scale
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := range side {
+		for x := range side {
+			if c.Dark(x/scale-QuietZone, y/scale-QuietZone) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return img
+}
+
+// PNG returns the symbol as a PNG image with scale pixels per module.
+func (c *Code) PNG(scale int) ([]byte, error) {
+	var b bytes.Buffer
+	if err := png.Encode(&b, c.Image(scale)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// Text renders the symbol with Unicode half-block characters, two module
+// rows per line, including the quiet zone. Dark modules are drawn as ink,
+// which suits a terminal with dark text on a light background; invert
+// draws the light modules instead, for light text on a dark background.
+func (c *Code) Text(invert bool) string {
+	ink := func(x, y int) bool { return c.Dark(x, y) != invert }
+	var b strings.Builder
+	for y := -QuietZone; y < c.Size+QuietZone; y += 2 {
+		for x := -QuietZone; x < c.Size+QuietZone; x++ {
+			top, bottom := ink(x, y), ink(x, y+1)
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// String renders the symbol as Text(false).
+func (c *Code) String() string { return c.Text(false) }
@@ -0,0 +1,60 @@
+package qr
+
+import "bytes"
+import "image/png"
+import "strings"
+import "testing"
+import "unicode/utf8"
+
+func TestPNG(t *testing.T) {
+	code, err := Encode("hello", Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := code.PNG(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	side := (code.Size + 2*QuietZone) * 3
+	if r := img.Bounds(); r.Dx() != side || r.Dy() != side {
+		t.Fatalf("image is %v, want %dx%d", r, side, side)
+	}
+	// The top-left module of the finder pattern is dark; the quiet zone is light.
+	dark := func(x, y int) bool { r, _, _, _ := img.At(x, y).RGBA(); return r == 0 }
+	q := QuietZone * 3
+	if !dark(q, q) || !dark(q+2, q+2) || dark(q-1, q) {
+		t.Error("finder pattern or quiet zone misplaced")
+	}
+}
+
+func TestText(t *testing.T) {
+	code, err := Encode("hello", Low)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, invert := range []bool{false, true} {
+		lines := strings.Split(strings.TrimSuffix(code.Text(invert), "\n"), "\n")
+		width := code.Size + 2*QuietZone
+		if len(lines) != (width+1)/2 {
+			t.Errorf("invert=%v: %d lines, want %d", invert, len(lines), (width+1)/2)
+		}
+		for i, l := range lines {
+			if n := utf8.RuneCountInString(l); n != width {
+				t.Errorf("invert=%v: line %d has %d runes, want %d", invert, i, n, width)
+			}
+		}
+		// Line 2 holds module rows 0 and 1. In column 1 these are the top
+		// edge of the finder pattern and then its light ring.
+		want := '▀'
+		if invert {
+			want = '▄'
+		}
+		if r := []rune(lines[2])[QuietZone+1]; r != want {
+			t.Errorf("invert=%v: finder corner drawn as %q, want %q", invert, r, want)
+		}
+	}
+}
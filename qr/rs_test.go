@@ -0,0 +1,60 @@
+package qr
+
+import "bytes"
+import "testing"
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" at 1-M, from the worked example at thonky.com.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(10)); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder() == %v, want %v", got, want)
+	}
+}
+
+func TestCapacities(t *testing.T) {
+	for v := 1; v <= 40; v++ {
+		for l := Low; l <= High; l++ {
+			total := rawModules(v) / 8
+			if n := dataCodewords(v, l); n <= 0 || n >= total {
+				t.Errorf("version %d-%v: %d data codewords of %d", v, l, n, total)
+			}
+			// Blocks differ in length by at most one data codeword.
+			if eccBlocks[l][v] > total {
+				t.Errorf("version %d-%v: more blocks than codewords", v, l)
+			}
+		}
+	}
+	cases := []struct {
+		version int
+		level   Level
+		want    int
+	}{
+		{1, Low, 19}, {1, High, 9}, {5, Quartile, 62}, {10, Medium, 216}, {40, Low, 2956}, {40, High, 1276},
+	}
+	for _, c := range cases {
+		if got := dataCodewords(c.version, c.level); got != c.want {
+			t.Errorf("dataCodewords(%d, %v) == %d, want %d", c.version, c.level, got, c.want)
+		}
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	// 5-Q has two blocks of 15 data codewords and two of 16.
+	data := make([]byte, dataCodewords(5, Quartile))
+	for i := range data {
+		data[i] = byte(i)
+	}
+	out := interleave(data, 5, Quartile)
+	if len(out) != rawModules(5)/8 {
+		t.Fatalf("len == %d", len(out))
+	}
+	want := []byte{0, 15, 30, 46, 1, 16, 31, 47}
+	if !bytes.Equal(out[:8], want) {
+		t.Errorf("first codewords == %v, want %v", out[:8], want)
+	}
+	// After 15 rounds only the long blocks contribute a data codeword.
+	if got := out[60:62]; !bytes.Equal(got, []byte{45, 61}) {
+		t.Errorf("codewords 60-61 == %v, want [45 61]", got)
+	}
+}
@@ -0,0 +1,210 @@
+package golib
+
+import "crypto/rand"
+import "encoding/binary"
+import "errors"
+import "fmt"
+import "math"
+import "slices"
+
+import mathrand "math/rand/v2"
+
+// Character sets for RandString.
+const (
+	Digits       = "0123456789"
+	Lowercase    = "abcdefghijklmnopqrstuvwxyz"
+	Uppercase    = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Letters      = Lowercase + Uppercase
+	Alphanumeric = Digits + Letters
+)
+
+// cryptoSource is a math/rand source drawing from crypto/rand. Unlike
+// most sources it is safe for concurrent use.
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	rand.Read(b[:]) // never fails; see crypto/rand.Read
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// secure is shared by all goroutines: math/rand/v2.Rand keeps no state of
+// its own, so it is as concurrency-safe as its source.
+var secure = &Fast{r: mathrand.New(cryptoSource{})}
+
+// RandInt returns a cryptographically secure random integer in the
+// inclusive range [min, max]. It panics if max < min.
+func RandInt(min, max int) int { return secure.Int(min, max) }
+
+// RandBytes returns n cryptographically secure random bytes, suitable for
+// keys, tokens and nonces.
+func RandBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
+
+// RandString returns a cryptographically secure random string of n
+// characters drawn uniformly from charset, which may contain any runes.
+// It panics if charset is empty.
+func RandString(n int, charset string) string { return secure.String(n, charset) }
+
+// Fast is a fast, deterministic random generator with the same methods as
+// the secure helpers, for simulations and reproducible tests. It is not
+// safe for concurrent use and must never be used for secrets.
+type Fast struct {
+	r *mathrand.Rand
+}
+
+// NewFast returns a generator seeded with seed. Generators with the same
+// seed produce the same sequence.
+func NewFast(seed uint64) *Fast {
+	return &Fast{r: mathrand.New(mathrand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+// Int returns a random integer in the inclusive range [min, max]. It
+// panics if max < min.
+func (f *Fast) Int(min, max int) int {
+	if max < min {
+		panic("golib: random range max < min")
+	}
+	// Work in uint64 so that ranges wider than MaxInt, such as the full
+	// int range, do not overflow.
+	span := uint64(max) - uint64(min)
+	if span == ^uint64(0) {
+		return int(f.r.Uint64())
+	}
+	return min + int(f.r.Uint64N(span+1))
+}
+
+// Float64 returns a random number in [0.0, 1.0).
+func (f *Fast) Float64() float64 { return f.r.Float64() }
+
+// Bytes returns n random bytes.
+func (f *Fast) Bytes(n int) []byte {
+	b := make([]byte, n)
+	for i := 0; i < n; i += 8 {
+		var w [8]byte
+		binary.LittleEndian.PutUint64(w[:], f.r.Uint64())
+		copy(b[i:], w[:])
+	}
+	return b
+}
+
+// String returns a random string of n characters drawn uniformly from
+// charset. It panics if charset is empty.
+func (f *Fast) String(n int, charset string) string {
+	runes := []rune(charset)
+	if len(runes) == 0 {
+		panic("golib: empty charset")
+	}
+	out := make([]rune, n)
+	for i := range out {
+		out[i] = runes[f.r.IntN(len(runes))]
+	}
+	return string(out)
+}
+
+// Chooser picks items at random in proportion to their weights. Building
+// one is O(n); each pick is O(log n). A Chooser is safe for concurrent
+// use by Pick; PickWith is as safe as the Fast it is given.
+type Chooser[T any] struct {
+	items []T
+	cum   []float64 // cum[i] is the sum of weights[0..i]
+}
+
+// NewChooser returns a Chooser for items, where item i is picked with
+// probability weights[i] / sum(weights). Weights must be finite and
+// non-negative, and at least one must be positive.
+func NewChooser[T any](items []T, weights []float64) (*Chooser[T], error) {
+	if len(items) != len(weights) {
+		return nil, fmt.Errorf("golib: %d items but %d weights", len(items), len(weights))
+	}
+	cum := make([]float64, len(weights))
+	total := 0.0
+	for i, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("golib: invalid weight %v for item %d", w, i)
+		}
+		total += w
+		cum[i] = total
+	}
+	if total <= 0 {
+		return nil, errors.New("golib: weights sum to zero")
+	}
+	return &Chooser[T]{items: items, cum: cum}, nil
+}
+
+// Pick returns a random item, using the secure generator.
+func (c *Chooser[T]) Pick() T { return c.PickWith(secure) }
+
+// PickWith returns a random item drawn using f, for reproducible results.
+func (c *Chooser[T]) PickWith(f *Fast) T {
+	u := f.Float64() * c.cum[len(c.cum)-1]
+	i, _ := slices.BinarySearchFunc(c.cum, u, func(cum, u float64) int {
+		if cum <= u {
+			return -1 // zero-weight items share their predecessor's sum and are skipped
+		}
+		return 1
+	})
+	return c.items[min(i, len(c.items)-1)]
+}
+
+// WeightedChoice returns one of items picked in proportion to weights. To
+// pick many times from the same items, build a Chooser once instead.
+func WeightedChoice[T any](items []T, weights []float64) (T, error) {
+	c, err := NewChooser(items, weights)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return c.Pick(), nil
+}
+
+// Shuffle randomly permutes s in place, using the secure generator.
+func Shuffle[T any](s []T) { ShuffleWith(secure, s) }
+
+// ShuffleWith is like Shuffle but draws from f, for reproducible results.
+func ShuffleWith[T any](f *Fast, s []T) {
+	f.r.Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+}
+
+// Sample returns a random element of s, using the secure generator. It
+// reports false if s is empty.
+func Sample[T any](s []T) (T, bool) { return SampleWith(secure, s) }
+
+// SampleWith is like Sample but draws from f.
+func SampleWith[T any](f *Fast, s []T) (T, bool) {
+	if len(s) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s[f.r.IntN(len(s))], true
+}
+
+// SampleN returns k distinct elements of s, by position, in random order,
+// using the secure generator. If k exceeds len(s), all of s is returned
+// shuffled. s is not modified.
+func SampleN[T any](s []T, k int) []T { return SampleNWith(secure, s, k) }
+
+// SampleNWith is like SampleN but draws from f. It runs a partial
+// Fisher-Yates shuffle over the indexes of s, recording only the swapped
+// positions, so it takes O(k) time and space however long s is.
+func SampleNWith[T any](f *Fast, s []T, k int) []T {
+	k = min(max(k, 0), len(s))
+	swapped := make(map[int]int, k)
+	at := func(i int) int {
+		if j, ok := swapped[i]; ok {
+			return j
+		}
+		return i
+	}
+	out := make([]T, k)
+	for i := range k {
+		j := i + f.r.IntN(len(s)-i)
+		vi, vj := at(i), at(j)
+		swapped[j] = vi
+		out[i] = s[vj]
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+package golib
+
+import "math"
+import "slices"
+import "strings"
+import "testing"
+import "unicode/utf8"
+
+func TestRandInt(t *testing.T) {
+	seen := map[int]bool{}
+	for range 1000 {
+		n := RandInt(-2, 2)
+		if n < -2 || n > 2 {
+			t.Fatalf("RandInt(-2, 2) == %d", n)
+		}
+		seen[n] = true
+	}
+	if len(seen) != 5 {
+		t.Errorf("RandInt(-2, 2) produced only %v", seen)
+	}
+	if n := RandInt(7, 7); n != 7 {
+		t.Errorf("RandInt(7, 7) == %d", n)
+	}
+	RandInt(math.MinInt, math.MaxInt) // must not panic
+}
+
+func TestRandBytesAndString(t *testing.T) {
+	if b := RandBytes(32); len(b) != 32 || string(b) == string(make([]byte, 32)) {
+		t.Errorf("RandBytes(32) == %x", b)
+	}
+	s := RandString(20, "héllo")
+	if utf8.RuneCountInString(s) != 20 || strings.Trim(s, "héllo") != "" {
+		t.Errorf("RandString(20, héllo) == %q", s)
+	}
+	if RandString(16, Alphanumeric) == RandString(16, Alphanumeric) {
+		t.Error("RandString repeated itself")
+	}
+}
+
+func TestFast(t *testing.T) {
+	a, b := NewFast(42), NewFast(42)
+	for range 10 {
+		if x, y := a.Int(0, 1000), b.Int(0, 1000); x != y {
+			t.Fatalf("same seed diverged: %d != %d", x, y)
+		}
+	}
+	if a.String(10, Letters) != b.String(10, Letters) || string(a.Bytes(13)) != string(b.Bytes(13)) {
+		t.Error("same seed diverged")
+	}
+	if f := a.Float64(); f < 0 || f >= 1 {
+		t.Errorf("Float64() == %v", f)
+	}
+	if NewFast(1).Int(0, 1<<30) == NewFast(2).Int(0, 1<<30) {
+		t.Error("different seeds agreed")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("Int(1, 0) did not panic")
+		}
+	}()
+	a.Int(1, 0)
+}
+
+func TestChooser(t *testing.T) {
+	items := []string{"a", "b", "never", "c"}
+	c, err := NewChooser(items, []float64{1, 2, 0, 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := NewFast(1)
+	counts := map[string]int{}
+	const n = 100000
+	for range n {
+		counts[c.PickWith(f)]++
+	}
+	if counts["never"] != 0 {
+		t.Errorf("zero-weight item picked %d times", counts["never"])
+	}
+	for item, want := range map[string]float64{"a": 0.1, "b": 0.2, "c": 0.7} {
+		if got := float64(counts[item]) / n; math.Abs(got-want) > 0.01 {
+			t.Errorf("%s picked %.3f of the time, want %.1f", item, got, want)
+		}
+	}
+
+	for _, w := range [][]float64{{1, 2}, {1, -1, 1, 1}, {0, 0, 0, 0}, {1, math.NaN(), 1, 1}, {1, math.Inf(1), 1, 1}} {
+		if _, err := NewChooser(items, w); err == nil {
+			t.Errorf("NewChooser(weights %v) succeeded", w)
+		}
+	}
+}
+
+func TestWeightedChoice(t *testing.T) {
+	if got, err := WeightedChoice([]int{1, 2, 3}, []float64{0, 5, 0}); got != 2 || err != nil {
+		t.Errorf("WeightedChoice() == %d, %v", got, err)
+	}
+	if _, err := WeightedChoice([]int{1}, nil); err == nil {
+		t.Error("WeightedChoice with mismatched weights succeeded")
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	a, b := slices.Clone(s), slices.Clone(s)
+	ShuffleWith(NewFast(3), a)
+	ShuffleWith(NewFast(3), b)
+	if !slices.Equal(a, b) {
+		t.Errorf("same seed shuffled to %v and %v", a, b)
+	}
+	Shuffle(a)
+	slices.Sort(a)
+	if !slices.Equal(a, s) {
+		t.Errorf("Shuffle changed the elements: %v", a)
+	}
+	Shuffle([]int(nil)) // must not panic
+}
+
+func TestSample(t *testing.T) {
+	if _, ok := Sample([]int(nil)); ok {
+		t.Error("Sample(nil) reported an element")
+	}
+	f := NewFast(1)
+	counts := map[string]int{}
+	for range 3000 {
+		v, _ := SampleWith(f, []string{"a", "b", "c"})
+		counts[v]++
+	}
+	for _, v := range []string{"a", "b", "c"} {
+		if counts[v] < 900 {
+			t.Errorf("%s sampled %d times of 3000", v, counts[v])
+		}
+	}
+}
+
+func TestSampleN(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	for _, k := range []int{-1, 0, 3, 10, 20} {
+		got := SampleN(s, k)
+		if want := min(max(k, 0), len(s)); len(got) != want {
+			t.Errorf("SampleN(s, %d) returned %d elements, want %d", k, len(got), want)
+		}
+		if len(Unique(got)) != len(got) {
+			t.Errorf("SampleN(s, %d) == %v has repeats", k, got)
+		}
+	}
+	if !slices.Equal(s, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Errorf("SampleN modified its argument: %v", s)
+	}
+	if a, b := SampleNWith(NewFast(9), s, 4), SampleNWith(NewFast(9), s, 4); !slices.Equal(a, b) {
+		t.Errorf("same seed sampled %v and %v", a, b)
+	}
+
+	// Every element is equally likely to be chosen.
+	f := NewFast(2)
+	counts := make([]int, len(s))
+	for range 10000 {
+		for _, v := range SampleNWith(f, s, 3) {
+			counts[v]++
+		}
+	}
+	for v, n := range counts {
+		if n < 2700 || n > 3300 {
+			t.Errorf("element %d sampled %d times, want about 3000", v, n)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package ratelimit implements token-bucket rate limiting, per process or
+// per key, with HTTP middleware that rejects excess requests.
+package ratelimit
+
+import "context"
+import "errors"
+import "math"
+import "net"
+import "net/http"
+import "strconv"
+import "sync"
+import "time"
+
+// ErrExceedsBurst is returned by WaitN when n can never be satisfied.
+var ErrExceedsBurst = errors.New("ratelimit: request exceeds burst size")
+
+// Limiter is a token bucket: it holds up to burst tokens and refills at
+// rate tokens per second. Each event consumes one token.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// New returns a limiter that allows rate events per second on average and
+// bursts of up to burst events. The bucket starts full.
+func New(rate float64, burst int) *Limiter {
+	return &Limiter{rate: rate, burst: float64(burst), tokens: float64(burst), now: time.Now}
+}
+
+// advance refills the bucket for the time elapsed since the last call.
+// l.mu must be held.
+func (l *Limiter) advance() time.Time {
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	return now
+}
+
+// Allow reports whether an event may happen now, consuming a token if so.
+func (l *Limiter) Allow() bool {
+	return l.AllowN(1)
+}
+
+// AllowN reports whether n events may happen now, consuming n tokens if so.
+func (l *Limiter) AllowN(n int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance()
+	if l.tokens < float64(n) {
+		return false
+	}
+	l.tokens -= float64(n)
+	return true
+}
+
+// Delay returns how long the caller would have to wait for one token.
+func (l *Limiter) Delay() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance()
+	return l.delay(1)
+}
+
+// delay returns the time until n tokens are available. l.mu must be held.
+func (l *Limiter) delay(n float64) time.Duration {
+	if l.tokens >= n {
+		return 0
+	}
+	if l.rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration((n - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Wait blocks until an event may happen or ctx is done.
+func (l *Limiter) Wait(ctx context.Context) error {
+	return l.WaitN(ctx, 1)
+}
+
+// WaitN blocks until n events may happen or ctx is done. The tokens are
+// reserved immediately, so concurrent waiters are served in order; they
+// are returned to the bucket if ctx ends first.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	l.mu.Lock()
+	if float64(n) > l.burst {
+		l.mu.Unlock()
+		return ErrExceedsBurst
+	}
+	l.advance()
+	d := l.delay(float64(n))
+	if deadline, ok := ctx.Deadline(); ok && l.now().Add(d).After(deadline) {
+		l.mu.Unlock()
+		return context.DeadlineExceeded
+	}
+	l.tokens -= float64(n)
+	l.mu.Unlock()
+
+	if d == 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens += float64(n)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Keyed holds an independent Limiter per key, such as a client address.
+// Limiters idle long enough to have refilled completely are discarded.
+type Keyed struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    int
+	limiters map[string]*Limiter
+	next     int // prune when len(limiters) reaches next
+	now      func() time.Time
+}
+
+// minPrune is the fewest limiters a Keyed holds before pruning.
+const minPrune = 1024
+
+// NewKeyed returns a Keyed whose limiters are created as by New.
+func NewKeyed(rate float64, burst int) *Keyed {
+	return &Keyed{rate: rate, burst: burst, limiters: map[string]*Limiter{}, next: minPrune, now: time.Now}
+}
+
+// Get returns the limiter for key, creating it if needed.
+func (k *Keyed) Get(key string) *Limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	l, ok := k.limiters[key]
+	if !ok {
+		if len(k.limiters) >= k.next {
+			k.prune()
+		}
+		l = New(k.rate, k.burst)
+		l.now = k.now
+		k.limiters[key] = l
+	}
+	return l
+}
+
+// Allow reports whether an event for key may happen now.
+func (k *Keyed) Allow(key string) bool {
+	return k.Get(key).Allow()
+}
+
+// prune drops limiters that would be full by now, then sets the next
+// prune for when the survivors have doubled, so that the cost of
+// scanning them is spread over as many new keys. k.mu must be held.
+func (k *Keyed) prune() {
+	defer func() { k.next = max(minPrune, 2*len(k.limiters)) }()
+	for key, l := range k.limiters {
+		l.mu.Lock()
+		l.advance()
+		full := l.tokens >= l.burst
+		l.mu.Unlock()
+		if full {
+			delete(k.limiters, key)
+		}
+	}
+}
+
+// ClientIP returns the host part of r.RemoteAddr.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Middleware limits requests per key, as returned by key (ClientIP if nil),
+// replying 429 Too Many Requests with a Retry-After header when a client
+// exceeds its limit.
+func Middleware(k *Keyed, key func(*http.Request) string) func(http.Handler) http.Handler {
+	if key == nil {
+		key = ClientIP
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			l := k.Get(key(r))
+			if !l.Allow() {
+				secs := int(math.Ceil(l.Delay().Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
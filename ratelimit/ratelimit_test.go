@@ -0,0 +1,110 @@
+package ratelimit
+
+import "context"
+import "net/http"
+import "net/http/httptest"
+import "strconv"
+import "testing"
+import "time"
+
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestLimiter(t *testing.T) {
+	clock := &fakeClock{time.Unix(0, 0)}
+	l := New(2, 3) // 2 per second, bursts of 3
+	l.now = clock.now
+
+	steps := []struct {
+		advance time.Duration
+		want    bool
+	}{
+		{0, true}, {0, true}, {0, true}, {0, false},
+		{250 * time.Millisecond, false},
+		{250 * time.Millisecond, true}, // one token after 500ms
+		{0, false},
+		{10 * time.Second, true}, {0, true}, {0, true}, {0, false}, // refill caps at burst
+	}
+	for i, s := range steps {
+		clock.t = clock.t.Add(s.advance)
+		if got := l.Allow(); got != s.want {
+			t.Errorf("step %d: Allow() == %v, want %v", i, got, s.want)
+		}
+	}
+	if d := l.Delay(); d != 500*time.Millisecond {
+		t.Errorf("Delay() == %v, want 500ms", d)
+	}
+}
+
+func TestKeyedPrune(t *testing.T) {
+	clock := &fakeClock{time.Unix(0, 0)}
+	k := NewKeyed(1, 1)
+	k.now = clock.now
+	key := func(i int) string { return strconv.Itoa(i) }
+
+	// Every limiter is in use, so the first prune frees nothing and the
+	// next is put off until the map has doubled.
+	for i := range minPrune + 1 {
+		k.Allow(key(i))
+	}
+	if n, next := len(k.limiters), k.next; n != minPrune+1 || next != 2*minPrune {
+		t.Errorf("after busy prune: %d limiters, next prune at %d, want %d and %d", n, next, minPrune+1, 2*minPrune)
+	}
+
+	// Once they have all refilled, the next prune drops them.
+	clock.t = clock.t.Add(time.Second)
+	for i := minPrune + 1; i <= 2*minPrune; i++ {
+		k.Get(key(i))
+	}
+	if n, next := len(k.limiters), k.next; n != 1 || next != minPrune {
+		t.Errorf("after idle prune: %d limiters, next prune at %d, want 1 and %d", n, next, minPrune)
+	}
+}
+
+func TestWait(t *testing.T) {
+	l := New(100, 1)
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("three waits at 100/s took %v, want at least 15ms", elapsed)
+	}
+	if err := l.WaitN(ctx, 2); err != ErrExceedsBurst {
+		t.Errorf("WaitN beyond burst == %v, want ErrExceedsBurst", err)
+	}
+
+	slow := New(0.1, 1)
+	slow.Allow()
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := slow.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Wait past deadline == %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	h := Middleware(NewKeyed(1, 2), nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	codes := map[string][]int{
+		"10.0.0.1:1000": {200, 200, 429},
+		"10.0.0.2:1000": {200},
+	}
+	for addr, want := range codes {
+		for i, code := range want {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.RemoteAddr = addr
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != code {
+				t.Errorf("%s request %d: status %d, want %d", addr, i, rec.Code, code)
+			}
+			if code == 429 && rec.Header().Get("Retry-After") != "1" {
+				t.Errorf("Retry-After == %q, want \"1\"", rec.Header().Get("Retry-After"))
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Package redact hides sensitive data before it reaches logs, error
+// reports and fixtures: masks for strings such as card numbers and email
+// addresses, and a Redactor that copies a struct with its tagged fields
+// masked.
+package redact
+
+import "strings"
+import "unicode"
+import "unicode/utf8"
+
+// Mask is the rune that replaces hidden characters.
+const Mask = '*'
+
+// Redact returns s with all but its first keepFirst and last keepLast
+// runes replaced by Mask, keeping its length. If that would show all of
+// s, as for short strings, all of it is masked instead.
+//
+//	Redact("sk_live_4eC39HqLyjWD", 3, 4) == "sk_*************yjWD"
+func Redact(s string, keepFirst, keepLast int) string {
+	n := utf8.RuneCountInString(s)
+	keepFirst, keepLast = max(keepFirst, 0), max(keepLast, 0)
+	if keepFirst+keepLast >= n {
+		keepFirst, keepLast = 0, 0
+	}
+	var b strings.Builder
+	i := 0
+	for _, r := range s {
+		if i >= keepFirst && i < n-keepLast {
+			r = Mask
+		}
+		b.WriteRune(r)
+		i++
+	}
+	return b.String()
+}
+
+// MaskCreditCard masks every digit of the card number s except the last
+// four, leaving spaces and hyphens in place:
+//
+//	MaskCreditCard("4111 1111 1111 1111") == "**** **** **** 1111"
+//
+// A number of four digits or fewer is masked entirely.
+func MaskCreditCard(s string) string {
+	digits := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	keep := 4
+	if digits <= keep {
+		keep = 0
+	}
+	var b strings.Builder
+	seen := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			if seen++; seen <= digits-keep {
+				r = Mask
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// MaskEmail masks the local part of the email address s but its first
+// rune, and keeps the domain, so that the address can still be told
+// apart from others at a glance:
+//
+//	MaskEmail("jane.doe@example.com") == "j*******@example.com"
+//
+// A string without a local part and domain is masked entirely.
+func MaskEmail(s string) string {
+	i := strings.LastIndexByte(s, '@')
+	if i <= 0 || i == len(s)-1 {
+		return Redact(s, 0, 0)
+	}
+	local := s[:i]
+	keep := 1
+	if utf8.RuneCountInString(local) == 1 {
+		keep = 0
+	}
+	return Redact(local, keep, 0) + s[i:]
+}
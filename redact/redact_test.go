@@ -0,0 +1,53 @@
+package redact
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		s           string
+		first, last int
+		want        string
+	}{
+		{"sk_live_4eC39HqLyjWD", 3, 4, "sk_*************yjWD"},
+		{"password", 0, 0, "********"},
+		{"héllo wörld", 1, 1, "h*********d"},
+		{"abc", 2, 2, "***"},
+		{"abc", -1, 1, "**c"},
+		{"", 1, 1, ""},
+	}
+	for _, c := range cases {
+		if got := Redact(c.s, c.first, c.last); got != c.want {
+			t.Errorf("Redact(%q, %d, %d) = %q, want %q", c.s, c.first, c.last, got, c.want)
+		}
+	}
+}
+
+func TestMaskCreditCard(t *testing.T) {
+	cases := map[string]string{
+		"4111 1111 1111 1111": "**** **** **** 1111",
+		"5500-0000-0000-0004": "****-****-****-0004",
+		"378282246310005":     "***********0005",
+		"1234":                "****",
+		"":                    "",
+	}
+	for in, want := range cases {
+		if got := MaskCreditCard(in); got != want {
+			t.Errorf("MaskCreditCard(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	cases := map[string]string{
+		"jane.doe@example.com": "j*******@example.com",
+		"j@example.com":        "*@example.com",
+		"not an email":         "************",
+		"@example.com":         "************",
+		"jane@":                "*****",
+	}
+	for in, want := range cases {
+		if got := MaskEmail(in); got != want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
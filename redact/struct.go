@@ -0,0 +1,154 @@
+package redact
+
+import "fmt"
+import "reflect"
+
+// Redacted replaces the values of fields tagged redact:"true".
+const Redacted = "[REDACTED]"
+
+// A Redactor copies values with their sensitive fields hidden, so that
+// the copy can be logged. Fields are chosen by their struct tags:
+//
+//	type User struct {
+//		Name     string
+//		Email    string `redact:"email"`
+//		Password string `redact:"true"`
+//		Card     Card
+//	}
+//
+//	type Card struct {
+//		Number string `redact:"card"`
+//	}
+//
+// A tag of "true" replaces a string with Replacement and sets any other
+// type to its zero value; "email" and "card" apply MaskEmail and
+// MaskCreditCard to strings and *strings. Untagged fields are copied,
+// with structs, pointers, slices, arrays, maps and interfaces walked for
+// tagged fields in turn. Unexported fields are copied as they are, and so
+// are never redacted.
+//
+// The zero Redactor reads redact tags and replaces with Redacted.
+type Redactor struct {
+	// Tag is the struct tag to read. Empty means "redact".
+	Tag string
+	// Replacement replaces strings tagged "true". Empty means Redacted.
+	Replacement string
+}
+
+// Struct returns a copy of v with its tagged fields hidden, by the zero
+// Redactor.
+func Struct(v any) any { return Redactor{}.Redact(v) }
+
+// Redact returns a copy of v with its tagged fields hidden. v itself is
+// not changed, and shares no memory with the copy that the copy could
+// change. It panics on a tag value it does not know.
+func (r Redactor) Redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	if r.Tag == "" {
+		r.Tag = "redact"
+	}
+	if r.Replacement == "" {
+		r.Replacement = Redacted
+	}
+	w := walker{Redactor: r, seen: map[uintptr]reflect.Value{}}
+	return w.copy(reflect.ValueOf(v)).Interface()
+}
+
+type walker struct {
+	Redactor
+	seen map[uintptr]reflect.Value // copies of pointers, for cycles
+}
+
+func (w *walker) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		if c, ok := w.seen[v.Pointer()]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		w.seen[v.Pointer()] = c
+		c.Elem().Set(w.copy(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(w.copy(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := range v.NumField() {
+			f := v.Type().Field(i)
+			if f.IsExported() {
+				c.Field(i).Set(w.field(v.Field(i), f.Tag.Get(w.Tag)))
+			}
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(w.copy(v.Index(i)))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := range v.Len() {
+			c.Index(i).Set(w.copy(v.Index(i)))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for it := v.MapRange(); it.Next(); {
+			c.SetMapIndex(it.Key(), w.copy(it.Value()))
+		}
+		return c
+	}
+	return v
+}
+
+// field returns the copy of the field v with the given tag value.
+func (w *walker) field(v reflect.Value, tag string) reflect.Value {
+	switch tag {
+	case "", "-", "false":
+		return w.copy(v)
+	case "true":
+		if v.Kind() == reflect.String {
+			return reflect.ValueOf(w.Replacement).Convert(v.Type())
+		}
+		return reflect.Zero(v.Type())
+	case "email":
+		return mask(v, MaskEmail)
+	case "card":
+		return mask(v, MaskCreditCard)
+	}
+	panic(fmt.Sprintf("redact: unknown %s tag %q", w.Tag, tag))
+}
+
+// mask applies fn to a string or *string, and zeroes any other value.
+func mask(v reflect.Value, fn func(string) string) reflect.Value {
+	switch {
+	case v.Kind() == reflect.String:
+		return reflect.ValueOf(fn(v.String())).Convert(v.Type())
+	case v.Kind() == reflect.Pointer && v.Type().Elem().Kind() == reflect.String:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(mask(v.Elem(), fn))
+		return c
+	}
+	return reflect.Zero(v.Type())
+}
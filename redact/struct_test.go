@@ -0,0 +1,87 @@
+package redact
+
+import "reflect"
+import "testing"
+
+type token string
+
+type card struct {
+	Number string `redact:"card"`
+	Expiry string
+}
+
+type user struct {
+	Name     string
+	Email    *string `redact:"email"`
+	Password string  `redact:"true"`
+	Token    token   `redact:"true"`
+	PIN      int     `redact:"true"`
+	Cards    []card
+	Meta     map[string]any
+	Friend   *user
+	secret   string
+}
+
+func TestRedactor(t *testing.T) {
+	email := "jane@example.com"
+	u := &user{
+		Name:     "Jane",
+		Email:    &email,
+		Password: "hunter2",
+		Token:    "abc",
+		PIN:      1234,
+		Cards:    []card{{Number: "4111111111111111", Expiry: "12/30"}},
+		Meta:     map[string]any{"card": card{Number: "5500000000000004"}},
+		secret:   "kept",
+	}
+	u.Friend = u
+
+	got := Struct(u).(*user)
+	wantEmail := "j***@example.com"
+	want := &user{
+		Name:     "Jane",
+		Email:    &wantEmail,
+		Password: Redacted,
+		Token:    Redacted,
+		Cards:    []card{{Number: "************1111", Expiry: "12/30"}},
+		Meta:     map[string]any{"card": card{Number: "************0004"}},
+		secret:   "kept",
+	}
+	want.Friend = want
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Struct =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// The original is untouched.
+	if u.Password != "hunter2" || *u.Email != email || u.Cards[0].Number != "4111111111111111" ||
+		u.Meta["card"].(card).Number != "5500000000000004" {
+		t.Errorf("original changed: %+v", u)
+	}
+
+	if v := Struct(card{Number: "4111111111111111"}).(card); v.Number != "************1111" {
+		t.Errorf("by value: %+v", v)
+	}
+	if Struct(nil) != nil || Struct(42) != 42 {
+		t.Error("non-structs changed")
+	}
+}
+
+func TestRedactorOptions(t *testing.T) {
+	type login struct {
+		User string `log:"-"`
+		Pass string `log:"true" redact:"-"`
+	}
+	r := Redactor{Tag: "log", Replacement: "xxx"}
+	if got := r.Redact(login{"jane", "pw"}).(login); got != (login{"jane", "xxx"}) {
+		t.Errorf("Redact = %+v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("unknown tag value did not panic")
+		}
+	}()
+	Struct(struct {
+		A string `redact:"yes"`
+	}{})
+}
@@ -0,0 +1,100 @@
+package resilience
+
+import "sync"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+// A State is the state of a Breaker.
+type State int
+
+const (
+	// Closed lets calls through, counting consecutive failures.
+	Closed State = iota
+	// Open rejects calls until the cooldown has passed.
+	Open
+	// HalfOpen lets one trial call through: if it succeeds the breaker
+	// closes, and if it fails it opens again.
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// A Breaker is a circuit breaker: after Threshold consecutive failures it
+// opens and rejects calls for Cooldown, so that a failing dependency is
+// not hammered while it recovers. Share one Breaker between all calls to
+// the same dependency. It is safe for concurrent use.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	trial    bool // whether the half-open trial call is in flight
+}
+
+// NewBreaker returns a closed Breaker that opens after threshold
+// consecutive failures and tries again after cooldown. A nil clock means
+// clock.Real.
+func NewBreaker(threshold int, cooldown time.Duration, c clock.Clock) *Breaker {
+	return &Breaker{threshold: max(threshold, 1), cooldown: cooldown, clock: clock.Or(c)}
+}
+
+// State returns the breaker's state. An open breaker whose cooldown has
+// passed is reported as half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tick()
+	return b.state
+}
+
+// tick moves an open breaker whose cooldown has passed to half-open.
+func (b *Breaker) tick() {
+	if b.state == Open && b.clock.Now().Sub(b.openedAt) >= b.cooldown {
+		b.state = HalfOpen
+		b.trial = false
+	}
+}
+
+// Allow reports whether a call may go ahead, returning ErrOpen if not. A
+// call that is allowed must be followed by Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tick()
+	switch {
+	case b.state == Open, b.state == HalfOpen && b.trial:
+		return ErrOpen
+	case b.state == HalfOpen:
+		b.trial = true
+	}
+	return nil
+}
+
+// Record records the outcome of an allowed call.
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.state, b.failures = Closed, 0
+		return
+	}
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		b.state, b.openedAt = Open, b.clock.Now()
+	}
+}
@@ -0,0 +1,43 @@
+package resilience
+
+import "context"
+import "errors"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+func TestBreaker(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	b := NewBreaker(2, time.Minute, clk)
+	var m Metrics
+	primary, calls := flaky(3)
+	opts := Options{Breaker: b, Metrics: &m, Retry: Retry{Attempts: 5}}
+
+	if _, err := Execute(context.Background(), primary, nil, opts); !errors.Is(err, ErrOpen) {
+		t.Fatalf("err %v, want ErrOpen", err)
+	}
+	if *calls != 2 || b.State() != Open || m.Rejected.Load() != 1 {
+		t.Fatalf("%d calls, state %v, %d rejected", *calls, b.State(), m.Rejected.Load())
+	}
+
+	clk.Advance(time.Minute)
+	if b.State() != HalfOpen {
+		t.Fatalf("state %v after cooldown", b.State())
+	}
+	if _, err := Execute(context.Background(), primary, nil, Options{Breaker: b}); !errors.Is(err, errDown) || b.State() != Open {
+		t.Fatalf("failed trial: %v, state %v", err, b.State())
+	}
+
+	clk.Advance(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Errorf("second half-open call allowed")
+	}
+	b.Record(nil)
+	if b.State() != Closed {
+		t.Errorf("state %v after successful trial", b.State())
+	}
+}
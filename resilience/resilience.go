@@ -0,0 +1,191 @@
+// Package resilience runs calls to unreliable dependencies under a
+// declared set of policies: a timeout for each attempt, retries with
+// exponential backoff, a circuit breaker and a fallback.
+//
+// The policies nest in a fixed order. Each attempt must pass the breaker
+// and is cut off by the timeout; failed attempts are retried; and when
+// the retries are spent the fallback, if any, supplies the result:
+//
+//	opts := resilience.Options{
+//		Timeout: time.Second,
+//		Retry:   resilience.Retry{Attempts: 3, Backoff: 100 * time.Millisecond},
+//		Breaker: breaker, // shared by every call to this service
+//		Metrics: &metrics,
+//	}
+//	user, err := resilience.Execute(ctx, fetchUser, cachedUser, opts)
+package resilience
+
+import "context"
+import "errors"
+import "fmt"
+import "sync/atomic"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+// ErrOpen is returned for a call rejected by an open Breaker.
+var ErrOpen = errors.New("resilience: circuit breaker open")
+
+// ErrTimeout is returned for an attempt cut off by Options.Timeout.
+var ErrTimeout = errors.New("resilience: attempt timed out")
+
+// Options declare the policies for Execute. The zero value makes one
+// attempt with no timeout, breaker or metrics.
+type Options struct {
+	// Timeout limits each attempt. Zero means no limit.
+	Timeout time.Duration
+	Retry   Retry
+	// Breaker, if not nil, must allow each attempt and records its
+	// outcome.
+	Breaker *Breaker
+	// Metrics, if not nil, counts what each policy did.
+	Metrics *Metrics
+	// Clock times timeouts and backoff. Nil means clock.Real.
+	Clock clock.Clock
+}
+
+// Retry says how failed attempts are retried.
+type Retry struct {
+	// Attempts is the most attempts made, including the first. Zero
+	// means one.
+	Attempts int
+	// Backoff is the wait before the first retry, doubling before each
+	// one after, up to MaxBackoff. Zero MaxBackoff means no cap.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Retryable reports whether an attempt's error is worth retrying. Nil
+	// means every error is, except ErrOpen and the cancellation of the
+	// call's context.
+	Retryable func(error) bool
+}
+
+// Metrics counts, across calls, what the policies did. Its fields may be
+// read at any time. The zero value is ready to use.
+type Metrics struct {
+	Calls     atomic.Int64 // calls to Execute
+	Successes atomic.Int64 // calls that returned the primary's result
+	Failures  atomic.Int64 // calls that returned an error
+	Attempts  atomic.Int64 // calls to the primary
+	Retries   atomic.Int64 // attempts after the first
+	Timeouts  atomic.Int64 // attempts cut off by the timeout
+	Rejected  atomic.Int64 // attempts rejected by the breaker
+	Fallbacks atomic.Int64 // calls that returned the fallback's result
+}
+
+// Execute calls primary under the policies in opts. If every attempt
+// fails and fallback is not nil, it returns fallback's result for the
+// last error; otherwise it returns that error. An attempt that times out
+// is abandoned: its context is cancelled, but Execute does not wait for
+// primary to return.
+func Execute[T any](ctx context.Context, primary func(context.Context) (T, error), fallback func(context.Context, error) (T, error), opts Options) (T, error) {
+	m := opts.Metrics
+	if m != nil {
+		m.Calls.Add(1)
+	}
+	v, err := attempts(ctx, primary, opts)
+	if err == nil {
+		if m != nil {
+			m.Successes.Add(1)
+		}
+		return v, nil
+	}
+	if fallback != nil {
+		if m != nil {
+			m.Fallbacks.Add(1)
+		}
+		v, err = fallback(ctx, err)
+	}
+	if err != nil && m != nil {
+		m.Failures.Add(1)
+	}
+	return v, err
+}
+
+// attempts makes up to opts.Retry.Attempts attempts, backing off between
+// them, and returns the result of the last.
+func attempts[T any](ctx context.Context, primary func(context.Context) (T, error), opts Options) (T, error) {
+	var zero T
+	clk := clock.Or(opts.Clock)
+	r := opts.Retry
+	retryable := r.Retryable
+	if retryable == nil {
+		retryable = func(err error) bool { return !errors.Is(err, ErrOpen) && ctx.Err() == nil }
+	}
+	backoff := r.Backoff
+	for n := 1; ; n++ {
+		if n > 1 && opts.Metrics != nil {
+			opts.Metrics.Retries.Add(1)
+		}
+		v, err := attempt(ctx, primary, opts, clk)
+		if err == nil || n >= max(r.Attempts, 1) || !retryable(err) {
+			return v, err
+		}
+		if backoff > 0 {
+			select {
+			case <-clk.After(backoff):
+			case <-ctx.Done():
+				return zero, fmt.Errorf("%w (after %w)", ctx.Err(), err)
+			}
+			backoff *= 2
+			if r.MaxBackoff > 0 {
+				backoff = min(backoff, r.MaxBackoff)
+			}
+		}
+	}
+}
+
+// attempt calls primary once, through the breaker and under the timeout.
+func attempt[T any](ctx context.Context, primary func(context.Context) (T, error), opts Options, clk clock.Clock) (T, error) {
+	var zero T
+	m := opts.Metrics
+	if b := opts.Breaker; b != nil {
+		if err := b.Allow(); err != nil {
+			if m != nil {
+				m.Rejected.Add(1)
+			}
+			return zero, err
+		}
+	}
+	if m != nil {
+		m.Attempts.Add(1)
+	}
+	v, err := timed(ctx, primary, opts.Timeout, clk)
+	if errors.Is(err, ErrTimeout) && m != nil {
+		m.Timeouts.Add(1)
+	}
+	if b := opts.Breaker; b != nil {
+		b.Record(err)
+	}
+	return v, err
+}
+
+type result[T any] struct {
+	v   T
+	err error
+}
+
+// timed calls primary, giving up after d if d is positive or when ctx
+// ends.
+func timed[T any](ctx context.Context, primary func(context.Context) (T, error), d time.Duration, clk clock.Clock) (T, error) {
+	if d <= 0 {
+		return primary(ctx)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	done := make(chan result[T], 1)
+	go func() {
+		v, err := primary(ctx)
+		done <- result[T]{v, err}
+	}()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case <-clk.After(d):
+		cancel(ErrTimeout)
+		var zero T
+		return zero, fmt.Errorf("%w after %v", ErrTimeout, d)
+	}
+}
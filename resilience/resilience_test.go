@@ -0,0 +1,115 @@
+package resilience
+
+import "context"
+import "errors"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+var errDown = errors.New("down")
+
+// flaky returns a primary that fails its first n calls, and a count of
+// calls.
+func flaky(n int) (func(context.Context) (string, error), *int) {
+	calls := 0
+	return func(context.Context) (string, error) {
+		calls++
+		if calls <= n {
+			return "", errDown
+		}
+		return "ok", nil
+	}, &calls
+}
+
+func TestRetry(t *testing.T) {
+	primary, calls := flaky(2)
+	var m Metrics
+	v, err := Execute(context.Background(), primary, nil, Options{Retry: Retry{Attempts: 3}, Metrics: &m})
+	if v != "ok" || err != nil {
+		t.Fatalf("Execute = %q, %v", v, err)
+	}
+	if *calls != 3 || m.Retries.Load() != 2 || m.Successes.Load() != 1 {
+		t.Errorf("calls %d, retries %d, successes %d", *calls, m.Retries.Load(), m.Successes.Load())
+	}
+
+	primary, calls = flaky(5)
+	_, err = Execute(context.Background(), primary, nil, Options{Retry: Retry{Attempts: 3}, Metrics: &m})
+	if !errors.Is(err, errDown) || *calls != 3 || m.Failures.Load() != 1 {
+		t.Errorf("err %v after %d calls, %d failures", err, *calls, m.Failures.Load())
+	}
+
+	primary, calls = flaky(5)
+	never := Retry{Attempts: 3, Retryable: func(error) bool { return false }}
+	if _, err = Execute(context.Background(), primary, nil, Options{Retry: never}); *calls != 1 {
+		t.Errorf("%d calls with nothing retryable", *calls)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	primary, _ := flaky(3)
+	done := make(chan error)
+	go func() {
+		_, err := Execute(context.Background(), primary, nil, Options{
+			Retry: Retry{Attempts: 4, Backoff: time.Second, MaxBackoff: 3 * time.Second},
+			Clock: clk,
+		})
+		done <- err
+	}()
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second} {
+		clk.BlockUntil(1)
+		clk.Advance(d - time.Nanosecond)
+		if clk.Waiters() != 1 {
+			t.Fatalf("backoff ended before %v", d)
+		}
+		clk.Advance(time.Nanosecond)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	clk := clock.NewFake(time.Unix(0, 0))
+	var m Metrics
+	cancelled := make(chan error, 1)
+	slow := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		cancelled <- context.Cause(ctx)
+		return 0, ctx.Err()
+	}
+	done := make(chan error)
+	go func() {
+		_, err := Execute(context.Background(), slow, nil, Options{Timeout: time.Second, Clock: clk, Metrics: &m})
+		done <- err
+	}()
+	clk.BlockUntil(1)
+	clk.Advance(time.Second)
+	if err := <-done; !errors.Is(err, ErrTimeout) {
+		t.Errorf("Execute error %v, want ErrTimeout", err)
+	}
+	if err := <-cancelled; !errors.Is(err, ErrTimeout) {
+		t.Errorf("attempt's context cause %v", err)
+	}
+	if m.Timeouts.Load() != 1 {
+		t.Errorf("%d timeouts", m.Timeouts.Load())
+	}
+}
+
+func TestFallback(t *testing.T) {
+	primary, _ := flaky(1)
+	var m Metrics
+	var got error
+	fallback := func(_ context.Context, err error) (string, error) {
+		got = err
+		return "cached", nil
+	}
+	v, err := Execute(context.Background(), primary, fallback, Options{Metrics: &m})
+	if v != "cached" || err != nil || !errors.Is(got, errDown) {
+		t.Errorf("Execute = %q, %v; fallback got %v", v, err, got)
+	}
+	if m.Fallbacks.Load() != 1 || m.Failures.Load() != 0 {
+		t.Errorf("%d fallbacks, %d failures", m.Fallbacks.Load(), m.Failures.Load())
+	}
+}
@@ -0,0 +1,105 @@
+package golib
+
+// A RingMode says what a full Ring does with a new element.
+type RingMode int
+
+const (
+	// RingOverwrite drops the oldest element to make room, so the ring
+	// keeps the most recent elements, like the last N lines of a log.
+	RingOverwrite RingMode = iota
+	// RingReject refuses the new element.
+	RingReject
+)
+
+// Ring is a first-in, first-out queue of fixed capacity. It is not safe
+// for concurrent use.
+type Ring[T any] struct {
+	buf   []T
+	start int // index of the oldest element
+	len   int
+	mode  RingMode
+}
+
+// NewRing returns an empty ring holding up to capacity elements. It
+// panics if capacity < 1.
+func NewRing[T any](capacity int, mode RingMode) *Ring[T] {
+	if capacity < 1 {
+		panic("golib: ring capacity < 1")
+	}
+	return &Ring[T]{buf: make([]T, capacity), mode: mode}
+}
+
+// Len returns the number of elements in r.
+func (r *Ring[T]) Len() int { return r.len }
+
+// Cap returns the most elements r can hold.
+func (r *Ring[T]) Cap() int { return len(r.buf) }
+
+// Full reports whether r holds Cap elements.
+func (r *Ring[T]) Full() bool { return r.len == len(r.buf) }
+
+// Push adds v as the newest element and reports whether it did, which it
+// does not only when r is full and rejects new elements.
+func (r *Ring[T]) Push(v T) bool {
+	if r.Full() {
+		if r.mode == RingReject {
+			return false
+		}
+		r.buf[r.start] = v
+		r.start = (r.start + 1) % len(r.buf)
+		return true
+	}
+	r.buf[(r.start+r.len)%len(r.buf)] = v
+	r.len++
+	return true
+}
+
+// Pop removes and returns the oldest element, or reports false if r is
+// empty.
+func (r *Ring[T]) Pop() (T, bool) {
+	var zero T
+	if r.len == 0 {
+		return zero, false
+	}
+	v := r.buf[r.start]
+	r.buf[r.start] = zero // do not keep garbage alive
+	r.start = (r.start + 1) % len(r.buf)
+	r.len--
+	return v, true
+}
+
+// Peek returns the oldest element without removing it, or reports false
+// if r is empty.
+func (r *Ring[T]) Peek() (T, bool) {
+	if r.len == 0 {
+		var zero T
+		return zero, false
+	}
+	return r.buf[r.start], true
+}
+
+// At returns the ith element, counting from 0 for the oldest. It panics
+// if i is out of range.
+func (r *Ring[T]) At(i int) T {
+	if i < 0 || i >= r.len {
+		panic("golib: ring index out of range")
+	}
+	return r.buf[(r.start+i)%len(r.buf)]
+}
+
+// Snapshot returns a copy of the elements of r, oldest first.
+func (r *Ring[T]) Snapshot() []T {
+	s := make([]T, 0, r.len)
+	end := r.start + r.len
+	if end <= len(r.buf) {
+		return append(s, r.buf[r.start:end]...)
+	}
+	s = append(s, r.buf[r.start:]...)
+	return append(s, r.buf[:end-len(r.buf)]...)
+}
+
+// Clear removes every element from r.
+func (r *Ring[T]) Clear() {
+	clear(r.buf)
+	r.start, r.len = 0, 0
+}
@@ -0,0 +1,51 @@
+package golib
+
+import "slices"
+import "testing"
+
+func TestRingOverwrite(t *testing.T) {
+	r := NewRing[int](3, RingOverwrite)
+	for i := 1; i <= 5; i++ {
+		if !r.Push(i) {
+			t.Fatalf("Push(%d) rejected", i)
+		}
+	}
+	if got := r.Snapshot(); !slices.Equal(got, []int{3, 4, 5}) || r.Len() != 3 || r.Cap() != 3 {
+		t.Fatalf("Snapshot() == %v with Len %d, want [3 4 5]", got, r.Len())
+	}
+	if r.At(0) != 3 || r.At(2) != 5 {
+		t.Errorf("At(0), At(2) == %d, %d", r.At(0), r.At(2))
+	}
+	if v, ok := r.Pop(); v != 3 || !ok {
+		t.Errorf("Pop() == %d, %v", v, ok)
+	}
+	r.Push(6)
+	r.Push(7)
+	if got := r.Snapshot(); !slices.Equal(got, []int{5, 6, 7}) {
+		t.Errorf("Snapshot() == %v, want [5 6 7]", got)
+	}
+	if v, ok := r.Peek(); v != 5 || !ok {
+		t.Errorf("Peek() == %d, %v", v, ok)
+	}
+	r.Clear()
+	if _, ok := r.Pop(); ok || r.Len() != 0 || len(r.Snapshot()) != 0 {
+		t.Error("ring not empty after Clear")
+	}
+}
+
+func TestRingReject(t *testing.T) {
+	r := NewRing[string](2, RingReject)
+	if !r.Push("a") || !r.Push("b") || r.Push("c") {
+		t.Fatal("RingReject ring accepted an element when full or refused one with room")
+	}
+	if !r.Full() {
+		t.Error("Full() == false")
+	}
+	r.Pop()
+	if !r.Push("c") {
+		t.Error("Push after Pop rejected")
+	}
+	if got := r.Snapshot(); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("Snapshot() == %q, want [b c]", got)
+	}
+}
@@ -0,0 +1,56 @@
+package golib
+
+import "fmt"
+import "strconv"
+import "strings"
+import "unicode"
+import "unicode/utf8"
+
+// RunLengthEncode replaces each run of a repeated character in s with its
+// length and the character: "aaab" becomes "3a1b". Digits in s make the
+// encoding ambiguous, so encode only text without them.
+func RunLengthEncode(s string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		n := 0
+		for len(s) > 0 {
+			r2, size2 := utf8.DecodeRuneInString(s)
+			if r2 != r || size2 != size {
+				break
+			}
+			s = s[size:]
+			n++
+		}
+		b.WriteString(strconv.Itoa(n))
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// MaxRunLengthDecoded is the most bytes RunLengthDecode will produce, so
+// that a short input such as "99999999999a" cannot exhaust memory.
+const MaxRunLengthDecoded = 64 << 20
+
+// RunLengthDecode reverses RunLengthEncode. It fails if the result would
+// be larger than MaxRunLengthDecoded.
+func RunLengthDecode(s string) (string, error) {
+	var b strings.Builder
+	for len(s) > 0 {
+		i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+		if i <= 0 {
+			return "", fmt.Errorf("golib: invalid run-length encoding at %q", s)
+		}
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return "", fmt.Errorf("golib: invalid run length %q", s[:i])
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if n > (MaxRunLengthDecoded-b.Len())/len(string(r)) {
+			return "", fmt.Errorf("golib: run-length decoding exceeds %d bytes", MaxRunLengthDecoded)
+		}
+		b.WriteString(strings.Repeat(string(r), n))
+		s = s[i+size:]
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,30 @@
+package golib
+
+import "testing"
+
+func TestRunLength(t *testing.T) {
+	cases := []struct{ in, want string }{
+		{"aaab", "3a1b"},
+		{"", ""},
+		{"ééé  x", "3é2 1x"},
+		{"wwwwwwwwwwwwb", "12w1b"},
+	}
+	for _, c := range cases {
+		got := RunLengthEncode(c.in)
+		if got != c.want {
+			t.Errorf("RunLengthEncode(%q) == %q, want %q", c.in, got, c.want)
+		}
+		if back, err := RunLengthDecode(got); back != c.in || err != nil {
+			t.Errorf("RunLengthDecode(%q) == %q, %v", got, back, err)
+		}
+	}
+	huge := []string{"99999999999a", "99999999999999999999999a", "67108864a1b", "33554433é"}
+	for _, bad := range append([]string{"a", "3", "3a2"}, huge...) {
+		if _, err := RunLengthDecode(bad); err == nil {
+			t.Errorf("RunLengthDecode(%q) succeeded", bad)
+		}
+	}
+	if got, err := RunLengthDecode("67108864a"); len(got) != MaxRunLengthDecoded || err != nil {
+		t.Errorf("RunLengthDecode at the limit == %d bytes, %v", len(got), err)
+	}
+}
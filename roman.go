@@ -0,0 +1,49 @@
+package golib
+
+import "fmt"
+import "strings"
+
+var romanNumerals = []struct {
+	value  int
+	symbol string
+}{
+	{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+	{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+	{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+}
+
+// ToRoman returns n in Roman numerals. Standard numerals only reach 3999,
+// so n must be between 1 and 3999.
+func ToRoman(n int) (string, error) {
+	if n < 1 || n > 3999 {
+		return "", fmt.Errorf("golib: %d cannot be written in Roman numerals", n)
+	}
+	var b strings.Builder
+	for _, r := range romanNumerals {
+		for n >= r.value {
+			b.WriteString(r.symbol)
+			n -= r.value
+		}
+	}
+	return b.String(), nil
+}
+
+// FromRoman parses a Roman numeral, in either case. Only the standard
+// subtractive form is accepted: "IV" is 4, but "IIII", "IIV" and "VX" are
+// errors.
+func FromRoman(s string) (int, error) {
+	u := strings.ToUpper(s)
+	n, rest := 0, u
+	for _, r := range romanNumerals {
+		for strings.HasPrefix(rest, r.symbol) {
+			n += r.value
+			rest = rest[len(r.symbol):]
+		}
+	}
+	// Greedy parsing accepts some malformed numerals, such as "IIII";
+	// only the canonical spelling of n is valid.
+	if canonical, err := ToRoman(n); rest != "" || err != nil || canonical != u {
+		return 0, fmt.Errorf("golib: invalid Roman numeral %q", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,48 @@
+package golib
+
+import "testing"
+
+func TestRoman(t *testing.T) {
+	cases := []struct {
+		n int
+		s string
+	}{
+		{1, "I"},
+		{4, "IV"},
+		{9, "IX"},
+		{14, "XIV"},
+		{40, "XL"},
+		{90, "XC"},
+		{400, "CD"},
+		{1994, "MCMXCIV"},
+		{2024, "MMXXIV"},
+		{3999, "MMMCMXCIX"},
+	}
+	for _, c := range cases {
+		if got, err := ToRoman(c.n); got != c.s || err != nil {
+			t.Errorf("ToRoman(%d) == %q, %v, want %q", c.n, got, err, c.s)
+		}
+		if got, err := FromRoman(c.s); got != c.n || err != nil {
+			t.Errorf("FromRoman(%q) == %d, %v, want %d", c.s, got, err, c.n)
+		}
+	}
+	if got, err := FromRoman("mcmxciv"); got != 1994 || err != nil {
+		t.Errorf("FromRoman(lower case) == %d, %v", got, err)
+	}
+	for n := 1; n <= 3999; n++ {
+		s, _ := ToRoman(n)
+		if got, err := FromRoman(s); got != n || err != nil {
+			t.Fatalf("FromRoman(ToRoman(%d)) == %d, %v", n, got, err)
+		}
+	}
+	for _, n := range []int{0, -1, 4000} {
+		if _, err := ToRoman(n); err == nil {
+			t.Errorf("ToRoman(%d) succeeded", n)
+		}
+	}
+	for _, s := range []string{"", "IIII", "IIV", "VX", "IC", "MMMM", "VV", "XLX", "ABC", "I V"} {
+		if _, err := FromRoman(s); err == nil {
+			t.Errorf("FromRoman(%q) succeeded", s)
+		}
+	}
+}
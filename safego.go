@@ -0,0 +1,84 @@
+package golib
+
+import "context"
+import "log"
+import "runtime/debug"
+import "time"
+
+// SafeGo runs fn in a new goroutine, recovering a panic in it and passing
+// it to onPanic rather than crashing the program. A nil onPanic logs the
+// panic and its stack with log.Printf.
+func SafeGo(fn func(), onPanic func(*PanicError)) {
+	go func() {
+		defer recoverTo(onPanic)
+		fn()
+	}()
+}
+
+// SafeGoOptions configure SafeGoCtx.
+type SafeGoOptions struct {
+	// OnPanic receives each panic. Nil means log it with log.Printf.
+	OnPanic func(*PanicError)
+	// Restart runs fn again after it panics, for long-lived workers. It
+	// is not restarted once it returns or its context is done.
+	Restart bool
+	// Backoff is how long to wait before a restart.
+	Backoff time.Duration
+	// MaxRestarts limits how many times fn is restarted. Zero means no
+	// limit.
+	MaxRestarts int
+}
+
+// SafeGoCtx is like SafeGo but passes ctx to fn and can restart fn when it
+// panics. The returned channel is closed when the goroutine exits: when
+// fn returns, when ctx is done, or after a panic it is not restarted from.
+func SafeGoCtx(ctx context.Context, fn func(context.Context), opts SafeGoOptions) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for restarts := 0; ; restarts++ {
+			if !runSafely(ctx, fn, opts.OnPanic) || !opts.Restart || ctx.Err() != nil {
+				return
+			}
+			if opts.MaxRestarts > 0 && restarts >= opts.MaxRestarts {
+				return
+			}
+			if opts.Backoff > 0 {
+				t := time.NewTimer(opts.Backoff)
+				select {
+				case <-t.C:
+				case <-ctx.Done():
+					t.Stop()
+					return
+				}
+			}
+		}
+	}()
+	return done
+}
+
+// runSafely calls fn and reports whether it panicked.
+func runSafely(ctx context.Context, fn func(context.Context), onPanic func(*PanicError)) (panicked bool) {
+	defer func() {
+		if v := recover(); v != nil {
+			panicked = true
+			report(&PanicError{Value: v, Stack: debug.Stack()}, onPanic)
+		}
+	}()
+	fn(ctx)
+	return false
+}
+
+func recoverTo(onPanic func(*PanicError)) {
+	if v := recover(); v != nil {
+		report(&PanicError{Value: v, Stack: debug.Stack()}, onPanic)
+	}
+}
+
+func report(pe *PanicError, onPanic func(*PanicError)) {
+	if onPanic == nil {
+		log.Printf("golib: recovered %v\n%s", pe, pe.Stack)
+		return
+	}
+	onPanic(pe)
+}
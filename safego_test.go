@@ -0,0 +1,58 @@
+package golib
+
+import "bytes"
+import "context"
+import "log"
+import "strings"
+import "sync/atomic"
+import "testing"
+
+func TestSafeGo(t *testing.T) {
+	got := make(chan *PanicError)
+	SafeGo(func() { panic("boom") }, func(pe *PanicError) { got <- pe })
+	if pe := <-got; pe.Value != "boom" || !strings.Contains(string(pe.Stack), "TestSafeGo") {
+		t.Errorf("onPanic got %v", pe)
+	}
+
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+	done := SafeGoCtx(context.Background(), func(context.Context) { panic("logged") }, SafeGoOptions{})
+	<-done
+	if !strings.Contains(buf.String(), "panic: logged") {
+		t.Errorf("log = %q", buf.String())
+	}
+}
+
+func TestSafeGoCtxRestart(t *testing.T) {
+	var runs, panics atomic.Int32
+	done := SafeGoCtx(context.Background(), func(context.Context) {
+		if runs.Add(1) < 3 {
+			panic("again")
+		}
+	}, SafeGoOptions{Restart: true, OnPanic: func(*PanicError) { panics.Add(1) }})
+	<-done
+	if runs.Load() != 3 || panics.Load() != 2 {
+		t.Errorf("%d runs, %d panics", runs.Load(), panics.Load())
+	}
+
+	runs.Store(0)
+	<-SafeGoCtx(context.Background(), func(context.Context) {
+		runs.Add(1)
+		panic("always")
+	}, SafeGoOptions{Restart: true, MaxRestarts: 2, OnPanic: func(*PanicError) {}})
+	if runs.Load() != 3 {
+		t.Errorf("%d runs with MaxRestarts 2", runs.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runs.Store(0)
+	<-SafeGoCtx(ctx, func(context.Context) {
+		runs.Add(1)
+		cancel()
+		panic("cancelled")
+	}, SafeGoOptions{Restart: true, OnPanic: func(*PanicError) {}})
+	if runs.Load() != 1 {
+		t.Errorf("%d runs after cancellation", runs.Load())
+	}
+}
@@ -0,0 +1,214 @@
+// Package scan probes TCP ports on a host concurrently and reports those
+// accepting connections, optionally with the banner each service sends.
+//
+// Only scan hosts you are authorised to test.
+package scan
+
+import "bufio"
+import "context"
+import "errors"
+import "fmt"
+import "net"
+import "slices"
+import "strconv"
+import "strings"
+import "sync"
+import "time"
+import "unicode"
+
+// Defaults for the zero fields of Options.
+const (
+	DefaultTimeout     = time.Second
+	DefaultConcurrency = 100
+	DefaultBannerWait  = time.Second
+)
+
+// Options configures a scan. The zero value is ready to use.
+type Options struct {
+	// Timeout bounds each connection attempt.
+	Timeout time.Duration
+	// Concurrency is the number of ports probed at once.
+	Concurrency int
+	// Banner reads the first line each open port sends. Services that
+	// wait for the client to speak first, such as HTTP, are sent a HEAD
+	// request if they have said nothing after BannerWait.
+	Banner     bool
+	BannerWait time.Duration
+}
+
+// A Result describes an open port.
+type Result struct {
+	Port    int
+	Service string // conventional service name, if well known
+	Banner  string // first line sent by the service, if Options.Banner
+}
+
+// Scan probes ports on host and returns the open ones in port order. If
+// ctx is done before the scan completes, Scan returns the ports found so
+// far and ctx.Err().
+func Scan(ctx context.Context, host string, ports []int, opts Options) ([]Result, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	if opts.BannerWait <= 0 {
+		opts.BannerWait = DefaultBannerWait
+	}
+
+	jobs := make(chan int)
+	var (
+		mu   sync.Mutex
+		open []Result
+		wg   sync.WaitGroup
+	)
+	for range min(opts.Concurrency, len(ports)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for port := range jobs {
+				if r, ok := Probe(ctx, host, port, opts); ok {
+					mu.Lock()
+					open = append(open, r)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+feed:
+	for _, port := range ports {
+		select {
+		case jobs <- port:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	slices.SortFunc(open, func(a, b Result) int { return a.Port - b.Port })
+	return open, ctx.Err()
+}
+
+// Probe connects to a single port and reports whether it is open.
+func Probe(ctx context.Context, host string, port int, opts Options) (Result, bool) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	d := net.Dialer{Timeout: opts.Timeout}
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return Result{}, false
+	}
+	defer conn.Close()
+	r := Result{Port: port, Service: services[port]}
+	if opts.Banner {
+		if opts.BannerWait <= 0 {
+			opts.BannerWait = DefaultBannerWait
+		}
+		r.Banner = grab(ctx, conn, host, opts.BannerWait)
+	}
+	return r, true
+}
+
+// grab returns the first line conn sends, prompting it with an HTTP HEAD
+// request if it is silent for wait.
+func grab(ctx context.Context, conn net.Conn, host string, wait time.Duration) string {
+	// Unblock the read if ctx ends first.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	br := bufio.NewReaderSize(conn, 512)
+	conn.SetReadDeadline(time.Now().Add(wait))
+	line, err := readLine(br)
+	var nerr net.Error
+	if line == "" && errors.As(err, &nerr) && nerr.Timeout() && ctx.Err() == nil {
+		conn.SetDeadline(time.Now().Add(wait))
+		fmt.Fprintf(conn, "HEAD / HTTP/1.0\r\nHost: %s\r\n\r\n", host)
+		line, _ = readLine(br)
+	}
+	return line
+}
+
+// readLine reads up to the first newline or 256 bytes, whichever comes
+// first, and returns it with control characters removed.
+func readLine(br *bufio.Reader) (string, error) {
+	var b strings.Builder
+	for b.Len() < 256 {
+		c, err := br.ReadByte()
+		if err != nil {
+			return strings.TrimSpace(b.String()), err
+		}
+		if c == '\n' {
+			break
+		}
+		if c < 0x80 && unicode.IsPrint(rune(c)) {
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// ParsePorts parses a comma-separated list of ports and inclusive ranges,
+// such as "22,80,8000-8100", into a sorted list without duplicates.
+func ParsePorts(spec string) ([]int, error) {
+	var ports []int
+	for part := range strings.SplitSeq(spec, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := parsePort(lo)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parsePort(hi); err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, fmt.Errorf("scan: bad port range %q", part)
+			}
+		}
+		for p := first; p <= last; p++ {
+			ports = append(ports, p)
+		}
+	}
+	slices.Sort(ports)
+	return slices.Compact(ports), nil
+}
+
+func parsePort(s string) (int, error) {
+	p, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || p < 1 || p > 65535 {
+		return 0, fmt.Errorf("scan: bad port %q", s)
+	}
+	return p, nil
+}
+
+// services names the well-known ports.
+var services = map[int]string{
+	21:    "ftp",
+	22:    "ssh",
+	23:    "telnet",
+	25:    "smtp",
+	53:    "domain",
+	80:    "http",
+	110:   "pop3",
+	143:   "imap",
+	443:   "https",
+	465:   "smtps",
+	587:   "submission",
+	993:   "imaps",
+	995:   "pop3s",
+	1433:  "mssql",
+	3306:  "mysql",
+	3389:  "rdp",
+	5432:  "postgresql",
+	5672:  "amqp",
+	6379:  "redis",
+	8080:  "http-alt",
+	8443:  "https-alt",
+	9200:  "elasticsearch",
+	11211: "memcached",
+	27017: "mongodb",
+}
@@ -0,0 +1,128 @@
+package scan
+
+import "context"
+import "io"
+import "net"
+import "net/http"
+import "net/http/httptest"
+import "reflect"
+import "strconv"
+import "testing"
+import "time"
+
+// listen starts a TCP server on a free local port that writes greeting to
+// each connection, and returns the port.
+func listen(t *testing.T, greeting string) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			io.WriteString(c, greeting)
+			go func() { io.Copy(io.Discard, c); c.Close() }()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// closedPort returns a local port with nothing listening on it.
+func closedPort(t *testing.T) int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	return port
+}
+
+func TestScan(t *testing.T) {
+	ssh := listen(t, "SSH-2.0-OpenSSH_9.6\r\n")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	web, _ := strconv.Atoi(srv.URL[len("http://127.0.0.1:"):])
+	closed := closedPort(t)
+
+	opts := Options{Banner: true, BannerWait: 100 * time.Millisecond, Concurrency: 2}
+	got, err := Scan(context.Background(), "127.0.0.1", []int{closed, web, ssh}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Result{{Port: ssh, Banner: "SSH-2.0-OpenSSH_9.6"}, {Port: web, Banner: "HTTP/1.0 200 OK"}}
+	if want[0].Port > want[1].Port {
+		want[0], want[1] = want[1], want[0]
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() == %+v, want %+v", got, want)
+	}
+
+	got, _ = Scan(context.Background(), "127.0.0.1", []int{ssh}, Options{})
+	if len(got) != 1 || got[0].Banner != "" {
+		t.Errorf("Scan() without Banner == %+v", got)
+	}
+}
+
+func TestScanCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ports := make([]int, 1000)
+	for i := range ports {
+		ports[i] = i + 1
+	}
+	start := time.Now()
+	if _, err := Scan(ctx, "127.0.0.1", ports, Options{Concurrency: 1}); err != context.Canceled {
+		t.Errorf("Scan() with a cancelled context: err == %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("cancelled scan took %v", d)
+	}
+}
+
+func TestBannerCancel(t *testing.T) {
+	// A server that never speaks or replies.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer c.Close() // held open until the listener closes
+		}
+	}()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	r, ok := Probe(ctx, "127.0.0.1", port, Options{Banner: true, BannerWait: 10 * time.Second})
+	if !ok || r.Banner != "" {
+		t.Errorf("Probe() == %+v, %v", r, ok)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Probe() ignored cancellation, took %v", d)
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	got, err := ParsePorts("443, 20-22,80,21")
+	if want := []int{20, 21, 22, 80, 443}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsePorts() == %v, %v, want %v", got, err, want)
+	}
+	for _, bad := range []string{"", "0", "65536", "x", "10-5", "1-", "22,"} {
+		if _, err := ParsePorts(bad); err == nil {
+			t.Errorf("ParsePorts(%q) succeeded", bad)
+		}
+	}
+}
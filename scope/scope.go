@@ -0,0 +1,148 @@
+// Package scope runs goroutines that cannot outlive the code that starts
+// them. Tasks are started in a Scope, and Wait does not return until all
+// of them have; the first to fail cancels the rest, and a panic in any of
+// them is raised again by Wait, in the goroutine that waits, rather than
+// crashing the program from a goroutine nobody is watching.
+//
+//	s := scope.NewScope(ctx)
+//	for _, url := range urls {
+//		s.Go(func(ctx context.Context) error { return fetch(ctx, url) })
+//	}
+//	err := s.Wait()
+package scope
+
+import "context"
+import "fmt"
+import "runtime/debug"
+import "sync"
+import "time"
+
+// A PanicError is a panic in a task, raised again by Wait.
+type PanicError struct {
+	Value any
+	Stack []byte // of the goroutine that panicked
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("scope: task panicked: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// A Scope is a set of tasks that Wait waits for. Its methods may be called
+// from any goroutine, but Go must not be called once Wait has returned,
+// nor concurrently with Wait except by a task of the scope.
+type Scope struct {
+	parent *Scope
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error
+	panic  *PanicError
+	closed bool // whether Wait has returned
+}
+
+// NewScope returns a Scope whose tasks get a context derived from ctx.
+func NewScope(ctx context.Context) *Scope {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Scope{ctx: ctx, cancel: cancel}
+}
+
+// Nest returns a scope within s. Its tasks are cancelled with s, and s's
+// Wait waits for them too. A panic in one of them is raised by both
+// scopes' Wait, but an error is returned only by the nested scope's.
+func (s *Scope) Nest() *Scope {
+	child := NewScope(s.ctx)
+	child.parent = s
+	return child
+}
+
+// Context returns the context passed to s's tasks. It is cancelled when
+// a task fails, when Cancel is called, or when Wait returns.
+func (s *Scope) Context() context.Context { return s.ctx }
+
+// Cancel cancels s's context, asking its tasks to stop.
+func (s *Scope) Cancel() { s.cancel() }
+
+// Go starts fn in a new goroutine. If fn returns an error, or panics, s is
+// cancelled. It panics if s, or a scope it is nested in, has been waited
+// for.
+func (s *Scope) Go(fn func(ctx context.Context) error) {
+	s.start(s.ctx, nil, fn)
+}
+
+// GoTimeout is like Go but cancels fn's context after d, with a deadline
+// that applies to this task only.
+func (s *Scope) GoTimeout(d time.Duration, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(s.ctx, d)
+	s.start(ctx, cancel, fn)
+}
+
+func (s *Scope) start(ctx context.Context, cancel context.CancelFunc, fn func(context.Context) error) {
+	for p := s; p != nil; p = p.parent {
+		p.mu.Lock()
+		closed := p.closed
+		p.mu.Unlock()
+		if closed {
+			panic("scope: Go called after Wait")
+		}
+	}
+	for p := s; p != nil; p = p.parent {
+		p.wg.Add(1)
+	}
+	go func() {
+		defer func() {
+			if cancel != nil {
+				cancel()
+			}
+			if v := recover(); v != nil {
+				pe := &PanicError{Value: v, Stack: debug.Stack()}
+				for p := s; p != nil; p = p.parent {
+					p.fail(nil, pe)
+				}
+			}
+			for p := s; p != nil; p = p.parent {
+				p.wg.Done()
+			}
+		}()
+		if err := fn(ctx); err != nil {
+			s.fail(err, nil)
+		}
+	}()
+}
+
+// fail records the first error or panic and cancels s.
+func (s *Scope) fail(err error, pe *PanicError) {
+	s.mu.Lock()
+	if pe != nil && s.panic == nil {
+		s.panic = pe
+	}
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	s.mu.Unlock()
+	s.cancel()
+}
+
+// Wait waits for every task of s, and of scopes nested in it, to return.
+// If a task panicked, Wait panics with a *PanicError; otherwise it
+// returns the first error a task of s returned. Wait may be called more
+// than once.
+func (s *Scope) Wait() error {
+	s.wg.Wait()
+	s.mu.Lock()
+	s.closed = true
+	err, pe := s.err, s.panic
+	s.mu.Unlock()
+	s.cancel()
+	if pe != nil {
+		panic(pe)
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+package scope
+
+import "context"
+import "errors"
+import "sync/atomic"
+import "testing"
+import "time"
+
+func TestWait(t *testing.T) {
+	s := NewScope(context.Background())
+	var n atomic.Int32
+	for range 10 {
+		s.Go(func(context.Context) error {
+			time.Sleep(time.Millisecond)
+			n.Add(1)
+			return nil
+		})
+	}
+	if err := s.Wait(); err != nil || n.Load() != 10 {
+		t.Fatalf("Wait = %v after %d tasks", err, n.Load())
+	}
+	if s.Context().Err() == nil {
+		t.Error("context not cancelled after Wait")
+	}
+}
+
+func TestErrorCancels(t *testing.T) {
+	s := NewScope(context.Background())
+	boom := errors.New("boom")
+	s.Go(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.Go(func(context.Context) error { return boom })
+	if err := s.Wait(); !errors.Is(err, boom) {
+		t.Errorf("Wait = %v, want boom", err)
+	}
+}
+
+func TestGoTimeout(t *testing.T) {
+	s := NewScope(context.Background())
+	s.GoTimeout(time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if err := s.Wait(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait = %v", err)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	s := NewScope(context.Background())
+	s.Go(func(context.Context) error { panic("boom") })
+	defer func() {
+		pe, ok := recover().(*PanicError)
+		if !ok || pe.Value != "boom" || len(pe.Stack) == 0 {
+			t.Errorf("recovered %v", pe)
+		}
+	}()
+	s.Wait()
+}
+
+func TestNest(t *testing.T) {
+	parent := NewScope(context.Background())
+	child := parent.Nest()
+	boom := errors.New("boom")
+	var finished atomic.Bool
+	child.Go(func(context.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		finished.Store(true)
+		return boom
+	})
+	if err := parent.Wait(); err != nil || !finished.Load() {
+		t.Errorf("parent Wait = %v before the nested task finished: %v", err, !finished.Load())
+	}
+	if err := child.Wait(); !errors.Is(err, boom) {
+		t.Errorf("child Wait = %v", err)
+	}
+
+	parent = NewScope(context.Background())
+	parent.Nest().Go(func(context.Context) error { panic("deep") })
+	defer func() {
+		if pe, ok := recover().(*PanicError); !ok || pe.Value != "deep" {
+			t.Errorf("recovered %v", pe)
+		}
+	}()
+	parent.Wait()
+}
+
+func TestGoAfterWait(t *testing.T) {
+	s := NewScope(context.Background())
+	s.Wait()
+	defer func() {
+		if recover() == nil {
+			t.Error("Go after Wait did not panic")
+		}
+	}()
+	s.Nest().Go(func(context.Context) error { return nil })
+}
@@ -0,0 +1,319 @@
+// Package seed loads fixtures from YAML or JSON files into a key/value
+// store, resolving references between fixtures and filling in fake data.
+//
+// Each fixture is a record of fields. Two keys are special:
+//
+//   - _key, required, is the key the fixture is stored under.
+//   - _name, optional, names the fixture for references; it defaults to
+//     the _key as written.
+//
+// A field whose value is the string "@name" is replaced by the key of the
+// fixture called name, and "@name.field" by that fixture's field. String
+// values may also be text/template templates calling the generators of
+// the fake package, as in "{{email}}", and the template function ref,
+// as in "{{ref "ann" "email"}}". A value consisting of a single action,
+// such as "{{int 18 90}}", becomes a number or boolean if its output
+// parses as one.
+//
+// Fake data is deterministic: the same file and seed always produce the
+// same fixtures, and each fixture's values depend only on its position in
+// the file, not on the order in which references are resolved.
+package seed
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "fmt"
+import "io"
+import "os"
+import "strconv"
+import "strings"
+import "text/template"
+
+import "github.com/lukehedger/golib/convert"
+import "github.com/lukehedger/golib/fake"
+import "github.com/lukehedger/golib/kv"
+
+// A Fixture is a resolved record, ready to be stored.
+type Fixture struct {
+	Key    string
+	Fields convert.Record // without the special _key and _name fields
+}
+
+// MarshalJSON encodes f's fields as a JSON object, in order.
+func (f Fixture) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, fl := range f.Fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, _ := json.Marshal(fl.Key)
+		v, err := json.Marshal(fl.Value)
+		if err != nil {
+			return nil, fmt.Errorf("seed: %s.%s: %w", f.Key, fl.Key, err)
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// A Sink stores fixtures.
+type Sink interface {
+	Put(key string, value []byte) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(key string, value []byte) error
+
+// Put calls f(key, value).
+func (f SinkFunc) Put(key string, value []byte) error { return f(key, value) }
+
+// KV returns a Sink that stores fixtures in s.
+func KV(s *kv.Store) Sink {
+	return SinkFunc(func(key string, value []byte) error {
+		s.Put(key, value)
+		return nil
+	})
+}
+
+// Apply stores each fixture in sink as a JSON object, in order.
+func Apply(sink Sink, fixtures []Fixture) error {
+	for _, f := range fixtures {
+		data, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		if err := sink.Put(f.Key, data); err != nil {
+			return fmt.Errorf("seed: %s: %w", f.Key, err)
+		}
+	}
+	return nil
+}
+
+// Load reads fixtures in format f and resolves them with fake data from
+// seed.
+func Load(r io.Reader, f convert.Format, seed uint64) ([]Fixture, error) {
+	rd, err := convert.NewReader(r, f, convert.Options{Infer: true})
+	if err != nil {
+		return nil, err
+	}
+	var recs []convert.Record
+	for {
+		rec, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return Resolve(recs, seed)
+}
+
+// LoadFile reads fixtures from the named file, choosing the format from
+// its extension.
+func LoadFile(name string, seed uint64) ([]Fixture, error) {
+	f, err := convert.FormatFromFilename(name)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Load(file, f, seed)
+}
+
+// Resolve turns raw records into fixtures, as described in the package
+// documentation.
+func Resolve(recs []convert.Record, seed uint64) ([]Fixture, error) {
+	r := &resolver{
+		recs:   recs,
+		out:    make([]Fixture, len(recs)),
+		state:  make([]int, len(recs)),
+		names:  map[string]int{},
+		labels: make([]string, len(recs)),
+		seed:   seed,
+	}
+	for i, rec := range recs {
+		key, ok := rec.Get("_key")
+		if !ok {
+			return nil, fmt.Errorf("seed: fixture %d: no _key", i+1)
+		}
+		name, ok := rec.Get("_name")
+		if !ok {
+			name = key
+		}
+		s, ok := name.(string)
+		if !ok {
+			return nil, fmt.Errorf("seed: fixture %d: _name must be a string", i+1)
+		}
+		if _, dup := r.names[s]; dup {
+			return nil, fmt.Errorf("seed: fixture %d: duplicate name %q", i+1, s)
+		}
+		r.names[s] = i
+		r.labels[i] = strconv.Quote(s)
+	}
+	for i := range recs {
+		if err := r.resolve(i); err != nil {
+			return nil, fmt.Errorf("seed: %w", err)
+		}
+	}
+	return r.out, nil
+}
+
+const (
+	unresolved = iota
+	resolving
+	resolved
+)
+
+type resolver struct {
+	recs   []convert.Record
+	out    []Fixture
+	state  []int
+	names  map[string]int
+	labels []string // quoted fixture names, for errors
+	seed   uint64
+}
+
+var errCycle = errors.New("reference cycle")
+
+func (r *resolver) resolve(i int) error {
+	switch r.state[i] {
+	case resolved:
+		return nil
+	case resolving:
+		return fmt.Errorf("fixture %s: %w", r.labels[i], errCycle)
+	}
+	r.state[i] = resolving
+
+	// Each fixture gets its own generator, so its fake values do not
+	// depend on the order in which fixtures are resolved.
+	f := fake.New(r.seed + uint64(i)*0x9e3779b97f4a7c15)
+	funcs := f.Funcs()
+	funcs["ref"] = func(name string, field ...string) (any, error) {
+		return r.ref(name, field...)
+	}
+
+	var fx Fixture
+	for _, fl := range r.recs[i] {
+		v, err := r.value(fl.Value, funcs)
+		if err != nil {
+			return fmt.Errorf("fixture %s: %s: %w", r.labels[i], fl.Key, err)
+		}
+		switch fl.Key {
+		case "_key":
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("fixture %s: _key must be a string", r.labels[i])
+			}
+			fx.Key = s
+		case "_name":
+		default:
+			fx.Fields = append(fx.Fields, convert.Field{Key: fl.Key, Value: v})
+		}
+	}
+	r.out[i] = fx
+	r.state[i] = resolved
+	return nil
+}
+
+// ref returns the key of the named fixture, or one of its fields.
+func (r *resolver) ref(name string, field ...string) (any, error) {
+	j, ok := r.names[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fixture %q", name)
+	}
+	if err := r.resolve(j); err != nil {
+		return nil, err
+	}
+	if len(field) == 0 {
+		return r.out[j].Key, nil
+	}
+	v, ok := r.out[j].Fields.Get(field[0])
+	if !ok {
+		return nil, fmt.Errorf("fixture %q has no field %q", name, field[0])
+	}
+	return v, nil
+}
+
+// value resolves references and templates within v.
+func (r *resolver) value(v any, funcs template.FuncMap) (any, error) {
+	switch v := v.(type) {
+	case string:
+		if rest, ok := strings.CutPrefix(v, "@"); ok && !strings.HasPrefix(rest, "@") {
+			// Names may contain dots, so prefer a whole-name match.
+			if _, ok := r.names[rest]; ok {
+				return r.ref(rest)
+			}
+			if i := strings.LastIndex(rest, "."); i > 0 {
+				return r.ref(rest[:i], rest[i+1:])
+			}
+			return r.ref(rest)
+		}
+		if strings.HasPrefix(v, "@@") {
+			return v[1:], nil
+		}
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		t, err := template.New("").Funcs(funcs).Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, nil); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(v, "{{") && strings.HasSuffix(v, "}}") && strings.Count(v, "{{") == 1 {
+			return infer(b.String()), nil
+		}
+		return b.String(), nil
+	case []any:
+		out := make([]any, len(v))
+		for i, x := range v {
+			y, err := r.value(x, funcs)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = y
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, x := range v {
+			y, err := r.value(x, funcs)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = y
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// infer converts the output of a single template action to a number or
+// boolean where it parses as one.
+func infer(s string) any {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
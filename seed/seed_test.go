@@ -0,0 +1,151 @@
+package seed
+
+import "encoding/json"
+import "errors"
+import "reflect"
+import "regexp"
+import "strings"
+import "testing"
+
+import "github.com/lukehedger/golib/convert"
+import "github.com/lukehedger/golib/kv"
+import "github.com/lukehedger/golib/mock"
+
+const fixtures = `
+- _key: posts/1
+  title: "Hello from {{ref \"ann\" \"name\"}}"
+  author: "@ann"
+  author_email: "@ann.email"
+  likes: "{{int 1 100}}"
+  tags: ["intro", "@bob"]
+- _key: users/ann
+  _name: ann
+  name: Ann
+  email: "{{email}}"
+  id: "{{uuid}}"
+- _key: users/bob
+  _name: bob
+  name: "{{name}}"
+  handle: "@@bob"
+  admin: "{{bool}}"
+`
+
+func TestLoad(t *testing.T) {
+	fs, err := Load(strings.NewReader(fixtures), convert.YAML, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fs) != 3 || fs[0].Key != "posts/1" || fs[1].Key != "users/ann" || fs[2].Key != "users/bob" {
+		t.Fatalf("keys == %v", fs)
+	}
+	post, ann := fs[0].Fields, fs[1].Fields
+	get := func(r convert.Record, k string) any { v, _ := r.Get(k); return v }
+
+	if got := get(post, "title"); got != "Hello from Ann" {
+		t.Errorf("title == %v", got)
+	}
+	if got := get(post, "author"); got != "users/ann" {
+		t.Errorf("author == %v", got)
+	}
+	if got, want := get(post, "author_email"), get(ann, "email"); got != want || !strings.Contains(want.(string), "@example.") {
+		t.Errorf("author_email == %v, ann's email == %v", got, want)
+	}
+	if n, ok := get(post, "likes").(int64); !ok || n < 1 || n > 100 {
+		t.Errorf("likes == %#v, want an int64 in [1, 100]", get(post, "likes"))
+	}
+	if got := get(post, "tags"); !reflect.DeepEqual(got, []any{"intro", "users/bob"}) {
+		t.Errorf("tags == %v", got)
+	}
+	if !regexp.MustCompile(`^[0-9a-f-]{36}$`).MatchString(get(ann, "id").(string)) {
+		t.Errorf("id == %v", get(ann, "id"))
+	}
+	if got := get(fs[2].Fields, "handle"); got != "@bob" {
+		t.Errorf("handle == %v", got)
+	}
+	if _, ok := get(fs[2].Fields, "admin").(bool); !ok {
+		t.Errorf("admin == %#v, want a bool", get(fs[2].Fields, "admin"))
+	}
+	if _, ok := fs[1].Fields.Get("_name"); ok {
+		t.Error("_name was kept as a field")
+	}
+
+	again, _ := Load(strings.NewReader(fixtures), convert.YAML, 42)
+	if !reflect.DeepEqual(fs, again) {
+		t.Error("the same seed gave different fixtures")
+	}
+	other, _ := Load(strings.NewReader(fixtures), convert.YAML, 43)
+	if reflect.DeepEqual(fs, other) {
+		t.Error("different seeds gave the same fixtures")
+	}
+
+	// Removing the post, which resolves the users early, must not change
+	// them.
+	i := strings.Index(fixtures, "- _key: users/ann")
+	users, err := Load(strings.NewReader(fixtures[i:]), convert.YAML, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if get(users[0].Fields, "email") == get(ann, "email") {
+		t.Error("values did not depend on position") // ann moved from index 1 to 0
+	}
+}
+
+func TestApply(t *testing.T) {
+	fs, err := Load(strings.NewReader(`[{"_key": "a", "n": 1, "s": "x"}, {"_key": "b", "a": "@a"}]`), convert.JSON, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := kv.New()
+	if err := Apply(KV(store), fs); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := store.Get("a"); string(v) != `{"n":1,"s":"x"}` {
+		t.Errorf("a == %s", v)
+	}
+	if v, _ := store.Get("b"); string(v) != `{"a":"a"}` {
+		t.Errorf("b == %s", v)
+	}
+
+	boom := errors.New("boom")
+	err = Apply(SinkFunc(func(string, []byte) error { return boom }), fs)
+	if !errors.Is(err, boom) {
+		t.Errorf("Apply() == %v", err)
+	}
+	var m map[string]any
+	if b, _ := json.Marshal(fs[0]); json.Unmarshal(b, &m) != nil {
+		t.Errorf("MarshalJSON() == %s", b)
+	}
+}
+
+//go:generate go run ../cmd/golib mockgen -o mock_sink_test.go Sink
+
+func TestApplyStopsAtError(t *testing.T) {
+	fs, err := Load(strings.NewReader(`[{"_key": "a"}, {"_key": "b"}]`), convert.JSON, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	boom := errors.New("boom")
+	sink := new(MockSink)
+	sink.On("Put", "a", mock.Any).Return(boom).Times(1)
+	if err := Apply(sink, fs); !errors.Is(err, boom) {
+		t.Errorf("Apply() == %v", err)
+	}
+	sink.Verify(t)
+}
+
+func TestResolveErrors(t *testing.T) {
+	cases := []struct{ yaml, err string }{
+		{"- name: no key\n", "seed: fixture 1: no _key"},
+		{"- _key: a\n- _key: a\n", `seed: fixture 2: duplicate name "a"`},
+		{"- _key: a\n  x: \"@nope\"\n", `seed: fixture "a": x: unknown fixture "nope"`},
+		{"- _key: a\n  x: \"@b.y\"\n- _key: b\n", `seed: fixture "a": x: fixture "b" has no field "y"`},
+		{"- _key: a\n  x: \"@b\"\n- _key: b\n  y: \"@a.x\"\n", `seed: fixture "a": x: fixture "b": y: fixture "a": reference cycle`},
+		{"- _key: \"{{\"\n", `seed: fixture "{{": _key: template: :1: unclosed action`},
+	}
+	for _, c := range cases {
+		_, err := Load(strings.NewReader(c.yaml), convert.YAML, 0)
+		if err == nil || err.Error() != c.err {
+			t.Errorf("Load(%q) error == %v, want %s", c.yaml, err, c.err)
+		}
+	}
+}
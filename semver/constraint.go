@@ -0,0 +1,211 @@
+package semver
+
+import "fmt"
+import "strings"
+
+// A Constraint is a set of versions, such as "^1.2" or ">=1.0 <2.0". It
+// is a list of alternatives separated by "||", each of which is a list of
+// comparisons, separated by spaces or commas, that must all hold. The
+// comparisons are:
+//
+//	1.2.3  =1.2.3   exactly 1.2.3
+//	!=1.2.3         anything but 1.2.3
+//	>1.2.3  >=1.2.3  <1.2.3  <=1.2.3
+//	~1.2.3          >=1.2.3 <1.3.0: patch releases
+//	^1.2.3          >=1.2.3 <2.0.0: releases compatible by semver, which
+//	                below 1.0.0 means ^0.2.3 is <0.3.0 and ^0.0.3 is <0.0.4
+//	1.2.x  1.2  1.*  *   wildcards
+//
+// Versions may be partial, as in "^1.2" or ">=1", with missing numbers
+// standing for any: "<=1.2" is "<1.3.0" and ">1" is ">=2.0.0".
+//
+// As with npm, a prerelease matches only an alternative that names a
+// prerelease of the same major.minor.patch: ">=1.2.3-beta" matches
+// 1.2.3-rc.1 but not 1.3.0-rc.1, and "^1.2" matches neither, for a
+// prerelease of an unrelated version should not be picked up by accident.
+type Constraint struct {
+	text string
+	alts [][]comparison
+}
+
+type comparison struct {
+	op string // "=", "!=", ">", ">=", "<" or "<="
+	v  Version
+}
+
+var ops = []string{"!=", ">=", "<=", ">", "<", "=", "~", "^"}
+
+// ParseConstraint parses a constraint.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{text: strings.TrimSpace(s)}
+	for _, alt := range strings.Split(s, "||") {
+		var cmps []comparison
+		fields := strings.FieldsFunc(alt, func(r rune) bool { return r == ' ' || r == '\t' || r == ',' })
+		if len(fields) == 0 {
+			fields = []string{"*"}
+		}
+		for i := 0; i < len(fields); i++ {
+			f := fields[i]
+			// Allow a space between an operator and its version.
+			if isOp(f) && i+1 < len(fields) {
+				i++
+				f += fields[i]
+			}
+			cs, err := parseComparison(f)
+			if err != nil {
+				return nil, fmt.Errorf("semver: constraint %q: %w", s, err)
+			}
+			cmps = append(cmps, cs...)
+		}
+		c.alts = append(c.alts, cmps)
+	}
+	return c, nil
+}
+
+// MustParseConstraint is like ParseConstraint but panics if s is not a
+// constraint.
+func MustParseConstraint(s string) *Constraint {
+	c, err := ParseConstraint(s)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func isOp(s string) bool {
+	for _, op := range ops {
+		if s == op {
+			return true
+		}
+	}
+	return false
+}
+
+// parseComparison turns one comparison into the plain comparisons it
+// stands for.
+func parseComparison(s string) ([]comparison, error) {
+	op := ""
+	for _, o := range ops {
+		if strings.HasPrefix(s, o) {
+			op, s = o, s[len(o):]
+			break
+		}
+	}
+	v, n, err := parse(strings.TrimPrefix(s, "v"), true)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %s", s, err)
+	}
+	if n == 3 {
+		switch op {
+		case "", "=":
+			return []comparison{{"=", v}}, nil
+		case "~":
+			return []comparison{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor + 1}}}, nil
+		case "^":
+			return []comparison{{">=", v}, {"<", caretLimit(v, n)}}, nil
+		}
+		return []comparison{{op, v}}, nil
+	}
+
+	// A partial version is the range from lo up to, but not including, hi.
+	lo, hi := Version{Major: v.Major, Minor: v.Minor}, Version{Major: v.Major + 1}
+	if n == 2 {
+		hi = Version{Major: v.Major, Minor: v.Minor + 1}
+	}
+	none := []comparison{{"<", Version{}}}
+	if n == 0 {
+		switch op {
+		case ">", "<", "!=":
+			return none, nil
+		}
+		return nil, nil
+	}
+	switch op {
+	case "", "=":
+		return []comparison{{">=", lo}, {"<", hi}}, nil
+	case "!=":
+		return nil, fmt.Errorf("%q: != needs a full version", s)
+	case ">":
+		return []comparison{{">=", hi}}, nil
+	case ">=":
+		return []comparison{{">=", lo}}, nil
+	case "<":
+		return []comparison{{"<", lo}}, nil
+	case "<=":
+		return []comparison{{"<", hi}}, nil
+	case "~":
+		return []comparison{{">=", lo}, {"<", hi}}, nil
+	}
+	return []comparison{{">=", lo}, {"<", caretLimit(v, n)}}, nil // ^
+}
+
+// caretLimit returns the first version incompatible with v, of which n
+// numbers were given: the next major, or below 1.0.0 the next number
+// after the first non-zero one given.
+func caretLimit(v Version, n int) Version {
+	switch {
+	case v.Major > 0 || n == 1:
+		return Version{Major: v.Major + 1}
+	case v.Minor > 0 || n == 2:
+		return Version{Minor: v.Minor + 1}
+	}
+	return Version{Patch: v.Patch + 1}
+}
+
+func (c comparison) holds(v Version) bool {
+	r := v.Compare(c.v)
+	switch c.op {
+	case "=":
+		return r == 0
+	case "!=":
+		return r != 0
+	case ">":
+		return r > 0
+	case ">=":
+		return r >= 0
+	case "<":
+		return r < 0
+	}
+	return r <= 0 // <=
+}
+
+// Check reports whether v is in c.
+func (c *Constraint) Check(v Version) bool {
+	for _, alt := range c.alts {
+		if allHold(alt, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func allHold(alt []comparison, v Version) bool {
+	for _, cmp := range alt {
+		if !cmp.holds(v) {
+			return false
+		}
+	}
+	if v.Prerelease == "" {
+		return true
+	}
+	for _, cmp := range alt {
+		if cmp.v.Prerelease != "" && cmpNums(cmp.v, v) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the versions in vs that are in c, in their order.
+func (c *Constraint) Filter(vs []Version) []Version {
+	var in []Version
+	for _, v := range vs {
+		if c.Check(v) {
+			in = append(in, v)
+		}
+	}
+	return in
+}
+
+// String returns the constraint as it was written.
+func (c *Constraint) String() string { return c.text }
@@ -0,0 +1,79 @@
+package semver
+
+import "slices"
+import "testing"
+
+func TestConstraint(t *testing.T) {
+	cases := []struct {
+		c       string
+		in, out []string
+	}{
+		{"1.2.3", []string{"1.2.3", "1.2.3+meta"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{"!=1.2.3", []string{"1.2.4", "0.1.0"}, []string{"1.2.3"}},
+		{">1.2.3", []string{"1.2.4", "2.0.0"}, []string{"1.2.3", "1.0.0"}},
+		{"<=1.2.3", []string{"1.2.3", "0.0.1"}, []string{"1.2.4"}},
+		{"^1.2", []string{"1.2.0", "1.9.9"}, []string{"1.1.9", "2.0.0", "2.0.0-rc.1", "1.5.0-beta"}},
+		{"^1.2.3", []string{"1.2.3", "1.99.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^0", []string{"0.0.0", "0.9.0"}, []string{"1.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">=1.0 <2.0", []string{"1.0.0", "1.9.9"}, []string{"0.9.9", "2.0.0"}},
+		{">= 1.0, < 2", []string{"1.5.0"}, []string{"2.0.0"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+		{">1", []string{"2.0.0"}, []string{"1.9.9"}},
+		{"<1.2", []string{"1.1.9"}, []string{"1.2.0"}},
+		{"1.2.x", []string{"1.2.0", "1.2.7"}, []string{"1.3.0", "1.1.0"}},
+		{"1.*", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"*", []string{"0.0.0", "9.9.9"}, []string{"1.0.0-rc.1"}},
+		{"", []string{"1.0.0"}, nil},
+		{"^1.0 || ^3.0", []string{"1.1.0", "3.1.0"}, []string{"2.0.0"}},
+		{">=1.2.3-beta", []string{"1.2.3-beta", "1.2.3-rc.1", "1.2.3", "1.3.0"}, []string{"1.2.3-alpha", "1.3.0-rc.1"}},
+		{">=1.0.0-rc.1 <2.0.0", []string{"1.0.0-rc.2"}, []string{"2.0.0-rc.1"}},
+		{"v1.2.3", []string{"1.2.3"}, nil},
+	}
+	for _, c := range cases {
+		con, err := ParseConstraint(c.c)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", c.c, err)
+			continue
+		}
+		for _, s := range c.in {
+			if !con.Check(MustParse(s)) {
+				t.Errorf("%q does not match %s", c.c, s)
+			}
+		}
+		for _, s := range c.out {
+			if con.Check(MustParse(s)) {
+				t.Errorf("%q matches %s", c.c, s)
+			}
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, s := range []string{">=", "1.2.3.4", "^x.1", "!=1.2", "1.2-rc", "~>1.2", "1.*.3", ">=1.0 <"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded", s)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	var vs []Version
+	for _, s := range []string{"2.1.0", "1.0.0", "1.4.2", "1.5.0-rc.1", "0.9.0"} {
+		vs = append(vs, MustParse(s))
+	}
+	c := MustParseConstraint("^1.0")
+	got := c.Filter(vs)
+	want := []Version{MustParse("1.0.0"), MustParse("1.4.2")}
+	if !slices.Equal(got, want) {
+		t.Errorf("Filter = %v", got)
+	}
+	if c.String() != "^1.0" {
+		t.Errorf("String = %q", c)
+	}
+}
@@ -0,0 +1,225 @@
+// Package semver parses, compares and sorts semantic versions, as
+// specified at https://semver.org, and matches them against constraints
+// in the syntax of npm and Cargo, such as "^1.2" or ">=1.0 <2.0".
+package semver
+
+import "errors"
+import "fmt"
+import "slices"
+import "strconv"
+import "strings"
+
+// A Version is a semantic version. The zero value is 0.0.0. Versions are
+// comparable with ==, but that also compares Build, which precedence
+// ignores: use Compare to order them.
+type Version struct {
+	Major, Minor, Patch uint64
+	// Prerelease is the dot-separated identifiers after "-", such as
+	// "rc.1", or empty for a release.
+	Prerelease string
+	// Build is the dot-separated metadata after "+", such as "exp.sha.5114f85".
+	Build string
+}
+
+// ErrInvalid is returned by Parse for a string that is not a version.
+var ErrInvalid = errors.New("semver: invalid version")
+
+// Parse parses a version such as "1.2.3-rc.1+meta". A leading "v", as in
+// Git tags, is allowed. All three numbers are required.
+func Parse(s string) (Version, error) {
+	v, n, err := parse(strings.TrimPrefix(s, "v"), false)
+	if err != nil {
+		return Version{}, fmt.Errorf("%w %q: %s", ErrInvalid, s, err)
+	}
+	if n < 3 {
+		return Version{}, fmt.Errorf("%w %q: want major.minor.patch", ErrInvalid, s)
+	}
+	return v, nil
+}
+
+// MustParse is like Parse but panics if s is not a version. It is for
+// versions written in code.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// parse parses a version of which, if partial is true, only a prefix of
+// the numbers need be given, ending early or with x, X or *. It returns
+// the number of numbers given. A prerelease or build needs all three.
+func parse(s string, partial bool) (v Version, n int, err error) {
+	var hasBuild, hasPre bool
+	s, v.Build, hasBuild = strings.Cut(s, "+")
+	s, v.Prerelease, hasPre = strings.Cut(s, "-")
+	if hasBuild && v.Build == "" || hasPre && v.Prerelease == "" {
+		return Version{}, 0, errors.New("empty prerelease or build")
+	}
+	nums := strings.Split(s, ".")
+	if len(nums) > 3 {
+		return Version{}, 0, errors.New("more than three numbers")
+	}
+	fields := [3]*uint64{&v.Major, &v.Minor, &v.Patch}
+	for i, num := range nums {
+		if partial && (num == "x" || num == "X" || num == "*") {
+			if i < len(nums)-1 {
+				return Version{}, 0, errors.New("a wildcard must come last")
+			}
+			break
+		}
+		if !isNumber(num) {
+			return Version{}, 0, fmt.Errorf("bad number %q", num)
+		}
+		if *fields[i], err = strconv.ParseUint(num, 10, 64); err != nil {
+			return Version{}, 0, fmt.Errorf("number %s out of range", num)
+		}
+		n++
+	}
+	if n < 3 && (v.Prerelease != "" || v.Build != "") {
+		return Version{}, 0, errors.New("prerelease or build on a partial version")
+	}
+	if err := checkIdents(v.Prerelease, true); err != nil {
+		return Version{}, 0, fmt.Errorf("prerelease: %s", err)
+	}
+	if err := checkIdents(v.Build, false); err != nil {
+		return Version{}, 0, fmt.Errorf("build: %s", err)
+	}
+	return v, n, nil
+}
+
+// isNumber reports whether s is a number without leading zeros.
+func isNumber(s string) bool {
+	if s == "" || len(s) > 1 && s[0] == '0' {
+		return false
+	}
+	for i := range len(s) {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// checkIdents checks the dot-separated identifiers of a prerelease or
+// build, which are non-empty runs of [0-9A-Za-z-]. Numeric prerelease
+// identifiers may not have leading zeros.
+func checkIdents(s string, prerelease bool) error {
+	if s == "" {
+		return nil
+	}
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return errors.New("empty identifier")
+		}
+		numeric := true
+		for i := range len(id) {
+			c := id[i]
+			switch {
+			case '0' <= c && c <= '9':
+			case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', c == '-':
+				numeric = false
+			default:
+				return fmt.Errorf("bad character %q", c)
+			}
+		}
+		if prerelease && numeric && !isNumber(id) {
+			return fmt.Errorf("leading zero in %q", id)
+		}
+	}
+	return nil
+}
+
+// String returns v in its canonical form, without a "v".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 as v has lower, equal or higher precedence
+// than w. A prerelease comes before its release, and prereleases compare
+// identifier by identifier, numbers numerically and below words. Build
+// metadata is ignored.
+func (v Version) Compare(w Version) int {
+	if c := cmpNums(v, w); c != 0 {
+		return c
+	}
+	switch {
+	case v.Prerelease == w.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return +1
+	case w.Prerelease == "":
+		return -1
+	}
+	a, b := strings.Split(v.Prerelease, "."), strings.Split(w.Prerelease, ".")
+	for i := range min(len(a), len(b)) {
+		if c := cmpIdent(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return cmpInt(len(a), len(b))
+}
+
+func cmpNums(v, w Version) int {
+	if c := cmpInt(v.Major, w.Major); c != 0 {
+		return c
+	}
+	if c := cmpInt(v.Minor, w.Minor); c != 0 {
+		return c
+	}
+	return cmpInt(v.Patch, w.Patch)
+}
+
+func cmpIdent(a, b string) int {
+	an, bn := isNumber(a), isNumber(b)
+	switch {
+	case an && bn: // without leading zeros, longer is larger
+		if c := cmpInt(len(a), len(b)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return +1
+	}
+	return strings.Compare(a, b)
+}
+
+func cmpInt[T int | uint64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return +1
+	}
+	return 0
+}
+
+// Less reports whether v has lower precedence than w.
+func (v Version) Less(w Version) bool { return v.Compare(w) < 0 }
+
+// Sort sorts vs in ascending order of precedence. Versions of equal
+// precedence keep their order.
+func Sort(vs []Version) { slices.SortStableFunc(vs, Version.Compare) }
+
+// MarshalText implements encoding.TextMarshaler, and so JSON.
+func (v Version) MarshalText() ([]byte, error) { return []byte(v.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler, and so JSON.
+func (v *Version) UnmarshalText(b []byte) error {
+	w, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*v = w
+	return nil
+}
@@ -0,0 +1,78 @@
+package semver
+
+import "encoding/json"
+import "errors"
+import "math/rand/v2"
+import "slices"
+import "testing"
+
+func TestParse(t *testing.T) {
+	v, err := Parse("v1.2.3-rc.1+build.5")
+	want := Version{1, 2, 3, "rc.1", "build.5"}
+	if v != want || err != nil {
+		t.Fatalf("Parse = %+v, %v", v, err)
+	}
+	if v.String() != "1.2.3-rc.1+build.5" {
+		t.Errorf("String = %s", v)
+	}
+	for _, s := range []string{"0.0.0", "1.0.0-alpha-1", "1.0.0+20130313144700", "10.20.30-0.3.7", "1.0.0-x.7.z.92"} {
+		if v, err := Parse(s); err != nil || v.String() != s {
+			t.Errorf("Parse(%q) = %s, %v", s, v, err)
+		}
+	}
+	bad := []string{"", "1", "1.2", "1.2.3.4", "01.2.3", "1.02.3", "1.2.x", "1.2.3-", "1.2.3-rc..1",
+		"1.2.3-01", "1.2.3+", "1.2.3-rc_1", "a.b.c", "18446744073709551616.0.0"}
+	for _, s := range bad {
+		if _, err := Parse(s); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error %v", s, err)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// In ascending order, from the semver specification.
+	order := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "1.10.0", "2.0.0"}
+	for i := range order {
+		for j := range order {
+			a, b := MustParse(order[i]), MustParse(order[j])
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = +1
+			}
+			if got := a.Compare(b); got != want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+	if MustParse("1.0.0+a").Compare(MustParse("1.0.0+b")) != 0 {
+		t.Error("build metadata compared")
+	}
+
+	vs := make([]Version, len(order))
+	for i, s := range order {
+		vs[i] = MustParse(s)
+	}
+	shuffled := slices.Clone(vs)
+	rand.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	Sort(shuffled)
+	if !slices.Equal(shuffled, vs) {
+		t.Errorf("Sort = %v", shuffled)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	b, err := json.Marshal(map[string]Version{"v": MustParse("1.2.3-rc.1")})
+	if err != nil || string(b) != `{"v":"1.2.3-rc.1"}` {
+		t.Fatalf("Marshal = %s, %v", b, err)
+	}
+	var m map[string]Version
+	if err := json.Unmarshal(b, &m); err != nil || m["v"] != MustParse("1.2.3-rc.1") {
+		t.Errorf("Unmarshal = %v, %v", m, err)
+	}
+	if err := json.Unmarshal([]byte(`{"v":"1.2"}`), &m); err == nil {
+		t.Error("partial version unmarshalled")
+	}
+}
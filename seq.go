@@ -0,0 +1,99 @@
+package golib
+
+import "iter"
+import "math"
+
+// Naturals yields 0, 1, 2, ... up to math.MaxInt.
+func Naturals() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			if !yield(i) || i == math.MaxInt {
+				return
+			}
+		}
+	}
+}
+
+// Fibonacci yields the Fibonacci numbers 0, 1, 1, 2, 3, 5, ... It stops
+// before the first term that would overflow an int.
+func Fibonacci() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		a, b := 0, 1
+		for {
+			if !yield(a) {
+				return
+			}
+			if b < a {
+				return // b wrapped around
+			}
+			a, b = b, a+b
+		}
+	}
+}
+
+// Primes yields the prime numbers in increasing order.
+func Primes() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for p := uint64(2); p != 0 && p <= math.MaxInt; p = NextPrime(p) {
+			if !yield(int(p)) {
+				return
+			}
+		}
+	}
+}
+
+// Take yields at most the first n values of seq.
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			if i++; i == n {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile yields values of seq for as long as keep returns true.
+func TakeWhile[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if !keep(v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate yields each value of seq with its index, counting from zero.
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// ZipSeq yields the values of a and b in step, stopping when either ends.
+func ZipSeq[A, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		next, stop := iter.Pull(b)
+		defer stop()
+		for x := range a {
+			y, ok := next()
+			if !ok || !yield(x, y) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package golib
+
+import "fmt"
+import "math"
+import "reflect"
+import "slices"
+import "testing"
+
+func TestSequences(t *testing.T) {
+	cases := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Naturals", slices.Collect(Take(Naturals(), 5)), []int{0, 1, 2, 3, 4}},
+		{"Fibonacci", slices.Collect(Take(Fibonacci(), 10)), []int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34}},
+		{"Primes", slices.Collect(Take(Primes(), 8)), []int{2, 3, 5, 7, 11, 13, 17, 19}},
+		{"TakeWhile", slices.Collect(TakeWhile(Fibonacci(), func(n int) bool { return n < 100 })),
+			[]int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89}},
+		{"Take zero", slices.Collect(Take(Naturals(), 0)), nil},
+	}
+	for _, c := range cases {
+		if !slices.Equal(c.got, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+
+	// Fibonacci ends rather than overflowing.
+	var last int
+	n := 0
+	for f := range Fibonacci() {
+		last = f
+		n++
+	}
+	if math.MaxInt == math.MaxInt64 && (n != 93 || last != 7540113804746346429) {
+		t.Errorf("Fibonacci ended after %d terms at %d", n, last)
+	}
+}
+
+func ExampleTakeWhile() {
+	for n := range TakeWhile(Primes(), func(p int) bool { return p < 20 }) {
+		fmt.Print(n, " ")
+	}
+	// Output: 2 3 5 7 11 13 17 19
+}
+
+func TestEnumerate(t *testing.T) {
+	var got []Pair[int, string]
+	for i, s := range Enumerate(slices.Values([]string{"x", "y", "z"})) {
+		got = append(got, Pair[int, string]{i, s})
+		if i == 1 {
+			break
+		}
+	}
+	want := []Pair[int, string]{{0, "x"}, {1, "y"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Enumerate() == %v, want %v", got, want)
+	}
+}
+
+func TestZipSeq(t *testing.T) {
+	var got []Pair[int, int]
+	for n, f := range ZipSeq(Naturals(), Take(Fibonacci(), 5)) {
+		got = append(got, Pair[int, int]{n, f})
+	}
+	want := []Pair[int, int]{{0, 0}, {1, 1}, {2, 1}, {3, 2}, {4, 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ZipSeq() == %v, want %v", got, want)
+	}
+	for range ZipSeq(Naturals(), Primes()) {
+		break // stopping early must release the pulled iterator
+	}
+}
@@ -0,0 +1,103 @@
+package serve
+
+import "expvar"
+import "fmt"
+import "log"
+import "net/http"
+import "time"
+
+// Middleware wraps a handler with additional behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with mw so that the first middleware is the outermost.
+func Chain(h http.Handler, mw ...Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
+
+// statusRecorder captures the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func record(w http.ResponseWriter) *statusRecorder {
+	if r, ok := w.(*statusRecorder); ok {
+		return r
+	}
+	return &statusRecorder{ResponseWriter: w}
+}
+
+func (r *statusRecorder) code() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+// Logger logs the method, path, status and duration of each request.
+func Logger(l *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := record(w)
+			next.ServeHTTP(rec, r)
+			l.Printf("%s %s %d %s", r.Method, r.URL.Path, rec.code(), time.Since(start).Round(time.Microsecond))
+		})
+	}
+}
+
+// Recover turns a panicking handler into a 500 response and logs the
+// panic value.
+func Recover(l *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if v := recover(); v != nil {
+					if v == http.ErrAbortHandler {
+						panic(v)
+					}
+					l.Printf("panic serving %s %s: %v", r.Method, r.URL.Path, v)
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Metrics counts requests into m: "requests" in total, one counter per
+// status code (e.g. "status_200") and the cumulative "latency_us".
+func Metrics(m *expvar.Map) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := record(w)
+			next.ServeHTTP(rec, r)
+			m.Add("requests", 1)
+			m.Add(fmt.Sprintf("status_%d", rec.code()), 1)
+			m.Add("latency_us", time.Since(start).Microseconds())
+		})
+	}
+}
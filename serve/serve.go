@@ -0,0 +1,71 @@
+// Package serve runs HTTP servers that shut down gracefully when their
+// context is cancelled, and provides middleware shared by the examples.
+package serve
+
+import "context"
+import "errors"
+import "net"
+import "net/http"
+import "time"
+
+// DefaultShutdownTimeout bounds how long in-flight requests may take to
+// finish once shutdown begins.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// Server is an HTTP server bound to a context.
+type Server struct {
+	Addr    string
+	Handler http.Handler
+
+	// ShutdownTimeout overrides DefaultShutdownTimeout when positive.
+	ShutdownTimeout time.Duration
+
+	// OnShutdown functions are called when shutdown begins, for example
+	// to close long-lived connections that http.Server does not track.
+	OnShutdown []func()
+}
+
+// ListenAndServe listens on s.Addr and serves until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is done, then stops accepting,
+// waits for in-flight requests to complete and returns. It returns nil
+// after a clean shutdown.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.Handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	for _, f := range s.OnShutdown {
+		srv.RegisterOnShutdown(f)
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	timeout := s.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	// The parent context is already done, so shut down on a fresh one.
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	err := srv.Shutdown(sctx)
+	if serr := <-errc; !errors.Is(serr, http.ErrServerClosed) {
+		return serr
+	}
+	return err
+}
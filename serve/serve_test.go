@@ -0,0 +1,90 @@
+package serve
+
+import "bytes"
+import "context"
+import "expvar"
+import "io"
+import "log"
+import "net"
+import "net/http"
+import "net/http/httptest"
+import "strings"
+import "testing"
+import "time"
+
+func TestMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	l := log.New(&logs, "", 0)
+	m := new(expvar.Map).Init()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "ok") })
+	mux.HandleFunc("/boom", func(w http.ResponseWriter, r *http.Request) { panic("boom") })
+	h := Chain(mux, Logger(l), Metrics(m), Recover(l))
+
+	cases := []struct {
+		path string
+		code int
+	}{
+		{"/ok", http.StatusOK},
+		{"/boom", http.StatusInternalServerError},
+		{"/missing", http.StatusNotFound},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", c.path, nil))
+		if rec.Code != c.code {
+			t.Errorf("GET %s: status %d, want %d", c.path, rec.Code, c.code)
+		}
+	}
+
+	if got := m.Get("requests").String(); got != "3" {
+		t.Errorf("requests counter == %s, want 3", got)
+	}
+	if got := m.Get("status_500"); got == nil || got.String() != "1" {
+		t.Errorf("status_500 counter == %v, want 1", got)
+	}
+	for _, want := range []string{"GET /ok 200", "panic serving GET /boom: boom", "GET /boom 500"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log missing %q:\n%s", want, logs.String())
+		}
+	}
+}
+
+func TestServeShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	started := make(chan struct{})
+	s := &Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, "done")
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Serve(ctx, ln) }()
+
+	respc := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			respc <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		respc <- string(b)
+	}()
+
+	<-started
+	cancel()
+	if err := <-errc; err != nil {
+		t.Fatalf("Serve returned %v", err)
+	}
+	if got := <-respc; got != "done" {
+		t.Errorf("in-flight request got %q, want it to complete", got)
+	}
+}
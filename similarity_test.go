@@ -0,0 +1,51 @@
+package golib
+
+import "math"
+import "reflect"
+import "testing"
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"世界", "世间", 1},
+	}
+	for _, c := range cases {
+		if got := Distance(c.a, c.b); got != c.want {
+			t.Errorf("Distance(%q, %q) == %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b string
+		fn   func(a, b string) float64
+		want float64
+	}{
+		{"", "", Similarity, 1},
+		{"abcd", "abcf", Similarity, 0.75},
+		{"abc", "xyz", Similarity, 0},
+		{"MARTHA", "MARHTA", JaroWinkler, 0.9611},
+		{"DIXON", "DICKSONX", JaroWinkler, 0.8133},
+		{"abc", "", JaroWinkler, 0},
+	}
+	for _, c := range cases {
+		if got := c.fn(c.a, c.b); math.Abs(got-c.want) > 1e-4 {
+			t.Errorf("similarity of %q and %q == %.4f, want %.4f", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	got := Suggest("strcut", []string{"structs", "switch", "string", "pointers"}, 0.75)
+	want := []string{"structs", "string"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Suggest == %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,269 @@
+package golib
+
+import "iter"
+import "slices"
+import "sort"
+
+// Zip pairs up the elements of a and b by index. If the slices differ in
+// length, the extra elements of the longer one are ignored.
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	ps := make([]Pair[A, B], n)
+	for i := range n {
+		ps[i] = Pair[A, B]{a[i], b[i]}
+	}
+	return ps
+}
+
+// Unzip splits pairs into a slice of their first elements and a slice of
+// their second elements, reversing Zip.
+func Unzip[A, B any](ps []Pair[A, B]) ([]A, []B) {
+	a, b := make([]A, len(ps)), make([]B, len(ps))
+	for i, p := range ps {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
+
+// Chunk splits s into consecutive slices of n elements; the last may be
+// shorter. The chunks share s's backing array but are capped, so
+// appending to one never overwrites the next. Chunk panics if n < 1.
+func Chunk[T any](s []T, n int) [][]T {
+	if n < 1 {
+		panic("golib: chunk size must be positive")
+	}
+	chunks := make([][]T, 0, (len(s)+n-1)/n)
+	for i := 0; i < len(s); i += n {
+		end := min(i+n, len(s))
+		chunks = append(chunks, s[i:end:end])
+	}
+	return chunks
+}
+
+// Partition returns the elements of s for which pred is true and those for
+// which it is false, each in their original order.
+func Partition[T any](s []T, pred func(T) bool) (matched, rest []T) {
+	for _, v := range s {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return matched, rest
+}
+
+// SlidingWindow returns the windows of size consecutive elements of s,
+// starting at every step-th element. Trailing elements that do not fill a
+// window are dropped, so SlidingWindow(s, 2, 2) of an odd-length s omits
+// the last element. Like Chunk, the windows share s's backing array but
+// are capped. It panics if size or step is less than 1.
+func SlidingWindow[T any](s []T, size, step int) [][]T {
+	if size < 1 || step < 1 {
+		panic("golib: window size and step must be positive")
+	}
+	var windows [][]T
+	for i := 0; i+size <= len(s); i += step {
+		windows = append(windows, s[i:i+size:i+size])
+	}
+	return windows
+}
+
+// Unique returns the distinct elements of s in the order they first
+// appear. s is not modified.
+func Unique[T comparable](s []T) []T {
+	return UniqueInPlace(slices.Clone(s))
+}
+
+// UniqueBy returns the elements of s with distinct keys, keeping the first
+// element for each key, for elements that are not comparable themselves.
+// s is not modified.
+func UniqueBy[T any, K comparable](s []T, key func(T) K) []T {
+	return UniqueByInPlace(slices.Clone(s), key)
+}
+
+// UniqueInPlace is like Unique but reuses s's backing array, returning
+// s shortened to its distinct elements. The elements past the new length
+// are zeroed, so they do not keep garbage alive.
+func UniqueInPlace[T comparable](s []T) []T {
+	return UniqueByInPlace(s, identity[T])
+}
+
+// UniqueByInPlace is like UniqueBy but reuses s's backing array, as
+// UniqueInPlace does.
+func UniqueByInPlace[T any, K comparable](s []T, key func(T) K) []T {
+	seen := make(map[K]struct{}, len(s))
+	n := 0
+	for _, v := range s {
+		k := key(v)
+		if _, dup := seen[k]; dup {
+			continue
+		}
+		seen[k] = struct{}{}
+		s[n] = v
+		n++
+	}
+	clear(s[n:])
+	return s[:n]
+}
+
+// GroupBy groups the elements of items by key, preserving their order
+// within each group.
+func GroupBy[T any, K comparable](items []T, key func(T) K) map[K][]T {
+	groups := make(map[K][]T)
+	for _, v := range items {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// GroupByOrdered is like GroupBy but returns the groups as key/elements
+// pairs, ordered by the first occurrence of each key.
+func GroupByOrdered[T any, K comparable](items []T, key func(T) K) []Pair[K, []T] {
+	var groups []Pair[K, []T]
+	index := make(map[K]int)
+	for _, v := range items {
+		k := key(v)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Pair[K, []T]{First: k})
+		}
+		groups[i].Second = append(groups[i].Second, v)
+	}
+	return groups
+}
+
+// CountBy returns the number of elements of items with each key.
+func CountBy[T any, K comparable](items []T, key func(T) K) map[K]int {
+	counts := make(map[K]int)
+	for _, v := range items {
+		counts[key(v)]++
+	}
+	return counts
+}
+
+// Flatten concatenates the slices in s into a single new slice.
+func Flatten[T any](s [][]T) []T {
+	return slices.Concat(s...)
+}
+
+// FlatMap applies fn to each element of items and concatenates the
+// results. The results are collected before concatenating, so the output
+// is allocated once at its final length.
+func FlatMap[T, U any](items []T, fn func(T) []U) []U {
+	parts := make([][]U, len(items))
+	for i, v := range items {
+		parts[i] = fn(v)
+	}
+	return Flatten(parts)
+}
+
+// BinarySearch searches sorted, which must be ordered by cmp, for
+// target. It returns the position of the first element equal to target
+// and true, or the position where target would be inserted and false.
+func BinarySearch[T any](sorted []T, target T, cmp func(a, b T) int) (int, bool) {
+	return slices.BinarySearchFunc(sorted, target, cmp)
+}
+
+// SortedInsert inserts v into sorted, which must be ordered by cmp,
+// keeping it ordered, and returns the updated slice. v goes after any
+// elements equal to it, so repeated inserts are stable.
+func SortedInsert[T any](sorted []T, v T, cmp func(a, b T) int) []T {
+	i := sort.Search(len(sorted), func(i int) bool { return cmp(sorted[i], v) > 0 })
+	return slices.Insert(sorted, i, v)
+}
+
+// Intersect returns the distinct elements of a that are also in b, in
+// the order they appear in a.
+func Intersect[T comparable](a, b []T) []T {
+	return IntersectBy(a, b, identity[T])
+}
+
+// Union returns the distinct elements of a followed by those of b that
+// are not in a, each in order of first appearance.
+func Union[T comparable](a, b []T) []T {
+	return UnionBy(a, b, identity[T])
+}
+
+// Difference returns the distinct elements of a that are not in b, in
+// the order they appear in a.
+func Difference[T comparable](a, b []T) []T {
+	return DifferenceBy(a, b, identity[T])
+}
+
+// SymmetricDifference returns the distinct elements that are in exactly
+// one of a and b: those of a first, then those of b, each in order.
+func SymmetricDifference[T comparable](a, b []T) []T {
+	return SymmetricDifferenceBy(a, b, identity[T])
+}
+
+// IntersectBy is like Intersect but compares elements by key, keeping
+// the first element of a for each key.
+func IntersectBy[T any, K comparable](a, b []T, key func(T) K) []T {
+	in := keySet(b, key)
+	var out []T
+	for k, v := range distinct(a, key) {
+		if _, ok := in[k]; ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// UnionBy is like Union but compares elements by key, keeping the first
+// element for each key.
+func UnionBy[T any, K comparable](a, b []T, key func(T) K) []T {
+	out := make([]T, 0, len(a)+len(b))
+	out = append(append(out, a...), b...)
+	return UniqueByInPlace(out, key)
+}
+
+// DifferenceBy is like Difference but compares elements by key, keeping
+// the first element of a for each key.
+func DifferenceBy[T any, K comparable](a, b []T, key func(T) K) []T {
+	in := keySet(b, key)
+	var out []T
+	for k, v := range distinct(a, key) {
+		if _, ok := in[k]; !ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// SymmetricDifferenceBy is like SymmetricDifference but compares
+// elements by key, keeping the first element for each key.
+func SymmetricDifferenceBy[T any, K comparable](a, b []T, key func(T) K) []T {
+	return append(DifferenceBy(a, b, key), DifferenceBy(b, a, key)...)
+}
+
+func identity[T any](v T) T { return v }
+
+func keySet[T any, K comparable](s []T, key func(T) K) map[K]struct{} {
+	set := make(map[K]struct{}, len(s))
+	for _, v := range s {
+		set[key(v)] = struct{}{}
+	}
+	return set
+}
+
+// distinct yields the key and element of the first element of s with
+// each key.
+func distinct[T any, K comparable](s []T, key func(T) K) iter.Seq2[K, T] {
+	return func(yield func(K, T) bool) {
+		seen := make(map[K]struct{}, len(s))
+		for _, v := range s {
+			k := key(v)
+			if _, dup := seen[k]; dup {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
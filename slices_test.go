@@ -0,0 +1,263 @@
+package golib
+
+import "cmp"
+import "reflect"
+import "strings"
+import "testing"
+
+func TestZipUnzip(t *testing.T) {
+	names := []string{"a", "b", "c"}
+	ages := []int{1, 2}
+	got := Zip(names, ages)
+	want := []Pair[string, int]{{"a", 1}, {"b", 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Zip() == %v, want %v", got, want)
+	}
+	n, a := Unzip(got)
+	if !reflect.DeepEqual(n, names[:2]) || !reflect.DeepEqual(a, ages) {
+		t.Errorf("Unzip() == %v, %v", n, a)
+	}
+	if z := Zip([]int(nil), names); len(z) != 0 {
+		t.Errorf("Zip(nil, names) == %v", z)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	cases := []struct {
+		in   []int
+		n    int
+		want [][]int
+	}{
+		{[]int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{[]int{1, 2}, 5, [][]int{{1, 2}}},
+		{nil, 3, [][]int{}},
+	}
+	for _, c := range cases {
+		if got := Chunk(c.in, c.n); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Chunk(%v, %d) == %v, want %v", c.in, c.n, got, c.want)
+		}
+	}
+
+	s := []int{1, 2, 3, 4}
+	chunks := Chunk(s, 2)
+	_ = append(chunks[0], 99)
+	if s[2] != 3 {
+		t.Error("appending to a chunk overwrote the next one")
+	}
+}
+
+func TestPartition(t *testing.T) {
+	even, odd := Partition([]int{1, 2, 3, 4, 5, 6}, func(n int) bool { return n%2 == 0 })
+	if !reflect.DeepEqual(even, []int{2, 4, 6}) || !reflect.DeepEqual(odd, []int{1, 3, 5}) {
+		t.Errorf("Partition() == %v, %v", even, odd)
+	}
+	yes, no := Partition([]string{"a"}, func(string) bool { return true })
+	if len(yes) != 1 || no != nil {
+		t.Errorf("Partition() == %v, %v", yes, no)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	cases := []struct {
+		size, step int
+		want       [][]int
+	}{
+		{3, 1, [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}},
+		{2, 2, [][]int{{1, 2}, {3, 4}}},
+		{2, 3, [][]int{{1, 2}, {4, 5}}},
+		{5, 1, [][]int{{1, 2, 3, 4, 5}}},
+		{6, 1, nil},
+	}
+	for _, c := range cases {
+		if got := SlidingWindow(s, c.size, c.step); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("SlidingWindow(%v, %d, %d) == %v, want %v", s, c.size, c.step, got, c.want)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("SlidingWindow with step 0 did not panic")
+		}
+	}()
+	SlidingWindow(s, 1, 0)
+}
+
+func TestUnique(t *testing.T) {
+	s := []int{3, 1, 3, 2, 1, 3}
+	if got := Unique(s); !reflect.DeepEqual(got, []int{3, 1, 2}) {
+		t.Errorf("Unique() == %v", got)
+	}
+	if !reflect.DeepEqual(s, []int{3, 1, 3, 2, 1, 3}) {
+		t.Errorf("Unique modified its argument: %v", s)
+	}
+	if got := Unique([]string(nil)); len(got) != 0 {
+		t.Errorf("Unique(nil) == %v", got)
+	}
+
+	got := UniqueInPlace(s)
+	if !reflect.DeepEqual(got, []int{3, 1, 2}) || &got[0] != &s[0] {
+		t.Errorf("UniqueInPlace() == %v", got)
+	}
+	if !reflect.DeepEqual(s[3:], []int{0, 0, 0}) {
+		t.Errorf("UniqueInPlace left %v past the end", s[3:])
+	}
+}
+
+func TestUniqueBy(t *testing.T) {
+	type user struct {
+		Name string
+		Tags []string // makes user incomparable
+	}
+	users := []user{{"ann", nil}, {"bob", []string{"x"}}, {"ann", []string{"y"}}}
+	byName := func(u user) string { return u.Name }
+	got := UniqueBy(users, byName)
+	if len(got) != 2 || got[0].Name != "ann" || got[0].Tags != nil || got[1].Name != "bob" {
+		t.Errorf("UniqueBy() == %v", got)
+	}
+	if len(users) != 3 || users[2].Name != "ann" {
+		t.Errorf("UniqueBy modified its argument: %v", users)
+	}
+	got = UniqueByInPlace(users, byName)
+	if len(got) != 2 || users[2].Tags != nil {
+		t.Errorf("UniqueByInPlace() == %v, tail %v", got, users[2:])
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	words := []string{"apple", "bob", "avocado", "cat", "banana"}
+	first := func(s string) byte { return s[0] }
+
+	got := GroupBy(words, first)
+	want := map[byte][]string{'a': {"apple", "avocado"}, 'b': {"bob", "banana"}, 'c': {"cat"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupBy() == %v, want %v", got, want)
+	}
+
+	ordered := GroupByOrdered(words, first)
+	wantOrdered := []Pair[byte, []string]{
+		{'a', []string{"apple", "avocado"}},
+		{'b', []string{"bob", "banana"}},
+		{'c', []string{"cat"}},
+	}
+	if !reflect.DeepEqual(ordered, wantOrdered) {
+		t.Errorf("GroupByOrdered() == %v, want %v", ordered, wantOrdered)
+	}
+
+	counts := CountBy(words, func(s string) int { return len(s) })
+	if !reflect.DeepEqual(counts, map[int]int{5: 1, 3: 2, 7: 1, 6: 1}) {
+		t.Errorf("CountBy() == %v", counts)
+	}
+	if len(GroupBy([]int(nil), func(int) int { return 0 })) != 0 || GroupByOrdered([]int(nil), func(int) int { return 0 }) != nil {
+		t.Error("grouping nothing returned groups")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	if got := Flatten([][]int{{1, 2}, nil, {3}, {}}); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("Flatten() == %v", got)
+	}
+	if got := Flatten[int](nil); len(got) != 0 {
+		t.Errorf("Flatten(nil) == %v", got)
+	}
+
+	got := FlatMap([]string{"a b", "", "c"}, strings.Fields)
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Errorf("FlatMap() == %q", got)
+	}
+}
+
+func TestBinarySearch(t *testing.T) {
+	s := []int{1, 3, 3, 5, 9}
+	tests := []struct {
+		target int
+		i      int
+		found  bool
+	}{
+		{0, 0, false}, {1, 0, true}, {3, 1, true}, {4, 3, false}, {9, 4, true}, {10, 5, false},
+	}
+	for _, tt := range tests {
+		if i, found := BinarySearch(s, tt.target, cmp.Compare[int]); i != tt.i || found != tt.found {
+			t.Errorf("BinarySearch(%d) == %d, %v, want %d, %v", tt.target, i, found, tt.i, tt.found)
+		}
+	}
+	if i, found := BinarySearch(nil, 1, cmp.Compare[int]); i != 0 || found {
+		t.Errorf("BinarySearch(nil) == %d, %v", i, found)
+	}
+}
+
+func TestSortedInsert(t *testing.T) {
+	type item struct {
+		key  int
+		name string
+	}
+	byKey := func(a, b item) int { return cmp.Compare(a.key, b.key) }
+	var s []item
+	for _, it := range []item{{3, "a"}, {1, "b"}, {3, "c"}, {2, "d"}, {0, "e"}, {3, "f"}} {
+		s = SortedInsert(s, it, byKey)
+	}
+	var names []string
+	for _, it := range s {
+		names = append(names, it.name)
+	}
+	if want := []string{"e", "b", "d", "a", "c", "f"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("SortedInsert order == %q, want %q", names, want)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := []int{1, 2, 2, 3, 4}
+	b := []int{4, 3, 5, 5, 6}
+	tests := []struct {
+		name string
+		f    func(a, b []int) []int
+		want []int
+	}{
+		{"Intersect", Intersect[int], []int{3, 4}},
+		{"Union", Union[int], []int{1, 2, 3, 4, 5, 6}},
+		{"Difference", Difference[int], []int{1, 2}},
+		{"SymmetricDifference", SymmetricDifference[int], []int{1, 2, 5, 6}},
+	}
+	for _, tt := range tests {
+		if got := tt.f(a, b); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s() == %v, want %v", tt.name, got, tt.want)
+		}
+		if got := tt.f(nil, nil); len(got) != 0 {
+			t.Errorf("%s(nil, nil) == %v", tt.name, got)
+		}
+	}
+	if !reflect.DeepEqual(a, []int{1, 2, 2, 3, 4}) || !reflect.DeepEqual(b, []int{4, 3, 5, 5, 6}) {
+		t.Errorf("set operations modified their arguments: %v, %v", a, b)
+	}
+}
+
+func TestSetOperationsBy(t *testing.T) {
+	type user struct {
+		Name string
+		Tags []string
+	}
+	a := []user{{"ann", []string{"a"}}, {"bob", nil}, {"ann", nil}}
+	b := []user{{"cat", nil}, {"ann", []string{"b"}}}
+	byName := func(u user) string { return u.Name }
+	names := func(us []user) []string {
+		var s []string
+		for _, u := range us {
+			s = append(s, u.Name)
+		}
+		return s
+	}
+
+	got := IntersectBy(a, b, byName)
+	if !reflect.DeepEqual(names(got), []string{"ann"}) || got[0].Tags[0] != "a" {
+		t.Errorf("IntersectBy() == %v", got)
+	}
+	if got := UnionBy(a, b, byName); !reflect.DeepEqual(names(got), []string{"ann", "bob", "cat"}) {
+		t.Errorf("UnionBy() == %v", got)
+	}
+	if got := DifferenceBy(a, b, byName); !reflect.DeepEqual(names(got), []string{"bob"}) {
+		t.Errorf("DifferenceBy() == %v", got)
+	}
+	if got := SymmetricDifferenceBy(a, b, byName); !reflect.DeepEqual(names(got), []string{"bob", "cat"}) {
+		t.Errorf("SymmetricDifferenceBy() == %v", got)
+	}
+}
@@ -0,0 +1,115 @@
+package golib
+
+import "cmp"
+import "slices"
+import "strings"
+import "unicode"
+
+// SlugOptions configures SlugifyWith.
+type SlugOptions struct {
+	// Separator joins words; it defaults to "-".
+	Separator string
+	// MaxLength limits the slug to at most this many bytes, cutting at a
+	// word boundary where possible. Zero means no limit.
+	MaxLength int
+	// Replacements are substituted before anything else, for example
+	// {"&": "and"} or {"C++": "cpp"}.
+	Replacements map[string]string
+}
+
+// Slugify returns a lowercase, URL-safe identifier for s: accented letters
+// are transliterated to ASCII, punctuation is dropped and runs of
+// separators are collapsed to a single hyphen.
+func Slugify(s string) string {
+	return SlugifyWith(s, SlugOptions{})
+}
+
+// SlugifyWith is like Slugify but configurable.
+func SlugifyWith(s string, opts SlugOptions) string {
+	sep := opts.Separator
+	if sep == "" {
+		sep = "-"
+	}
+	if len(opts.Replacements) > 0 {
+		// Longer keys first so that "C++" wins over "+", then in order
+		// so that the result does not depend on map iteration.
+		var pairs []string
+		keys := make([]string, 0, len(opts.Replacements))
+		for k := range opts.Replacements {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, func(a, b string) int {
+			return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a, b))
+		})
+		for _, k := range keys {
+			pairs = append(pairs, k, " "+opts.Replacements[k]+" ")
+		}
+		s = strings.NewReplacer(pairs...).Replace(s)
+	}
+
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range s {
+		r = unicode.ToLower(r)
+		switch {
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(r)
+		case r == '\'' || r == '’':
+			// Keep contractions such as "don't" as one word.
+		default:
+			if t, ok := transliterations[r]; ok {
+				word.WriteString(t)
+			} else {
+				flush()
+			}
+		}
+	}
+	flush()
+
+	slug := strings.Join(words, sep)
+	if opts.MaxLength > 0 && len(slug) > opts.MaxLength {
+		// Keep the whole words that fit, so that no part of a word or of
+		// a multi-character separator is left at the end. A first word
+		// too long on its own is cut.
+		n, keep := len(words[0]), 1
+		for _, w := range words[1:] {
+			if n+len(sep)+len(w) > opts.MaxLength {
+				break
+			}
+			n += len(sep) + len(w)
+			keep++
+		}
+		slug = strings.Join(words[:keep], sep)
+		if len(slug) > opts.MaxLength {
+			slug = slug[:opts.MaxLength]
+		}
+	}
+	return slug
+}
+
+// transliterations maps lowercase non-ASCII letters to ASCII spellings.
+var transliterations = func() map[rune]string {
+	m := map[rune]string{
+		'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d",
+		'þ': "th", 'ł': "l", 'ı': "i", 'ŋ': "ng",
+	}
+	groups := map[string]string{
+		"a": "àáâãäåāăą", "c": "çćĉċč", "d": "ď", "e": "èéêëēĕėęě",
+		"g": "ĝğġģ", "h": "ĥħ", "i": "ìíîïĩīĭį", "j": "ĵ", "k": "ķ",
+		"l": "ĺļľŀ", "n": "ñńņňŉ", "o": "òóôõöōŏő", "r": "ŕŗř",
+		"s": "śŝşšș", "t": "ţťŧț", "u": "ùúûüũūŭůűų", "w": "ŵ",
+		"y": "ýÿŷ", "z": "źżž",
+	}
+	for ascii, runes := range groups {
+		for _, r := range runes {
+			m[r] = ascii
+		}
+	}
+	return m
+}()
@@ -0,0 +1,50 @@
+package golib
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  Crème Brûlée -- à la carte  ", "creme-brulee-a-la-carte"},
+		{"Straße in Łódź", "strasse-in-lodz"},
+		{"Don't stop", "dont-stop"},
+		{"日本語", ""},
+		{"", ""},
+	}
+	for _, c := range cases {
+		if got := Slugify(c.in); got != c.want {
+			t.Errorf("Slugify(%q) == %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestSlugifyWith(t *testing.T) {
+	cases := []struct {
+		in   string
+		opts SlugOptions
+		want string
+	}{
+		{"Tom & Jerry", SlugOptions{Replacements: map[string]string{"&": "and"}}, "tom-and-jerry"},
+		{"C++ and C", SlugOptions{Replacements: map[string]string{"C++": "cpp", "+": "plus"}}, "cpp-and-c"},
+		{"a slug that is too long", SlugOptions{MaxLength: 12}, "a-slug-that"},
+		{"abcdefghij", SlugOptions{MaxLength: 4}, "abcd"},
+		{"ab cd ef", SlugOptions{MaxLength: 5}, "ab-cd"},
+		{"ab cd ef", SlugOptions{MaxLength: 6}, "ab-cd"},
+		{"ab cd ef", SlugOptions{MaxLength: 4}, "ab"},
+		{"ab cd ef", SlugOptions{MaxLength: 5, Separator: "__"}, "ab"},
+		{"ab cd ef", SlugOptions{MaxLength: 6, Separator: "__"}, "ab__cd"},
+		{"ab cd", SlugOptions{MaxLength: 3, Separator: "__"}, "ab"},
+		{"ab cd ef", SlugOptions{MaxLength: 6, Separator: "-_"}, "ab-_cd"},
+		{"ab cd ef", SlugOptions{MaxLength: 7, Separator: "-_"}, "ab-_cd"},
+		{"ab cd ef", SlugOptions{MaxLength: 9, Separator: "-_"}, "ab-_cd"},
+		{"abc", SlugOptions{Replacements: map[string]string{"bc": "y", "ab": "x"}}, "x-c"},
+		{"snake case please", SlugOptions{Separator: "_"}, "snake_case_please"},
+	}
+	for _, c := range cases {
+		if got := SlugifyWith(c.in, c.opts); got != c.want {
+			t.Errorf("SlugifyWith(%q, %+v) == %q, want %q", c.in, c.opts, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package snowflake generates 64-bit IDs that sort by the time they were
+// made, without coordination between machines, in the manner of Twitter's
+// Snowflake. Each ID holds, from the most significant bit:
+//
+//	1 bit   zero, so that IDs are positive as int64
+//	41 bits milliseconds since the epoch, enough for 69 years
+//	10 bits the node, one of 1024 generators
+//	12 bits a sequence number, for 4096 IDs per millisecond per node
+//
+// Every generator running at once must have its own node number.
+package snowflake
+
+import "errors"
+import "fmt"
+import "strconv"
+import "sync"
+import "time"
+
+import "github.com/lukehedger/golib/clock"
+
+const (
+	nodeBits = 10
+	seqBits  = 12
+	timeBits = 41
+
+	// MaxNode is the largest node number.
+	MaxNode = 1<<nodeBits - 1
+	maxSeq  = 1<<seqBits - 1
+)
+
+// DefaultEpoch is the epoch used when Config.Epoch is zero.
+var DefaultEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrClockBackwards is returned by Next when the clock has gone back
+// further than Config.MaxRollback.
+var ErrClockBackwards = errors.New("snowflake: clock moved backwards")
+
+// ErrTimeOverflow is returned by Next when the time since the epoch no
+// longer fits in an ID.
+var ErrTimeOverflow = errors.New("snowflake: time out of range for the epoch")
+
+// An ID is a generated identifier.
+type ID int64
+
+// String returns id in decimal.
+func (id ID) String() string { return strconv.FormatInt(int64(id), 10) }
+
+// Parse parses an ID from decimal.
+func Parse(s string) (ID, error) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("snowflake: invalid ID %q", s)
+	}
+	return ID(n), nil
+}
+
+// Parts are the fields of an ID.
+type Parts struct {
+	Time time.Time // to the millisecond
+	Node int64
+	Seq  int64
+}
+
+// Decode splits id, made by a generator with the given epoch, into its
+// parts. A zero epoch means DefaultEpoch.
+func Decode(id ID, epoch time.Time) Parts {
+	if epoch.IsZero() {
+		epoch = DefaultEpoch
+	}
+	ms := int64(id) >> (nodeBits + seqBits)
+	return Parts{
+		Time: epoch.Add(time.Duration(ms) * time.Millisecond),
+		Node: int64(id) >> seqBits & MaxNode,
+		Seq:  int64(id) & maxSeq,
+	}
+}
+
+// Config configures a Generator.
+type Config struct {
+	// Node distinguishes this generator from others running at the same
+	// time, from 0 to MaxNode.
+	Node int64
+	// Epoch is the time from which IDs count. Zero means DefaultEpoch.
+	Epoch time.Time
+	// MaxRollback is how far the clock may go back, as when it is
+	// corrected, before Next fails rather than waiting for the clock to
+	// catch up. Zero means one second.
+	MaxRollback time.Duration
+	// Clock tells the time. Nil means clock.Real.
+	Clock clock.Clock
+}
+
+// A Generator makes IDs for one node. It is safe for concurrent use.
+type Generator struct {
+	node        int64
+	epoch       time.Time
+	maxRollback time.Duration
+	clock       clock.Clock
+
+	mu   sync.Mutex
+	last int64 // milliseconds since epoch of the last ID
+	seq  int64
+}
+
+// New returns a Generator configured by c.
+func New(c Config) (*Generator, error) {
+	if c.Node < 0 || c.Node > MaxNode {
+		return nil, fmt.Errorf("snowflake: node %d out of range 0-%d", c.Node, MaxNode)
+	}
+	g := &Generator{node: c.Node, epoch: c.Epoch, maxRollback: c.MaxRollback, clock: clock.Or(c.Clock), last: -1}
+	if g.epoch.IsZero() {
+		g.epoch = DefaultEpoch
+	}
+	if g.maxRollback == 0 {
+		g.maxRollback = time.Second
+	}
+	return g, nil
+}
+
+// Next returns a new ID, greater than every ID g has made before. If the
+// sequence for this millisecond is used up, or the clock has gone back by
+// no more than MaxRollback, it waits until the clock passes the time of
+// the last ID.
+func (g *Generator) Next() (ID, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.millis()
+	if now < 0 || now >= 1<<timeBits {
+		return 0, ErrTimeOverflow
+	}
+	if now < g.last {
+		behind := time.Duration(g.last-now) * time.Millisecond
+		if behind > g.maxRollback {
+			return 0, fmt.Errorf("%w by %v", ErrClockBackwards, behind)
+		}
+		g.clock.Sleep(behind)
+		now = g.wait()
+	}
+	if now == g.last {
+		g.seq = (g.seq + 1) & maxSeq
+		if g.seq == 0 {
+			now = g.wait()
+		}
+	} else {
+		g.seq = 0
+	}
+	if now >= 1<<timeBits {
+		return 0, ErrTimeOverflow
+	}
+	g.last = now
+	return ID(now<<(nodeBits+seqBits) | g.node<<seqBits | g.seq), nil
+}
+
+// Decode splits id into its parts, using g's epoch.
+func (g *Generator) Decode(id ID) Parts { return Decode(id, g.epoch) }
+
+func (g *Generator) millis() int64 {
+	return g.clock.Now().Sub(g.epoch).Milliseconds()
+}
+
+// wait sleeps until the clock is past the millisecond of the last ID and
+// returns the new time.
+func (g *Generator) wait() int64 {
+	for {
+		now := g.millis()
+		if now > g.last {
+			return now
+		}
+		g.clock.Sleep(time.Duration(g.last-now+1)*time.Millisecond - time.Duration(g.clock.Now().Sub(g.epoch)%time.Millisecond))
+	}
+}
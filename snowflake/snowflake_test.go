@@ -0,0 +1,132 @@
+package snowflake
+
+import "errors"
+import "sync"
+import "testing"
+import "time"
+
+// manualClock stands still until slept on or set.
+type manualClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *manualClock) After(d time.Duration) <-chan time.Time {
+	c.Sleep(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+func (c *manualClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func (c *manualClock) set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = t
+}
+
+func TestNext(t *testing.T) {
+	start := DefaultEpoch.Add(1000 * time.Hour)
+	clk := &manualClock{t: start}
+	g, err := New(Config{Node: 7, Clock: clk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var prev ID
+	for i := range maxSeq + 10 {
+		id, err := g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= prev {
+			t.Fatalf("ID %d not after %d", id, prev)
+		}
+		prev = id
+		p := g.Decode(id)
+		if p.Node != 7 {
+			t.Fatalf("node %d", p.Node)
+		}
+		if i <= maxSeq && (!p.Time.Equal(start) || p.Seq != int64(i)) {
+			t.Fatalf("ID %d: %+v", i, p)
+		}
+	}
+	if p := g.Decode(prev); !p.Time.Equal(start.Add(time.Millisecond)) || p.Seq != 8 {
+		t.Errorf("after the sequence ran out: %+v", p)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	start := DefaultEpoch.Add(time.Hour)
+	clk := &manualClock{t: start}
+	g, _ := New(Config{Clock: clk, MaxRollback: 10 * time.Millisecond})
+	a, _ := g.Next()
+
+	clk.set(start.Add(-5 * time.Millisecond))
+	b, err := g.Next()
+	if err != nil || b <= a {
+		t.Fatalf("after a small rollback: %d, %v; last %d", b, err, a)
+	}
+
+	clk.set(start.Add(-time.Second))
+	if _, err := g.Next(); !errors.Is(err, ErrClockBackwards) {
+		t.Errorf("after a large rollback: %v", err)
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	g, _ := New(Config{Node: MaxNode})
+	var mu sync.Mutex
+	seen := map[ID]bool{}
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 2000 {
+				id, err := g.Next()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ID %d", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestConfig(t *testing.T) {
+	for _, node := range []int64{-1, MaxNode + 1} {
+		if _, err := New(Config{Node: node}); err == nil {
+			t.Errorf("node %d accepted", node)
+		}
+	}
+	epoch := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	g, _ := New(Config{Epoch: epoch, Clock: &manualClock{t: epoch.Add(-time.Hour)}})
+	if _, err := g.Next(); !errors.Is(err, ErrTimeOverflow) {
+		t.Errorf("time before epoch: %v", err)
+	}
+	id, _ := Parse("123")
+	if id != 123 || id.String() != "123" {
+		t.Errorf("Parse = %d", id)
+	}
+	if _, err := Parse("-1"); err == nil {
+		t.Error("negative ID parsed")
+	}
+}
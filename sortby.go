@@ -0,0 +1,43 @@
+package golib
+
+import "cmp"
+import "slices"
+
+// SortBy sorts items in ascending order of key. The sort is not stable.
+func SortBy[T any, K cmp.Ordered](items []T, key func(T) K) {
+	slices.SortFunc(items, By(key))
+}
+
+// SortStableBy sorts items in ascending order of key, keeping items with
+// equal keys in their original order.
+func SortStableBy[T any, K cmp.Ordered](items []T, key func(T) K) {
+	slices.SortStableFunc(items, By(key))
+}
+
+// An Ordering compares two values, returning a negative number, zero or a
+// positive number as a sorts before, with or after b. It can be passed to
+// slices.SortFunc and its relatives. Orderings for several keys chain
+// with ThenBy:
+//
+//	slices.SortStableFunc(people, By(lastName).ThenBy(By(firstName)))
+type Ordering[T any] func(a, b T) int
+
+// By returns the Ordering of T in ascending order of key.
+func By[T any, K cmp.Ordered](key func(T) K) Ordering[T] {
+	return func(a, b T) int { return cmp.Compare(key(a), key(b)) }
+}
+
+// ThenBy returns an Ordering that orders by o, then breaks ties by next.
+func (o Ordering[T]) ThenBy(next Ordering[T]) Ordering[T] {
+	return func(a, b T) int {
+		if c := o(a, b); c != 0 {
+			return c
+		}
+		return next(a, b)
+	}
+}
+
+// Reverse returns o in descending order.
+func (o Ordering[T]) Reverse() Ordering[T] {
+	return func(a, b T) int { return o(b, a) }
+}
@@ -0,0 +1,57 @@
+package golib
+
+import "slices"
+import "strings"
+import "testing"
+
+type person struct {
+	first, last string
+	age         int
+}
+
+func TestSortBy(t *testing.T) {
+	words := []string{"ccc", "a", "bb", ""}
+	SortBy(words, func(s string) int { return len(s) })
+	if want := []string{"", "a", "bb", "ccc"}; !slices.Equal(words, want) {
+		t.Errorf("SortBy == %q, want %q", words, want)
+	}
+
+	words = []string{"Bob", "al", "Cy", "bea", "ann"}
+	SortStableBy(words, func(s string) string { return strings.ToLower(s[:1]) })
+	if want := []string{"al", "ann", "Bob", "bea", "Cy"}; !slices.Equal(words, want) {
+		t.Errorf("SortStableBy == %q, want %q", words, want)
+	}
+}
+
+func TestOrdering(t *testing.T) {
+	people := []person{
+		{"Grace", "Hopper", 85},
+		{"Ada", "Lovelace", 36},
+		{"Alan", "Turing", 41},
+		{"Walter", "Hopper", 41},
+		{"Ada", "Hopper", 36},
+	}
+	last := func(p person) string { return p.last }
+	first := func(p person) string { return p.first }
+	age := func(p person) int { return p.age }
+
+	slices.SortFunc(people, By(last).ThenBy(By(first)))
+	var names []string
+	for _, p := range people {
+		names = append(names, p.first+" "+p.last)
+	}
+	want := []string{"Ada Hopper", "Grace Hopper", "Walter Hopper", "Ada Lovelace", "Alan Turing"}
+	if !slices.Equal(names, want) {
+		t.Errorf("by last then first == %q, want %q", names, want)
+	}
+
+	slices.SortStableFunc(people, By(age).Reverse().ThenBy(By(last).Reverse()))
+	names = names[:0]
+	for _, p := range people {
+		names = append(names, p.first+" "+p.last)
+	}
+	want = []string{"Grace Hopper", "Alan Turing", "Walter Hopper", "Ada Lovelace", "Ada Hopper"}
+	if !slices.Equal(names, want) {
+		t.Errorf("by age then last, descending == %q, want %q", names, want)
+	}
+}
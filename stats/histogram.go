@@ -0,0 +1,35 @@
+package stats
+
+import "slices"
+
+// Bin is one bar of a histogram: the count of values in [Lo, Hi). The
+// last bin of a histogram also includes its Hi.
+type Bin struct {
+	Lo, Hi float64
+	Count  int
+}
+
+// Histogram sorts xs into n bins of equal width spanning their range. If
+// all values are equal, the bins span a unit interval centred on them.
+func Histogram[T Number](xs []T, n int) ([]Bin, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	n = max(n, 1)
+	lo, hi := float64(slices.Min(xs)), float64(slices.Max(xs))
+	if lo == hi {
+		lo, hi = lo-0.5, hi+0.5
+	}
+	width := (hi - lo) / float64(n)
+	bins := make([]Bin, n)
+	for i := range bins {
+		bins[i].Lo = lo + float64(i)*width
+		bins[i].Hi = lo + float64(i+1)*width
+	}
+	bins[n-1].Hi = hi // avoid rounding error at the top edge
+	for _, x := range xs {
+		i := int((float64(x) - lo) / width)
+		bins[min(max(i, 0), n-1)].Count++
+	}
+	return bins, nil
+}
@@ -0,0 +1,27 @@
+package stats
+
+import "testing"
+
+func TestHistogram(t *testing.T) {
+	bins, err := Histogram([]int{1, 2, 2, 3, 4, 5, 9, 10}, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Bin{{1, 4, 4}, {4, 7, 2}, {7, 10, 2}}
+	if len(bins) != len(want) {
+		t.Fatalf("Histogram() == %v", bins)
+	}
+	for i := range want {
+		if bins[i] != want[i] {
+			t.Errorf("bin %d == %v, want %v", i, bins[i], want[i])
+		}
+	}
+
+	bins, _ = Histogram([]float64{7, 7, 7}, 2)
+	if bins[0].Lo != 6.5 || bins[1].Hi != 7.5 || bins[0].Count+bins[1].Count != 3 {
+		t.Errorf("Histogram(constant) == %v", bins)
+	}
+	if _, err := Histogram([]float64{}, 3); err != ErrEmpty {
+		t.Errorf("Histogram(empty) error == %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package stats
+
+import "math"
+
+// Online accumulates the count, mean, variance and range of a stream of
+// values in constant memory using Welford's algorithm, which stays
+// numerically stable where summing squares would not. The zero value is
+// ready to use.
+type Online struct {
+	n        int
+	mean     float64
+	m2       float64 // sum of squared differences from the mean
+	min, max float64
+}
+
+// Add records x.
+func (o *Online) Add(x float64) {
+	o.n++
+	if o.n == 1 {
+		o.min, o.max = x, x
+	} else {
+		o.min, o.max = math.Min(o.min, x), math.Max(o.max, x)
+	}
+	delta := x - o.mean
+	o.mean += delta / float64(o.n)
+	o.m2 += delta * (x - o.mean)
+}
+
+// Merge folds the values recorded by other into o, as if they had been
+// added to o directly. It lets partial results from several goroutines be
+// combined.
+func (o *Online) Merge(other *Online) {
+	switch {
+	case other.n == 0:
+		return
+	case o.n == 0:
+		*o = *other
+		return
+	}
+	n := o.n + other.n
+	delta := other.mean - o.mean
+	o.m2 += other.m2 + delta*delta*float64(o.n)*float64(other.n)/float64(n)
+	o.mean += delta * float64(other.n) / float64(n)
+	o.min, o.max = math.Min(o.min, other.min), math.Max(o.max, other.max)
+	o.n = n
+}
+
+// N returns the number of values recorded.
+func (o *Online) N() int { return o.n }
+
+// Mean returns the mean of the values, or 0 if there are none.
+func (o *Online) Mean() float64 { return o.mean }
+
+// Min returns the smallest value, or 0 if there are none.
+func (o *Online) Min() float64 { return o.min }
+
+// Max returns the largest value, or 0 if there are none.
+func (o *Online) Max() float64 { return o.max }
+
+// Variance returns the population variance, or 0 if there are no values.
+func (o *Online) Variance() float64 {
+	if o.n == 0 {
+		return 0
+	}
+	return o.m2 / float64(o.n)
+}
+
+// SampleVariance returns the sample variance, or 0 for fewer than two
+// values.
+func (o *Online) SampleVariance() float64 {
+	if o.n < 2 {
+		return 0
+	}
+	return o.m2 / float64(o.n-1)
+}
+
+// StdDev returns the population standard deviation.
+func (o *Online) StdDev() float64 { return math.Sqrt(o.Variance()) }
+
+// SampleStdDev returns the sample standard deviation.
+func (o *Online) SampleStdDev() float64 { return math.Sqrt(o.SampleVariance()) }
@@ -0,0 +1,144 @@
+// Package stats provides descriptive statistics over numeric slices, and
+// an Online accumulator for streams too large to hold in memory.
+package stats
+
+import "errors"
+import "math"
+import "slices"
+
+// Number is any integer or floating-point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+var (
+	// ErrEmpty is returned when a statistic is undefined for no values.
+	ErrEmpty = errors.New("stats: empty input")
+	// ErrTooFew is returned by sample statistics given fewer than two values.
+	ErrTooFew = errors.New("stats: need at least two values")
+	// ErrRange is returned for a quantile outside [0, 1] or a percentile
+	// outside [0, 100].
+	ErrRange = errors.New("stats: quantile out of range")
+)
+
+// Sum returns the sum of xs as a float64, avoiding integer overflow.
+func Sum[T Number](xs []T) float64 {
+	var s float64
+	for _, x := range xs {
+		s += float64(x)
+	}
+	return s
+}
+
+// Mean returns the arithmetic mean of xs.
+func Mean[T Number](xs []T) (float64, error) {
+	if len(xs) == 0 {
+		return 0, ErrEmpty
+	}
+	return Sum(xs) / float64(len(xs)), nil
+}
+
+// Median returns the middle value of xs, or the mean of the two middle
+// values when len(xs) is even.
+func Median[T Number](xs []T) (float64, error) {
+	return Quantile(xs, 0.5)
+}
+
+// Mode returns the most frequent values in xs in ascending order. Every
+// value is returned when all occur equally often.
+func Mode[T Number](xs []T) ([]T, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	counts := map[T]int{}
+	best := 0
+	for _, x := range xs {
+		counts[x]++
+		best = max(best, counts[x])
+	}
+	var modes []T
+	for x, n := range counts {
+		if n == best {
+			modes = append(modes, x)
+		}
+	}
+	slices.Sort(modes)
+	return modes, nil
+}
+
+// Variance returns the population variance of xs.
+func Variance[T Number](xs []T) (float64, error) {
+	if len(xs) == 0 {
+		return 0, ErrEmpty
+	}
+	return online(xs).Variance(), nil
+}
+
+// SampleVariance returns the unbiased sample variance of xs, dividing by
+// n-1.
+func SampleVariance[T Number](xs []T) (float64, error) {
+	if len(xs) < 2 {
+		return 0, ErrTooFew
+	}
+	return online(xs).SampleVariance(), nil
+}
+
+// StdDev returns the population standard deviation of xs.
+func StdDev[T Number](xs []T) (float64, error) {
+	v, err := Variance(xs)
+	return math.Sqrt(v), err
+}
+
+// SampleStdDev returns the sample standard deviation of xs.
+func SampleStdDev[T Number](xs []T) (float64, error) {
+	v, err := SampleVariance(xs)
+	return math.Sqrt(v), err
+}
+
+func online[T Number](xs []T) *Online {
+	var o Online
+	for _, x := range xs {
+		o.Add(float64(x))
+	}
+	return &o
+}
+
+// Quantile returns the q-th quantile of xs for q in [0, 1], interpolating
+// linearly between the closest ranks (the method used by most spreadsheet
+// software). xs need not be sorted and is not modified.
+func Quantile[T Number](xs []T, q float64) (float64, error) {
+	qs, err := Quantiles(xs, q)
+	if err != nil {
+		return 0, err
+	}
+	return qs[0], nil
+}
+
+// Quantiles is like Quantile for several quantiles at once, sorting xs
+// only once.
+func Quantiles[T Number](xs []T, qs ...float64) ([]float64, error) {
+	if len(xs) == 0 {
+		return nil, ErrEmpty
+	}
+	sorted := slices.Clone(xs)
+	slices.Sort(sorted)
+	out := make([]float64, len(qs))
+	for i, q := range qs {
+		if q < 0 || q > 1 || math.IsNaN(q) {
+			return nil, ErrRange
+		}
+		pos := q * float64(len(sorted)-1)
+		lo := int(math.Floor(pos))
+		hi := min(lo+1, len(sorted)-1)
+		frac := pos - float64(lo)
+		out[i] = float64(sorted[lo]) + frac*(float64(sorted[hi])-float64(sorted[lo]))
+	}
+	return out, nil
+}
+
+// Percentile returns the p-th percentile of xs for p in [0, 100].
+func Percentile[T Number](xs []T, p float64) (float64, error) {
+	return Quantile(xs, p/100)
+}
@@ -0,0 +1,103 @@
+package stats
+
+import "math"
+import "reflect"
+import "testing"
+
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+func TestDescriptive(t *testing.T) {
+	xs := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	cases := []struct {
+		name string
+		fn   func([]int) (float64, error)
+		want float64
+	}{
+		{"Mean", Mean[int], 5},
+		{"Median", Median[int], 4.5},
+		{"Variance", Variance[int], 4},
+		{"StdDev", StdDev[int], 2},
+		{"SampleVariance", SampleVariance[int], 32.0 / 7},
+	}
+	for _, c := range cases {
+		got, err := c.fn(xs)
+		if err != nil || !near(got, c.want) {
+			t.Errorf("%s(%v) == %v, %v, want %v", c.name, xs, got, err, c.want)
+		}
+	}
+
+	if _, err := Mean([]float64{}); err != ErrEmpty {
+		t.Errorf("Mean of empty slice: err == %v, want ErrEmpty", err)
+	}
+	if _, err := SampleStdDev([]float64{1}); err != ErrTooFew {
+		t.Errorf("SampleStdDev of one value: err == %v, want ErrTooFew", err)
+	}
+}
+
+func TestMode(t *testing.T) {
+	cases := []struct {
+		in   []int
+		want []int
+	}{
+		{[]int{1, 2, 2, 3}, []int{2}},
+		{[]int{3, 1, 3, 1, 2}, []int{1, 3}},
+		{[]int{5}, []int{5}},
+	}
+	for _, c := range cases {
+		got, err := Mode(c.in)
+		if err != nil || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Mode(%v) == %v, %v, want %v", c.in, got, err, c.want)
+		}
+	}
+}
+
+func TestQuantile(t *testing.T) {
+	xs := []float64{15, 20, 35, 40, 50}
+	cases := []struct {
+		q, want float64
+	}{
+		{0, 15}, {0.25, 20}, {0.5, 35}, {0.4, 29}, {1, 50},
+	}
+	for _, c := range cases {
+		got, err := Quantile(xs, c.q)
+		if err != nil || !near(got, c.want) {
+			t.Errorf("Quantile(%v, %v) == %v, %v, want %v", xs, c.q, got, err, c.want)
+		}
+	}
+	if p, _ := Percentile(xs, 90); !near(p, 46) {
+		t.Errorf("Percentile(90) == %v, want 46", p)
+	}
+	if _, err := Quantile(xs, 1.5); err != ErrRange {
+		t.Errorf("Quantile(1.5): err == %v, want ErrRange", err)
+	}
+	if xs[0] != 15 || xs[4] != 50 {
+		t.Error("Quantile modified its input")
+	}
+}
+
+func TestOnline(t *testing.T) {
+	var all, left, right Online
+	for i, x := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		all.Add(x)
+		if i < 3 {
+			left.Add(x)
+		} else {
+			right.Add(x)
+		}
+	}
+	left.Merge(&right)
+	for _, o := range []*Online{&all, &left} {
+		if o.N() != 8 || !near(o.Mean(), 5) || !near(o.StdDev(), 2) || o.Min() != 2 || o.Max() != 9 {
+			t.Errorf("Online = n %d, mean %v, stddev %v, range [%v, %v]", o.N(), o.Mean(), o.StdDev(), o.Min(), o.Max())
+		}
+	}
+
+	// Values with a large offset lose precision with the naive formula.
+	var big Online
+	for _, x := range []float64{1e9 + 4, 1e9 + 7, 1e9 + 13, 1e9 + 16} {
+		big.Add(x)
+	}
+	if !near(big.SampleVariance(), 30) {
+		t.Errorf("SampleVariance with large offset == %v, want 30", big.SampleVariance())
+	}
+}
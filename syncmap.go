@@ -0,0 +1,205 @@
+package golib
+
+import "hash/maphash"
+import "iter"
+import "sync"
+
+// ConcurrentMap is a map safe for concurrent use, implemented by SyncMap
+// and ShardedMap.
+type ConcurrentMap[K comparable, V any] interface {
+	Load(k K) (V, bool)
+	Store(k K, v V)
+	Delete(k K)
+	LoadOrStore(k K, v V) (actual V, loaded bool)
+	LoadOrCompute(k K, fn func() V) (actual V, loaded bool)
+	LoadAndDelete(k K) (V, bool)
+	Range(fn func(K, V) bool)
+}
+
+// SyncMap is a sync.Map with typed keys and values. Like sync.Map, it
+// suits keys that are written once and read many times, or disjoint sets
+// of keys used by different goroutines. The zero value is empty and ready
+// to use.
+type SyncMap[K comparable, V any] struct {
+	m sync.Map
+}
+
+// Load returns the value for k.
+func (m *SyncMap[K, V]) Load(k K) (V, bool) {
+	v, ok := m.m.Load(k)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	x, _ := v.(V) // a nil interface V is stored as nil, which fails v.(V)
+	return x, true
+}
+
+// Store sets the value for k.
+func (m *SyncMap[K, V]) Store(k K, v V) { m.m.Store(k, v) }
+
+// Delete removes k.
+func (m *SyncMap[K, V]) Delete(k K) { m.m.Delete(k) }
+
+// LoadOrStore returns the existing value for k if there is one.
+// Otherwise it stores and returns v. loaded reports which.
+func (m *SyncMap[K, V]) LoadOrStore(k K, v V) (actual V, loaded bool) {
+	a, loaded := m.m.LoadOrStore(k, v)
+	x, _ := a.(V)
+	return x, loaded
+}
+
+// LoadOrCompute is like LoadOrStore but calls fn for the value only if k
+// is absent. Goroutines racing to add the same key may each call fn, but
+// all get the value stored first.
+func (m *SyncMap[K, V]) LoadOrCompute(k K, fn func() V) (actual V, loaded bool) {
+	if v, ok := m.Load(k); ok {
+		return v, true
+	}
+	return m.LoadOrStore(k, fn())
+}
+
+// LoadAndDelete removes k, returning its value if it had one.
+func (m *SyncMap[K, V]) LoadAndDelete(k K) (V, bool) {
+	v, ok := m.m.LoadAndDelete(k)
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	x, _ := v.(V)
+	return x, true
+}
+
+// Range calls fn for each key and value, in no particular order, until fn
+// returns false. It has the consistency of sync.Map.Range.
+func (m *SyncMap[K, V]) Range(fn func(K, V) bool) {
+	m.m.Range(func(k, v any) bool {
+		kk, _ := k.(K)
+		vv, _ := v.(V)
+		return fn(kk, vv)
+	})
+}
+
+// All yields the keys and values of m as Range visits them.
+func (m *SyncMap[K, V]) All() iter.Seq2[K, V] { return m.Range }
+
+// ShardedMap is a map split into shards, each a plain map behind its own
+// lock, so that goroutines writing different keys rarely contend. It
+// suits workloads with many writes, where sync.Map does poorly.
+type ShardedMap[K comparable, V any] struct {
+	seed   maphash.Seed
+	shards []shard[K, V]
+}
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  map[K]V
+}
+
+// NewShardedMap returns an empty map with n shards. Zero or less means
+// 32.
+func NewShardedMap[K comparable, V any](n int) *ShardedMap[K, V] {
+	if n <= 0 {
+		n = 32
+	}
+	m := &ShardedMap[K, V]{seed: maphash.MakeSeed(), shards: make([]shard[K, V], n)}
+	for i := range m.shards {
+		m.shards[i].m = map[K]V{}
+	}
+	return m
+}
+
+func (m *ShardedMap[K, V]) shard(k K) *shard[K, V] {
+	return &m.shards[maphash.Comparable(m.seed, k)%uint64(len(m.shards))]
+}
+
+// Load returns the value for k.
+func (m *ShardedMap[K, V]) Load(k K) (V, bool) {
+	s := m.shard(k)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.m[k]
+	return v, ok
+}
+
+// Store sets the value for k.
+func (m *ShardedMap[K, V]) Store(k K, v V) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[k] = v
+}
+
+// Delete removes k.
+func (m *ShardedMap[K, V]) Delete(k K) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, k)
+}
+
+// LoadOrStore returns the existing value for k if there is one.
+// Otherwise it stores and returns v. loaded reports which.
+func (m *ShardedMap[K, V]) LoadOrStore(k K, v V) (actual V, loaded bool) {
+	return m.LoadOrCompute(k, func() V { return v })
+}
+
+// LoadOrCompute is like LoadOrStore but calls fn for the value only if k
+// is absent. fn is called at most once per missing key, with k's shard
+// locked, so it must not use m.
+func (m *ShardedMap[K, V]) LoadOrCompute(k K, fn func() V) (actual V, loaded bool) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[k]; ok {
+		return v, true
+	}
+	v := fn()
+	s.m[k] = v
+	return v, false
+}
+
+// LoadAndDelete removes k, returning its value if it had one.
+func (m *ShardedMap[K, V]) LoadAndDelete(k K) (V, bool) {
+	s := m.shard(k)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[k]
+	delete(s.m, k)
+	return v, ok
+}
+
+// Len returns the number of keys in m.
+func (m *ShardedMap[K, V]) Len() int {
+	n := 0
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Range calls fn for each key and value, in no particular order, until fn
+// returns false. Each shard is copied before fn sees it, so fn may use m,
+// but it may miss changes made during the walk.
+func (m *ShardedMap[K, V]) Range(fn func(K, V) bool) {
+	for i := range m.shards {
+		s := &m.shards[i]
+		s.mu.RLock()
+		pairs := make([]Pair[K, V], 0, len(s.m))
+		for k, v := range s.m {
+			pairs = append(pairs, Pair[K, V]{k, v})
+		}
+		s.mu.RUnlock()
+		for _, p := range pairs {
+			if !fn(p.First, p.Second) {
+				return
+			}
+		}
+	}
+}
+
+// All yields the keys and values of m as Range visits them.
+func (m *ShardedMap[K, V]) All() iter.Seq2[K, V] { return m.Range }
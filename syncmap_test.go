@@ -0,0 +1,106 @@
+package golib
+
+import "errors"
+import "sync"
+import "sync/atomic"
+import "testing"
+
+var (
+	_ ConcurrentMap[string, int] = (*SyncMap[string, int])(nil)
+	_ ConcurrentMap[string, int] = (*ShardedMap[string, int])(nil)
+)
+
+func testConcurrentMap(t *testing.T, m ConcurrentMap[int, int]) {
+	t.Helper()
+	if _, ok := m.Load(1); ok {
+		t.Fatal("Load on empty map found a value")
+	}
+	m.Store(1, 10)
+	if v, ok := m.Load(1); v != 10 || !ok {
+		t.Errorf("Load(1) == %d, %v", v, ok)
+	}
+	if v, loaded := m.LoadOrStore(1, 11); v != 10 || !loaded {
+		t.Errorf("LoadOrStore(1) == %d, %v", v, loaded)
+	}
+	if v, loaded := m.LoadOrStore(2, 20); v != 20 || loaded {
+		t.Errorf("LoadOrStore(2) == %d, %v", v, loaded)
+	}
+	if v, ok := m.LoadAndDelete(2); v != 20 || !ok {
+		t.Errorf("LoadAndDelete(2) == %d, %v", v, ok)
+	}
+	m.Delete(1)
+	if _, ok := m.Load(1); ok {
+		t.Error("Delete(1) left a value")
+	}
+
+	// Concurrent LoadOrCompute calls all see the first value stored.
+	var wg sync.WaitGroup
+	var calls atomic.Int64
+	results := make([]int, 16)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = m.LoadOrCompute(3, func() int { return int(calls.Add(1)) })
+		}()
+	}
+	wg.Wait()
+	for _, r := range results {
+		if r != results[0] {
+			t.Fatalf("LoadOrCompute results differ: %v", results)
+		}
+	}
+
+	for i := range 100 {
+		m.Store(i, i*i)
+	}
+	n := 0
+	m.Range(func(k, v int) bool {
+		if v != k*k {
+			t.Errorf("Range yielded %d: %d", k, v)
+		}
+		n++
+		return true
+	})
+	if n != 100 {
+		t.Errorf("Range visited %d keys, want 100", n)
+	}
+}
+
+func TestSyncMap(t *testing.T) {
+	testConcurrentMap(t, &SyncMap[int, int]{})
+}
+
+func TestSyncMapNilInterface(t *testing.T) {
+	var m SyncMap[string, error]
+	m.Store("k", nil)
+	if v, ok := m.Load("k"); v != nil || !ok {
+		t.Errorf("Load == %v, %v", v, ok)
+	}
+	if v, loaded := m.LoadOrStore("k", errors.New("x")); v != nil || !loaded {
+		t.Errorf("LoadOrStore == %v, %v", v, loaded)
+	}
+	m.Range(func(k string, v error) bool {
+		if k != "k" || v != nil {
+			t.Errorf("Range visited %q, %v", k, v)
+		}
+		return true
+	})
+	if v, ok := m.LoadAndDelete("k"); v != nil || !ok {
+		t.Errorf("LoadAndDelete == %v, %v", v, ok)
+	}
+}
+
+func TestShardedMap(t *testing.T) {
+	m := NewShardedMap[int, int](4)
+	testConcurrentMap(t, m)
+	if m.Len() != 100 {
+		t.Errorf("Len() == %d, want 100", m.Len())
+	}
+	var calls int
+	m.LoadOrCompute(1000, func() int { calls++; return 0 })
+	m.LoadOrCompute(1000, func() int { calls++; return 0 })
+	if calls != 1 {
+		t.Errorf("LoadOrCompute called fn %d times, want 1", calls)
+	}
+}
@@ -0,0 +1,149 @@
+package golib
+
+import "fmt"
+import "io"
+import "strings"
+
+// Align controls how a Table column is padded.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignRight
+	AlignCenter
+)
+
+// Table collects rows of cells and renders them as aligned text columns,
+// optionally framed with Unicode box-drawing borders. Columns are sized by
+// display width, so wide CJK characters line up. The zero value is an
+// empty table ready to use.
+type Table struct {
+	headers  []string
+	rows     [][]string
+	align    map[int]Align
+	maxWidth int
+	borders  bool
+}
+
+// SetHeaders sets the column headings.
+func (t *Table) SetHeaders(headers ...string) *Table {
+	t.headers = headers
+	return t
+}
+
+// AddRow appends a row. Cells are formatted with fmt.Sprint.
+func (t *Table) AddRow(cells ...any) *Table {
+	row := make([]string, len(cells))
+	for i, c := range cells {
+		row[i] = fmt.Sprint(c)
+	}
+	t.rows = append(t.rows, row)
+	return t
+}
+
+// SetAlign sets the alignment of column col (counting from zero). Columns
+// are left-aligned by default.
+func (t *Table) SetAlign(col int, a Align) *Table {
+	if t.align == nil {
+		t.align = map[int]Align{}
+	}
+	t.align[col] = a
+	return t
+}
+
+// SetMaxWidth truncates cells wider than n columns, marking the cut with
+// an ellipsis. Zero means no limit.
+func (t *Table) SetMaxWidth(n int) *Table {
+	t.maxWidth = n
+	return t
+}
+
+// SetBorders enables or disables Unicode box-drawing borders.
+func (t *Table) SetBorders(on bool) *Table {
+	t.borders = on
+	return t
+}
+
+// Render writes the table to w.
+func (t *Table) Render(w io.Writer) error {
+	rows := t.rows
+	if len(t.headers) > 0 {
+		rows = append([][]string{t.headers}, rows...)
+	}
+
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	cells := make([][]string, len(rows))
+	widths := make([]int, cols)
+	for i, row := range rows {
+		cells[i] = make([]string, cols)
+		for j, c := range row {
+			if t.maxWidth > 0 {
+				c = TruncateWidth(c, t.maxWidth, "…")
+			}
+			cells[i][j] = c
+			widths[j] = max(widths[j], StringWidth(c))
+		}
+	}
+
+	var b strings.Builder
+	rule := func(left, mid, right string) {
+		b.WriteString(left)
+		for j, wd := range widths {
+			if j > 0 {
+				b.WriteString(mid)
+			}
+			b.WriteString(strings.Repeat("─", wd+2))
+		}
+		b.WriteString(right)
+		b.WriteByte('\n')
+	}
+	line := func(row []string) {
+		var l strings.Builder
+		if t.borders {
+			l.WriteString("│ ")
+		}
+		for j, c := range row {
+			if j > 0 {
+				if t.borders {
+					l.WriteString(" │ ")
+				} else {
+					l.WriteString("  ")
+				}
+			}
+			switch t.align[j] {
+			case AlignRight:
+				l.WriteString(PadLeft(c, widths[j], ' '))
+			case AlignCenter:
+				l.WriteString(Center(c, widths[j], ' '))
+			default:
+				l.WriteString(PadRight(c, widths[j], ' '))
+			}
+		}
+		if t.borders {
+			l.WriteString(" │")
+			b.WriteString(l.String())
+		} else {
+			b.WriteString(strings.TrimRight(l.String(), " "))
+		}
+		b.WriteByte('\n')
+	}
+
+	if t.borders {
+		rule("┌", "┬", "┐")
+	}
+	for i, row := range cells {
+		line(row)
+		if i == 0 && len(t.headers) > 0 && len(cells) > 1 && t.borders {
+			rule("├", "┼", "┤")
+		}
+	}
+	if t.borders {
+		rule("└", "┴", "┘")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
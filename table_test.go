@@ -0,0 +1,49 @@
+package golib
+
+import "strings"
+import "testing"
+
+func TestTableRender(t *testing.T) {
+	cases := []struct {
+		table *Table
+		want  string
+	}{
+		{
+			new(Table).SetHeaders("NAME", "AGE").AddRow("alice", 30).AddRow("bob", 4).SetAlign(1, AlignRight),
+			"NAME   AGE\n" +
+				"alice   30\n" +
+				"bob      4\n",
+		},
+		{
+			new(Table).AddRow("abcdefgh", "x").SetMaxWidth(4),
+			"abc…  x\n",
+		},
+		{
+			new(Table).AddRow("世界世界", "x").AddRow("ab", "y").SetMaxWidth(5),
+			"世界…  x\n" +
+				"ab     y\n",
+		},
+		{
+			new(Table).SetHeaders("K", "V").AddRow("a", "1").SetBorders(true).SetAlign(1, AlignCenter),
+			"┌───┬───┐\n" +
+				"│ K │ V │\n" +
+				"├───┼───┤\n" +
+				"│ a │ 1 │\n" +
+				"└───┴───┘\n",
+		},
+		{
+			new(Table).SetHeaders("世界", "x").AddRow("a", "b"),
+			"世界  x\n" +
+				"a     b\n",
+		},
+	}
+	for i, c := range cases {
+		var b strings.Builder
+		if err := c.table.Render(&b); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != c.want {
+			t.Errorf("case %d: Render() ==\n%s\nwant\n%s", i, got, c.want)
+		}
+	}
+}
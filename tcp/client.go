@@ -0,0 +1,142 @@
+package tcp
+
+import "context"
+import "errors"
+import "net"
+import "strings"
+import "sync"
+import "time"
+
+import "github.com/lukehedger/golib/framing"
+
+// Defaults for the zero fields of a Client.
+const (
+	DefaultDialTimeout = 5 * time.Second
+	DefaultRetries     = 3
+	DefaultBackoff     = 100 * time.Millisecond
+)
+
+// Client sends lines to a line-based server over a single connection,
+// dialling it on first use and redialling when it breaks. A Client is safe
+// for concurrent use; requests are sent one at a time.
+type Client struct {
+	Addr string
+
+	// DialTimeout overrides DefaultDialTimeout when positive.
+	DialTimeout time.Duration
+
+	// Retries is how many times a failed request is retried on a new
+	// connection. Zero means DefaultRetries; negative means none.
+	Retries int
+
+	// Backoff is the delay before the first retry, doubling for each
+	// one after. Zero means DefaultBackoff.
+	Backoff time.Duration
+
+	// MaxLineSize limits the length of a reply. Zero means
+	// framing.DefaultMaxFrameSize.
+	MaxLineSize int
+
+	mu   sync.Mutex
+	conn *Conn
+}
+
+// Do sends line and returns the server's one-line reply. If the
+// connection fails, Do reconnects and sends line again, so requests that
+// are not safe to repeat should be sent with Retries set to -1.
+func (c *Client) Do(ctx context.Context, line string) (string, error) {
+	return c.retry(ctx, line, true)
+}
+
+// Send sends line without waiting for a reply, reconnecting as Do does.
+func (c *Client) Send(ctx context.Context, line string) error {
+	_, err := c.retry(ctx, line, false)
+	return err
+}
+
+// Close closes the current connection, if any. The Client may still be
+// used; the next request dials again.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+func (c *Client) retry(ctx context.Context, line string, reply bool) (string, error) {
+	if strings.Contains(line, "\n") {
+		return "", framing.ErrDelimInFrame
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	retries := c.Retries
+	if retries == 0 {
+		retries = DefaultRetries
+	}
+	backoff := c.Backoff
+	if backoff <= 0 {
+		backoff = DefaultBackoff
+	}
+	for attempt := 0; ; attempt++ {
+		resp, err := c.try(ctx, line, reply)
+		if err == nil {
+			return resp, nil
+		}
+		if c.conn != nil {
+			c.conn.Close()
+			c.conn = nil
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if attempt >= retries {
+			return "", err
+		}
+		t := time.NewTimer(backoff << attempt)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return "", ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (c *Client) try(ctx context.Context, line string, reply bool) (string, error) {
+	if c.conn == nil {
+		if c.Addr == "" {
+			return "", errors.New("tcp: client has no address")
+		}
+		timeout := c.DialTimeout
+		if timeout <= 0 {
+			timeout = DefaultDialTimeout
+		}
+		d := net.Dialer{Timeout: timeout}
+		nc, err := d.DialContext(ctx, "tcp", c.Addr)
+		if err != nil {
+			return "", err
+		}
+		c.conn = NewConn(nc, c.MaxLineSize, 0)
+	}
+
+	conn := c.conn
+	if d, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(d)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer func() {
+		if stop() {
+			conn.SetDeadline(time.Time{})
+		}
+	}()
+
+	if err := conn.WriteLine(line); err != nil || !reply {
+		return "", err
+	}
+	return conn.ReadLine()
+}
@@ -0,0 +1,86 @@
+package tcp
+
+import "context"
+import "errors"
+import "net"
+import "sync/atomic"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/framing"
+
+// testContext returns a context that expires well before the test binary
+// times out, so that a missing reply fails the test instead of hanging it.
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestClient(t *testing.T) {
+	addr, _ := start(t, &Server{Handler: Echo})
+	c := &Client{Addr: addr}
+	defer c.Close()
+	ctx := testContext(t)
+	for _, line := range []string{"a", "b", ""} {
+		if got, err := c.Do(ctx, line); err != nil || got != line {
+			t.Errorf("Do(%q) == %q, %v", line, got, err)
+		}
+	}
+	if _, err := c.Do(ctx, "a\nb"); err != framing.ErrDelimInFrame {
+		t.Errorf("Do with a newline: err == %v, want ErrDelimInFrame", err)
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	// The server hangs up after every reply, so each request after the
+	// first finds a dead connection and must redial.
+	var conns atomic.Int32
+	h := HandlerFunc(func(ctx context.Context, c *Conn) {
+		conns.Add(1)
+		if line, err := c.ReadLine(); err == nil {
+			c.WriteLine(line)
+		}
+	})
+	addr, _ := start(t, &Server{Handler: h})
+	c := &Client{Addr: addr, Backoff: time.Millisecond}
+	defer c.Close()
+	for _, line := range []string{"one", "two", "three"} {
+		if got, err := c.Do(testContext(t), line); err != nil || got != line {
+			t.Errorf("Do(%q) == %q, %v", line, got, err)
+		}
+	}
+	if n := conns.Load(); n != 3 {
+		t.Errorf("server saw %d connections, want 3", n)
+	}
+}
+
+func TestClientGivesUp(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	c := &Client{Addr: addr, Retries: 2, Backoff: time.Millisecond}
+	if _, err := c.Do(testContext(t), "x"); err == nil {
+		t.Errorf("Do to a closed port succeeded")
+	}
+}
+
+func TestClientContext(t *testing.T) {
+	h := HandlerFunc(func(ctx context.Context, c *Conn) {
+		c.ReadLine()
+		<-ctx.Done() // never reply
+	})
+	addr, _ := start(t, &Server{Handler: h})
+	c := &Client{Addr: addr}
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(testContext(t), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.Do(ctx, "x"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do with expired context: err == %v, want DeadlineExceeded", err)
+	}
+}
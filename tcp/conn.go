@@ -0,0 +1,75 @@
+package tcp
+
+import "errors"
+import "net"
+import "os"
+import "strings"
+import "sync"
+import "time"
+
+import "github.com/lukehedger/golib/framing"
+
+// ErrServerClosed is returned by Conn.ReadLine once the server has begun
+// shutting down, so that handlers stop reading new requests.
+var ErrServerClosed = errors.New("tcp: server closed")
+
+// Conn is a connection that reads and writes newline-terminated lines.
+// Lines may also end in "\r\n"; the "\r" is dropped.
+type Conn struct {
+	net.Conn
+
+	r    *framing.DelimReader
+	w    *framing.DelimWriter
+	idle time.Duration
+
+	mu       sync.Mutex
+	draining bool
+}
+
+// NewConn wraps c. Lines longer than maxLine bytes are rejected with
+// framing.ErrFrameTooBig; zero means framing.DefaultMaxFrameSize. If idle
+// is positive, ReadLine fails when no line arrives within that time.
+func NewConn(c net.Conn, maxLine int, idle time.Duration) *Conn {
+	r := framing.NewDelimReader(c, "\n")
+	r.MaxFrameSize = maxLine
+	return &Conn{Conn: c, r: r, w: framing.NewDelimWriter(c, "\n"), idle: idle}
+}
+
+// ReadLine returns the next line without its line ending.
+func (c *Conn) ReadLine() (string, error) {
+	c.mu.Lock()
+	if c.draining {
+		c.mu.Unlock()
+		return "", ErrServerClosed
+	}
+	if c.idle > 0 {
+		c.Conn.SetReadDeadline(time.Now().Add(c.idle))
+	}
+	c.mu.Unlock()
+
+	p, err := c.r.ReadFrame()
+	if err != nil {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.draining && errors.Is(err, os.ErrDeadlineExceeded) {
+			return "", ErrServerClosed
+		}
+		return "", err
+	}
+	return strings.TrimSuffix(string(p), "\r"), nil
+}
+
+// WriteLine writes s followed by a newline. s must not contain one.
+func (c *Conn) WriteLine(s string) error {
+	return c.w.WriteFrame([]byte(s))
+}
+
+// drain makes any pending and future ReadLine calls return
+// ErrServerClosed. A line already read is unaffected, so a handler can
+// finish replying to it.
+func (c *Conn) drain() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draining = true
+	c.Conn.SetReadDeadline(time.Now())
+}
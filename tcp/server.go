@@ -0,0 +1,205 @@
+// Package tcp runs line-based TCP servers and clients: a networking
+// counterpart to the serve package for protocols simpler than HTTP.
+//
+// A Server accepts connections and runs a Handler for each, enforcing a
+// connection limit and an idle timeout. When its context is cancelled it
+// stops accepting, lets handlers finish the request they are working on,
+// and then closes whatever connections remain.
+package tcp
+
+import "context"
+import "log"
+import "net"
+import "runtime/debug"
+import "sync"
+import "time"
+
+// DefaultIdleTimeout is how long a connection may wait for its next line
+// unless Server.IdleTimeout says otherwise.
+const DefaultIdleTimeout = 5 * time.Minute
+
+// DefaultShutdownTimeout bounds how long handlers may take to finish once
+// shutdown begins.
+const DefaultShutdownTimeout = 10 * time.Second
+
+// A Handler serves one connection. The connection is closed when
+// ServeConn returns. ctx is cancelled when the server shuts down.
+type Handler interface {
+	ServeConn(ctx context.Context, c *Conn)
+}
+
+// HandlerFunc adapts a function to a Handler.
+type HandlerFunc func(ctx context.Context, c *Conn)
+
+func (f HandlerFunc) ServeConn(ctx context.Context, c *Conn) { f(ctx, c) }
+
+// Lines returns a Handler that calls f for each line read and writes back
+// its reply, which may be an empty line. The connection is closed when the client
+// closes it, when f returns an error, or when the server shuts down.
+func Lines(f func(ctx context.Context, line string) (string, error)) Handler {
+	return HandlerFunc(func(ctx context.Context, c *Conn) {
+		for {
+			line, err := c.ReadLine()
+			if err != nil {
+				return
+			}
+			reply, err := f(ctx, line)
+			if err != nil {
+				return
+			}
+			if err := c.WriteLine(reply); err != nil {
+				return
+			}
+		}
+	})
+}
+
+// Echo is a Handler that writes every line back to its sender.
+var Echo = Lines(func(ctx context.Context, line string) (string, error) { return line, nil })
+
+// Server is a TCP server bound to a context.
+type Server struct {
+	Addr    string
+	Handler Handler
+
+	// MaxConns limits the number of connections served at once.
+	// Connections beyond it are closed as soon as they are accepted.
+	// Zero means no limit.
+	MaxConns int
+
+	// MaxLineSize limits the length of a line. Zero means
+	// framing.DefaultMaxFrameSize.
+	MaxLineSize int
+
+	// IdleTimeout overrides DefaultIdleTimeout when positive.
+	IdleTimeout time.Duration
+
+	// ShutdownTimeout overrides DefaultShutdownTimeout when positive.
+	ShutdownTimeout time.Duration
+
+	// ErrorLog receives handler panics and refused connections. Nil
+	// means log.Default().
+	ErrorLog *log.Logger
+}
+
+// ListenAndServe listens on s.Addr and serves until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is done, then stops
+// accepting, drains the open connections and returns. It returns nil
+// after a clean shutdown. If the handlers have not finished within the
+// shutdown timeout, Serve closes their connections and returns
+// context.DeadlineExceeded without waiting further.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		conns = make(map[*Conn]struct{})
+		wg    sync.WaitGroup
+	)
+	stop := context.AfterFunc(ctx, func() { ln.Close() })
+	defer stop()
+
+	var err error
+	for {
+		var nc net.Conn
+		nc, err = ln.Accept()
+		if err != nil {
+			break
+		}
+		mu.Lock()
+		if s.MaxConns > 0 && len(conns) >= s.MaxConns {
+			mu.Unlock()
+			s.logf("tcp: refusing %v: %d connections open", nc.RemoteAddr(), s.MaxConns)
+			nc.Close()
+			continue
+		}
+		c := NewConn(nc, s.MaxLineSize, s.idleTimeout())
+		conns[c] = struct{}{}
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				delete(conns, c)
+				mu.Unlock()
+			}()
+			s.serveConn(connCtx, c)
+		}()
+	}
+	if ctx.Err() == nil {
+		// The listener failed on its own; close the connections too.
+		cancel()
+		mu.Lock()
+		for c := range conns {
+			c.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+		return err
+	}
+
+	// Cancelling connCtx (by way of ctx) has already told every
+	// connection to stop reading; wait for the handlers to return.
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	timeout := s.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return nil
+	case <-timer.C:
+	}
+	mu.Lock()
+	for c := range conns {
+		c.Close()
+	}
+	mu.Unlock()
+	return context.DeadlineExceeded
+}
+
+func (s *Server) serveConn(ctx context.Context, c *Conn) {
+	defer c.Close()
+	defer func() {
+		if v := recover(); v != nil {
+			s.logf("tcp: panic serving %v: %v\n%s", c.RemoteAddr(), v, debug.Stack())
+		}
+	}()
+	// The connection must stop reading when shutdown begins even if the
+	// accept loop has not yet seen it.
+	stop := context.AfterFunc(ctx, c.drain)
+	defer stop()
+	s.Handler.ServeConn(ctx, c)
+}
+
+func (s *Server) idleTimeout() time.Duration {
+	if s.IdleTimeout > 0 {
+		return s.IdleTimeout
+	}
+	return DefaultIdleTimeout
+}
+
+func (s *Server) logf(format string, args ...any) {
+	l := s.ErrorLog
+	if l == nil {
+		l = log.Default()
+	}
+	l.Printf(format, args...)
+}
@@ -0,0 +1,163 @@
+package tcp
+
+import "bufio"
+import "context"
+import "io"
+import "log"
+import "net"
+import "strings"
+import "testing"
+import "time"
+
+// start serves s on a local port and returns its address and a function
+// that shuts it down and returns Serve's result.
+func start(t *testing.T, s *Server) (string, func() error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ErrorLog == nil {
+		s.ErrorLog = log.New(io.Discard, "", 0)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() { errc <- s.Serve(ctx, ln) }()
+	stopped := false
+	var err2 error
+	stop := func() error {
+		if !stopped {
+			cancel()
+			err2, stopped = <-errc, true
+		}
+		return err2
+	}
+	t.Cleanup(func() { stop() })
+	return ln.Addr().String(), stop
+}
+
+func dial(t *testing.T, addr string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	c.SetDeadline(time.Now().Add(5 * time.Second))
+	return c, bufio.NewReader(c)
+}
+
+func TestEcho(t *testing.T) {
+	addr, _ := start(t, &Server{Handler: Echo})
+	c, r := dial(t, addr)
+	io.WriteString(c, "hello\r\nworld\n")
+	for _, want := range []string{"hello\n", "world\n"} {
+		if got, err := r.ReadString('\n'); got != want {
+			t.Errorf("read %q, %v, want %q", got, err, want)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	h := Lines(func(ctx context.Context, line string) (string, error) {
+		if line == "quit" {
+			return "", io.EOF
+		}
+		if line == "blank" {
+			return "", nil
+		}
+		return strings.ToUpper(line), nil
+	})
+	addr, _ := start(t, &Server{Handler: h})
+	c, r := dial(t, addr)
+	io.WriteString(c, "blank\nabc\nquit\n")
+	for _, want := range []string{"\n", "ABC\n"} {
+		if got, err := r.ReadString('\n'); got != want {
+			t.Errorf("read %q, %v, want %q", got, err, want)
+		}
+	}
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("after quit: err == %v, want io.EOF", err)
+	}
+}
+
+func TestMaxConns(t *testing.T) {
+	addr, _ := start(t, &Server{Handler: Echo, MaxConns: 1})
+	c1, r1 := dial(t, addr)
+	io.WriteString(c1, "one\n")
+	if got, _ := r1.ReadString('\n'); got != "one\n" {
+		t.Fatalf("first connection read %q", got)
+	}
+	_, r2 := dial(t, addr)
+	if _, err := r2.ReadString('\n'); err == nil {
+		t.Errorf("second connection was served")
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	addr, _ := start(t, &Server{Handler: Echo, IdleTimeout: 20 * time.Millisecond})
+	_, r := dial(t, addr)
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("idle connection: err == %v, want io.EOF", err)
+	}
+}
+
+func TestShutdownDrains(t *testing.T) {
+	started := make(chan struct{})
+	h := Lines(func(ctx context.Context, line string) (string, error) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		return "done", nil
+	})
+	addr, stop := start(t, &Server{Handler: h})
+	_, idleR := dial(t, addr)
+	busy, busyR := dial(t, addr)
+	io.WriteString(busy, "work\n")
+
+	<-started
+	if err := stop(); err != nil {
+		t.Fatalf("Serve returned %v", err)
+	}
+	if got, _ := busyR.ReadString('\n'); got != "done\n" {
+		t.Errorf("in-flight request got %q, want it to complete", got)
+	}
+	if _, err := idleR.ReadString('\n'); err != io.EOF {
+		t.Errorf("idle connection: err == %v, want io.EOF", err)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	started := make(chan struct{})
+	h := HandlerFunc(func(ctx context.Context, c *Conn) {
+		close(started)
+		<-block
+	})
+	addr, stop := start(t, &Server{Handler: h, ShutdownTimeout: 20 * time.Millisecond})
+	_, r := dial(t, addr)
+	<-started
+	if err := stop(); err != context.DeadlineExceeded {
+		t.Errorf("Serve returned %v, want context.DeadlineExceeded", err)
+	}
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("connection left open after shutdown timeout: %v", err)
+	}
+}
+
+func TestPanic(t *testing.T) {
+	var logged strings.Builder
+	s := &Server{
+		Handler:  HandlerFunc(func(ctx context.Context, c *Conn) { panic("boom") }),
+		ErrorLog: log.New(&logged, "", 0),
+	}
+	addr, stop := start(t, s)
+	_, r := dial(t, addr)
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("err == %v, want io.EOF", err)
+	}
+	stop()
+	if !strings.Contains(logged.String(), "panic serving") {
+		t.Errorf("panic not logged: %q", logged.String())
+	}
+}
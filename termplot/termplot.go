@@ -0,0 +1,121 @@
+// Package termplot draws small charts with Unicode block characters for
+// display in a terminal: sparklines, horizontal bar charts and
+// histograms.
+package termplot
+
+import "fmt"
+import "io"
+import "math"
+import "strconv"
+import "strings"
+
+import "github.com/lukehedger/golib"
+import "github.com/lukehedger/golib/stats"
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws xs as a single line of bars scaled between their
+// minimum and maximum. If width is positive and xs is longer, runs of
+// values are averaged so that the line is width characters wide. NaN and
+// infinite values, which have no place on the scale, are drawn as spaces.
+func Sparkline(xs []float64, width int) string {
+	if width > 0 && len(xs) > width {
+		xs = resample(xs, width)
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, x := range xs {
+		if finite(x) {
+			lo, hi = math.Min(lo, x), math.Max(hi, x)
+		}
+	}
+	var b strings.Builder
+	for _, x := range xs {
+		switch {
+		case !finite(x):
+			b.WriteByte(' ')
+		case hi == lo:
+			b.WriteRune(sparks[len(sparks)/2])
+		default:
+			i := int((x - lo) / (hi - lo) * float64(len(sparks)-1))
+			b.WriteRune(sparks[i])
+		}
+	}
+	return b.String()
+}
+
+func finite(x float64) bool { return !math.IsNaN(x) && !math.IsInf(x, 0) }
+
+// resample averages xs into n buckets of near-equal size.
+func resample(xs []float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		lo, hi := i*len(xs)/n, (i+1)*len(xs)/n
+		sum, count := 0.0, 0
+		for _, x := range xs[lo:hi] {
+			if !math.IsNaN(x) {
+				sum += x
+				count++
+			}
+		}
+		out[i] = math.NaN()
+		if count > 0 {
+			out[i] = sum / float64(count)
+		}
+	}
+	return out
+}
+
+// Bar is one row of a bar chart.
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// eighths are the partial blocks for drawing bars at 1/8 resolution.
+var eighths = []rune(" ▏▎▍▌▋▊▉")
+
+// Bars writes a horizontal bar chart to w: one labelled row per bar with
+// its length proportional to its value and the longest bar width
+// characters long. Negative values are drawn as empty bars.
+func Bars(w io.Writer, bars []Bar, width int) error {
+	width = max(width, 1)
+	labelWidth, top := 0, 0.0
+	for _, b := range bars {
+		labelWidth = max(labelWidth, golib.StringWidth(b.Label))
+		top = math.Max(top, b.Value)
+	}
+	var sb strings.Builder
+	for _, b := range bars {
+		sb.WriteString(golib.PadRight(b.Label, labelWidth, ' '))
+		sb.WriteString(" │")
+		n := 0 // length in eighths
+		if top > 0 && b.Value > 0 {
+			n = int(math.Round(b.Value / top * float64(width*8)))
+		}
+		sb.WriteString(strings.Repeat("█", n/8))
+		if n%8 > 0 {
+			sb.WriteRune(eighths[n%8])
+		}
+		fmt.Fprintf(&sb, " %s\n", formatValue(b.Value))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Histogram writes bins as a bar chart labelled with their ranges.
+func Histogram(w io.Writer, bins []stats.Bin, width int) error {
+	bars := make([]Bar, len(bins))
+	for i, b := range bins {
+		bars[i] = Bar{formatValue(b.Lo) + " – " + formatValue(b.Hi), float64(b.Count)}
+	}
+	return Bars(w, bars, width)
+}
+
+// formatValue prints v with at most four significant digits after the
+// point, dropping trailing zeros.
+func formatValue(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
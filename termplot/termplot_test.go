@@ -0,0 +1,60 @@
+package termplot
+
+import "math"
+import "strings"
+import "testing"
+
+import "github.com/lukehedger/golib/stats"
+
+func TestSparkline(t *testing.T) {
+	cases := []struct {
+		xs    []float64
+		width int
+		want  string
+	}{
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8}, 0, "▁▂▃▄▅▆▇█"},
+		{[]float64{0, 10, 5}, 0, "▁█▄"},
+		{[]float64{3, 3, 3}, 0, "▅▅▅"},
+		{[]float64{1, math.NaN(), 2}, 0, "▁ █"},
+		{[]float64{1, math.Inf(1), 2, math.Inf(-1)}, 0, "▁ █ "},
+		{[]float64{math.Inf(1), 4}, 0, " ▅"},
+		{[]float64{0, 0, 10, 10}, 2, "▁█"},
+		{nil, 0, ""},
+	}
+	for _, c := range cases {
+		if got := Sparkline(c.xs, c.width); got != c.want {
+			t.Errorf("Sparkline(%v, %d) == %q, want %q", c.xs, c.width, got, c.want)
+		}
+	}
+}
+
+func TestBars(t *testing.T) {
+	var b strings.Builder
+	err := Bars(&b, []Bar{{"go", 4}, {"rust", 2}, {"c", 1}, {"neg", -1}}, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "go   │████ 4\n" +
+		"rust │██ 2\n" +
+		"c    │█ 1\n" +
+		"neg  │ -1\n"
+	if b.String() != want {
+		t.Errorf("Bars() ==\n%s\nwant\n%s", b.String(), want)
+	}
+
+	b.Reset()
+	Bars(&b, []Bar{{"a", 8}, {"b", 1}}, 2)
+	if got := strings.Split(b.String(), "\n")[1]; got != "b │▎ 1" {
+		t.Errorf("partial bar == %q", got)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	var b strings.Builder
+	Histogram(&b, []stats.Bin{{Lo: 0, Hi: 0.5, Count: 3}, {Lo: 0.5, Hi: 1, Count: 1}}, 3)
+	want := "0 – 0.5 │███ 3\n" +
+		"0.5 – 1 │█ 1\n"
+	if b.String() != want {
+		t.Errorf("Histogram() ==\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,139 @@
+package golib
+
+import "strings"
+import "unicode"
+import "unicode/utf8"
+
+// Wrap reflows s so that no line is longer than width runes, breaking at
+// whitespace. Blank lines separate paragraphs and are preserved; within a
+// paragraph, runs of whitespace (including newlines) collapse to a single
+// space. Words longer than width are left on a line of their own.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	var out []string
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, wrapWords(strings.Fields(strings.Join(para, " ")), width)...)
+			para = para[:0]
+		}
+	}
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) == "" {
+			flush()
+			out = append(out, "")
+			continue
+		}
+		para = append(para, line)
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+func wrapWords(words []string, width int) []string {
+	var lines []string
+	var line strings.Builder
+	n := 0 // runes in line
+	for _, w := range words {
+		wn := utf8.RuneCountInString(w)
+		if n > 0 && n+1+wn > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			n = 0
+		}
+		if n > 0 {
+			line.WriteByte(' ')
+			n++
+		}
+		line.WriteString(w)
+		n += wn
+	}
+	if n > 0 {
+		lines = append(lines, line.String())
+	}
+	return lines
+}
+
+// Indent adds prefix to the start of every non-blank line of s.
+func Indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Dedent removes the longest run of leading spaces and tabs common to
+// every non-blank line of s, so that text written indented in source, such
+// as a raw string literal, lines up at the left margin. Lines holding only
+// whitespace are emptied.
+func Dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	margin := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			margin, first = indent, false
+			continue
+		}
+		// Shorten the margin to the prefix it shares with this line.
+		i := 0
+		for i < len(margin) && i < len(indent) && margin[i] == indent[i] {
+			i++
+		}
+		margin = margin[:i]
+	}
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		} else {
+			lines[i] = strings.TrimPrefix(line, margin)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Truncate shortens s to at most n runes, replacing the removed tail with
+// suffix (for example "…" or "..."). The suffix counts towards n; if it
+// does not fit, s is simply cut at n runes. Multi-byte characters are
+// never split.
+func Truncate(s string, n int, suffix string) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	keep := n - utf8.RuneCountInString(suffix)
+	if keep < 0 {
+		return string(r[:max(n, 0)])
+	}
+	return string(r[:keep]) + suffix
+}
+
+// TruncateWords is like Truncate but cuts at the last word boundary that
+// fits, so no word is split. A single word too long to fit is cut as by
+// Truncate.
+func TruncateWords(s string, n int, suffix string) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	keep := n - utf8.RuneCountInString(suffix)
+	if keep < 0 {
+		return string(r[:max(n, 0)])
+	}
+	// Cut where the next rune is a space: r[keep] is the first rune dropped.
+	for i := keep; i > 0; i-- {
+		if unicode.IsSpace(r[i]) {
+			return strings.TrimRightFunc(string(r[:i]), unicode.IsSpace) + suffix
+		}
+	}
+	return string(r[:keep]) + suffix
+}
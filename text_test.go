@@ -0,0 +1,78 @@
+package golib
+
+import "testing"
+
+func TestWrap(t *testing.T) {
+	cases := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"the quick brown fox jumps", 10, "the quick\nbrown fox\njumps"},
+		{"a\nb c", 80, "a b c"},
+		{"one two\n\nthree four", 7, "one two\n\nthree\nfour"},
+		{"supercalifragilistic is long", 5, "supercalifragilistic\nis\nlong"},
+		{"世界 世界 世界", 5, "世界 世界\n世界"},
+		{"", 10, ""},
+	}
+	for _, c := range cases {
+		if got := Wrap(c.in, c.width); got != c.want {
+			t.Errorf("Wrap(%q, %d) == %q, want %q", c.in, c.width, got, c.want)
+		}
+	}
+}
+
+func TestIndent(t *testing.T) {
+	cases := []struct {
+		in, prefix, want string
+	}{
+		{"a\nb", "  ", "  a\n  b"},
+		{"a\n\nb\n", "> ", "> a\n\n> b\n"},
+	}
+	for _, c := range cases {
+		if got := Indent(c.in, c.prefix); got != c.want {
+			t.Errorf("Indent(%q, %q) == %q, want %q", c.in, c.prefix, got, c.want)
+		}
+	}
+}
+
+func TestDedent(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"    a\n      b\n    c", "a\n  b\nc"},
+		{"\n\tfunc f() {\n\t\treturn\n\t}\n", "\nfunc f() {\n\treturn\n}\n"},
+		{"  a\n   \n  b", "a\n\nb"},
+		{"a\n  b", "a\n  b"},
+	}
+	for _, c := range cases {
+		if got := Dedent(c.in); got != c.want {
+			t.Errorf("Dedent(%q) == %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		in     string
+		n      int
+		suffix string
+		want   string
+		words  string
+	}{
+		{"Hello, world", 20, "…", "Hello, world", "Hello, world"},
+		{"Hello, world", 8, "…", "Hello, …", "Hello,…"},
+		{"Hello, 世界 and more", 10, "...", "Hello, ...", "Hello,..."},
+		{"Hello, 世界世界", 9, "", "Hello, 世界", "Hello,"},
+		{"abcdef", 2, "...", "ab", "ab"},
+		{"abcdefgh", 5, "…", "abcd…", "abcd…"},
+	}
+	for _, c := range cases {
+		if got := Truncate(c.in, c.n, c.suffix); got != c.want {
+			t.Errorf("Truncate(%q, %d, %q) == %q, want %q", c.in, c.n, c.suffix, got, c.want)
+		}
+		if got := TruncateWords(c.in, c.n, c.suffix); got != c.words {
+			t.Errorf("TruncateWords(%q, %d, %q) == %q, want %q", c.in, c.n, c.suffix, got, c.words)
+		}
+	}
+}
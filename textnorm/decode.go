@@ -0,0 +1,108 @@
+package textnorm
+
+import "io"
+import "unicode/utf16"
+import "unicode/utf8"
+
+// windows1252 maps the bytes 0x80-0x9F of Windows-1252 to code points.
+// The five bytes it leaves undefined map to the C1 controls, as in Latin1.
+var windows1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// Decode returns a reader of the text in r, which is in encoding enc,
+// decoded to UTF-8. A byte order mark at the start for enc is dropped.
+// Invalid UTF-16, including a stream that ends partway through a
+// character, decodes to U+FFFD; UTF-8 is passed through unchecked.
+func Decode(r io.Reader, enc Encoding) io.Reader {
+	return &decoder{r: r, enc: enc, buf: make([]byte, 4096), bom: true}
+}
+
+type decoder struct {
+	r   io.Reader
+	enc Encoding
+	buf []byte
+	in  []byte // read but not yet decoded
+	out []byte // decoded but not yet returned
+	err error
+	bom bool // whether a BOM may still start the input
+}
+
+func (d *decoder) Read(p []byte) (int, error) {
+	for len(d.out) == 0 && d.err == nil {
+		n, err := d.r.Read(d.buf)
+		d.in = append(d.in, d.buf[:n]...)
+		d.err = err
+		d.decode(err != nil)
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	if len(d.out) == 0 {
+		d.out = d.out[:0:0]
+		return n, d.err
+	}
+	return n, nil
+}
+
+// decode moves what it can from in to out. Unless final, a character
+// split across reads is left in in for the next.
+func (d *decoder) decode(final bool) {
+	if d.bom {
+		enc, n := BOM(d.in)
+		if n == 0 && len(d.in) < 3 && !final {
+			return // too short to tell yet
+		}
+		if n > 0 && enc == d.enc {
+			d.in = d.in[n:]
+		}
+		d.bom = false
+	}
+	switch d.enc {
+	case UTF8:
+		d.out, d.in = append(d.out, d.in...), d.in[:0]
+	case Latin1, Windows1252:
+		for _, c := range d.in {
+			r := rune(c)
+			if d.enc == Windows1252 && 0x80 <= c && c <= 0x9F {
+				r = windows1252[c-0x80]
+			}
+			d.out = utf8.AppendRune(d.out, r)
+		}
+		d.in = d.in[:0]
+	case UTF16LE, UTF16BE:
+		d.decodeUTF16(final)
+	}
+}
+
+func (d *decoder) decodeUTF16(final bool) {
+	unit := func(i int) rune {
+		if d.enc == UTF16LE {
+			return rune(d.in[i]) | rune(d.in[i+1])<<8
+		}
+		return rune(d.in[i])<<8 | rune(d.in[i+1])
+	}
+	i := 0
+	for ; i+1 < len(d.in); i += 2 {
+		r := unit(i)
+		if utf16.IsSurrogate(r) {
+			if i+3 >= len(d.in) {
+				if !final {
+					break // wait for the low surrogate
+				}
+				r = utf8.RuneError
+			} else if dec := utf16.DecodeRune(r, unit(i+2)); dec != utf8.RuneError {
+				r = dec
+				i += 2
+			} else {
+				r = utf8.RuneError
+			}
+		}
+		d.out = utf8.AppendRune(d.out, r)
+	}
+	if final && i < len(d.in) {
+		d.out = utf8.AppendRune(d.out, utf8.RuneError)
+		i = len(d.in)
+	}
+	d.in = append(d.in[:0], d.in[i:]...)
+}
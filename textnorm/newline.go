@@ -0,0 +1,56 @@
+package textnorm
+
+import "io"
+import "strings"
+
+// Line endings for NormalizeNewlines.
+const (
+	LF   = "\n"
+	CRLF = "\r\n"
+)
+
+// NormalizeNewlines returns a reader of the text in r with every line
+// ending — CRLF, a lone CR or a lone LF — replaced by nl.
+func NormalizeNewlines(r io.Reader, nl string) io.Reader {
+	return &newlines{r: r, nl: nl, buf: make([]byte, 4096)}
+}
+
+type newlines struct {
+	r      io.Reader
+	nl     string
+	buf    []byte
+	out    []byte
+	err    error
+	skipLF bool // the last byte was a CR, so an LF next is part of it
+}
+
+func (n *newlines) Read(p []byte) (int, error) {
+	for len(n.out) == 0 && n.err == nil {
+		m, err := n.r.Read(n.buf)
+		n.err = err
+		for _, c := range n.buf[:m] {
+			switch {
+			case c == '\n' && n.skipLF:
+			case c == '\r' || c == '\n':
+				n.out = append(n.out, n.nl...)
+			default:
+				n.out = append(n.out, c)
+			}
+			n.skipLF = c == '\r'
+		}
+	}
+	m := copy(p, n.out)
+	n.out = n.out[m:]
+	if len(n.out) == 0 {
+		n.out = n.out[:0:0]
+		return m, n.err
+	}
+	return m, nil
+}
+
+// Newlines returns s with every line ending replaced by nl.
+func Newlines(s, nl string) string {
+	var b strings.Builder
+	io.Copy(&b, NormalizeNewlines(strings.NewReader(s), nl))
+	return b.String()
+}
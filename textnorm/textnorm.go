@@ -0,0 +1,144 @@
+// Package textnorm normalises text from the outside world: it detects and
+// strips byte order marks, decodes UTF-16 and the common single-byte
+// legacy encodings to UTF-8, and converts line endings, all as streaming
+// readers so that large files need not be held in memory.
+package textnorm
+
+import "bufio"
+import "bytes"
+import "io"
+import "unicode/utf8"
+
+// An Encoding is a character encoding that text can be decoded from.
+type Encoding int
+
+const (
+	UTF8 Encoding = iota
+	UTF16LE
+	UTF16BE
+	// Latin1 is ISO 8859-1, in which each byte is the code point of the
+	// same value.
+	Latin1
+	// Windows1252 is Latin1 with printable characters, such as curly
+	// quotes and the euro sign, in place of the C1 controls 0x80-0x9F.
+	Windows1252
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case UTF8:
+		return "utf-8"
+	case UTF16LE:
+		return "utf-16le"
+	case UTF16BE:
+		return "utf-16be"
+	case Latin1:
+		return "iso-8859-1"
+	case Windows1252:
+		return "windows-1252"
+	}
+	return "unknown"
+}
+
+// sniffLen is how much of a stream NewReader inspects.
+const sniffLen = 4096
+
+var boms = []struct {
+	enc Encoding
+	bom []byte
+}{
+	{UTF8, []byte{0xEF, 0xBB, 0xBF}},
+	{UTF16LE, []byte{0xFF, 0xFE}},
+	{UTF16BE, []byte{0xFE, 0xFF}},
+}
+
+// BOM reports the encoding announced by the byte order mark at the start
+// of p and the mark's length, which is 0 if there is none.
+func BOM(p []byte) (Encoding, int) {
+	for _, b := range boms {
+		if bytes.HasPrefix(p, b.bom) {
+			return b.enc, len(b.bom)
+		}
+	}
+	return UTF8, 0
+}
+
+// TrimBOM returns p without any byte order mark at its start.
+func TrimBOM(p []byte) []byte {
+	_, n := BOM(p)
+	return p[n:]
+}
+
+// Sniff guesses the encoding of text beginning with p. A byte order mark
+// decides it. Otherwise text with a NUL in most odd or even positions is
+// taken as UTF-16 and valid UTF-8 as UTF-8. Anything else is Windows1252
+// if it has bytes in 0x80-0x9F, which Latin1 text almost never does, or
+// Latin1. p may end partway through a character.
+func Sniff(p []byte) Encoding {
+	if enc, n := BOM(p); n > 0 {
+		return enc
+	}
+	if enc, ok := sniffUTF16(p); ok {
+		return enc
+	}
+	if utf8.Valid(trimPartialRune(p)) {
+		return UTF8
+	}
+	for _, c := range p {
+		if 0x80 <= c && c <= 0x9F {
+			return Windows1252
+		}
+	}
+	return Latin1
+}
+
+// sniffUTF16 looks for the NULs that the high bytes of ASCII characters
+// leave in UTF-16 text.
+func sniffUTF16(p []byte) (Encoding, bool) {
+	pairs := len(p) / 2
+	if pairs == 0 {
+		return 0, false
+	}
+	var even, odd int
+	for i := 0; i+1 < len(p); i += 2 {
+		if p[i] == 0 {
+			even++
+		}
+		if p[i+1] == 0 {
+			odd++
+		}
+	}
+	switch {
+	case odd*2 > pairs && even*10 < pairs:
+		return UTF16LE, true
+	case even*2 > pairs && odd*10 < pairs:
+		return UTF16BE, true
+	}
+	return 0, false
+}
+
+// trimPartialRune drops an incomplete UTF-8 sequence from the end of p.
+func trimPartialRune(p []byte) []byte {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return p[:i]
+			}
+			break
+		}
+	}
+	return p
+}
+
+// NewReader sniffs the encoding of the text in r from its first few
+// kilobytes and returns a reader of the text decoded to UTF-8, without a
+// byte order mark, along with the encoding detected.
+func NewReader(r io.Reader) (io.Reader, Encoding, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	p, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, 0, err
+	}
+	enc := Sniff(p)
+	return Decode(br, enc), enc, nil
+}
@@ -0,0 +1,105 @@
+package textnorm
+
+import "bytes"
+import "io"
+import "testing"
+import "testing/iotest"
+import "unicode/utf16"
+
+func utf16Bytes(s string, bigEndian, bom bool) []byte {
+	var b []byte
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xFEFF}, units...)
+	}
+	for _, u := range units {
+		if bigEndian {
+			b = append(b, byte(u>>8), byte(u))
+		} else {
+			b = append(b, byte(u), byte(u>>8))
+		}
+	}
+	return b
+}
+
+func TestSniff(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []byte
+		want Encoding
+	}{
+		{"ascii", []byte("hello"), UTF8},
+		{"utf-8", []byte("naïve café"), UTF8},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, "x"...), UTF8},
+		{"utf-8 cut short", []byte("café")[:4], UTF8},
+		{"utf-16le bom", utf16Bytes("hi", false, true), UTF16LE},
+		{"utf-16be bom", utf16Bytes("hi", true, true), UTF16BE},
+		{"utf-16le", utf16Bytes("hello, world", false, false), UTF16LE},
+		{"utf-16be", utf16Bytes("hello, world", true, false), UTF16BE},
+		{"latin-1", []byte("caf\xe9 cr\xe8me"), Latin1},
+		{"windows-1252", []byte("\x93quoted\x94"), Windows1252},
+	}
+	for _, c := range cases {
+		if got := Sniff(c.in); got != c.want {
+			t.Errorf("Sniff(%s) == %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	const text = "Grüße, 世界 😀\r\nbye"
+	cases := []struct {
+		name string
+		in   []byte
+		want string
+		enc  Encoding
+	}{
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, text...), text, UTF8},
+		{"utf-16le bom", utf16Bytes(text, false, true), text, UTF16LE},
+		{"utf-16be", utf16Bytes(text, true, false), text, UTF16BE},
+		{"latin-1", []byte("Gr\xfc\xdfe"), "Grüße", Latin1},
+		{"windows-1252", []byte("\x80 \x93x\x94 \x81"), "€ “x” \u0081", Windows1252},
+	}
+	for _, c := range cases {
+		// One byte at a time splits every character across reads.
+		r, enc, err := NewReader(iotest.OneByteReader(bytes.NewReader(c.in)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(iotest.OneByteReader(r))
+		if string(got) != c.want || err != nil || enc != c.enc {
+			t.Errorf("%s: read %q, %v as %v, want %q as %v", c.name, got, err, enc, c.want, c.enc)
+		}
+	}
+}
+
+func TestDecodeInvalidUTF16(t *testing.T) {
+	in := []byte{'a', 0, 0x3D, 0xD8, 'b', 0, 'c'} // lone high surrogate, odd byte
+	got, _ := io.ReadAll(Decode(bytes.NewReader(in), UTF16LE))
+	if string(got) != "a�b�" {
+		t.Errorf("Decode == %q", got)
+	}
+}
+
+func TestTrimBOM(t *testing.T) {
+	if got := TrimBOM([]byte("\xEF\xBB\xBFx")); string(got) != "x" {
+		t.Errorf("TrimBOM == %q", got)
+	}
+	if got := TrimBOM([]byte("x")); string(got) != "x" {
+		t.Errorf("TrimBOM == %q", got)
+	}
+}
+
+func TestNewlines(t *testing.T) {
+	const in = "a\r\nb\rc\nd\r\r\ne\r"
+	if got := Newlines(in, LF); got != "a\nb\nc\nd\n\ne\n" {
+		t.Errorf("Newlines(LF) == %q", got)
+	}
+	if got := Newlines(in, CRLF); got != "a\r\nb\r\nc\r\nd\r\n\r\ne\r\n" {
+		t.Errorf("Newlines(CRLF) == %q", got)
+	}
+	got, _ := io.ReadAll(NormalizeNewlines(iotest.OneByteReader(bytes.NewReader([]byte(in))), LF))
+	if string(got) != "a\nb\nc\nd\n\ne\n" {
+		t.Errorf("NormalizeNewlines one byte at a time == %q", got)
+	}
+}
@@ -0,0 +1,216 @@
+package golib
+
+import "slices"
+import "strings"
+
+// Trie maps string keys to values of type T and finds keys by prefix, as
+// autocomplete and routing need. Keys are compared byte by byte, and
+// walks visit keys in byte order.
+//
+// A Trie from NewRadixTrie is compressed: each edge holds a run of bytes
+// rather than a single byte, so keys with long unshared tails cost one
+// node each rather than one per byte. Both kinds behave the same.
+//
+// The zero value is an empty uncompressed trie ready to use. A Trie is
+// not safe for concurrent use.
+type Trie[T any] struct {
+	root  trieNode[T]
+	radix bool
+	len   int
+}
+
+type trieNode[T any] struct {
+	value T
+	has   bool
+	edges []trieEdge[T] // sorted by first byte, which is unique
+}
+
+type trieEdge[T any] struct {
+	label string
+	node  *trieNode[T]
+}
+
+// NewTrie returns an empty trie with an edge per byte.
+func NewTrie[T any]() *Trie[T] { return &Trie[T]{} }
+
+// NewRadixTrie returns an empty compressed trie.
+func NewRadixTrie[T any]() *Trie[T] { return &Trie[T]{radix: true} }
+
+// Len returns the number of keys in t.
+func (t *Trie[T]) Len() int { return t.len }
+
+// Insert sets the value for key, replacing any existing one.
+func (t *Trie[T]) Insert(key string, value T) {
+	n := &t.root
+	for key != "" {
+		i, ok := n.find(key[0])
+		if !ok {
+			n = n.add(i, key, t.radix)
+			break
+		}
+		e := &n.edges[i]
+		c := commonPrefix(e.label, key)
+		if c < len(e.label) {
+			// Split the edge where key leaves it.
+			mid := &trieNode[T]{edges: []trieEdge[T]{{e.label[c:], e.node}}}
+			e.label, e.node = e.label[:c], mid
+		}
+		n, key = e.node, key[c:]
+	}
+	if !n.has {
+		t.len++
+	}
+	n.value, n.has = value, true
+}
+
+// Get returns the value for key and whether it is present.
+func (t *Trie[T]) Get(key string) (T, bool) {
+	n := &t.root
+	for key != "" {
+		i, ok := n.find(key[0])
+		if !ok || !strings.HasPrefix(key, n.edges[i].label) {
+			var zero T
+			return zero, false
+		}
+		n, key = n.edges[i].node, key[len(n.edges[i].label):]
+	}
+	return n.value, n.has
+}
+
+// Delete removes key and reports whether it was present.
+func (t *Trie[T]) Delete(key string) bool {
+	if !t.delete(&t.root, key) {
+		return false
+	}
+	t.len--
+	return true
+}
+
+func (t *Trie[T]) delete(n *trieNode[T], key string) bool {
+	if key == "" {
+		if !n.has {
+			return false
+		}
+		var zero T
+		n.value, n.has = zero, false
+		return true
+	}
+	i, ok := n.find(key[0])
+	if !ok || !strings.HasPrefix(key, n.edges[i].label) {
+		return false
+	}
+	e := &n.edges[i]
+	if !t.delete(e.node, key[len(e.label):]) {
+		return false
+	}
+	// Prune the child if it is now empty, or in a radix trie merge it
+	// with its only child.
+	child := e.node
+	switch {
+	case !child.has && len(child.edges) == 0:
+		n.edges = slices.Delete(n.edges, i, i+1)
+	case t.radix && !child.has && len(child.edges) == 1:
+		e.label += child.edges[0].label
+		e.node = child.edges[0].node
+	}
+	return true
+}
+
+// HasPrefix reports whether any key in t begins with prefix.
+func (t *Trie[T]) HasPrefix(prefix string) bool {
+	n, _ := t.seek(prefix)
+	return n != nil && (n.has || len(n.edges) > 0)
+}
+
+// WalkPrefix calls fn for each key in t that begins with prefix, with its
+// value, in byte order, stopping if fn returns false. t must not be
+// changed during the walk.
+func (t *Trie[T]) WalkPrefix(prefix string, fn func(key string, value T) bool) {
+	if n, key := t.seek(prefix); n != nil {
+		n.walk(key, fn)
+	}
+}
+
+// LongestPrefix returns the longest key in t that is a prefix of s, with
+// its value, as a router matching a path against its routes would.
+func (t *Trie[T]) LongestPrefix(s string) (key string, value T, ok bool) {
+	n, depth := &t.root, 0
+	for {
+		if n.has {
+			key, value, ok = s[:depth], n.value, true
+		}
+		if depth == len(s) {
+			return key, value, ok
+		}
+		i, found := n.find(s[depth])
+		if !found || !strings.HasPrefix(s[depth:], n.edges[i].label) {
+			return key, value, ok
+		}
+		n, depth = n.edges[i].node, depth+len(n.edges[i].label)
+	}
+}
+
+// seek returns the node under which every key beginning with prefix lies,
+// and that node's key, which extends prefix if prefix ends partway along
+// an edge. It returns nil if no key begins with prefix.
+func (t *Trie[T]) seek(prefix string) (*trieNode[T], string) {
+	n, depth := &t.root, 0
+	for depth < len(prefix) {
+		i, ok := n.find(prefix[depth])
+		if !ok {
+			return nil, ""
+		}
+		label, rest := n.edges[i].label, prefix[depth:]
+		if !strings.HasPrefix(label, rest) && !strings.HasPrefix(rest, label) {
+			return nil, ""
+		}
+		n = n.edges[i].node
+		if len(rest) <= len(label) {
+			return n, prefix + label[len(rest):]
+		}
+		depth += len(label)
+	}
+	return n, prefix
+}
+
+func (n *trieNode[T]) walk(key string, fn func(string, T) bool) bool {
+	if n.has && !fn(key, n.value) {
+		return false
+	}
+	for _, e := range n.edges {
+		if !e.node.walk(key+e.label, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// find returns the index of the edge starting with b, or where it would
+// be inserted, and whether it exists.
+func (n *trieNode[T]) find(b byte) (int, bool) {
+	return slices.BinarySearchFunc(n.edges, b, func(e trieEdge[T], b byte) int { return int(e.label[0]) - int(b) })
+}
+
+// add inserts, at index i, edges leading to a new node for key: a single
+// edge in a radix trie, or one per byte otherwise. It returns the node.
+func (n *trieNode[T]) add(i int, key string, radix bool) *trieNode[T] {
+	label := key
+	if !radix {
+		label = key[:1]
+	}
+	child := &trieNode[T]{}
+	n.edges = slices.Insert(n.edges, i, trieEdge[T]{label, child})
+	if len(label) < len(key) {
+		return child.add(0, key[len(label):], radix)
+	}
+	return child
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b.
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
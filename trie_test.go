@@ -0,0 +1,113 @@
+package golib
+
+import "slices"
+import "testing"
+
+func collectPrefix[T any](t *Trie[T], prefix string) []string {
+	var keys []string
+	t.WalkPrefix(prefix, func(k string, _ T) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func TestTrie(t *testing.T) {
+	for _, tr := range []*Trie[int]{NewTrie[int](), NewRadixTrie[int]()} {
+		keys := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom", ""}
+		for i, k := range keys {
+			tr.Insert(k, i)
+		}
+		tr.Insert("ruber", 100)
+		if tr.Len() != len(keys) {
+			t.Fatalf("radix %v: Len() == %d, want %d", tr.radix, tr.Len(), len(keys))
+		}
+		for i, k := range keys {
+			want := i
+			if k == "ruber" {
+				want = 100
+			}
+			if v, ok := tr.Get(k); !ok || v != want {
+				t.Errorf("radix %v: Get(%q) == %d, %v, want %d", tr.radix, k, v, ok, want)
+			}
+		}
+		for _, k := range []string{"r", "roman", "rubiconx", "x"} {
+			if _, ok := tr.Get(k); ok {
+				t.Errorf("radix %v: Get(%q) found a value", tr.radix, k)
+			}
+		}
+
+		if got, want := collectPrefix(tr, "rub"), []string{"rubens", "ruber", "rubicon", "rubicundus"}; !slices.Equal(got, want) {
+			t.Errorf("radix %v: WalkPrefix(rub) == %q, want %q", tr.radix, got, want)
+		}
+		if got, want := collectPrefix(tr, "roma"), []string{"romane", "romanus"}; !slices.Equal(got, want) {
+			t.Errorf("radix %v: WalkPrefix(roma) == %q, want %q", tr.radix, got, want)
+		}
+		if got := collectPrefix(tr, ""); len(got) != len(keys) || !slices.IsSorted(got) {
+			t.Errorf("radix %v: WalkPrefix(\"\") == %q", tr.radix, got)
+		}
+		if !tr.HasPrefix("rubic") || tr.HasPrefix("rubix") || tr.HasPrefix("romulusx") {
+			t.Errorf("radix %v: HasPrefix wrong", tr.radix)
+		}
+
+		var first []string
+		tr.WalkPrefix("r", func(k string, _ int) bool {
+			first = append(first, k)
+			return len(first) < 2
+		})
+		if len(first) != 2 {
+			t.Errorf("radix %v: WalkPrefix did not stop: %q", tr.radix, first)
+		}
+
+		if tr.Delete("roman") || !tr.Delete("romane") || tr.Delete("romane") {
+			t.Errorf("radix %v: Delete reported wrongly", tr.radix)
+		}
+		for _, k := range []string{"rubicundus", "rubicon", "rom", ""} {
+			tr.Delete(k)
+		}
+		if got, want := collectPrefix(tr, ""), []string{"romanus", "romulus", "rubens", "ruber"}; !slices.Equal(got, want) || tr.Len() != 4 {
+			t.Errorf("radix %v: after Delete keys == %q, Len %d, want %q", tr.radix, got, tr.Len(), want)
+		}
+		if tr.HasPrefix("rubi") {
+			t.Errorf("radix %v: deleted prefix still present", tr.radix)
+		}
+	}
+}
+
+func TestTrieRadixCompresses(t *testing.T) {
+	tr := NewRadixTrie[bool]()
+	tr.Insert("test", true)
+	tr.Insert("team", true)
+	tr.Insert("toast", true)
+	// root -t-> {e -> {st, am}, oast}
+	if e := tr.root.edges; len(e) != 1 || e[0].label != "t" || len(e[0].node.edges) != 2 {
+		t.Fatalf("unexpected shape under root: %+v", e)
+	}
+	tr.Delete("toast")
+	// "t" and "e" merge once "toast" is gone.
+	if e := tr.root.edges; len(e) != 1 || e[0].label != "te" {
+		t.Errorf("edge after Delete == %q, want te", e[0].label)
+	}
+}
+
+func TestTrieLongestPrefix(t *testing.T) {
+	for _, tr := range []*Trie[string]{NewTrie[string](), NewRadixTrie[string]()} {
+		tr.Insert("/", "root")
+		tr.Insert("/api/", "api")
+		tr.Insert("/api/users/", "users")
+		cases := []struct{ path, key string }{
+			{"/api/users/42", "/api/users/"},
+			{"/api/user", "/api/"},
+			{"/static/app.js", "/"},
+			{"/", "/"},
+		}
+		for _, c := range cases {
+			if k, _, ok := tr.LongestPrefix(c.path); !ok || k != c.key {
+				t.Errorf("radix %v: LongestPrefix(%q) == %q, %v, want %q", tr.radix, c.path, k, ok, c.key)
+			}
+		}
+		if _, _, ok := tr.LongestPrefix("api"); ok {
+			t.Errorf("radix %v: LongestPrefix(api) matched", tr.radix)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package tui
+
+import "bufio"
+import "io"
+import "unicode/utf8"
+
+// A KeyCode identifies a key that does not type a character.
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota // a character, in Key.Rune
+	KeyCtrl                // a control key, with its letter in Key.Rune
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEsc
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyDelete
+	KeyUnknown // an escape sequence not recognised
+)
+
+// A Key is a key press.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// Is reports whether k is the character r.
+func (k Key) Is(r rune) bool { return k.Code == KeyRune && k.Rune == r }
+
+// IsCtrl reports whether k is Ctrl with the letter r, such as 'c'.
+func (k Key) IsCtrl(r rune) bool { return k.Code == KeyCtrl && k.Rune == r }
+
+// KeyReader reads key presses from a terminal in raw mode.
+type KeyReader struct {
+	r *bufio.Reader
+}
+
+// NewKeyReader returns a KeyReader reading from r.
+func NewKeyReader(r io.Reader) *KeyReader { return &KeyReader{bufio.NewReader(r)} }
+
+// csi maps the final byte of "ESC [ x" and "ESC O x" sequences to keys,
+// and tilde maps the number of "ESC [ n ~" sequences.
+var (
+	csi = map[byte]KeyCode{
+		'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft, 'H': KeyHome, 'F': KeyEnd,
+	}
+	tilde = map[int]KeyCode{
+		1: KeyHome, 3: KeyDelete, 4: KeyEnd, 5: KeyPageUp, 6: KeyPageDown, 7: KeyHome, 8: KeyEnd,
+	}
+)
+
+// ReadKey waits for and returns the next key press. An escape byte with
+// nothing after it in the same read is the Esc key, since a terminal
+// sends a whole escape sequence at once.
+func (k *KeyReader) ReadKey() (Key, error) {
+	b, err := k.r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	switch {
+	case b == '\r' || b == '\n':
+		return Key{Code: KeyEnter}, nil
+	case b == '\t':
+		return Key{Code: KeyTab}, nil
+	case b == 0x7f || b == 0x08:
+		return Key{Code: KeyBackspace}, nil
+	case b == 0x1b:
+		if k.r.Buffered() == 0 {
+			return Key{Code: KeyEsc}, nil
+		}
+		return k.escape()
+	case b < 0x20:
+		return Key{Code: KeyCtrl, Rune: rune('a' + b - 1)}, nil
+	case b < utf8.RuneSelf:
+		return Key{Code: KeyRune, Rune: rune(b)}, nil
+	}
+	k.r.UnreadByte()
+	r, _, err := k.r.ReadRune()
+	return Key{Code: KeyRune, Rune: r}, err
+}
+
+// escape reads the rest of an escape sequence.
+func (k *KeyReader) escape() (Key, error) {
+	b, err := k.r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if b != '[' && b != 'O' {
+		return Key{Code: KeyUnknown}, nil // Alt with a key, say
+	}
+	n := 0
+	for {
+		c, err := k.r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		switch {
+		case '0' <= c && c <= '9':
+			n = n*10 + int(c-'0')
+		case c == ';':
+			n = 0 // modifiers are not reported
+		case c == '~':
+			if code, ok := tilde[n]; ok {
+				return Key{Code: code}, nil
+			}
+			return Key{Code: KeyUnknown}, nil
+		default:
+			if code, ok := csi[c]; ok {
+				return Key{Code: code}, nil
+			}
+			return Key{Code: KeyUnknown}, nil
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package tui
+
+import "io"
+import "strings"
+import "testing"
+
+func TestReadKey(t *testing.T) {
+	in := "a\r\x1b[A\x1b[B\x1bOC\x1b[5~\x1b[1;5D\x03é\x7f\t\x1b[9~"
+	want := []Key{
+		{KeyRune, 'a'},
+		{Code: KeyEnter},
+		{Code: KeyUp},
+		{Code: KeyDown},
+		{Code: KeyRight},
+		{Code: KeyPageUp},
+		{Code: KeyLeft},
+		{KeyCtrl, 'c'},
+		{KeyRune, 'é'},
+		{Code: KeyBackspace},
+		{Code: KeyTab},
+		{Code: KeyUnknown},
+	}
+	k := NewKeyReader(strings.NewReader(in))
+	for i, w := range want {
+		got, err := k.ReadKey()
+		if err != nil || got != w {
+			t.Fatalf("key %d == %+v, %v, want %+v", i, got, err, w)
+		}
+	}
+	if _, err := k.ReadKey(); err != io.EOF {
+		t.Errorf("ReadKey at end: %v, want EOF", err)
+	}
+}
+
+func TestReadKeyEsc(t *testing.T) {
+	// An escape alone in its read is the Esc key.
+	k := NewKeyReader(io.MultiReader(strings.NewReader("\x1b"), strings.NewReader("q")))
+	if got, _ := k.ReadKey(); got.Code != KeyEsc {
+		t.Errorf("ReadKey == %+v, want Esc", got)
+	}
+	if got, _ := k.ReadKey(); !got.Is('q') {
+		t.Errorf("ReadKey == %+v, want q", got)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package tui
+
+type termios struct{}
+
+func getState(fd uintptr) (*termios, error) { return nil, ErrNotTerminal }
+
+func setState(fd uintptr, t *termios) error { return ErrNotTerminal }
+
+func raw(old *termios) *termios { return old }
+
+func getSize(fd uintptr) (int, int, error) { return 0, 0, ErrNotTerminal }
@@ -0,0 +1,46 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package tui
+
+import "syscall"
+import "unsafe"
+
+func getState(fd uintptr) (*syscall.Termios, error) {
+	var t syscall.Termios
+	if err := ioctl(fd, ioctlGetTermios, unsafe.Pointer(&t)); err != nil {
+		return nil, ErrNotTerminal
+	}
+	return &t, nil
+}
+
+func setState(fd uintptr, t *syscall.Termios) error {
+	return ioctl(fd, ioctlSetTermios, unsafe.Pointer(t))
+}
+
+// raw returns old with the settings of cfmakeraw(3).
+func raw(old *syscall.Termios) *syscall.Termios {
+	t := *old
+	t.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	t.Oflag &^= syscall.OPOST
+	t.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	t.Cflag &^= syscall.CSIZE | syscall.PARENB
+	t.Cflag |= syscall.CS8
+	t.Cc[syscall.VMIN] = 1
+	t.Cc[syscall.VTIME] = 0
+	return &t
+}
+
+func getSize(fd uintptr) (int, int, error) {
+	var ws struct{ Row, Col, X, Y uint16 }
+	if err := ioctl(fd, syscall.TIOCGWINSZ, unsafe.Pointer(&ws)); err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+func ioctl(fd, req uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
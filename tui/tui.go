@@ -0,0 +1,105 @@
+// Package tui drives full-screen terminal interfaces with nothing beyond
+// the standard library: it switches a terminal to raw mode, draws frames
+// on the alternate screen and reads keys, including arrows and other
+// escape sequences.
+//
+// Raw mode is supported on Linux and the BSDs, macOS included. Elsewhere
+// IsTerminal reports false, so that callers fall back to plain,
+// line-by-line prompts, as they should whenever input or output is not a
+// terminal.
+package tui
+
+import "bufio"
+import "errors"
+import "fmt"
+import "io"
+import "os"
+import "strings"
+
+import "github.com/lukehedger/golib"
+
+// ErrNotTerminal is returned when raw mode is asked of a file that is not
+// a terminal, or on a platform without support.
+var ErrNotTerminal = errors.New("tui: not a terminal")
+
+// IsTerminal reports whether f is a terminal that MakeRaw can use.
+func IsTerminal(f *os.File) bool {
+	_, err := getState(f.Fd())
+	return err == nil
+}
+
+// MakeRaw puts the terminal f in raw mode, in which keys are read as they
+// are pressed, unechoed and without signals for Ctrl-C, and output is
+// not translated, so lines must end "\r\n". It returns a function that
+// restores the previous mode.
+func MakeRaw(f *os.File) (restore func() error, err error) {
+	fd := f.Fd()
+	old, err := getState(fd)
+	if err != nil {
+		return nil, err
+	}
+	if err := setState(fd, raw(old)); err != nil {
+		return nil, err
+	}
+	return func() error { return setState(fd, old) }, nil
+}
+
+// Size returns the width and height of the terminal f in characters.
+func Size(f *os.File) (width, height int, err error) {
+	return getSize(f.Fd())
+}
+
+// Screen draws full frames on the alternate screen of a terminal, leaving
+// the user's scrollback untouched.
+type Screen struct {
+	w *bufio.Writer
+}
+
+// NewScreen returns a Screen writing to w.
+func NewScreen(w io.Writer) *Screen { return &Screen{bufio.NewWriter(w)} }
+
+// Enter switches to the alternate screen and hides the cursor.
+func (s *Screen) Enter() error {
+	s.w.WriteString("\x1b[?1049h\x1b[?25l")
+	return s.w.Flush()
+}
+
+// Exit shows the cursor and returns to the main screen.
+func (s *Screen) Exit() error {
+	s.w.WriteString("\x1b[?25h\x1b[?1049l")
+	return s.w.Flush()
+}
+
+// Draw clears the screen and writes lines from its top-left corner. Lines
+// should fit the terminal: cut them with Fit before styling them, as
+// escape sequences have no width.
+func (s *Screen) Draw(lines []string) error {
+	s.w.WriteString("\x1b[H\x1b[2J")
+	s.w.WriteString(strings.Join(lines, "\r\n"))
+	return s.w.Flush()
+}
+
+// Fit cuts s to width characters, marking the cut with an ellipsis. A
+// width of 0 or less leaves s whole.
+func Fit(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return golib.TruncateWidth(s, width, "…")
+}
+
+// Bar returns a progress bar width characters wide, filled in proportion
+// to done out of total, followed by the count.
+func Bar(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(width*done/total, width)
+	}
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("#", filled), strings.Repeat("-", width-filled), done, total)
+}
+
+// Reverse returns s in reverse video, to highlight a selection.
+func Reverse(s string) string { return "\x1b[7m" + s + "\x1b[0m" }
+
+// Dim returns s faint, for things that are not available.
+func Dim(s string) string { return "\x1b[2m" + s + "\x1b[0m" }
@@ -0,0 +1,38 @@
+package tui
+
+import "os"
+import "strings"
+import "testing"
+
+func TestIsTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if IsTerminal(r) {
+		t.Error("IsTerminal(pipe) == true")
+	}
+	if _, err := MakeRaw(r); err != ErrNotTerminal {
+		t.Errorf("MakeRaw(pipe) error == %v, want ErrNotTerminal", err)
+	}
+}
+
+func TestScreen(t *testing.T) {
+	var b strings.Builder
+	s := NewScreen(&b)
+	s.Draw([]string{"one", Fit("two three", 5)})
+	if want := "\x1b[H\x1b[2Jone\r\ntwo …"; b.String() != want {
+		t.Errorf("Draw wrote %q, want %q", b.String(), want)
+	}
+}
+
+func TestBar(t *testing.T) {
+	if got := Bar(1, 4, 8); got != "[##------] 1/4" {
+		t.Errorf("Bar(1, 4, 8) == %q", got)
+	}
+	if got := Bar(0, 0, 4); got != "[----] 0/0" {
+		t.Errorf("Bar(0, 0, 4) == %q", got)
+	}
+}
@@ -0,0 +1,208 @@
+// Package ulid generates and parses ULIDs: 128-bit identifiers made of a
+// 48-bit millisecond timestamp and 80 random bits, written as 26
+// characters of Crockford's base32. Both the bytes and the text sort in
+// the order the ULIDs were made, which keeps database indexes compact
+// where random UUIDs scatter them.
+package ulid
+
+import "crypto/rand"
+import "database/sql/driver"
+import "errors"
+import "fmt"
+import "io"
+import "sync"
+import "time"
+
+// A ULID is a universally unique lexicographically sortable identifier.
+type ULID [16]byte
+
+// ErrInvalid is returned by Parse for a string that is not a ULID.
+var ErrInvalid = errors.New("ulid: invalid ULID")
+
+// ErrTimeOverflow is returned by Generator.New for a time before the
+// Unix epoch or after MaxTime, which a ULID cannot hold.
+var ErrTimeOverflow = errors.New("ulid: time out of range")
+
+// alphabet is Crockford's base32, which leaves out I, L, O and U.
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// MaxTime is the latest time a ULID can hold.
+var MaxTime = time.UnixMilli(1<<48 - 1)
+
+// A Generator makes ULIDs that increase strictly, even when several are
+// made in one millisecond: the random part of each after the first is
+// one more than the last. The zero value uses crypto/rand. It is safe for
+// concurrent use.
+type Generator struct {
+	// Entropy supplies the random bits. Nil means crypto/rand.
+	Entropy io.Reader
+
+	mu   sync.Mutex
+	last ULID
+}
+
+// New returns a ULID for time t. If t is not after the time of the last
+// ULID made, as when several are made in one millisecond or the clock
+// goes back, the result is the last ULID plus one, so that order is kept.
+// It returns ErrTimeOverflow if t is out of range, or if the last ULID is
+// the largest there is, and an error if Entropy fails.
+func (g *Generator) New(t time.Time) (ULID, error) {
+	if ms := t.UnixMilli(); ms < 0 || ms > MaxTime.UnixMilli() {
+		return ULID{}, fmt.Errorf("%w: %v", ErrTimeOverflow, t)
+	}
+	var u ULID
+	putTime(&u, t)
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if u.Time().After(g.last.Time()) {
+		r := g.Entropy
+		if r == nil {
+			r = rand.Reader
+		}
+		if _, err := io.ReadFull(r, u[6:]); err != nil {
+			return ULID{}, fmt.Errorf("ulid: %w", err)
+		}
+	} else {
+		u = g.last
+		i := 15
+		for ; i >= 0; i-- { // carries into the timestamp on overflow
+			u[i]++
+			if u[i] != 0 {
+				break
+			}
+		}
+		if i < 0 {
+			return ULID{}, fmt.Errorf("%w: no ULID after %s", ErrTimeOverflow, g.last)
+		}
+	}
+	g.last = u
+	return u, nil
+}
+
+var defaultGenerator Generator
+
+// Make returns a new ULID for the current time from a process-wide
+// Generator.
+func Make() ULID {
+	u, err := defaultGenerator.New(time.Now())
+	if err != nil {
+		panic(err) // crypto/rand does not fail, and now is in range
+	}
+	return u
+}
+
+func putTime(u *ULID, t time.Time) {
+	ms := uint64(t.UnixMilli())
+	for i := range 6 {
+		u[i] = byte(ms >> (40 - 8*i))
+	}
+}
+
+// Time returns the time held in u, to the millisecond.
+func (u ULID) Time() time.Time {
+	var ms int64
+	for i := range 6 {
+		ms = ms<<8 | int64(u[i])
+	}
+	return time.UnixMilli(ms)
+}
+
+// String returns u as 26 characters of Crockford's base32.
+func (u ULID) String() string {
+	// The 128 bits are read as 130, with two leading zeros, in groups
+	// of five.
+	var b [26]byte
+	for i := range b {
+		b[i] = alphabet[bits5(u, i*5-2)]
+	}
+	return string(b[:])
+}
+
+// bits5 returns the five bits of u starting at bit off, counting from the
+// most significant, with bits before the start reading as zero.
+func bits5(u ULID, off int) byte {
+	var v byte
+	for k := range 5 {
+		bit := off + k
+		v <<= 1
+		if bit >= 0 && u[bit/8]&(0x80>>(bit%8)) != 0 {
+			v |= 1
+		}
+	}
+	return v
+}
+
+// decoding maps characters to their values, or 0xff if invalid. It
+// accepts lower case, and I and L for 1 and O for 0, as Crockford's
+// base32 does.
+var decoding = func() [256]byte {
+	var d [256]byte
+	for i := range d {
+		d[i] = 0xff
+	}
+	for i := range len(alphabet) {
+		d[alphabet[i]] = byte(i)
+		d[alphabet[i]|0x20] = byte(i) // lower case; digits are unchanged
+	}
+	for _, c := range "IiLl" {
+		d[c] = 1
+	}
+	d['O'], d['o'] = 0, 0
+	return d
+}()
+
+// Parse parses a ULID from its 26-character form.
+func Parse(s string) (ULID, error) {
+	var u ULID
+	if len(s) != 26 {
+		return u, fmt.Errorf("%w: %q has %d characters, want 26", ErrInvalid, s, len(s))
+	}
+	for i := range 26 {
+		v := decoding[s[i]]
+		if v == 0xff {
+			return ULID{}, fmt.Errorf("%w: %q has %q", ErrInvalid, s, s[i])
+		}
+		if i == 0 && v > 7 {
+			return ULID{}, fmt.Errorf("%w: %q overflows 128 bits", ErrInvalid, s)
+		}
+		for k := range 5 {
+			bit := i*5 - 2 + k
+			if bit >= 0 && v&(0x10>>k) != 0 {
+				u[bit/8] |= 0x80 >> (bit % 8)
+			}
+		}
+	}
+	return u, nil
+}
+
+// MarshalText implements encoding.TextMarshaler, and so JSON.
+func (u ULID) MarshalText() ([]byte, error) { return []byte(u.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler, and so JSON.
+func (u *ULID) UnmarshalText(b []byte) error {
+	v, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// Value implements driver.Valuer, storing u as its string.
+func (u ULID) Value() (driver.Value, error) { return u.String(), nil }
+
+// Scan implements sql.Scanner. It accepts a string, or 16 raw bytes or
+// text as []byte.
+func (u *ULID) Scan(src any) error {
+	switch src := src.(type) {
+	case string:
+		return u.UnmarshalText([]byte(src))
+	case []byte:
+		if len(src) == 16 {
+			copy(u[:], src)
+			return nil
+		}
+		return u.UnmarshalText(src)
+	}
+	return fmt.Errorf("ulid: cannot scan %T", src)
+}
@@ -0,0 +1,119 @@
+package ulid
+
+import "bytes"
+import "errors"
+import "strings"
+import "testing"
+import "time"
+
+func TestString(t *testing.T) {
+	var ones ULID
+	for i := range ones {
+		ones[i] = 0xff
+	}
+	cases := []struct {
+		u    ULID
+		want string
+	}{
+		{ULID{}, "00000000000000000000000000"},
+		{ones, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{ULID{15: 1}, "00000000000000000000000001"},
+		{ULID{15: 32}, "00000000000000000000000010"},
+	}
+	for _, c := range cases {
+		if got := c.u.String(); got != c.want {
+			t.Errorf("%x.String() = %s, want %s", c.u[:], got, c.want)
+		}
+		if back, err := Parse(c.want); back != c.u || err != nil {
+			t.Errorf("Parse(%s) = %x, %v", c.want, back[:], err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	u := Make()
+	s := u.String()
+	if v, err := Parse(strings.ToLower(s)); v != u || err != nil {
+		t.Errorf("lower case: %s, %v", v, err)
+	}
+	if v, err := Parse("0000000000000000000000000I"); v != (ULID{15: 1}) || err != nil {
+		t.Errorf("I for 1: %s, %v", v, err)
+	}
+	for _, bad := range []string{"", s[1:], "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "0000000000000000000000000U"} {
+		if _, err := Parse(bad); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error %v", bad, err)
+		}
+	}
+}
+
+func TestMonotonic(t *testing.T) {
+	var g Generator
+	now := time.UnixMilli(1700000000000)
+	prev, _ := g.New(now)
+	if !prev.Time().Equal(now) {
+		t.Errorf("Time = %v, want %v", prev.Time(), now)
+	}
+	for i := range 1000 {
+		ts := now
+		if i == 500 {
+			ts = now.Add(-time.Hour) // the clock goes back
+		}
+		u, err := g.New(ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Compare(prev[:], u[:]) >= 0 || prev.String() >= u.String() {
+			t.Fatalf("%s not after %s", u, prev)
+		}
+		prev = u
+	}
+
+	// An exhausted random part carries into the timestamp.
+	g = Generator{Entropy: bytes.NewReader(bytes.Repeat([]byte{0xff}, 10))}
+	a, _ := g.New(now)
+	b, _ := g.New(now)
+	if !b.Time().Equal(now.Add(time.Millisecond)) {
+		t.Errorf("after overflow Time = %v", b.Time())
+	}
+	if a.String() >= b.String() {
+		t.Errorf("%s not after %s", b, a)
+	}
+}
+
+func TestTimeOverflow(t *testing.T) {
+	var g Generator
+	for _, ts := range []time.Time{time.UnixMilli(-1), MaxTime.Add(time.Millisecond)} {
+		if _, err := g.New(ts); !errors.Is(err, ErrTimeOverflow) {
+			t.Errorf("New(%v) error %v", ts, err)
+		}
+	}
+	if u, err := g.New(MaxTime); err != nil || !u.Time().Equal(MaxTime) {
+		t.Errorf("New(MaxTime) = %s, %v", u, err)
+	}
+
+	g = Generator{Entropy: bytes.NewReader(bytes.Repeat([]byte{0xff}, 10))}
+	if _, err := g.New(MaxTime); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.New(MaxTime); !errors.Is(err, ErrTimeOverflow) {
+		t.Errorf("after the largest ULID: %v", err)
+	}
+}
+
+func TestEntropyError(t *testing.T) {
+	g := Generator{Entropy: bytes.NewReader(nil)}
+	if _, err := g.New(time.Now()); err == nil {
+		t.Error("no error from empty entropy")
+	}
+}
+
+func TestScan(t *testing.T) {
+	u := Make()
+	v, _ := u.Value()
+	for _, src := range []any{v, []byte(u.String()), u[:]} {
+		var got ULID
+		if err := got.Scan(src); err != nil || got != u {
+			t.Errorf("Scan(%T) = %s, %v", src, got, err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+// Package uuid generates and parses UUIDs as defined by RFC 9562: random
+// version 4 UUIDs, and version 7 UUIDs, which begin with a millisecond
+// timestamp and so sort in the order they were made, suiting database
+// keys.
+package uuid
+
+import "crypto/rand"
+import "database/sql/driver"
+import "encoding/hex"
+import "errors"
+import "fmt"
+import "sync"
+import "time"
+
+// A UUID is a 128-bit universally unique identifier.
+type UUID [16]byte
+
+// Nil is the UUID with every bit zero.
+var Nil UUID
+
+// ErrInvalid is returned by Parse for a string that is not a UUID.
+var ErrInvalid = errors.New("uuid: invalid UUID")
+
+// NewV4 returns a random UUID.
+func NewV4() UUID {
+	var u UUID
+	rand.Read(u[:])
+	u.setVersion(4)
+	return u
+}
+
+var v7 struct {
+	sync.Mutex
+	ms  int64
+	seq uint16 // 12 bits
+}
+
+// NewV7 returns a UUID that starts with the current Unix time in
+// milliseconds followed by random bits. UUIDs made by one process are
+// strictly increasing even within a millisecond: the 12 bits after the
+// timestamp count up from a random start, borrowing the next millisecond
+// if they run out or if the clock goes back.
+func NewV7() UUID {
+	var u UUID
+	rand.Read(u[6:])
+
+	v7.Lock()
+	ms := time.Now().UnixMilli()
+	if ms > v7.ms {
+		v7.ms = ms
+		v7.seq = (uint16(u[6])<<8 | uint16(u[7])) & 0x7ff // leave room to count
+	} else {
+		v7.seq++
+		if v7.seq > 0xfff {
+			v7.ms++
+			v7.seq = 0
+		}
+	}
+	ms, seq := v7.ms, v7.seq
+	v7.Unlock()
+
+	for i := range 6 {
+		u[i] = byte(ms >> (40 - 8*i))
+	}
+	u[6], u[7] = byte(seq>>8), byte(seq)
+	u.setVersion(7)
+	return u
+}
+
+// setVersion sets the version and the RFC 9562 variant.
+func (u *UUID) setVersion(v byte) {
+	u[6] = u[6]&0x0f | v<<4
+	u[8] = u[8]&0x3f | 0x80
+}
+
+// Version returns the version number in u.
+func (u UUID) Version() int { return int(u[6] >> 4) }
+
+// Time returns the time at which a version 7 UUID was made, to the
+// millisecond, and whether u is a version 7 UUID.
+func (u UUID) Time() (time.Time, bool) {
+	if u.Version() != 7 {
+		return time.Time{}, false
+	}
+	var ms int64
+	for i := range 6 {
+		ms = ms<<8 | int64(u[i])
+	}
+	return time.UnixMilli(ms), true
+}
+
+// IsNil reports whether u is Nil.
+func (u UUID) IsNil() bool { return u == Nil }
+
+// String returns u in the standard form, such as
+// "0190b1f4-8f3c-7a2e-9d4b-5c6e7f801234".
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// Parse parses a UUID in the standard form, in either case, optionally
+// enclosed in braces or prefixed with "urn:uuid:".
+func Parse(s string) (UUID, error) {
+	var u UUID
+	switch {
+	case len(s) == 38 && s[0] == '{' && s[37] == '}':
+		s = s[1:37]
+	case len(s) == 45 && s[:9] == "urn:uuid:":
+		s = s[9:]
+	}
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return Nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	j := 0
+	for _, part := range [5][2]int{{0, 8}, {9, 13}, {14, 18}, {19, 23}, {24, 36}} {
+		n, err := hex.Decode(u[j:], []byte(s[part[0]:part[1]]))
+		if err != nil {
+			return Nil, fmt.Errorf("%w: %q", ErrInvalid, s)
+		}
+		j += n
+	}
+	return u, nil
+}
+
+// MustParse is like Parse but panics on error, for constants.
+func MustParse(s string) UUID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// MarshalText implements encoding.TextMarshaler, and so JSON.
+func (u UUID) MarshalText() ([]byte, error) { return []byte(u.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler, and so JSON.
+func (u *UUID) UnmarshalText(b []byte) error {
+	v, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// Value implements driver.Valuer, storing u as a string.
+func (u UUID) Value() (driver.Value, error) { return u.String(), nil }
+
+// Scan implements sql.Scanner. It accepts a string, or 16 raw bytes or
+// text as []byte. NULL scans as Nil.
+func (u *UUID) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*u = Nil
+		return nil
+	case string:
+		return u.UnmarshalText([]byte(src))
+	case []byte:
+		if len(src) == 16 {
+			copy(u[:], src)
+			return nil
+		}
+		return u.UnmarshalText(src)
+	}
+	return fmt.Errorf("uuid: cannot scan %T", src)
+}
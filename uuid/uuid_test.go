@@ -0,0 +1,84 @@
+package uuid
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "testing"
+import "time"
+
+func TestNewV4(t *testing.T) {
+	a, b := NewV4(), NewV4()
+	if a == b {
+		t.Fatal("two random UUIDs are equal")
+	}
+	if a.Version() != 4 || a[8]&0xc0 != 0x80 {
+		t.Errorf("%s has version %d, variant bits %02x", a, a.Version(), a[8]>>6)
+	}
+	if _, ok := a.Time(); ok {
+		t.Error("v4 has a time")
+	}
+}
+
+func TestNewV7(t *testing.T) {
+	start := time.Now().Truncate(time.Millisecond)
+	prev := NewV7()
+	for range 10000 {
+		u := NewV7()
+		if bytes.Compare(prev[:], u[:]) >= 0 || u.String() <= prev.String() {
+			t.Fatalf("%s not after %s", u, prev)
+		}
+		prev = u
+	}
+	if prev.Version() != 7 || prev[8]&0xc0 != 0x80 {
+		t.Errorf("%s has version %d", prev, prev.Version())
+	}
+	ts, ok := prev.Time()
+	if !ok || ts.Before(start) || ts.After(time.Now().Add(time.Second)) {
+		t.Errorf("Time = %v, %v; started at %v", ts, ok, start)
+	}
+}
+
+func TestParse(t *testing.T) {
+	const s = "0190b1f4-8f3c-7a2e-9d4b-5c6e7f801234"
+	u := MustParse(s)
+	if u.String() != s || u.Version() != 7 {
+		t.Errorf("round trip gives %s, version %d", u, u.Version())
+	}
+	for _, in := range []string{"0190B1F4-8F3C-7A2E-9D4B-5C6E7F801234", "{" + s + "}", "urn:uuid:" + s} {
+		if v, err := Parse(in); v != u || err != nil {
+			t.Errorf("Parse(%q) = %s, %v", in, v, err)
+		}
+	}
+	for _, in := range []string{"", "0190b1f48f3c7a2e9d4b5c6e7f801234", "0190b1f4-8f3c-7a2e-9d4b-5c6e7f80123g", "0190b1f4+8f3c-7a2e-9d4b-5c6e7f801234"} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error %v", in, err)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	u := NewV4()
+	b, err := json.Marshal(map[string]UUID{"id": u})
+	if err != nil || string(b) != `{"id":"`+u.String()+`"}` {
+		t.Fatalf("json = %s, %v", b, err)
+	}
+	var m map[string]UUID
+	if err := json.Unmarshal(b, &m); err != nil || m["id"] != u {
+		t.Errorf("unmarshal = %v, %v", m, err)
+	}
+
+	v, _ := u.Value()
+	var got UUID
+	for _, src := range []any{v, []byte(u.String()), u[:]} {
+		got = Nil
+		if err := got.Scan(src); err != nil || got != u {
+			t.Errorf("Scan(%T) = %s, %v", src, got, err)
+		}
+	}
+	if err := got.Scan(nil); err != nil || !got.IsNil() {
+		t.Errorf("Scan(nil) = %s, %v", got, err)
+	}
+	if err := got.Scan(42); err == nil {
+		t.Error("Scan(42) succeeded")
+	}
+}
@@ -0,0 +1,255 @@
+// Package validate checks strings against common formats, and structs
+// against rules in their field tags.
+//
+// Each format check returns nil for a valid value and otherwise an
+// *Error saying what is wrong, for messages shown to whoever supplied the
+// value. All such errors match ErrInvalid with errors.Is.
+package validate
+
+import "errors"
+import "fmt"
+import "net"
+import "net/mail"
+import "net/netip"
+import "net/url"
+import "strconv"
+import "strings"
+import "unicode"
+
+// ErrInvalid matches every error returned for a value that fails a check.
+var ErrInvalid = errors.New("validate: invalid value")
+
+// An Error describes a value that fails a check.
+type Error struct {
+	Value  string
+	Format string // what the value should be, such as "email address"
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%q is not a valid %s: %s", e.Value, e.Format, e.Reason)
+}
+
+// Is reports whether target is ErrInvalid.
+func (e *Error) Is(target error) bool { return target == ErrInvalid }
+
+func invalid(value, format, reason string, args ...any) error {
+	return &Error{value, format, fmt.Sprintf(reason, args...)}
+}
+
+// IsEmail checks that s is a bare email address, such as
+// "ann@example.com", with a domain that has a dot in it. Display names,
+// as in "Ann <ann@example.com>", are not accepted.
+func IsEmail(s string) error {
+	const format = "email address"
+	at := strings.LastIndexByte(s, '@')
+	switch {
+	case at < 0:
+		return invalid(s, format, "missing @")
+	case at == 0:
+		return invalid(s, format, "nothing before @")
+	}
+	a, err := mail.ParseAddress(s)
+	if err != nil || a.Name != "" || a.Address != s {
+		return invalid(s, format, "malformed")
+	}
+	if err := IsHostname(s[at+1:]); err != nil || !strings.Contains(s[at+1:], ".") {
+		return invalid(s, format, "domain %q is not a valid host name", s[at+1:])
+	}
+	return nil
+}
+
+// IsURL checks that s is an absolute URL with a scheme and a host, such
+// as "https://example.com/path".
+func IsURL(s string) error {
+	const format = "URL"
+	u, err := url.Parse(s)
+	switch {
+	case err != nil:
+		return invalid(s, format, "%v", errors.Unwrap(err))
+	case u.Scheme == "":
+		return invalid(s, format, "missing scheme, such as https://")
+	case u.Host == "":
+		return invalid(s, format, "missing host")
+	}
+	return nil
+}
+
+// IsUUID checks that s is a UUID in its usual form of 36 characters, such
+// as "123e4567-e89b-12d3-a456-426614174000", in either case.
+func IsUUID(s string) error {
+	const format = "UUID"
+	if len(s) != 36 {
+		return invalid(s, format, "%d characters, want 36", len(s))
+	}
+	for i, c := range []byte(s) {
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return invalid(s, format, "want - at position %d", i+1)
+			}
+		default:
+			if !isHex(c) {
+				return invalid(s, format, "%q is not a hexadecimal digit", c)
+			}
+		}
+	}
+	return nil
+}
+
+// IsIP checks that s is an IPv4 or IPv6 address.
+func IsIP(s string) error {
+	if _, err := netip.ParseAddr(s); err != nil {
+		return invalid(s, "IP address", "%s", ipReason(err))
+	}
+	return nil
+}
+
+// IsIPv4 checks that s is an IPv4 address in dotted decimal, such as
+// "192.0.2.1".
+func IsIPv4(s string) error {
+	a, err := netip.ParseAddr(s)
+	switch {
+	case err != nil:
+		return invalid(s, "IPv4 address", "%s", ipReason(err))
+	case !a.Is4():
+		return invalid(s, "IPv4 address", "it is an IPv6 address")
+	}
+	return nil
+}
+
+// IsIPv6 checks that s is an IPv6 address, such as "2001:db8::1".
+func IsIPv6(s string) error {
+	a, err := netip.ParseAddr(s)
+	switch {
+	case err != nil:
+		return invalid(s, "IPv6 address", "%s", ipReason(err))
+	case !a.Is6():
+		return invalid(s, "IPv6 address", "it is an IPv4 address")
+	}
+	return nil
+}
+
+// ipReason returns the part of a netip parse error that says what is
+// wrong, without the repeated input.
+func ipReason(err error) string {
+	msg := err.Error()
+	if i := strings.LastIndex(msg, ": "); i >= 0 {
+		return msg[i+2:]
+	}
+	return msg
+}
+
+// IsCIDR checks that s is an IP network in CIDR notation, such as
+// "10.0.0.0/8".
+func IsCIDR(s string) error {
+	if _, err := netip.ParsePrefix(s); err != nil {
+		return invalid(s, "CIDR network", "%s", ipReason(err))
+	}
+	return nil
+}
+
+// IsMAC checks that s is a hardware address, such as "00:00:5e:00:53:01".
+func IsMAC(s string) error {
+	if _, err := net.ParseMAC(s); err != nil {
+		return invalid(s, "MAC address", "malformed")
+	}
+	return nil
+}
+
+// IsHostname checks that s is a host name under RFC 1123: dot-separated
+// labels of letters, digits and hyphens, each at most 63 characters and
+// not starting or ending with a hyphen, 253 characters in all at most.
+func IsHostname(s string) error {
+	const format = "host name"
+	if s == "" {
+		return invalid(s, format, "empty")
+	}
+	if len(s) > 253 {
+		return invalid(s, format, "longer than 253 characters")
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(s, "."), ".") {
+		switch {
+		case label == "":
+			return invalid(s, format, "empty label")
+		case len(label) > 63:
+			return invalid(s, format, "label %q longer than 63 characters", label)
+		case label[0] == '-' || label[len(label)-1] == '-':
+			return invalid(s, format, "label %q starts or ends with -", label)
+		}
+		for _, c := range []byte(label) {
+			if !isAlnum(c) && c != '-' {
+				return invalid(s, format, "%q not allowed", c)
+			}
+		}
+	}
+	return nil
+}
+
+// IsPort checks that s is a TCP or UDP port number, from 1 to 65535.
+func IsPort(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 65535 {
+		return invalid(s, "port", "want a number from 1 to 65535")
+	}
+	return nil
+}
+
+// IsHexColor checks that s is a CSS hex color: # and 3, 4, 6 or 8
+// hexadecimal digits, as in "#fff" or "#1e90ffcc".
+func IsHexColor(s string) error {
+	const format = "hex color"
+	if !strings.HasPrefix(s, "#") {
+		return invalid(s, format, "missing #")
+	}
+	switch len(s) - 1 {
+	case 3, 4, 6, 8:
+	default:
+		return invalid(s, format, "%d digits, want 3, 4, 6 or 8", len(s)-1)
+	}
+	for _, c := range []byte(s[1:]) {
+		if !isHex(c) {
+			return invalid(s, format, "%q is not a hexadecimal digit", c)
+		}
+	}
+	return nil
+}
+
+// IsAlpha checks that s is not empty and has only letters, in any
+// script.
+func IsAlpha(s string) error {
+	return only(s, "alphabetic string", "letters", unicode.IsLetter)
+}
+
+// IsAlphanumeric checks that s is not empty and has only ASCII letters
+// and digits.
+func IsAlphanumeric(s string) error {
+	return only(s, "alphanumeric string", "ASCII letters and digits", func(r rune) bool {
+		return r < 0x80 && isAlnum(byte(r))
+	})
+}
+
+// IsNumeric checks that s is not empty and has only the digits 0 to 9.
+func IsNumeric(s string) error {
+	return only(s, "numeric string", "digits", func(r rune) bool { return '0' <= r && r <= '9' })
+}
+
+func only(s, format, allowed string, ok func(rune) bool) error {
+	if s == "" {
+		return invalid(s, format, "empty")
+	}
+	for _, r := range s {
+		if !ok(r) {
+			return invalid(s, format, "%q is not allowed; want only %s", r, allowed)
+		}
+	}
+	return nil
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func isAlnum(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
@@ -0,0 +1,61 @@
+package validate
+
+import "errors"
+import "testing"
+
+func TestFormats(t *testing.T) {
+	cases := []struct {
+		name  string
+		check func(string) error
+		valid []string
+		bad   []string
+	}{
+		{"IsEmail", IsEmail,
+			[]string{"ann@example.com", "first.last+tag@mail.example.co.uk"},
+			[]string{"", "ann", "@example.com", "ann@localhost", "Ann <ann@example.com>", "ann@exa_mple.com", "a b@example.com"}},
+		{"IsURL", IsURL,
+			[]string{"https://example.com", "http://localhost:8080/a?b=c"},
+			[]string{"", "example.com", "/path", "https://", "http://[::1"}},
+		{"IsUUID", IsUUID,
+			[]string{"123e4567-e89b-12d3-a456-426614174000", "123E4567-E89B-12D3-A456-426614174000"},
+			[]string{"", "123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g", "123e4567-e89b-12d3-a456_426614174000"}},
+		{"IsIP", IsIP, []string{"192.0.2.1", "::1"}, []string{"", "256.0.0.1", "1.2.3"}},
+		{"IsIPv4", IsIPv4, []string{"192.0.2.1"}, []string{"2001:db8::1", "1.2.3.4.5"}},
+		{"IsIPv6", IsIPv6, []string{"2001:db8::1", "::ffff:192.0.2.1"}, []string{"192.0.2.1", "2001:db8:::1"}},
+		{"IsCIDR", IsCIDR, []string{"10.0.0.0/8", "2001:db8::/32"}, []string{"10.0.0.0", "10.0.0.0/33"}},
+		{"IsMAC", IsMAC, []string{"00:00:5e:00:53:01"}, []string{"00:00:5e:00:53", "zz:00:5e:00:53:01"}},
+		{"IsHostname", IsHostname,
+			[]string{"example.com", "a-b.example.com.", "localhost"},
+			[]string{"", "-a.com", "a..com", "a_b.com"}},
+		{"IsPort", IsPort, []string{"1", "8080", "65535"}, []string{"0", "65536", "http", ""}},
+		{"IsHexColor", IsHexColor, []string{"#fff", "#FFFF", "#1e90ff", "#1e90ffcc"}, []string{"fff", "#ff", "#12345", "#ggg"}},
+		{"IsAlpha", IsAlpha, []string{"abc", "Ünïcödé"}, []string{"", "abc1", "a b"}},
+		{"IsAlphanumeric", IsAlphanumeric, []string{"abc123"}, []string{"", "abc-123", "é1"}},
+		{"IsNumeric", IsNumeric, []string{"0123"}, []string{"", "-1", "1.5", "١٢"}},
+	}
+	for _, c := range cases {
+		for _, s := range c.valid {
+			if err := c.check(s); err != nil {
+				t.Errorf("%s(%q) == %v", c.name, s, err)
+			}
+		}
+		for _, s := range c.bad {
+			err := c.check(s)
+			var e *Error
+			if !errors.Is(err, ErrInvalid) || !errors.As(err, &e) || e.Value != s {
+				t.Errorf("%s(%q) == %v, want an *Error", c.name, s, err)
+			}
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	want := `"#12345" is not a valid hex color: 5 digits, want 3, 4, 6 or 8`
+	if err := IsHexColor("#12345"); err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+	want = `"ann" is not a valid email address: missing @`
+	if err := IsEmail("ann"); err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+}
@@ -0,0 +1,254 @@
+package validate
+
+import "errors"
+import "fmt"
+import "reflect"
+import "slices"
+import "strconv"
+import "strings"
+import "sync"
+import "unicode/utf8"
+
+// A Rule checks a field's value against the rule's parameter, the text
+// after = in the tag, which is empty for rules without one.
+type Rule func(field reflect.Value, param string) error
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]Rule{}
+)
+
+// Register adds a rule that tags can name, replacing any rule of that
+// name, including the built-in ones. It panics if name is not a plain
+// word.
+func Register(name string, rule Rule) {
+	if name == "" || strings.ContainsAny(name, ",= ") {
+		panic(fmt.Sprintf("validate: invalid rule name %q", name))
+	}
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[name] = rule
+}
+
+func init() {
+	for name, fn := range map[string]func(string) error{
+		"email": IsEmail, "url": IsURL, "uuid": IsUUID, "ip": IsIP,
+		"ipv4": IsIPv4, "ipv6": IsIPv6, "cidr": IsCIDR, "mac": IsMAC,
+		"hostname": IsHostname, "port": IsPort, "hexcolor": IsHexColor,
+		"alpha": IsAlpha, "alphanumeric": IsAlphanumeric, "numeric": IsNumeric,
+	} {
+		Register(name, stringRule(fn))
+	}
+	Register("min", sizeRule("at least", func(n, p float64) bool { return n >= p }))
+	Register("max", sizeRule("at most", func(n, p float64) bool { return n <= p }))
+	Register("len", sizeRule("exactly", func(n, p float64) bool { return n == p }))
+	Register("oneof", oneOf)
+}
+
+// A FieldError is a field that breaks a rule.
+type FieldError struct {
+	// Field is the path to the field from the validated struct, such as
+	// "Address.City" or "Items[2].Name".
+	Field string
+	Rule  string
+	Param string
+	Err   error
+}
+
+func (e *FieldError) Error() string { return e.Field + ": " + e.Err.Error() }
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// Errors lists every field that broke a rule, in field order.
+type Errors []*FieldError
+
+func (es Errors) Error() string {
+	s := make([]string, len(es))
+	for i, e := range es {
+		s[i] = e.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// Validate checks the struct v, or the struct v points to, against the
+// rules in its fields' validate tags, and returns Errors if any field
+// breaks one. Rules are separated by commas, with any parameter after =:
+//
+//	type Signup struct {
+//		Name  string   `validate:"required,max=50"`
+//		Email string   `validate:"required,email"`
+//		Plan  string   `validate:"oneof=free pro"`
+//		Tags  []string `validate:"max=5"`
+//		Home  Address  // checked too, as are structs in slices
+//	}
+//
+// The rules are required, min, max, len and oneof; the formats email,
+// url, uuid, ip, ipv4, ipv6, cidr, mac, hostname, port, hexcolor, alpha,
+// alphanumeric and numeric, each checked by the function of that name;
+// and any added with Register. For strings min, max and len count
+// characters, for slices, arrays and maps elements, and for numbers they
+// bound the value.
+//
+// Rules apply to zero values too, so that min=18 rejects an age of 0. Mark
+// optional fields omitempty to skip their rules when they are zero:
+//
+//	Phone string `validate:"omitempty,numeric"`
+//
+// Rules look through pointers, with a nil pointer checked as the zero
+// value of the type it points to, except that required rejects only a nil
+// pointer and omitempty skips only a nil pointer: a pointer to "" is a
+// value that was given.
+//
+// Fields of struct type, pointers to structs, and slices and arrays of
+// them are checked in turn. A tag of "-" skips a field. An unknown rule
+// is a programming error, and panics.
+func Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: %T is not a struct", v)
+	}
+	var errs Errors
+	validateStruct(rv, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(rv reflect.Value, path string, errs *Errors) {
+	t := rv.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("validate")
+		if !f.IsExported() || tag == "-" {
+			continue
+		}
+		name := f.Name
+		if path != "" {
+			name = path + "." + name
+		}
+		fv := rv.Field(i)
+		if tag != "" {
+			validateField(fv, name, tag, errs)
+		}
+		nested(fv, name, errs)
+	}
+}
+
+// nested checks the structs in v: v itself, what it points to, or its
+// elements.
+func nested(v reflect.Value, path string, errs *Errors) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if !v.IsNil() {
+			nested(v.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		validateStruct(v, path, errs)
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			nested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+func validateField(v reflect.Value, path, tag string, errs *Errors) {
+	rs := strings.Split(tag, ",")
+	for i := range rs {
+		rs[i] = strings.TrimSpace(rs[i])
+	}
+	if slices.Contains(rs, "omitempty") && v.IsZero() {
+		return
+	}
+	ev := v
+	for ev.Kind() == reflect.Pointer {
+		if ev.IsNil() {
+			ev = reflect.Zero(ev.Type().Elem())
+		} else {
+			ev = ev.Elem()
+		}
+	}
+	for _, r := range rs {
+		name, param, _ := strings.Cut(r, "=")
+		if name == "omitempty" {
+			continue
+		}
+		if name == "required" {
+			if v.IsZero() {
+				*errs = append(*errs, &FieldError{path, name, param, errRequired})
+				return
+			}
+			continue
+		}
+		rulesMu.RLock()
+		rule, ok := rules[name]
+		rulesMu.RUnlock()
+		if !ok {
+			panic(fmt.Sprintf("validate: unknown rule %q on %s", name, path))
+		}
+		if err := rule(ev, param); err != nil {
+			*errs = append(*errs, &FieldError{path, name, param, err})
+		}
+	}
+}
+
+var errRequired = errors.New("is required")
+
+// stringRule makes a Rule of a format check, for string fields.
+func stringRule(check func(string) error) Rule {
+	return func(v reflect.Value, _ string) error {
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("is a %s, not a string", v.Kind())
+		}
+		return check(v.String())
+	}
+}
+
+// sizeRule makes a Rule that compares a number, or the length of a string
+// or collection, to the parameter.
+func sizeRule(bound string, ok func(n, param float64) bool) Rule {
+	return func(v reflect.Value, param string) error {
+		p, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Errorf("bad parameter %q", param)
+		}
+		var n float64
+		unit := ""
+		switch v.Kind() {
+		case reflect.String:
+			n, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+		case reflect.Slice, reflect.Array, reflect.Map:
+			n, unit = float64(v.Len()), " elements"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			n = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = v.Float()
+		default:
+			return fmt.Errorf("has no size: it is a %s", v.Kind())
+		}
+		if ok(n, p) {
+			return nil
+		}
+		if unit != "" {
+			return fmt.Errorf("must have %s %s%s", bound, param, unit)
+		}
+		return fmt.Errorf("must be %s %s", bound, param)
+	}
+}
+
+// oneOf checks that the value, formatted with fmt.Sprint, is one of the
+// space-separated words of the parameter.
+func oneOf(v reflect.Value, param string) error {
+	s := fmt.Sprint(v.Interface())
+	for _, w := range strings.Fields(param) {
+		if s == w {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(strings.Fields(param), ", "))
+}
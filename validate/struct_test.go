@@ -0,0 +1,154 @@
+package validate
+
+import "errors"
+import "fmt"
+import "reflect"
+import "strings"
+import "testing"
+
+type address struct {
+	City string `validate:"required"`
+	Zip  string `validate:"omitempty,len=5,numeric"`
+}
+
+type signup struct {
+	Name    string   `validate:"required,max=5"`
+	Email   string   `validate:"required,email"`
+	Plan    string   `validate:"omitempty,oneof=free pro"`
+	Age     int      `validate:"min=18,max=130"`
+	Tags    []string `validate:"max=2"`
+	Home    address
+	Work    *address
+	Others  []address
+	Ignored string `validate:"-"`
+	private string `validate:"required"`
+}
+
+func TestValidate(t *testing.T) {
+	ok := signup{Name: "ann", Email: "ann@example.com", Age: 30, Home: address{City: "Oslo"}}
+	if err := Validate(ok); err != nil {
+		t.Fatalf("Validate(valid) == %v", err)
+	}
+	if err := Validate(&ok); err != nil {
+		t.Fatalf("Validate(&valid) == %v", err)
+	}
+
+	bad := signup{
+		Name:   "annabel",
+		Plan:   "gold",
+		Age:    12,
+		Tags:   []string{"a", "b", "c"},
+		Home:   address{Zip: "12a45"},
+		Work:   &address{City: "Rome", Zip: "123"},
+		Others: []address{{City: "Nice"}, {}},
+	}
+	err := Validate(bad)
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate == %v, want Errors", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Field+" "+e.Rule)
+	}
+	want := []string{
+		"Name max", "Email required", "Plan oneof", "Age min", "Tags max",
+		"Home.City required", "Home.Zip numeric", "Work.Zip len", "Others[1].City required",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("errors\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	msgs := map[string]string{
+		"Name":     "Name: must have at most 5 characters",
+		"Plan":     "Plan: must be one of free, pro",
+		"Age":      "Age: must be at least 18",
+		"Work.Zip": "Work.Zip: must have exactly 5 characters",
+	}
+	for _, e := range errs {
+		if m, ok := msgs[e.Field]; ok && e.Error() != m {
+			t.Errorf("message %q, want %q", e.Error(), m)
+		}
+	}
+	if !errors.Is(errs[6], ErrInvalid) {
+		t.Error("format rule error does not match ErrInvalid")
+	}
+
+	if err := Validate(42); err == nil {
+		t.Error("Validate(42) succeeded")
+	}
+}
+
+func TestZeroValues(t *testing.T) {
+	type s struct {
+		Age   int    `validate:"min=18"`
+		Phone string `validate:"omitempty,numeric"`
+		Score int    `validate:"omitempty,min=1"`
+	}
+	err := Validate(s{})
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Age" {
+		t.Errorf("Validate(zero) == %v, want only Age to fail", err)
+	}
+	if err := Validate(s{Age: 18, Phone: "12a"}); err == nil || err.Error() != "Phone: "+IsNumeric("12a").Error() {
+		t.Errorf("Validate(bad phone) == %v", err)
+	}
+}
+
+func TestPointers(t *testing.T) {
+	type s struct {
+		Email *string `validate:"email"`
+		Age   *int    `validate:"omitempty,min=18"`
+		Name  *string `validate:"required"`
+	}
+	str := func(v string) *string { return &v }
+	num := func(v int) *int { return &v }
+	if err := Validate(s{Email: str("ann@example.com"), Age: num(20), Name: str("")}); err != nil {
+		t.Errorf("Validate(valid pointers) == %v", err)
+	}
+
+	err := Validate(s{Email: str("nope"), Age: num(12)})
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate == %v, want Errors", err)
+	}
+	var got []string
+	for _, e := range errs {
+		got = append(got, e.Field+" "+e.Rule)
+	}
+	if want := []string{"Email email", "Age min", "Name required"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("errors %q, want %q", got, want)
+	}
+
+	// A nil pointer is checked as the zero value, unless omitempty.
+	err = Validate(s{Name: str("ann")})
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Field != "Email" {
+		t.Errorf("Validate(nil pointers) == %v, want only Email to fail", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("even", func(v reflect.Value, _ string) error {
+		if v.Int()%2 != 0 {
+			return fmt.Errorf("must be even")
+		}
+		return nil
+	})
+	type s struct {
+		N int `validate:"even"`
+	}
+	if err := Validate(s{4}); err != nil {
+		t.Errorf("Validate(4) == %v", err)
+	}
+	if err := Validate(s{3}); err == nil || err.Error() != "N: must be even" {
+		t.Errorf("Validate(3) == %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("unknown rule did not panic")
+		}
+	}()
+	Validate(struct {
+		X string `validate:"nosuchrule"`
+	}{"x"})
+}
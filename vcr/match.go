@@ -0,0 +1,59 @@
+package vcr
+
+import "bytes"
+import "net/http"
+import "net/url"
+
+// A Matcher reports whether a recorded request answers req, whose body
+// has already been read into body.
+type Matcher func(req *http.Request, body []byte, rec Request) bool
+
+// MatchMethodURL matches requests with the same method and URL, ignoring
+// the order of query parameters.
+func MatchMethodURL(req *http.Request, body []byte, rec Request) bool {
+	if req.Method != rec.Method {
+		return false
+	}
+	u, err := url.Parse(rec.URL)
+	if err != nil {
+		return false
+	}
+	return req.URL.Scheme == u.Scheme && req.URL.Host == u.Host && req.URL.Path == u.Path &&
+		req.URL.Query().Encode() == u.Query().Encode()
+}
+
+// MatchBody matches requests with the same body.
+func MatchBody(req *http.Request, body []byte, rec Request) bool {
+	return bytes.Equal(body, rec.Body)
+}
+
+// MatchHeaders returns a Matcher for requests with the same values of the
+// named headers.
+func MatchHeaders(names ...string) Matcher {
+	return func(req *http.Request, body []byte, rec Request) bool {
+		for _, name := range names {
+			a, b := req.Header.Values(name), rec.Header.Values(name)
+			if len(a) != len(b) {
+				return false
+			}
+			for i := range a {
+				if a[i] != b[i] {
+					return false
+				}
+			}
+		}
+		return true
+	}
+}
+
+// All returns a Matcher that matches when every one of ms does.
+func All(ms ...Matcher) Matcher {
+	return func(req *http.Request, body []byte, rec Request) bool {
+		for _, m := range ms {
+			if !m(req, body, rec) {
+				return false
+			}
+		}
+		return true
+	}
+}
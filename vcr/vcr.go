@@ -0,0 +1,265 @@
+// Package vcr records HTTP interactions to a cassette file and replays
+// them, so that tests of code that calls real services run offline and
+// repeatably.
+//
+// A Recorder is an http.RoundTripper. On the first run, with no cassette
+// yet, it passes requests to the real transport and records each exchange;
+// Stop writes the cassette. Later runs replay the recorded responses and
+// make no network calls. Commit the cassette with the tests, after
+// checking that Scrub has removed every secret from it.
+package vcr
+
+import "bytes"
+import "encoding/json"
+import "errors"
+import "fmt"
+import "io"
+import "io/fs"
+import "net/http"
+import "os"
+import "path/filepath"
+import "sync"
+import "unicode/utf8"
+
+// ErrNoMatch is returned when replaying a request that matches no unused
+// recorded interaction.
+var ErrNoMatch = errors.New("vcr: no recorded interaction matches the request")
+
+// A Mode says whether a Recorder records or replays.
+type Mode int
+
+const (
+	// Auto replays if the cassette exists and records if it does not.
+	Auto Mode = iota
+	// Replay replays the cassette, which must exist.
+	Replay
+	// Record records a new cassette, replacing any that exists.
+	Record
+)
+
+// Redacted replaces the values of scrubbed headers.
+const Redacted = "[REDACTED]"
+
+// DefaultScrub lists the headers scrubbed when Options.Scrub is nil.
+var DefaultScrub = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie", "X-Api-Key"}
+
+// Options configure a Recorder.
+type Options struct {
+	Mode Mode
+	// Transport makes the real requests when recording. Nil means
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	// Match decides which recorded interaction answers a request. Nil
+	// means MatchMethodURL.
+	Match Matcher
+	// Scrub names headers, of requests and responses, whose values are
+	// replaced by Redacted in the cassette. Nil means DefaultScrub.
+	Scrub []string
+}
+
+// Cassette is the file format: the interactions in the order they were
+// recorded.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// An Interaction is a recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Body is a recorded body. It is stored as a JSON string if it is valid
+// UTF-8, so that cassettes can be read and diffed, and as base64
+// otherwise.
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string][]byte{"base64": b})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var enc struct {
+		Base64 []byte `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return err
+	}
+	*b = enc.Base64
+	return nil
+}
+
+// Recorder records or replays the interactions of one cassette. It is
+// safe for concurrent use.
+type Recorder struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+	match     Matcher
+	scrub     []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder for the cassette at path.
+func New(path string, opts Options) (*Recorder, error) {
+	r := &Recorder{path: path, transport: opts.Transport, match: opts.Match, scrub: opts.Scrub}
+	if r.transport == nil {
+		r.transport = http.DefaultTransport
+	}
+	if r.match == nil {
+		r.match = MatchMethodURL
+	}
+	if r.scrub == nil {
+		r.scrub = DefaultScrub
+	}
+	if opts.Mode == Record {
+		r.recording = true
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && opts.Mode == Auto {
+		r.recording = true
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("vcr: %s: %w", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Recording reports whether r is recording rather than replaying.
+func (r *Recorder) Recording() bool { return r.recording }
+
+// Client returns an http.Client that uses r.
+func (r *Recorder) Client() *http.Client { return &http.Client{Transport: r} }
+
+// RoundTrip records or replays req.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.recording {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	in := Interaction{
+		Request:  Request{Method: req.Method, URL: req.URL.String(), Header: r.scrubbed(req.Header), Body: body},
+		Response: Response{Status: resp.StatusCode, Header: r.scrubbed(resp.Header), Body: respBody},
+	}
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !r.match(req, body, in.Request) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrNoMatch, req.Method, req.URL)
+}
+
+// Stop writes the cassette if r is recording, creating its directory if
+// need be. Call it when the test is done, typically with t.Cleanup.
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// scrubbed returns a copy of h with the scrubbed headers redacted.
+func (r *Recorder) scrubbed(h http.Header) http.Header {
+	h = h.Clone()
+	for _, name := range r.scrub {
+		if vs := h.Values(name); len(vs) > 0 {
+			h.Set(name, Redacted)
+		}
+	}
+	return h
+}
+
+// readBody reads req's body, if any, and replaces it so that it can be
+// sent as well as recorded.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
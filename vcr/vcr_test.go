@@ -0,0 +1,117 @@
+package vcr
+
+import "errors"
+import "io"
+import "net/http"
+import "net/http/httptest"
+import "os"
+import "path/filepath"
+import "strings"
+import "testing"
+
+func get(t *testing.T, c *http.Client, url string) string {
+	t.Helper()
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(resp.Body)
+	return string(b)
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Set-Cookie", "session=abc")
+		io.WriteString(w, "hello "+r.URL.Query().Get("name"))
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "testdata", "hello.json")
+
+	rec, err := New(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Recording() {
+		t.Fatal("not recording without a cassette")
+	}
+	if got := get(t, rec.Client(), srv.URL+"/?name=go&x=1"); got != "hello go" {
+		t.Fatalf("recorded body %q", got)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "abc") {
+		t.Fatalf("cassette holds a secret:\n%s", data)
+	}
+	srv.Close()
+
+	rep, err := New(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Recording() {
+		t.Fatal("recording with a cassette")
+	}
+	if got := get(t, rep.Client(), srv.URL+"/?x=1&name=go"); got != "hello go" {
+		t.Fatalf("replayed body %q", got)
+	}
+	if calls != 1 {
+		t.Fatalf("server called %d times", calls)
+	}
+	if _, err := rep.Client().Get(srv.URL + "/?name=go&x=1"); !errors.Is(err, ErrNoMatch) {
+		t.Fatalf("second replay: %v, want ErrNoMatch", err)
+	}
+}
+
+func TestReplayMissing(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "none.json"), Options{Mode: Replay}); err == nil {
+		t.Fatal("no error replaying a missing cassette")
+	}
+}
+
+func TestMatchBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+		w.Write([]byte{0xff})
+	}))
+	defer srv.Close()
+	path := filepath.Join(t.TempDir(), "echo.json")
+	opts := Options{Match: All(MatchMethodURL, MatchBody)}
+
+	rec, _ := New(path, opts)
+	for _, s := range []string{"a", "b"} {
+		resp, err := rec.Client().Post(srv.URL, "text/plain", strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := New(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"b", "a"} {
+		resp, err := rep.Client().Post(srv.URL, "text/plain", strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if want := s + "\xff"; string(b) != want {
+			t.Errorf("body %q, want %q", b, want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package golib
+
+import "math"
+
+// Vector is a point or displacement in the plane.
+type Vector struct {
+	X, Y float64
+}
+
+// Add returns v+w.
+func (v Vector) Add(w Vector) Vector { return Vector{v.X + w.X, v.Y + w.Y} }
+
+// Sub returns v-w.
+func (v Vector) Sub(w Vector) Vector { return Vector{v.X - w.X, v.Y - w.Y} }
+
+// Scale returns v scaled by k.
+func (v Vector) Scale(k float64) Vector { return Vector{k * v.X, k * v.Y} }
+
+// Dot returns the dot product of v and w.
+func (v Vector) Dot(w Vector) float64 { return v.X*w.X + v.Y*w.Y }
+
+// Len returns the length of v.
+func (v Vector) Len() float64 { return math.Hypot(v.X, v.Y) }
@@ -0,0 +1,22 @@
+package golib
+
+import "testing"
+
+func TestVector(t *testing.T) {
+	v, w := Vector{3, 4}, Vector{1, -2}
+	if got := v.Add(w); got != (Vector{4, 2}) {
+		t.Errorf("Add() == %v", got)
+	}
+	if got := v.Sub(w); got != (Vector{2, 6}) {
+		t.Errorf("Sub() == %v", got)
+	}
+	if got := v.Scale(-2); got != (Vector{-6, -8}) {
+		t.Errorf("Scale() == %v", got)
+	}
+	if got := v.Dot(w); got != -5 {
+		t.Errorf("Dot() == %v", got)
+	}
+	if got := v.Len(); got != 5 {
+		t.Errorf("Len() == %v", got)
+	}
+}
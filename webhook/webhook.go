@@ -0,0 +1,193 @@
+// Package webhook receives signed webhook deliveries over HTTP and
+// publishes them as events on a bus.
+//
+// A delivery is a POST whose body is the JSON event payload, with headers:
+//
+//	Webhook-Id:        unique delivery ID, used to drop retries; no dots
+//	Webhook-Event:     event type, e.g. "order.created"
+//	Webhook-Timestamp: Unix time in seconds when the delivery was signed
+//	Webhook-Signature: "sha256=" + hex HMAC-SHA256 of
+//	                   id + "." + event + "." + timestamp + "." + body
+//
+// Signing the timestamp with the body stops an attacker replaying an old
+// delivery with a fresh timestamp. Signing the ID and event type stops
+// them replaying a recent one under a new ID, which would get past the
+// check for retries, or under another type, which would send the payload
+// to the wrong handler. The ID may not contain a dot, so that no two
+// pairs of ID and type sign the same.
+package webhook
+
+import "crypto/hmac"
+import "crypto/sha256"
+import "encoding/hex"
+import "encoding/json"
+import "errors"
+import "io"
+import "net/http"
+import "strconv"
+import "strings"
+import "time"
+
+import "github.com/lukehedger/golib/bus"
+import "github.com/lukehedger/golib/cache"
+
+// Header names used by deliveries.
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderEvent     = "Webhook-Event"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+// DefaultTolerance is how far a delivery's timestamp may be from the
+// receiver's clock.
+const DefaultTolerance = 5 * time.Minute
+
+// MaxBodySize limits the size of a delivery.
+const MaxBodySize = 1 << 20
+
+// Event is a verified delivery.
+type Event struct {
+	ID        string
+	Type      string
+	Timestamp time.Time
+	Payload   json.RawMessage
+}
+
+// Receiver is an http.Handler that verifies deliveries and publishes each
+// new one to Bus. It replies 202 Accepted for new deliveries, 200 OK for
+// duplicates, 400 for malformed or stale ones and 401 for bad signatures.
+// If no subscriber has room for the event it replies 503 Service
+// Unavailable and forgets the delivery, so that the sender's retry is
+// accepted.
+type Receiver struct {
+	secret    []byte
+	tolerance time.Duration
+	bus       *bus.Bus[Event]
+	seen      *cache.Cache[string, struct{}]
+	now       func() time.Time
+}
+
+// NewReceiver returns a receiver that verifies signatures with secret and
+// publishes to b. Delivery IDs are remembered for twice the timestamp
+// tolerance, which covers every delivery that could still pass the
+// freshness check.
+func NewReceiver(secret []byte, b *bus.Bus[Event], tolerance time.Duration) *Receiver {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	return &Receiver{
+		secret:    secret,
+		tolerance: tolerance,
+		bus:       b,
+		seen:      cache.New[string, struct{}](2 * tolerance),
+		now:       time.Now,
+	}
+}
+
+// Sign returns the signature header value for the delivery id of an
+// event of type eventType with body, signed at timestamp, for use by
+// senders and tests.
+func Sign(secret []byte, id, eventType string, timestamp time.Time, body []byte) string {
+	return "sha256=" + hex.EncodeToString(mac(secret, id, eventType, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+func mac(secret []byte, id, eventType, timestamp string, body []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	for _, s := range []string{id, eventType, timestamp} {
+		m.Write([]byte(s))
+		m.Write([]byte{'.'})
+	}
+	m.Write(body)
+	return m.Sum(nil)
+}
+
+var (
+	errMissing   = errors.New("missing webhook headers")
+	errID        = errors.New("webhook ID contains a dot")
+	errStale     = errors.New("timestamp outside tolerance")
+	errSignature = errors.New("invalid signature")
+)
+
+func (rc *Receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		http.Error(w, "cannot read body", http.StatusBadRequest)
+		return
+	}
+
+	e, err := rc.verify(r.Header, body)
+	switch err {
+	case nil:
+	case errSignature:
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !rc.seen.Add(e.ID, struct{}{}) {
+		w.WriteHeader(http.StatusOK) // already delivered; the sender retried
+		return
+	}
+	if rc.bus.Publish(e) == 0 {
+		rc.seen.Delete(e.ID)
+		http.Error(w, "event not dispatched", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (rc *Receiver) verify(h http.Header, body []byte) (Event, error) {
+	id, typ, ts, sig := h.Get(HeaderID), h.Get(HeaderEvent), h.Get(HeaderTimestamp), h.Get(HeaderSignature)
+	if id == "" || typ == "" || ts == "" || sig == "" {
+		return Event{}, errMissing
+	}
+	if strings.Contains(id, ".") {
+		return Event{}, errID
+	}
+	secs, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return Event{}, errStale
+	}
+	t := time.Unix(secs, 0)
+	if d := rc.now().Sub(t); d > rc.tolerance || d < -rc.tolerance {
+		return Event{}, errStale
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(sig, "sha256="))
+	if err != nil || !strings.HasPrefix(sig, "sha256=") || !hmac.Equal(got, mac(rc.secret, id, typ, ts, body)) {
+		return Event{}, errSignature
+	}
+	if !json.Valid(body) {
+		return Event{}, errors.New("payload is not JSON")
+	}
+	return Event{ID: id, Type: typ, Timestamp: t, Payload: body}, nil
+}
+
+// On subscribes to b and calls fn with the payload of every event of type
+// eventType decoded into a T. Events whose payload does not decode are
+// skipped. Call the returned function to unsubscribe; fn is called from a
+// single goroutine, in delivery order.
+func On[T any](b *bus.Bus[Event], eventType string, fn func(Event, T)) (stop func()) {
+	sub := b.Subscribe(64)
+	go func() {
+		for e := range sub.C {
+			if e.Type != eventType {
+				continue
+			}
+			var v T
+			if json.Unmarshal(e.Payload, &v) != nil {
+				continue
+			}
+			fn(e, v)
+		}
+	}()
+	return sub.Close
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import "net/http"
+import "net/http/httptest"
+import "strconv"
+import "strings"
+import "testing"
+import "time"
+
+import "github.com/lukehedger/golib/bus"
+
+type order struct {
+	ID    int     `json:"id"`
+	Total float64 `json:"total"`
+}
+
+func TestReceiver(t *testing.T) {
+	secret := []byte("s3cret")
+	now := time.Unix(1_700_000_000, 0)
+	b := bus.New[Event]()
+	rc := NewReceiver(secret, b, time.Minute)
+	rc.now = func() time.Time { return now }
+
+	orders := make(chan order, 4)
+	stop := On(b, "order.created", func(e Event, o order) { orders <- o })
+	defer stop()
+
+	deliver := func(id, typ, body string, ts time.Time, sig string) int {
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+		req.Header.Set(HeaderID, id)
+		req.Header.Set(HeaderEvent, typ)
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts.Unix(), 10))
+		req.Header.Set(HeaderSignature, sig)
+		rec := httptest.NewRecorder()
+		rc.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	body := `{"id": 7, "total": 9.5}`
+	cases := []struct {
+		name string
+		id   string
+		typ  string
+		ts   time.Time
+		sig  string
+		want int
+	}{
+		{"valid", "d1", "order.created", now, Sign(secret, "d1", "order.created", now, []byte(body)), http.StatusAccepted},
+		{"duplicate", "d1", "order.created", now, Sign(secret, "d1", "order.created", now, []byte(body)), http.StatusOK},
+		{"other type", "d2", "order.paid", now, Sign(secret, "d2", "order.paid", now, []byte(body)), http.StatusAccepted},
+		{"replayed under a new ID", "d5", "order.created", now, Sign(secret, "d1", "order.created", now, []byte(body)), http.StatusUnauthorized},
+		{"retyped", "d6", "order.paid", now, Sign(secret, "d6", "order.created", now, []byte(body)), http.StatusUnauthorized},
+		{"dot in ID", "d7.order", "created", now, Sign(secret, "d7.order", "created", now, []byte(body)), http.StatusBadRequest},
+		{"wrong secret", "d3", "order.created", now, Sign([]byte("nope"), "d3", "order.created", now, []byte(body)), http.StatusUnauthorized},
+		{"stale", "d4", "order.created", now.Add(-2 * time.Minute), Sign(secret, "d4", "order.created", now.Add(-2*time.Minute), []byte(body)), http.StatusBadRequest},
+		{"missing id", "", "order.created", now, Sign(secret, "", "order.created", now, []byte(body)), http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		if got := deliver(c.id, c.typ, body, c.ts, c.sig); got != c.want {
+			t.Errorf("%s: status %d, want %d", c.name, got, c.want)
+		}
+	}
+
+	select {
+	case o := <-orders:
+		if o.ID != 7 || o.Total != 9.5 {
+			t.Errorf("decoded order == %+v", o)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no order.created event dispatched")
+	}
+	select {
+	case o := <-orders:
+		t.Errorf("unexpected second order %+v", o)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestReceiverUndelivered(t *testing.T) {
+	secret := []byte("s3cret")
+	b := bus.New[Event]()
+	rc := NewReceiver(secret, b, time.Minute)
+
+	deliver := func() int {
+		body := `{"id": 1}`
+		now := time.Now()
+		req := httptest.NewRequest("POST", "/hook", strings.NewReader(body))
+		req.Header.Set(HeaderID, "d1")
+		req.Header.Set(HeaderEvent, "order.created")
+		req.Header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		req.Header.Set(HeaderSignature, Sign(secret, "d1", "order.created", now, []byte(body)))
+		rec := httptest.NewRecorder()
+		rc.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Nobody is listening, so the event is lost and the sender must retry.
+	if got := deliver(); got != http.StatusServiceUnavailable {
+		t.Errorf("delivery with no subscribers: status %d, want 503", got)
+	}
+	sub := b.Subscribe(1)
+	defer sub.Close()
+	if got := deliver(); got != http.StatusAccepted {
+		t.Errorf("retry: status %d, want 202", got)
+	}
+	if got := deliver(); got != http.StatusOK {
+		t.Errorf("second retry: status %d, want 200", got)
+	}
+}
@@ -0,0 +1,310 @@
+// Package websocket implements the WebSocket protocol (RFC 6455) for
+// servers and clients: the opening handshake, message framing with
+// fragmentation and masking, and ping, pong and close control frames.
+//
+// Extensions and subprotocol negotiation are not supported.
+package websocket
+
+import "bufio"
+import "crypto/rand"
+import "crypto/sha1"
+import "encoding/base64"
+import "encoding/binary"
+import "errors"
+import "fmt"
+import "io"
+import "net"
+import "sync"
+import "time"
+
+// MessageType identifies the payload of a data message.
+type MessageType int
+
+const (
+	TextMessage   MessageType = 1
+	BinaryMessage MessageType = 2
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close status codes defined by RFC 6455.
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseNoStatus      = 1005
+	CloseMessageTooBig = 1009
+	CloseInternalError = 1011
+)
+
+// DefaultMaxMessageSize bounds incoming messages unless Conn.MaxMessageSize
+// says otherwise.
+const DefaultMaxMessageSize = 1 << 20
+
+// ErrMessageTooBig is returned by ReadMessage when a message exceeds the
+// connection's size limit.
+var ErrMessageTooBig = errors.New("websocket: message too big")
+
+// CloseError is returned by ReadMessage when the peer closes the
+// connection.
+type CloseError struct {
+	Code   int
+	Reason string
+}
+
+func (e *CloseError) Error() string {
+	return fmt.Sprintf("websocket: closed with code %d %s", e.Code, e.Reason)
+}
+
+// Conn is a WebSocket connection. ReadMessage must not be called
+// concurrently; WriteMessage and Close may be called from any goroutine.
+type Conn struct {
+	// MaxMessageSize limits the size of an incoming message. Zero means
+	// DefaultMaxMessageSize.
+	MaxMessageSize int64
+
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // clients mask the frames they send
+
+	wmu       sync.Mutex
+	closeSent bool
+}
+
+func newConn(c net.Conn, br *bufio.Reader, client bool) *Conn {
+	if br == nil {
+		br = bufio.NewReader(c)
+	}
+	return &Conn{conn: c, br: br, client: client}
+}
+
+// RemoteAddr returns the address of the peer.
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetReadDeadline sets the deadline for future ReadMessage calls.
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// ReadMessage returns the next data message, reassembling fragments and
+// answering pings along the way. When the peer closes the connection it
+// returns a *CloseError after acknowledging the close.
+func (c *Conn) ReadMessage() (MessageType, []byte, error) {
+	limit := c.MaxMessageSize
+	if limit <= 0 {
+		limit = DefaultMaxMessageSize
+	}
+	var typ MessageType
+	var msg []byte
+	for {
+		f, err := c.readFrame(limit - int64(len(msg)))
+		if err != nil {
+			if err == ErrMessageTooBig {
+				c.writeClose(CloseMessageTooBig, "")
+			}
+			return 0, nil, err
+		}
+		switch f.op {
+		case opPing:
+			if err := c.writeFrame(opPong, f.payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			ce := &CloseError{Code: CloseNoStatus}
+			if len(f.payload) >= 2 {
+				ce.Code = int(binary.BigEndian.Uint16(f.payload))
+				ce.Reason = string(f.payload[2:])
+			}
+			c.writeClose(ce.Code, "")
+			c.conn.Close()
+			return 0, nil, ce
+		case opText, opBinary:
+			if msg != nil {
+				return 0, nil, c.protocolError("new message before previous one finished")
+			}
+			typ = MessageType(f.op)
+			msg = f.payload
+		case opContinuation:
+			if msg == nil {
+				return 0, nil, c.protocolError("continuation without a message")
+			}
+			msg = append(msg, f.payload...)
+		default:
+			return 0, nil, c.protocolError(fmt.Sprintf("unknown opcode %d", f.op))
+		}
+		if f.fin {
+			if msg == nil {
+				msg = []byte{}
+			}
+			return typ, msg, nil
+		}
+	}
+}
+
+func (c *Conn) protocolError(msg string) error {
+	c.writeClose(CloseProtocolError, "")
+	c.conn.Close()
+	return errors.New("websocket: protocol error: " + msg)
+}
+
+// A frame is a single decoded frame.
+type frame struct {
+	fin     bool
+	op      byte
+	payload []byte
+}
+
+// readFrame reads one frame, rejecting payloads larger than limit.
+func (c *Conn) readFrame(limit int64) (frame, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(c.br, h[:]); err != nil {
+		return frame{}, err
+	}
+	f := frame{fin: h[0]&0x80 != 0, op: h[0] & 0x0F}
+	if h[0]&0x70 != 0 {
+		return frame{}, c.protocolError("reserved bits set")
+	}
+	masked := h[1]&0x80 != 0
+	if masked == c.client {
+		// Clients must mask every frame and servers must not.
+		return frame{}, c.protocolError("incorrect masking")
+	}
+
+	n := int64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return frame{}, err
+		}
+		n = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return frame{}, err
+		}
+		n = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+	control := f.op&0x8 != 0
+	if control && (n > 125 || !f.fin) {
+		return frame{}, c.protocolError("invalid control frame")
+	}
+	if !control && (n < 0 || n > limit) {
+		return frame{}, ErrMessageTooBig
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return frame{}, err
+		}
+	}
+	f.payload = make([]byte, n)
+	if _, err := io.ReadFull(c.br, f.payload); err != nil {
+		return frame{}, err
+	}
+	if masked {
+		for i := range f.payload {
+			f.payload[i] ^= mask[i%4]
+		}
+	}
+	return f, nil
+}
+
+// WriteMessage sends data as a single-frame message.
+func (c *Conn) WriteMessage(typ MessageType, data []byte) error {
+	if typ != TextMessage && typ != BinaryMessage {
+		return fmt.Errorf("websocket: invalid message type %d", typ)
+	}
+	return c.writeFrame(byte(typ), data)
+}
+
+// Ping sends a ping; the peer's pong is consumed by ReadMessage.
+func (c *Conn) Ping(data []byte) error {
+	return c.writeFrame(opPing, data)
+}
+
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closeSent {
+		return net.ErrClosed
+	}
+	if op == opClose {
+		c.closeSent = true
+	}
+
+	buf := make([]byte, 0, 14+len(payload))
+	buf = append(buf, 0x80|op)
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(payload); {
+	case n <= 125:
+		buf = append(buf, maskBit|byte(n))
+	case n <= 0xFFFF:
+		buf = append(buf, maskBit|126)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf = append(buf, maskBit|127)
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		buf = append(buf, mask[:]...)
+		start := len(buf)
+		buf = append(buf, payload...)
+		for i := range payload {
+			buf[start+i] ^= mask[i%4]
+		}
+	} else {
+		buf = append(buf, payload...)
+	}
+	_, err := c.conn.Write(buf)
+	return err
+}
+
+func (c *Conn) writeClose(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	if code == CloseNoStatus {
+		payload = nil // 1005 must not be sent on the wire
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	return c.writeFrame(opClose, append(payload, reason...))
+}
+
+// Close sends a normal close frame and closes the underlying connection
+// without waiting for the peer's acknowledgement.
+func (c *Conn) Close() error {
+	return c.CloseWith(CloseNormal, "")
+}
+
+// CloseWith is like Close but sends the given status code and reason.
+func (c *Conn) CloseWith(code int, reason string) error {
+	c.writeClose(code, reason)
+	return c.conn.Close()
+}
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// acceptKey computes the Sec-WebSocket-Accept value for a client key.
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
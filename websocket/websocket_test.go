@@ -0,0 +1,108 @@
+package websocket
+
+import "bytes"
+import "context"
+import "errors"
+import "net/http"
+import "net/http/httptest"
+import "strings"
+import "testing"
+import "time"
+
+// echo serves a handler that sends every message straight back.
+func echo() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		c.MaxMessageSize = 1024
+		for {
+			typ, msg, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+			if err := c.WriteMessage(typ, msg); err != nil {
+				return
+			}
+		}
+	}))
+}
+
+func dial(t *testing.T, srv *httptest.Server) *Conn {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c, err := Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestEcho(t *testing.T) {
+	srv := echo()
+	defer srv.Close()
+	c := dial(t, srv)
+	defer c.Close()
+
+	cases := []struct {
+		typ MessageType
+		msg []byte
+	}{
+		{TextMessage, []byte("hello")},
+		{BinaryMessage, []byte{0, 1, 2, 255}},
+		{TextMessage, []byte{}},
+		{BinaryMessage, bytes.Repeat([]byte("x"), 1000)}, // 16-bit length
+	}
+	for _, tc := range cases {
+		if err := c.WriteMessage(tc.typ, tc.msg); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Ping([]byte("ping")); err != nil {
+			t.Fatal(err)
+		}
+		typ, msg, err := c.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != tc.typ || !bytes.Equal(msg, tc.msg) {
+			t.Errorf("echo of %d-byte message: got type %d, %d bytes", len(tc.msg), typ, len(msg))
+		}
+	}
+
+	// The server's limit is 1024 bytes, so it closes the connection.
+	c.WriteMessage(BinaryMessage, make([]byte, 2000))
+	_, _, err := c.ReadMessage()
+	var ce *CloseError
+	if !errors.As(err, &ce) || ce.Code != CloseMessageTooBig {
+		t.Errorf("oversized message: ReadMessage error %v, want close code %d", err, CloseMessageTooBig)
+	}
+}
+
+func TestUpgradeRejects(t *testing.T) {
+	cases := []struct {
+		header http.Header
+		code   int
+	}{
+		{http.Header{}, http.StatusBadRequest},
+		{http.Header{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}, "Sec-Websocket-Version": {"8"}}, http.StatusUpgradeRequired},
+		{http.Header{"Connection": {"keep-alive, Upgrade"}, "Upgrade": {"websocket"}, "Sec-Websocket-Version": {"13"},
+			"Sec-Websocket-Key": {"dGhlIHNhbXBsZSBub25jZQ=="}, "Origin": {"http://evil.example"}}, http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header = c.header
+		rec := httptest.NewRecorder()
+		if _, err := Upgrade(rec, req); err == nil || rec.Code != c.code {
+			t.Errorf("Upgrade with %v: status %d, err %v, want status %d", c.header, rec.Code, err, c.code)
+		}
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// The example from RFC 6455 section 1.3.
+	if got, want := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("acceptKey == %q, want %q", got, want)
+	}
+}